@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+var captureCmd = &cobra.Command{
+	Use:   "capture [filter expression]",
+	Short: "Capture the packets of the NetBird interface in the pcapng format",
+	Long: `Captures the decrypted packets passing the userspace firewall of the daemon and writes them in the pcapng format.
+This works in the userspace and netstack modes where there is no kernel interface to capture on.
+
+The optional filter expression uses a subset of the tcpdump syntax:
+  ip, ip6, tcp, udp, icmp, icmp6, proto <name|number>
+  [src|dst] host <address>, [src|dst] net <prefix>
+  [src|dst] port <port>, [src|dst] portrange <from>-<to>
+  inbound, outbound
+combined with and, or, not and parentheses.
+
+The capture stops after the duration, after the packet count is reached or on interrupt.`,
+	Example: `
+  netbird debug capture -w netbird.pcapng
+  netbird debug capture -w dns.pcapng --duration 30s --count 100 udp port 53
+  netbird debug capture -w - tcp port 443 and host 100.64.0.10 | wireshark -k -i -
+  netbird debug capture -w nat.pcapng --post-nat inbound and net 10.0.0.0/8`,
+	RunE: capturePackets,
+}
+
+func init() {
+	debugCmd.AddCommand(captureCmd)
+
+	captureCmd.Flags().StringP("output", "w", "", "Output file, - writes to stdout")
+	captureCmd.Flags().Duration("duration", time.Minute, "Capture duration (max 1h)")
+	captureCmd.Flags().Uint32("count", 0, "Stop after the given number of packets, 0 means no limit")
+	captureCmd.Flags().Uint32("snaplen", 0, "Truncate the packets to the given length, 0 means no truncation")
+	captureCmd.Flags().Bool("post-nat", false, "Additionally capture the packets after NAT modified them")
+	_ = captureCmd.MarkFlagRequired("output")
+}
+
+func capturePackets(cmd *cobra.Command, args []string) error {
+	output, _ := cmd.Flags().GetString("output")
+	duration, _ := cmd.Flags().GetDuration("duration")
+	count, _ := cmd.Flags().GetUint32("count")
+	snapLen, _ := cmd.Flags().GetUint32("snaplen")
+	postNAT, _ := cmd.Flags().GetBool("post-nat")
+
+	if duration <= 0 {
+		return fmt.Errorf("invalid duration: %s", duration)
+	}
+
+	var out io.Writer = os.Stdout
+	if output != "-" {
+		f, err := os.Create(output)
+		if err != nil {
+			return fmt.Errorf("create output file: %v", err)
+		}
+		defer func() {
+			if err := f.Close(); err != nil {
+				cmd.PrintErrf("Failed to close output file: %v\n", err)
+			}
+		}()
+		out = f
+	}
+
+	conn, err := getClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.Errorf(errCloseConnection, err)
+		}
+	}()
+
+	ctx, cancel := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	client := proto.NewDaemonServiceClient(conn)
+	stream, err := client.CapturePackets(ctx, &proto.CapturePacketsRequest{
+		Filter:     strings.Join(args, " "),
+		Duration:   durationpb.New(duration),
+		MaxPackets: count,
+		PostNat:    postNAT,
+		SnapLen:    snapLen,
+	})
+	if err != nil {
+		return fmt.Errorf("capture failed: %v", status.Convert(err).Message())
+	}
+
+	cmd.PrintErrf("Capturing for %s, press Ctrl+C to stop\n", duration)
+
+	for {
+		resp, err := stream.Recv()
+		switch {
+		case errors.Is(err, io.EOF):
+			return nil
+		case status.Code(err) == codes.Canceled || errors.Is(ctx.Err(), context.Canceled):
+			cmd.PrintErrln("Capture interrupted")
+			return nil
+		case err != nil:
+			return fmt.Errorf("capture failed: %v", status.Convert(err).Message())
+		}
+
+		if len(resp.GetData()) > 0 {
+			if _, err := out.Write(resp.GetData()); err != nil {
+				return fmt.Errorf("write capture: %v", err)
+			}
+			continue
+		}
+
+		cmd.PrintErrf("%d packets captured, %d packets dropped\n", resp.GetCapturedPackets(), resp.GetDroppedPackets())
+	}
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+var flowsCmd = &cobra.Command{
+	Use:   "flows",
+	Short: "Query the flows recorded on this host",
+	Long: `Queries the network flows recorded by the daemon in the local flow store, newest first.
+This works without the hosted flow service, but requires the daemon to run with NB_FLOW_STORE=true.
+The size and age of the store are limited by NB_FLOW_STORE_MAX_SIZE_MB (default 100) and NB_FLOW_STORE_MAX_AGE (default 168h).`,
+	Example: `
+  netbird flows
+  netbird flows --peer peer-a --since 1h
+  netbird flows --ip 10.0.0.10 --protocol tcp --port 443 --direction egress
+  netbird flows --limit 1000 --json`,
+	Args: cobra.NoArgs,
+	RunE: listFlows,
+}
+
+func init() {
+	flowsCmd.Flags().String("peer", "", "Only show flows of the peer with the given FQDN, hostname, IP or public key")
+	flowsCmd.Flags().String("ip", "", "Only show flows with the given source or destination IP")
+	flowsCmd.Flags().Uint16("port", 0, "Only show flows with the given source or destination port")
+	flowsCmd.Flags().String("protocol", "", "Only show flows of the given protocol: tcp, udp, icmp, sctp or a number")
+	flowsCmd.Flags().String("direction", "", "Only show flows of the given direction: ingress or egress")
+	flowsCmd.Flags().Duration("since", 0, "Only show flows of the given time window, e.g. 30m")
+	flowsCmd.Flags().Uint32("limit", 100, "Maximum number of flows to show")
+	flowsCmd.Flags().Bool("json", false, "Print the flows in JSON format")
+	flowsCmd.MarkFlagsMutuallyExclusive("peer", "ip")
+}
+
+func listFlows(cmd *cobra.Command, _ []string) error {
+	peerName, _ := cmd.Flags().GetString("peer")
+	ip, _ := cmd.Flags().GetString("ip")
+	port, _ := cmd.Flags().GetUint16("port")
+	protocol, _ := cmd.Flags().GetString("protocol")
+	direction, _ := cmd.Flags().GetString("direction")
+	since, _ := cmd.Flags().GetDuration("since")
+	limit, _ := cmd.Flags().GetUint32("limit")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	req := &proto.ListFlowsRequest{
+		Peer:      peerName,
+		Ip:        ip,
+		Port:      uint32(port),
+		Protocol:  protocol,
+		Direction: direction,
+		Limit:     limit,
+	}
+	if since > 0 {
+		req.Since = timestamppb.New(time.Now().Add(-since))
+	}
+
+	conn, err := getClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.Errorf(errCloseConnection, err)
+		}
+	}()
+
+	client := proto.NewDaemonServiceClient(conn)
+	resp, err := client.ListFlows(cmd.Context(), req)
+	if err != nil {
+		return fmt.Errorf("list flows failed: %v", status.Convert(err).Message())
+	}
+
+	if jsonOutput {
+		data, err := protojson.MarshalOptions{Multiline: true, EmitUnpopulated: true}.Marshal(resp)
+		if err != nil {
+			return fmt.Errorf("marshal flows: %v", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+
+	if len(resp.GetFlows()) == 0 {
+		cmd.Println("No flows found.")
+		return nil
+	}
+
+	printFlows(cmd, resp.GetFlows())
+	return nil
+}
+
+func printFlows(cmd *cobra.Command, flows []*proto.FlowEntry) {
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "TIME\tTYPE\tDIRECTION\tPROTOCOL\tSOURCE\tDESTINATION\tPACKETS RX/TX\tBYTES RX/TX")
+
+	for _, flow := range flows {
+		protocol := flow.GetProtocol()
+		if protocol == "ICMP" {
+			protocol = fmt.Sprintf("ICMP %d/%d", flow.GetIcmpType(), flow.GetIcmpCode())
+		}
+
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%d/%d\t%d/%d\n",
+			flow.GetTimestamp().AsTime().Local().Format(time.DateTime),
+			flow.GetType(),
+			flow.GetDirection(),
+			protocol,
+			flowEndpoint(flow.GetSourceName(), flow.GetSourceIp(), flow.GetSourcePort()),
+			flowEndpoint(flow.GetDestName(), flow.GetDestIp(), flow.GetDestPort()),
+			flow.GetRxPackets(), flow.GetTxPackets(),
+			flow.GetRxBytes(), flow.GetTxBytes(),
+		)
+	}
+
+	_ = w.Flush()
+}
+
+func flowEndpoint(name, ip string, port uint32) string {
+	addr := ip
+	if port != 0 {
+		addr = net.JoinHostPort(ip, strconv.FormatUint(uint64(port), 10))
+	}
+	if name == "" {
+		return addr
+	}
+	return fmt.Sprintf("%s (%s)", name, addr)
+}
@@ -18,7 +18,10 @@ var traceCmd = &cobra.Command{
   netbird debug trace in 192.168.1.10 10.10.0.2 -p tcp --sport 12345 --dport 443 --syn --ack
   netbird debug trace out 10.10.0.1 8.8.8.8 -p udp  --dport 53
   netbird debug trace in 10.10.0.2 10.10.0.1 -p icmp --icmp-type 8 --icmp-code 0
-  netbird debug trace in 100.64.1.1 self -p tcp --dport 80`,
+  netbird debug trace in 100.64.1.1 self -p tcp --dport 80
+  netbird debug trace in fd00::2 self -p tcp --dport 22 --syn
+  netbird debug trace out self 2001:db8::1 -p udp --dport 53
+  netbird debug trace in fd00::2 fd00::1 -p icmpv6 --icmp-type 128`,
 	Args: cobra.ExactArgs(3),
 	RunE: tracePacket,
 }
@@ -26,10 +29,10 @@ var traceCmd = &cobra.Command{
 func init() {
 	debugCmd.AddCommand(traceCmd)
 
-	traceCmd.Flags().StringP("protocol", "p", "tcp", "Protocol (tcp/udp/icmp)")
+	traceCmd.Flags().StringP("protocol", "p", "tcp", "Protocol (tcp/udp/icmp/icmpv6), icmp selects ICMPv6 for IPv6 addresses")
 	traceCmd.Flags().Uint16("sport", 0, "Source port")
 	traceCmd.Flags().Uint16("dport", 0, "Destination port")
-	traceCmd.Flags().Uint8("icmp-type", 0, "ICMP type")
+	traceCmd.Flags().Uint8("icmp-type", 0, "ICMP type, defaults to echo request (8 for IPv4, 128 for IPv6)")
 	traceCmd.Flags().Uint8("icmp-code", 0, "ICMP code")
 	traceCmd.Flags().Bool("syn", false, "TCP SYN flag")
 	traceCmd.Flags().Bool("ack", false, "TCP ACK flag")
@@ -46,8 +49,9 @@ func tracePacket(cmd *cobra.Command, args []string) error {
 	}
 
 	protocol := cmd.Flag("protocol").Value.String()
-	if protocol != "tcp" && protocol != "udp" && protocol != "icmp" {
-		return fmt.Errorf("invalid protocol: use tcp/udp/icmp")
+	isICMP := protocol == "icmp" || protocol == "icmpv6"
+	if protocol != "tcp" && protocol != "udp" && !isICMP {
+		return fmt.Errorf("invalid protocol: use tcp/udp/icmp/icmpv6")
 	}
 
 	sport, err := cmd.Flags().GetUint16("sport")
@@ -60,7 +64,7 @@ func tracePacket(cmd *cobra.Command, args []string) error {
 	}
 
 	// For TCP/UDP, generate random ephemeral port (49152-65535) if not specified
-	if protocol != "icmp" {
+	if !isICMP {
 		if sport == 0 {
 			sport = uint16(rand.Intn(16383) + 49152)
 		}
@@ -88,8 +92,20 @@ func tracePacket(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	icmpType, _ := cmd.Flags().GetUint32("icmp-type")
-	icmpCode, _ := cmd.Flags().GetUint32("icmp-code")
+	var icmpType *uint32
+	if cmd.Flags().Changed("icmp-type") {
+		t, err := cmd.Flags().GetUint8("icmp-type")
+		if err != nil {
+			return fmt.Errorf("invalid ICMP type: %v", err)
+		}
+		v := uint32(t)
+		icmpType = &v
+	}
+	code, err := cmd.Flags().GetUint8("icmp-code")
+	if err != nil {
+		return fmt.Errorf("invalid ICMP code: %v", err)
+	}
+	icmpCode := uint32(code)
 
 	conn, err := getClient(cmd)
 	if err != nil {
@@ -106,7 +122,7 @@ func tracePacket(cmd *cobra.Command, args []string) error {
 		DestinationPort: uint32(dport),
 		Direction:       direction,
 		TcpFlags:        tcpFlags,
-		IcmpType:        &icmpType,
+		IcmpType:        icmpType,
 		IcmpCode:        &icmpCode,
 	})
 	if err != nil {
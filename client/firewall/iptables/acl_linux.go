@@ -21,6 +21,9 @@ const (
 
 	// rules chains contains the effective ACL rules
 	chainNameInputRules = "NETBIRD-ACL-INPUT"
+
+	// ipsetSuffixV6 separates the IPv6 ipsets from the IPv4 ones, ipsets are shared by both protocols
+	ipsetSuffixV6 = "-v6"
 )
 
 type aclEntries map[string][][]string
@@ -36,6 +39,8 @@ type aclManager struct {
 	entries         aclEntries
 	optionalEntries map[string][]entry
 	ipsetStore      *ipsetStore
+	// v6 is true when the manager filters the IPv6 overlay traffic through ip6tables
+	v6 bool
 
 	stateManager *statemanager.Manager
 }
@@ -47,6 +52,7 @@ func newAclManager(iptablesClient *iptables.IPTables, wgIface iFaceMapper) (*acl
 		entries:         make(map[string][][]string),
 		optionalEntries: make(map[string][]entry),
 		ipsetStore:      newIpsetStore(),
+		v6:              iptablesClient.Proto() == iptables.ProtocolIPv6,
 	}, nil
 }
 
@@ -81,7 +87,15 @@ func (m *aclManager) AddPeerFiltering(
 	chain := chainNameInputRules
 
 	ipsetName = transformIPsetName(ipsetName, sPort, dPort, action)
-	specs := filterRuleSpecs(ip, string(protocol), sPort, dPort, action, ipsetName)
+	if ipsetName != "" && m.v6 {
+		ipsetName += ipsetSuffixV6
+	}
+
+	proto := string(protocol)
+	if m.v6 && protocol == firewall.ProtocolICMP {
+		proto = "ipv6-icmp"
+	}
+	specs := filterRuleSpecs(ip, proto, sPort, dPort, action, ipsetName)
 
 	mangleSpecs := slices.Clone(specs)
 	mangleSpecs = append(mangleSpecs,
@@ -145,7 +159,10 @@ func (m *aclManager) AddPeerFiltering(
 		return nil, err
 	}
 
-	if err := m.iptablesClient.Append(tableMangle, chainRTPRE, mangleSpecs...); err != nil {
+	// the redirect mark chain is managed by the router, which only exists for IPv4
+	if m.v6 {
+		mangleSpecs = nil
+	} else if err := m.iptablesClient.Append(tableMangle, chainRTPRE, mangleSpecs...); err != nil {
 		log.Errorf("failed to add mangle rule: %v", err)
 		mangleSpecs = nil
 	}
@@ -341,11 +358,20 @@ func (m *aclManager) seedInitialEntries() {
 	// For outbound we respect the FORWARD policy. However, we need to allow established/related traffic for inbound rules.
 	m.appendToEntries("FORWARD", []string{"-i", m.wgIface.Name(), "-j", "DROP"})
 
+	// routing is not supported over IPv6 yet, the routing chains only exist for IPv4
+	if m.v6 {
+		return
+	}
+
 	m.appendToEntries("FORWARD", []string{"-o", m.wgIface.Name(), "-j", chainRTFWDOUT})
 	m.appendToEntries("FORWARD", []string{"-i", m.wgIface.Name(), "-j", chainRTFWDIN})
 }
 
 func (m *aclManager) seedInitialOptionalEntries() {
+	if m.v6 {
+		return
+	}
+
 	m.optionalEntries["FORWARD"] = []entry{
 		{
 			spec:     []string{"-m", "mark", "--mark", fmt.Sprintf("%#x", nbnet.PreroutingFwmarkRedirected), "-j", "ACCEPT"},
@@ -376,8 +402,13 @@ func (m *aclManager) updateState() {
 	currentState.Lock()
 	defer currentState.Unlock()
 
-	currentState.ACLEntries = m.entries
-	currentState.ACLIPsetStore = m.ipsetStore
+	if m.v6 {
+		currentState.ACLEntries6 = m.entries
+		currentState.ACLIPsetStore6 = m.ipsetStore
+	} else {
+		currentState.ACLEntries = m.entries
+		currentState.ACLIPsetStore = m.ipsetStore
+	}
 
 	if err := m.stateManager.UpdateState(currentState); err != nil {
 		log.Errorf("failed to update state: %v", err)
@@ -437,6 +468,9 @@ func (m *aclManager) createIPSet(name string) error {
 	opts := ipset.CreateOptions{
 		Replace: true,
 	}
+	if m.v6 {
+		opts.Family = ipset.FamilyIPV6
+	}
 
 	if err := ipset.Create(name, ipset.TypeHashNet, opts); err != nil {
 		return fmt.Errorf("create ipset %s: %w", name, err)
@@ -26,6 +26,10 @@ type Manager struct {
 	ipv4Client *iptables.IPTables
 	aclMgr     *aclManager
 	router     *router
+
+	// aclMgr6 filters the IPv6 overlay traffic, it is nil when the interface has no IPv6 address
+	// or ip6tables is not available
+	aclMgr6 *aclManager
 }
 
 // iFaceMapper defines subset methods of interface required for manager
@@ -57,9 +61,29 @@ func Create(wgIface iFaceMapper, mtu uint16) (*Manager, error) {
 		return nil, fmt.Errorf("create acl manager: %w", err)
 	}
 
+	if wgIface.Address().HasIPv6() {
+		if err := m.createAclManager6(); err != nil {
+			log.Warnf("IPv6 overlay traffic will not be filtered: %v", err)
+		}
+	}
+
 	return m, nil
 }
 
+func (m *Manager) createAclManager6() error {
+	ipv6Client, err := iptables.NewWithProtocol(iptables.ProtocolIPv6)
+	if err != nil {
+		return fmt.Errorf("init ip6tables: %w", err)
+	}
+
+	m.aclMgr6, err = newAclManager(ipv6Client, m.wgIface)
+	if err != nil {
+		return fmt.Errorf("create ipv6 acl manager: %w", err)
+	}
+
+	return nil
+}
+
 func (m *Manager) Init(stateManager *statemanager.Manager) error {
 	state := &ShutdownState{
 		InterfaceState: &InterfaceState{
@@ -83,6 +107,12 @@ func (m *Manager) Init(stateManager *statemanager.Manager) error {
 		return fmt.Errorf("acl manager init: %w", err)
 	}
 
+	if m.aclMgr6 != nil {
+		if err := m.aclMgr6.init(stateManager); err != nil {
+			return fmt.Errorf("ipv6 acl manager init: %w", err)
+		}
+	}
+
 	// persist early to ensure cleanup of chains
 	go func() {
 		if err := stateManager.PersistState(context.Background()); err != nil {
@@ -108,6 +138,13 @@ func (m *Manager) AddPeerFiltering(
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
+	if ip.To4() == nil {
+		if m.aclMgr6 == nil || ip.To16() == nil {
+			return nil, fmt.Errorf("unsupported IP version: %s", ip.String())
+		}
+		return m.aclMgr6.AddPeerFiltering(id, ip, proto, sPort, dPort, action, ipsetName)
+	}
+
 	return m.aclMgr.AddPeerFiltering(id, ip, proto, sPort, dPort, action, ipsetName)
 }
 
@@ -134,6 +171,12 @@ func (m *Manager) DeletePeerRule(rule firewall.Rule) error {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
+	if r, ok := rule.(*Rule); ok && m.aclMgr6 != nil {
+		if ip := net.ParseIP(r.ip); ip != nil && ip.To4() == nil {
+			return m.aclMgr6.DeletePeerRule(rule)
+		}
+	}
+
 	return m.aclMgr.DeletePeerRule(rule)
 }
 
@@ -180,6 +223,11 @@ func (m *Manager) Close(stateManager *statemanager.Manager) error {
 	if err := m.aclMgr.Reset(); err != nil {
 		merr = multierror.Append(merr, fmt.Errorf("reset acl manager: %w", err))
 	}
+	if m.aclMgr6 != nil {
+		if err := m.aclMgr6.Reset(); err != nil {
+			merr = multierror.Append(merr, fmt.Errorf("reset ipv6 acl manager: %w", err))
+		}
+	}
 	if err := m.router.Reset(); err != nil {
 		merr = multierror.Append(merr, fmt.Errorf("reset router: %w", err))
 	}
@@ -212,6 +260,12 @@ func (m *Manager) AllowNetbird() error {
 	if err != nil {
 		return fmt.Errorf("allow netbird interface traffic: %w", err)
 	}
+
+	if m.aclMgr6 != nil {
+		if _, err := m.AddPeerFiltering(nil, net.IPv6unspecified, firewall.ProtocolALL, nil, nil, firewall.ActionAccept, ""); err != nil {
+			return fmt.Errorf("allow netbird interface ipv6 traffic: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -145,7 +145,7 @@ func (m *AclManager) DeletePeerRule(rule firewall.Rule) error {
 	}
 
 	if _, ok := ips[r.ip.String()]; ok {
-		err := m.sConn.SetDeleteElements(r.nftSet, []nftables.SetElement{{Key: r.ip.To4()}})
+		err := m.sConn.SetDeleteElements(r.nftSet, []nftables.SetElement{{Key: m.rawIP(r.ip)}})
 		if err != nil {
 			log.Errorf("delete elements for set %q: %v", r.nftSet.Name, err)
 		}
@@ -251,17 +251,28 @@ func (m *AclManager) addIOFiltering(
 	var expressions []expr.Any
 
 	if proto != firewall.ProtocolALL {
-		expressions = append(expressions, &expr.Payload{
-			DestRegister: 1,
-			Base:         expr.PayloadBaseNetworkHeader,
-			Offset:       uint32(9),
-			Len:          uint32(1),
-		})
+		if m.isIPv6() {
+			// the IPv6 header has no fixed protocol field, extension headers may come first
+			expressions = append(expressions, &expr.Meta{
+				Key:      expr.MetaKeyL4PROTO,
+				Register: 1,
+			})
+		} else {
+			expressions = append(expressions, &expr.Payload{
+				DestRegister: 1,
+				Base:         expr.PayloadBaseNetworkHeader,
+				Offset:       uint32(9),
+				Len:          uint32(1),
+			})
+		}
 
 		protoData, err := protoToInt(proto)
 		if err != nil {
 			return nil, fmt.Errorf("convert protocol to number: %v", err)
 		}
+		if m.isIPv6() && proto == firewall.ProtocolICMP {
+			protoData = unix.IPPROTO_ICMPV6
+		}
 
 		expressions = append(expressions, &expr.Cmp{
 			Register: 1,
@@ -270,19 +281,22 @@ func (m *AclManager) addIOFiltering(
 		})
 	}
 
-	rawIP := ip.To4()
-	// check if rawIP contains zeroed IPv4 0.0.0.0 value
+	rawIP := m.rawIP(ip)
+	// check if rawIP contains zeroed 0.0.0.0 or :: value
 	// in that case not add IP match expression into the rule definition
 	if !bytes.HasPrefix(anyIP, rawIP) {
 		// source address position
 		addrOffset := uint32(12)
+		if m.isIPv6() {
+			addrOffset = 8
+		}
 
 		expressions = append(expressions,
 			&expr.Payload{
 				DestRegister: 1,
 				Base:         expr.PayloadBaseNetworkHeader,
 				Offset:       addrOffset,
-				Len:          4,
+				Len:          uint32(len(rawIP)),
 			},
 		)
 		// add individual IP for match if no ipset defined
@@ -356,6 +370,11 @@ func (m *AclManager) addIOFiltering(
 }
 
 func (m *AclManager) createPreroutingRule(expressions []expr.Any, userData []byte) *nftables.Rule {
+	// the mangle prerouting chain is managed by the router, which only exists in the IPv4 table
+	if m.isIPv6() {
+		return nil
+	}
+
 	if m.chainPrerouting == nil {
 		log.Warn("prerouting chain is not created")
 		return nil
@@ -438,7 +457,9 @@ func (m *AclManager) createDefaultChains() (err error) {
 
 	// netbird-acl-forward-filter
 	chainFwFilter := m.createFilterChainWithHook(chainNameForwardFilter, nftables.ChainHookForward)
-	m.addJumpRulesToRtForward(chainFwFilter) // to netbird-rt-fwd
+	if !m.isIPv6() {
+		m.addJumpRulesToRtForward(chainFwFilter) // to netbird-rt-fwd
+	}
 	m.addDropExpressions(chainFwFilter, expr.MetaKeyIIFNAME)
 
 	err = m.rConn.Flush()
@@ -447,6 +468,11 @@ func (m *AclManager) createDefaultChains() (err error) {
 		return fmt.Errorf(flushError, err)
 	}
 
+	// routing is not supported over IPv6 yet, there is nothing to redirect
+	if m.isIPv6() {
+		return nil
+	}
+
 	if err := m.allowRedirectedTraffic(chainFwFilter); err != nil {
 		log.Errorf("failed to allow redirected traffic: %s", err)
 	}
@@ -587,7 +613,7 @@ func (m *AclManager) addJumpRule(chain *nftables.Chain, to string, ifaceKey expr
 
 func (m *AclManager) addIpToSet(ipsetName string, ip net.IP) (*nftables.Set, error) {
 	ipset, err := m.rConn.GetSetByName(m.workTable, ipsetName)
-	rawIP := ip.To4()
+	rawIP := m.rawIP(ip)
 	if err != nil {
 		if ipset, err = m.createSet(m.workTable, ipsetName); err != nil {
 			return nil, fmt.Errorf("get set name: %v", err)
@@ -615,11 +641,16 @@ func (m *AclManager) addIpToSet(ipsetName string, ip net.IP) (*nftables.Set, err
 
 // createSet in given table by name
 func (m *AclManager) createSet(table *nftables.Table, name string) (*nftables.Set, error) {
+	keyType := nftables.TypeIPAddr
+	if m.isIPv6() {
+		keyType = nftables.TypeIP6Addr
+	}
+
 	ipset := &nftables.Set{
 		Name:    name,
 		Table:   table,
 		Dynamic: true,
-		KeyType: nftables.TypeIPAddr,
+		KeyType: keyType,
 	}
 
 	if err := m.rConn.AddSet(ipset, nil); err != nil {
@@ -684,6 +715,19 @@ func (m *AclManager) refreshRuleHandles(chain *nftables.Chain, mangle bool) erro
 	return nil
 }
 
+// isIPv6 returns true if the manager works on the ip6 family table
+func (m *AclManager) isIPv6() bool {
+	return m.workTable != nil && m.workTable.Family == nftables.TableFamilyIPv6
+}
+
+// rawIP returns the IP in the byte representation of the table family
+func (m *AclManager) rawIP(ip net.IP) []byte {
+	if m.isIPv6() {
+		return ip.To16()
+	}
+	return ip.To4()
+}
+
 func generatePeerRuleId(ip net.IP, proto firewall.Protocol, sPort *firewall.Port, dPort *firewall.Port, action firewall.Action, ipset *nftables.Set) string {
 	rulesetID := ":" + string(proto) + ":"
 	if sPort != nil {
@@ -50,6 +50,8 @@ type Manager struct {
 
 	router     *router
 	aclManager *AclManager
+	// aclManager6 filters the IPv6 overlay traffic, it is nil when the interface has no IPv6 address
+	aclManager6 *AclManager
 }
 
 // Create nftables firewall manager
@@ -72,12 +74,20 @@ func Create(wgIface iFaceMapper, mtu uint16) (*Manager, error) {
 		return nil, fmt.Errorf("create acl manager: %w", err)
 	}
 
+	if wgIface.Address().HasIPv6() {
+		workTable6 := &nftables.Table{Name: getTableName(), Family: nftables.TableFamilyIPv6}
+		m.aclManager6, err = newAclManager(workTable6, wgIface, "")
+		if err != nil {
+			return nil, fmt.Errorf("create ipv6 acl manager: %w", err)
+		}
+	}
+
 	return m, nil
 }
 
 // Init nftables firewall manager
 func (m *Manager) Init(stateManager *statemanager.Manager) error {
-	workTable, err := m.createWorkTable()
+	workTable, err := m.createWorkTable(nftables.TableFamilyIPv4)
 	if err != nil {
 		return fmt.Errorf("create work table: %w", err)
 	}
@@ -91,6 +101,17 @@ func (m *Manager) Init(stateManager *statemanager.Manager) error {
 		return fmt.Errorf("acl manager init: %w", err)
 	}
 
+	if m.aclManager6 != nil {
+		workTable6, err := m.createWorkTable(nftables.TableFamilyIPv6)
+		if err != nil {
+			return fmt.Errorf("create ipv6 work table: %w", err)
+		}
+
+		if err := m.aclManager6.init(workTable6); err != nil {
+			return fmt.Errorf("ipv6 acl manager init: %w", err)
+		}
+	}
+
 	stateManager.RegisterState(&ShutdownState{})
 
 	// We only need to record minimal interface state for potential recreation.
@@ -134,9 +155,11 @@ func (m *Manager) AddPeerFiltering(
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
-	rawIP := ip.To4()
-	if rawIP == nil {
-		return nil, fmt.Errorf("unsupported IP version: %s", ip.String())
+	if ip.To4() == nil {
+		if m.aclManager6 == nil || ip.To16() == nil {
+			return nil, fmt.Errorf("unsupported IP version: %s", ip.String())
+		}
+		return m.aclManager6.AddPeerFiltering(id, ip, proto, sPort, dPort, action, ipsetName)
 	}
 
 	return m.aclManager.AddPeerFiltering(id, ip, proto, sPort, dPort, action, ipsetName)
@@ -165,6 +188,10 @@ func (m *Manager) DeletePeerRule(rule firewall.Rule) error {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
+	if r, ok := rule.(*Rule); ok && m.aclManager6 != nil && r.ip.To4() == nil {
+		return m.aclManager6.DeletePeerRule(rule)
+	}
+
 	return m.aclManager.DeletePeerRule(rule)
 }
 
@@ -210,6 +237,11 @@ func (m *Manager) AllowNetbird() error {
 	if err := m.aclManager.createDefaultAllowRules(); err != nil {
 		return fmt.Errorf("create default allow rules: %w", err)
 	}
+	if m.aclManager6 != nil {
+		if err := m.aclManager6.createDefaultAllowRules(); err != nil {
+			return fmt.Errorf("create default ipv6 allow rules: %w", err)
+		}
+	}
 	if err := m.rConn.Flush(); err != nil {
 		return fmt.Errorf("flush allow input netbird rules: %w", err)
 	}
@@ -288,7 +320,13 @@ func (m *Manager) Flush() error {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
-	return m.aclManager.Flush()
+	if err := m.aclManager.Flush(); err != nil {
+		return err
+	}
+	if m.aclManager6 != nil {
+		return m.aclManager6.Flush()
+	}
+	return nil
 }
 
 // AddDNATRule adds a DNAT rule
@@ -331,8 +369,8 @@ func (m *Manager) RemoveInboundDNAT(localAddr netip.Addr, protocol firewall.Prot
 	return m.router.RemoveInboundDNAT(localAddr, protocol, sourcePort, targetPort)
 }
 
-func (m *Manager) createWorkTable() (*nftables.Table, error) {
-	tables, err := m.rConn.ListTablesOfFamily(nftables.TableFamilyIPv4)
+func (m *Manager) createWorkTable(family nftables.TableFamily) (*nftables.Table, error) {
+	tables, err := m.rConn.ListTablesOfFamily(family)
 	if err != nil {
 		return nil, fmt.Errorf("list of tables: %w", err)
 	}
@@ -344,7 +382,7 @@ func (m *Manager) createWorkTable() (*nftables.Table, error) {
 		}
 	}
 
-	table := m.rConn.AddTable(&nftables.Table{Name: getTableName(), Family: nftables.TableFamilyIPv4})
+	table := m.rConn.AddTable(&nftables.Table{Name: getTableName(), Family: family})
 	err = m.rConn.Flush()
 	return table, err
 }
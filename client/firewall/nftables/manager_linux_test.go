@@ -249,6 +249,80 @@ func TestNftablesManagerRuleOrder(t *testing.T) {
 		denyRuleIndex, acceptRuleIndex)
 }
 
+func TestNftablesManagerIPv6(t *testing.T) {
+	ifaceMock6 := &iFaceMock{
+		NameFunc: ifaceMock.NameFunc,
+		AddressFunc: func() wgaddr.Address {
+			addr := ifaceMock.AddressFunc()
+			require.NoError(t, addr.SetIPv6("fd00:1234::1/64"))
+			return addr
+		},
+	}
+
+	manager, err := Create(ifaceMock6, iface.DefaultMTU)
+	require.NoError(t, err)
+	require.NotNil(t, manager.aclManager6, "expected ipv6 acl manager")
+	require.NoError(t, manager.Init(nil))
+
+	defer func() {
+		err = manager.Close(nil)
+		require.NoError(t, err)
+	}()
+
+	ip := netip.MustParseAddr("fd00:1234::2")
+	testClient := &nftables.Conn{}
+
+	rule, err := manager.AddPeerFiltering(nil, ip.AsSlice(), fw.ProtocolICMP, nil, nil, fw.ActionDrop, "")
+	require.NoError(t, err, "failed to add rule")
+
+	err = manager.Flush()
+	require.NoError(t, err, "failed to flush")
+
+	require.Equal(t, nftables.TableFamilyIPv6, manager.aclManager6.workTable.Family)
+	rules, err := testClient.GetRules(manager.aclManager6.workTable, manager.aclManager6.chainInputRules)
+	require.NoError(t, err, "failed to get rules")
+	require.Len(t, rules, 2, "expected drop and established rules")
+
+	expectedDropExprs := []expr.Any{
+		&expr.Meta{
+			Key:      expr.MetaKeyL4PROTO,
+			Register: 1,
+		},
+		&expr.Cmp{
+			Register: 1,
+			Op:       expr.CmpOpEq,
+			Data:     []byte{unix.IPPROTO_ICMPV6},
+		},
+		&expr.Payload{
+			DestRegister: 1,
+			Base:         expr.PayloadBaseNetworkHeader,
+			Offset:       8,
+			Len:          16,
+		},
+		&expr.Cmp{
+			Op:       expr.CmpOpEq,
+			Register: 1,
+			Data:     ip.AsSlice(),
+		},
+		&expr.Verdict{Kind: expr.VerdictDrop},
+	}
+	compareExprsIgnoringCounters(t, rules[0].Exprs, expectedDropExprs)
+
+	// the ipv4 table must not contain the ipv6 rule
+	rules4, err := testClient.GetRules(manager.aclManager.workTable, manager.aclManager.chainInputRules)
+	require.NoError(t, err, "failed to get rules")
+	require.Len(t, rules4, 1, "expected only the established rule")
+
+	for _, r := range rule {
+		require.NoError(t, manager.DeletePeerRule(r), "failed to delete rule")
+	}
+	require.NoError(t, manager.Flush(), "failed to flush")
+
+	rules, err = testClient.GetRules(manager.aclManager6.workTable, manager.aclManager6.chainInputRules)
+	require.NoError(t, err, "failed to get rules")
+	require.Len(t, rules, 1, "expected 1 rule after deletion")
+}
+
 func TestNFtablesCreatePerformance(t *testing.T) {
 	mock := &iFaceMock{
 		NameFunc: func() string {
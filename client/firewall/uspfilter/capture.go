@@ -0,0 +1,29 @@
+package uspfilter
+
+import (
+	"github.com/netbirdio/netbird/client/firewall/uspfilter/capture"
+)
+
+type packetTap struct {
+	capture.Tap
+}
+
+// SetPacketTap starts passing the filtered packets to the tap, nil removes the tap.
+// Returns capture.ErrCaptureRunning if another tap is already set.
+func (m *Manager) SetPacketTap(tap capture.Tap) error {
+	if tap == nil {
+		m.packetTap.Store(nil)
+		return nil
+	}
+
+	if !m.packetTap.CompareAndSwap(nil, &packetTap{Tap: tap}) {
+		return capture.ErrCaptureRunning
+	}
+	return nil
+}
+
+func (m *Manager) capturePacket(packetData []byte, direction capture.Direction, stage capture.Stage) {
+	if tap := m.packetTap.Load(); tap != nil {
+		tap.Capture(packetData, direction, stage)
+	}
+}
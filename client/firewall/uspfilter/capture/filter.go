@@ -0,0 +1,447 @@
+package capture
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+const (
+	protoICMP   = 1
+	protoTCP    = 6
+	protoUDP    = 17
+	protoICMPv6 = 58
+)
+
+var protocolNames = map[string]uint8{
+	"icmp":  protoICMP,
+	"tcp":   protoTCP,
+	"udp":   protoUDP,
+	"icmp6": protoICMPv6,
+}
+
+// packetInfo holds the header fields the filter expressions match on
+type packetInfo struct {
+	direction Direction
+	version   uint8
+	protocol  uint8
+	src       netip.Addr
+	dst       netip.Addr
+	srcPort   uint16
+	dstPort   uint16
+	hasPorts  bool
+}
+
+// parsePacket extracts the header fields of a raw IP packet. IPv6 extension headers are not followed.
+func parsePacket(data []byte, direction Direction) (packetInfo, bool) {
+	info := packetInfo{direction: direction}
+	if len(data) == 0 {
+		return info, false
+	}
+
+	var transport []byte
+	switch data[0] >> 4 {
+	case 4:
+		if len(data) < 20 {
+			return info, false
+		}
+		ihl := int(data[0]&0x0F) * 4
+		if ihl < 20 || len(data) < ihl {
+			return info, false
+		}
+		info.version = 4
+		info.protocol = data[9]
+		info.src = netip.AddrFrom4([4]byte(data[12:16]))
+		info.dst = netip.AddrFrom4([4]byte(data[16:20]))
+		// only the first fragment carries the transport header
+		if binary.BigEndian.Uint16(data[6:8])&0x1FFF == 0 {
+			transport = data[ihl:]
+		}
+	case 6:
+		if len(data) < 40 {
+			return info, false
+		}
+		info.version = 6
+		info.protocol = data[6]
+		info.src = netip.AddrFrom16([16]byte(data[8:24]))
+		info.dst = netip.AddrFrom16([16]byte(data[24:40]))
+		transport = data[40:]
+	default:
+		return info, false
+	}
+
+	if (info.protocol == protoTCP || info.protocol == protoUDP) && len(transport) >= 4 {
+		info.srcPort = binary.BigEndian.Uint16(transport[0:2])
+		info.dstPort = binary.BigEndian.Uint16(transport[2:4])
+		info.hasPorts = true
+	}
+
+	return info, true
+}
+
+type matcher func(p *packetInfo) bool
+
+// Filter is a compiled filter expression.
+//
+// The expressions use a subset of the tcpdump (pcap-filter) syntax:
+//
+//	ip, ip6, tcp, udp, icmp, icmp6, proto <name|number>
+//	[src|dst] host <address>
+//	[src|dst] net <prefix>
+//	[src|dst] port <port>
+//	[src|dst] portrange <port>-<port>
+//	inbound, outbound
+//
+// Primitives can be combined with and (&&), or (||), not (!) and parentheses. A protocol followed by a primitive
+// is a shorthand for "and", e.g. "tcp port 443". A bare address or prefix matches as host or net.
+type Filter struct {
+	expr  string
+	match matcher
+}
+
+// ParseFilter compiles a filter expression, an empty expression matches all packets
+func ParseFilter(expr string) (*Filter, error) {
+	expr = strings.TrimSpace(expr)
+	f := &Filter{expr: expr}
+	if expr == "" {
+		return f, nil
+	}
+
+	p := &parser{tokens: tokenize(expr)}
+	m, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("parse filter %q: %w", expr, err)
+	}
+	if tok := p.peek(); tok != "" {
+		return nil, fmt.Errorf("parse filter %q: unexpected %q", expr, tok)
+	}
+	f.match = m
+	return f, nil
+}
+
+// String returns the filter expression
+func (f *Filter) String() string {
+	return f.expr
+}
+
+// Match returns true if the raw IP packet matches the filter
+func (f *Filter) Match(data []byte, direction Direction) bool {
+	if f == nil || f.match == nil {
+		return true
+	}
+
+	info, ok := parsePacket(data, direction)
+	if !ok {
+		return false
+	}
+	return f.match(&info)
+}
+
+func tokenize(expr string) []string {
+	var tokens []string
+	var current strings.Builder
+
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			flush()
+		case c == '(' || c == ')':
+			flush()
+			tokens = append(tokens, string(c))
+		case c == '!' && (i+1 >= len(expr) || expr[i+1] != '='):
+			flush()
+			tokens = append(tokens, "!")
+		case (c == '&' || c == '|') && i+1 < len(expr) && expr[i+1] == c:
+			flush()
+			tokens = append(tokens, expr[i:i+2])
+			i++
+		default:
+			current.WriteByte(c)
+		}
+	}
+	flush()
+
+	return tokens
+}
+
+type parser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *parser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() string {
+	tok := p.peek()
+	if tok != "" {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) expectValue(keyword string) (string, error) {
+	v := p.next()
+	if v == "" || v == "(" || v == ")" {
+		return "", fmt.Errorf("%s requires a value", keyword)
+	}
+	return v, nil
+}
+
+func (p *parser) parseOr() (matcher, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for tok := p.peek(); tok == "or" || tok == "||"; tok = p.peek() {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(pi *packetInfo) bool { return l(pi) || right(pi) }
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (matcher, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for tok := p.peek(); tok == "and" || tok == "&&"; tok = p.peek() {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(pi *packetInfo) bool { return l(pi) && right(pi) }
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (matcher, error) {
+	if tok := p.peek(); tok == "not" || tok == "!" {
+		p.next()
+		m, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return func(pi *packetInfo) bool { return !m(pi) }, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (matcher, error) {
+	tok := p.next()
+	switch tok {
+	case "":
+		return nil, fmt.Errorf("unexpected end of expression")
+	case "(":
+		m, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		return m, nil
+	case "inbound":
+		return func(pi *packetInfo) bool { return pi.direction == Inbound }, nil
+	case "outbound":
+		return func(pi *packetInfo) bool { return pi.direction == Outbound }, nil
+	case "ip":
+		return p.withQualifier(func(pi *packetInfo) bool { return pi.version == 4 })
+	case "ip6":
+		return p.withQualifier(func(pi *packetInfo) bool { return pi.version == 6 })
+	case "proto":
+		v, err := p.expectValue(tok)
+		if err != nil {
+			return nil, err
+		}
+		proto, err := parseProtocol(v)
+		if err != nil {
+			return nil, err
+		}
+		return protocolMatcher(proto), nil
+	case "src", "dst", "host", "net", "port", "portrange":
+		return p.parseAddressPrimitive(tok)
+	}
+
+	if proto, ok := protocolNames[tok]; ok {
+		return p.withQualifier(protocolMatcher(proto))
+	}
+
+	if addr, err := netip.ParseAddr(tok); err == nil {
+		return hostMatcher(addr.Unmap(), ""), nil
+	}
+	if prefix, err := netip.ParsePrefix(tok); err == nil {
+		return netMatcher(prefix, ""), nil
+	}
+
+	return nil, fmt.Errorf("unknown primitive %q", tok)
+}
+
+// withQualifier combines a protocol primitive with a directly following address or port primitive
+func (p *parser) withQualifier(m matcher) (matcher, error) {
+	switch tok := p.peek(); tok {
+	case "src", "dst", "host", "net", "port", "portrange":
+		p.next()
+		q, err := p.parseAddressPrimitive(tok)
+		if err != nil {
+			return nil, err
+		}
+		return func(pi *packetInfo) bool { return m(pi) && q(pi) }, nil
+	default:
+		return m, nil
+	}
+}
+
+func (p *parser) parseAddressPrimitive(tok string) (matcher, error) {
+	dir := ""
+	if tok == "src" || tok == "dst" {
+		dir = tok
+		tok = p.next()
+	}
+
+	switch tok {
+	case "host":
+		v, err := p.expectValue(tok)
+		if err != nil {
+			return nil, err
+		}
+		addr, err := netip.ParseAddr(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid host %q: %w", v, err)
+		}
+		return hostMatcher(addr.Unmap(), dir), nil
+	case "net":
+		v, err := p.expectValue(tok)
+		if err != nil {
+			return nil, err
+		}
+		prefix, err := netip.ParsePrefix(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid net %q: %w", v, err)
+		}
+		return netMatcher(prefix, dir), nil
+	case "port":
+		v, err := p.expectValue(tok)
+		if err != nil {
+			return nil, err
+		}
+		port, err := parsePort(v)
+		if err != nil {
+			return nil, err
+		}
+		return portMatcher(port, port, dir), nil
+	case "portrange":
+		v, err := p.expectValue(tok)
+		if err != nil {
+			return nil, err
+		}
+		from, to, ok := strings.Cut(v, "-")
+		if !ok {
+			return nil, fmt.Errorf("invalid port range %q", v)
+		}
+		first, err := parsePort(from)
+		if err != nil {
+			return nil, err
+		}
+		last, err := parsePort(to)
+		if err != nil {
+			return nil, err
+		}
+		if first > last {
+			return nil, fmt.Errorf("invalid port range %q", v)
+		}
+		return portMatcher(first, last, dir), nil
+	case "":
+		return nil, fmt.Errorf("unexpected end of expression after %s", dir)
+	default:
+		// tcpdump allows "src 10.0.0.1" as a shorthand for "src host 10.0.0.1"
+		if addr, err := netip.ParseAddr(tok); err == nil && dir != "" {
+			return hostMatcher(addr.Unmap(), dir), nil
+		}
+		if prefix, err := netip.ParsePrefix(tok); err == nil && dir != "" {
+			return netMatcher(prefix, dir), nil
+		}
+		return nil, fmt.Errorf("unexpected %q", tok)
+	}
+}
+
+func parseProtocol(v string) (uint8, error) {
+	if proto, ok := protocolNames[v]; ok {
+		return proto, nil
+	}
+	n, err := strconv.ParseUint(v, 10, 8)
+	if err != nil {
+		return 0, fmt.Errorf("invalid protocol %q", v)
+	}
+	return uint8(n), nil
+}
+
+func parsePort(v string) (uint16, error) {
+	n, err := strconv.ParseUint(v, 10, 16)
+	if err != nil {
+		return 0, fmt.Errorf("invalid port %q", v)
+	}
+	return uint16(n), nil
+}
+
+func protocolMatcher(proto uint8) matcher {
+	return func(pi *packetInfo) bool { return pi.protocol == proto }
+}
+
+func hostMatcher(addr netip.Addr, dir string) matcher {
+	switch dir {
+	case "src":
+		return func(pi *packetInfo) bool { return pi.src == addr }
+	case "dst":
+		return func(pi *packetInfo) bool { return pi.dst == addr }
+	default:
+		return func(pi *packetInfo) bool { return pi.src == addr || pi.dst == addr }
+	}
+}
+
+func netMatcher(prefix netip.Prefix, dir string) matcher {
+	prefix = prefix.Masked()
+	switch dir {
+	case "src":
+		return func(pi *packetInfo) bool { return prefix.Contains(pi.src) }
+	case "dst":
+		return func(pi *packetInfo) bool { return prefix.Contains(pi.dst) }
+	default:
+		return func(pi *packetInfo) bool { return prefix.Contains(pi.src) || prefix.Contains(pi.dst) }
+	}
+}
+
+func portMatcher(first, last uint16, dir string) matcher {
+	in := func(port uint16) bool { return port >= first && port <= last }
+	switch dir {
+	case "src":
+		return func(pi *packetInfo) bool { return pi.hasPorts && in(pi.srcPort) }
+	case "dst":
+		return func(pi *packetInfo) bool { return pi.hasPorts && in(pi.dstPort) }
+	default:
+		return func(pi *packetInfo) bool { return pi.hasPorts && (in(pi.srcPort) || in(pi.dstPort)) }
+	}
+}
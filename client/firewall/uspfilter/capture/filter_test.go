@@ -0,0 +1,135 @@
+package capture
+
+import (
+	"net"
+	"testing"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func buildPacket(t *testing.T, src, dst string, transport gopacket.SerializableLayer) []byte {
+	t.Helper()
+
+	srcIP, dstIP := net.ParseIP(src), net.ParseIP(dst)
+	var ip gopacket.NetworkLayer
+	var ipLayer gopacket.SerializableLayer
+	if srcIP.To4() != nil {
+		ip4 := &layers.IPv4{Version: 4, TTL: 64, SrcIP: srcIP.To4(), DstIP: dstIP.To4()}
+		ip, ipLayer = ip4, ip4
+	} else {
+		ip6 := &layers.IPv6{Version: 6, HopLimit: 64, SrcIP: srcIP, DstIP: dstIP}
+		ip, ipLayer = ip6, ip6
+	}
+
+	switch l := transport.(type) {
+	case *layers.TCP:
+		require.NoError(t, l.SetNetworkLayerForChecksum(ip))
+		setProtocol(ipLayer, layers.IPProtocolTCP)
+	case *layers.UDP:
+		require.NoError(t, l.SetNetworkLayerForChecksum(ip))
+		setProtocol(ipLayer, layers.IPProtocolUDP)
+	case *layers.ICMPv4:
+		setProtocol(ipLayer, layers.IPProtocolICMPv4)
+	case *layers.ICMPv6:
+		require.NoError(t, l.SetNetworkLayerForChecksum(ip))
+		setProtocol(ipLayer, layers.IPProtocolICMPv6)
+	}
+
+	buf := gopacket.NewSerializeBuffer()
+	opts := gopacket.SerializeOptions{ComputeChecksums: true, FixLengths: true}
+	require.NoError(t, gopacket.SerializeLayers(buf, opts, ipLayer, transport))
+	return buf.Bytes()
+}
+
+func setProtocol(ip gopacket.SerializableLayer, proto layers.IPProtocol) {
+	switch l := ip.(type) {
+	case *layers.IPv4:
+		l.Protocol = proto
+	case *layers.IPv6:
+		l.NextHeader = proto
+	}
+}
+
+func TestFilter_Match(t *testing.T) {
+	tcp := buildPacket(t, "100.64.0.1", "100.64.0.2", &layers.TCP{SrcPort: 50000, DstPort: 443})
+	udp := buildPacket(t, "100.64.0.2", "10.0.0.53", &layers.UDP{SrcPort: 40000, DstPort: 53})
+	icmp := buildPacket(t, "100.64.0.1", "100.64.0.2", &layers.ICMPv4{TypeCode: layers.CreateICMPv4TypeCode(8, 0)})
+	tcp6 := buildPacket(t, "fd00::1", "fd00::2", &layers.TCP{SrcPort: 50000, DstPort: 22})
+	icmp6 := buildPacket(t, "fd00::1", "fd00::2", &layers.ICMPv6{TypeCode: layers.CreateICMPv6TypeCode(128, 0)})
+
+	testCases := []struct {
+		name      string
+		filter    string
+		packet    []byte
+		direction Direction
+		expected  bool
+	}{
+		{"empty filter", "", tcp, Inbound, true},
+		{"protocol", "tcp", tcp, Inbound, true},
+		{"protocol mismatch", "udp", tcp, Inbound, false},
+		{"icmp", "icmp", icmp, Inbound, true},
+		{"icmp6", "icmp6", icmp6, Inbound, true},
+		{"ip version", "ip", tcp, Inbound, true},
+		{"ip6 version", "ip6", tcp, Inbound, false},
+		{"ip6 tcp", "ip6 and tcp port 22", tcp6, Inbound, true},
+		{"host", "host 100.64.0.2", tcp, Inbound, true},
+		{"bare host", "100.64.0.2", tcp, Inbound, true},
+		{"src host", "src host 100.64.0.2", tcp, Inbound, false},
+		{"dst shorthand", "dst 100.64.0.2", tcp, Inbound, true},
+		{"ipv6 host", "host fd00::2", tcp6, Inbound, true},
+		{"net", "net 10.0.0.0/8", udp, Inbound, true},
+		{"dst net mismatch", "dst net 192.168.0.0/16", udp, Inbound, false},
+		{"port", "port 53", udp, Inbound, true},
+		{"src port", "src port 53", udp, Inbound, false},
+		{"protocol shorthand", "udp dst port 53", udp, Inbound, true},
+		{"portrange", "portrange 440-450", tcp, Inbound, true},
+		{"port on icmp", "port 0", icmp, Inbound, false},
+		{"proto number", "proto 17", udp, Inbound, true},
+		{"direction", "inbound", tcp, Inbound, true},
+		{"direction mismatch", "outbound", tcp, Inbound, false},
+		{"and", "tcp and port 443", tcp, Inbound, true},
+		{"or", "udp or icmp", tcp, Inbound, false},
+		{"not", "not port 22", tcp, Inbound, true},
+		{"operators", "!udp && (port 443 || port 80)", tcp, Inbound, true},
+		{"precedence", "udp or tcp and port 80", tcp, Inbound, false},
+		{"parentheses", "(udp or tcp) and not host 100.64.0.1", tcp, Inbound, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := ParseFilter(tc.filter)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, f.Match(tc.packet, tc.direction))
+		})
+	}
+}
+
+func TestParseFilter_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"foo",
+		"host",
+		"host 1.2.3",
+		"port 70000",
+		"portrange 80",
+		"portrange 90-80",
+		"(tcp",
+		"tcp)",
+		"tcp and",
+		"not",
+		"src",
+		"proto xyz",
+	} {
+		_, err := ParseFilter(expr)
+		assert.Error(t, err, "expression %q should not parse", expr)
+	}
+}
+
+func TestFilter_MatchInvalidPacket(t *testing.T) {
+	f, err := ParseFilter("tcp")
+	require.NoError(t, err)
+	assert.False(t, f.Match(nil, Inbound))
+	assert.False(t, f.Match([]byte{0x45, 0x00}, Inbound))
+}
@@ -0,0 +1,292 @@
+// Package capture records the packets passing the userspace filter in the pcapng format.
+//
+// The userspace filter sees the decrypted packets of the WireGuard interface, so a capture works in the userspace
+// and netstack modes where there is no kernel interface to run tcpdump on. Every combination of direction and NAT
+// stage is written as a separate pcapng interface, the packets can be told apart by the interface in Wireshark.
+package capture
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/google/gopacket/pcapgo"
+)
+
+const (
+	// DefaultDuration is the capture duration used when no duration is set
+	DefaultDuration = time.Minute
+	// MaxDuration is the longest allowed capture
+	MaxDuration = time.Hour
+
+	// queueSize is the number of packets buffered between the packet path and the writer
+	queueSize = 1024
+	// flushInterval is how often the buffered pcapng data is written out
+	flushInterval = 500 * time.Millisecond
+)
+
+// ErrCaptureRunning is returned when a capture is started while another one is running
+var ErrCaptureRunning = errors.New("a packet capture is already running")
+
+// Direction of a captured packet
+type Direction int
+
+const (
+	Inbound Direction = iota
+	Outbound
+)
+
+func (d Direction) String() string {
+	if d == Inbound {
+		return "in"
+	}
+	return "out"
+}
+
+// Stage is the point of the filtering path a packet was captured at
+type Stage int
+
+const (
+	// StagePreNAT is the packet as received from the tunnel or from the host
+	StagePreNAT Stage = iota
+	// StagePostNAT is the packet after it was modified by DNAT, unmodified packets are only captured before NAT
+	StagePostNAT
+)
+
+// Tap receives the packets of the filtering path. The packet data must not be retained.
+type Tap interface {
+	Capture(packetData []byte, direction Direction, stage Stage)
+}
+
+// Options of a capture session
+type Options struct {
+	// InterfaceName is the name of the captured interface, used for the pcapng interface names
+	InterfaceName string
+	// Filter is a filter expression, see Filter for the syntax
+	Filter string
+	// Duration limits the capture, defaults to DefaultDuration
+	Duration time.Duration
+	// MaxPackets stops the capture after the given number of packets, zero means no limit
+	MaxPackets uint64
+	// PostNAT additionally captures the packets after NAT modified them
+	PostNAT bool
+	// SnapLen truncates the captured packets, zero means no truncation
+	SnapLen uint32
+}
+
+type packet struct {
+	timestamp time.Time
+	length    int
+	data      []byte
+	iface     int
+}
+
+// Session is a running packet capture. It implements Tap, the captured packets are written by Run.
+type Session struct {
+	opts       Options
+	filter     *Filter
+	writer     *pcapgo.NgWriter
+	interfaces [2][2]int
+
+	packets  chan packet
+	matched  atomic.Uint64
+	dropped  atomic.Uint64
+	captured uint64
+
+	full      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewSession creates a capture session writing pcapng to w. The section and interface headers are written right away.
+func NewSession(w io.Writer, opts Options) (*Session, error) {
+	if opts.Duration <= 0 {
+		opts.Duration = DefaultDuration
+	}
+	if opts.Duration > MaxDuration {
+		return nil, fmt.Errorf("capture duration %s exceeds the maximum of %s", opts.Duration, MaxDuration)
+	}
+
+	filter, err := ParseFilter(opts.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Session{
+		opts:    opts,
+		filter:  filter,
+		packets: make(chan packet, queueSize),
+		full:    make(chan struct{}),
+	}
+
+	if err := s.createWriter(w); err != nil {
+		return nil, fmt.Errorf("create pcapng writer: %w", err)
+	}
+
+	return s, nil
+}
+
+func (s *Session) createWriter(w io.Writer) error {
+	name := s.opts.InterfaceName
+	if name == "" {
+		name = "netbird"
+	}
+
+	newInterface := func(direction Direction, stage Stage) pcapgo.NgInterface {
+		intf := pcapgo.NgInterface{
+			Name:                fmt.Sprintf("%s-%s", name, direction),
+			Description:         fmt.Sprintf("%s %sbound packets before NAT", name, direction),
+			Filter:              s.filter.String(),
+			OS:                  runtime.GOOS,
+			LinkType:            layers.LinkTypeRaw,
+			SnapLength:          s.opts.SnapLen,
+			TimestampResolution: 9,
+		}
+		if stage == StagePostNAT {
+			intf.Name += "-nat"
+			intf.Description = fmt.Sprintf("%s %sbound packets after NAT", name, direction)
+		}
+		return intf
+	}
+
+	writer, err := pcapgo.NewNgWriterInterface(w, newInterface(Inbound, StagePreNAT), pcapgo.NgWriterOptions{
+		SectionInfo: pcapgo.NgSectionInfo{
+			Hardware:    runtime.GOARCH,
+			OS:          runtime.GOOS,
+			Application: "netbird",
+		},
+	})
+	if err != nil {
+		return err
+	}
+	s.interfaces[Inbound][StagePreNAT] = 0
+
+	for _, k := range []struct {
+		direction Direction
+		stage     Stage
+	}{
+		{Outbound, StagePreNAT},
+		{Inbound, StagePostNAT},
+		{Outbound, StagePostNAT},
+	} {
+		if k.stage == StagePostNAT && !s.opts.PostNAT {
+			continue
+		}
+		id, err := writer.AddInterface(newInterface(k.direction, k.stage))
+		if err != nil {
+			return err
+		}
+		s.interfaces[k.direction][k.stage] = id
+	}
+
+	s.writer = writer
+	return writer.Flush()
+}
+
+// Capture queues a packet if it matches the filter. It never blocks, the packet is dropped if the writer falls behind.
+func (s *Session) Capture(packetData []byte, direction Direction, stage Stage) {
+	if stage == StagePostNAT && !s.opts.PostNAT {
+		return
+	}
+	if !s.filter.Match(packetData, direction) {
+		return
+	}
+
+	n := s.matched.Add(1)
+	if s.opts.MaxPackets > 0 && n > s.opts.MaxPackets {
+		return
+	}
+
+	length := len(packetData)
+	if s.opts.SnapLen > 0 && uint32(len(packetData)) > s.opts.SnapLen {
+		packetData = packetData[:s.opts.SnapLen]
+	}
+
+	p := packet{
+		timestamp: time.Now(),
+		length:    length,
+		data:      append([]byte(nil), packetData...),
+		iface:     s.interfaces[direction][stage],
+	}
+
+	select {
+	case s.packets <- p:
+	default:
+		s.dropped.Add(1)
+	}
+
+	if s.opts.MaxPackets > 0 && n == s.opts.MaxPackets {
+		s.closeOnce.Do(func() { close(s.full) })
+	}
+}
+
+// Run writes the captured packets until the context is done, the duration elapsed or the packet limit was reached
+func (s *Session) Run(ctx context.Context) error {
+	timer := time.NewTimer(s.opts.Duration)
+	defer timer.Stop()
+
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case p := <-s.packets:
+			if err := s.write(p); err != nil {
+				return err
+			}
+		case <-ticker.C:
+			if err := s.writer.Flush(); err != nil {
+				return fmt.Errorf("flush pcapng: %w", err)
+			}
+		case <-s.full:
+			return s.drain()
+		case <-timer.C:
+			return s.drain()
+		case <-ctx.Done():
+			return s.drain()
+		}
+	}
+}
+
+// drain writes the queued packets and flushes the writer
+func (s *Session) drain() error {
+	for {
+		select {
+		case p := <-s.packets:
+			if err := s.write(p); err != nil {
+				return err
+			}
+		default:
+			if err := s.writer.Flush(); err != nil {
+				return fmt.Errorf("flush pcapng: %w", err)
+			}
+			return nil
+		}
+	}
+}
+
+func (s *Session) write(p packet) error {
+	ci := gopacket.CaptureInfo{
+		Timestamp:      p.timestamp,
+		CaptureLength:  len(p.data),
+		Length:         p.length,
+		InterfaceIndex: p.iface,
+	}
+	if err := s.writer.WritePacket(ci, p.data); err != nil {
+		return fmt.Errorf("write packet: %w", err)
+	}
+	s.captured++
+	return nil
+}
+
+// Stats returns the number of written packets and the number of packets dropped because the writer fell behind.
+// It must not be called concurrently with Run.
+func (s *Session) Stats() (captured, dropped uint64) {
+	return s.captured, s.dropped.Load()
+}
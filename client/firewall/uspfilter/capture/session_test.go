@@ -0,0 +1,130 @@
+package capture
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/gopacket/layers"
+	"github.com/google/gopacket/pcapgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readPackets(t *testing.T, data []byte) (*pcapgo.NgReader, [][]byte, []int) {
+	t.Helper()
+
+	r, err := pcapgo.NewNgReader(bytes.NewReader(data), pcapgo.DefaultNgReaderOptions)
+	require.NoError(t, err)
+
+	var packets [][]byte
+	var interfaces []int
+	for {
+		pkt, ci, err := r.ReadPacketData()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		packets = append(packets, pkt)
+		interfaces = append(interfaces, ci.InterfaceIndex)
+	}
+	return r, packets, interfaces
+}
+
+func TestSession_CaptureWithFilter(t *testing.T) {
+	var buf bytes.Buffer
+	s, err := NewSession(&buf, Options{InterfaceName: "wt0", Filter: "tcp", Duration: time.Second})
+	require.NoError(t, err)
+
+	tcp := buildPacket(t, "100.64.0.1", "100.64.0.2", &layers.TCP{SrcPort: 50000, DstPort: 443})
+	udp := buildPacket(t, "100.64.0.1", "100.64.0.2", &layers.UDP{SrcPort: 50000, DstPort: 53})
+
+	s.Capture(tcp, Inbound, StagePreNAT)
+	s.Capture(udp, Inbound, StagePreNAT)
+	s.Capture(tcp, Outbound, StagePreNAT)
+	// post NAT packets are not captured unless requested
+	s.Capture(tcp, Outbound, StagePostNAT)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.NoError(t, s.Run(ctx))
+
+	captured, dropped := s.Stats()
+	assert.Equal(t, uint64(2), captured)
+	assert.Equal(t, uint64(0), dropped)
+
+	r, packets, interfaces := readPackets(t, buf.Bytes())
+	require.Len(t, packets, 2)
+	assert.Equal(t, tcp, packets[0])
+	assert.Equal(t, []int{0, 1}, interfaces)
+
+	require.Equal(t, 2, r.NInterfaces())
+	intf, err := r.Interface(0)
+	require.NoError(t, err)
+	assert.Equal(t, "wt0-in", intf.Name)
+	assert.Equal(t, "tcp", intf.Filter)
+	assert.Equal(t, layers.LinkTypeRaw, intf.LinkType)
+	intf, err = r.Interface(1)
+	require.NoError(t, err)
+	assert.Equal(t, "wt0-out", intf.Name)
+}
+
+func TestSession_PostNATAndSnapLen(t *testing.T) {
+	var buf bytes.Buffer
+	s, err := NewSession(&buf, Options{InterfaceName: "wt0", PostNAT: true, SnapLen: 20})
+	require.NoError(t, err)
+
+	tcp := buildPacket(t, "100.64.0.1", "100.64.0.2", &layers.TCP{SrcPort: 50000, DstPort: 443})
+	s.Capture(tcp, Inbound, StagePostNAT)
+	s.Capture(tcp, Outbound, StagePostNAT)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.NoError(t, s.Run(ctx))
+
+	r, packets, interfaces := readPackets(t, buf.Bytes())
+	require.Equal(t, 4, r.NInterfaces())
+	require.Len(t, packets, 2)
+	assert.Len(t, packets[0], 20)
+	assert.Equal(t, []int{2, 3}, interfaces)
+
+	intf, err := r.Interface(3)
+	require.NoError(t, err)
+	assert.Equal(t, "wt0-out-nat", intf.Name)
+}
+
+func TestSession_StopsAtMaxPackets(t *testing.T) {
+	var buf bytes.Buffer
+	s, err := NewSession(&buf, Options{MaxPackets: 2, Duration: time.Minute})
+	require.NoError(t, err)
+
+	tcp := buildPacket(t, "100.64.0.1", "100.64.0.2", &layers.TCP{SrcPort: 50000, DstPort: 443})
+	for i := 0; i < 5; i++ {
+		s.Capture(tcp, Inbound, StagePreNAT)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- s.Run(context.Background())
+	}()
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("capture did not stop after reaching the packet limit")
+	}
+
+	_, packets, _ := readPackets(t, buf.Bytes())
+	assert.Len(t, packets, 2)
+}
+
+func TestNewSession_Invalid(t *testing.T) {
+	_, err := NewSession(io.Discard, Options{Filter: "host"})
+	assert.Error(t, err)
+
+	_, err = NewSession(io.Discard, Options{Duration: 2 * MaxDuration})
+	assert.Error(t, err)
+}
@@ -0,0 +1,71 @@
+package uspfilter
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	fw "github.com/netbirdio/netbird/client/firewall/manager"
+	"github.com/netbirdio/netbird/client/firewall/uspfilter/capture"
+	nbiface "github.com/netbirdio/netbird/client/iface"
+	"github.com/netbirdio/netbird/client/iface/device"
+	"github.com/netbirdio/netbird/client/iface/wgaddr"
+)
+
+type capturedPacket struct {
+	direction capture.Direction
+	stage     capture.Stage
+}
+
+type tapMock struct {
+	packets []capturedPacket
+}
+
+func (t *tapMock) Capture(_ []byte, direction capture.Direction, stage capture.Stage) {
+	t.packets = append(t.packets, capturedPacket{direction: direction, stage: stage})
+}
+
+func TestManager_PacketTap(t *testing.T) {
+	ifaceMock := &IFaceMock{
+		SetFilterFunc: func(device.PacketFilter) error { return nil },
+		AddressFunc: func() wgaddr.Address {
+			return wgaddr.Address{
+				IP:      netip.MustParseAddr("100.10.0.100"),
+				Network: netip.MustParsePrefix("100.10.0.0/16"),
+			}
+		},
+	}
+	m, err := Create(ifaceMock, false, flowLogger, nbiface.DefaultMTU)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, m.Close(nil))
+	})
+
+	pb := &PacketBuilder{
+		SrcIP:    netip.MustParseAddr("100.10.0.100"),
+		DstIP:    netip.MustParseAddr("100.10.0.1"),
+		Protocol: fw.ProtocolUDP,
+		SrcPort:  50000,
+		DstPort:  53,
+	}
+	packet, err := pb.Build()
+	require.NoError(t, err)
+
+	tap := &tapMock{}
+	require.NoError(t, m.SetPacketTap(tap))
+	assert.ErrorIs(t, m.SetPacketTap(&tapMock{}), capture.ErrCaptureRunning, "only one tap can be set")
+
+	m.FilterOutbound(packet, len(packet))
+	m.FilterInbound(packet, len(packet))
+
+	assert.Equal(t, []capturedPacket{
+		{direction: capture.Outbound, stage: capture.StagePreNAT},
+		{direction: capture.Inbound, stage: capture.StagePreNAT},
+	}, tap.packets)
+
+	require.NoError(t, m.SetPacketTap(nil))
+	m.FilterOutbound(packet, len(packet))
+	assert.Len(t, tap.packets, 2, "removed tap must not receive packets")
+}
@@ -19,6 +19,7 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	firewall "github.com/netbirdio/netbird/client/firewall/manager"
+	"github.com/netbirdio/netbird/client/firewall/uspfilter/capture"
 	"github.com/netbirdio/netbird/client/firewall/uspfilter/common"
 	"github.com/netbirdio/netbird/client/firewall/uspfilter/conntrack"
 	"github.com/netbirdio/netbird/client/firewall/uspfilter/forwarder"
@@ -135,6 +136,9 @@ type Manager struct {
 	mtu             uint16
 	mssClampValue   uint16
 	mssClampEnabled bool
+
+	// packetTap receives copies of the filtered packets while a capture is running
+	packetTap atomic.Pointer[packetTap]
 }
 
 // decoder for packages
@@ -148,10 +152,36 @@ type decoder struct {
 	icmp6   layers.ICMPv6
 	decoded []gopacket.LayerType
 	parser  *gopacket.DecodingLayerParser
+	parser6 *gopacket.DecodingLayerParser
 
 	dnatOrigPort uint16
 }
 
+func newDecoder() *decoder {
+	d := &decoder{
+		decoded: []gopacket.LayerType{},
+	}
+	d.parser = gopacket.NewDecodingLayerParser(
+		layers.LayerTypeIPv4,
+		&d.eth, &d.ip4, &d.ip6, &d.icmp4, &d.icmp6, &d.tcp, &d.udp,
+	)
+	d.parser.IgnoreUnsupported = true
+	d.parser6 = gopacket.NewDecodingLayerParser(
+		layers.LayerTypeIPv6,
+		&d.eth, &d.ip4, &d.ip6, &d.icmp4, &d.icmp6, &d.tcp, &d.udp,
+	)
+	d.parser6.IgnoreUnsupported = true
+	return d
+}
+
+// decodePacket decodes the packet with the parser matching the IP version of the packet
+func (d *decoder) decodePacket(packetData []byte) error {
+	if len(packetData) > 0 && packetData[0]>>4 == 6 {
+		return d.parser6.DecodeLayers(packetData, &d.decoded)
+	}
+	return d.parser.DecodeLayers(packetData, &d.decoded)
+}
+
 // Create userspace firewall manager constructor
 func Create(iface common.IFaceMapper, disableServerRoutes bool, flowLogger nftypes.FlowLogger, mtu uint16) (*Manager, error) {
 	return create(iface, nil, disableServerRoutes, flowLogger, mtu)
@@ -206,15 +236,7 @@ func create(iface common.IFaceMapper, nativeFirewall firewall.Manager, disableSe
 	m := &Manager{
 		decoders: sync.Pool{
 			New: func() any {
-				d := &decoder{
-					decoded: []gopacket.LayerType{},
-				}
-				d.parser = gopacket.NewDecodingLayerParser(
-					layers.LayerTypeIPv4,
-					&d.eth, &d.ip4, &d.ip6, &d.icmp4, &d.icmp6, &d.tcp, &d.udp,
-				)
-				d.parser.IgnoreUnsupported = true
-				return d
+				return newDecoder()
 			},
 		},
 		nativeFirewall:      nativeFirewall,
@@ -618,11 +640,13 @@ func (m *Manager) UpdateSet(set firewall.Set, prefixes []netip.Prefix) error {
 
 // FilterOutbound filters outgoing packets
 func (m *Manager) FilterOutbound(packetData []byte, size int) bool {
+	m.capturePacket(packetData, capture.Outbound, capture.StagePreNAT)
 	return m.filterOutbound(packetData, size)
 }
 
 // FilterInbound filters incoming packets
 func (m *Manager) FilterInbound(packetData []byte, size int) bool {
+	m.capturePacket(packetData, capture.Inbound, capture.StagePreNAT)
 	return m.filterInbound(packetData, size)
 }
 
@@ -635,7 +659,7 @@ func (m *Manager) filterOutbound(packetData []byte, size int) bool {
 	d := m.decoders.Get().(*decoder)
 	defer m.decoders.Put(d)
 
-	if err := d.parser.DecodeLayers(packetData, &d.decoded); err != nil {
+	if err := d.decodePacket(packetData); err != nil {
 		return false
 	}
 
@@ -662,8 +686,10 @@ func (m *Manager) filterOutbound(packetData []byte, size int) bool {
 		}
 	}
 
-	m.trackOutbound(d, srcIP, dstIP, packetData, size)
-	m.translateOutboundDNAT(packetData, d)
+	portRewritten := m.trackOutbound(d, srcIP, dstIP, packetData, size)
+	if translated := m.translateOutboundDNAT(packetData, d); translated || portRewritten {
+		m.capturePacket(packetData, capture.Outbound, capture.StagePostNAT)
+	}
 
 	return false
 }
@@ -709,7 +735,7 @@ func getTCPFlags(tcp *layers.TCP) uint8 {
 // clampTCPMSS clamps the TCP MSS option in SYN and SYN-ACK packets to prevent fragmentation.
 // Both sides advertise their MSS during connection establishment, so we need to clamp both.
 func (m *Manager) clampTCPMSS(packetData []byte, d *decoder) bool {
-	if !d.tcp.SYN {
+	if !d.tcp.SYN || d.decoded[0] != layers.LayerTypeIPv4 {
 		return false
 	}
 	if len(d.tcp.Options) == 0 {
@@ -797,7 +823,8 @@ func (m *Manager) recalculateTCPChecksum(packetData []byte, d *decoder, tcpHeade
 	binary.BigEndian.PutUint16(tcpLayer[16:18], checksum)
 }
 
-func (m *Manager) trackOutbound(d *decoder, srcIP, dstIP netip.Addr, packetData []byte, size int) {
+// trackOutbound tracks the outbound packet and returns true if the source port was rewritten back after port DNAT
+func (m *Manager) trackOutbound(d *decoder, srcIP, dstIP netip.Addr, packetData []byte, size int) bool {
 	transport := d.decoded[1]
 	switch transport {
 	case layers.LayerTypeUDP:
@@ -807,7 +834,9 @@ func (m *Manager) trackOutbound(d *decoder, srcIP, dstIP netip.Addr, packetData
 		}
 		if err := m.rewriteUDPPort(packetData, d, origPort, sourcePortOffset); err != nil {
 			m.logger.Error1("failed to rewrite UDP port: %v", err)
+			break
 		}
+		return true
 	case layers.LayerTypeTCP:
 		flags := getTCPFlags(&d.tcp)
 		origPort := m.tcpTracker.TrackOutbound(srcIP, dstIP, uint16(d.tcp.SrcPort), uint16(d.tcp.DstPort), flags, size)
@@ -816,10 +845,17 @@ func (m *Manager) trackOutbound(d *decoder, srcIP, dstIP netip.Addr, packetData
 		}
 		if err := m.rewriteTCPPort(packetData, d, origPort, sourcePortOffset); err != nil {
 			m.logger.Error1("failed to rewrite TCP port: %v", err)
+			break
 		}
+		return true
 	case layers.LayerTypeICMPv4:
 		m.icmpTracker.TrackOutbound(srcIP, dstIP, d.icmp4.Id, d.icmp4.TypeCode, d.icmp4.Payload, size)
+	case layers.LayerTypeICMPv6:
+		if id, typecode, ok := icmpv6Echo(&d.icmp6); ok {
+			m.icmpTracker.TrackOutbound(srcIP, dstIP, id, typecode, d.icmp6.Payload[4:], size)
+		}
 	}
+	return false
 }
 
 func (m *Manager) trackInbound(d *decoder, srcIP, dstIP netip.Addr, ruleID []byte, size int) {
@@ -832,6 +868,10 @@ func (m *Manager) trackInbound(d *decoder, srcIP, dstIP netip.Addr, ruleID []byt
 		m.tcpTracker.TrackInbound(srcIP, dstIP, uint16(d.tcp.SrcPort), uint16(d.tcp.DstPort), flags, ruleID, size, d.dnatOrigPort)
 	case layers.LayerTypeICMPv4:
 		m.icmpTracker.TrackInbound(srcIP, dstIP, d.icmp4.Id, d.icmp4.TypeCode, ruleID, d.icmp4.Payload, size)
+	case layers.LayerTypeICMPv6:
+		if id, typecode, ok := icmpv6Echo(&d.icmp6); ok {
+			m.icmpTracker.TrackInbound(srcIP, dstIP, id, typecode, ruleID, d.icmp6.Payload[4:], size)
+		}
 	}
 
 	d.dnatOrigPort = 0
@@ -897,24 +937,30 @@ func (m *Manager) filterInbound(packetData []byte, size int) bool {
 	}
 
 	// TODO: optimize port DNAT by caching matched rules in conntrack
-	if translated := m.translateInboundPortDNAT(packetData, d, srcIP, dstIP); translated {
+	portTranslated := m.translateInboundPortDNAT(packetData, d, srcIP, dstIP)
+	if portTranslated {
 		// Re-decode after port DNAT translation to update port information
-		if err := d.parser.DecodeLayers(packetData, &d.decoded); err != nil {
+		if err := d.decodePacket(packetData); err != nil {
 			m.logger.Error1("failed to re-decode packet after port DNAT: %v", err)
 			return true
 		}
 		srcIP, dstIP = m.extractIPs(d)
 	}
 
-	if translated := m.translateInboundReverse(packetData, d); translated {
+	translated := m.translateInboundReverse(packetData, d)
+	if translated {
 		// Re-decode after translation to get original addresses
-		if err := d.parser.DecodeLayers(packetData, &d.decoded); err != nil {
+		if err := d.decodePacket(packetData); err != nil {
 			m.logger.Error1("failed to re-decode packet after reverse DNAT: %v", err)
 			return true
 		}
 		srcIP, dstIP = m.extractIPs(d)
 	}
 
+	if portTranslated || translated {
+		m.capturePacket(packetData, capture.Inbound, capture.StagePostNAT)
+	}
+
 	if m.stateful && m.isValidTrackedConnection(d, srcIP, dstIP, size) {
 		return false
 	}
@@ -1085,7 +1131,7 @@ func getPortsFromPacket(d *decoder) (srcPort, dstPort uint16) {
 // It returns true, false if the packet is valid and not a fragment.
 // It returns true, true if the packet is a fragment and valid.
 func (m *Manager) isValidPacket(d *decoder, packetData []byte) (bool, bool) {
-	if err := d.parser.DecodeLayers(packetData, &d.decoded); err != nil {
+	if err := d.decodePacket(packetData); err != nil {
 		m.logger.Trace1("couldn't decode packet, err: %s", err)
 		return false, false
 	}
@@ -1139,21 +1185,58 @@ func (m *Manager) isValidTrackedConnection(d *decoder, srcIP, dstIP netip.Addr,
 			size,
 		)
 
-		// TODO: ICMPv6
+	case layers.LayerTypeICMPv6:
+		id, typecode, ok := icmpv6Echo(&d.icmp6)
+		if !ok {
+			return false
+		}
+		return m.icmpTracker.IsValidInbound(
+			srcIP,
+			dstIP,
+			id,
+			typecode.Type(),
+			size,
+		)
 	}
 
 	return false
 }
 
+// icmpv6Echo returns the identifier of an ICMPv6 echo request or reply together with the equivalent ICMPv4 type code,
+// so the ICMP tracker handles the echo messages of both IP versions the same way.
+func icmpv6Echo(icmp *layers.ICMPv6) (uint16, layers.ICMPv4TypeCode, bool) {
+	var typecode layers.ICMPv4TypeCode
+	switch icmp.TypeCode.Type() {
+	case layers.ICMPv6TypeEchoRequest:
+		typecode = layers.CreateICMPv4TypeCode(layers.ICMPv4TypeEchoRequest, 0)
+	case layers.ICMPv6TypeEchoReply:
+		typecode = layers.CreateICMPv4TypeCode(layers.ICMPv4TypeEchoReply, 0)
+	default:
+		return 0, 0, false
+	}
+
+	// identifier and sequence number follow the ICMPv6 header
+	if len(icmp.Payload) < 4 {
+		return 0, 0, false
+	}
+	return binary.BigEndian.Uint16(icmp.Payload[0:2]), typecode, true
+}
+
 // isSpecialICMP returns true if the packet is a special ICMP packet that should be allowed
 func (m *Manager) isSpecialICMP(d *decoder) bool {
-	if d.decoded[1] != layers.LayerTypeICMPv4 {
+	switch d.decoded[1] {
+	case layers.LayerTypeICMPv4:
+		icmpType := d.icmp4.TypeCode.Type()
+		return icmpType == layers.ICMPv4TypeDestinationUnreachable ||
+			icmpType == layers.ICMPv4TypeTimeExceeded
+	case layers.LayerTypeICMPv6:
+		icmpType := d.icmp6.TypeCode.Type()
+		return icmpType == layers.ICMPv6TypeDestinationUnreachable ||
+			icmpType == layers.ICMPv6TypePacketTooBig ||
+			icmpType == layers.ICMPv6TypeTimeExceeded
+	default:
 		return false
 	}
-
-	icmpType := d.icmp4.TypeCode.Type()
-	return icmpType == layers.ICMPv4TypeDestinationUnreachable ||
-		icmpType == layers.ICMPv4TypeTimeExceeded
 }
 
 func (m *Manager) peerACLsBlock(srcIP netip.Addr, d *decoder, packetData []byte) ([]byte, bool) {
@@ -1251,8 +1334,7 @@ func (m *Manager) routeACLsPass(srcIP, dstIP netip.Addr, protoLayer gopacket.Lay
 }
 
 func (m *Manager) ruleMatches(rule *RouteRule, srcAddr, dstAddr netip.Addr, protoLayer gopacket.LayerType, srcPort, dstPort uint16) bool {
-	// TODO: handle ipv6 vs ipv4 icmp rules
-	if rule.protoLayer != layerTypeAll && rule.protoLayer != protoLayer {
+	if !protoLayerMatches(rule.protoLayer, protoLayer) {
 		return false
 	}
 
@@ -1284,6 +1366,19 @@ func (m *Manager) ruleMatches(rule *RouteRule, srcAddr, dstAddr netip.Addr, prot
 	return sourceMatched
 }
 
+// protoLayerMatches returns true if a rule with the given protocol layer applies to the packet protocol layer.
+// ICMP route rules apply to ICMPv4 and ICMPv6 alike, the address family is matched by the rule prefixes.
+func protoLayerMatches(ruleLayer, packetLayer gopacket.LayerType) bool {
+	if ruleLayer == layerTypeAll || ruleLayer == packetLayer {
+		return true
+	}
+	return isICMPLayer(ruleLayer) && isICMPLayer(packetLayer)
+}
+
+func isICMPLayer(layer gopacket.LayerType) bool {
+	return layer == layers.LayerTypeICMPv4 || layer == layers.LayerTypeICMPv6
+}
+
 // AddUDPPacketHook calls hook when UDP packet from given direction matched
 //
 // Hook function returns flag which indicates should be the matched package dropped or not
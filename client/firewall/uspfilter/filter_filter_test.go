@@ -539,6 +539,60 @@ func TestPeerACLFiltering(t *testing.T) {
 	}
 }
 
+func TestPeerACLFiltering_IPv6(t *testing.T) {
+	ifaceMock := &IFaceMock{
+		SetFilterFunc: func(device.PacketFilter) error { return nil },
+		AddressFunc: func() wgaddr.Address {
+			return wgaddr.Address{
+				IP:      netip.MustParseAddr("100.10.0.100"),
+				Network: netip.MustParsePrefix("100.10.0.0/16"),
+				IPv6:    netip.MustParseAddr("fd00::100"),
+				IPv6Net: netip.MustParsePrefix("fd00::/64"),
+			}
+		},
+	}
+
+	manager, err := Create(ifaceMock, false, flowLogger, iface.DefaultMTU)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, manager.Close(nil))
+	})
+	require.NoError(t, manager.UpdateLocalIPs())
+
+	packet := func(srcIP, dstIP string, protocol fw.Protocol, dstPort uint16, icmpType uint8) []byte {
+		return createTestPacket6(t, srcIP, dstIP, protocol, 12345, dstPort, icmpType)
+	}
+
+	t.Run("peer rule", func(t *testing.T) {
+		_, err := manager.AddPeerFiltering(nil, net.ParseIP("fd00::1"), fw.ProtocolTCP, nil, &fw.Port{Values: []uint16{22}}, fw.ActionAccept, "")
+		require.NoError(t, err)
+
+		require.False(t, manager.FilterInbound(packet("fd00::1", "fd00::100", fw.ProtocolTCP, 22, 0), 0))
+		require.True(t, manager.FilterInbound(packet("fd00::1", "fd00::100", fw.ProtocolTCP, 23, 0), 0))
+		require.True(t, manager.FilterInbound(packet("fd00::2", "fd00::100", fw.ProtocolTCP, 22, 0), 0))
+	})
+
+	t.Run("ICMPv6 echo reply of a tracked request", func(t *testing.T) {
+		require.False(t, manager.FilterOutbound(packet("fd00::100", "fd00::3", fw.ProtocolICMP, 0, layers.ICMPv6TypeEchoRequest), 0))
+
+		require.False(t, manager.FilterInbound(packet("fd00::3", "fd00::100", fw.ProtocolICMP, 0, layers.ICMPv6TypeEchoReply), 0))
+		require.True(t, manager.FilterInbound(packet("fd00::4", "fd00::100", fw.ProtocolICMP, 0, layers.ICMPv6TypeEchoReply), 0),
+			"unsolicited echo reply must be dropped")
+	})
+
+	t.Run("ICMPv6 packet too big", func(t *testing.T) {
+		require.False(t, manager.FilterInbound(packet("fd00::5", "fd00::100", fw.ProtocolICMP, 0, layers.ICMPv6TypePacketTooBig), 0))
+	})
+}
+
+func TestProtoLayerMatches(t *testing.T) {
+	require.True(t, protoLayerMatches(layerTypeAll, layers.LayerTypeICMPv6))
+	require.True(t, protoLayerMatches(layers.LayerTypeTCP, layers.LayerTypeTCP))
+	require.True(t, protoLayerMatches(layers.LayerTypeICMPv4, layers.LayerTypeICMPv6), "ICMP rules apply to ICMPv6")
+	require.False(t, protoLayerMatches(layers.LayerTypeTCP, layers.LayerTypeUDP))
+	require.False(t, protoLayerMatches(layers.LayerTypeICMPv4, layers.LayerTypeTCP))
+}
+
 func createTestPacket(t *testing.T, srcIP, dstIP string, proto fw.Protocol, srcPort, dstPort uint16) []byte {
 	t.Helper()
 
@@ -1498,3 +1552,56 @@ func TestRouteACLSet(t *testing.T) {
 	_, isAllowed = manager.routeACLsPass(srcIP, dstIP, protoToLayer(fw.ProtocolTCP, layers.LayerTypeIPv4), 12345, 80)
 	require.True(t, isAllowed, "After set update, traffic to the added network should be allowed")
 }
+
+func createTestPacket6(t *testing.T, srcIP, dstIP string, proto fw.Protocol, srcPort, dstPort uint16, icmpType uint8) []byte {
+	t.Helper()
+
+	buf := gopacket.NewSerializeBuffer()
+	opts := gopacket.SerializeOptions{
+		ComputeChecksums: true,
+		FixLengths:       true,
+	}
+
+	ipLayer := &layers.IPv6{
+		Version:  6,
+		HopLimit: 64,
+		SrcIP:    net.ParseIP(srcIP),
+		DstIP:    net.ParseIP(dstIP),
+	}
+
+	var err error
+	switch proto {
+	case fw.ProtocolTCP:
+		ipLayer.NextHeader = layers.IPProtocolTCP
+		tcp := &layers.TCP{
+			SrcPort: layers.TCPPort(srcPort),
+			DstPort: layers.TCPPort(dstPort),
+		}
+		require.NoError(t, tcp.SetNetworkLayerForChecksum(ipLayer))
+		err = gopacket.SerializeLayers(buf, opts, ipLayer, tcp)
+
+	case fw.ProtocolUDP:
+		ipLayer.NextHeader = layers.IPProtocolUDP
+		udp := &layers.UDP{
+			SrcPort: layers.UDPPort(srcPort),
+			DstPort: layers.UDPPort(dstPort),
+		}
+		require.NoError(t, udp.SetNetworkLayerForChecksum(ipLayer))
+		err = gopacket.SerializeLayers(buf, opts, ipLayer, udp)
+
+	case fw.ProtocolICMP:
+		ipLayer.NextHeader = layers.IPProtocolICMPv6
+		icmp := &layers.ICMPv6{
+			TypeCode: layers.CreateICMPv6TypeCode(icmpType, 0),
+		}
+		require.NoError(t, icmp.SetNetworkLayerForChecksum(ipLayer))
+		echo := &layers.ICMPv6Echo{Identifier: 1, SeqNumber: 1}
+		err = gopacket.SerializeLayers(buf, opts, ipLayer, icmp, echo)
+
+	default:
+		t.Fatalf("unsupported protocol: %s", proto)
+	}
+
+	require.NoError(t, err)
+	return buf.Bytes()
+}
@@ -7,6 +7,7 @@ import (
 	"sync"
 
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/exp/maps"
 
 	"github.com/netbirdio/netbird/client/firewall/uspfilter/common"
 )
@@ -16,6 +17,9 @@ type localIPManager struct {
 
 	// fixed-size high array for upper byte of a IPv4 address
 	ipv4Bitmap [256]*ipv4LowBitmap
+
+	// ipv6Set holds the local IPv6 addresses, there are only a few of them so a map is sufficient
+	ipv6Set map[netip.Addr]struct{}
 }
 
 // ipv4LowBitmap is a map for the low 16 bits of a IPv4 address
@@ -81,12 +85,16 @@ func (m *localIPManager) checkBitmapBit(ip []byte) bool {
 	return (m.ipv4Bitmap[high].bitmap[index] & (1 << bit)) != 0
 }
 
-func (m *localIPManager) processIP(ip netip.Addr, bitmap *[256]*ipv4LowBitmap, ipv4Set map[netip.Addr]struct{}, ipv4Addresses *[]netip.Addr) error {
+func (m *localIPManager) processIP(ip netip.Addr, bitmap *[256]*ipv4LowBitmap, ipv4Set map[netip.Addr]struct{}, ipv4Addresses *[]netip.Addr, ipv6Set map[netip.Addr]struct{}) error {
+	if ip.Is6() {
+		ipv6Set[ip.WithZone("")] = struct{}{}
+		return nil
+	}
 	m.setBitInBitmap(ip, bitmap, ipv4Set, ipv4Addresses)
 	return nil
 }
 
-func (m *localIPManager) processInterface(iface net.Interface, bitmap *[256]*ipv4LowBitmap, ipv4Set map[netip.Addr]struct{}, ipv4Addresses *[]netip.Addr) {
+func (m *localIPManager) processInterface(iface net.Interface, bitmap *[256]*ipv4LowBitmap, ipv4Set map[netip.Addr]struct{}, ipv4Addresses *[]netip.Addr, ipv6Set map[netip.Addr]struct{}) {
 	addrs, err := iface.Addrs()
 	if err != nil {
 		log.Debugf("get addresses for interface %s failed: %v", iface.Name, err)
@@ -110,7 +118,7 @@ func (m *localIPManager) processInterface(iface net.Interface, bitmap *[256]*ipv
 			continue
 		}
 
-		if err := m.processIP(addr.Unmap(), bitmap, ipv4Set, ipv4Addresses); err != nil {
+		if err := m.processIP(addr.Unmap(), bitmap, ipv4Set, ipv4Addresses, ipv6Set); err != nil {
 			log.Debugf("process IP failed: %v", err)
 		}
 	}
@@ -126,6 +134,9 @@ func (m *localIPManager) UpdateLocalIPs(iface common.IFaceMapper) (err error) {
 	var newIPv4Bitmap [256]*ipv4LowBitmap
 	ipv4Set := make(map[netip.Addr]struct{})
 	var ipv4Addresses []netip.Addr
+	ipv6Set := map[netip.Addr]struct{}{
+		netip.IPv6Loopback(): {},
+	}
 
 	// 127.0.0.0/8
 	newIPv4Bitmap[127] = &ipv4LowBitmap{}
@@ -135,9 +146,14 @@ func (m *localIPManager) UpdateLocalIPs(iface common.IFaceMapper) (err error) {
 	}
 
 	if iface != nil {
-		if err := m.processIP(iface.Address().IP, &newIPv4Bitmap, ipv4Set, &ipv4Addresses); err != nil {
+		if err := m.processIP(iface.Address().IP, &newIPv4Bitmap, ipv4Set, &ipv4Addresses, ipv6Set); err != nil {
 			return err
 		}
+		if iface.Address().HasIPv6() {
+			if err := m.processIP(iface.Address().IPv6, &newIPv4Bitmap, ipv4Set, &ipv4Addresses, ipv6Set); err != nil {
+				return err
+			}
+		}
 	}
 
 	interfaces, err := net.Interfaces()
@@ -145,25 +161,32 @@ func (m *localIPManager) UpdateLocalIPs(iface common.IFaceMapper) (err error) {
 		log.Warnf("failed to get interfaces: %v", err)
 	} else {
 		for _, intf := range interfaces {
-			m.processInterface(intf, &newIPv4Bitmap, ipv4Set, &ipv4Addresses)
+			m.processInterface(intf, &newIPv4Bitmap, ipv4Set, &ipv4Addresses, ipv6Set)
 		}
 	}
 
 	m.mu.Lock()
 	m.ipv4Bitmap = newIPv4Bitmap
+	m.ipv6Set = ipv6Set
 	m.mu.Unlock()
 
 	log.Debugf("Local IPv4 addresses: %v", ipv4Addresses)
+	log.Debugf("Local IPv6 addresses: %v", maps.Keys(ipv6Set))
 	return nil
 }
 
 func (m *localIPManager) IsLocalIP(ip netip.Addr) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if ip.Is6() {
+		_, ok := m.ipv6Set[ip.WithZone("")]
+		return ok
+	}
+
 	if !ip.Is4() {
 		return false
 	}
 
-	m.mu.RLock()
-	defer m.mu.RUnlock()
-
 	return m.checkBitmapBit(ip.AsSlice())
 }
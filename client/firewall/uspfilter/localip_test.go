@@ -72,14 +72,45 @@ func TestLocalIPManager(t *testing.T) {
 			expected: false,
 		},
 		{
-			name: "IPv6 address",
+			name: "IPv6 overlay address matches",
 			setupAddr: wgaddr.Address{
-				IP:      netip.MustParseAddr("fe80::1"),
+				IP:      netip.MustParseAddr("192.168.1.1"),
+				Network: netip.MustParsePrefix("192.168.1.0/24"),
+				IPv6:    netip.MustParseAddr("fd00:1234::1"),
+				IPv6Net: netip.MustParsePrefix("fd00:1234::/64"),
+			},
+			testIP:   netip.MustParseAddr("fd00:1234::1"),
+			expected: true,
+		},
+		{
+			name: "IPv6 overlay address doesn't match",
+			setupAddr: wgaddr.Address{
+				IP:      netip.MustParseAddr("192.168.1.1"),
+				Network: netip.MustParsePrefix("192.168.1.0/24"),
+				IPv6:    netip.MustParseAddr("fd00:1234::1"),
+				IPv6Net: netip.MustParsePrefix("fd00:1234::/64"),
+			},
+			testIP:   netip.MustParseAddr("fd00:1234::2"),
+			expected: false,
+		},
+		{
+			name: "IPv6 address without IPv6 overlay",
+			setupAddr: wgaddr.Address{
+				IP:      netip.MustParseAddr("192.168.1.1"),
 				Network: netip.MustParsePrefix("192.168.1.0/24"),
 			},
-			testIP:   netip.MustParseAddr("fe80::1"),
+			testIP:   netip.MustParseAddr("fd00:1234::1"),
 			expected: false,
 		},
+		{
+			name: "IPv6 loopback",
+			setupAddr: wgaddr.Address{
+				IP:      netip.MustParseAddr("192.168.1.1"),
+				Network: netip.MustParsePrefix("192.168.1.0/24"),
+			},
+			testIP:   netip.MustParseAddr("::1"),
+			expected: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -230,7 +230,7 @@ func (m *Manager) findReverseDNATMapping(translatedAddr netip.Addr) (netip.Addr,
 
 // translateOutboundDNAT applies DNAT translation to outbound packets.
 func (m *Manager) translateOutboundDNAT(packetData []byte, d *decoder) bool {
-	if !m.dnatEnabled.Load() {
+	if !m.dnatEnabled.Load() || d.decoded[0] != layers.LayerTypeIPv4 {
 		return false
 	}
 
@@ -252,7 +252,7 @@ func (m *Manager) translateOutboundDNAT(packetData []byte, d *decoder) bool {
 
 // translateInboundReverse applies reverse DNAT to inbound return traffic.
 func (m *Manager) translateInboundReverse(packetData []byte, d *decoder) bool {
-	if !m.dnatEnabled.Load() {
+	if !m.dnatEnabled.Load() || d.decoded[0] != layers.LayerTypeIPv4 {
 		return false
 	}
 
@@ -468,7 +468,7 @@ func (m *Manager) RemoveInboundDNAT(localAddr netip.Addr, protocol firewall.Prot
 
 // translateInboundPortDNAT applies port-specific DNAT translation to inbound packets.
 func (m *Manager) translateInboundPortDNAT(packetData []byte, d *decoder, srcIP, dstIP netip.Addr) bool {
-	if !m.portDNATEnabled.Load() {
+	if !m.portDNATEnabled.Load() || d.decoded[0] != layers.LayerTypeIPv4 {
 		return false
 	}
 
@@ -1,6 +1,7 @@
 package uspfilter
 
 import (
+	"encoding/binary"
 	"fmt"
 	"net/netip"
 	"time"
@@ -111,7 +112,17 @@ func (t *PacketTrace) AddResultWithForwarder(stage PacketStage, message string,
 	})
 }
 
+// networkLayer is an IPv4 or IPv6 layer
+type networkLayer interface {
+	gopacket.NetworkLayer
+	gopacket.SerializableLayer
+}
+
 func (p *PacketBuilder) Build() ([]byte, error) {
+	if err := p.validateAddresses(); err != nil {
+		return nil, err
+	}
+
 	ip := p.buildIPLayer()
 	pktLayers := []gopacket.SerializableLayer{ip}
 
@@ -129,30 +140,57 @@ func (p *PacketBuilder) Build() ([]byte, error) {
 	return serializePacket(pktLayers)
 }
 
-func (p *PacketBuilder) buildIPLayer() *layers.IPv4 {
+func (p *PacketBuilder) validateAddresses() error {
+	if !p.SrcIP.IsValid() || !p.DstIP.IsValid() {
+		return fmt.Errorf("source and destination IP addresses are required")
+	}
+	if p.SrcIP.Unmap().Is4() != p.DstIP.Unmap().Is4() {
+		return fmt.Errorf("source IP %s and destination IP %s are of different address families", p.SrcIP, p.DstIP)
+	}
+	return nil
+}
+
+func (p *PacketBuilder) isIPv6() bool {
+	return !p.SrcIP.Unmap().Is4()
+}
+
+func (p *PacketBuilder) buildIPLayer() networkLayer {
+	if p.isIPv6() {
+		return &layers.IPv6{
+			Version:    6,
+			HopLimit:   64,
+			NextHeader: layers.IPProtocol(getIPProtocolNumber(p.Protocol, true)),
+			SrcIP:      p.SrcIP.AsSlice(),
+			DstIP:      p.DstIP.AsSlice(),
+		}
+	}
+
 	return &layers.IPv4{
 		Version:  4,
 		TTL:      64,
-		Protocol: layers.IPProtocol(getIPProtocolNumber(p.Protocol)),
-		SrcIP:    p.SrcIP.AsSlice(),
-		DstIP:    p.DstIP.AsSlice(),
+		Protocol: layers.IPProtocol(getIPProtocolNumber(p.Protocol, false)),
+		SrcIP:    p.SrcIP.Unmap().AsSlice(),
+		DstIP:    p.DstIP.Unmap().AsSlice(),
 	}
 }
 
-func (p *PacketBuilder) buildTransportLayer(ip *layers.IPv4) ([]gopacket.SerializableLayer, error) {
+func (p *PacketBuilder) buildTransportLayer(ip networkLayer) ([]gopacket.SerializableLayer, error) {
 	switch p.Protocol {
 	case "tcp":
 		return p.buildTCPLayer(ip)
 	case "udp":
 		return p.buildUDPLayer(ip)
 	case "icmp":
+		if p.isIPv6() {
+			return p.buildICMPv6Layer(ip)
+		}
 		return p.buildICMPLayer()
 	default:
 		return nil, fmt.Errorf("unsupported protocol: %s", p.Protocol)
 	}
 }
 
-func (p *PacketBuilder) buildTCPLayer(ip *layers.IPv4) ([]gopacket.SerializableLayer, error) {
+func (p *PacketBuilder) buildTCPLayer(ip networkLayer) ([]gopacket.SerializableLayer, error) {
 	tcp := &layers.TCP{
 		SrcPort: layers.TCPPort(p.SrcPort),
 		DstPort: layers.TCPPort(p.DstPort),
@@ -170,7 +208,7 @@ func (p *PacketBuilder) buildTCPLayer(ip *layers.IPv4) ([]gopacket.SerializableL
 	return []gopacket.SerializableLayer{tcp}, nil
 }
 
-func (p *PacketBuilder) buildUDPLayer(ip *layers.IPv4) ([]gopacket.SerializableLayer, error) {
+func (p *PacketBuilder) buildUDPLayer(ip networkLayer) ([]gopacket.SerializableLayer, error) {
 	udp := &layers.UDP{
 		SrcPort: layers.UDPPort(p.SrcPort),
 		DstPort: layers.UDPPort(p.DstPort),
@@ -192,6 +230,23 @@ func (p *PacketBuilder) buildICMPLayer() ([]gopacket.SerializableLayer, error) {
 	return []gopacket.SerializableLayer{icmp}, nil
 }
 
+func (p *PacketBuilder) buildICMPv6Layer(ip networkLayer) ([]gopacket.SerializableLayer, error) {
+	icmp := &layers.ICMPv6{
+		TypeCode: layers.CreateICMPv6TypeCode(p.ICMPType, p.ICMPCode),
+	}
+	if err := icmp.SetNetworkLayerForChecksum(ip); err != nil {
+		return nil, fmt.Errorf("set network layer for ICMPv6 checksum: %w", err)
+	}
+	if p.ICMPType == layers.ICMPv6TypeEchoRequest || p.ICMPType == layers.ICMPv6TypeEchoReply {
+		echo := &layers.ICMPv6Echo{
+			Identifier: 1,
+			SeqNumber:  1,
+		}
+		return []gopacket.SerializableLayer{icmp, echo}, nil
+	}
+	return []gopacket.SerializableLayer{icmp}, nil
+}
+
 func serializePacket(layers []gopacket.SerializableLayer) ([]byte, error) {
 	buf := gopacket.NewSerializeBuffer()
 	opts := gopacket.SerializeOptions{
@@ -204,13 +259,16 @@ func serializePacket(layers []gopacket.SerializableLayer) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
-func getIPProtocolNumber(protocol fw.Protocol) int {
+func getIPProtocolNumber(protocol fw.Protocol, ipv6 bool) int {
 	switch protocol {
 	case fw.ProtocolTCP:
 		return int(layers.IPProtocolTCP)
 	case fw.ProtocolUDP:
 		return int(layers.IPProtocolUDP)
 	case fw.ProtocolICMP:
+		if ipv6 {
+			return int(layers.IPProtocolICMPv6)
+		}
 		return int(layers.IPProtocolICMPv4)
 	default:
 		return 0
@@ -234,7 +292,7 @@ func (m *Manager) TracePacket(packetData []byte, direction fw.RuleDirection) *Pa
 	trace := &PacketTrace{Direction: direction}
 
 	// Initial packet decoding
-	if err := d.parser.DecodeLayers(packetData, &d.decoded); err != nil {
+	if err := d.decodePacket(packetData); err != nil {
 		trace.AddResult(StageReceived, fmt.Sprintf("Failed to decode packet: %v", err), false)
 		return trace
 	}
@@ -256,10 +314,12 @@ func (m *Manager) TracePacket(packetData []byte, direction fw.RuleDirection) *Pa
 		trace.DestinationPort = uint16(d.udp.DstPort)
 	case layers.LayerTypeICMPv4:
 		trace.Protocol = "ICMP"
+	case layers.LayerTypeICMPv6:
+		trace.Protocol = "ICMPv6"
 	}
 
-	trace.AddResult(StageReceived, fmt.Sprintf("Received %s packet: %s:%d -> %s:%d",
-		trace.Protocol, srcIP, trace.SourcePort, dstIP, trace.DestinationPort), true)
+	trace.AddResult(StageReceived, fmt.Sprintf("Received %s packet: %s -> %s", trace.Protocol,
+		netip.AddrPortFrom(srcIP, trace.SourcePort), netip.AddrPortFrom(dstIP, trace.DestinationPort)), true)
 
 	if direction == fw.RuleDirectionOUT {
 		return m.traceOutbound(packetData, trace)
@@ -319,6 +379,11 @@ func (m *Manager) buildConntrackStateMessage(d *decoder) string {
 			flags&conntrack.TCPFin != 0)
 	case layers.LayerTypeICMPv4:
 		msg += fmt.Sprintf(" (ICMP ID=%d, Seq=%d)", d.icmp4.Id, d.icmp4.Seq)
+	case layers.LayerTypeICMPv6:
+		if len(d.icmp6.Payload) >= 4 {
+			msg += fmt.Sprintf(" (ICMPv6 ID=%d, Seq=%d)",
+				binary.BigEndian.Uint16(d.icmp6.Payload[0:2]), binary.BigEndian.Uint16(d.icmp6.Payload[2:4]))
+		}
 	}
 	return msg
 }
@@ -395,7 +460,7 @@ func (m *Manager) handleRouteACLs(trace *PacketTrace, d *decoder, srcIP, dstIP n
 	trace.AddResult(StageRouteACL, msg, allowed)
 
 	if allowed && m.forwarder.Load() != nil {
-		m.addForwardingResult(trace, "proxy-remote", fmt.Sprintf("%s:%d", dstIP, dstPort), true)
+		m.addForwardingResult(trace, "proxy-remote", netip.AddrPortFrom(dstIP, dstPort).String(), true)
 	}
 
 	trace.AddResult(StageCompleted, msgProcessingCompleted, allowed)
@@ -415,7 +480,7 @@ func (m *Manager) traceOutbound(packetData []byte, trace *PacketTrace) *PacketTr
 	d := m.decoders.Get().(*decoder)
 	defer m.decoders.Put(d)
 
-	if err := d.parser.DecodeLayers(packetData, &d.decoded); err != nil {
+	if err := d.decodePacket(packetData); err != nil {
 		trace.AddResult(StageCompleted, "Packet dropped - decode error", false)
 		return trace
 	}
@@ -434,7 +499,7 @@ func (m *Manager) traceOutbound(packetData []byte, trace *PacketTrace) *PacketTr
 func (m *Manager) handleInboundDNAT(trace *PacketTrace, packetData []byte, d *decoder, srcIP, dstIP *netip.Addr) bool {
 	portDNATApplied := m.traceInboundPortDNAT(trace, packetData, d)
 	if portDNATApplied {
-		if err := d.parser.DecodeLayers(packetData, &d.decoded); err != nil {
+		if err := d.decodePacket(packetData); err != nil {
 			trace.AddResult(StageInboundPortDNAT, "Failed to re-decode after port DNAT", false)
 			return true
 		}
@@ -444,7 +509,7 @@ func (m *Manager) handleInboundDNAT(trace *PacketTrace, packetData []byte, d *de
 
 	nat1to1Applied := m.traceInbound1to1NAT(trace, packetData, d)
 	if nat1to1Applied {
-		if err := d.parser.DecodeLayers(packetData, &d.decoded); err != nil {
+		if err := d.decodePacket(packetData); err != nil {
 			trace.AddResult(StageInbound1to1NAT, "Failed to re-decode after 1:1 NAT", false)
 			return true
 		}
@@ -509,6 +574,11 @@ func (m *Manager) traceInbound1to1NAT(trace *PacketTrace, packetData []byte, d *
 		return false
 	}
 
+	if len(packetData) < 20 || d.decoded[0] != layers.LayerTypeIPv4 {
+		trace.AddResult(StageInbound1to1NAT, "Not IPv4, skipping 1:1 NAT", true)
+		return false
+	}
+
 	srcIP := netip.AddrFrom4([4]byte{packetData[12], packetData[13], packetData[14], packetData[15]})
 
 	translated := m.translateInboundReverse(packetData, d)
@@ -539,6 +609,11 @@ func (m *Manager) traceOutbound1to1NAT(trace *PacketTrace, packetData []byte, d
 		return false
 	}
 
+	if len(packetData) < 20 || d.decoded[0] != layers.LayerTypeIPv4 {
+		trace.AddResult(StageOutbound1to1NAT, "Not IPv4, skipping 1:1 NAT", true)
+		return false
+	}
+
 	dstIP := netip.AddrFrom4([4]byte{packetData[16], packetData[17], packetData[18], packetData[19]})
 
 	translated := m.translateOutboundDNAT(packetData, d)
@@ -41,6 +41,8 @@ func TestTracePacket(t *testing.T) {
 				return wgaddr.Address{
 					IP:      netip.MustParseAddr("100.10.0.100"),
 					Network: netip.MustParsePrefix("100.10.0.0/16"),
+					IPv6:    netip.MustParseAddr("fd00::100"),
+					IPv6Net: netip.MustParsePrefix("fd00::/64"),
 				}
 			},
 		}
@@ -443,6 +445,174 @@ func TestTracePacket(t *testing.T) {
 			},
 			expectedAllow: false,
 		},
+		{
+			name: "IPv6_LocalTraffic_ACLAllowed",
+			setup: func(m *Manager) {
+				ip := net.ParseIP("fd00::1")
+				proto := fw.ProtocolTCP
+				port := &fw.Port{Values: []uint16{80}}
+				action := fw.ActionAccept
+				_, err := m.AddPeerFiltering(nil, ip, proto, nil, port, action, "")
+				require.NoError(t, err)
+			},
+			packetBuilder: func() *PacketBuilder {
+				return createPacketBuilder("fd00::1", "fd00::100", "tcp", 12345, 80, fw.RuleDirectionIN)
+			},
+			expectedStages: []PacketStage{
+				StageReceived,
+				StageInboundPortDNAT,
+				StageInbound1to1NAT,
+				StageConntrack,
+				StageRouting,
+				StagePeerACL,
+				StageCompleted,
+			},
+			expectedAllow: true,
+		},
+		{
+			name: "IPv6_LocalTraffic_NoRule",
+			setup: func(m *Manager) {
+			},
+			packetBuilder: func() *PacketBuilder {
+				return createPacketBuilder("fd00::1", "fd00::100", "udp", 12345, 53, fw.RuleDirectionIN)
+			},
+			expectedStages: []PacketStage{
+				StageReceived,
+				StageInboundPortDNAT,
+				StageInbound1to1NAT,
+				StageConntrack,
+				StageRouting,
+				StagePeerACL,
+				StageCompleted,
+			},
+			expectedAllow: false,
+		},
+		{
+			name: "IPv6_ConnectionTracking_Hit",
+			setup: func(m *Manager) {
+				srcIP := netip.MustParseAddr("fd00::100")
+				dstIP := netip.MustParseAddr("fd00::1")
+
+				m.tcpTracker.TrackOutbound(srcIP, dstIP, 12345, 80, conntrack.TCPSyn, 0)
+			},
+			packetBuilder: func() *PacketBuilder {
+				pb := createPacketBuilder("fd00::1", "fd00::100", "tcp", 80, 12345, fw.RuleDirectionIN)
+				pb.TCPState = &TCPState{SYN: true, ACK: true}
+				return pb
+			},
+			expectedStages: []PacketStage{
+				StageReceived,
+				StageInboundPortDNAT,
+				StageInbound1to1NAT,
+				StageConntrack,
+				StageCompleted,
+			},
+			expectedAllow: true,
+		},
+		{
+			name: "IPv6_ICMPv6EchoRequest",
+			setup: func(m *Manager) {
+				ip := net.ParseIP("fd00::1")
+				_, err := m.AddPeerFiltering(nil, ip, fw.ProtocolICMP, nil, nil, fw.ActionAccept, "")
+				require.NoError(t, err)
+			},
+			packetBuilder: func() *PacketBuilder {
+				return createICMPPacketBuilder("fd00::1", "fd00::100", 128, 0, fw.RuleDirectionIN)
+			},
+			expectedStages: []PacketStage{
+				StageReceived,
+				StageInboundPortDNAT,
+				StageInbound1to1NAT,
+				StageConntrack,
+				StageRouting,
+				StagePeerACL,
+				StageCompleted,
+			},
+			expectedAllow: true,
+		},
+		{
+			name: "IPv6_ICMPv6EchoReply_ConnectionTracking_Hit",
+			setup: func(m *Manager) {
+				// the outbound echo request is tracked by the outbound filter
+				_, err := m.TracePacketFromBuilder(createICMPPacketBuilder("fd00::100", "fd00::1", 128, 0, fw.RuleDirectionOUT))
+				require.NoError(t, err)
+			},
+			packetBuilder: func() *PacketBuilder {
+				return createICMPPacketBuilder("fd00::1", "fd00::100", 129, 0, fw.RuleDirectionIN)
+			},
+			expectedStages: []PacketStage{
+				StageReceived,
+				StageInboundPortDNAT,
+				StageInbound1to1NAT,
+				StageConntrack,
+				StageCompleted,
+			},
+			expectedAllow: true,
+		},
+		{
+			name: "IPv6_ICMPv6PacketTooBig",
+			setup: func(m *Manager) {
+				ip := net.ParseIP("fd00::1")
+				_, err := m.AddPeerFiltering(nil, ip, fw.ProtocolICMP, nil, nil, fw.ActionDrop, "")
+				require.NoError(t, err)
+			},
+			packetBuilder: func() *PacketBuilder {
+				return createICMPPacketBuilder("fd00::1", "fd00::100", 2, 0, fw.RuleDirectionIN)
+			},
+			expectedStages: []PacketStage{
+				StageReceived,
+				StageInboundPortDNAT,
+				StageInbound1to1NAT,
+				StageConntrack,
+				StageRouting,
+				StagePeerACL,
+				StageCompleted,
+			},
+			expectedAllow: true,
+		},
+		{
+			name: "IPv6_RoutedTraffic_ICMPRouteACL",
+			setup: func(m *Manager) {
+				m.routingEnabled.Store(true)
+				m.nativeRouter.Store(false)
+
+				m.forwarder.Store(&forwarder.Forwarder{})
+
+				src := netip.MustParsePrefix("fd00::1/128")
+				dst := netip.MustParsePrefix("2001:db8::/64")
+				_, err := m.AddRouteFiltering(nil, []netip.Prefix{src}, fw.Network{Prefix: dst}, fw.ProtocolICMP, nil, nil, fw.ActionAccept)
+				require.NoError(t, err)
+			},
+			packetBuilder: func() *PacketBuilder {
+				return createICMPPacketBuilder("fd00::1", "2001:db8::1", 128, 0, fw.RuleDirectionIN)
+			},
+			expectedStages: []PacketStage{
+				StageReceived,
+				StageInboundPortDNAT,
+				StageInbound1to1NAT,
+				StageConntrack,
+				StageRouting,
+				StageRouteACL,
+				StageForwarding,
+				StageCompleted,
+			},
+			expectedAllow: true,
+		},
+		{
+			name: "IPv6_OutboundTraffic",
+			setup: func(m *Manager) {
+			},
+			packetBuilder: func() *PacketBuilder {
+				return createPacketBuilder("fd00::100", "2001:db8::1", "udp", 12345, 53, fw.RuleDirectionOUT)
+			},
+			expectedStages: []PacketStage{
+				StageReceived,
+				StageOutbound1to1NAT,
+				StageOutboundPortReverse,
+				StageCompleted,
+			},
+			expectedAllow: true,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -466,3 +636,13 @@ func TestTracePacket(t *testing.T) {
 		})
 	}
 }
+
+func TestPacketBuilder_AddressFamilyMismatch(t *testing.T) {
+	pb := &PacketBuilder{
+		SrcIP:    netip.MustParseAddr("100.10.0.1"),
+		DstIP:    netip.MustParseAddr("fd00::1"),
+		Protocol: "tcp",
+	}
+	_, err := pb.Build()
+	require.Error(t, err)
+}
@@ -5,6 +5,7 @@ package device
 import (
 	"fmt"
 	"os/exec"
+	"strconv"
 
 	log "github.com/sirupsen/logrus"
 	"golang.zx2c4.com/wireguard/device"
@@ -148,9 +149,29 @@ func (t *TunDevice) assignAddr() error {
 		log.Errorf("adding route command '%v' failed with output: %s", routeCmd.String(), out)
 		return err
 	}
+
+	if t.address.HasIPv6() {
+		t.assignAddrV6()
+	}
 	return nil
 }
 
+// assignAddrV6 adds the IPv6 overlay address and its network route. Failures are logged only so that the IPv4
+// overlay keeps working.
+func (t *TunDevice) assignAddrV6() {
+	prefix := t.address.IPv6Prefix()
+	cmd := exec.Command("ifconfig", t.name, "inet6", prefix.Addr().String(), "prefixlen", strconv.Itoa(prefix.Bits()), "alias")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		log.Errorf("adding address command '%v' failed with output: %s", cmd.String(), out)
+		return
+	}
+
+	routeCmd := exec.Command("route", "add", "-inet6", "-net", t.address.IPv6Net.String(), "-interface", t.name)
+	if out, err := routeCmd.CombinedOutput(); err != nil {
+		log.Errorf("adding route command '%v' failed with output: %s", routeCmd.String(), out)
+	}
+}
+
 func (t *TunDevice) GetNet() *netstack.Net {
 	return nil
 }
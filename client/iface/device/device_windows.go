@@ -178,8 +178,12 @@ func (t *TunDevice) GetInterfaceGUIDString() (string, error) {
 // assignAddr Adds IP address to the tunnel interface and network route based on the range provided
 func (t *TunDevice) assignAddr() error {
 	luid := winipcfg.LUID(t.nativeTunDevice.LUID())
-	log.Debugf("adding address %s to interface: %s", t.address.IP, t.name)
-	return luid.SetIPAddresses([]netip.Prefix{netip.MustParsePrefix(t.address.String())})
+	prefixes := []netip.Prefix{netip.MustParsePrefix(t.address.String())}
+	if t.address.HasIPv6() {
+		prefixes = append(prefixes, t.address.IPv6Prefix())
+	}
+	log.Debugf("adding addresses %v to interface: %s", prefixes, t.name)
+	return luid.SetIPAddresses(prefixes)
 }
 
 func (t *TunDevice) GetNet() *netstack.Net {
@@ -81,6 +81,14 @@ func (l *wgLink) assignAddr(address wgaddr.Address) error {
 		return fmt.Errorf("assign addr: %w", err)
 	}
 
+	if address.HasIPv6() {
+		prefix := address.IPv6Prefix()
+		log.Infof("assign addr %s to %s interface", prefix, l.name)
+		if err := link.AssignAddr6(prefix.Addr().String(), prefix.Bits()); err != nil {
+			log.Errorf("failed to assign IPv6 address %s: %v", prefix, err)
+		}
+	}
+
 	err = link.Up()
 	if err != nil {
 		return fmt.Errorf("up: %w", err)
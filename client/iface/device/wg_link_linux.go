@@ -4,10 +4,12 @@ package device
 
 import (
 	"fmt"
+	"net/netip"
 	"os"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
 
 	"github.com/netbirdio/netbird/client/iface/wgaddr"
 )
@@ -126,6 +128,10 @@ func (l *wgLink) assignAddr(address wgaddr.Address) error {
 		return fmt.Errorf("add addr: %w", err)
 	}
 
+	if address.HasIPv6() {
+		l.assignAddrV6(address.IPv6Prefix())
+	}
+
 	// On linux, the link must be brought up
 	if err := netlink.LinkSetUp(l); err != nil {
 		return fmt.Errorf("link setup: %w", err)
@@ -133,3 +139,25 @@ func (l *wgLink) assignAddr(address wgaddr.Address) error {
 
 	return nil
 }
+
+// assignAddrV6 adds the IPv6 overlay address to the interface. Failures are logged only so that the IPv4 overlay
+// keeps working on hosts with IPv6 disabled.
+func (l *wgLink) assignAddrV6(prefix netip.Prefix) {
+	name := l.attrs.Name
+	log.Debugf("adding address %s to interface: %s", prefix, name)
+
+	addr, err := netlink.ParseAddr(prefix.String())
+	if err != nil {
+		log.Errorf("failed to parse IPv6 address %s: %v", prefix, err)
+		return
+	}
+	// the overlay address must be usable right away, don't wait for duplicate address detection
+	addr.Flags |= unix.IFA_F_NODAD
+
+	err = netlink.AddrAdd(l, addr)
+	if os.IsExist(err) {
+		log.Infof("interface %s already has the address: %s", name, prefix)
+	} else if err != nil {
+		log.Errorf("failed to add IPv6 address %s to interface %s: %v", prefix, name, err)
+	}
+}
@@ -103,6 +103,22 @@ func (l *Link) AssignAddr(ip, netmask string) error {
 	return l.setAddr(ip, netmask)
 }
 
+// AssignAddr6 adds an IPv6 address with the given prefix length to the interface
+func (l *Link) AssignAddr6(ip string, prefixLen int) error {
+	var stderr bytes.Buffer
+
+	cmd := exec.Command("ifconfig", l.name, "inet6", ip, "prefixlen", strconv.Itoa(prefixLen), "alias")
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		log.Debugf("ifconfig out: %s", stderr.String())
+
+		return fmt.Errorf("set interface inet6 addr: %w", err)
+	}
+
+	return nil
+}
+
 func (l *Link) Up() error {
 	return l.up(l.name)
 }
@@ -56,6 +56,7 @@ type wgProxyFactory interface {
 type WGIFaceOpts struct {
 	IFaceName    string
 	Address      string
+	AddressV6    string
 	WGPort       int
 	WGPrivKey    string
 	MTU          uint16
@@ -142,6 +143,10 @@ func (w *WGIface) UpdateAddr(newAddr string) error {
 		return err
 	}
 
+	// keep the IPv6 overlay address, it is not part of the IPv4 address string
+	current := w.tun.WgAddress()
+	addr.IPv6, addr.IPv6Net = current.IPv6, current.IPv6Net
+
 	return w.tun.UpdateAddr(addr)
 }
 
@@ -331,3 +336,19 @@ func (w *WGIface) GetNet() *netstack.Net {
 
 	return w.tun.GetNet()
 }
+
+// parseWGAddress parses the IPv4 address and the optional IPv6 address of the interface options
+func parseWGAddress(opts WGIFaceOpts) (wgaddr.Address, error) {
+	addr, err := wgaddr.ParseWGAddress(opts.Address)
+	if err != nil {
+		return wgaddr.Address{}, err
+	}
+
+	if opts.AddressV6 != "" {
+		if err := addr.SetIPv6(opts.AddressV6); err != nil {
+			return wgaddr.Address{}, fmt.Errorf("parse IPv6 address: %w", err)
+		}
+	}
+
+	return addr, nil
+}
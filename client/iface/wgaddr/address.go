@@ -9,6 +9,10 @@ import (
 type Address struct {
 	IP      netip.Addr
 	Network netip.Prefix
+
+	// IPv6 is the optional IPv6 overlay address, it is invalid when the peer has no IPv6 address
+	IPv6    netip.Addr
+	IPv6Net netip.Prefix
 }
 
 // ParseWGAddress parse a string ("1.2.3.4/24") address to WG Address
@@ -23,6 +27,34 @@ func ParseWGAddress(address string) (Address, error) {
 	}, nil
 }
 
+// SetIPv6 parses a string ("fd00::1/64") IPv6 address and sets it as the IPv6 overlay address
+func (addr *Address) SetIPv6(address string) error {
+	prefix, err := netip.ParsePrefix(address)
+	if err != nil {
+		return err
+	}
+	if !prefix.Addr().Is6() || prefix.Addr().Is4In6() {
+		return fmt.Errorf("%s is not an IPv6 address", address)
+	}
+
+	addr.IPv6 = prefix.Addr()
+	addr.IPv6Net = prefix.Masked()
+	return nil
+}
+
+// HasIPv6 returns true if an IPv6 overlay address is set
+func (addr Address) HasIPv6() bool {
+	return addr.IPv6.IsValid()
+}
+
+// IPv6Prefix returns the IPv6 overlay address with the prefix length of its network
+func (addr Address) IPv6Prefix() netip.Prefix {
+	if !addr.HasIPv6() {
+		return netip.Prefix{}
+	}
+	return netip.PrefixFrom(addr.IPv6, addr.IPv6Net.Bits())
+}
+
 func (addr Address) String() string {
 	return fmt.Sprintf("%s/%d", addr.IP.String(), addr.Network.Bits())
 }
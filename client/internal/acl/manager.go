@@ -13,6 +13,7 @@ import (
 
 	"github.com/hashicorp/go-multierror"
 	log "github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/proto"
 
 	nberrors "github.com/netbirdio/netbird/client/errors"
 	firewall "github.com/netbirdio/netbird/client/firewall/manager"
@@ -102,6 +103,8 @@ func (d *DefaultManager) applyPeerACLs(networkMap *mgmProto.NetworkMap) {
 		)
 	}
 
+	rules = appendIPv6PeerRules(rules, networkMap)
+
 	newRulePairs := make(map[id.RuleID][]firewall.Rule)
 	ipsetByRuleSelectors := make(map[string]string)
 
@@ -116,6 +119,11 @@ func (d *DefaultManager) applyPeerACLs(networkMap *mgmProto.NetworkMap) {
 			ipsetByRuleSelectors[selector] = ipsetName
 		}
 		pairID, rulePair, err := d.protoRuleToFirewallRule(r, ipsetName)
+		if err != nil && isIPv6Rule(r) {
+			// IPv6 rules are mirrored locally, don't let a firewall without IPv6 support break the IPv4 rules
+			log.Warnf("failed to apply IPv6 firewall rule: %+v, %v", r, err)
+			continue
+		}
 		if err != nil {
 			log.Errorf("failed to apply firewall rule: %+v, %v", r, err)
 			d.rollBack(newRulePairs)
@@ -141,6 +149,69 @@ func (d *DefaultManager) applyPeerACLs(networkMap *mgmProto.NetworkMap) {
 	d.peerRulesPairs = newRulePairs
 }
 
+// appendIPv6PeerRules mirrors the peer rules for the IPv6 overlay addresses of the remote peers.
+// Management sends the rules for the IPv4 addresses only, the IPv6 address of a peer is taken from its allowed IPs.
+// Rules are only mirrored when this peer has an IPv6 overlay address itself.
+func appendIPv6PeerRules(rules []*mgmProto.FirewallRule, networkMap *mgmProto.NetworkMap) []*mgmProto.FirewallRule {
+	if networkMap.GetPeerConfig().GetAddressV6() == "" {
+		return rules
+	}
+
+	peerIPv6 := map[string]string{
+		netip.IPv4Unspecified().String(): netip.IPv6Unspecified().String(),
+	}
+	for _, peers := range [][]*mgmProto.RemotePeerConfig{networkMap.GetRemotePeers(), networkMap.GetOfflinePeers()} {
+		for _, peer := range peers {
+			v4, v6, ok := peerOverlayAddresses(peer.GetAllowedIps())
+			if ok {
+				peerIPv6[v4.String()] = v6.String()
+			}
+		}
+	}
+
+	mirrored := make([]*mgmProto.FirewallRule, 0, len(rules))
+	for _, r := range rules {
+		ip, err := netip.ParseAddr(r.PeerIP)
+		if err != nil {
+			continue
+		}
+		v6, ok := peerIPv6[ip.Unmap().String()]
+		if !ok {
+			continue
+		}
+		rule := proto.Clone(r).(*mgmProto.FirewallRule)
+		rule.PeerIP = v6
+		mirrored = append(mirrored, rule)
+	}
+
+	return append(rules, mirrored...)
+}
+
+func isIPv6Rule(r *mgmProto.FirewallRule) bool {
+	ip, err := netip.ParseAddr(r.PeerIP)
+	return err == nil && ip.Is6() && !ip.Is4In6()
+}
+
+// peerOverlayAddresses returns the IPv4 and IPv6 overlay addresses from the allowed IPs of a remote peer.
+// The overlay addresses are the first single host IPv4 and IPv6 prefixes.
+func peerOverlayAddresses(allowedIPs []string) (netip.Addr, netip.Addr, bool) {
+	var v4, v6 netip.Addr
+	for _, allowedIP := range allowedIPs {
+		prefix, err := netip.ParsePrefix(allowedIP)
+		if err != nil || !prefix.IsSingleIP() {
+			continue
+		}
+		addr := prefix.Addr()
+		switch {
+		case addr.Is4() && !v4.IsValid():
+			v4 = addr
+		case addr.Is6() && !v6.IsValid():
+			v6 = addr
+		}
+	}
+	return v4, v6, v4.IsValid() && v6.IsValid()
+}
+
 func (d *DefaultManager) applyRouteACLs(rules []*mgmProto.RouteFirewallRule, dynamicResolver bool) error {
 	newRouteRules := make(map[id.RuleID]struct{}, len(rules))
 	var merr *multierror.Error
@@ -16,7 +16,7 @@ import (
 	mgmProto "github.com/netbirdio/netbird/shared/management/proto"
 )
 
-var flowLogger = netflow.NewManager(nil, []byte{}, nil).GetLogger()
+var flowLogger = netflow.NewManager(nil, []byte{}, nil, nil).GetLogger()
 
 func TestDefaultManager(t *testing.T) {
 	networkMap := &mgmProto.NetworkMap{
@@ -272,3 +272,44 @@ func TestPortInfoEmpty(t *testing.T) {
 		})
 	}
 }
+
+func TestAppendIPv6PeerRules(t *testing.T) {
+	rules := []*mgmProto.FirewallRule{
+		{PeerIP: "100.64.0.2", Direction: mgmProto.RuleDirection_IN, Action: mgmProto.RuleAction_ACCEPT, Protocol: mgmProto.RuleProtocol_TCP, Port: "22"},
+		{PeerIP: "100.64.0.3", Direction: mgmProto.RuleDirection_IN, Action: mgmProto.RuleAction_ACCEPT, Protocol: mgmProto.RuleProtocol_ALL},
+		{PeerIP: "100.64.0.4", Direction: mgmProto.RuleDirection_IN, Action: mgmProto.RuleAction_ACCEPT, Protocol: mgmProto.RuleProtocol_ALL},
+		{PeerIP: "0.0.0.0", Direction: mgmProto.RuleDirection_IN, Action: mgmProto.RuleAction_ACCEPT, Protocol: mgmProto.RuleProtocol_ICMP},
+	}
+	networkMap := &mgmProto.NetworkMap{
+		PeerConfig: &mgmProto.PeerConfig{Address: "100.64.0.1/16", AddressV6: "fd00::1/64"},
+		RemotePeers: []*mgmProto.RemotePeerConfig{
+			{WgPubKey: "peer2", AllowedIps: []string{"100.64.0.2/32", "fd00::2/128"}},
+			{WgPubKey: "peer3", AllowedIps: []string{"100.64.0.3/32"}},
+		},
+		OfflinePeers: []*mgmProto.RemotePeerConfig{
+			{WgPubKey: "peer4", AllowedIps: []string{"100.64.0.4/32", "fd00::4/128"}},
+		},
+	}
+
+	t.Run("rules are mirrored for peers with IPv6", func(t *testing.T) {
+		result := appendIPv6PeerRules(rules, networkMap)
+		require.Len(t, result, 7)
+		assert.Equal(t, rules, result[:4], "original rules must be kept")
+
+		mirrored := result[4:]
+		assert.Equal(t, "fd00::2", mirrored[0].PeerIP)
+		assert.Equal(t, "22", mirrored[0].Port)
+		assert.Equal(t, mgmProto.RuleProtocol_TCP, mirrored[0].Protocol)
+		assert.Equal(t, "fd00::4", mirrored[1].PeerIP)
+		assert.Equal(t, "::", mirrored[2].PeerIP)
+		assert.Equal(t, mgmProto.RuleProtocol_ICMP, mirrored[2].Protocol)
+
+		assert.Equal(t, "100.64.0.2", rules[0].PeerIP, "original rule must not be modified")
+	})
+
+	t.Run("rules are not mirrored without local IPv6 address", func(t *testing.T) {
+		networkMap.PeerConfig.AddressV6 = ""
+		result := appendIPv6PeerRules(rules, networkMap)
+		assert.Equal(t, rules, result)
+	})
+}
@@ -480,6 +480,7 @@ func createEngineConfig(key wgtypes.Key, config *profilemanager.Config, peerConf
 	engineConf := &EngineConfig{
 		WgIfaceName:                   config.WgIface,
 		WgAddr:                        peerConfig.Address,
+		WgAddrV6:                      peerConfig.GetAddressV6(),
 		IFaceBlackList:                config.IFaceBlackList,
 		DisableIPv6Discovery:          config.DisableIPv6Discovery,
 		WgPrivateKey:                  key,
@@ -9,6 +9,7 @@ import (
 	"net/netip"
 	"net/url"
 	"os"
+	"path/filepath"
 	"runtime"
 	"slices"
 	"sort"
@@ -42,6 +43,7 @@ import (
 	"github.com/netbirdio/netbird/client/internal/peer/guard"
 	icemaker "github.com/netbirdio/netbird/client/internal/peer/ice"
 	"github.com/netbirdio/netbird/client/internal/peerstore"
+	"github.com/netbirdio/netbird/client/internal/profilemanager"
 	"github.com/netbirdio/netbird/client/internal/relay"
 	"github.com/netbirdio/netbird/client/internal/rosenpass"
 	"github.com/netbirdio/netbird/client/internal/routemanager"
@@ -85,6 +87,9 @@ type EngineConfig struct {
 	// WgAddr is a Wireguard local address (Netbird Network IP)
 	WgAddr string
 
+	// WgAddrV6 is the optional Wireguard local IPv6 address, empty when IPv6 overlay addressing is disabled
+	WgAddrV6 string
+
 	// WgPrivateKey is a Wireguard private key of our peer (it MUST never leave the machine)
 	WgPrivateKey wgtypes.Key
 
@@ -403,7 +408,7 @@ func (e *Engine) Start(netbirdConfig *mgmProto.NetbirdConfig, mgmtURL *url.URL)
 
 	// start flow manager right after interface creation
 	publicKey := e.config.WgPrivateKey.PublicKey()
-	e.flowManager = netflow.NewManager(e.wgInterface, publicKey[:], e.statusRecorder)
+	e.flowManager = netflow.NewManager(e.wgInterface, publicKey[:], e.statusRecorder, flowLocalOptions())
 
 	if e.config.RosenpassEnabled {
 		log.Infof("rosenpass is enabled")
@@ -828,6 +833,8 @@ func (e *Engine) handleSync(update *mgmProto.SyncResponse) error {
 		return nil
 	}
 
+	e.statusRecorder.UpdatePostureCheckResults(toPostureCheckResults(update.GetPostureCheckResults()))
+
 	// Store sync response if persistence is enabled
 	if e.persistSyncResponse {
 		e.latestSyncResponse = update
@@ -943,6 +950,13 @@ func (e *Engine) updateConfig(conf *mgmProto.PeerConfig) error {
 	if e.wgInterface.Address().String() != conf.Address {
 		log.Infof("peer IP address has changed from %s to %s", e.wgInterface.Address().String(), conf.Address)
 	}
+	var currentV6 string
+	if e.wgInterface.Address().HasIPv6() {
+		currentV6 = e.wgInterface.Address().IPv6Prefix().String()
+	}
+	if currentV6 != conf.GetAddressV6() {
+		log.Infof("peer IPv6 address has changed from %q to %q", currentV6, conf.GetAddressV6())
+	}
 
 	if conf.GetSshConfig() != nil {
 		if err := e.updateSSH(conf.GetSshConfig()); err != nil {
@@ -1592,6 +1606,7 @@ func (e *Engine) newWgIface() (*iface.WGIface, error) {
 	opts := iface.WGIFaceOpts{
 		IFaceName:    e.config.WgIfaceName,
 		Address:      e.config.WgAddr,
+		AddressV6:    e.config.WgAddrV6,
 		WGPort:       e.config.WgPort,
 		WGPrivKey:    e.config.WgPrivateKey.String(),
 		MTU:          e.config.MTU,
@@ -1679,6 +1694,27 @@ func (e *Engine) GetFirewallManager() firewallManager.Manager {
 	return e.firewall
 }
 
+// GetFlowManager returns the netflow manager
+func (e *Engine) GetFlowManager() nftypes.FlowManager {
+	return e.flowManager
+}
+
+// flowLocalOptions returns the options of the on-disk flow store and the flow exporter if enabled in the environment
+func flowLocalOptions() *netflow.LocalOptions {
+	if runtime.GOOS == "ios" || runtime.GOOS == "android" {
+		return nil
+	}
+
+	opts := &netflow.LocalOptions{
+		Disk:   netflow.DiskStoreOptionsFromEnv(filepath.Join(profilemanager.DefaultConfigPathDir, "flows")),
+		Export: netflow.ExportOptionsFromEnv(),
+	}
+	if opts.Disk == nil && opts.Export == nil {
+		return nil
+	}
+	return opts
+}
+
 func findIPFromInterfaceName(ifaceName string) (net.IP, error) {
 	iface, err := net.InterfaceByName(ifaceName)
 	if err != nil {
@@ -1893,6 +1929,14 @@ func (e *Engine) GetWgAddr() netip.Addr {
 	return e.wgInterface.Address().IP
 }
 
+// GetWgIPv6Addr returns the IPv6 overlay address of the peer, it is invalid if the peer has no IPv6 address
+func (e *Engine) GetWgIPv6Addr() netip.Addr {
+	if e.wgInterface == nil {
+		return netip.Addr{}
+	}
+	return e.wgInterface.Address().IPv6
+}
+
 func (e *Engine) RenewTun(fd int) error {
 	e.syncMsgMux.Lock()
 	wgInterface := e.wgInterface
@@ -2067,6 +2111,22 @@ func (e *Engine) toExcludedLazyPeers(rules []firewallManager.ForwardRule, peers
 	return excludedPeers
 }
 
+// toPostureCheckResults converts the posture check results evaluated by the management server for the status recorder
+func toPostureCheckResults(results []*mgmProto.PostureCheckResult) []peer.PostureCheckResult {
+	converted := make([]peer.PostureCheckResult, 0, len(results))
+	for _, result := range results {
+		converted = append(converted, peer.PostureCheckResult{
+			PostureChecksID:   result.GetPostureCheckId(),
+			PostureChecksName: result.GetPostureCheckName(),
+			Check:             result.GetCheck(),
+			Passed:            result.GetPassed(),
+			Reason:            result.GetReason(),
+			EvaluatedAt:       result.GetEvaluatedAt().AsTime(),
+		})
+	}
+	return converted
+}
+
 // isChecksEqual checks if two slices of checks are equal.
 func isChecksEqual(checks1, checks2 []*mgmProto.Checks) bool {
 	normalize := func(checks []*mgmProto.Checks) []string {
@@ -2075,7 +2135,7 @@ func isChecksEqual(checks1, checks2 []*mgmProto.Checks) bool {
 		for i, check := range checks {
 			sortedFiles := slices.Clone(check.Files)
 			sort.Strings(sortedFiles)
-			normalized[i] = strings.Join(sortedFiles, "|")
+			normalized[i] = fmt.Sprintf("%s|%t|%t", strings.Join(sortedFiles, "|"), check.GetDiskEncryption(), check.GetOsFirewall())
 		}
 
 		sort.Strings(normalized)
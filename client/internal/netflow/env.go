@@ -0,0 +1,92 @@
+package netflow
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/client/internal/netflow/ipfix"
+	"github.com/netbirdio/netbird/client/internal/netflow/store"
+)
+
+const (
+	// EnvKeyFlowStore enables storing the flows on disk for local queries
+	EnvKeyFlowStore = "NB_FLOW_STORE"
+	// EnvKeyFlowStoreMaxSize sets the maximum size of the flow store in megabytes
+	EnvKeyFlowStoreMaxSize = "NB_FLOW_STORE_MAX_SIZE_MB"
+	// EnvKeyFlowStoreMaxAge sets the maximum age of the stored flows, e.g. 72h
+	EnvKeyFlowStoreMaxAge = "NB_FLOW_STORE_MAX_AGE"
+	// EnvKeyFlowExportCollector enables exporting the flows to the IPFIX or NetFlow v9 collector at host:port
+	EnvKeyFlowExportCollector = "NB_FLOW_EXPORT_COLLECTOR"
+	// EnvKeyFlowExportProtocol sets the export protocol, ipfix (default) or netflow9
+	EnvKeyFlowExportProtocol = "NB_FLOW_EXPORT_PROTOCOL"
+	// EnvKeyFlowExportDomainID sets the observation domain id (NetFlow v9 source id) of the exported flows
+	EnvKeyFlowExportDomainID = "NB_FLOW_EXPORT_DOMAIN_ID"
+)
+
+// ErrDiskStoreDisabled is returned when querying flows without the disk store
+var ErrDiskStoreDisabled = errors.New("flow store is disabled, set " + EnvKeyFlowStore + "=true for the daemon to enable it")
+
+// DiskStoreOptionsFromEnv returns the options of the disk store in dir, or nil if it is not enabled
+func DiskStoreOptionsFromEnv(dir string) *store.DiskOptions {
+	if !strings.EqualFold(os.Getenv(EnvKeyFlowStore), "true") {
+		return nil
+	}
+
+	opts := &store.DiskOptions{Dir: dir}
+
+	if v := os.Getenv(EnvKeyFlowStoreMaxSize); v != "" {
+		size, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || size <= 0 {
+			log.Warnf("invalid %s value %q, using the default", EnvKeyFlowStoreMaxSize, v)
+		} else {
+			opts.MaxSize = size << 20
+		}
+	}
+
+	if v := os.Getenv(EnvKeyFlowStoreMaxAge); v != "" {
+		age, err := time.ParseDuration(v)
+		if err != nil || age <= 0 {
+			log.Warnf("invalid %s value %q, using the default", EnvKeyFlowStoreMaxAge, v)
+		} else {
+			opts.MaxAge = age
+		}
+	}
+
+	return opts
+}
+
+// ExportOptionsFromEnv returns the options of the IPFIX/NetFlow v9 exporter, or nil if it is not enabled
+func ExportOptionsFromEnv() *ipfix.Options {
+	collector := os.Getenv(EnvKeyFlowExportCollector)
+	if collector == "" {
+		return nil
+	}
+
+	opts := &ipfix.Options{Collector: collector}
+
+	switch protocol := strings.ToLower(os.Getenv(EnvKeyFlowExportProtocol)); protocol {
+	case "", "ipfix":
+		opts.Version = ipfix.VersionIPFIX
+	case "netflow9", "netflow", "v9":
+		opts.Version = ipfix.VersionNetFlowV9
+	default:
+		log.Warnf("invalid %s value %q, using ipfix", EnvKeyFlowExportProtocol, protocol)
+		opts.Version = ipfix.VersionIPFIX
+	}
+
+	if v := os.Getenv(EnvKeyFlowExportDomainID); v != "" {
+		id, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			log.Warnf("invalid %s value %q, using 0", EnvKeyFlowExportDomainID, v)
+		} else {
+			opts.ObservationDomainID = uint32(id)
+		}
+	}
+
+	return opts
+}
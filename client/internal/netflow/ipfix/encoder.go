@@ -0,0 +1,418 @@
+package ipfix
+
+import (
+	"encoding/binary"
+	"net/netip"
+	"time"
+
+	"github.com/netbirdio/netbird/client/internal/netflow/types"
+)
+
+const (
+	// maxMessageSize keeps the messages below the usual path MTU, UDP messages must not be fragmented
+	maxMessageSize = 1400
+
+	ipfixHeaderLen = 16
+	v9HeaderLen    = 20
+	setHeaderLen   = 4
+
+	ipfixTemplateSetID = 2
+	v9TemplateSetID    = 0
+
+	// reverseEnterprise is the private enterprise number of the reverse information elements (RFC 5103)
+	reverseEnterprise = 29305
+
+	protocolICMPv6 = 58
+)
+
+// template ids, data sets use the id of their template as set id
+const (
+	templateIPv4 uint16 = 256 + iota
+	templateIPv6
+	templateIPv4Counters
+	templateIPv6Counters
+)
+
+type valueKind int
+
+const (
+	valueTimestamp valueKind = iota
+	valueUptime
+	valueSourceAddr
+	valueDestAddr
+	valueSourcePort
+	valueDestPort
+	valueProtocol
+	valueICMPTypeCode
+	valueDirection
+	valueFirewallEvent
+	valueOctets
+	valuePackets
+	valueReverseOctets
+	valueReversePackets
+)
+
+// field is a field specifier of a template
+type field struct {
+	id         uint16
+	enterprise uint32
+	length     uint16
+	value      valueKind
+}
+
+type template struct {
+	id     uint16
+	fields []field
+}
+
+func (t template) recordLength() int {
+	var n int
+	for _, f := range t.fields {
+		n += int(f.length)
+	}
+	return n
+}
+
+// record is an event queued for export
+type record struct {
+	event    *types.Event
+	counters bool
+}
+
+// buildTemplates returns the templates of the version, indexed by template id - templateIPv4.
+// The information elements of NetFlow v9 are a subset of the IPFIX ones with the same ids,
+// except for the timestamps and the reverse counters.
+func buildTemplates(version Version) []template {
+	var timestamps, counters []field
+	icmpV6 := field{id: 139, length: 2, value: valueICMPTypeCode}
+	if version == VersionNetFlowV9 {
+		timestamps = []field{
+			{id: 22, length: 4, value: valueUptime}, // FIRST_SWITCHED
+			{id: 21, length: 4, value: valueUptime}, // LAST_SWITCHED
+		}
+		counters = []field{
+			{id: 1, length: 8, value: valueOctets},         // IN_BYTES
+			{id: 2, length: 8, value: valuePackets},        // IN_PKTS
+			{id: 23, length: 8, value: valueReverseOctets}, // OUT_BYTES
+			{id: 24, length: 8, value: valueReversePackets},
+		}
+		// NetFlow v9 has no separate ICMPv6 type field
+		icmpV6.id = 32
+	} else {
+		timestamps = []field{
+			{id: 152, length: 8, value: valueTimestamp}, // flowStartMilliseconds
+			{id: 153, length: 8, value: valueTimestamp}, // flowEndMilliseconds
+		}
+		counters = []field{
+			{id: 1, length: 8, value: valueOctets},  // octetDeltaCount
+			{id: 2, length: 8, value: valuePackets}, // packetDeltaCount
+			{id: 1, enterprise: reverseEnterprise, length: 8, value: valueReverseOctets},
+			{id: 2, enterprise: reverseEnterprise, length: 8, value: valueReversePackets},
+		}
+	}
+
+	common := []field{
+		{id: 7, length: 2, value: valueSourcePort},
+		{id: 11, length: 2, value: valueDestPort},
+		{id: 4, length: 1, value: valueProtocol},
+		{id: 61, length: 1, value: valueDirection},
+		{id: 233, length: 1, value: valueFirewallEvent},
+	}
+
+	v4 := concat(timestamps, []field{
+		{id: 8, length: 4, value: valueSourceAddr},
+		{id: 12, length: 4, value: valueDestAddr},
+		{id: 32, length: 2, value: valueICMPTypeCode},
+	}, common)
+	v6 := concat(timestamps, []field{
+		{id: 27, length: 16, value: valueSourceAddr},
+		{id: 28, length: 16, value: valueDestAddr},
+		icmpV6,
+	}, common)
+
+	return []template{
+		{id: templateIPv4, fields: v4},
+		{id: templateIPv6, fields: v6},
+		{id: templateIPv4Counters, fields: concat(v4, counters)},
+		{id: templateIPv6Counters, fields: concat(v6, counters)},
+	}
+}
+
+func concat(parts ...[]field) []field {
+	var fields []field
+	for _, p := range parts {
+		fields = append(fields, p...)
+	}
+	return fields
+}
+
+// encoder encodes records into IPFIX or NetFlow v9 messages. It is not safe for concurrent use.
+type encoder struct {
+	version   Version
+	domainID  uint32
+	start     time.Time
+	templates []template
+	// sequence is the number of data records sent for IPFIX and the number of messages sent for NetFlow v9
+	sequence uint32
+}
+
+func newEncoder(version Version, domainID uint32, start time.Time) *encoder {
+	return &encoder{
+		version:   version,
+		domainID:  domainID,
+		start:     start,
+		templates: buildTemplates(version),
+	}
+}
+
+// message is a message under construction
+type message struct {
+	buf []byte
+	// setStart is the offset of the header of the open set, -1 if no set is open
+	setStart int
+	setID    uint16
+	// records is the number of template and data records, dataRecords the number of data records
+	records     int
+	dataRecords int
+}
+
+func (e *encoder) headerLen() int {
+	if e.version == VersionNetFlowV9 {
+		return v9HeaderLen
+	}
+	return ipfixHeaderLen
+}
+
+func (e *encoder) newMessage() *message {
+	hl := e.headerLen()
+	buf := make([]byte, hl, maxMessageSize)
+	return &message{buf: buf, setStart: -1}
+}
+
+// encode encodes the records into messages of at most maxMessageSize bytes.
+// With templates set, the first message starts with the template set.
+func (e *encoder) encode(records []record, now time.Time, templates bool) [][]byte {
+	var messages [][]byte
+
+	m := e.newMessage()
+	if templates {
+		e.addTemplates(m)
+	}
+
+	for _, r := range records {
+		t := e.templateFor(r)
+		if !m.fits(t) {
+			messages = append(messages, e.finish(m, now))
+			m = e.newMessage()
+		}
+		e.addRecord(m, t, r)
+	}
+
+	if m.records > 0 {
+		messages = append(messages, e.finish(m, now))
+	}
+
+	return messages
+}
+
+func (e *encoder) templateFor(r record) template {
+	id := templateIPv4
+	if !r.event.SourceIP.Unmap().Is4() || !r.event.DestIP.Unmap().Is4() {
+		id = templateIPv6
+	}
+	if r.counters {
+		id += templateIPv4Counters - templateIPv4
+	}
+	return e.templates[id-templateIPv4]
+}
+
+func (e *encoder) addTemplates(m *message) {
+	setID := uint16(ipfixTemplateSetID)
+	if e.version == VersionNetFlowV9 {
+		setID = v9TemplateSetID
+	}
+	m.openSet(setID)
+
+	for _, t := range e.templates {
+		m.buf = binary.BigEndian.AppendUint16(m.buf, t.id)
+		m.buf = binary.BigEndian.AppendUint16(m.buf, uint16(len(t.fields)))
+		for _, f := range t.fields {
+			if f.enterprise != 0 {
+				m.buf = binary.BigEndian.AppendUint16(m.buf, f.id|0x8000)
+				m.buf = binary.BigEndian.AppendUint16(m.buf, f.length)
+				m.buf = binary.BigEndian.AppendUint32(m.buf, f.enterprise)
+				continue
+			}
+			m.buf = binary.BigEndian.AppendUint16(m.buf, f.id)
+			m.buf = binary.BigEndian.AppendUint16(m.buf, f.length)
+		}
+		m.records++
+	}
+
+	m.closeSet()
+}
+
+func (m *message) openSet(id uint16) {
+	m.closeSet()
+	m.setStart = len(m.buf)
+	m.setID = id
+	m.buf = append(m.buf, 0, 0, 0, 0)
+	binary.BigEndian.PutUint16(m.buf[m.setStart:], id)
+}
+
+// closeSet pads the open set to a multiple of four bytes and writes its length
+func (m *message) closeSet() {
+	if m.setStart < 0 {
+		return
+	}
+	for (len(m.buf)-m.setStart)%4 != 0 {
+		m.buf = append(m.buf, 0)
+	}
+	binary.BigEndian.PutUint16(m.buf[m.setStart+2:], uint16(len(m.buf)-m.setStart))
+	m.setStart = -1
+}
+
+// fits returns whether a record of the template fits into the message, including a new set header and padding
+func (m *message) fits(t template) bool {
+	size := len(m.buf) + t.recordLength()
+	if m.setStart < 0 || m.setID != t.id {
+		size += 3 + setHeaderLen
+	}
+	return size+3 <= maxMessageSize
+}
+
+func (e *encoder) addRecord(m *message, t template, r record) {
+	if m.setStart < 0 || m.setID != t.id {
+		m.openSet(t.id)
+	}
+
+	for _, f := range t.fields {
+		m.buf = e.appendValue(m.buf, f, r.event)
+	}
+	m.records++
+	m.dataRecords++
+}
+
+func (e *encoder) appendValue(b []byte, f field, event *types.Event) []byte {
+	switch f.value {
+	case valueTimestamp:
+		return binary.BigEndian.AppendUint64(b, uint64(event.Timestamp.UnixMilli()))
+	case valueUptime:
+		return binary.BigEndian.AppendUint32(b, e.uptime(event.Timestamp))
+	case valueSourceAddr:
+		return appendAddr(b, f.length, event.SourceIP)
+	case valueDestAddr:
+		return appendAddr(b, f.length, event.DestIP)
+	case valueSourcePort:
+		return binary.BigEndian.AppendUint16(b, event.SourcePort)
+	case valueDestPort:
+		return binary.BigEndian.AppendUint16(b, event.DestPort)
+	case valueProtocol:
+		return append(b, uint8(event.Protocol))
+	case valueICMPTypeCode:
+		var typeCode uint16
+		if event.Protocol == types.ICMP || event.Protocol == protocolICMPv6 {
+			typeCode = uint16(event.ICMPType)<<8 | uint16(event.ICMPCode)
+		}
+		return binary.BigEndian.AppendUint16(b, typeCode)
+	case valueDirection:
+		return append(b, flowDirection(event.Direction))
+	case valueFirewallEvent:
+		return append(b, firewallEvent(event.Type))
+	case valueOctets, valuePackets, valueReverseOctets, valueReversePackets:
+		return binary.BigEndian.AppendUint64(b, counterValue(f.value, event))
+	default:
+		return append(b, make([]byte, f.length)...)
+	}
+}
+
+// uptime returns the milliseconds since the start of the exporter, the time base of NetFlow v9
+func (e *encoder) uptime(t time.Time) uint32 {
+	d := t.Sub(e.start)
+	if d < 0 {
+		return 0
+	}
+	return uint32(d.Milliseconds())
+}
+
+func (e *encoder) finish(m *message, now time.Time) []byte {
+	m.closeSet()
+	b := m.buf
+
+	if e.version == VersionNetFlowV9 {
+		binary.BigEndian.PutUint16(b[0:], uint16(VersionNetFlowV9))
+		binary.BigEndian.PutUint16(b[2:], uint16(m.records))
+		binary.BigEndian.PutUint32(b[4:], e.uptime(now))
+		binary.BigEndian.PutUint32(b[8:], uint32(now.Unix()))
+		binary.BigEndian.PutUint32(b[12:], e.sequence)
+		binary.BigEndian.PutUint32(b[16:], e.domainID)
+		e.sequence++
+		return b
+	}
+
+	binary.BigEndian.PutUint16(b[0:], uint16(VersionIPFIX))
+	binary.BigEndian.PutUint16(b[2:], uint16(len(b)))
+	binary.BigEndian.PutUint32(b[4:], uint32(now.Unix()))
+	binary.BigEndian.PutUint32(b[8:], e.sequence)
+	binary.BigEndian.PutUint32(b[12:], e.domainID)
+	e.sequence += uint32(m.dataRecords)
+	return b
+}
+
+// appendAddr appends the address as IPv4 or, for the IPv6 templates, as IPv6 address
+func appendAddr(b []byte, length uint16, addr netip.Addr) []byte {
+	if length == 4 {
+		a := addr.As4()
+		return append(b, a[:]...)
+	}
+	a := addr.As16()
+	return append(b, a[:]...)
+}
+
+// flowDirection returns the flowDirection (61) value, 0 for ingress and 1 for egress
+func flowDirection(d types.Direction) uint8 {
+	switch d {
+	case types.Ingress:
+		return 0
+	case types.Egress:
+		return 1
+	default:
+		return 0xff
+	}
+}
+
+// firewallEvent returns the firewallEvent (233) value of the event type
+func firewallEvent(t types.Type) uint8 {
+	switch t {
+	case types.TypeStart:
+		return 1 // flow created
+	case types.TypeEnd:
+		return 2 // flow deleted
+	case types.TypeDrop:
+		return 3 // flow denied
+	default:
+		return 0
+	}
+}
+
+// counterValue maps the rx/tx counters of the peer to the source -> destination (forward) and
+// the destination -> source (reverse) direction of the flow
+func counterValue(kind valueKind, event *types.Event) uint64 {
+	forwardOctets, forwardPackets := event.TxBytes, event.TxPackets
+	reverseOctets, reversePackets := event.RxBytes, event.RxPackets
+	if event.Direction == types.Ingress {
+		forwardOctets, reverseOctets = reverseOctets, forwardOctets
+		forwardPackets, reversePackets = reversePackets, forwardPackets
+	}
+
+	switch kind {
+	case valueOctets:
+		return forwardOctets
+	case valuePackets:
+		return forwardPackets
+	case valueReverseOctets:
+		return reverseOctets
+	default:
+		return reversePackets
+	}
+}
@@ -0,0 +1,235 @@
+// Package ipfix exports the flow events to an IPFIX (RFC 7011) or NetFlow v9 (RFC 3954) collector over UDP
+package ipfix
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/client/internal/netflow/types"
+)
+
+// Version is the version of the export protocol, as sent in the message header
+type Version uint16
+
+const (
+	VersionNetFlowV9 Version = 9
+	VersionIPFIX     Version = 10
+)
+
+func (v Version) String() string {
+	switch v {
+	case VersionNetFlowV9:
+		return "netflow9"
+	case VersionIPFIX:
+		return "ipfix"
+	default:
+		return fmt.Sprintf("version %d", uint16(v))
+	}
+}
+
+const (
+	// DefaultTemplateRefresh is the default interval the templates are sent again.
+	// Collectors lose them on restarts and UDP gives no feedback about it.
+	DefaultTemplateRefresh = time.Minute
+
+	flushInterval = time.Second
+	// maxPending is the number of queued records that triggers an export before the flush interval
+	maxPending = 256
+	queueSize  = 1000
+)
+
+// Options configures the exporter
+type Options struct {
+	// Collector is the UDP address of the collector, host:port
+	Collector string
+	// Version is the export protocol, IPFIX if not set
+	Version Version
+	// ObservationDomainID identifies the exporter at the collector, it is the source id for NetFlow v9
+	ObservationDomainID uint32
+	// TemplateRefresh is the interval the templates are sent again, DefaultTemplateRefresh if not set
+	TemplateRefresh time.Duration
+}
+
+// Exporter exports the flow events it stores to a collector and passes them on to the next store,
+// which keeps them for the flow receiver.
+type Exporter struct {
+	next    types.Store
+	conn    net.Conn
+	encoder *encoder
+	refresh time.Duration
+
+	records    chan record
+	dropped    atomic.Uint64
+	forwarding atomic.Bool
+	counters   atomic.Bool
+
+	cancel    context.CancelFunc
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewExporter creates an exporter sending to the collector of opts, wrapping next
+func NewExporter(opts Options, next types.Store) (*Exporter, error) {
+	switch opts.Version {
+	case 0:
+		opts.Version = VersionIPFIX
+	case VersionIPFIX, VersionNetFlowV9:
+	default:
+		return nil, fmt.Errorf("unsupported export protocol %s", opts.Version)
+	}
+
+	if opts.TemplateRefresh <= 0 {
+		opts.TemplateRefresh = DefaultTemplateRefresh
+	}
+
+	conn, err := net.Dial("udp", opts.Collector)
+	if err != nil {
+		return nil, fmt.Errorf("dial collector %s: %w", opts.Collector, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	e := &Exporter{
+		next:    next,
+		conn:    conn,
+		encoder: newEncoder(opts.Version, opts.ObservationDomainID, time.Now()),
+		refresh: opts.TemplateRefresh,
+		records: make(chan record, queueSize),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	e.forwarding.Store(true)
+
+	go e.run(ctx)
+
+	return e, nil
+}
+
+// SetForwarding sets whether the events are passed on to the next store.
+// Disabling it drops the events pending in the next store.
+func (e *Exporter) SetForwarding(enabled bool) {
+	if e.forwarding.Swap(enabled) == enabled || enabled {
+		return
+	}
+
+	events := e.next.GetEvents()
+	ids := make([]uuid.UUID, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.ID)
+	}
+	e.next.DeleteEvents(ids)
+}
+
+// SetCounters sets whether the packet and byte counters are exported
+func (e *Exporter) SetCounters(enabled bool) {
+	e.counters.Store(enabled)
+}
+
+func (e *Exporter) StoreEvent(event *types.Event) {
+	select {
+	case e.records <- record{event: event, counters: e.counters.Load()}:
+	default:
+		e.dropped.Add(1)
+	}
+
+	if e.forwarding.Load() {
+		e.next.StoreEvent(event)
+	}
+}
+
+func (e *Exporter) GetEvents() []*types.Event {
+	return e.next.GetEvents()
+}
+
+func (e *Exporter) DeleteEvents(ids []uuid.UUID) {
+	e.next.DeleteEvents(ids)
+}
+
+// Close exports the queued events and closes the exporter and the next store
+func (e *Exporter) Close() {
+	e.closeOnce.Do(func() {
+		e.cancel()
+		<-e.done
+
+		if err := e.conn.Close(); err != nil {
+			log.Debugf("failed to close flow export connection: %v", err)
+		}
+
+		e.next.Close()
+	})
+}
+
+func (e *Exporter) run(ctx context.Context) {
+	defer close(e.done)
+
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	// send the templates right away, collectors drop data records of unknown templates
+	var lastTemplates time.Time
+	pending := make([]record, 0, maxPending)
+
+	flush := func() {
+		now := time.Now()
+		templates := now.Sub(lastTemplates) >= e.refresh
+		if len(pending) == 0 && !templates {
+			return
+		}
+		if templates {
+			lastTemplates = now
+		}
+
+		e.send(e.encoder.encode(pending, now, templates))
+		clear(pending)
+		pending = pending[:0]
+
+		if dropped := e.dropped.Swap(0); dropped > 0 {
+			log.Warnf("dropped %d flow events, the export queue is full", dropped)
+		}
+	}
+
+	flush()
+	for {
+		select {
+		case <-ctx.Done():
+			e.drain(&pending)
+			flush()
+			return
+		case r := <-e.records:
+			pending = append(pending, r)
+			if len(pending) >= maxPending {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// drain moves the queued records to pending
+func (e *Exporter) drain(pending *[]record) {
+	for {
+		select {
+		case r := <-e.records:
+			*pending = append(*pending, r)
+		default:
+			return
+		}
+	}
+}
+
+func (e *Exporter) send(messages [][]byte) {
+	for _, msg := range messages {
+		if _, err := e.conn.Write(msg); err != nil {
+			// the records are lost, UDP export has no retransmission
+			log.Warnf("failed to export flows to %s: %v", e.conn.RemoteAddr(), err)
+			return
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package ipfix
+
+import (
+	"encoding/binary"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/client/internal/netflow/store"
+	"github.com/netbirdio/netbird/client/internal/netflow/types"
+)
+
+// decodedSet is a set of a decoded message
+type decodedSet struct {
+	id   uint16
+	body []byte
+}
+
+// decodeMessage checks the header of the message and splits it into its sets
+func decodeMessage(t *testing.T, version Version, msg []byte) (header []byte, sets []decodedSet) {
+	t.Helper()
+
+	require.Equal(t, uint16(version), binary.BigEndian.Uint16(msg))
+	hl := ipfixHeaderLen
+	if version == VersionNetFlowV9 {
+		hl = v9HeaderLen
+	} else {
+		require.Equal(t, len(msg), int(binary.BigEndian.Uint16(msg[2:])), "message length")
+	}
+	require.LessOrEqual(t, len(msg), maxMessageSize)
+
+	header, rest := msg[:hl], msg[hl:]
+	for len(rest) > 0 {
+		require.GreaterOrEqual(t, len(rest), setHeaderLen)
+		length := int(binary.BigEndian.Uint16(rest[2:]))
+		require.GreaterOrEqual(t, length, setHeaderLen)
+		require.LessOrEqual(t, length, len(rest))
+		require.Zero(t, length%4, "sets are padded")
+
+		sets = append(sets, decodedSet{id: binary.BigEndian.Uint16(rest), body: rest[setHeaderLen:length]})
+		rest = rest[length:]
+	}
+	return header, sets
+}
+
+// decodeTemplates returns the record length of the templates in a template set
+func decodeTemplates(t *testing.T, body []byte) map[uint16]int {
+	t.Helper()
+
+	lengths := make(map[uint16]int)
+	for len(body) >= 4 {
+		id := binary.BigEndian.Uint16(body)
+		count := int(binary.BigEndian.Uint16(body[2:]))
+		if id == 0 && count == 0 {
+			// padding
+			break
+		}
+		body = body[4:]
+
+		var length int
+		for i := 0; i < count; i++ {
+			ie := binary.BigEndian.Uint16(body)
+			length += int(binary.BigEndian.Uint16(body[2:]))
+			body = body[4:]
+			if ie&0x8000 != 0 {
+				assert.Equal(t, uint32(reverseEnterprise), binary.BigEndian.Uint32(body))
+				body = body[4:]
+			}
+		}
+		lengths[id] = length
+	}
+	return lengths
+}
+
+func testEvent(src, dst string, direction types.Direction) *types.Event {
+	return &types.Event{
+		ID:        uuid.New(),
+		Timestamp: time.UnixMilli(1_700_000_000_123),
+		EventFields: types.EventFields{
+			FlowID:     uuid.New(),
+			Type:       types.TypeStart,
+			Direction:  direction,
+			Protocol:   types.TCP,
+			SourceIP:   netip.MustParseAddr(src),
+			DestIP:     netip.MustParseAddr(dst),
+			SourcePort: 40000,
+			DestPort:   443,
+			RxPackets:  1,
+			TxPackets:  2,
+			RxBytes:    100,
+			TxBytes:    200,
+		},
+	}
+}
+
+func TestEncoder_IPFIX(t *testing.T) {
+	enc := newEncoder(VersionIPFIX, 42, time.Now())
+	now := time.Unix(1_700_000_001, 0)
+
+	records := []record{
+		{event: testEvent("100.64.0.1", "100.64.0.2", types.Egress)},
+		{event: testEvent("fd00::1", "fd00::2", types.Ingress), counters: true},
+	}
+
+	messages := enc.encode(records, now, true)
+	require.Len(t, messages, 1)
+
+	header, sets := decodeMessage(t, VersionIPFIX, messages[0])
+	assert.Equal(t, uint32(now.Unix()), binary.BigEndian.Uint32(header[4:]))
+	assert.Equal(t, uint32(0), binary.BigEndian.Uint32(header[8:]), "sequence")
+	assert.Equal(t, uint32(42), binary.BigEndian.Uint32(header[12:]), "observation domain")
+
+	require.Len(t, sets, 3)
+	assert.Equal(t, uint16(ipfixTemplateSetID), sets[0].id)
+	lengths := decodeTemplates(t, sets[0].body)
+	require.Len(t, lengths, 4)
+
+	// IPv4 record: timestamps, addresses, icmp, ports, protocol, direction, firewall event
+	v4 := sets[1]
+	assert.Equal(t, templateIPv4, v4.id)
+	require.GreaterOrEqual(t, len(v4.body), lengths[templateIPv4])
+	assert.Equal(t, uint64(1_700_000_000_123), binary.BigEndian.Uint64(v4.body[0:]))
+	assert.Equal(t, []byte{100, 64, 0, 1}, v4.body[16:20])
+	assert.Equal(t, []byte{100, 64, 0, 2}, v4.body[20:24])
+	assert.Equal(t, uint16(40000), binary.BigEndian.Uint16(v4.body[26:]))
+	assert.Equal(t, uint16(443), binary.BigEndian.Uint16(v4.body[28:]))
+	assert.Equal(t, uint8(types.TCP), v4.body[30])
+	assert.Equal(t, uint8(1), v4.body[31], "egress")
+	assert.Equal(t, uint8(1), v4.body[32], "flow created")
+
+	// IPv6 record with counters, ingress flows are received by the peer in the forward direction
+	v6 := sets[2]
+	assert.Equal(t, templateIPv6Counters, v6.id)
+	require.GreaterOrEqual(t, len(v6.body), lengths[templateIPv6Counters])
+	assert.Equal(t, netip.MustParseAddr("fd00::1").AsSlice(), v6.body[16:32])
+	counters := v6.body[lengths[templateIPv6Counters]-32:]
+	assert.Equal(t, uint64(100), binary.BigEndian.Uint64(counters[0:]), "forward octets")
+	assert.Equal(t, uint64(1), binary.BigEndian.Uint64(counters[8:]), "forward packets")
+	assert.Equal(t, uint64(200), binary.BigEndian.Uint64(counters[16:]), "reverse octets")
+	assert.Equal(t, uint64(2), binary.BigEndian.Uint64(counters[24:]), "reverse packets")
+
+	// the sequence number counts the data records
+	messages = enc.encode(records[:1], now, false)
+	require.Len(t, messages, 1)
+	header, sets = decodeMessage(t, VersionIPFIX, messages[0])
+	assert.Equal(t, uint32(2), binary.BigEndian.Uint32(header[8:]))
+	require.Len(t, sets, 1)
+}
+
+func TestEncoder_NetFlowV9(t *testing.T) {
+	start := time.UnixMilli(1_700_000_000_000)
+	enc := newEncoder(VersionNetFlowV9, 7, start)
+
+	messages := enc.encode([]record{{event: testEvent("100.64.0.1", "100.64.0.2", types.Egress)}}, start.Add(time.Second), true)
+	require.Len(t, messages, 1)
+
+	header, sets := decodeMessage(t, VersionNetFlowV9, messages[0])
+	assert.Equal(t, uint16(5), binary.BigEndian.Uint16(header[2:]), "4 templates and 1 data record")
+	assert.Equal(t, uint32(1000), binary.BigEndian.Uint32(header[4:]), "sys uptime")
+	assert.Equal(t, uint32(0), binary.BigEndian.Uint32(header[12:]), "sequence")
+	assert.Equal(t, uint32(7), binary.BigEndian.Uint32(header[16:]), "source id")
+
+	require.Len(t, sets, 2)
+	assert.Equal(t, uint16(v9TemplateSetID), sets[0].id)
+	assert.Equal(t, templateIPv4, sets[1].id)
+	assert.Equal(t, uint32(123), binary.BigEndian.Uint32(sets[1].body[0:]), "first switched")
+
+	// the sequence number counts the messages
+	messages = enc.encode([]record{{event: testEvent("100.64.0.1", "100.64.0.2", types.Egress)}}, start, false)
+	header, _ = decodeMessage(t, VersionNetFlowV9, messages[0])
+	assert.Equal(t, uint32(1), binary.BigEndian.Uint32(header[12:]))
+}
+
+func TestEncoder_SplitsMessages(t *testing.T) {
+	enc := newEncoder(VersionIPFIX, 0, time.Now())
+
+	var records []record
+	for i := 0; i < 100; i++ {
+		records = append(records, record{event: testEvent("fd00::1", "fd00::2", types.Egress), counters: true})
+	}
+
+	messages := enc.encode(records, time.Now(), true)
+	require.Greater(t, len(messages), 1)
+
+	var total int
+	for _, msg := range messages {
+		_, sets := decodeMessage(t, VersionIPFIX, msg)
+		for _, set := range sets {
+			if set.id == templateIPv6Counters {
+				total += len(set.body) / enc.templates[templateIPv6Counters-templateIPv4].recordLength()
+			}
+		}
+	}
+	assert.Equal(t, len(records), total)
+}
+
+func TestExporter(t *testing.T) {
+	collector, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = collector.Close()
+	})
+
+	next := store.NewMemoryStore()
+	exporter, err := NewExporter(Options{Collector: collector.LocalAddr().String()}, next)
+	require.NoError(t, err)
+	t.Cleanup(exporter.Close)
+
+	read := func() []decodedSet {
+		t.Helper()
+		buf := make([]byte, 2048)
+		require.NoError(t, collector.SetReadDeadline(time.Now().Add(5*time.Second)))
+		n, err := collector.Read(buf)
+		require.NoError(t, err)
+		_, sets := decodeMessage(t, VersionIPFIX, buf[:n])
+		return sets
+	}
+
+	// templates are sent on start
+	sets := read()
+	require.Len(t, sets, 1)
+	assert.Equal(t, uint16(ipfixTemplateSetID), sets[0].id)
+
+	exporter.SetCounters(true)
+	event := testEvent("100.64.0.1", "100.64.0.2", types.Egress)
+	exporter.StoreEvent(event)
+
+	sets = read()
+	require.Len(t, sets, 1)
+	assert.Equal(t, templateIPv4Counters, sets[0].id)
+	assert.Len(t, exporter.GetEvents(), 1, "event is passed on to the next store")
+
+	exporter.SetForwarding(false)
+	assert.Empty(t, next.GetEvents(), "pending events are dropped")
+
+	exporter.StoreEvent(testEvent("100.64.0.1", "100.64.0.2", types.Ingress))
+	read()
+	assert.Empty(t, next.GetEvents())
+}
+
+func TestNewExporter_InvalidVersion(t *testing.T) {
+	_, err := NewExporter(Options{Collector: "127.0.0.1:4739", Version: 5}, store.NewMemoryStore())
+	assert.Error(t, err)
+}
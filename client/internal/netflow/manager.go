@@ -14,7 +14,9 @@ import (
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/netbirdio/netbird/client/internal/netflow/conntrack"
+	"github.com/netbirdio/netbird/client/internal/netflow/ipfix"
 	"github.com/netbirdio/netbird/client/internal/netflow/logger"
+	"github.com/netbirdio/netbird/client/internal/netflow/store"
 	nftypes "github.com/netbirdio/netbird/client/internal/netflow/types"
 	"github.com/netbirdio/netbird/client/internal/peer"
 	"github.com/netbirdio/netbird/flow/client"
@@ -31,10 +33,21 @@ type Manager struct {
 	receiverClient *client.GRPCClient
 	publicKey      []byte
 	cancel         context.CancelFunc
+	disk           *store.Disk
+	exporter       *ipfix.Exporter
 }
 
-// NewManager creates a new netflow manager
-func NewManager(iface nftypes.IFaceMapper, publicKey []byte, statusRecorder *peer.Status) *Manager {
+// LocalOptions configures the flow collection independent of the flow receiver configuration
+type LocalOptions struct {
+	// Disk enables the on-disk store for local queries
+	Disk *store.DiskOptions
+	// Export enables exporting the flows to an IPFIX or NetFlow v9 collector
+	Export *ipfix.Options
+}
+
+// NewManager creates a new netflow manager. If localOpts is set, flows are additionally
+// stored on disk or exported, independent of the flow receiver configuration.
+func NewManager(iface nftypes.IFaceMapper, publicKey []byte, statusRecorder *peer.Status, localOpts *LocalOptions) *Manager {
 	var prefix netip.Prefix
 	if iface != nil {
 		prefix = iface.Address().Network
@@ -46,11 +59,80 @@ func NewManager(iface nftypes.IFaceMapper, publicKey []byte, statusRecorder *pee
 		ct = conntrack.New(flowLogger, iface)
 	}
 
-	return &Manager{
+	m := &Manager{
 		logger:    flowLogger,
 		conntrack: ct,
 		publicKey: publicKey,
 	}
+
+	if localOpts == nil {
+		return m
+	}
+
+	if opts := localOpts.Disk; opts != nil {
+		disk, err := store.NewDiskStore(*opts)
+		if err != nil {
+			log.Warnf("failed to open flow store, flows are not stored on disk: %v", err)
+		} else {
+			flowLogger.Store = disk
+			m.disk = disk
+			log.Infof("storing flows in %s", opts.Dir)
+		}
+	}
+
+	if opts := localOpts.Export; opts != nil {
+		exporter, err := ipfix.NewExporter(*opts, flowLogger.Store)
+		if err != nil {
+			log.Warnf("failed to create flow exporter, flows are not exported: %v", err)
+		} else {
+			flowLogger.Store = exporter
+			m.exporter = exporter
+			log.Infof("exporting flows to %s collector %s", opts.Version, opts.Collector)
+		}
+	}
+
+	if m.collectsLocally() {
+		// events are kept for the flow receiver once it is enabled
+		m.setForwarding(false)
+		m.startLocalCollection()
+	}
+
+	return m
+}
+
+// collectsLocally returns whether flows are collected independent of the flow receiver configuration
+func (m *Manager) collectsLocally() bool {
+	return m.disk != nil || m.exporter != nil
+}
+
+// setForwarding sets whether the locally collected events are kept for the flow receiver
+func (m *Manager) setForwarding(enabled bool) {
+	if m.disk != nil {
+		m.disk.SetForwarding(enabled)
+		return
+	}
+	if m.exporter != nil {
+		m.exporter.SetForwarding(enabled)
+	}
+}
+
+// startLocalCollection starts collecting flows for the disk store and the exporter
+func (m *Manager) startLocalCollection() {
+	m.logger.Enable()
+
+	if m.conntrack != nil {
+		if err := m.conntrack.Start(true); err != nil {
+			log.Warnf("failed to start conntrack for the flow store: %v", err)
+		}
+	}
+}
+
+func (m *Manager) stopCollection() {
+	if m.conntrack != nil {
+		m.conntrack.Stop()
+	}
+
+	m.logger.Close()
 }
 
 // Update applies new flow configuration settings
@@ -73,6 +155,12 @@ func (m *Manager) enableFlow(previous *nftypes.FlowConfig) error {
 		}
 	}
 
+	if m.collectsLocally() {
+		// collection is already running
+		m.setForwarding(true)
+		return nil
+	}
+
 	m.logger.Enable()
 
 	if m.conntrack != nil {
@@ -125,12 +213,13 @@ func (m *Manager) disableFlow() error {
 		m.cancel()
 	}
 
-	if m.conntrack != nil {
-		m.conntrack.Stop()
+	if m.collectsLocally() {
+		// keep collecting for the disk store and the exporter
+		m.setForwarding(false)
+	} else {
+		m.stopCollection()
 	}
 
-	m.logger.Close()
-
 	if m.receiverClient == nil {
 		return nil
 	}
@@ -166,6 +255,9 @@ func (m *Manager) Update(update *nftypes.FlowConfig) error {
 	}
 
 	m.logger.UpdateConfig(update.DNSCollection, update.ExitNodeCollection)
+	if m.exporter != nil {
+		m.exporter.SetCounters(update.Counters)
+	}
 
 	changed := previous != nil && update.Enabled != previous.Enabled
 	if update.Enabled {
@@ -187,6 +279,9 @@ func (m *Manager) Close() {
 	if err := m.disableFlow(); err != nil {
 		log.Warnf("failed to disable flow manager: %v", err)
 	}
+	if m.collectsLocally() {
+		m.stopCollection()
+	}
 	m.mux.Unlock()
 
 	m.shutdownWg.Wait()
@@ -197,6 +292,14 @@ func (m *Manager) GetLogger() nftypes.FlowLogger {
 	return m.logger
 }
 
+// QueryFlows returns the flows from the disk store matching the filter, newest first
+func (m *Manager) QueryFlows(filter store.Filter) ([]*nftypes.Event, error) {
+	if m.disk == nil {
+		return nil, ErrDiskStoreDisabled
+	}
+	return m.disk.Query(filter)
+}
+
 func (m *Manager) startSender(ctx context.Context) {
 	ticker := time.NewTicker(m.flowConfig.Interval)
 	defer ticker.Stop()
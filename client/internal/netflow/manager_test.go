@@ -1,6 +1,8 @@
 package netflow
 
 import (
+	"encoding/binary"
+	"net"
 	"net/netip"
 	"testing"
 	"time"
@@ -9,6 +11,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/netbirdio/netbird/client/iface/wgaddr"
+	"github.com/netbirdio/netbird/client/internal/netflow/ipfix"
 	"github.com/netbirdio/netbird/client/internal/netflow/types"
 	"github.com/netbirdio/netbird/client/internal/peer"
 )
@@ -41,7 +44,7 @@ func TestManager_Update(t *testing.T) {
 	publicKey := []byte("test-public-key")
 	statusRecorder := peer.NewRecorder("")
 
-	manager := NewManager(mockIFace, publicKey, statusRecorder)
+	manager := NewManager(mockIFace, publicKey, statusRecorder, nil)
 
 	tests := []struct {
 		name   string
@@ -105,7 +108,7 @@ func TestManager_Update_TokenPreservation(t *testing.T) {
 	}
 
 	publicKey := []byte("test-public-key")
-	manager := NewManager(mockIFace, publicKey, nil)
+	manager := NewManager(mockIFace, publicKey, nil, nil)
 
 	// First update with tokens
 	initialConfig := &types.FlowConfig{
@@ -192,3 +195,53 @@ func TestManager_NeedsNewClient(t *testing.T) {
 		})
 	}
 }
+
+func TestManager_Export(t *testing.T) {
+	collector, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = collector.Close()
+	})
+
+	mockIFace := &mockIFaceMapper{
+		address: wgaddr.Address{
+			Network: netip.MustParsePrefix("100.64.0.1/16"),
+		},
+		isUserspaceBind: true,
+	}
+
+	manager := NewManager(mockIFace, []byte("test-public-key"), peer.NewRecorder(""), &LocalOptions{
+		Export: &ipfix.Options{Collector: collector.LocalAddr().String()},
+	})
+	t.Cleanup(manager.Close)
+
+	// the logger is enabled asynchronously
+	flowLogger := manager.GetLogger()
+	require.Eventually(t, func() bool {
+		flowLogger.StoreEvent(types.EventFields{
+			Type:      types.TypeStart,
+			Direction: types.Egress,
+			Protocol:  types.TCP,
+			SourceIP:  netip.MustParseAddr("100.64.0.1"),
+			DestIP:    netip.MustParseAddr("100.64.0.2"),
+			DestPort:  443,
+		})
+
+		buf := make([]byte, 2048)
+		if err := collector.SetReadDeadline(time.Now().Add(1500 * time.Millisecond)); err != nil {
+			return false
+		}
+		for {
+			n, err := collector.Read(buf)
+			if err != nil {
+				return false
+			}
+			// skip the template only messages
+			if n > 20 && binary.BigEndian.Uint16(buf[16:]) >= 256 {
+				return true
+			}
+		}
+	}, 10*time.Second, 100*time.Millisecond)
+
+	assert.Empty(t, flowLogger.GetEvents(), "events are not kept without the flow receiver")
+}
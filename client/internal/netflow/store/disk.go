@@ -0,0 +1,487 @@
+package store
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/client/internal/netflow/types"
+)
+
+const (
+	DefaultMaxSize     = 100 << 20
+	DefaultMaxAge      = 7 * 24 * time.Hour
+	DefaultSegmentSize = 4 << 20
+
+	segmentPrefix = "flows-"
+	segmentSuffix = ".jsonl"
+
+	// segmentDuration limits how long a segment is written to, so age based retention can remove data in steps
+	segmentDuration = time.Hour
+	flushInterval   = time.Second
+	defaultLimit    = 100
+)
+
+// DiskOptions configures the on-disk flow store
+type DiskOptions struct {
+	// Dir is the directory holding the segment files
+	Dir string
+	// MaxSize is the maximum total size of all segments in bytes
+	MaxSize int64
+	// MaxAge is the maximum age of the stored events
+	MaxAge time.Duration
+	// SegmentSize is the size in bytes after which a new segment is started
+	SegmentSize int64
+}
+
+// Filter selects the events returned by Disk.Query. Zero values match everything.
+type Filter struct {
+	Since     time.Time
+	IP        netip.Addr
+	Port      uint16
+	Protocol  types.Protocol
+	Direction types.Direction
+	// Limit is the maximum number of returned events, defaults to 100
+	Limit int
+}
+
+func (f Filter) match(e *types.Event) bool {
+	if !f.Since.IsZero() && e.Timestamp.Before(f.Since) {
+		return false
+	}
+	if f.IP.IsValid() && e.SourceIP != f.IP && e.DestIP != f.IP {
+		return false
+	}
+	if f.Port != 0 && e.SourcePort != f.Port && e.DestPort != f.Port {
+		return false
+	}
+	if f.Protocol != types.ProtocolUnknown && e.Protocol != f.Protocol {
+		return false
+	}
+	if f.Direction != types.DirectionUnknown && e.Direction != f.Direction {
+		return false
+	}
+	return true
+}
+
+type segment struct {
+	path  string
+	start time.Time
+	// end is the time of the last write, zero for the active segment
+	end  time.Time
+	size int64
+}
+
+// record is the on-disk representation of an event
+type record struct {
+	ID               uuid.UUID       `json:"id"`
+	Timestamp        time.Time       `json:"ts"`
+	FlowID           uuid.UUID       `json:"flow_id"`
+	Type             types.Type      `json:"type"`
+	RuleID           []byte          `json:"rule_id,omitempty"`
+	Direction        types.Direction `json:"dir"`
+	Protocol         types.Protocol  `json:"proto"`
+	SourceIP         netip.Addr      `json:"src_ip"`
+	DestIP           netip.Addr      `json:"dst_ip"`
+	SourceResourceID []byte          `json:"src_res,omitempty"`
+	DestResourceID   []byte          `json:"dst_res,omitempty"`
+	SourcePort       uint16          `json:"src_port,omitempty"`
+	DestPort         uint16          `json:"dst_port,omitempty"`
+	ICMPType         uint8           `json:"icmp_type,omitempty"`
+	ICMPCode         uint8           `json:"icmp_code,omitempty"`
+	RxPackets        uint64          `json:"rx_packets,omitempty"`
+	TxPackets        uint64          `json:"tx_packets,omitempty"`
+	RxBytes          uint64          `json:"rx_bytes,omitempty"`
+	TxBytes          uint64          `json:"tx_bytes,omitempty"`
+}
+
+func toRecord(e *types.Event) *record {
+	return &record{
+		ID:               e.ID,
+		Timestamp:        e.Timestamp,
+		FlowID:           e.FlowID,
+		Type:             e.Type,
+		RuleID:           e.RuleID,
+		Direction:        e.Direction,
+		Protocol:         e.Protocol,
+		SourceIP:         e.SourceIP,
+		DestIP:           e.DestIP,
+		SourceResourceID: e.SourceResourceID,
+		DestResourceID:   e.DestResourceID,
+		SourcePort:       e.SourcePort,
+		DestPort:         e.DestPort,
+		ICMPType:         e.ICMPType,
+		ICMPCode:         e.ICMPCode,
+		RxPackets:        e.RxPackets,
+		TxPackets:        e.TxPackets,
+		RxBytes:          e.RxBytes,
+		TxBytes:          e.TxBytes,
+	}
+}
+
+func (r *record) toEvent() *types.Event {
+	return &types.Event{
+		ID:        r.ID,
+		Timestamp: r.Timestamp,
+		EventFields: types.EventFields{
+			FlowID:           r.FlowID,
+			Type:             r.Type,
+			RuleID:           r.RuleID,
+			Direction:        r.Direction,
+			Protocol:         r.Protocol,
+			SourceIP:         r.SourceIP,
+			DestIP:           r.DestIP,
+			SourceResourceID: r.SourceResourceID,
+			DestResourceID:   r.DestResourceID,
+			SourcePort:       r.SourcePort,
+			DestPort:         r.DestPort,
+			ICMPType:         r.ICMPType,
+			ICMPCode:         r.ICMPCode,
+			RxPackets:        r.RxPackets,
+			TxPackets:        r.TxPackets,
+			RxBytes:          r.RxBytes,
+			TxBytes:          r.TxBytes,
+		},
+	}
+}
+
+// Disk persists flow events in size and age bounded segment files, so they can be queried locally.
+// Events are additionally kept in memory until they are acknowledged by the flow receiver while forwarding is enabled.
+type Disk struct {
+	mux       sync.Mutex
+	opts      DiskOptions
+	pending   *Memory
+	forward   atomic.Bool
+	segments  []*segment
+	file      *os.File
+	writer    *bufio.Writer
+	lastFlush time.Time
+	closed    bool
+}
+
+// NewDiskStore opens the flow store in the configured directory and applies the retention to existing segments
+func NewDiskStore(opts DiskOptions) (*Disk, error) {
+	if opts.Dir == "" {
+		return nil, fmt.Errorf("flow store directory not set")
+	}
+	if opts.MaxSize <= 0 {
+		opts.MaxSize = DefaultMaxSize
+	}
+	if opts.MaxAge <= 0 {
+		opts.MaxAge = DefaultMaxAge
+	}
+	if opts.SegmentSize <= 0 {
+		opts.SegmentSize = DefaultSegmentSize
+	}
+	if opts.SegmentSize > opts.MaxSize {
+		opts.SegmentSize = opts.MaxSize
+	}
+
+	if err := os.MkdirAll(opts.Dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create flow store directory: %w", err)
+	}
+
+	segments, err := listSegments(opts.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Disk{
+		opts:     opts,
+		pending:  NewMemoryStore(),
+		segments: segments,
+	}
+	d.applyRetention(time.Now())
+
+	return d, nil
+}
+
+func listSegments(dir string) ([]*segment, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read flow store directory: %w", err)
+	}
+
+	var segments []*segment
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, segmentPrefix) || !strings.HasSuffix(name, segmentSuffix) {
+			continue
+		}
+
+		nanos, err := strconv.ParseInt(strings.TrimSuffix(strings.TrimPrefix(name, segmentPrefix), segmentSuffix), 10, 64)
+		if err != nil {
+			log.Debugf("ignoring unknown file %s in flow store", name)
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		segments = append(segments, &segment{
+			path:  filepath.Join(dir, name),
+			start: time.Unix(0, nanos),
+			end:   info.ModTime(),
+			size:  info.Size(),
+		})
+	}
+
+	sort.Slice(segments, func(i, j int) bool {
+		return segments[i].start.Before(segments[j].start)
+	})
+
+	return segments, nil
+}
+
+// SetForwarding enables keeping the events in memory until they are deleted by the flow receiver.
+// Disabling it drops all pending events.
+func (d *Disk) SetForwarding(enabled bool) {
+	if !d.forward.Swap(enabled) || enabled {
+		return
+	}
+	d.pending.Close()
+}
+
+func (d *Disk) StoreEvent(event *types.Event) {
+	if d.forward.Load() {
+		d.pending.StoreEvent(event)
+	}
+
+	data, err := json.Marshal(toRecord(event))
+	if err != nil {
+		log.Warnf("failed to encode flow event: %v", err)
+		return
+	}
+	data = append(data, '\n')
+
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	if d.closed {
+		return
+	}
+
+	if err := d.write(data, event.Timestamp); err != nil {
+		log.Warnf("failed to write flow event to disk: %v", err)
+	}
+}
+
+func (d *Disk) write(data []byte, now time.Time) error {
+	if d.needsRotation(int64(len(data)), now) {
+		if err := d.rotate(now); err != nil {
+			return err
+		}
+	}
+
+	n, err := d.writer.Write(data)
+	d.current().size += int64(n)
+	if err != nil {
+		return err
+	}
+
+	if time.Since(d.lastFlush) >= flushInterval {
+		return d.flush()
+	}
+	return nil
+}
+
+func (d *Disk) current() *segment {
+	return d.segments[len(d.segments)-1]
+}
+
+func (d *Disk) needsRotation(size int64, now time.Time) bool {
+	if d.file == nil {
+		return true
+	}
+
+	cur := d.current()
+	return cur.size+size > d.opts.SegmentSize || now.Sub(cur.start) >= segmentDuration
+}
+
+func (d *Disk) rotate(now time.Time) error {
+	if err := d.closeFile(); err != nil {
+		log.Warnf("failed to close flow segment: %v", err)
+	}
+
+	// segment names must be unique, even if the clock went backwards
+	start := now
+	if len(d.segments) > 0 {
+		d.current().end = now
+		if !start.After(d.current().start) {
+			start = d.current().start.Add(time.Nanosecond)
+		}
+	}
+
+	path := filepath.Join(d.opts.Dir, fmt.Sprintf("%s%d%s", segmentPrefix, start.UnixNano(), segmentSuffix))
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("create flow segment: %w", err)
+	}
+
+	d.file = f
+	d.writer = bufio.NewWriter(f)
+	d.lastFlush = time.Now()
+	d.segments = append(d.segments, &segment{path: path, start: start})
+
+	d.applyRetention(now)
+	return nil
+}
+
+// applyRetention removes the oldest segments exceeding the size or age limit. The active segment is never removed.
+func (d *Disk) applyRetention(now time.Time) {
+	var total int64
+	for _, s := range d.segments {
+		total += s.size
+	}
+
+	removable := len(d.segments)
+	if d.file != nil {
+		removable--
+	}
+
+	var removed int
+	for removed < removable {
+		s := d.segments[removed]
+		if total <= d.opts.MaxSize && now.Sub(s.end) <= d.opts.MaxAge {
+			break
+		}
+
+		if err := os.Remove(s.path); err != nil && !os.IsNotExist(err) {
+			log.Warnf("failed to remove flow segment %s: %v", s.path, err)
+			break
+		}
+		total -= s.size
+		removed++
+	}
+
+	d.segments = d.segments[removed:]
+}
+
+func (d *Disk) flush() error {
+	if d.writer == nil {
+		return nil
+	}
+	d.lastFlush = time.Now()
+	return d.writer.Flush()
+}
+
+func (d *Disk) closeFile() error {
+	if d.file == nil {
+		return nil
+	}
+
+	flushErr := d.flush()
+	closeErr := d.file.Close()
+	d.file = nil
+	d.writer = nil
+
+	if flushErr != nil {
+		return flushErr
+	}
+	return closeErr
+}
+
+// Query returns the stored events matching the filter, newest first
+func (d *Disk) Query(filter Filter) ([]*types.Event, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultLimit
+	}
+
+	d.mux.Lock()
+	if err := d.flush(); err != nil {
+		log.Warnf("failed to flush flow segment: %v", err)
+	}
+	segments := make([]*segment, len(d.segments))
+	copy(segments, d.segments)
+	d.mux.Unlock()
+
+	var events []*types.Event
+	for i := len(segments) - 1; i >= 0 && len(events) < filter.Limit; i-- {
+		// skip segments where all events are older than requested
+		if i+1 < len(segments) && !filter.Since.IsZero() && segments[i+1].start.Before(filter.Since) {
+			break
+		}
+
+		matched, err := readSegment(segments[i].path, filter)
+		if err != nil {
+			return nil, err
+		}
+
+		// events of a segment are in write order, collect them newest first
+		for j := len(matched) - 1; j >= 0 && len(events) < filter.Limit; j-- {
+			events = append(events, matched[j])
+		}
+	}
+
+	return events, nil
+}
+
+func readSegment(path string, filter Filter) ([]*types.Event, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		// removed by the retention in the meantime
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open flow segment: %w", err)
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			log.Debugf("failed to close flow segment: %v", err)
+		}
+	}()
+
+	var events []*types.Event
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r record
+		// lines can be incomplete after a crash
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			continue
+		}
+
+		event := r.toEvent()
+		if filter.match(event) {
+			events = append(events, event)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read flow segment %s: %w", path, err)
+	}
+
+	return events, nil
+}
+
+func (d *Disk) GetEvents() []*types.Event {
+	return d.pending.GetEvents()
+}
+
+func (d *Disk) DeleteEvents(ids []uuid.UUID) {
+	d.pending.DeleteEvents(ids)
+}
+
+func (d *Disk) Close() {
+	d.pending.Close()
+
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	d.closed = true
+	if err := d.closeFile(); err != nil {
+		log.Warnf("failed to close flow segment: %v", err)
+	}
+}
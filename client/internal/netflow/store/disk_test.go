@@ -0,0 +1,181 @@
+package store
+
+import (
+	"net/netip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/client/internal/netflow/types"
+)
+
+func newEvent(ts time.Time, proto types.Protocol, dir types.Direction, src, dst string, dstPort uint16) *types.Event {
+	return &types.Event{
+		ID:        uuid.New(),
+		Timestamp: ts,
+		EventFields: types.EventFields{
+			FlowID:     uuid.New(),
+			Type:       types.TypeStart,
+			Direction:  dir,
+			Protocol:   proto,
+			SourceIP:   netip.MustParseAddr(src),
+			DestIP:     netip.MustParseAddr(dst),
+			SourcePort: 50000,
+			DestPort:   dstPort,
+			RxBytes:    100,
+		},
+	}
+}
+
+func TestDisk_Query(t *testing.T) {
+	d, err := NewDiskStore(DiskOptions{Dir: t.TempDir()})
+	require.NoError(t, err)
+	t.Cleanup(d.Close)
+
+	now := time.Now()
+	tcp := newEvent(now.Add(-2*time.Hour), types.TCP, types.Egress, "100.64.0.1", "100.64.0.2", 443)
+	udp := newEvent(now.Add(-time.Minute), types.UDP, types.Ingress, "100.64.0.3", "100.64.0.1", 53)
+	icmp := newEvent(now, types.ICMP, types.Egress, "100.64.0.1", "10.0.0.1", 0)
+	for _, e := range []*types.Event{tcp, udp, icmp} {
+		d.StoreEvent(e)
+	}
+
+	testCases := []struct {
+		name     string
+		filter   Filter
+		expected []*types.Event
+	}{
+		{"all newest first", Filter{}, []*types.Event{icmp, udp, tcp}},
+		{"limit", Filter{Limit: 1}, []*types.Event{icmp}},
+		{"ip", Filter{IP: netip.MustParseAddr("100.64.0.2")}, []*types.Event{tcp}},
+		{"port", Filter{Port: 53}, []*types.Event{udp}},
+		{"protocol", Filter{Protocol: types.ICMP}, []*types.Event{icmp}},
+		{"direction", Filter{Direction: types.Egress}, []*types.Event{icmp, tcp}},
+		{"since", Filter{Since: now.Add(-time.Hour)}, []*types.Event{icmp, udp}},
+		{"no match", Filter{Port: 22}, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			events, err := d.Query(tc.filter)
+			require.NoError(t, err)
+			require.Len(t, events, len(tc.expected))
+			for i, e := range tc.expected {
+				assert.Equal(t, e.ID, events[i].ID)
+				assert.Equal(t, e.EventFields, events[i].EventFields)
+				assert.True(t, e.Timestamp.Equal(events[i].Timestamp))
+			}
+		})
+	}
+}
+
+func TestDisk_PersistsAcrossRestarts(t *testing.T) {
+	dir := t.TempDir()
+
+	d, err := NewDiskStore(DiskOptions{Dir: dir})
+	require.NoError(t, err)
+	event := newEvent(time.Now(), types.TCP, types.Egress, "100.64.0.1", "100.64.0.2", 22)
+	d.StoreEvent(event)
+	d.Close()
+
+	d, err = NewDiskStore(DiskOptions{Dir: dir})
+	require.NoError(t, err)
+	t.Cleanup(d.Close)
+
+	d.StoreEvent(newEvent(time.Now(), types.UDP, types.Egress, "100.64.0.1", "100.64.0.2", 53))
+
+	events, err := d.Query(Filter{})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, event.ID, events[1].ID)
+}
+
+func TestDisk_Forwarding(t *testing.T) {
+	d, err := NewDiskStore(DiskOptions{Dir: t.TempDir()})
+	require.NoError(t, err)
+	t.Cleanup(d.Close)
+
+	d.StoreEvent(newEvent(time.Now(), types.TCP, types.Egress, "100.64.0.1", "100.64.0.2", 22))
+	assert.Empty(t, d.GetEvents(), "events must not be queued without forwarding")
+
+	d.SetForwarding(true)
+	event := newEvent(time.Now(), types.TCP, types.Egress, "100.64.0.1", "100.64.0.2", 22)
+	d.StoreEvent(event)
+	require.Len(t, d.GetEvents(), 1)
+
+	d.DeleteEvents([]uuid.UUID{event.ID})
+	assert.Empty(t, d.GetEvents())
+
+	d.StoreEvent(newEvent(time.Now(), types.TCP, types.Egress, "100.64.0.1", "100.64.0.2", 22))
+	d.SetForwarding(false)
+	assert.Empty(t, d.GetEvents(), "disabling forwarding must drop pending events")
+
+	events, err := d.Query(Filter{})
+	require.NoError(t, err)
+	assert.Len(t, events, 3, "acknowledged events must stay on disk")
+}
+
+func TestDisk_Retention(t *testing.T) {
+	dir := t.TempDir()
+
+	// stale segment from a previous run
+	old := filepath.Join(dir, "flows-1.jsonl")
+	require.NoError(t, os.WriteFile(old, []byte("{}\n"), 0o600))
+	stale := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, os.Chtimes(old, stale, stale))
+
+	d, err := NewDiskStore(DiskOptions{Dir: dir, MaxSize: 2048, SegmentSize: 512, MaxAge: time.Hour})
+	require.NoError(t, err)
+	t.Cleanup(d.Close)
+	assert.NoFileExists(t, old, "segments older than the max age must be removed")
+
+	now := time.Now()
+	for i := 0; i < 100; i++ {
+		d.StoreEvent(newEvent(now.Add(time.Duration(i)*time.Millisecond), types.TCP, types.Egress, "100.64.0.1", "100.64.0.2", 22))
+	}
+
+	var total int64
+	for _, s := range d.segments {
+		total += s.size
+	}
+	assert.LessOrEqual(t, total, int64(2048+512), "store must stay within the size limit")
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, len(d.segments))
+
+	events, err := d.Query(Filter{Limit: 1000})
+	require.NoError(t, err)
+	assert.NotEmpty(t, events)
+	assert.Less(t, len(events), 100, "oldest events must be removed")
+	assert.Equal(t, uint16(22), events[0].DestPort)
+}
+
+func TestDisk_SkipsCorruptLines(t *testing.T) {
+	dir := t.TempDir()
+
+	d, err := NewDiskStore(DiskOptions{Dir: dir})
+	require.NoError(t, err)
+	d.StoreEvent(newEvent(time.Now(), types.TCP, types.Egress, "100.64.0.1", "100.64.0.2", 22))
+	d.Close()
+
+	// simulate a write interrupted by a crash
+	f, err := os.OpenFile(d.segments[0].path, os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"id":"`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	d, err = NewDiskStore(DiskOptions{Dir: dir})
+	require.NoError(t, err)
+	t.Cleanup(d.Close)
+
+	events, err := d.Query(Filter{})
+	require.NoError(t, err)
+	assert.Len(t, events, 1)
+}
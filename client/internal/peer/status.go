@@ -148,6 +148,16 @@ type NSGroupState struct {
 	Error   error
 }
 
+// PostureCheckResult is the outcome of a posture check evaluated by the management server on this peer
+type PostureCheckResult struct {
+	PostureChecksID   string
+	PostureChecksName string
+	Check             string
+	Passed            bool
+	Reason            string
+	EvaluatedAt       time.Time
+}
+
 // FullStatus contains the full state held by the Status instance
 type FullStatus struct {
 	Peers                 []State
@@ -159,6 +169,7 @@ type FullStatus struct {
 	NSGroupStates         []NSGroupState
 	NumOfForwardingRules  int
 	LazyConnectionEnabled bool
+	PostureCheckResults   []PostureCheckResult
 	Events                []*proto.SystemEvent
 }
 
@@ -203,6 +214,7 @@ type Status struct {
 	nsGroupStates         []NSGroupState
 	resolvedDomainsStates map[domain.Domain]ResolvedDomainInfo
 	lazyConnectionEnabled bool
+	postureCheckResults   []PostureCheckResult
 
 	// To reduce the number of notification invocation this bool will be true when need to call the notification
 	// Some Peer actions mostly used by in a batch when the network map has been synchronized. In these type of events
@@ -807,6 +819,13 @@ func (d *Status) UpdateDNSStates(dnsStates []NSGroupState) {
 	d.nsGroupStates = dnsStates
 }
 
+// UpdatePostureCheckResults replaces the posture check results received from the management server
+func (d *Status) UpdatePostureCheckResults(results []PostureCheckResult) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	d.postureCheckResults = results
+}
+
 func (d *Status) UpdateResolvedDomainsStates(originalDomain domain.Domain, resolvedDomain domain.Domain, prefixes []netip.Prefix, resourceId route.ResID) {
 	d.mux.Lock()
 	defer d.mux.Unlock()
@@ -853,6 +872,13 @@ func (d *Status) GetLazyConnection() bool {
 	return d.lazyConnectionEnabled
 }
 
+// GetPostureCheckResults returns the latest posture check results received from the management server
+func (d *Status) GetPostureCheckResults() []PostureCheckResult {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	return slices.Clone(d.postureCheckResults)
+}
+
 func (d *Status) GetManagementState() ManagementState {
 	d.mux.Lock()
 	defer d.mux.Unlock()
@@ -971,6 +997,7 @@ func (d *Status) GetFullStatus() FullStatus {
 		NSGroupStates:         d.GetDNSStates(),
 		NumOfForwardingRules:  len(d.ForwardingRules()),
 		LazyConnectionEnabled: d.GetLazyConnection(),
+		PostureCheckResults:   d.GetPostureCheckResults(),
 	}
 
 	d.mux.Lock()
@@ -1274,6 +1301,17 @@ func (fs FullStatus) ToProto() *proto.FullStatus {
 		pbFullStatus.DnsServers = append(pbFullStatus.DnsServers, pbDnsState)
 	}
 
+	for _, result := range fs.PostureCheckResults {
+		pbFullStatus.PostureChecks = append(pbFullStatus.PostureChecks, &proto.PostureCheckResult{
+			PostureCheckId:   result.PostureChecksID,
+			PostureCheckName: result.PostureChecksName,
+			Check:            result.Check,
+			Passed:           result.Passed,
+			Reason:           result.Reason,
+			EvaluatedAt:      timestamppb.New(result.EvaluatedAt),
+		})
+	}
+
 	pbFullStatus.Events = fs.Events
 
 	return &pbFullStatus
@@ -0,0 +1,101 @@
+package server
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/client/firewall/uspfilter/capture"
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+type packetCapturer interface {
+	SetPacketTap(tap capture.Tap) error
+}
+
+// captureStreamWriter sends the written pcapng data to the client
+type captureStreamWriter struct {
+	stream proto.DaemonService_CapturePacketsServer
+}
+
+func (w *captureStreamWriter) Write(p []byte) (int, error) {
+	// the message is serialized by Send, the buffer can be reused by the caller afterwards
+	if err := w.stream.Send(&proto.CapturePacketsResponse{Data: p}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// CapturePackets captures the packets of the userspace filter and streams them to the client in the pcapng format
+func (s *Server) CapturePackets(req *proto.CapturePacketsRequest, stream proto.DaemonService_CapturePacketsServer) error {
+	capturer, ifaceName, err := s.getPacketCapturer()
+	if err != nil {
+		return err
+	}
+
+	session, err := capture.NewSession(&captureStreamWriter{stream: stream}, capture.Options{
+		InterfaceName: ifaceName,
+		Filter:        req.GetFilter(),
+		Duration:      req.GetDuration().AsDuration(),
+		MaxPackets:    uint64(req.GetMaxPackets()),
+		PostNAT:       req.GetPostNat(),
+		SnapLen:       req.GetSnapLen(),
+	})
+	if err != nil {
+		return fmt.Errorf("start capture: %w", err)
+	}
+
+	if err := capturer.SetPacketTap(session); err != nil {
+		return err
+	}
+	log.Infof("started packet capture with filter %q", req.GetFilter())
+
+	runErr := session.Run(stream.Context())
+
+	if err := capturer.SetPacketTap(nil); err != nil {
+		log.Warnf("failed to remove packet tap: %v", err)
+	}
+
+	captured, dropped := session.Stats()
+	log.Infof("stopped packet capture, %d packets captured, %d dropped", captured, dropped)
+
+	if runErr != nil {
+		return fmt.Errorf("capture packets: %w", runErr)
+	}
+
+	return stream.Send(&proto.CapturePacketsResponse{
+		CapturedPackets: captured,
+		DroppedPackets:  dropped,
+	})
+}
+
+func (s *Server) getPacketCapturer() (packetCapturer, string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.connectClient == nil {
+		return nil, "", fmt.Errorf("connect client not initialized")
+	}
+
+	engine := s.connectClient.Engine()
+	if engine == nil {
+		return nil, "", fmt.Errorf("engine not initialized")
+	}
+
+	fwManager := engine.GetFirewallManager()
+	if fwManager == nil {
+		return nil, "", fmt.Errorf("firewall manager not initialized")
+	}
+
+	capturer, ok := fwManager.(packetCapturer)
+	if !ok {
+		return nil, "", fmt.Errorf("packet capture requires the userspace firewall, use tcpdump on the interface instead")
+	}
+
+	var ifaceName string
+	if s.config != nil {
+		ifaceName = s.config.WgIface
+	}
+
+	return capturer, ifaceName, nil
+}
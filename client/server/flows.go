@@ -0,0 +1,217 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/netbirdio/netbird/client/internal/netflow/store"
+	nftypes "github.com/netbirdio/netbird/client/internal/netflow/types"
+	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+const maxFlowsLimit = 10000
+
+type flowQuerier interface {
+	QueryFlows(filter store.Filter) ([]*nftypes.Event, error)
+}
+
+// ListFlows returns the flows from the local flow store, newest first
+func (s *Server) ListFlows(_ context.Context, req *proto.ListFlowsRequest) (*proto.ListFlowsResponse, error) {
+	querier, err := s.getFlowQuerier()
+	if err != nil {
+		return nil, err
+	}
+
+	fullStatus := s.statusRecorder.GetFullStatus()
+
+	filter, err := parseFlowFilter(req, fullStatus)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := querier.QueryFlows(filter)
+	if err != nil {
+		return nil, fmt.Errorf("query flows: %w", err)
+	}
+
+	names := peerNames(fullStatus)
+	flows := make([]*proto.FlowEntry, 0, len(events))
+	for _, event := range events {
+		flows = append(flows, toFlowEntry(event, names))
+	}
+
+	return &proto.ListFlowsResponse{Flows: flows}, nil
+}
+
+func (s *Server) getFlowQuerier() (flowQuerier, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.connectClient == nil {
+		return nil, fmt.Errorf("connect client not initialized")
+	}
+
+	engine := s.connectClient.Engine()
+	if engine == nil {
+		return nil, fmt.Errorf("engine not initialized")
+	}
+
+	querier, ok := engine.GetFlowManager().(flowQuerier)
+	if !ok {
+		return nil, fmt.Errorf("flow manager not initialized")
+	}
+
+	return querier, nil
+}
+
+func parseFlowFilter(req *proto.ListFlowsRequest, fullStatus peer.FullStatus) (store.Filter, error) {
+	filter := store.Filter{
+		Limit: int(min(req.GetLimit(), maxFlowsLimit)),
+	}
+
+	if req.GetSince() != nil {
+		filter.Since = req.GetSince().AsTime()
+	}
+
+	if req.GetPort() > 65535 {
+		return filter, fmt.Errorf("invalid port: %d", req.GetPort())
+	}
+	filter.Port = uint16(req.GetPort())
+
+	if req.GetIp() != "" && req.GetPeer() != "" {
+		return filter, fmt.Errorf("peer and ip filters are mutually exclusive")
+	}
+
+	if req.GetIp() != "" {
+		ip, err := netip.ParseAddr(req.GetIp())
+		if err != nil {
+			return filter, fmt.Errorf("invalid IP address: %s", req.GetIp())
+		}
+		filter.IP = ip.Unmap()
+	}
+
+	if req.GetPeer() != "" {
+		ip, err := findPeerIP(req.GetPeer(), fullStatus)
+		if err != nil {
+			return filter, err
+		}
+		filter.IP = ip
+	}
+
+	protocol, err := parseFlowProtocol(req.GetProtocol())
+	if err != nil {
+		return filter, err
+	}
+	filter.Protocol = protocol
+
+	switch strings.ToLower(req.GetDirection()) {
+	case "":
+	case "ingress", "in", "inbound":
+		filter.Direction = nftypes.Ingress
+	case "egress", "out", "outbound":
+		filter.Direction = nftypes.Egress
+	default:
+		return filter, fmt.Errorf("invalid direction: %s", req.GetDirection())
+	}
+
+	return filter, nil
+}
+
+func parseFlowProtocol(protocol string) (nftypes.Protocol, error) {
+	switch strings.ToLower(protocol) {
+	case "":
+		return nftypes.ProtocolUnknown, nil
+	case "tcp":
+		return nftypes.TCP, nil
+	case "udp":
+		return nftypes.UDP, nil
+	case "icmp":
+		return nftypes.ICMP, nil
+	case "sctp":
+		return nftypes.SCTP, nil
+	}
+
+	num, err := strconv.ParseUint(protocol, 10, 8)
+	if err != nil || num == 0 {
+		return nftypes.ProtocolUnknown, fmt.Errorf("invalid protocol: %s", protocol)
+	}
+	return nftypes.Protocol(num), nil
+}
+
+// findPeerIP resolves a peer by FQDN, hostname, IP or public key to its overlay address
+func findPeerIP(name string, fullStatus peer.FullStatus) (netip.Addr, error) {
+	name = strings.TrimSuffix(strings.ToLower(name), ".")
+
+	for _, p := range fullStatus.Peers {
+		fqdn := strings.TrimSuffix(strings.ToLower(p.FQDN), ".")
+		hostname, _, _ := strings.Cut(fqdn, ".")
+		if name != fqdn && name != hostname && name != p.IP && name != strings.ToLower(p.PubKey) {
+			continue
+		}
+
+		ip, err := netip.ParseAddr(p.IP)
+		if err != nil {
+			return netip.Addr{}, fmt.Errorf("parse IP of peer %s: %w", p.FQDN, err)
+		}
+		return ip, nil
+	}
+
+	return netip.Addr{}, fmt.Errorf("peer %s not found", name)
+}
+
+func peerNames(fullStatus peer.FullStatus) map[netip.Addr]string {
+	names := make(map[netip.Addr]string, len(fullStatus.Peers)+1)
+	for _, p := range fullStatus.Peers {
+		if ip, err := netip.ParseAddr(p.IP); err == nil {
+			names[ip] = p.FQDN
+		}
+	}
+
+	if prefix, err := netip.ParsePrefix(fullStatus.LocalPeerState.IP); err == nil {
+		names[prefix.Addr()] = fullStatus.LocalPeerState.FQDN
+	}
+
+	return names
+}
+
+func flowTypeString(t nftypes.Type) string {
+	switch t {
+	case nftypes.TypeStart:
+		return "start"
+	case nftypes.TypeEnd:
+		return "end"
+	case nftypes.TypeDrop:
+		return "drop"
+	default:
+		return "unknown"
+	}
+}
+
+func toFlowEntry(event *nftypes.Event, names map[netip.Addr]string) *proto.FlowEntry {
+	return &proto.FlowEntry{
+		Id:         event.ID.String(),
+		FlowId:     event.FlowID.String(),
+		Timestamp:  timestamppb.New(event.Timestamp),
+		Type:       flowTypeString(event.Type),
+		Direction:  event.Direction.String(),
+		Protocol:   event.Protocol.String(),
+		SourceIp:   event.SourceIP.String(),
+		SourcePort: uint32(event.SourcePort),
+		DestIp:     event.DestIP.String(),
+		DestPort:   uint32(event.DestPort),
+		IcmpType:   uint32(event.ICMPType),
+		IcmpCode:   uint32(event.ICMPCode),
+		RxPackets:  event.RxPackets,
+		TxPackets:  event.TxPackets,
+		RxBytes:    event.RxBytes,
+		TxBytes:    event.TxBytes,
+		SourceName: names[event.SourceIP],
+		DestName:   names[event.DestIP],
+	}
+}
@@ -0,0 +1,63 @@
+package server
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	nftypes "github.com/netbirdio/netbird/client/internal/netflow/types"
+	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+func TestParseFlowFilter(t *testing.T) {
+	fullStatus := peer.FullStatus{
+		Peers: []peer.State{
+			{IP: "100.64.0.2", FQDN: "peer-a.netbird.cloud", PubKey: "KEY-A"},
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		req      *proto.ListFlowsRequest
+		expectIP netip.Addr
+		wantErr  bool
+	}{
+		{name: "empty", req: &proto.ListFlowsRequest{}},
+		{name: "peer by fqdn", req: &proto.ListFlowsRequest{Peer: "peer-a.netbird.cloud."}, expectIP: netip.MustParseAddr("100.64.0.2")},
+		{name: "peer by hostname", req: &proto.ListFlowsRequest{Peer: "Peer-A"}, expectIP: netip.MustParseAddr("100.64.0.2")},
+		{name: "peer by key", req: &proto.ListFlowsRequest{Peer: "KEY-A"}, expectIP: netip.MustParseAddr("100.64.0.2")},
+		{name: "unknown peer", req: &proto.ListFlowsRequest{Peer: "peer-b"}, wantErr: true},
+		{name: "ip", req: &proto.ListFlowsRequest{Ip: "10.0.0.1"}, expectIP: netip.MustParseAddr("10.0.0.1")},
+		{name: "invalid ip", req: &proto.ListFlowsRequest{Ip: "10.0.0"}, wantErr: true},
+		{name: "peer and ip", req: &proto.ListFlowsRequest{Peer: "peer-a", Ip: "10.0.0.1"}, wantErr: true},
+		{name: "invalid port", req: &proto.ListFlowsRequest{Port: 70000}, wantErr: true},
+		{name: "invalid protocol", req: &proto.ListFlowsRequest{Protocol: "foo"}, wantErr: true},
+		{name: "invalid direction", req: &proto.ListFlowsRequest{Direction: "sideways"}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filter, err := parseFlowFilter(tc.req, fullStatus)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectIP, filter.IP)
+		})
+	}
+
+	filter, err := parseFlowFilter(&proto.ListFlowsRequest{Protocol: "TCP", Direction: "egress", Port: 443, Limit: 1 << 20}, fullStatus)
+	require.NoError(t, err)
+	assert.Equal(t, nftypes.TCP, filter.Protocol)
+	assert.Equal(t, nftypes.Egress, filter.Direction)
+	assert.Equal(t, uint16(443), filter.Port)
+	assert.Equal(t, maxFlowsLimit, filter.Limit)
+
+	filter, err = parseFlowFilter(&proto.ListFlowsRequest{Protocol: "47"}, fullStatus)
+	require.NoError(t, err)
+	assert.Equal(t, nftypes.Protocol(47), filter.Protocol)
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"net/netip"
 
+	"github.com/google/gopacket/layers"
+
 	fw "github.com/netbirdio/netbird/client/firewall/manager"
 	"github.com/netbirdio/netbird/client/firewall/uspfilter"
 	"github.com/netbirdio/netbird/client/internal"
@@ -24,17 +26,12 @@ func (s *Server) TracePacket(_ context.Context, req *proto.TracePacketRequest) (
 		return nil, err
 	}
 
-	srcAddr, err := s.parseAddress(req.GetSourceIp(), engine)
-	if err != nil {
-		return nil, fmt.Errorf("invalid source IP address: %w", err)
-	}
-
-	dstAddr, err := s.parseAddress(req.GetDestinationIp(), engine)
+	srcAddr, dstAddr, err := s.parseAddresses(req.GetSourceIp(), req.GetDestinationIp(), engine)
 	if err != nil {
-		return nil, fmt.Errorf("invalid destination IP address: %w", err)
+		return nil, err
 	}
 
-	protocol, err := s.parseProtocol(req.GetProtocol())
+	protocol, err := s.parseProtocol(req.GetProtocol(), dstAddr)
 	if err != nil {
 		return nil, err
 	}
@@ -54,7 +51,7 @@ func (s *Server) TracePacket(_ context.Context, req *proto.TracePacketRequest) (
 		DstPort:   uint16(req.GetDestinationPort()),
 		Direction: direction,
 		TCPState:  tcpState,
-		ICMPType:  uint8(req.GetIcmpType()),
+		ICMPType:  s.parseICMPType(req, dstAddr),
 		ICMPCode:  uint8(req.GetIcmpCode()),
 	}
 
@@ -89,9 +86,29 @@ func (s *Server) getPacketTracer() (packetTracer, *internal.Engine, error) {
 	return tracer, engine, nil
 }
 
-func (s *Server) parseAddress(addr string, engine *internal.Engine) (netip.Addr, error) {
+// parseAddresses parses the source and destination addresses. "self" resolves to the overlay address of the same
+// address family as the other address, both addresses must be of the same family.
+func (s *Server) parseAddresses(src, dst string, engine *internal.Engine) (netip.Addr, netip.Addr, error) {
+	srcAddr, err := s.parseAddress(src, dst, engine)
+	if err != nil {
+		return netip.Addr{}, netip.Addr{}, fmt.Errorf("invalid source IP address: %w", err)
+	}
+
+	dstAddr, err := s.parseAddress(dst, src, engine)
+	if err != nil {
+		return netip.Addr{}, netip.Addr{}, fmt.Errorf("invalid destination IP address: %w", err)
+	}
+
+	if srcAddr.Is4() != dstAddr.Is4() {
+		return netip.Addr{}, netip.Addr{}, fmt.Errorf("source IP address %s and destination IP address %s are of different address families", srcAddr, dstAddr)
+	}
+
+	return srcAddr, dstAddr, nil
+}
+
+func (s *Server) parseAddress(addr, other string, engine *internal.Engine) (netip.Addr, error) {
 	if addr == "self" {
-		return engine.GetWgAddr(), nil
+		return s.selfAddress(other, engine)
 	}
 
 	a, err := netip.ParseAddr(addr)
@@ -102,7 +119,20 @@ func (s *Server) parseAddress(addr string, engine *internal.Engine) (netip.Addr,
 	return a.Unmap(), nil
 }
 
-func (s *Server) parseProtocol(protocol string) (fw.Protocol, error) {
+func (s *Server) selfAddress(other string, engine *internal.Engine) (netip.Addr, error) {
+	otherAddr, err := netip.ParseAddr(other)
+	if err != nil || otherAddr.Unmap().Is4() {
+		return engine.GetWgAddr(), nil
+	}
+
+	addr := engine.GetWgIPv6Addr()
+	if !addr.IsValid() {
+		return netip.Addr{}, fmt.Errorf("peer has no IPv6 overlay address")
+	}
+	return addr, nil
+}
+
+func (s *Server) parseProtocol(protocol string, dstAddr netip.Addr) (fw.Protocol, error) {
 	switch protocol {
 	case "tcp":
 		return fw.ProtocolTCP, nil
@@ -110,11 +140,27 @@ func (s *Server) parseProtocol(protocol string) (fw.Protocol, error) {
 		return fw.ProtocolUDP, nil
 	case "icmp":
 		return fw.ProtocolICMP, nil
+	case "icmpv6":
+		if dstAddr.Is4() {
+			return "", fmt.Errorf("icmpv6 requires IPv6 addresses")
+		}
+		return fw.ProtocolICMP, nil
 	default:
 		return "", fmt.Errorf("invalid protocol")
 	}
 }
 
+// parseICMPType returns the requested ICMP type, defaulting to an echo request of the address family
+func (s *Server) parseICMPType(req *proto.TracePacketRequest, dstAddr netip.Addr) uint8 {
+	if req.IcmpType != nil {
+		return uint8(req.GetIcmpType())
+	}
+	if dstAddr.Is4() {
+		return layers.ICMPv4TypeEchoRequest
+	}
+	return layers.ICMPv6TypeEchoRequest
+}
+
 func (s *Server) parseDirection(direction string) (fw.RuleDirection, error) {
 	switch direction {
 	case "in":
@@ -0,0 +1,47 @@
+package server
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+func TestParseICMPType(t *testing.T) {
+	echoReply := uint32(0)
+
+	tests := []struct {
+		name     string
+		req      *proto.TracePacketRequest
+		dstAddr  netip.Addr
+		expected uint8
+	}{
+		{
+			name:     "IPv4 defaults to echo request",
+			req:      &proto.TracePacketRequest{},
+			dstAddr:  netip.MustParseAddr("100.64.0.1"),
+			expected: 8,
+		},
+		{
+			name:     "IPv6 defaults to echo request",
+			req:      &proto.TracePacketRequest{},
+			dstAddr:  netip.MustParseAddr("fd00::1"),
+			expected: 128,
+		},
+		{
+			name:     "explicit type is kept",
+			req:      &proto.TracePacketRequest{IcmpType: &echoReply},
+			dstAddr:  netip.MustParseAddr("100.64.0.1"),
+			expected: 0,
+		},
+	}
+
+	s := &Server{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, s.parseICMPType(tt.req, tt.dstAddr))
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package status
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+type PostureCheckOutput struct {
+	PostureChecksID   string    `json:"postureChecksId" yaml:"postureChecksId"`
+	PostureChecksName string    `json:"postureChecksName" yaml:"postureChecksName"`
+	Check             string    `json:"check" yaml:"check"`
+	Passed            bool      `json:"passed" yaml:"passed"`
+	Reason            string    `json:"reason" yaml:"reason"`
+	EvaluatedAt       time.Time `json:"evaluatedAt" yaml:"evaluatedAt"`
+}
+
+type PostureChecksOutput struct {
+	Total   int                  `json:"total" yaml:"total"`
+	Passed  int                  `json:"passed" yaml:"passed"`
+	Details []PostureCheckOutput `json:"details" yaml:"details"`
+}
+
+func mapPostureChecks(results []*proto.PostureCheckResult) PostureChecksOutput {
+	details := make([]PostureCheckOutput, 0, len(results))
+
+	var passed int
+	for _, result := range results {
+		details = append(details, PostureCheckOutput{
+			PostureChecksID:   result.GetPostureCheckId(),
+			PostureChecksName: result.GetPostureCheckName(),
+			Check:             result.GetCheck(),
+			Passed:            result.GetPassed(),
+			Reason:            result.GetReason(),
+			EvaluatedAt:       result.GetEvaluatedAt().AsTime(),
+		})
+
+		if result.GetPassed() {
+			passed++
+		}
+	}
+
+	return PostureChecksOutput{
+		Total:   len(results),
+		Passed:  passed,
+		Details: details,
+	}
+}
+
+func parsePostureChecks(postureChecks PostureChecksOutput) string {
+	if len(postureChecks.Details) == 0 {
+		return " No posture checks applied"
+	}
+
+	var postureChecksString strings.Builder
+	for _, check := range postureChecks.Details {
+		result := "Passed"
+		if !check.Passed {
+			result = fmt.Sprintf("Failed, reason: %s", check.Reason)
+		}
+
+		postureChecksString.WriteString(fmt.Sprintf("\n  [%s] %s: %s"+
+			"\n    Evaluated: %s",
+			check.PostureChecksName,
+			check.Check,
+			result,
+			timeAgo(check.EvaluatedAt),
+		))
+	}
+	return postureChecksString.String()
+}
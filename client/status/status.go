@@ -114,6 +114,7 @@ type OutputOverview struct {
 	LazyConnectionEnabled   bool                       `json:"lazyConnectionEnabled" yaml:"lazyConnectionEnabled"`
 	ProfileName             string                     `json:"profileName" yaml:"profileName"`
 	SSHServerState          SSHServerStateOutput       `json:"sshServer" yaml:"sshServer"`
+	PostureChecks           PostureChecksOutput        `json:"postureChecks" yaml:"postureChecks"`
 }
 
 func ConvertToStatusOutputOverview(resp *proto.StatusResponse, anon bool, statusFilter string, prefixNamesFilter []string, prefixNamesFilterMap map[string]struct{}, ipsFilter map[string]struct{}, connectionTypeFilter string, profName string) OutputOverview {
@@ -157,6 +158,7 @@ func ConvertToStatusOutputOverview(resp *proto.StatusResponse, anon bool, status
 		LazyConnectionEnabled:   pbFullStatus.GetLazyConnectionEnabled(),
 		ProfileName:             profName,
 		SSHServerState:          sshServerOverview,
+		PostureChecks:           mapPostureChecks(pbFullStatus.GetPostureChecks()),
 	}
 
 	if anon {
@@ -487,6 +489,8 @@ func (o *OutputOverview) GeneralSummary(showURL bool, showRelays bool, showNameS
 		}
 	}
 
+	postureChecksString := fmt.Sprintf("%d/%d Passed", o.PostureChecks.Passed, o.PostureChecks.Total)
+
 	peersCountString := fmt.Sprintf("%d/%d Connected", o.Peers.Connected, o.Peers.Total)
 
 	goos := runtime.GOOS
@@ -513,6 +517,7 @@ func (o *OutputOverview) GeneralSummary(showURL bool, showRelays bool, showNameS
 			"SSH Server: %s\n"+
 			"Networks: %s\n"+
 			"Forwarding rules: %d\n"+
+			"Posture checks: %s\n"+
 			"Peers count: %s\n",
 		fmt.Sprintf("%s/%s%s", goos, goarch, goarm),
 		o.DaemonVersion,
@@ -530,6 +535,7 @@ func (o *OutputOverview) GeneralSummary(showURL bool, showRelays bool, showNameS
 		sshServerStatus,
 		networks,
 		o.NumberOfForwardingRules,
+		postureChecksString,
 		peersCountString,
 	)
 	return summary
@@ -539,6 +545,7 @@ func (o *OutputOverview) GeneralSummary(showURL bool, showRelays bool, showNameS
 func (o *OutputOverview) FullDetailSummary() string {
 	parsedPeersString := parsePeers(o.Peers, o.RosenpassEnabled, o.RosenpassPermissive)
 	parsedEventsString := parseEvents(o.Events)
+	parsedPostureChecksString := parsePostureChecks(o.PostureChecks)
 	summary := o.GeneralSummary(true, true, true, true)
 
 	return fmt.Sprintf(
@@ -546,9 +553,12 @@ func (o *OutputOverview) FullDetailSummary() string {
 			"%s\n"+
 			"Events:"+
 			"%s\n"+
+			"Posture checks detail:"+
+			"%s\n"+
 			"%s",
 		parsedPeersString,
 		parsedEventsString,
+		parsedPostureChecksString,
 		summary,
 	)
 }
@@ -832,6 +842,10 @@ func anonymizeOverview(a *anonymize.Anonymizer, overview *OutputOverview) {
 		}
 	}
 
+	for i, check := range overview.PostureChecks.Details {
+		overview.PostureChecks.Details[i].Reason = a.AnonymizeString(check.Reason)
+	}
+
 	for i, session := range overview.SSHServerState.Sessions {
 		if host, port, err := net.SplitHostPort(session.RemoteAddress); err == nil {
 			overview.SSHServerState.Sessions[i].RemoteAddress = fmt.Sprintf("%s:%s", a.AnonymizeIPString(host), port)
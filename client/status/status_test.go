@@ -119,6 +119,23 @@ var resp = &proto.StatusResponse{
 				Error:   "timeout",
 			},
 		},
+		PostureChecks: []*proto.PostureCheckResult{
+			{
+				PostureCheckId:   "checks1",
+				PostureCheckName: "production",
+				Check:            "NBVersionCheck",
+				Passed:           true,
+				EvaluatedAt:      timestamppb.New(time.Date(2003, 3, 3, 3, 3, 3, 0, time.UTC)),
+			},
+			{
+				PostureCheckId:   "checks1",
+				PostureCheckName: "production",
+				Check:            "OSVersionCheck",
+				Passed:           false,
+				Reason:           "kernel version 5.15.0 < 6.1",
+				EvaluatedAt:      timestamppb.New(time.Date(2003, 3, 3, 3, 3, 3, 0, time.UTC)),
+			},
+		},
 	},
 	DaemonVersion: "0.14.1",
 }
@@ -235,6 +252,27 @@ var overview = OutputOverview{
 		Enabled:  false,
 		Sessions: []SSHSessionOutput{},
 	},
+	PostureChecks: PostureChecksOutput{
+		Total:  2,
+		Passed: 1,
+		Details: []PostureCheckOutput{
+			{
+				PostureChecksID:   "checks1",
+				PostureChecksName: "production",
+				Check:             "NBVersionCheck",
+				Passed:            true,
+				EvaluatedAt:       time.Date(2003, 3, 3, 3, 3, 3, 0, time.UTC),
+			},
+			{
+				PostureChecksID:   "checks1",
+				PostureChecksName: "production",
+				Check:             "OSVersionCheck",
+				Passed:            false,
+				Reason:            "kernel version 5.15.0 < 6.1",
+				EvaluatedAt:       time.Date(2003, 3, 3, 3, 3, 3, 0, time.UTC),
+			},
+		},
+	},
 }
 
 func TestConversionFromFullStatusToOutputOverview(t *testing.T) {
@@ -393,7 +431,29 @@ func TestParsingToJSON(t *testing.T) {
 		  "sshServer":{
 		    "enabled":false,
 			"sessions":[]
-		  }
+		  },
+          "postureChecks": {
+            "total": 2,
+            "passed": 1,
+            "details": [
+              {
+                "postureChecksId": "checks1",
+                "postureChecksName": "production",
+                "check": "NBVersionCheck",
+                "passed": true,
+                "reason": "",
+                "evaluatedAt": "2003-03-03T03:03:03Z"
+              },
+              {
+                "postureChecksId": "checks1",
+                "postureChecksName": "production",
+                "check": "OSVersionCheck",
+                "passed": false,
+                "reason": "kernel version 5.15.0 \u003c 6.1",
+                "evaluatedAt": "2003-03-03T03:03:03Z"
+              }
+            ]
+          }
         }`
 	// @formatter:on
 
@@ -499,6 +559,22 @@ profileName: ""
 sshServer:
     enabled: false
     sessions: []
+postureChecks:
+    total: 2
+    passed: 1
+    details:
+        - postureChecksId: checks1
+          postureChecksName: production
+          check: NBVersionCheck
+          passed: true
+          reason: ""
+          evaluatedAt: 2003-03-03T03:03:03Z
+        - postureChecksId: checks1
+          postureChecksName: production
+          check: OSVersionCheck
+          passed: false
+          reason: kernel version 5.15.0 < 6.1
+          evaluatedAt: 2003-03-03T03:03:03Z
 `
 
 	assert.Equal(t, expectedYAML, yaml)
@@ -510,6 +586,7 @@ func TestParsingToDetail(t *testing.T) {
 	lastHandshake1 := timeAgo(overview.Peers.Details[0].LastWireguardHandshake)
 	lastConnectionUpdate2 := timeAgo(overview.Peers.Details[1].LastStatusUpdate)
 	lastHandshake2 := timeAgo(overview.Peers.Details[1].LastWireguardHandshake)
+	postureChecksEvaluated := timeAgo(overview.PostureChecks.Details[0].EvaluatedAt)
 
 	detail := overview.FullDetailSummary()
 
@@ -548,6 +625,11 @@ func TestParsingToDetail(t *testing.T) {
   Latency: 10ms
 
 Events: No events recorded
+Posture checks detail:
+  [production] NBVersionCheck: Passed
+    Evaluated: %s
+  [production] OSVersionCheck: Failed, reason: kernel version 5.15.0 < 6.1
+    Evaluated: %s
 OS: %s/%s
 Daemon version: 0.14.1
 CLI version: %s
@@ -568,8 +650,9 @@ Lazy connection: false
 SSH Server: Disabled
 Networks: 10.10.0.0/24
 Forwarding rules: 0
+Posture checks: 1/2 Passed
 Peers count: 2/2 Connected
-`, lastConnectionUpdate1, lastHandshake1, lastConnectionUpdate2, lastHandshake2, runtime.GOOS, runtime.GOARCH, overview.CliVersion)
+`, lastConnectionUpdate1, lastHandshake1, lastConnectionUpdate2, lastHandshake2, postureChecksEvaluated, postureChecksEvaluated, runtime.GOOS, runtime.GOARCH, overview.CliVersion)
 
 	assert.Equal(t, expectedDetail, detail)
 }
@@ -593,6 +676,7 @@ Lazy connection: false
 SSH Server: Disabled
 Networks: 10.10.0.0/24
 Forwarding rules: 0
+Posture checks: 1/2 Passed
 Peers count: 2/2 Connected
 `
 
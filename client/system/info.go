@@ -40,6 +40,18 @@ type File struct {
 	ProcessIsRunning bool
 }
 
+// DiskEncryption holds the disk encryption state collected for posture checks
+type DiskEncryption struct {
+	// EncryptedVolumes are the mount points backed by an encrypted block device
+	EncryptedVolumes []string
+}
+
+// OSFirewall holds the operating system firewall state collected for posture checks
+type OSFirewall struct {
+	// ActiveBackends are the firewall frontends that currently enforce rules
+	ActiveBackends []string
+}
+
 // Info is an object that contains machine information
 // Most of the code is taken from https://github.com/matishsiao/goInfo
 type Info struct {
@@ -59,6 +71,8 @@ type Info struct {
 	SystemManufacturer string
 	Environment        Environment
 	Files              []File // for posture checks
+	DiskEncryption     DiskEncryption
+	OSFirewall         OSFirewall
 
 	RosenpassEnabled    bool
 	RosenpassPermissive bool
@@ -73,6 +87,9 @@ type Info struct {
 
 	LazyConnectionEnabled bool
 
+	// IPv6Supported indicates that the client is able to configure an IPv6 overlay address
+	IPv6Supported bool
+
 	EnableSSHRoot                 bool
 	EnableSSHSFTP                 bool
 	EnableSSHLocalPortForwarding  bool
@@ -102,6 +119,7 @@ func (i *Info) SetFlags(
 	i.BlockInbound = blockInbound
 
 	i.LazyConnectionEnabled = lazyConnectionEnabled
+	i.IPv6Supported = ipv6Supported()
 
 	if enableSSHRoot != nil {
 		i.EnableSSHRoot = *enableSSHRoot
@@ -199,8 +217,11 @@ func isDuplicated(addresses []NetworkAddress, addr NetworkAddress) bool {
 func GetInfoWithChecks(ctx context.Context, checks []*proto.Checks) (*Info, error) {
 	log.Debugf("gathering system information with checks: %d", len(checks))
 	processCheckPaths := make([]string, 0)
+	var checkDiskEncryption, checkOSFirewall bool
 	for _, check := range checks {
 		processCheckPaths = append(processCheckPaths, check.GetFiles()...)
+		checkDiskEncryption = checkDiskEncryption || check.GetDiskEncryption()
+		checkOSFirewall = checkOSFirewall || check.GetOsFirewall()
 	}
 
 	files, err := checkFileAndProcess(processCheckPaths)
@@ -212,6 +233,16 @@ func GetInfoWithChecks(ctx context.Context, checks []*proto.Checks) (*Info, erro
 	info := GetInfo(ctx)
 	info.Files = files
 
+	if checkDiskEncryption {
+		info.DiskEncryption = getDiskEncryption()
+		log.Debugf("gathering disk encryption information completed")
+	}
+
+	if checkOSFirewall {
+		info.OSFirewall = getOSFirewall(ctx)
+		log.Debugf("gathering firewall information completed")
+	}
+
 	log.Debugf("all system information gathered successfully")
 	return info, nil
 }
@@ -0,0 +1,15 @@
+//go:build (linux && !android) || (darwin && !ios) || windows || freebsd
+
+package system
+
+import "os"
+
+// envUseNetstackMode mirrors netstack.EnvUseNetstackMode, the netstack package is not imported to keep its
+// dependencies out of this package
+const envUseNetstackMode = "NB_USE_NETSTACK_MODE"
+
+// ipv6Supported reports whether the client is able to configure an IPv6 overlay address on its interface.
+// The netstack (userspace network stack) mode has no IPv6 support.
+func ipv6Supported() bool {
+	return os.Getenv(envUseNetstackMode) != "true"
+}
@@ -0,0 +1,9 @@
+//go:build !((linux && !android) || (darwin && !ios) || windows || freebsd)
+
+package system
+
+// ipv6Supported reports whether the client is able to configure an IPv6 overlay address on its interface.
+// Mobile and browser clients don't configure the interface address themselves.
+func ipv6Supported() bool {
+	return false
+}
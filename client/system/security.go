@@ -0,0 +1,15 @@
+//go:build !linux || android
+
+package system
+
+import "context"
+
+// getDiskEncryption is not implemented on this platform, no encrypted volumes are reported
+func getDiskEncryption() DiskEncryption {
+	return DiskEncryption{}
+}
+
+// getOSFirewall is not implemented on this platform, no active firewall is reported
+func getOSFirewall(_ context.Context) OSFirewall {
+	return OSFirewall{}
+}
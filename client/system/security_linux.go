@@ -0,0 +1,271 @@
+//go:build !android
+
+package system
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/nftables"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// dmCryptUUIDPrefix is the device-mapper uuid prefix of LUKS and plain dm-crypt targets
+	dmCryptUUIDPrefix = "CRYPT-"
+	// maxBlockDeviceDepth limits the walk through stacked block devices, e.g. LVM on LUKS
+	maxBlockDeviceDepth = 8
+
+	firewallCommandTimeout = 5 * time.Second
+
+	firewallBackendNftables  = "nftables"
+	firewallBackendUFW       = "ufw"
+	firewallBackendFirewalld = "firewalld"
+
+	// netbirdNftablesTable is the table managed by the netbird client itself, it doesn't count as an OS firewall
+	netbirdNftablesTable = "netbird"
+)
+
+var (
+	// they are overridden in tests
+	mountInfoPath  = "/proc/self/mountinfo"
+	sysDevBlockDir = "/sys/dev/block"
+)
+
+// getDiskEncryption returns the mount points which are backed by a dm-crypt (LUKS or plain) device.
+// Stacked devices like LVM on top of LUKS are followed down to the encrypted layer.
+func getDiskEncryption() DiskEncryption {
+	mounts, err := readBlockMounts()
+	if err != nil {
+		log.Warnf("failed to read mounts for disk encryption check: %v", err)
+		return DiskEncryption{}
+	}
+
+	var encrypted []string
+	for _, m := range mounts {
+		if isEncryptedBlockDevice(m.device, 0) {
+			encrypted = append(encrypted, m.mountPoint)
+		}
+	}
+
+	return DiskEncryption{EncryptedVolumes: encrypted}
+}
+
+type blockMount struct {
+	// device is the block device number in the major:minor format
+	device     string
+	mountPoint string
+}
+
+// readBlockMounts parses the mountinfo file and returns the mounts backed by a block device
+func readBlockMounts() ([]blockMount, error) {
+	f, err := os.Open(mountInfoPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	seen := make(map[string]struct{})
+	var mounts []blockMount
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// 36 35 98:0 /mnt1 /mnt2 rw,noatime master:1 - ext3 /dev/root rw,errors=continue
+		fields := strings.Fields(scanner.Text())
+		sep := -1
+		for i, field := range fields {
+			if field == "-" {
+				sep = i
+				break
+			}
+		}
+		if len(fields) < 5 || sep < 0 || len(fields) < sep+3 {
+			continue
+		}
+
+		mountPoint := unescapeMountPath(fields[4])
+		if _, ok := seen[mountPoint]; ok {
+			continue
+		}
+
+		device := fields[2]
+		// filesystems like btrfs report an anonymous device number, resolve the source device instead
+		if strings.HasPrefix(device, "0:") {
+			source := fields[sep+2]
+			if !strings.HasPrefix(source, "/dev/") {
+				continue
+			}
+			device, err = blockDeviceNumber(source)
+			if err != nil {
+				continue
+			}
+		}
+
+		seen[mountPoint] = struct{}{}
+		mounts = append(mounts, blockMount{device: device, mountPoint: mountPoint})
+	}
+
+	return mounts, scanner.Err()
+}
+
+func blockDeviceNumber(path string) (string, error) {
+	var stat unix.Stat_t
+	if err := unix.Stat(path, &stat); err != nil {
+		return "", err
+	}
+	if stat.Mode&unix.S_IFMT != unix.S_IFBLK {
+		return "", fmt.Errorf("%s is not a block device", path)
+	}
+	return fmt.Sprintf("%d:%d", unix.Major(stat.Rdev), unix.Minor(stat.Rdev)), nil
+}
+
+// isEncryptedBlockDevice checks whether the device or one of the devices it is stacked on is a dm-crypt target
+func isEncryptedBlockDevice(device string, depth int) bool {
+	if depth > maxBlockDeviceDepth {
+		return false
+	}
+
+	devicePath := filepath.Join(sysDevBlockDir, device)
+	uuid, err := os.ReadFile(filepath.Join(devicePath, "dm", "uuid"))
+	if err == nil && strings.HasPrefix(strings.TrimSpace(string(uuid)), dmCryptUUIDPrefix) {
+		return true
+	}
+
+	slaves, err := os.ReadDir(filepath.Join(devicePath, "slaves"))
+	if err != nil {
+		return false
+	}
+
+	for _, slave := range slaves {
+		slaveDevice, err := os.ReadFile(filepath.Join(devicePath, "slaves", slave.Name(), "dev"))
+		if err != nil {
+			continue
+		}
+		if isEncryptedBlockDevice(strings.TrimSpace(string(slaveDevice)), depth+1) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// unescapeMountPath decodes the octal escapes (e.g. \040 for space) used in mountinfo paths
+func unescapeMountPath(path string) string {
+	if !strings.Contains(path, `\`) {
+		return path
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		if path[i] == '\\' && i+3 < len(path) {
+			if c, err := strconv.ParseUint(path[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(c))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(path[i])
+	}
+	return b.String()
+}
+
+// getOSFirewall returns the firewall frontends that currently enforce rules on the system
+func getOSFirewall(ctx context.Context) OSFirewall {
+	var backends []string
+
+	if isUFWActive(ctx) {
+		backends = append(backends, firewallBackendUFW)
+	}
+
+	if isFirewalldRunning(ctx) {
+		backends = append(backends, firewallBackendFirewalld)
+	}
+
+	active, err := hasNftablesInputPolicy()
+	if err != nil {
+		log.Debugf("failed to check nftables ruleset: %v", err)
+	}
+	if active {
+		backends = append(backends, firewallBackendNftables)
+	}
+
+	return OSFirewall{ActiveBackends: backends}
+}
+
+func isUFWActive(ctx context.Context) bool {
+	out, err := runFirewallCommand(ctx, "ufw", "status")
+	if err != nil {
+		return false
+	}
+	return bytes.Contains(out, []byte("Status: active"))
+}
+
+func isFirewalldRunning(ctx context.Context) bool {
+	out, err := runFirewallCommand(ctx, "firewall-cmd", "--state")
+	if err != nil {
+		return false
+	}
+	return strings.TrimSpace(string(out)) == "running"
+}
+
+func runFirewallCommand(ctx context.Context, name string, args ...string) ([]byte, error) {
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, firewallCommandTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, path, args...).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			log.Debugf("failed to run %s: %v", name, err)
+		}
+		return nil, err
+	}
+	return out, nil
+}
+
+// hasNftablesInputPolicy reports whether a filter chain hooked on input drops traffic by default.
+// The netbird table is skipped as it only covers the overlay interface.
+func hasNftablesInputPolicy() (bool, error) {
+	conn, err := nftables.New()
+	if err != nil {
+		return false, err
+	}
+
+	chains, err := conn.ListChains()
+	if err != nil {
+		return false, err
+	}
+
+	for _, chain := range chains {
+		if chain.Table == nil || chain.Table.Name == netbirdNftablesTable {
+			continue
+		}
+		switch chain.Table.Family {
+		case nftables.TableFamilyIPv4, nftables.TableFamilyIPv6, nftables.TableFamilyINet:
+		default:
+			continue
+		}
+		if chain.Type != nftables.ChainTypeFilter || chain.Hooknum == nil || *chain.Hooknum != *nftables.ChainHookInput {
+			continue
+		}
+		if chain.Policy != nil && *chain.Policy == nftables.ChainPolicyDrop {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
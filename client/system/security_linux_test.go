@@ -0,0 +1,53 @@
+//go:build !android
+
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func Test_getDiskEncryption(t *testing.T) {
+	dir := t.TempDir()
+
+	// LVM volume (253:1) on top of a LUKS container (253:0) mounted as root
+	writeTestFile(t, filepath.Join(dir, "block", "253:0", "dm", "uuid"), "CRYPT-LUKS2-8c5e6a6d1b2f4f0c9b1f0e8d6a4c2b1a-luks-root\n")
+	writeTestFile(t, filepath.Join(dir, "block", "253:1", "dm", "uuid"), "LVM-abcdef\n")
+	writeTestFile(t, filepath.Join(dir, "block", "253:1", "slaves", "dm-0", "dev"), "253:0\n")
+	// plain partition mounted as /boot
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "block", "259:1"), 0o755))
+	// plain LVM volume mounted with an escaped path
+	writeTestFile(t, filepath.Join(dir, "block", "253:2", "dm", "uuid"), "LVM-123456\n")
+
+	mountInfo := `22 1 253:1 / / rw,relatime shared:1 - ext4 /dev/mapper/vg-root rw
+23 22 259:1 / /boot rw,relatime shared:2 - ext4 /dev/nvme0n1p1 rw
+24 22 253:2 / /mnt/my\040data rw,relatime shared:3 - xfs /dev/mapper/vg-data rw
+25 22 0:22 / /proc rw,nosuid,nodev,noexec,relatime shared:4 - proc proc rw
+26 22 253:1 /var /var rw,relatime shared:1 - ext4 /dev/mapper/vg-root rw
+`
+	writeTestFile(t, filepath.Join(dir, "mountinfo"), mountInfo)
+
+	origMountInfoPath, origSysDevBlockDir := mountInfoPath, sysDevBlockDir
+	mountInfoPath = filepath.Join(dir, "mountinfo")
+	sysDevBlockDir = filepath.Join(dir, "block")
+	t.Cleanup(func() {
+		mountInfoPath, sysDevBlockDir = origMountInfoPath, origSysDevBlockDir
+	})
+
+	mounts, err := readBlockMounts()
+	require.NoError(t, err)
+	assert.Contains(t, mounts, blockMount{device: "253:2", mountPoint: "/mnt/my data"})
+
+	diskEncryption := getDiskEncryption()
+	assert.Equal(t, []string{"/", "/var"}, diskEncryption.EncryptedVolumes)
+}
@@ -1,11 +1,18 @@
 package dns
 
 import (
+	"errors"
 	"fmt"
 	"net/netip"
 	"net/url"
+	"regexp"
 	"strconv"
 	"strings"
+	"unicode/utf8"
+
+	"github.com/miekg/dns"
+
+	"github.com/netbirdio/netbird/shared/management/status"
 )
 
 const (
@@ -22,6 +29,15 @@ const (
 	InvalidNameServerTypeString = "invalid"
 	// UDPNameServerTypeString udp nameserver type as string
 	UDPNameServerTypeString = "udp"
+	// MaxNameServers maximum number of nameservers in a group
+	MaxNameServers = 3
+)
+
+const domainPattern = `^(?i)[a-z0-9]+([\-\.]{1}[a-z0-9]+)*[*.a-z]{1,}$`
+
+var (
+	errInvalidDomainName = errors.New("invalid domain name")
+	domainMatcher        = regexp.MustCompile(domainPattern)
 )
 
 // NameServerType nameserver type
@@ -86,6 +102,59 @@ func (g *NameServerGroup) EventMeta() map[string]any {
 	return map[string]any{"name": g.Name}
 }
 
+// Validate validates the settings of the nameserver group that don't depend on the other objects of the account
+func (g *NameServerGroup) Validate() error {
+	if err := validateDomainInput(g.Primary, g.Domains, g.SearchDomainsEnabled); err != nil {
+		return err
+	}
+
+	if len(g.NameServers) == 0 || len(g.NameServers) > MaxNameServers {
+		return status.Errorf(status.InvalidArgument, "the list of nameservers should be 1 or 3, got %d", len(g.NameServers))
+	}
+
+	if utf8.RuneCountInString(g.Name) > MaxGroupNameChar || g.Name == "" {
+		return status.Errorf(status.InvalidArgument, "nameserver group name should be between 1 and %d", MaxGroupNameChar)
+	}
+
+	return nil
+}
+
+func validateDomainInput(primary bool, domains []string, searchDomainsEnabled bool) error {
+	if !primary && len(domains) == 0 {
+		return status.Errorf(status.InvalidArgument, "nameserver group primary status is false and domains are empty,"+
+			" it should be primary or have at least one domain")
+	}
+	if primary && len(domains) != 0 {
+		return status.Errorf(status.InvalidArgument, "nameserver group primary status is true and domains are not empty,"+
+			" you should set either primary or domain")
+	}
+
+	if primary && searchDomainsEnabled {
+		return status.Errorf(status.InvalidArgument, "nameserver group primary status is true and search domains is enabled,"+
+			" you should not set search domains for primary nameservers")
+	}
+
+	for _, domain := range domains {
+		if err := validateDomain(domain); err != nil {
+			return status.Errorf(status.InvalidArgument, "nameserver group got an invalid domain: %s %q", domain, err)
+		}
+	}
+	return nil
+}
+
+func validateDomain(domain string) error {
+	if !domainMatcher.MatchString(domain) {
+		return errors.New("domain should consists of only letters, numbers, and hyphens with no leading, trailing hyphens, or spaces")
+	}
+
+	_, valid := dns.IsDomainName(domain)
+	if !valid {
+		return errInvalidDomainName
+	}
+
+	return nil
+}
+
 // Copy copies a nameserver object
 func (n *NameServer) Copy() *NameServer {
 	return &NameServer{
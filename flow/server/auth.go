@@ -0,0 +1,58 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+
+	flowauth "github.com/netbirdio/netbird/shared/flow/auth"
+)
+
+// Validator validates the token the flow client sends in the authorization header and returns the WireGuard public
+// key of the peer the token was issued for
+type Validator interface {
+	Validate(payload, signature string) (string, error)
+}
+
+// HMACValidator validates the peer bound HMAC-SHA256 tokens the management server issues with the shared secret
+type HMACValidator struct {
+	secret string
+}
+
+// NewHMACValidator creates a validator for tokens signed with secret
+func NewHMACValidator(secret string) *HMACValidator {
+	return &HMACValidator{secret: secret}
+}
+
+func (v *HMACValidator) Validate(payload, signature string) (string, error) {
+	return flowauth.ValidateToken(v.secret, payload, signature)
+}
+
+// tokenFromContext extracts the payload and signature from the "Bearer <signature>.<payload>" authorization header
+func tokenFromContext(ctx context.Context) (payload, signature string, err error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", "", errors.New("missing metadata")
+	}
+
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return "", "", errors.New("missing authorization header")
+	}
+
+	token, ok := strings.CutPrefix(values[0], "Bearer ")
+	if !ok {
+		return "", "", errors.New("authorization header is not a bearer token")
+	}
+
+	// the base64 signature does not contain dots, the payload may
+	signature, payload, ok = strings.Cut(token, ".")
+	if !ok || signature == "" || payload == "" {
+		return "", "", fmt.Errorf("malformed token")
+	}
+
+	return payload, signature, nil
+}
@@ -0,0 +1,104 @@
+// Package server implements the flow receiver collecting the flow events of the peers
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/netbirdio/netbird/flow/proto"
+	"github.com/netbirdio/netbird/flow/sink"
+)
+
+// Server receives the flow events of the peers, writes them to the sink and acknowledges them
+type Server struct {
+	proto.UnimplementedFlowServiceServer
+
+	validator Validator
+	sink      sink.Sink
+}
+
+// NewServer creates a flow receiver writing to s. Streams are only accepted with a token valid for validator.
+func NewServer(validator Validator, s sink.Sink) *Server {
+	return &Server{
+		validator: validator,
+		sink:      s,
+	}
+}
+
+// Events receives the event stream of a peer
+func (s *Server) Events(stream proto.FlowService_EventsServer) error {
+	ctx := stream.Context()
+
+	payload, signature, err := tokenFromContext(ctx)
+	if err != nil {
+		return status.Errorf(codes.Unauthenticated, "invalid token: %v", err)
+	}
+	peerKey, err := s.validator.Validate(payload, signature)
+	if err != nil {
+		log.Debugf("rejected flow stream: %v", err)
+		return status.Errorf(codes.Unauthenticated, "invalid token: %v", err)
+	}
+
+	// the client waits for the header before it starts sending
+	if err := stream.SendHeader(metadata.Pairs("x-netbird-flow", "ready")); err != nil {
+		return err
+	}
+
+	for {
+		event, err := stream.Recv()
+		if errors.Is(err, io.EOF) || status.Code(err) == codes.Canceled || errors.Is(ctx.Err(), context.Canceled) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if event.GetIsInitiator() {
+			if err := stream.Send(&proto.FlowEventAck{IsInitiator: true}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// a peer can only report its own events
+		if eventKey := base64.StdEncoding.EncodeToString(event.GetPublicKey()); eventKey != peerKey {
+			log.Warnf("rejected flow stream of peer %s: event reported for peer %s", peerKey, eventKey)
+			return status.Errorf(codes.PermissionDenied, "event of another peer")
+		}
+
+		if !s.handleEvent(ctx, event) {
+			continue
+		}
+
+		if err := stream.Send(&proto.FlowEventAck{EventId: event.GetEventId()}); err != nil {
+			return err
+		}
+	}
+}
+
+// handleEvent writes the event to the sink and returns whether it should be acknowledged
+func (s *Server) handleEvent(ctx context.Context, event *proto.FlowEvent) bool {
+	e, err := sink.FromProto(event, time.Now().UTC())
+	if err != nil {
+		// acknowledge invalid events, the peer would send them again otherwise
+		log.Warnf("dropping invalid flow event: %v", err)
+		return true
+	}
+
+	if err := s.sink.Write(ctx, e); err != nil {
+		// the peer sends unacknowledged events again
+		log.Errorf("failed to write flow event %s: %v", e.ID, err)
+		return false
+	}
+
+	log.Tracef("stored flow event %s of peer %s", e.ID, e.PeerKey)
+	return true
+}
@@ -0,0 +1,242 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/netbirdio/netbird/flow/proto"
+	"github.com/netbirdio/netbird/flow/sink"
+	flowauth "github.com/netbirdio/netbird/shared/flow/auth"
+)
+
+const testSecret = "test-secret"
+
+// testPeerKey is the base64 encoded public key of the test events
+var testPeerKey = base64.StdEncoding.EncodeToString(make([]byte, 32))
+
+type memorySink struct {
+	mu     sync.Mutex
+	events []*sink.Event
+	// failID fails the writes of the event with the id
+	failID string
+}
+
+func (m *memorySink) Write(_ context.Context, event *sink.Event) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if event.ID == m.failID {
+		return errors.New("disk full")
+	}
+	m.events = append(m.events, event)
+	return nil
+}
+
+func (m *memorySink) Close() error {
+	return nil
+}
+
+func (m *memorySink) stored() []*sink.Event {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*sink.Event(nil), m.events...)
+}
+
+func startServer(t *testing.T, s sink.Sink) proto.FlowServiceClient {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	grpcServer := grpc.NewServer()
+	proto.RegisterFlowServiceServer(grpcServer, NewServer(NewHMACValidator(testSecret), s))
+	go func() {
+		if err := grpcServer.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			t.Logf("server error: %v", err)
+		}
+	}()
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	return proto.NewFlowServiceClient(conn)
+}
+
+func openStream(t *testing.T, client proto.FlowServiceClient, secret string) (proto.FlowService_EventsClient, error) {
+	t.Helper()
+
+	payload, signature, err := flowauth.GenerateToken(secret, testPeerKey, time.Hour)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+signature+"."+payload)
+
+	stream, err := client.Events(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, stream.Send(&proto.FlowEvent{IsInitiator: true}))
+	if _, err := stream.Header(); err != nil {
+		return nil, err
+	}
+	ack, err := stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	require.True(t, ack.GetIsInitiator())
+
+	return stream, nil
+}
+
+func testEvent(t *testing.T) *proto.FlowEvent {
+	t.Helper()
+
+	eventID := uuid.New()
+	flowID := uuid.New()
+	return &proto.FlowEvent{
+		EventId:   eventID[:],
+		PublicKey: make([]byte, 32),
+		FlowFields: &proto.FlowFields{
+			FlowId:    flowID[:],
+			Type:      proto.Type_TYPE_START,
+			Direction: proto.Direction_EGRESS,
+			Protocol:  6,
+			SourceIp:  []byte{100, 64, 0, 1},
+			DestIp:    []byte{100, 64, 0, 2},
+			ConnectionInfo: &proto.FlowFields_PortInfo{
+				PortInfo: &proto.PortInfo{SourcePort: 12345, DestPort: 443},
+			},
+		},
+	}
+}
+
+func TestServer_StoresAndAcknowledgesEvents(t *testing.T) {
+	store := &memorySink{}
+	client := startServer(t, store)
+
+	stream, err := openStream(t, client, testSecret)
+	require.NoError(t, err)
+
+	event := testEvent(t)
+	require.NoError(t, stream.Send(event))
+
+	ack, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, event.EventId, ack.EventId)
+
+	stored := store.stored()
+	require.Len(t, stored, 1)
+	assert.Equal(t, uuid.UUID(event.EventId).String(), stored[0].ID)
+	assert.Equal(t, "100.64.0.1", stored[0].SourceIP)
+	assert.Equal(t, uint16(443), stored[0].DestPort)
+	assert.Equal(t, "egress", stored[0].Direction)
+}
+
+func TestServer_RejectsInvalidToken(t *testing.T) {
+	client := startServer(t, &memorySink{})
+
+	_, err := openStream(t, client, "other-secret")
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestServer_RejectsEventsOfOtherPeers(t *testing.T) {
+	store := &memorySink{}
+	client := startServer(t, store)
+
+	stream, err := openStream(t, client, testSecret)
+	require.NoError(t, err)
+
+	event := testEvent(t)
+	event.PublicKey = bytes.Repeat([]byte{1}, 32)
+	require.NoError(t, stream.Send(event))
+
+	_, err = stream.Recv()
+	require.Error(t, err)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Empty(t, store.stored())
+}
+
+func TestServer_AcknowledgesInvalidEvents(t *testing.T) {
+	store := &memorySink{}
+	client := startServer(t, store)
+
+	stream, err := openStream(t, client, testSecret)
+	require.NoError(t, err)
+
+	event := testEvent(t)
+	event.FlowFields.SourceIp = []byte{1, 2, 3}
+	require.NoError(t, stream.Send(event))
+
+	ack, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, event.EventId, ack.EventId)
+	assert.Empty(t, store.stored())
+}
+
+func TestServer_DoesNotAcknowledgeFailedWrites(t *testing.T) {
+	failing := testEvent(t)
+	store := &memorySink{failID: uuid.UUID(failing.EventId).String()}
+	client := startServer(t, store)
+
+	stream, err := openStream(t, client, testSecret)
+	require.NoError(t, err)
+
+	require.NoError(t, stream.Send(failing))
+
+	event := testEvent(t)
+	require.NoError(t, stream.Send(event))
+
+	// only the second event is acknowledged
+	ack, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, event.EventId, ack.EventId)
+	assert.Len(t, store.stored(), 1)
+}
+
+func TestTokenFromContext(t *testing.T) {
+	tests := []struct {
+		name      string
+		header    string
+		payload   string
+		signature string
+		wantErr   bool
+	}{
+		{name: "valid", header: "Bearer c2ln.1700000000", payload: "1700000000", signature: "c2ln"},
+		{name: "missing bearer", header: "c2ln.1700000000", wantErr: true},
+		{name: "missing payload", header: "Bearer c2ln", wantErr: true},
+		{name: "empty signature", header: "Bearer .1700000000", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", tt.header))
+			payload, signature, err := tokenFromContext(ctx)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.payload, payload)
+			assert.Equal(t, tt.signature, signature)
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	jsonlPrefix     = "flows-"
+	jsonlSuffix     = ".jsonl"
+	jsonlDateLayout = time.DateOnly
+)
+
+// JSONL writes the events as JSON lines into one file per day (UTC) of their arrival
+type JSONL struct {
+	mux  sync.Mutex
+	dir  string
+	day  string
+	file *os.File
+}
+
+// NewJSONL creates a JSON lines sink in dir
+func NewJSONL(dir string) (*JSONL, error) {
+	if dir == "" {
+		return nil, errors.New("jsonl sink directory not set")
+	}
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("create jsonl sink directory: %w", err)
+	}
+
+	return &JSONL{dir: dir}, nil
+}
+
+func (j *JSONL) Write(_ context.Context, event *Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshal event: %w", err)
+	}
+	data = append(data, '\n')
+
+	j.mux.Lock()
+	defer j.mux.Unlock()
+
+	day := event.ReceivedAt.UTC().Format(jsonlDateLayout)
+	if j.file == nil || day != j.day {
+		if err := j.open(day); err != nil {
+			return err
+		}
+	}
+
+	if _, err := j.file.Write(data); err != nil {
+		return fmt.Errorf("write event: %w", err)
+	}
+
+	return nil
+}
+
+func (j *JSONL) open(day string) error {
+	if j.file != nil {
+		if err := j.file.Close(); err != nil {
+			log.Warnf("failed to close flow file: %v", err)
+		}
+		j.file = nil
+	}
+
+	path := filepath.Join(j.dir, jsonlPrefix+day+jsonlSuffix)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return fmt.Errorf("open flow file: %w", err)
+	}
+
+	j.file = f
+	j.day = day
+	return nil
+}
+
+// Prune removes the files of the days before the given time
+func (j *JSONL) Prune(_ context.Context, before time.Time) error {
+	entries, err := os.ReadDir(j.dir)
+	if err != nil {
+		return fmt.Errorf("read jsonl sink directory: %w", err)
+	}
+
+	j.mux.Lock()
+	defer j.mux.Unlock()
+
+	// a file holds events of the whole day, keep it while any of them is within the retention
+	cutoff := before.UTC().Format(jsonlDateLayout)
+
+	var errs []error
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, jsonlPrefix) || !strings.HasSuffix(name, jsonlSuffix) {
+			continue
+		}
+
+		day := strings.TrimSuffix(strings.TrimPrefix(name, jsonlPrefix), jsonlSuffix)
+		if _, err := time.Parse(jsonlDateLayout, day); err != nil || day >= cutoff || day == j.day {
+			continue
+		}
+
+		if err := os.Remove(filepath.Join(j.dir, name)); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		log.Debugf("removed flow file %s", name)
+	}
+
+	return errors.Join(errs...)
+}
+
+func (j *JSONL) Close() error {
+	j.mux.Lock()
+	defer j.mux.Unlock()
+
+	if j.file == nil {
+		return nil
+	}
+
+	err := j.file.Close()
+	j.file = nil
+	return err
+}
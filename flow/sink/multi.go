@@ -0,0 +1,46 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Multi writes the events to all sinks
+type Multi []Sink
+
+func (m Multi) Write(ctx context.Context, event *Event) error {
+	var errs []error
+	for _, s := range m {
+		if err := s.Write(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Prune applies the retention to all sinks supporting it
+func (m Multi) Prune(ctx context.Context, before time.Time) error {
+	var errs []error
+	for _, s := range m {
+		p, ok := s.(Pruner)
+		if !ok {
+			continue
+		}
+		if err := p.Prune(ctx, before); err != nil {
+			errs = append(errs, fmt.Errorf("%T: %w", s, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m Multi) Close() error {
+	var errs []error
+	for _, s := range m {
+		if err := s.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
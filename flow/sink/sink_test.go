@@ -0,0 +1,158 @@
+package sink
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/netbirdio/netbird/flow/proto"
+)
+
+func testProtoEvent() *proto.FlowEvent {
+	eventID := uuid.New()
+	flowID := uuid.New()
+	return &proto.FlowEvent{
+		EventId:   eventID[:],
+		Timestamp: timestamppb.New(time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)),
+		PublicKey: make([]byte, 32),
+		FlowFields: &proto.FlowFields{
+			FlowId:    flowID[:],
+			Type:      proto.Type_TYPE_DROP,
+			Direction: proto.Direction_INGRESS,
+			Protocol:  1,
+			SourceIp:  []byte{100, 64, 0, 1},
+			DestIp:    []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xff, 0xff, 100, 64, 0, 2},
+			RuleId:    []byte("rule1"),
+			ConnectionInfo: &proto.FlowFields_IcmpInfo{
+				IcmpInfo: &proto.ICMPInfo{IcmpType: 8},
+			},
+		},
+	}
+}
+
+func testSinkEvent(t *testing.T, receivedAt time.Time) *Event {
+	t.Helper()
+	e, err := FromProto(testProtoEvent(), receivedAt)
+	require.NoError(t, err)
+	e.Timestamp = receivedAt
+	return e
+}
+
+func TestFromProto(t *testing.T) {
+	event := testProtoEvent()
+	e, err := FromProto(event, time.Now())
+	require.NoError(t, err)
+
+	assert.Equal(t, uuid.UUID(event.EventId).String(), e.ID)
+	assert.Equal(t, "drop", e.Type)
+	assert.Equal(t, "ingress", e.Direction)
+	assert.Equal(t, "100.64.0.1", e.SourceIP)
+	assert.Equal(t, "100.64.0.2", e.DestIP, "mapped address is unmapped")
+	assert.Equal(t, uint8(8), e.ICMPType)
+	assert.Equal(t, "rule1", e.RuleID)
+
+	invalid := []func(e *proto.FlowEvent){
+		func(e *proto.FlowEvent) { e.EventId = []byte("short") },
+		func(e *proto.FlowEvent) { e.PublicKey = []byte("short") },
+		func(e *proto.FlowEvent) { e.FlowFields = nil },
+		func(e *proto.FlowEvent) { e.FlowFields.SourceIp = nil },
+		func(e *proto.FlowEvent) { e.FlowFields.Protocol = 256 },
+	}
+	for i, modify := range invalid {
+		event := testProtoEvent()
+		modify(event)
+		_, err := FromProto(event, time.Now())
+		assert.Error(t, err, "case %d", i)
+	}
+}
+
+func TestJSONL_WriteAndPrune(t *testing.T) {
+	dir := t.TempDir()
+	s, err := New(context.Background(), "jsonl://"+dir)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, s.Close())
+	})
+
+	old := time.Date(2025, 3, 1, 23, 0, 0, 0, time.UTC)
+	current := old.Add(48 * time.Hour)
+
+	oldEvent := testSinkEvent(t, old)
+	require.NoError(t, s.Write(context.Background(), oldEvent))
+	currentEvent := testSinkEvent(t, current)
+	require.NoError(t, s.Write(context.Background(), currentEvent))
+	require.NoError(t, s.Write(context.Background(), testSinkEvent(t, current)))
+
+	assert.Equal(t, []string{oldEvent.ID}, readJSONL(t, filepath.Join(dir, "flows-2025-03-01.jsonl")))
+	assert.Len(t, readJSONL(t, filepath.Join(dir, "flows-2025-03-03.jsonl")), 2)
+
+	require.NoError(t, s.(Pruner).Prune(context.Background(), current.Add(-time.Hour)))
+
+	_, err = os.Stat(filepath.Join(dir, "flows-2025-03-01.jsonl"))
+	assert.True(t, os.IsNotExist(err), "old file should be removed")
+	assert.Len(t, readJSONL(t, filepath.Join(dir, "flows-2025-03-03.jsonl")), 2)
+}
+
+func readJSONL(t *testing.T, path string) []string {
+	t.Helper()
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var ids []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e Event
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &e))
+		ids = append(ids, e.ID)
+	}
+	require.NoError(t, scanner.Err())
+	return ids
+}
+
+func TestSQL_WriteAndPrune(t *testing.T) {
+	s, err := New(context.Background(), "sqlite://"+filepath.Join(t.TempDir(), "flows.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, s.Close())
+	})
+	sqlSink := s.(*SQL)
+
+	now := time.Now().UTC()
+	oldEvent := testSinkEvent(t, now.Add(-48*time.Hour))
+	newEvent := testSinkEvent(t, now)
+
+	require.NoError(t, s.Write(context.Background(), oldEvent))
+	require.NoError(t, s.Write(context.Background(), newEvent))
+	// resent events are ignored
+	require.NoError(t, s.Write(context.Background(), newEvent))
+
+	var count int64
+	require.NoError(t, sqlSink.db.Model(&Event{}).Count(&count).Error)
+	assert.Equal(t, int64(2), count)
+
+	require.NoError(t, sqlSink.Prune(context.Background(), now.Add(-24*time.Hour)))
+
+	var events []Event
+	require.NoError(t, sqlSink.db.Find(&events).Error)
+	require.Len(t, events, 1)
+	assert.Equal(t, newEvent.ID, events[0].ID)
+	assert.Equal(t, "100.64.0.2", events[0].DestIP)
+}
+
+func TestNew_InvalidURL(t *testing.T) {
+	for _, u := range []string{"/var/lib/flows", "ftp://host/path", "otlp://"} {
+		_, err := New(context.Background(), u)
+		assert.Error(t, err, u)
+	}
+}
@@ -0,0 +1,99 @@
+package sink
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+)
+
+// Engine is the database engine of the SQL sink
+type Engine string
+
+const (
+	EngineSQLite   Engine = "sqlite"
+	EnginePostgres Engine = "postgres"
+	EngineMySQL    Engine = "mysql"
+)
+
+// SQL stores the events in the flow_events table of a database
+type SQL struct {
+	db *gorm.DB
+}
+
+// NewSQL opens the database and migrates the flow_events table
+func NewSQL(engine Engine, dsn string) (*SQL, error) {
+	if dsn == "" {
+		return nil, fmt.Errorf("%s sink dsn not set", engine)
+	}
+
+	var dialector gorm.Dialector
+	switch engine {
+	case EngineSQLite:
+		dialector = sqlite.Open(dsn)
+	case EnginePostgres:
+		dialector = postgres.Open(dsn)
+	case EngineMySQL:
+		if !strings.Contains(dsn, "parseTime=") {
+			sep := "?"
+			if strings.Contains(dsn, "?") {
+				sep = "&"
+			}
+			dsn += sep + "parseTime=True"
+		}
+		dialector = mysql.Open(dsn)
+	default:
+		return nil, fmt.Errorf("unsupported sql engine: %s", engine)
+	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		return nil, fmt.Errorf("open %s database: %w", engine, err)
+	}
+
+	if engine == EngineSQLite {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return nil, err
+		}
+		sqlDB.SetMaxOpenConns(1)
+	}
+
+	if err := db.AutoMigrate(&Event{}); err != nil {
+		return nil, fmt.Errorf("migrate flow events table: %w", err)
+	}
+
+	return &SQL{db: db}, nil
+}
+
+func (s *SQL) Write(ctx context.Context, event *Event) error {
+	// events are sent again if the acknowledgement got lost
+	err := s.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(event).Error
+	if err != nil {
+		return fmt.Errorf("insert event: %w", err)
+	}
+	return nil
+}
+
+// Prune deletes the events older than before
+func (s *SQL) Prune(ctx context.Context, before time.Time) error {
+	if err := s.db.WithContext(ctx).Where("timestamp < ?", before).Delete(&Event{}).Error; err != nil {
+		return fmt.Errorf("delete events: %w", err)
+	}
+	return nil
+}
+
+func (s *SQL) Close() error {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return fmt.Errorf("get sql db: %w", err)
+	}
+	return sqlDB.Close()
+}
@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/netbirdio/netbird/management/internals/modules/accountconfig"
+	"github.com/netbirdio/netbird/shared/management/client/rest"
+	"github.com/netbirdio/netbird/shared/management/http/api"
+)
+
+var (
+	accountConfigManagementURL string
+	accountConfigToken         string
+	accountConfigFormat        string
+	accountConfigFile          string
+
+	accountConfigCmd = &cobra.Command{
+		Use:   "account-config",
+		Short: "Export and apply the declarative account configuration through the management API",
+		Long: "Export and apply the groups, policies, routes, nameserver groups, networks and DNS zones of an account " +
+			"as a versioned YAML or JSON document. The API token can also be set with the NB_API_TOKEN environment variable.",
+		SilenceUsage: true,
+	}
+
+	accountConfigExportCmd = &cobra.Command{
+		Use:   "export [--format yaml|json] [-f file]",
+		Short: "Export the account configuration to a document",
+		RunE:  exportAccountConfig,
+	}
+
+	accountConfigPlanCmd = &cobra.Command{
+		Use:   "plan -f file",
+		Short: "Show the changes required to make the account configuration match a document",
+		RunE:  planAccountConfig,
+	}
+
+	accountConfigApplyCmd = &cobra.Command{
+		Use:   "apply -f file",
+		Short: "Make the account configuration match a document",
+		RunE:  applyAccountConfig,
+	}
+)
+
+func init() {
+	accountConfigCmd.PersistentFlags().StringVar(&accountConfigManagementURL, "management-url", "http://localhost:80", "management service URL, http is only allowed to localhost")
+	accountConfigCmd.PersistentFlags().StringVar(&accountConfigToken, "token", "", "personal access token used to authenticate to the management API")
+
+	accountConfigExportCmd.Flags().StringVar(&accountConfigFormat, "format", accountconfig.FormatYAML, "document format, yaml or json")
+	accountConfigExportCmd.Flags().StringVarP(&accountConfigFile, "file", "f", "", "file to write the document to, defaults to stdout")
+	accountConfigPlanCmd.Flags().StringVarP(&accountConfigFile, "file", "f", "", "YAML or JSON document file")
+	accountConfigApplyCmd.Flags().StringVarP(&accountConfigFile, "file", "f", "", "YAML or JSON document file")
+	accountConfigPlanCmd.MarkFlagRequired("file")  //nolint
+	accountConfigApplyCmd.MarkFlagRequired("file") //nolint
+
+	accountConfigCmd.AddCommand(accountConfigExportCmd, accountConfigPlanCmd, accountConfigApplyCmd)
+	rootCmd.AddCommand(accountConfigCmd)
+}
+
+func exportAccountConfig(cmd *cobra.Command, _ []string) error {
+	client, err := accountConfigClient()
+	if err != nil {
+		return err
+	}
+
+	exported, err := client.AccountConfig.Export(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("export account configuration: %w", err)
+	}
+
+	document, err := convertDocument[accountconfig.Document](exported)
+	if err != nil {
+		return err
+	}
+
+	data, err := accountconfig.Marshal(document, accountConfigFormat)
+	if err != nil {
+		return err
+	}
+
+	if accountConfigFile == "" {
+		_, err = cmd.OutOrStdout().Write(data)
+		return err
+	}
+
+	return os.WriteFile(accountConfigFile, data, 0o600)
+}
+
+func planAccountConfig(cmd *cobra.Command, _ []string) error {
+	client, err := accountConfigClient()
+	if err != nil {
+		return err
+	}
+
+	document, err := readAccountConfigDocument(accountConfigFile)
+	if err != nil {
+		return err
+	}
+
+	changes, err := client.AccountConfig.Plan(cmd.Context(), *document)
+	if err != nil {
+		return fmt.Errorf("plan account configuration: %w", err)
+	}
+
+	created, updated, deleted := printAccountConfigChanges(cmd.OutOrStdout(), changes.Changes)
+	_, err = fmt.Fprintf(cmd.OutOrStdout(), "Plan: %d to create, %d to update, %d to delete.\n", created, updated, deleted)
+	return err
+}
+
+func applyAccountConfig(cmd *cobra.Command, _ []string) error {
+	client, err := accountConfigClient()
+	if err != nil {
+		return err
+	}
+
+	document, err := readAccountConfigDocument(accountConfigFile)
+	if err != nil {
+		return err
+	}
+
+	changes, err := client.AccountConfig.Apply(cmd.Context(), *document)
+	if err != nil {
+		return fmt.Errorf("apply account configuration: %w", err)
+	}
+
+	created, updated, deleted := printAccountConfigChanges(cmd.OutOrStdout(), changes.Changes)
+	_, err = fmt.Fprintf(cmd.OutOrStdout(), "Applied: %d created, %d updated, %d deleted.\n", created, updated, deleted)
+	return err
+}
+
+func accountConfigClient() (*rest.Client, error) {
+	token := accountConfigToken
+	if token == "" {
+		token = os.Getenv("NB_API_TOKEN")
+	}
+	if token == "" {
+		return nil, fmt.Errorf("an API token is required, set it with --token or NB_API_TOKEN")
+	}
+	if err := validateManagementURL(accountConfigManagementURL); err != nil {
+		return nil, err
+	}
+	return rest.New(strings.TrimSuffix(accountConfigManagementURL, "/"), token), nil
+}
+
+// validateManagementURL refuses to send the API token in plaintext, http is only allowed to a loopback address
+func validateManagementURL(managementURL string) error {
+	parsed, err := url.Parse(managementURL)
+	if err != nil || parsed.Hostname() == "" {
+		return fmt.Errorf("invalid management URL %q", managementURL)
+	}
+
+	switch parsed.Scheme {
+	case "https":
+		return nil
+	case "http":
+		host := parsed.Hostname()
+		if ip := net.ParseIP(host); strings.EqualFold(host, "localhost") || (ip != nil && ip.IsLoopback()) {
+			return nil
+		}
+		return fmt.Errorf("the management URL has to use https, the API token would be sent in plaintext to %s", host)
+	default:
+		return fmt.Errorf("unsupported management URL scheme %q", parsed.Scheme)
+	}
+}
+
+// readAccountConfigDocument reads a document file, parsed as JSON when it has the .json extension and as YAML otherwise
+func readAccountConfigDocument(path string) (*api.AccountConfigDocument, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read document: %w", err)
+	}
+
+	format := accountconfig.FormatYAML
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		format = accountconfig.FormatJSON
+	}
+
+	document, err := accountconfig.Unmarshal(data, format)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := document.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid document: %w", err)
+	}
+
+	return convertDocument[api.AccountConfigDocument](document)
+}
+
+// convertDocument converts between the API and the document types, which share the JSON representation
+func convertDocument[T any](from any) (*T, error) {
+	data, err := json.Marshal(from)
+	if err != nil {
+		return nil, fmt.Errorf("encode document: %w", err)
+	}
+
+	var to T
+	if err := json.Unmarshal(data, &to); err != nil {
+		return nil, fmt.Errorf("decode document: %w", err)
+	}
+	return &to, nil
+}
+
+// printAccountConfigChanges prints a line per change and returns the number of creates, updates and deletes
+func printAccountConfigChanges(w io.Writer, changes []api.AccountConfigChange) (created, updated, deleted int) {
+	for _, change := range changes {
+		sign := "~"
+		switch change.Action {
+		case api.AccountConfigChangeActionCreate:
+			sign = "+"
+			created++
+		case api.AccountConfigChangeActionUpdate:
+			updated++
+		case api.AccountConfigChangeActionDelete:
+			sign = "-"
+			deleted++
+		}
+
+		name := change.Name
+		if change.Parent != nil {
+			name = *change.Parent + "/" + name
+		}
+		_, _ = fmt.Fprintf(w, "%s %s %s %q\n", sign, change.Action, change.Kind, name)
+	}
+	return created, updated, deleted
+}
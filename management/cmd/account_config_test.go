@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateManagementURL(t *testing.T) {
+	tests := []struct {
+		url   string
+		valid bool
+	}{
+		{url: "https://api.netbird.io", valid: true},
+		{url: "https://netbird.example.com:33073/", valid: true},
+		{url: "http://localhost:80", valid: true},
+		{url: "http://127.0.0.1:8080", valid: true},
+		{url: "http://[::1]", valid: true},
+		{url: "http://netbird.example.com", valid: false},
+		{url: "http://10.0.0.1", valid: false},
+		{url: "ftp://netbird.example.com", valid: false},
+		{url: "netbird.example.com", valid: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.url, func(t *testing.T) {
+			err := validateManagementURL(tc.url)
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package accessrequests
+
+import (
+	"context"
+)
+
+type Manager interface {
+	GetAllRequests(ctx context.Context, accountID, userID string) ([]*AccessRequest, error)
+	GetRequest(ctx context.Context, accountID, userID, requestID string) (*AccessRequest, error)
+	CreateRequest(ctx context.Context, accountID, userID string, request *AccessRequest) (*AccessRequest, error)
+	ApproveRequest(ctx context.Context, accountID, userID, requestID string) (*AccessRequest, error)
+	DenyRequest(ctx context.Context, accountID, userID, requestID string) (*AccessRequest, error)
+	// LoadActiveGrants schedules the expiration of the access granted by approved requests
+	LoadActiveGrants(ctx context.Context)
+	Stop()
+}
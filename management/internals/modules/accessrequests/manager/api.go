@@ -0,0 +1,147 @@
+package manager
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/netbirdio/netbird/management/internals/modules/accessrequests"
+	nbcontext "github.com/netbirdio/netbird/management/server/context"
+	"github.com/netbirdio/netbird/shared/management/http/api"
+	"github.com/netbirdio/netbird/shared/management/http/util"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+type handler struct {
+	manager accessrequests.Manager
+}
+
+func RegisterEndpoints(router *mux.Router, manager accessrequests.Manager) {
+	h := &handler{
+		manager: manager,
+	}
+
+	router.HandleFunc("/access-requests", h.getAllRequests).Methods("GET", "OPTIONS")
+	router.HandleFunc("/access-requests", h.createRequest).Methods("POST", "OPTIONS")
+	router.HandleFunc("/access-requests/{requestId}", h.getRequest).Methods("GET", "OPTIONS")
+	router.HandleFunc("/access-requests/{requestId}/approve", h.approveRequest).Methods("POST", "OPTIONS")
+	router.HandleFunc("/access-requests/{requestId}/deny", h.denyRequest).Methods("POST", "OPTIONS")
+}
+
+func (h *handler) getAllRequests(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	requests, err := h.manager.GetAllRequests(r.Context(), userAuth.AccountId, userAuth.UserId)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	apiRequests := make([]*api.AccessRequest, 0, len(requests))
+	for _, request := range requests {
+		apiRequests = append(apiRequests, request.ToAPIResponse())
+	}
+
+	util.WriteJSONObject(r.Context(), w, apiRequests)
+}
+
+func (h *handler) createRequest(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	var req api.PostApiAccessRequestsJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.WriteErrorResponse("couldn't parse JSON request", http.StatusBadRequest, w)
+		return
+	}
+
+	request := new(accessrequests.AccessRequest)
+	request.FromAPIRequest(&req)
+
+	if err = request.Validate(); err != nil {
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "%s", err.Error()), w)
+		return
+	}
+
+	createdRequest, err := h.manager.CreateRequest(r.Context(), userAuth.AccountId, userAuth.UserId, request)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, createdRequest.ToAPIResponse())
+}
+
+func (h *handler) getRequest(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	requestID := mux.Vars(r)["requestId"]
+	if requestID == "" {
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "access request ID is required"), w)
+		return
+	}
+
+	request, err := h.manager.GetRequest(r.Context(), userAuth.AccountId, userAuth.UserId, requestID)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, request.ToAPIResponse())
+}
+
+func (h *handler) approveRequest(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	requestID := mux.Vars(r)["requestId"]
+	if requestID == "" {
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "access request ID is required"), w)
+		return
+	}
+
+	request, err := h.manager.ApproveRequest(r.Context(), userAuth.AccountId, userAuth.UserId, requestID)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, request.ToAPIResponse())
+}
+
+func (h *handler) denyRequest(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	requestID := mux.Vars(r)["requestId"]
+	if requestID == "" {
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "access request ID is required"), w)
+		return
+	}
+
+	request, err := h.manager.DenyRequest(r.Context(), userAuth.AccountId, userAuth.UserId, requestID)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, request.ToAPIResponse())
+}
@@ -0,0 +1,499 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/management/internals/modules/accessrequests"
+	"github.com/netbirdio/netbird/management/server/account"
+	"github.com/netbirdio/netbird/management/server/activity"
+	"github.com/netbirdio/netbird/management/server/permissions"
+	"github.com/netbirdio/netbird/management/server/permissions/modules"
+	"github.com/netbirdio/netbird/management/server/permissions/operations"
+	"github.com/netbirdio/netbird/management/server/store"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+// expirationRetryInterval is the delay before retrying to revoke expired grants after a failure
+const expirationRetryInterval = time.Minute
+
+var (
+	timeNow = time.Now
+)
+
+type managerImpl struct {
+	store              store.Store
+	accountManager     account.Manager
+	permissionsManager permissions.Manager
+
+	timerLock sync.Mutex
+	timer     *time.Timer
+}
+
+func NewManager(store store.Store, accountManager account.Manager, permissionsManager permissions.Manager) accessrequests.Manager {
+	m := &managerImpl{
+		store:              store,
+		accountManager:     accountManager,
+		permissionsManager: permissionsManager,
+	}
+	accountManager.AddAccountDeletedListener(m.onAccountDeleted)
+	return m
+}
+
+func (m *managerImpl) GetAllRequests(ctx context.Context, accountID, userID string) ([]*accessrequests.AccessRequest, error) {
+	allowed, err := m.permissionsManager.ValidateUserPermissions(ctx, accountID, userID, modules.AccessRequests, operations.Read)
+	if err != nil {
+		return nil, status.NewPermissionValidationError(err)
+	}
+
+	requests, err := m.store.GetAccountAccessRequests(ctx, store.LockingStrengthNone, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if allowed {
+		return requests, nil
+	}
+
+	// users without access request permissions can only see their own requests
+	userRequests := make([]*accessrequests.AccessRequest, 0)
+	for _, request := range requests {
+		if request.RequesterID == userID {
+			userRequests = append(userRequests, request)
+		}
+	}
+
+	return userRequests, nil
+}
+
+func (m *managerImpl) GetRequest(ctx context.Context, accountID, userID, requestID string) (*accessrequests.AccessRequest, error) {
+	allowed, err := m.permissionsManager.ValidateUserPermissions(ctx, accountID, userID, modules.AccessRequests, operations.Read)
+	if err != nil {
+		return nil, status.NewPermissionValidationError(err)
+	}
+
+	request, err := m.store.GetAccessRequestByID(ctx, store.LockingStrengthNone, accountID, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !allowed && request.RequesterID != userID {
+		return nil, status.NewPermissionDeniedError()
+	}
+
+	return request, nil
+}
+
+// CreateRequest stores a pending access request. Any user of the account can request access for themselves.
+func (m *managerImpl) CreateRequest(ctx context.Context, accountID, userID string, request *accessrequests.AccessRequest) (*accessrequests.AccessRequest, error) {
+	user, err := m.store.GetUserByUserID(ctx, store.LockingStrengthNone, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.AccountID != accountID {
+		return nil, status.NewUserNotPartOfAccountError()
+	}
+	if user.IsServiceUser {
+		return nil, status.Errorf(status.PermissionDenied, "service users can't request access")
+	}
+
+	request = accessrequests.NewAccessRequest(accountID, userID, request.TargetType, request.TargetID, request.Reason, request.Duration)
+	err = m.store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+		if err = validateRequestTarget(ctx, transaction, accountID, request); err != nil {
+			return err
+		}
+
+		requests, err := transaction.GetAccountAccessRequests(ctx, store.LockingStrengthNone, accountID)
+		if err != nil {
+			return fmt.Errorf("failed to get access requests: %w", err)
+		}
+
+		for _, existing := range requests {
+			if existing.Status == accessrequests.StatusPending && existing.RequesterID == userID &&
+				existing.TargetType == request.TargetType && existing.TargetID == request.TargetID {
+				return status.Errorf(status.AlreadyExists, "a pending access request for %s %s already exists", request.TargetType, request.TargetID)
+			}
+		}
+
+		if err = transaction.CreateAccessRequest(ctx, request); err != nil {
+			return fmt.Errorf("failed to create access request: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	m.accountManager.StoreEvent(ctx, userID, request.ID, accountID, activity.AccessRequestCreated, request.EventMeta())
+
+	return request, nil
+}
+
+// ApproveRequest grants the requested access until the requested duration passes
+func (m *managerImpl) ApproveRequest(ctx context.Context, accountID, userID, requestID string) (*accessrequests.AccessRequest, error) {
+	request, err := m.reviewRequest(ctx, accountID, userID, requestID, func(transaction store.Store, request *accessrequests.AccessRequest) error {
+		now := timeNow().UTC()
+		expiresAt := now.Add(request.Duration)
+
+		request.Status = accessrequests.StatusApproved
+		request.ReviewerID = userID
+		request.ReviewedAt = &now
+		request.ExpiresAt = &expiresAt
+
+		if err := grantAccess(ctx, transaction, request); err != nil {
+			return err
+		}
+
+		return transaction.IncrementNetworkSerial(ctx, accountID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	m.accountManager.StoreEvent(ctx, userID, request.ID, accountID, activity.AccessRequestApproved, request.EventMeta())
+	m.accountManager.UpdateAccountPeers(ctx, accountID)
+	m.scheduleExpiration(ctx)
+
+	return request, nil
+}
+
+func (m *managerImpl) DenyRequest(ctx context.Context, accountID, userID, requestID string) (*accessrequests.AccessRequest, error) {
+	request, err := m.reviewRequest(ctx, accountID, userID, requestID, func(transaction store.Store, request *accessrequests.AccessRequest) error {
+		now := timeNow().UTC()
+
+		request.Status = accessrequests.StatusDenied
+		request.ReviewerID = userID
+		request.ReviewedAt = &now
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	m.accountManager.StoreEvent(ctx, userID, request.ID, accountID, activity.AccessRequestDenied, request.EventMeta())
+
+	return request, nil
+}
+
+// reviewRequest validates that the user may review the pending request, applies the review and saves the request
+func (m *managerImpl) reviewRequest(ctx context.Context, accountID, userID, requestID string, review func(transaction store.Store, request *accessrequests.AccessRequest) error) (*accessrequests.AccessRequest, error) {
+	ok, err := m.permissionsManager.ValidateUserPermissions(ctx, accountID, userID, modules.AccessRequests, operations.Update)
+	if err != nil {
+		return nil, status.NewPermissionValidationError(err)
+	}
+	if !ok {
+		return nil, status.NewPermissionDeniedError()
+	}
+
+	var request *accessrequests.AccessRequest
+	err = m.store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+		request, err = transaction.GetAccessRequestByID(ctx, store.LockingStrengthUpdate, accountID, requestID)
+		if err != nil {
+			return fmt.Errorf("failed to get access request: %w", err)
+		}
+
+		if request.Status != accessrequests.StatusPending {
+			return status.Errorf(status.PreconditionFailed, "access request is already %s", request.Status)
+		}
+		if request.RequesterID == userID {
+			return status.Errorf(status.PermissionDenied, "users can't review their own access requests")
+		}
+
+		if err = review(transaction, request); err != nil {
+			return err
+		}
+
+		if err = transaction.SaveAccessRequest(ctx, request); err != nil {
+			return fmt.Errorf("failed to save access request: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+// LoadActiveGrants revokes grants that expired while the management server was down and schedules the next expiration
+func (m *managerImpl) LoadActiveGrants(ctx context.Context) {
+	m.expireGrants(ctx)
+}
+
+// Stop the expiration timer
+func (m *managerImpl) Stop() {
+	m.timerLock.Lock()
+	defer m.timerLock.Unlock()
+
+	if m.timer != nil {
+		m.timer.Stop()
+		m.timer = nil
+	}
+}
+
+// onAccountDeleted reschedules the expiration timer, the requests of the account are deleted with it and the timer
+// could be set to the expiry of one of them
+func (m *managerImpl) onAccountDeleted(ctx context.Context, _ string) {
+	m.scheduleExpiration(ctx)
+}
+
+// scheduleExpiration resets the expiration timer to the earliest expiry of all approved requests
+func (m *managerImpl) scheduleExpiration(ctx context.Context) {
+	approved, err := m.store.GetAccessRequestsByStatus(ctx, store.LockingStrengthNone, accessrequests.StatusApproved)
+	if err != nil {
+		log.WithContext(ctx).Errorf("failed to get approved access requests: %v", err)
+		m.resetTimer(ctx, expirationRetryInterval)
+		return
+	}
+
+	var next *time.Time
+	for _, request := range approved {
+		if request.ExpiresAt != nil && (next == nil || request.ExpiresAt.Before(*next)) {
+			next = request.ExpiresAt
+		}
+	}
+
+	if next == nil {
+		m.Stop()
+		return
+	}
+
+	delay := next.Sub(timeNow())
+	if delay < 0 {
+		delay = 0
+	}
+	m.resetTimer(ctx, delay)
+}
+
+func (m *managerImpl) resetTimer(ctx context.Context, delay time.Duration) {
+	m.timerLock.Lock()
+	defer m.timerLock.Unlock()
+
+	if m.timer != nil {
+		m.timer.Stop()
+	}
+	// the timer outlives the API request that scheduled it
+	ctx = context.WithoutCancel(ctx)
+	m.timer = time.AfterFunc(delay, func() {
+		m.expireGrants(ctx)
+	})
+}
+
+// expireGrants revokes the access granted by all expired requests and schedules the next expiration
+func (m *managerImpl) expireGrants(ctx context.Context) {
+	approved, err := m.store.GetAccessRequestsByStatus(ctx, store.LockingStrengthNone, accessrequests.StatusApproved)
+	if err != nil {
+		log.WithContext(ctx).Errorf("failed to get approved access requests: %v", err)
+		m.resetTimer(ctx, expirationRetryInterval)
+		return
+	}
+
+	now := timeNow()
+	var failed bool
+	for _, request := range approved {
+		if !request.IsExpiredAt(now) {
+			continue
+		}
+
+		if err = m.expireRequest(ctx, request.AccountID, request.ID); err != nil {
+			log.WithContext(ctx).Errorf("failed to expire access request %s of account %s: %v", request.ID, request.AccountID, err)
+			failed = true
+		}
+	}
+
+	// the failed requests are still due, retry them later instead of rescheduling right away
+	if failed {
+		m.resetTimer(ctx, expirationRetryInterval)
+		return
+	}
+
+	m.scheduleExpiration(ctx)
+}
+
+func (m *managerImpl) expireRequest(ctx context.Context, accountID, requestID string) error {
+	var request *accessrequests.AccessRequest
+	err := m.store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+		var err error
+		request, err = transaction.GetAccessRequestByID(ctx, store.LockingStrengthUpdate, accountID, requestID)
+		if err != nil {
+			return fmt.Errorf("failed to get access request: %w", err)
+		}
+
+		if !request.IsExpiredAt(timeNow()) {
+			return nil
+		}
+
+		if err = revokeAccess(ctx, transaction, request); err != nil {
+			return err
+		}
+
+		request.Status = accessrequests.StatusExpired
+		if err = transaction.SaveAccessRequest(ctx, request); err != nil {
+			return fmt.Errorf("failed to save access request: %w", err)
+		}
+
+		return transaction.IncrementNetworkSerial(ctx, accountID)
+	})
+	if err != nil {
+		return err
+	}
+
+	if request.Status != accessrequests.StatusExpired {
+		return nil
+	}
+
+	m.accountManager.StoreEvent(ctx, activity.SystemInitiator, request.ID, accountID, activity.AccessRequestExpired, request.EventMeta())
+	m.accountManager.UpdateAccountPeers(ctx, accountID)
+
+	return nil
+}
+
+func validateRequestTarget(ctx context.Context, transaction store.Store, accountID string, request *accessrequests.AccessRequest) error {
+	switch request.TargetType {
+	case accessrequests.TargetTypeGroup:
+		group, err := transaction.GetGroupByID(ctx, store.LockingStrengthNone, accountID, request.TargetID)
+		if err != nil {
+			return err
+		}
+		if group.IsGroupAll() {
+			return status.Errorf(status.InvalidArgument, "access to the All group can't be requested")
+		}
+	case accessrequests.TargetTypePolicy:
+		if _, err := transaction.GetPolicyByID(ctx, store.LockingStrengthNone, accountID, request.TargetID); err != nil {
+			return err
+		}
+	default:
+		return status.Errorf(status.InvalidArgument, "invalid target type %s", request.TargetType)
+	}
+
+	return nil
+}
+
+// grantAccess adds the requester peers to the target group or enables the target policy.
+// Only changes made by the grant are recorded, so that revoking it keeps pre-existing access.
+func grantAccess(ctx context.Context, transaction store.Store, request *accessrequests.AccessRequest) error {
+	if err := validateRequestTarget(ctx, transaction, request.AccountID, request); err != nil {
+		return err
+	}
+
+	switch request.TargetType {
+	case accessrequests.TargetTypeGroup:
+		group, err := transaction.GetGroupByID(ctx, store.LockingStrengthUpdate, request.AccountID, request.TargetID)
+		if err != nil {
+			return err
+		}
+
+		peers, err := transaction.GetUserPeers(ctx, store.LockingStrengthNone, request.AccountID, request.RequesterID)
+		if err != nil {
+			return fmt.Errorf("failed to get requester peers: %w", err)
+		}
+
+		request.GrantedPeers = make([]string, 0, len(peers))
+		for _, peer := range peers {
+			if slices.Contains(group.Peers, peer.ID) {
+				continue
+			}
+			if err = transaction.AddPeerToGroup(ctx, request.AccountID, peer.ID, group.ID); err != nil {
+				return fmt.Errorf("failed to add peer %s to group: %w", peer.ID, err)
+			}
+			request.GrantedPeers = append(request.GrantedPeers, peer.ID)
+		}
+	case accessrequests.TargetTypePolicy:
+		policy, err := transaction.GetPolicyByID(ctx, store.LockingStrengthUpdate, request.AccountID, request.TargetID)
+		if err != nil {
+			return err
+		}
+
+		if !policy.Enabled {
+			policy.Enabled = true
+			if err = transaction.SavePolicy(ctx, policy); err != nil {
+				return fmt.Errorf("failed to enable policy: %w", err)
+			}
+			request.PolicyEnabled = true
+		}
+	}
+
+	return nil
+}
+
+// revokeAccess reverts the changes made by grantAccess unless another active grant still requires them
+func revokeAccess(ctx context.Context, transaction store.Store, request *accessrequests.AccessRequest) error {
+	requests, err := transaction.GetAccountAccessRequests(ctx, store.LockingStrengthNone, request.AccountID)
+	if err != nil {
+		return fmt.Errorf("failed to get access requests: %w", err)
+	}
+
+	var activeGrants []*accessrequests.AccessRequest
+	for _, other := range requests {
+		if other.ID != request.ID && other.Status == accessrequests.StatusApproved &&
+			other.TargetType == request.TargetType && other.TargetID == request.TargetID && !other.IsExpiredAt(timeNow()) {
+			activeGrants = append(activeGrants, other)
+		}
+	}
+
+	switch request.TargetType {
+	case accessrequests.TargetTypeGroup:
+		// overlapping grants of the same requester did not record the peers that were already in the group,
+		// hand the membership over to one of them so it is removed when that grant expires
+		heirIdx := slices.IndexFunc(activeGrants, func(other *accessrequests.AccessRequest) bool {
+			return other.RequesterID == request.RequesterID
+		})
+
+		var handedOver bool
+		for _, peerID := range request.GrantedPeers {
+			stillGranted := slices.ContainsFunc(activeGrants, func(other *accessrequests.AccessRequest) bool {
+				return slices.Contains(other.GrantedPeers, peerID)
+			})
+			if stillGranted {
+				continue
+			}
+			if heirIdx >= 0 {
+				activeGrants[heirIdx].GrantedPeers = append(activeGrants[heirIdx].GrantedPeers, peerID)
+				handedOver = true
+				continue
+			}
+			if err = transaction.RemovePeerFromGroup(ctx, peerID, request.TargetID); err != nil {
+				return fmt.Errorf("failed to remove peer %s from group: %w", peerID, err)
+			}
+		}
+
+		if handedOver {
+			if err = transaction.SaveAccessRequest(ctx, activeGrants[heirIdx]); err != nil {
+				return fmt.Errorf("failed to save access request: %w", err)
+			}
+		}
+	case accessrequests.TargetTypePolicy:
+		if !request.PolicyEnabled {
+			return nil
+		}
+
+		// hand over the policy to the next active grant so it gets disabled when that one expires
+		if len(activeGrants) > 0 {
+			activeGrants[0].PolicyEnabled = true
+			return transaction.SaveAccessRequest(ctx, activeGrants[0])
+		}
+
+		policy, err := transaction.GetPolicyByID(ctx, store.LockingStrengthUpdate, request.AccountID, request.TargetID)
+		if err != nil {
+			if sErr, ok := status.FromError(err); ok && sErr.Type() == status.NotFound {
+				return nil
+			}
+			return err
+		}
+
+		policy.Enabled = false
+		if err = transaction.SavePolicy(ctx, policy); err != nil {
+			return fmt.Errorf("failed to disable policy: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,483 @@
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/management/internals/modules/accessrequests"
+	"github.com/netbirdio/netbird/management/server/activity"
+	"github.com/netbirdio/netbird/management/server/mock_server"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/permissions"
+	"github.com/netbirdio/netbird/management/server/permissions/modules"
+	"github.com/netbirdio/netbird/management/server/permissions/operations"
+	"github.com/netbirdio/netbird/management/server/store"
+	"github.com/netbirdio/netbird/management/server/types"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+const (
+	testAccountID   = "test-account-id"
+	testAdminID     = "test-admin-id"
+	testRequesterID = "test-requester-id"
+	testPeerID      = "test-peer-id"
+	testGroupID     = "test-group-id"
+	testPolicyID    = "test-policy-id"
+)
+
+func setupTest(t *testing.T) (*managerImpl, store.Store, *mock_server.MockAccountManager, *permissions.MockManager, *gomock.Controller, func()) {
+	t.Helper()
+
+	ctx := context.Background()
+	testStore, cleanup, err := store.NewTestStoreFromSQL(ctx, "", t.TempDir())
+	require.NoError(t, err)
+
+	err = testStore.SaveAccount(ctx, &types.Account{
+		Id: testAccountID,
+		Users: map[string]*types.User{
+			testAdminID: {
+				Id:        testAdminID,
+				AccountID: testAccountID,
+				Role:      types.UserRoleAdmin,
+			},
+			testRequesterID: {
+				Id:        testRequesterID,
+				AccountID: testAccountID,
+				Role:      types.UserRoleUser,
+			},
+		},
+		Peers: map[string]*nbpeer.Peer{
+			testPeerID: {
+				ID:        testPeerID,
+				AccountID: testAccountID,
+				Key:       "test-peer-key",
+				UserID:    testRequesterID,
+				Status:    &nbpeer.PeerStatus{},
+			},
+		},
+		Groups: map[string]*types.Group{
+			testGroupID: {
+				ID:        testGroupID,
+				AccountID: testAccountID,
+				Name:      "prod-db",
+				Peers:     []string{},
+			},
+		},
+		Policies: []*types.Policy{
+			{
+				ID:        testPolicyID,
+				AccountID: testAccountID,
+				Name:      "break-glass",
+				Enabled:   false,
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	mockAccountManager := &mock_server.MockAccountManager{}
+	mockPermissionsManager := permissions.NewMockManager(ctrl)
+
+	manager := &managerImpl{
+		store:              testStore,
+		accountManager:     mockAccountManager,
+		permissionsManager: mockPermissionsManager,
+	}
+
+	return manager, testStore, mockAccountManager, mockPermissionsManager, ctrl, func() {
+		manager.Stop()
+		cleanup()
+	}
+}
+
+func TestManagerImpl_CreateRequest(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		manager, testStore, mockAccountManager, _, ctrl, cleanup := setupTest(t)
+		defer cleanup()
+		defer ctrl.Finish()
+
+		mockAccountManager.StoreEventFunc = func(ctx context.Context, initiatorID, targetID, accountID string, activityID activity.ActivityDescriber, meta map[string]any) {
+			assert.Equal(t, testRequesterID, initiatorID)
+			assert.Equal(t, activity.AccessRequestCreated, activityID)
+		}
+
+		result, err := manager.CreateRequest(ctx, testAccountID, testRequesterID, &accessrequests.AccessRequest{
+			TargetType: accessrequests.TargetTypeGroup,
+			TargetID:   testGroupID,
+			Reason:     "incident",
+			Duration:   time.Hour,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, accessrequests.StatusPending, result.Status)
+
+		stored, err := testStore.GetAccessRequestByID(ctx, store.LockingStrengthNone, testAccountID, result.ID)
+		require.NoError(t, err)
+		assert.Equal(t, testRequesterID, stored.RequesterID)
+		assert.Equal(t, "incident", stored.Reason)
+		assert.Equal(t, time.Hour, stored.Duration)
+	})
+
+	t.Run("duplicate pending request", func(t *testing.T) {
+		manager, _, _, _, ctrl, cleanup := setupTest(t)
+		defer cleanup()
+		defer ctrl.Finish()
+
+		request := &accessrequests.AccessRequest{
+			TargetType: accessrequests.TargetTypeGroup,
+			TargetID:   testGroupID,
+			Reason:     "incident",
+			Duration:   time.Hour,
+		}
+		_, err := manager.CreateRequest(ctx, testAccountID, testRequesterID, request)
+		require.NoError(t, err)
+
+		_, err = manager.CreateRequest(ctx, testAccountID, testRequesterID, request)
+		require.Error(t, err)
+		s, ok := status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, status.AlreadyExists, s.Type())
+	})
+
+	t.Run("unknown target", func(t *testing.T) {
+		manager, _, _, _, ctrl, cleanup := setupTest(t)
+		defer cleanup()
+		defer ctrl.Finish()
+
+		_, err := manager.CreateRequest(ctx, testAccountID, testRequesterID, &accessrequests.AccessRequest{
+			TargetType: accessrequests.TargetTypePolicy,
+			TargetID:   "unknown",
+			Reason:     "incident",
+			Duration:   time.Hour,
+		})
+		require.Error(t, err)
+	})
+}
+
+func TestManagerImpl_ApproveRequest(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("group membership", func(t *testing.T) {
+		manager, testStore, mockAccountManager, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+		defer cleanup()
+		defer ctrl.Finish()
+
+		request := accessrequests.NewAccessRequest(testAccountID, testRequesterID, accessrequests.TargetTypeGroup, testGroupID, "incident", time.Hour)
+		require.NoError(t, testStore.CreateAccessRequest(ctx, request))
+
+		mockPermissionsManager.EXPECT().
+			ValidateUserPermissions(ctx, testAccountID, testAdminID, modules.AccessRequests, operations.Update).
+			Return(true, nil)
+
+		var peersUpdated bool
+		mockAccountManager.UpdateAccountPeersFunc = func(ctx context.Context, accountID string) {
+			peersUpdated = true
+		}
+		mockAccountManager.StoreEventFunc = func(ctx context.Context, initiatorID, targetID, accountID string, activityID activity.ActivityDescriber, meta map[string]any) {
+			assert.Equal(t, testAdminID, initiatorID)
+			assert.Equal(t, request.ID, targetID)
+			assert.Equal(t, activity.AccessRequestApproved, activityID)
+		}
+
+		result, err := manager.ApproveRequest(ctx, testAccountID, testAdminID, request.ID)
+		require.NoError(t, err)
+		assert.Equal(t, accessrequests.StatusApproved, result.Status)
+		assert.Equal(t, testAdminID, result.ReviewerID)
+		require.NotNil(t, result.ExpiresAt)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), *result.ExpiresAt, time.Minute)
+		assert.Equal(t, []string{testPeerID}, result.GrantedPeers)
+		assert.True(t, peersUpdated)
+
+		group, err := testStore.GetGroupByID(ctx, store.LockingStrengthNone, testAccountID, testGroupID)
+		require.NoError(t, err)
+		assert.Contains(t, group.Peers, testPeerID)
+	})
+
+	t.Run("own request", func(t *testing.T) {
+		manager, testStore, _, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+		defer cleanup()
+		defer ctrl.Finish()
+
+		request := accessrequests.NewAccessRequest(testAccountID, testAdminID, accessrequests.TargetTypeGroup, testGroupID, "incident", time.Hour)
+		require.NoError(t, testStore.CreateAccessRequest(ctx, request))
+
+		mockPermissionsManager.EXPECT().
+			ValidateUserPermissions(ctx, testAccountID, testAdminID, modules.AccessRequests, operations.Update).
+			Return(true, nil)
+
+		_, err := manager.ApproveRequest(ctx, testAccountID, testAdminID, request.ID)
+		require.Error(t, err)
+		s, ok := status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, status.PermissionDenied, s.Type())
+	})
+
+	t.Run("permission denied", func(t *testing.T) {
+		manager, testStore, _, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+		defer cleanup()
+		defer ctrl.Finish()
+
+		request := accessrequests.NewAccessRequest(testAccountID, testRequesterID, accessrequests.TargetTypeGroup, testGroupID, "incident", time.Hour)
+		require.NoError(t, testStore.CreateAccessRequest(ctx, request))
+
+		mockPermissionsManager.EXPECT().
+			ValidateUserPermissions(ctx, testAccountID, testRequesterID, modules.AccessRequests, operations.Update).
+			Return(false, nil)
+
+		_, err := manager.ApproveRequest(ctx, testAccountID, testRequesterID, request.ID)
+		require.Error(t, err)
+		s, ok := status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, status.PermissionDenied, s.Type())
+	})
+}
+
+func TestManagerImpl_DenyRequest(t *testing.T) {
+	ctx := context.Background()
+
+	manager, testStore, mockAccountManager, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+	defer cleanup()
+	defer ctrl.Finish()
+
+	request := accessrequests.NewAccessRequest(testAccountID, testRequesterID, accessrequests.TargetTypePolicy, testPolicyID, "incident", time.Hour)
+	require.NoError(t, testStore.CreateAccessRequest(ctx, request))
+
+	mockPermissionsManager.EXPECT().
+		ValidateUserPermissions(ctx, testAccountID, testAdminID, modules.AccessRequests, operations.Update).
+		Return(true, nil).
+		Times(2)
+
+	mockAccountManager.StoreEventFunc = func(ctx context.Context, initiatorID, targetID, accountID string, activityID activity.ActivityDescriber, meta map[string]any) {
+		assert.Equal(t, activity.AccessRequestDenied, activityID)
+	}
+
+	result, err := manager.DenyRequest(ctx, testAccountID, testAdminID, request.ID)
+	require.NoError(t, err)
+	assert.Equal(t, accessrequests.StatusDenied, result.Status)
+	assert.Nil(t, result.ExpiresAt)
+
+	policy, err := testStore.GetPolicyByID(ctx, store.LockingStrengthNone, testAccountID, testPolicyID)
+	require.NoError(t, err)
+	assert.False(t, policy.Enabled)
+
+	_, err = manager.ApproveRequest(ctx, testAccountID, testAdminID, request.ID)
+	require.Error(t, err)
+	s, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, status.PreconditionFailed, s.Type())
+}
+
+func TestManagerImpl_GetAllRequests(t *testing.T) {
+	ctx := context.Background()
+
+	manager, testStore, _, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+	defer cleanup()
+	defer ctrl.Finish()
+
+	require.NoError(t, testStore.CreateAccessRequest(ctx, accessrequests.NewAccessRequest(testAccountID, testRequesterID, accessrequests.TargetTypeGroup, testGroupID, "incident", time.Hour)))
+	require.NoError(t, testStore.CreateAccessRequest(ctx, accessrequests.NewAccessRequest(testAccountID, testAdminID, accessrequests.TargetTypePolicy, testPolicyID, "maintenance", time.Hour)))
+
+	mockPermissionsManager.EXPECT().
+		ValidateUserPermissions(ctx, testAccountID, testAdminID, modules.AccessRequests, operations.Read).
+		Return(true, nil)
+	mockPermissionsManager.EXPECT().
+		ValidateUserPermissions(ctx, testAccountID, testRequesterID, modules.AccessRequests, operations.Read).
+		Return(false, nil)
+
+	all, err := manager.GetAllRequests(ctx, testAccountID, testAdminID)
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+
+	own, err := manager.GetAllRequests(ctx, testAccountID, testRequesterID)
+	require.NoError(t, err)
+	require.Len(t, own, 1)
+	assert.Equal(t, testRequesterID, own[0].RequesterID)
+}
+
+func TestManagerImpl_ExpireGrants(t *testing.T) {
+	ctx := context.Background()
+
+	manager, testStore, mockAccountManager, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+	defer cleanup()
+	defer ctrl.Finish()
+
+	groupRequest := accessrequests.NewAccessRequest(testAccountID, testRequesterID, accessrequests.TargetTypeGroup, testGroupID, "incident", time.Hour)
+	require.NoError(t, testStore.CreateAccessRequest(ctx, groupRequest))
+	policyRequest := accessrequests.NewAccessRequest(testAccountID, testRequesterID, accessrequests.TargetTypePolicy, testPolicyID, "incident", time.Hour)
+	require.NoError(t, testStore.CreateAccessRequest(ctx, policyRequest))
+
+	mockPermissionsManager.EXPECT().
+		ValidateUserPermissions(ctx, testAccountID, testAdminID, modules.AccessRequests, operations.Update).
+		Return(true, nil).
+		Times(2)
+
+	_, err := manager.ApproveRequest(ctx, testAccountID, testAdminID, groupRequest.ID)
+	require.NoError(t, err)
+	_, err = manager.ApproveRequest(ctx, testAccountID, testAdminID, policyRequest.ID)
+	require.NoError(t, err)
+
+	policy, err := testStore.GetPolicyByID(ctx, store.LockingStrengthNone, testAccountID, testPolicyID)
+	require.NoError(t, err)
+	assert.True(t, policy.Enabled)
+
+	var expired []string
+	mockAccountManager.StoreEventFunc = func(ctx context.Context, initiatorID, targetID, accountID string, activityID activity.ActivityDescriber, meta map[string]any) {
+		assert.Equal(t, activity.SystemInitiator, initiatorID)
+		assert.Equal(t, activity.AccessRequestExpired, activityID)
+		expired = append(expired, targetID)
+	}
+
+	timeNow = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	defer func() { timeNow = time.Now }()
+
+	manager.expireGrants(ctx)
+	assert.ElementsMatch(t, []string{groupRequest.ID, policyRequest.ID}, expired)
+
+	group, err := testStore.GetGroupByID(ctx, store.LockingStrengthNone, testAccountID, testGroupID)
+	require.NoError(t, err)
+	assert.NotContains(t, group.Peers, testPeerID)
+
+	policy, err = testStore.GetPolicyByID(ctx, store.LockingStrengthNone, testAccountID, testPolicyID)
+	require.NoError(t, err)
+	assert.False(t, policy.Enabled)
+
+	stored, err := testStore.GetAccessRequestByID(ctx, store.LockingStrengthNone, testAccountID, groupRequest.ID)
+	require.NoError(t, err)
+	assert.Equal(t, accessrequests.StatusExpired, stored.Status)
+}
+
+func TestManagerImpl_AccountDeleted(t *testing.T) {
+	ctx := context.Background()
+
+	manager, testStore, _, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+	defer cleanup()
+	defer ctrl.Finish()
+
+	request := accessrequests.NewAccessRequest(testAccountID, testRequesterID, accessrequests.TargetTypeGroup, testGroupID, "incident", time.Hour)
+	require.NoError(t, testStore.CreateAccessRequest(ctx, request))
+
+	mockPermissionsManager.EXPECT().
+		ValidateUserPermissions(ctx, testAccountID, testAdminID, modules.AccessRequests, operations.Update).
+		Return(true, nil)
+
+	_, err := manager.ApproveRequest(ctx, testAccountID, testAdminID, request.ID)
+	require.NoError(t, err)
+	require.NotNil(t, manager.timer, "the expiration of the approved request is scheduled")
+
+	account, err := testStore.GetAccount(ctx, testAccountID)
+	require.NoError(t, err)
+	require.NoError(t, testStore.DeleteAccount(ctx, account))
+
+	requests, err := testStore.GetAccessRequestsByStatus(ctx, store.LockingStrengthNone, accessrequests.StatusApproved)
+	require.NoError(t, err)
+	assert.Empty(t, requests, "the requests are deleted with the account")
+
+	manager.onAccountDeleted(ctx, testAccountID)
+	assert.Nil(t, manager.timer, "the expiration of the deleted requests is cancelled")
+}
+
+func TestManagerImpl_ExpireGrants_Overlapping(t *testing.T) {
+	ctx := context.Background()
+
+	manager, testStore, mockAccountManager, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+	defer cleanup()
+	defer ctrl.Finish()
+
+	first := accessrequests.NewAccessRequest(testAccountID, testRequesterID, accessrequests.TargetTypeGroup, testGroupID, "incident", time.Hour)
+	require.NoError(t, testStore.CreateAccessRequest(ctx, first))
+
+	mockPermissionsManager.EXPECT().
+		ValidateUserPermissions(ctx, testAccountID, testAdminID, modules.AccessRequests, operations.Update).
+		Return(true, nil).
+		Times(2)
+
+	_, err := manager.ApproveRequest(ctx, testAccountID, testAdminID, first.ID)
+	require.NoError(t, err)
+
+	second := accessrequests.NewAccessRequest(testAccountID, testRequesterID, accessrequests.TargetTypeGroup, testGroupID, "incident follow-up", 3*time.Hour)
+	require.NoError(t, testStore.CreateAccessRequest(ctx, second))
+	result, err := manager.ApproveRequest(ctx, testAccountID, testAdminID, second.ID)
+	require.NoError(t, err)
+	assert.Empty(t, result.GrantedPeers)
+
+	timeNow = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	defer func() { timeNow = time.Now }()
+
+	manager.expireGrants(ctx)
+
+	group, err := testStore.GetGroupByID(ctx, store.LockingStrengthNone, testAccountID, testGroupID)
+	require.NoError(t, err)
+	assert.Contains(t, group.Peers, testPeerID, "the peer must stay in the group while the second grant is active")
+
+	stored, err := testStore.GetAccessRequestByID(ctx, store.LockingStrengthNone, testAccountID, second.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{testPeerID}, stored.GrantedPeers)
+
+	timeNow = func() time.Time { return time.Now().Add(4 * time.Hour) }
+	mockAccountManager.StoreEventFunc = nil
+
+	manager.expireGrants(ctx)
+
+	group, err = testStore.GetGroupByID(ctx, store.LockingStrengthNone, testAccountID, testGroupID)
+	require.NoError(t, err)
+	assert.NotContains(t, group.Peers, testPeerID)
+}
+
+// failingStore fails to load the access request with the given ID, also within transactions
+type failingStore struct {
+	store.Store
+	failRequestID string
+}
+
+func (s *failingStore) ExecuteInTransaction(ctx context.Context, f func(transaction store.Store) error) error {
+	return s.Store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+		return f(&failingStore{Store: transaction, failRequestID: s.failRequestID})
+	})
+}
+
+func (s *failingStore) GetAccessRequestByID(ctx context.Context, lockStrength store.LockingStrength, accountID, requestID string) (*accessrequests.AccessRequest, error) {
+	if requestID == s.failRequestID {
+		return nil, status.Errorf(status.Internal, "failed to get access request from store")
+	}
+	return s.Store.GetAccessRequestByID(ctx, lockStrength, accountID, requestID)
+}
+
+func TestManagerImpl_ExpireGrants_ContinuesOnError(t *testing.T) {
+	ctx := context.Background()
+
+	manager, testStore, mockAccountManager, _, ctrl, cleanup := setupTest(t)
+	defer cleanup()
+	defer ctrl.Finish()
+
+	expiresAt := time.Now().UTC().Add(-time.Minute)
+
+	broken := accessrequests.NewAccessRequest(testAccountID, testRequesterID, accessrequests.TargetTypeGroup, testGroupID, "incident", time.Hour)
+	broken.Status = accessrequests.StatusApproved
+	broken.ExpiresAt = &expiresAt
+	require.NoError(t, testStore.CreateAccessRequest(ctx, broken))
+
+	valid := accessrequests.NewAccessRequest(testAccountID, testRequesterID, accessrequests.TargetTypePolicy, testPolicyID, "incident", time.Hour)
+	valid.Status = accessrequests.StatusApproved
+	valid.ExpiresAt = &expiresAt
+	require.NoError(t, testStore.CreateAccessRequest(ctx, valid))
+
+	manager.store = &failingStore{Store: testStore, failRequestID: broken.ID}
+
+	var expired []string
+	mockAccountManager.StoreEventFunc = func(ctx context.Context, initiatorID, targetID, accountID string, activityID activity.ActivityDescriber, meta map[string]any) {
+		expired = append(expired, targetID)
+	}
+
+	manager.expireGrants(ctx)
+	assert.Equal(t, []string{valid.ID}, expired)
+
+	stored, err := testStore.GetAccessRequestByID(ctx, store.LockingStrengthNone, testAccountID, valid.ID)
+	require.NoError(t, err)
+	assert.Equal(t, accessrequests.StatusExpired, stored.Status)
+}
@@ -0,0 +1,135 @@
+package accessrequests
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/xid"
+
+	"github.com/netbirdio/netbird/shared/management/http/api"
+)
+
+const (
+	// MinDuration is the shortest time access can be requested for
+	MinDuration = time.Minute
+	// MaxDuration is the longest time access can be requested for
+	MaxDuration = 30 * 24 * time.Hour
+)
+
+// TargetType is the kind of resource an access request grants access to
+type TargetType string
+
+const (
+	// TargetTypeGroup grants temporary membership of the requester peers in a group
+	TargetTypeGroup TargetType = "group"
+	// TargetTypePolicy grants temporary enablement of a policy
+	TargetTypePolicy TargetType = "policy"
+)
+
+// Status is the state of an access request
+type Status string
+
+const (
+	StatusPending  Status = "pending"
+	StatusApproved Status = "approved"
+	StatusDenied   Status = "denied"
+	StatusExpired  Status = "expired"
+)
+
+// AccessRequest is a request of a user for temporary access to a group or a policy.
+// Once approved, the access is granted until ExpiresAt and revoked automatically afterwards.
+type AccessRequest struct {
+	ID          string `gorm:"primaryKey"`
+	AccountID   string `gorm:"index"`
+	RequesterID string
+	TargetType  TargetType
+	TargetID    string
+	Reason      string
+	Duration    time.Duration
+	Status      Status `gorm:"index"`
+	ReviewerID  string
+	CreatedAt   time.Time
+	ReviewedAt  *time.Time
+	ExpiresAt   *time.Time
+
+	// GrantedPeers are the requester peers that were added to the target group on approval
+	GrantedPeers []string `gorm:"serializer:json"`
+	// PolicyEnabled is set when the target policy was disabled and got enabled on approval
+	PolicyEnabled bool
+}
+
+func NewAccessRequest(accountID, requesterID string, targetType TargetType, targetID, reason string, duration time.Duration) *AccessRequest {
+	return &AccessRequest{
+		ID:          xid.New().String(),
+		AccountID:   accountID,
+		RequesterID: requesterID,
+		TargetType:  targetType,
+		TargetID:    targetID,
+		Reason:      reason,
+		Duration:    duration,
+		Status:      StatusPending,
+		CreatedAt:   time.Now().UTC(),
+	}
+}
+
+// IsExpiredAt returns true if the access granted by the request has expired at the given time
+func (r *AccessRequest) IsExpiredAt(t time.Time) bool {
+	return r.Status == StatusApproved && r.ExpiresAt != nil && !t.Before(*r.ExpiresAt)
+}
+
+func (r *AccessRequest) ToAPIResponse() *api.AccessRequest {
+	resp := &api.AccessRequest{
+		Id:          r.ID,
+		RequesterId: r.RequesterID,
+		TargetType:  api.AccessRequestTargetType(r.TargetType),
+		TargetId:    r.TargetID,
+		Reason:      r.Reason,
+		Duration:    int(r.Duration.Seconds()),
+		Status:      api.AccessRequestStatus(r.Status),
+		CreatedAt:   r.CreatedAt,
+		ReviewedAt:  r.ReviewedAt,
+		ExpiresAt:   r.ExpiresAt,
+	}
+
+	if r.ReviewerID != "" {
+		reviewerID := r.ReviewerID
+		resp.ReviewerId = &reviewerID
+	}
+
+	return resp
+}
+
+func (r *AccessRequest) FromAPIRequest(req *api.AccessRequestCreate) {
+	r.TargetType = TargetType(req.TargetType)
+	r.TargetID = req.TargetId
+	r.Reason = req.Reason
+	r.Duration = time.Duration(req.Duration) * time.Second
+}
+
+func (r *AccessRequest) Validate() error {
+	if r.TargetType != TargetTypeGroup && r.TargetType != TargetTypePolicy {
+		return fmt.Errorf("invalid target type %s", r.TargetType)
+	}
+	if r.TargetID == "" {
+		return errors.New("target ID is required")
+	}
+	if r.Reason == "" {
+		return errors.New("reason is required")
+	}
+	if r.Duration < MinDuration || r.Duration > MaxDuration {
+		return fmt.Errorf("duration must be between %d and %d seconds", int(MinDuration.Seconds()), int(MaxDuration.Seconds()))
+	}
+
+	return nil
+}
+
+func (r *AccessRequest) EventMeta() map[string]any {
+	return map[string]any{
+		"requester_id": r.RequesterID,
+		"target_type":  r.TargetType,
+		"target_id":    r.TargetID,
+		"reason":       r.Reason,
+		"duration":     r.Duration.String(),
+	}
+}
@@ -0,0 +1,459 @@
+package accountconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DocumentVersion is the version of the account configuration document format
+const DocumentVersion = "v1"
+
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+)
+
+// RuleResourceTypePeer is the rule resource type referencing a peer by name.
+// Other rule resource types reference network resources by name.
+const RuleResourceTypePeer = "peer"
+
+// Document is the declarative configuration of an account.
+// Every object is referenced by name. A section that is omitted (null) is not managed and left untouched,
+// while an empty section removes every object of that kind.
+type Document struct {
+	Version          string             `json:"version" yaml:"version"`
+	Groups           []*Group           `json:"groups" yaml:"groups"`
+	Policies         []*Policy          `json:"policies" yaml:"policies"`
+	Routes           []*Route           `json:"routes" yaml:"routes"`
+	NameserverGroups []*NameserverGroup `json:"nameserver_groups" yaml:"nameserver_groups"`
+	Networks         []*Network         `json:"networks" yaml:"networks"`
+	Zones            []*Zone            `json:"zones" yaml:"zones"`
+}
+
+// Group is an API managed group. Groups issued by JWT or integrations are not part of the document,
+// but can still be referenced by name.
+type Group struct {
+	Name  string   `json:"name" yaml:"name"`
+	Peers []string `json:"peers,omitempty" yaml:"peers,omitempty"`
+}
+
+type Policy struct {
+	Name                string          `json:"name" yaml:"name"`
+	Description         string          `json:"description,omitempty" yaml:"description,omitempty"`
+	Enabled             bool            `json:"enabled" yaml:"enabled"`
+	SourcePostureChecks []string        `json:"source_posture_checks,omitempty" yaml:"source_posture_checks,omitempty"`
+	Schedule            *PolicySchedule `json:"schedule,omitempty" yaml:"schedule,omitempty"`
+	Rules               []*PolicyRule   `json:"rules" yaml:"rules"`
+}
+
+type PolicyRule struct {
+	Name                string              `json:"name" yaml:"name"`
+	Description         string              `json:"description,omitempty" yaml:"description,omitempty"`
+	Enabled             bool                `json:"enabled" yaml:"enabled"`
+	Action              string              `json:"action" yaml:"action"`
+	Bidirectional       bool                `json:"bidirectional" yaml:"bidirectional"`
+	Protocol            string              `json:"protocol" yaml:"protocol"`
+	Ports               []string            `json:"ports,omitempty" yaml:"ports,omitempty"`
+	PortRanges          []PortRange         `json:"port_ranges,omitempty" yaml:"port_ranges,omitempty"`
+	Sources             []string            `json:"sources,omitempty" yaml:"sources,omitempty"`
+	SourceResource      *RuleResource       `json:"source_resource,omitempty" yaml:"source_resource,omitempty"`
+	Destinations        []string            `json:"destinations,omitempty" yaml:"destinations,omitempty"`
+	DestinationResource *RuleResource       `json:"destination_resource,omitempty" yaml:"destination_resource,omitempty"`
+	AuthorizedGroups    map[string][]string `json:"authorized_groups,omitempty" yaml:"authorized_groups,omitempty"`
+	AuthorizedUser      string              `json:"authorized_user,omitempty" yaml:"authorized_user,omitempty"`
+}
+
+type PortRange struct {
+	Start uint16 `json:"start" yaml:"start"`
+	End   uint16 `json:"end" yaml:"end"`
+}
+
+// RuleResource references a peer or a network resource by name
+type RuleResource struct {
+	Type string `json:"type" yaml:"type"`
+	Name string `json:"name" yaml:"name"`
+}
+
+type PolicySchedule struct {
+	NotBefore *time.Time             `json:"not_before,omitempty" yaml:"not_before,omitempty"`
+	NotAfter  *time.Time             `json:"not_after,omitempty" yaml:"not_after,omitempty"`
+	TimeZone  string                 `json:"time_zone,omitempty" yaml:"time_zone,omitempty"`
+	Windows   []PolicyScheduleWindow `json:"windows,omitempty" yaml:"windows,omitempty"`
+}
+
+type PolicyScheduleWindow struct {
+	// Days are the lowercase three-letter week day names, e.g. mon
+	Days  []string `json:"days" yaml:"days"`
+	Start string   `json:"start" yaml:"start"`
+	End   string   `json:"end" yaml:"end"`
+}
+
+type Route struct {
+	NetworkID           string   `json:"network_id" yaml:"network_id"`
+	Description         string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Network             string   `json:"network,omitempty" yaml:"network,omitempty"`
+	Domains             []string `json:"domains,omitempty" yaml:"domains,omitempty"`
+	KeepRoute           bool     `json:"keep_route" yaml:"keep_route"`
+	Peer                string   `json:"peer,omitempty" yaml:"peer,omitempty"`
+	PeerGroups          []string `json:"peer_groups,omitempty" yaml:"peer_groups,omitempty"`
+	Metric              int      `json:"metric" yaml:"metric"`
+	Masquerade          bool     `json:"masquerade" yaml:"masquerade"`
+	Enabled             bool     `json:"enabled" yaml:"enabled"`
+	Groups              []string `json:"groups" yaml:"groups"`
+	AccessControlGroups []string `json:"access_control_groups,omitempty" yaml:"access_control_groups,omitempty"`
+	SkipAutoApply       bool     `json:"skip_auto_apply" yaml:"skip_auto_apply"`
+}
+
+// Key identifies the route by its network identifier and the peer or peer groups routing it
+func (r *Route) Key() string {
+	return r.NetworkID + " via " + routingPeersKey(r.Peer, r.PeerGroups)
+}
+
+type NameserverGroup struct {
+	Name                 string       `json:"name" yaml:"name"`
+	Description          string       `json:"description,omitempty" yaml:"description,omitempty"`
+	Nameservers          []Nameserver `json:"nameservers" yaml:"nameservers"`
+	Groups               []string     `json:"groups" yaml:"groups"`
+	Primary              bool         `json:"primary" yaml:"primary"`
+	Domains              []string     `json:"domains,omitempty" yaml:"domains,omitempty"`
+	Enabled              bool         `json:"enabled" yaml:"enabled"`
+	SearchDomainsEnabled bool         `json:"search_domains_enabled" yaml:"search_domains_enabled"`
+}
+
+type Nameserver struct {
+	IP     string `json:"ip" yaml:"ip"`
+	NSType string `json:"ns_type" yaml:"ns_type"`
+	Port   int    `json:"port" yaml:"port"`
+}
+
+type Network struct {
+	Name        string             `json:"name" yaml:"name"`
+	Description string             `json:"description,omitempty" yaml:"description,omitempty"`
+	Resources   []*NetworkResource `json:"resources,omitempty" yaml:"resources,omitempty"`
+	Routers     []*NetworkRouter   `json:"routers,omitempty" yaml:"routers,omitempty"`
+}
+
+type NetworkResource struct {
+	Name        string   `json:"name" yaml:"name"`
+	Description string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Address     string   `json:"address" yaml:"address"`
+	Groups      []string `json:"groups,omitempty" yaml:"groups,omitempty"`
+	Enabled     bool     `json:"enabled" yaml:"enabled"`
+}
+
+type NetworkRouter struct {
+	Peer       string   `json:"peer,omitempty" yaml:"peer,omitempty"`
+	PeerGroups []string `json:"peer_groups,omitempty" yaml:"peer_groups,omitempty"`
+	Masquerade bool     `json:"masquerade" yaml:"masquerade"`
+	Metric     int      `json:"metric" yaml:"metric"`
+	Enabled    bool     `json:"enabled" yaml:"enabled"`
+}
+
+// Key identifies the router by its peer or peer groups
+func (r *NetworkRouter) Key() string {
+	return routingPeersKey(r.Peer, r.PeerGroups)
+}
+
+type Zone struct {
+	Domain             string    `json:"domain" yaml:"domain"`
+	Name               string    `json:"name" yaml:"name"`
+	Enabled            bool      `json:"enabled" yaml:"enabled"`
+	EnableSearchDomain bool      `json:"enable_search_domain" yaml:"enable_search_domain"`
+	DistributionGroups []string  `json:"distribution_groups" yaml:"distribution_groups"`
+	Records            []*Record `json:"records,omitempty" yaml:"records,omitempty"`
+}
+
+type Record struct {
+	Name    string `json:"name" yaml:"name"`
+	Type    string `json:"type" yaml:"type"`
+	Content string `json:"content" yaml:"content"`
+	TTL     int    `json:"ttl" yaml:"ttl"`
+}
+
+// Key identifies the record by its name, type and content
+func (r *Record) Key() string {
+	return fmt.Sprintf("%s %s %s", r.Name, r.Type, r.Content)
+}
+
+func routingPeersKey(peer string, peerGroups []string) string {
+	if peer != "" {
+		return "peer " + peer
+	}
+	groups := slices.Clone(peerGroups)
+	slices.Sort(groups)
+	return "groups " + strings.Join(groups, ",")
+}
+
+// Unmarshal parses a document in the given format, rejecting unknown fields
+func Unmarshal(data []byte, format string) (*Document, error) {
+	document := &Document{}
+
+	switch format {
+	case FormatJSON:
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(document); err != nil {
+			return nil, fmt.Errorf("parse JSON document: %w", err)
+		}
+	case FormatYAML:
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(document); err != nil {
+			return nil, fmt.Errorf("parse YAML document: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported document format %q", format)
+	}
+
+	return document, nil
+}
+
+// Marshal encodes the document in the given format
+func Marshal(document *Document, format string) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		return json.MarshalIndent(document, "", "  ")
+	case FormatYAML:
+		return yaml.Marshal(document)
+	default:
+		return nil, fmt.Errorf("unsupported document format %q", format)
+	}
+}
+
+// Validate checks the document structure. References and object settings are validated when applied.
+func (d *Document) Validate() error {
+	if d.Version != DocumentVersion {
+		return fmt.Errorf("unsupported document version %q, expected %q", d.Version, DocumentVersion)
+	}
+
+	if err := validateKeys("group", d.Groups, func(g *Group) string { return g.Name }); err != nil {
+		return err
+	}
+
+	if err := validateKeys("policy", d.Policies, func(p *Policy) string { return p.Name }); err != nil {
+		return err
+	}
+	for _, policy := range d.Policies {
+		if err := policy.validate(); err != nil {
+			return fmt.Errorf("policy %q: %w", policy.Name, err)
+		}
+	}
+
+	if err := validateKeys("route", d.Routes, (*Route).Key); err != nil {
+		return err
+	}
+	for _, route := range d.Routes {
+		if route.NetworkID == "" {
+			return errors.New("route network_id is required")
+		}
+		if (route.Network == "") == (len(route.Domains) == 0) {
+			return fmt.Errorf("route %q: exactly one of network or domains should be set", route.Key())
+		}
+		if (route.Peer == "") == (len(route.PeerGroups) == 0) {
+			return fmt.Errorf("route %q: exactly one of peer or peer_groups should be set", route.NetworkID)
+		}
+	}
+
+	if err := validateKeys("nameserver group", d.NameserverGroups, func(g *NameserverGroup) string { return g.Name }); err != nil {
+		return err
+	}
+
+	if err := validateKeys("network", d.Networks, func(n *Network) string { return n.Name }); err != nil {
+		return err
+	}
+	var resources []*NetworkResource
+	for _, network := range d.Networks {
+		resources = append(resources, network.Resources...)
+		if err := validateKeys("router", network.Routers, (*NetworkRouter).Key); err != nil {
+			return fmt.Errorf("network %q: %w", network.Name, err)
+		}
+		for _, router := range network.Routers {
+			if (router.Peer == "") == (len(router.PeerGroups) == 0) {
+				return fmt.Errorf("network %q: router: exactly one of peer or peer_groups should be set", network.Name)
+			}
+		}
+	}
+	// resource names are unique in the account
+	if err := validateKeys("network resource", resources, func(r *NetworkResource) string { return r.Name }); err != nil {
+		return err
+	}
+
+	if err := validateKeys("zone", d.Zones, func(z *Zone) string { return z.Domain }); err != nil {
+		return err
+	}
+	for _, zone := range d.Zones {
+		if err := validateKeys("record", zone.Records, (*Record).Key); err != nil {
+			return fmt.Errorf("zone %q: %w", zone.Domain, err)
+		}
+	}
+
+	return nil
+}
+
+func (p *Policy) validate() error {
+	if err := validateKeys("rule", p.Rules, func(r *PolicyRule) string { return r.Name }); err != nil {
+		return err
+	}
+
+	for _, rule := range p.Rules {
+		for _, resource := range []*RuleResource{rule.SourceResource, rule.DestinationResource} {
+			if resource != nil && (resource.Type == "" || resource.Name == "") {
+				return fmt.Errorf("rule %q: resource type and name are required", rule.Name)
+			}
+		}
+	}
+
+	if p.Schedule != nil {
+		for _, window := range p.Schedule.Windows {
+			for _, day := range window.Days {
+				if _, ok := weekdays[day]; !ok {
+					return fmt.Errorf("invalid schedule window day %q", day)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateKeys[T any](kind string, items []T, key func(T) string) error {
+	seen := make(map[string]struct{}, len(items))
+	for _, item := range items {
+		k := key(item)
+		if k == "" {
+			return fmt.Errorf("%s name is required", kind)
+		}
+		if _, ok := seen[k]; ok {
+			return fmt.Errorf("duplicate %s %q", kind, k)
+		}
+		seen[k] = struct{}{}
+	}
+	return nil
+}
+
+// Normalize sorts the unordered lists of the document and clears empty ones,
+// so that equal configurations compare equal regardless of how they were written.
+// Omitted sections stay nil and empty sections stay empty.
+func (d *Document) Normalize() {
+	for _, group := range d.Groups {
+		group.Peers = sortedOrNil(group.Peers)
+	}
+
+	for _, policy := range d.Policies {
+		policy.SourcePostureChecks = sortedOrNil(policy.SourcePostureChecks)
+		for _, rule := range policy.Rules {
+			rule.Ports = sortedOrNil(rule.Ports)
+			rule.Sources = sortedOrNil(rule.Sources)
+			rule.Destinations = sortedOrNil(rule.Destinations)
+			if len(rule.PortRanges) == 0 {
+				rule.PortRanges = nil
+			}
+			if len(rule.AuthorizedGroups) == 0 {
+				rule.AuthorizedGroups = nil
+			}
+			for name, users := range rule.AuthorizedGroups {
+				rule.AuthorizedGroups[name] = sortedOrNil(users)
+			}
+		}
+		if policy.Schedule != nil {
+			policy.Schedule.NotBefore = utcOrNil(policy.Schedule.NotBefore)
+			policy.Schedule.NotAfter = utcOrNil(policy.Schedule.NotAfter)
+			if len(policy.Schedule.Windows) == 0 {
+				policy.Schedule.Windows = nil
+			}
+		}
+	}
+
+	for _, route := range d.Routes {
+		route.Domains = sortedOrNil(route.Domains)
+		route.PeerGroups = sortedOrNil(route.PeerGroups)
+		route.Groups = sortedOrNil(route.Groups)
+		route.AccessControlGroups = sortedOrNil(route.AccessControlGroups)
+	}
+
+	for _, nsGroup := range d.NameserverGroups {
+		nsGroup.Groups = sortedOrNil(nsGroup.Groups)
+		nsGroup.Domains = sortedOrNil(nsGroup.Domains)
+		if len(nsGroup.Nameservers) == 0 {
+			nsGroup.Nameservers = nil
+		}
+	}
+
+	for _, network := range d.Networks {
+		for _, resource := range network.Resources {
+			resource.Address = normalizeAddress(resource.Address)
+			resource.Groups = sortedOrNil(resource.Groups)
+		}
+		for _, router := range network.Routers {
+			router.PeerGroups = sortedOrNil(router.PeerGroups)
+		}
+		if len(network.Resources) == 0 {
+			network.Resources = nil
+		}
+		if len(network.Routers) == 0 {
+			network.Routers = nil
+		}
+	}
+
+	for _, zone := range d.Zones {
+		zone.DistributionGroups = sortedOrNil(zone.DistributionGroups)
+		if len(zone.Records) == 0 {
+			zone.Records = nil
+		}
+	}
+}
+
+func sortedOrNil(items []string) []string {
+	if len(items) == 0 {
+		return nil
+	}
+	sorted := slices.Clone(items)
+	slices.Sort(sorted)
+	return sorted
+}
+
+func utcOrNil(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
+
+// normalizeAddress writes single host prefixes as addresses, the way resource addresses are usually given
+func normalizeAddress(address string) string {
+	prefix, err := netip.ParsePrefix(address)
+	if err != nil || !prefix.IsSingleIP() {
+		return address
+	}
+	return prefix.Addr().String()
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// ParseWeekday returns the week day of a lowercase three-letter day name
+func ParseWeekday(day string) (time.Weekday, bool) {
+	weekday, ok := weekdays[day]
+	return weekday, ok
+}
+
+// WeekdayName returns the lowercase three-letter name of the week day
+func WeekdayName(day time.Weekday) string {
+	return strings.ToLower(day.String()[:3])
+}
@@ -0,0 +1,136 @@
+package accountconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshal(t *testing.T) {
+	t.Run("omitted and empty sections", func(t *testing.T) {
+		document, err := Unmarshal([]byte("version: v1\ngroups: []\npolicies:\n  - name: all\n    enabled: true\n    rules: []\n"), FormatYAML)
+		require.NoError(t, err)
+
+		assert.NotNil(t, document.Groups)
+		assert.Empty(t, document.Groups)
+		assert.Len(t, document.Policies, 1)
+		assert.Nil(t, document.Routes)
+		assert.Nil(t, document.Zones)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		document, err := Unmarshal([]byte(`{"version":"v1","routes":null,"zones":[]}`), FormatJSON)
+		require.NoError(t, err)
+
+		assert.Nil(t, document.Routes)
+		assert.NotNil(t, document.Zones)
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		_, err := Unmarshal([]byte("version: v1\ngroup: []\n"), FormatYAML)
+		assert.Error(t, err)
+
+		_, err = Unmarshal([]byte(`{"version":"v1","group":[]}`), FormatJSON)
+		assert.Error(t, err)
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		document := &Document{
+			Version:  DocumentVersion,
+			Groups:   []*Group{{Name: "devs", Peers: []string{"laptop"}}},
+			Policies: []*Policy{},
+		}
+
+		data, err := Marshal(document, FormatYAML)
+		require.NoError(t, err)
+
+		parsed, err := Unmarshal(data, FormatYAML)
+		require.NoError(t, err)
+		assert.Equal(t, document.Groups, parsed.Groups)
+		assert.NotNil(t, parsed.Policies)
+	})
+}
+
+func TestDocument_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		document  Document
+		expectErr string
+	}{
+		{
+			name:     "valid",
+			document: Document{Version: DocumentVersion, Groups: []*Group{{Name: "devs"}}},
+		},
+		{
+			name:      "unsupported version",
+			document:  Document{Version: "v2"},
+			expectErr: `unsupported document version "v2", expected "v1"`,
+		},
+		{
+			name:      "duplicate group",
+			document:  Document{Version: DocumentVersion, Groups: []*Group{{Name: "devs"}, {Name: "devs"}}},
+			expectErr: `duplicate group "devs"`,
+		},
+		{
+			name:      "missing policy name",
+			document:  Document{Version: DocumentVersion, Policies: []*Policy{{}}},
+			expectErr: "policy name is required",
+		},
+		{
+			name: "invalid schedule day",
+			document: Document{Version: DocumentVersion, Policies: []*Policy{{
+				Name:     "work hours",
+				Schedule: &PolicySchedule{Windows: []PolicyScheduleWindow{{Days: []string{"monday"}, Start: "09:00", End: "17:00"}}},
+			}}},
+			expectErr: `policy "work hours": invalid schedule window day "monday"`,
+		},
+		{
+			name:      "route with network and domains",
+			document:  Document{Version: DocumentVersion, Routes: []*Route{{NetworkID: "office", Network: "10.0.0.0/24", Domains: []string{"example.com"}, Peer: "router"}}},
+			expectErr: `route "office via peer router": exactly one of network or domains should be set`,
+		},
+		{
+			name: "duplicate resource in different networks",
+			document: Document{Version: DocumentVersion, Networks: []*Network{
+				{Name: "office", Resources: []*NetworkResource{{Name: "db", Address: "10.0.0.1"}}},
+				{Name: "cloud", Resources: []*NetworkResource{{Name: "db", Address: "10.1.0.1"}}},
+			}},
+			expectErr: `duplicate network resource "db"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.document.Validate()
+			if tt.expectErr != "" {
+				assert.EqualError(t, err, tt.expectErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestDocument_Normalize(t *testing.T) {
+	document := &Document{
+		Version: DocumentVersion,
+		Groups:  []*Group{{Name: "devs", Peers: []string{"b", "a"}}, {Name: "empty", Peers: []string{}}},
+		Networks: []*Network{{
+			Name:      "office",
+			Resources: []*NetworkResource{{Name: "db", Address: "10.0.0.1/32"}, {Name: "lan", Address: "10.0.0.0/24"}},
+			Routers:   []*NetworkRouter{},
+		}},
+		Routes: []*Route{},
+	}
+
+	document.Normalize()
+
+	assert.Equal(t, []string{"a", "b"}, document.Groups[0].Peers)
+	assert.Nil(t, document.Groups[1].Peers)
+	assert.Equal(t, "10.0.0.1", document.Networks[0].Resources[0].Address)
+	assert.Equal(t, "10.0.0.0/24", document.Networks[0].Resources[1].Address)
+	assert.Nil(t, document.Networks[0].Routers)
+	assert.NotNil(t, document.Routes, "empty sections stay managed")
+	assert.Nil(t, document.Zones, "omitted sections stay unmanaged")
+}
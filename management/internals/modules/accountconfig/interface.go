@@ -0,0 +1,14 @@
+package accountconfig
+
+import (
+	"context"
+)
+
+type Manager interface {
+	// Export returns the current account configuration as a document
+	Export(ctx context.Context, accountID, userID string) (*Document, error)
+	// Plan returns the changes required to make the account configuration match the document
+	Plan(ctx context.Context, accountID, userID string, document *Document) ([]*Change, error)
+	// Apply makes the account configuration match the document and returns the applied changes
+	Apply(ctx context.Context, accountID, userID string, document *Document) ([]*Change, error)
+}
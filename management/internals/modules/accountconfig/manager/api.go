@@ -0,0 +1,141 @@
+package manager
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/netbirdio/netbird/management/internals/modules/accountconfig"
+	nbcontext "github.com/netbirdio/netbird/management/server/context"
+	"github.com/netbirdio/netbird/shared/management/http/api"
+	"github.com/netbirdio/netbird/shared/management/http/util"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+type handler struct {
+	manager accountconfig.Manager
+}
+
+func RegisterEndpoints(router *mux.Router, manager accountconfig.Manager) {
+	h := &handler{
+		manager: manager,
+	}
+
+	router.HandleFunc("/account-config", h.exportConfig).Methods("GET", "OPTIONS")
+	router.HandleFunc("/account-config/plan", h.planConfig).Methods("POST", "OPTIONS")
+	router.HandleFunc("/account-config/apply", h.applyConfig).Methods("POST", "OPTIONS")
+}
+
+func (h *handler) exportConfig(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = accountconfig.FormatJSON
+	}
+	if format != accountconfig.FormatJSON && format != accountconfig.FormatYAML {
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "invalid format %q, should be json or yaml", format), w)
+		return
+	}
+
+	document, err := h.manager.Export(r.Context(), userAuth.AccountId, userAuth.UserId)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	if format == accountconfig.FormatJSON {
+		util.WriteJSONObject(r.Context(), w, document)
+		return
+	}
+
+	data, err := accountconfig.Marshal(document, format)
+	if err != nil {
+		util.WriteError(r.Context(), status.Errorf(status.Internal, "failed to encode account configuration"), w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/yaml")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(data)
+}
+
+func (h *handler) planConfig(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	document, err := readDocument(r)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	changes, err := h.manager.Plan(r.Context(), userAuth.AccountId, userAuth.UserId, document)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, toChangesResponse(changes))
+}
+
+func (h *handler) applyConfig(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	document, err := readDocument(r)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	changes, err := h.manager.Apply(r.Context(), userAuth.AccountId, userAuth.UserId, document)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, toChangesResponse(changes))
+}
+
+// readDocument parses the request body as YAML when the content type says so and as JSON otherwise
+func readDocument(r *http.Request) (*accountconfig.Document, error) {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, status.Errorf(status.InvalidArgument, "couldn't read request body")
+	}
+
+	format := accountconfig.FormatJSON
+	if strings.Contains(r.Header.Get("Content-Type"), "yaml") {
+		format = accountconfig.FormatYAML
+	}
+
+	document, err := accountconfig.Unmarshal(data, format)
+	if err != nil {
+		return nil, status.Errorf(status.InvalidArgument, "%s", err.Error())
+	}
+
+	return document, nil
+}
+
+func toChangesResponse(changes []*accountconfig.Change) *api.AccountConfigChanges {
+	resp := &api.AccountConfigChanges{
+		Changes: make([]api.AccountConfigChange, 0, len(changes)),
+	}
+	for _, change := range changes {
+		resp.Changes = append(resp.Changes, change.ToAPIResponse())
+	}
+	return resp
+}
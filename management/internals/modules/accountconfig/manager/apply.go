@@ -0,0 +1,733 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"slices"
+
+	nbdns "github.com/netbirdio/netbird/dns"
+	"github.com/netbirdio/netbird/management/internals/modules/accountconfig"
+	"github.com/netbirdio/netbird/management/internals/modules/zones"
+	"github.com/netbirdio/netbird/management/internals/modules/zones/records"
+	resourceTypes "github.com/netbirdio/netbird/management/server/networks/resources/types"
+	routerTypes "github.com/netbirdio/netbird/management/server/networks/routers/types"
+	networkTypes "github.com/netbirdio/netbird/management/server/networks/types"
+	"github.com/netbirdio/netbird/management/server/types"
+	"github.com/netbirdio/netbird/route"
+	"github.com/netbirdio/netbird/shared/management/domain"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+// applyChange applies a single change of the plan. Objects created along the way are added to the state,
+// so that the following changes can reference them by name.
+// On a dry run state the change is only resolved and validated, created objects are added with a planned ID.
+func (m *managerImpl) applyChange(ctx context.Context, accountID, userID string, current *state, document *accountconfig.Document, change *accountconfig.Change) error {
+	switch change.Kind {
+	case accountconfig.KindGroup:
+		return m.applyGroup(ctx, accountID, userID, current, document, change)
+	case accountconfig.KindNetwork:
+		return m.applyNetwork(ctx, accountID, userID, current, document, change)
+	case accountconfig.KindNetworkResource:
+		return m.applyNetworkResource(ctx, accountID, userID, current, document, change)
+	case accountconfig.KindNetworkRouter:
+		return m.applyNetworkRouter(ctx, accountID, userID, current, document, change)
+	case accountconfig.KindPolicy:
+		return m.applyPolicy(ctx, accountID, userID, current, document, change)
+	case accountconfig.KindRoute:
+		return m.applyRoute(ctx, accountID, userID, current, document, change)
+	case accountconfig.KindNameserverGroup:
+		return m.applyNameserverGroup(ctx, accountID, userID, current, document, change)
+	case accountconfig.KindZone:
+		return m.applyZone(ctx, accountID, userID, current, document, change)
+	case accountconfig.KindRecord:
+		return m.applyRecord(ctx, accountID, userID, current, document, change)
+	default:
+		return fmt.Errorf("unsupported kind %s", change.Kind)
+	}
+}
+
+func (m *managerImpl) applyGroup(ctx context.Context, accountID, userID string, current *state, document *accountconfig.Document, change *accountconfig.Change) error {
+	existing := current.groupsByName[change.Name]
+
+	if change.Action == accountconfig.ActionDelete {
+		if current.dryRun {
+			return nil
+		}
+		return m.accountManager.DeleteGroup(ctx, accountID, userID, existing.ID)
+	}
+
+	desired := find(document.Groups, change.Name, func(g *accountconfig.Group) string { return g.Name })
+	peerIDs, err := current.peerIDs(desired.Peers)
+	if err != nil {
+		return err
+	}
+
+	if change.Action == accountconfig.ActionCreate {
+		group := &types.Group{
+			Name:   desired.Name,
+			Issued: types.GroupIssuedAPI,
+			Peers:  peerIDs,
+		}
+		if current.dryRun {
+			group.ID = plannedID(change)
+		} else if err = m.accountManager.CreateGroup(ctx, accountID, userID, group); err != nil {
+			return err
+		}
+		current.groups = append(current.groups, group)
+		current.index()
+		return nil
+	}
+
+	group := &types.Group{
+		ID:                   existing.ID,
+		Name:                 desired.Name,
+		Issued:               existing.Issued,
+		Peers:                peerIDs,
+		Resources:            existing.Resources,
+		IntegrationReference: existing.IntegrationReference,
+	}
+	if current.dryRun {
+		current.replaceGroup(group)
+		return nil
+	}
+	return m.accountManager.UpdateGroup(ctx, accountID, userID, group)
+}
+
+func (m *managerImpl) applyNetwork(ctx context.Context, accountID, userID string, current *state, document *accountconfig.Document, change *accountconfig.Change) error {
+	existing := current.networkByName(change.Name)
+
+	if current.dryRun {
+		if change.Action == accountconfig.ActionCreate {
+			current.networks = append(current.networks, &networkTypes.Network{ID: plannedID(change), AccountID: accountID, Name: change.Name})
+		}
+		return nil
+	}
+
+	if change.Action == accountconfig.ActionDelete {
+		return m.networksManager.DeleteNetwork(ctx, accountID, userID, existing.ID)
+	}
+
+	desired := find(document.Networks, change.Name, func(n *accountconfig.Network) string { return n.Name })
+
+	if change.Action == accountconfig.ActionCreate {
+		network, err := m.networksManager.CreateNetwork(ctx, userID, &networkTypes.Network{
+			AccountID:   accountID,
+			Name:        desired.Name,
+			Description: desired.Description,
+		})
+		if err != nil {
+			return err
+		}
+		current.networks = append(current.networks, network)
+		return nil
+	}
+
+	_, err := m.networksManager.UpdateNetwork(ctx, userID, &networkTypes.Network{
+		ID:          existing.ID,
+		AccountID:   accountID,
+		Name:        desired.Name,
+		Description: desired.Description,
+	})
+	return err
+}
+
+func (m *managerImpl) applyNetworkResource(ctx context.Context, accountID, userID string, current *state, document *accountconfig.Document, change *accountconfig.Change) error {
+	network := current.networkByName(change.Parent)
+	if network == nil {
+		return status.Errorf(status.NotFound, "network %q not found", change.Parent)
+	}
+
+	if change.Action == accountconfig.ActionDelete {
+		existing, err := current.resourceByName(change.Name)
+		if err != nil {
+			return err
+		}
+		if current.dryRun {
+			current.resources = slices.DeleteFunc(current.resources, func(r *resourceTypes.NetworkResource) bool { return r.ID == existing.ID })
+			current.index()
+			return nil
+		}
+		return m.resourcesManager.DeleteResource(ctx, accountID, userID, network.ID, existing.ID)
+	}
+
+	desiredNetwork := find(document.Networks, change.Parent, func(n *accountconfig.Network) string { return n.Name })
+	desired := find(desiredNetwork.Resources, change.Name, func(r *accountconfig.NetworkResource) string { return r.Name })
+	groupIDs, err := current.groupIDs(desired.Groups)
+	if err != nil {
+		return err
+	}
+
+	resource := &resourceTypes.NetworkResource{
+		NetworkID:   network.ID,
+		AccountID:   accountID,
+		Name:        desired.Name,
+		Description: desired.Description,
+		Address:     desired.Address,
+		GroupIDs:    groupIDs,
+		Enabled:     desired.Enabled,
+	}
+
+	if change.Action == accountconfig.ActionCreate {
+		if current.dryRun {
+			if resource, err = current.validateNewResource(resource); err != nil {
+				return err
+			}
+			resource.ID = plannedID(change)
+		} else if resource, err = m.resourcesManager.CreateResource(ctx, userID, resource); err != nil {
+			return err
+		}
+		current.resources = append(current.resources, resource)
+		current.index()
+		return nil
+	}
+
+	existing, err := current.resourceByName(change.Name)
+	if err != nil {
+		return err
+	}
+	if current.dryRun {
+		if _, _, _, err = resourceTypes.GetResourceType(resource.Address); err != nil {
+			return status.Errorf(status.InvalidArgument, "invalid address: %s", err.Error())
+		}
+		return nil
+	}
+	resource.ID = existing.ID
+	_, err = m.resourcesManager.UpdateResource(ctx, userID, resource)
+	return err
+}
+
+func (m *managerImpl) applyNetworkRouter(ctx context.Context, accountID, userID string, current *state, document *accountconfig.Document, change *accountconfig.Change) error {
+	network := current.networkByName(change.Parent)
+	if network == nil {
+		return status.Errorf(status.NotFound, "network %q not found", change.Parent)
+	}
+
+	var existing *routerTypes.NetworkRouter
+	for _, router := range current.routers[network.ID] {
+		if current.toDocumentRouter(router).Key() == change.Name {
+			existing = router
+		}
+	}
+
+	if change.Action == accountconfig.ActionDelete {
+		if current.dryRun {
+			return nil
+		}
+		return m.routersManager.DeleteRouter(ctx, accountID, userID, network.ID, existing.ID)
+	}
+
+	desiredNetwork := find(document.Networks, change.Parent, func(n *accountconfig.Network) string { return n.Name })
+	desired := find(desiredNetwork.Routers, change.Name, (*accountconfig.NetworkRouter).Key)
+
+	var peerID string
+	var err error
+	if desired.Peer != "" {
+		if peerID, err = current.peerID(desired.Peer); err != nil {
+			return err
+		}
+	}
+	peerGroups, err := current.groupIDs(desired.PeerGroups)
+	if err != nil {
+		return err
+	}
+
+	router, err := routerTypes.NewNetworkRouter(accountID, network.ID, peerID, peerGroups, desired.Masquerade, desired.Metric, desired.Enabled)
+	if err != nil {
+		return status.Errorf(status.InvalidArgument, "%s", err.Error())
+	}
+
+	if current.dryRun {
+		return nil
+	}
+
+	if change.Action == accountconfig.ActionCreate {
+		_, err = m.routersManager.CreateRouter(ctx, userID, router)
+		return err
+	}
+
+	router.ID = existing.ID
+	_, err = m.routersManager.UpdateRouter(ctx, userID, router)
+	return err
+}
+
+func (m *managerImpl) applyPolicy(ctx context.Context, accountID, userID string, current *state, document *accountconfig.Document, change *accountconfig.Change) error {
+	var existing *types.Policy
+	for _, policy := range current.policies {
+		if policy.Name == change.Name {
+			existing = policy
+		}
+	}
+
+	if change.Action == accountconfig.ActionDelete {
+		if current.dryRun {
+			return nil
+		}
+		return m.accountManager.DeletePolicy(ctx, accountID, existing.ID, userID)
+	}
+
+	desired := find(document.Policies, change.Name, func(p *accountconfig.Policy) string { return p.Name })
+	policy, err := current.toPolicy(desired, existing)
+	if err != nil {
+		return err
+	}
+	policy.AccountID = accountID
+
+	if current.dryRun {
+		if err = policy.Schedule.Validate(); err != nil {
+			return status.Errorf(status.InvalidArgument, "%s", err.Error())
+		}
+		return nil
+	}
+	_, err = m.accountManager.SavePolicy(ctx, accountID, userID, policy, change.Action == accountconfig.ActionCreate)
+	return err
+}
+
+func (m *managerImpl) applyRoute(ctx context.Context, accountID, userID string, current *state, document *accountconfig.Document, change *accountconfig.Change) error {
+	var existing *route.Route
+	for _, r := range current.routes {
+		if current.toDocumentRoute(r).Key() == change.Name {
+			existing = r
+		}
+	}
+
+	if change.Action == accountconfig.ActionDelete {
+		if current.dryRun {
+			current.routes = slices.DeleteFunc(current.routes, func(r *route.Route) bool { return r.ID == existing.ID })
+			return nil
+		}
+		return m.accountManager.DeleteRoute(ctx, accountID, existing.ID, userID)
+	}
+
+	desired := find(document.Routes, change.Name, (*accountconfig.Route).Key)
+	r, err := current.toRoute(desired)
+	if err != nil {
+		return err
+	}
+
+	if current.dryRun {
+		r.ID = route.ID(plannedID(change))
+		if existing != nil {
+			r.ID = existing.ID
+		}
+		return current.validateRoute(r)
+	}
+
+	if change.Action == accountconfig.ActionCreate {
+		_, err = m.accountManager.CreateRoute(ctx, accountID, r.Network, r.NetworkType, r.Domains, r.Peer, r.PeerGroups, r.Description,
+			r.NetID, r.Masquerade, r.Metric, r.Groups, r.AccessControlGroups, r.Enabled, userID, r.KeepRoute, r.SkipAutoApply)
+		return err
+	}
+
+	r.ID = existing.ID
+	return m.accountManager.SaveRoute(ctx, accountID, userID, r)
+}
+
+func (m *managerImpl) applyNameserverGroup(ctx context.Context, accountID, userID string, current *state, document *accountconfig.Document, change *accountconfig.Change) error {
+	var existing *nbdns.NameServerGroup
+	for _, nsGroup := range current.nsGroups {
+		if nsGroup.Name == change.Name {
+			existing = nsGroup
+		}
+	}
+
+	if change.Action == accountconfig.ActionDelete {
+		if current.dryRun {
+			current.nsGroups = slices.DeleteFunc(current.nsGroups, func(g *nbdns.NameServerGroup) bool { return g.ID == existing.ID })
+			return nil
+		}
+		return m.accountManager.DeleteNameServerGroup(ctx, accountID, existing.ID, userID)
+	}
+
+	desired := find(document.NameserverGroups, change.Name, func(g *accountconfig.NameserverGroup) string { return g.Name })
+
+	nameservers := make([]nbdns.NameServer, 0, len(desired.Nameservers))
+	for _, ns := range desired.Nameservers {
+		ip, err := netip.ParseAddr(ns.IP)
+		if err != nil {
+			return status.Errorf(status.InvalidArgument, "invalid nameserver IP %q", ns.IP)
+		}
+		nameservers = append(nameservers, nbdns.NameServer{
+			IP:     ip,
+			NSType: nbdns.ToNameServerType(ns.NSType),
+			Port:   ns.Port,
+		})
+	}
+
+	groupIDs, err := current.groupIDs(desired.Groups)
+	if err != nil {
+		return err
+	}
+
+	nsGroup := &nbdns.NameServerGroup{
+		AccountID:            accountID,
+		Name:                 desired.Name,
+		Description:          desired.Description,
+		NameServers:          nameservers,
+		Groups:               groupIDs,
+		Primary:              desired.Primary,
+		Domains:              desired.Domains,
+		Enabled:              desired.Enabled,
+		SearchDomainsEnabled: desired.SearchDomainsEnabled,
+	}
+
+	if current.dryRun {
+		nsGroup.ID = plannedID(change)
+		if existing != nil {
+			nsGroup.ID = existing.ID
+		}
+		return current.validateNameserverGroup(nsGroup)
+	}
+
+	if change.Action == accountconfig.ActionCreate {
+		_, err = m.accountManager.CreateNameServerGroup(ctx, accountID, desired.Name, desired.Description, nameservers, groupIDs,
+			desired.Primary, desired.Domains, desired.Enabled, userID, desired.SearchDomainsEnabled)
+		return err
+	}
+
+	nsGroup.ID = existing.ID
+	return m.accountManager.SaveNameServerGroup(ctx, accountID, userID, nsGroup)
+}
+
+func (m *managerImpl) applyZone(ctx context.Context, accountID, userID string, current *state, document *accountconfig.Document, change *accountconfig.Change) error {
+	existing := current.zoneByDomain(change.Name)
+
+	if change.Action == accountconfig.ActionDelete {
+		if current.dryRun {
+			return nil
+		}
+		return m.zonesManager.DeleteZone(ctx, accountID, userID, existing.ID)
+	}
+
+	desired := find(document.Zones, change.Name, func(z *accountconfig.Zone) string { return z.Domain })
+	groupIDs, err := current.groupIDs(desired.DistributionGroups)
+	if err != nil {
+		return err
+	}
+
+	zone := &zones.Zone{
+		AccountID:          accountID,
+		Name:               desired.Name,
+		Domain:             desired.Domain,
+		Enabled:            desired.Enabled,
+		EnableSearchDomain: desired.EnableSearchDomain,
+		DistributionGroups: groupIDs,
+	}
+	if err = zone.Validate(); err != nil {
+		return status.Errorf(status.InvalidArgument, "%s", err.Error())
+	}
+
+	if change.Action == accountconfig.ActionCreate {
+		if current.dryRun {
+			zone.ID = plannedID(change)
+		} else if zone, err = m.zonesManager.CreateZone(ctx, accountID, userID, zone); err != nil {
+			return err
+		}
+		current.zones = append(current.zones, zone)
+		return nil
+	}
+
+	if current.dryRun {
+		return nil
+	}
+	zone.ID = existing.ID
+	_, err = m.zonesManager.UpdateZone(ctx, accountID, userID, zone)
+	return err
+}
+
+func (m *managerImpl) applyRecord(ctx context.Context, accountID, userID string, current *state, document *accountconfig.Document, change *accountconfig.Change) error {
+	zone := current.zoneByDomain(change.Parent)
+	if zone == nil {
+		return status.Errorf(status.NotFound, "zone %q not found", change.Parent)
+	}
+
+	var existing *records.Record
+	for _, record := range zone.Records {
+		if current.toDocumentRecord(record).Key() == change.Name {
+			existing = record
+		}
+	}
+
+	if change.Action == accountconfig.ActionDelete {
+		if current.dryRun {
+			return nil
+		}
+		return m.recordsManager.DeleteRecord(ctx, accountID, userID, zone.ID, existing.ID)
+	}
+
+	desiredZone := find(document.Zones, change.Parent, func(z *accountconfig.Zone) string { return z.Domain })
+	desired := find(desiredZone.Records, change.Name, (*accountconfig.Record).Key)
+
+	record := &records.Record{
+		AccountID: accountID,
+		ZoneID:    zone.ID,
+		Name:      desired.Name,
+		Type:      records.RecordType(desired.Type),
+		Content:   desired.Content,
+		TTL:       desired.TTL,
+	}
+	if err := record.Validate(); err != nil {
+		return status.Errorf(status.InvalidArgument, "%s", err.Error())
+	}
+
+	if current.dryRun {
+		return nil
+	}
+
+	if change.Action == accountconfig.ActionCreate {
+		_, err := m.recordsManager.CreateRecord(ctx, accountID, userID, zone.ID, record)
+		return err
+	}
+
+	record.ID = existing.ID
+	_, err := m.recordsManager.UpdateRecord(ctx, accountID, userID, zone.ID, record)
+	return err
+}
+
+// toPolicy converts the document policy, reusing the IDs of the rules of the existing policy with the same name
+func (s *state) toPolicy(desired *accountconfig.Policy, existing *types.Policy) (*types.Policy, error) {
+	policy := &types.Policy{
+		Name:        desired.Name,
+		Description: desired.Description,
+		Enabled:     desired.Enabled,
+	}
+
+	existingRuleIDs := make(map[string]string)
+	if existing != nil {
+		policy.ID = existing.ID
+		for _, rule := range existing.Rules {
+			existingRuleIDs[rule.Name] = rule.ID
+		}
+	}
+
+	var err error
+	if policy.SourcePostureChecks, err = s.postureCheckIDs(desired.SourcePostureChecks); err != nil {
+		return nil, err
+	}
+
+	if desired.Schedule != nil {
+		policy.Schedule = &types.PolicySchedule{
+			NotBefore: desired.Schedule.NotBefore,
+			NotAfter:  desired.Schedule.NotAfter,
+			TimeZone:  desired.Schedule.TimeZone,
+		}
+		for _, window := range desired.Schedule.Windows {
+			w := types.PolicyScheduleWindow{Start: window.Start, End: window.End}
+			for _, day := range window.Days {
+				weekday, ok := accountconfig.ParseWeekday(day)
+				if !ok {
+					return nil, status.Errorf(status.InvalidArgument, "invalid schedule window day %q", day)
+				}
+				w.Days = append(w.Days, weekday)
+			}
+			policy.Schedule.Windows = append(policy.Schedule.Windows, w)
+		}
+	}
+
+	for _, desiredRule := range desired.Rules {
+		rule := &types.PolicyRule{
+			ID:             existingRuleIDs[desiredRule.Name],
+			PolicyID:       policy.ID,
+			Name:           desiredRule.Name,
+			Description:    desiredRule.Description,
+			Enabled:        desiredRule.Enabled,
+			Action:         types.PolicyTrafficActionType(desiredRule.Action),
+			Bidirectional:  desiredRule.Bidirectional,
+			Protocol:       types.PolicyRuleProtocolType(desiredRule.Protocol),
+			Ports:          desiredRule.Ports,
+			AuthorizedUser: desiredRule.AuthorizedUser,
+		}
+		for _, portRange := range desiredRule.PortRanges {
+			rule.PortRanges = append(rule.PortRanges, types.RulePortRange{Start: portRange.Start, End: portRange.End})
+		}
+		if rule.Sources, err = s.groupIDs(desiredRule.Sources); err != nil {
+			return nil, err
+		}
+		if rule.Destinations, err = s.groupIDs(desiredRule.Destinations); err != nil {
+			return nil, err
+		}
+		if rule.SourceResource, err = s.toRuleResource(desiredRule.SourceResource); err != nil {
+			return nil, err
+		}
+		if rule.DestinationResource, err = s.toRuleResource(desiredRule.DestinationResource); err != nil {
+			return nil, err
+		}
+		if len(desiredRule.AuthorizedGroups) > 0 {
+			rule.AuthorizedGroups = make(map[string][]string, len(desiredRule.AuthorizedGroups))
+			for name, users := range desiredRule.AuthorizedGroups {
+				groupID, err := s.groupID(name)
+				if err != nil {
+					return nil, err
+				}
+				rule.AuthorizedGroups[groupID] = users
+			}
+		}
+		policy.Rules = append(policy.Rules, rule)
+	}
+
+	return policy, nil
+}
+
+func (s *state) toRuleResource(resource *accountconfig.RuleResource) (types.Resource, error) {
+	if resource == nil {
+		return types.Resource{}, nil
+	}
+
+	if resource.Type == accountconfig.RuleResourceTypePeer {
+		peerID, err := s.peerID(resource.Name)
+		if err != nil {
+			return types.Resource{}, err
+		}
+		return types.Resource{ID: peerID, Type: types.ResourceTypePeer}, nil
+	}
+
+	networkResource, err := s.resourceByName(resource.Name)
+	if err != nil {
+		return types.Resource{}, err
+	}
+	return types.Resource{ID: networkResource.ID, Type: types.ResourceType(networkResource.Type.String())}, nil
+}
+
+func (s *state) toRoute(desired *accountconfig.Route) (*route.Route, error) {
+	r := &route.Route{
+		NetID:         route.NetID(desired.NetworkID),
+		Description:   desired.Description,
+		KeepRoute:     desired.KeepRoute,
+		Metric:        desired.Metric,
+		Masquerade:    desired.Masquerade,
+		Enabled:       desired.Enabled,
+		SkipAutoApply: desired.SkipAutoApply,
+	}
+
+	var err error
+	if len(desired.Domains) > 0 {
+		if r.Domains, err = domain.ValidateDomains(desired.Domains); err != nil {
+			return nil, status.Errorf(status.InvalidArgument, "invalid domains: %v", err)
+		}
+		r.NetworkType = route.DomainNetwork
+	} else {
+		if r.NetworkType, r.Network, err = route.ParseNetwork(desired.Network); err != nil {
+			return nil, err
+		}
+	}
+
+	if desired.Peer != "" {
+		if r.Peer, err = s.peerID(desired.Peer); err != nil {
+			return nil, err
+		}
+	}
+	if len(desired.PeerGroups) > 0 {
+		if r.PeerGroups, err = s.groupIDs(desired.PeerGroups); err != nil {
+			return nil, err
+		}
+	}
+	if r.Groups, err = s.groupIDs(desired.Groups); err != nil {
+		return nil, err
+	}
+	if len(desired.AccessControlGroups) > 0 {
+		if r.AccessControlGroups, err = s.groupIDs(desired.AccessControlGroups); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+// validateRoute runs the validation of the route manager against the planned state and adds the route to it
+func (s *state) validateRoute(r *route.Route) error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+
+	if len(r.Groups) == 0 {
+		return status.Errorf(status.InvalidArgument, "the list of group IDs should not be empty")
+	}
+
+	if err := types.ValidateRouteConflicts(r, s.routes, s.groupsByID, s.peersByID); err != nil {
+		return err
+	}
+
+	s.routes = slices.DeleteFunc(s.routes, func(existing *route.Route) bool { return existing.ID == r.ID })
+	s.routes = append(s.routes, r)
+	return nil
+}
+
+// validateNameserverGroup runs the validation of the nameserver group manager against the planned state and adds the
+// nameserver group to it
+func (s *state) validateNameserverGroup(nsGroup *nbdns.NameServerGroup) error {
+	if err := nsGroup.Validate(); err != nil {
+		return err
+	}
+
+	if len(nsGroup.Groups) == 0 {
+		return status.Errorf(status.InvalidArgument, "the list of group IDs should not be empty")
+	}
+
+	for _, existing := range s.nsGroups {
+		if existing.Name == nsGroup.Name && existing.ID != nsGroup.ID {
+			return status.Errorf(status.InvalidArgument, "nameserver group with name %s already exist", nsGroup.Name)
+		}
+	}
+
+	s.nsGroups = slices.DeleteFunc(s.nsGroups, func(existing *nbdns.NameServerGroup) bool { return existing.ID == nsGroup.ID })
+	s.nsGroups = append(s.nsGroups, nsGroup)
+	return nil
+}
+
+// validateNewResource runs the validation of the resources manager for a new resource against the planned state
+func (s *state) validateNewResource(resource *resourceTypes.NetworkResource) (*resourceTypes.NetworkResource, error) {
+	if _, err := s.resourceByName(resource.Name); err == nil {
+		return nil, status.Errorf(status.InvalidArgument, "resource with name %s already exists", resource.Name)
+	}
+
+	resource, err := resourceTypes.NewNetworkResource(resource.AccountID, resource.NetworkID, resource.Name, resource.Description,
+		resource.Address, resource.GroupIDs, resource.Enabled)
+	if err != nil {
+		return nil, status.Errorf(status.InvalidArgument, "%s", err.Error())
+	}
+	return resource, nil
+}
+
+// replaceGroup replaces the group with the same ID in the planned state
+func (s *state) replaceGroup(group *types.Group) {
+	for i, existing := range s.groups {
+		if existing.ID == group.ID {
+			s.groups[i] = group
+		}
+	}
+	s.index()
+}
+
+func (s *state) networkByName(name string) *networkTypes.Network {
+	for _, network := range s.networks {
+		if network.Name == name {
+			return network
+		}
+	}
+	return nil
+}
+
+func (s *state) zoneByDomain(domain string) *zones.Zone {
+	for _, zone := range s.zones {
+		if zone.Domain == domain {
+			return zone
+		}
+	}
+	return nil
+}
+
+// plannedID is the ID of an object created by the change during a dry run
+func plannedID(change *accountconfig.Change) string {
+	return "planned:" + string(change.Kind) + ":" + change.Parent + "/" + change.Name
+}
+
+// find returns the document object with the key, which the plan guarantees to exist
+func find[T any](items []T, key string, keyFunc func(T) string) T {
+	for _, item := range items {
+		if keyFunc(item) == key {
+			return item
+		}
+	}
+	var empty T
+	return empty
+}
@@ -0,0 +1,105 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/management/internals/modules/accountconfig"
+	"github.com/netbirdio/netbird/management/internals/modules/zones"
+	"github.com/netbirdio/netbird/management/internals/modules/zones/records"
+	"github.com/netbirdio/netbird/management/server/account"
+	"github.com/netbirdio/netbird/management/server/networks"
+	"github.com/netbirdio/netbird/management/server/networks/resources"
+	"github.com/netbirdio/netbird/management/server/networks/routers"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+// managerImpl reads and changes the account configuration through the managers of each kind of object,
+// so that permissions, validation and activity events are the same as for the individual API endpoints.
+type managerImpl struct {
+	accountManager   account.Manager
+	networksManager  networks.Manager
+	resourcesManager resources.Manager
+	routersManager   routers.Manager
+	zonesManager     zones.Manager
+	recordsManager   records.Manager
+}
+
+func NewManager(accountManager account.Manager, networksManager networks.Manager, resourcesManager resources.Manager, routersManager routers.Manager, zonesManager zones.Manager, recordsManager records.Manager) accountconfig.Manager {
+	return &managerImpl{
+		accountManager:   accountManager,
+		networksManager:  networksManager,
+		resourcesManager: resourcesManager,
+		routersManager:   routersManager,
+		zonesManager:     zonesManager,
+		recordsManager:   recordsManager,
+	}
+}
+
+func (m *managerImpl) Export(ctx context.Context, accountID, userID string) (*accountconfig.Document, error) {
+	current, err := m.loadState(ctx, accountID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return current.document(), nil
+}
+
+func (m *managerImpl) Plan(ctx context.Context, accountID, userID string, document *accountconfig.Document) ([]*accountconfig.Change, error) {
+	changes, _, err := m.plan(ctx, accountID, userID, document)
+	return changes, err
+}
+
+func (m *managerImpl) Apply(ctx context.Context, accountID, userID string, document *accountconfig.Document) ([]*accountconfig.Change, error) {
+	changes, current, err := m.plan(ctx, accountID, userID, document)
+	if err != nil {
+		return nil, err
+	}
+
+	// every change is first resolved and checked by the validators of the managers against the planned state,
+	// so that an invalid document doesn't leave the account half configured. The changes are applied one by one
+	// afterwards, a failure of the store on the way still leaves the preceding changes applied
+	planned := current.dryRunCopy()
+	for _, change := range changes {
+		if err := m.applyChange(ctx, accountID, userID, planned, document, change); err != nil {
+			return nil, changeError(change, err)
+		}
+	}
+
+	for i, change := range changes {
+		if err := m.applyChange(ctx, accountID, userID, current, document, change); err != nil {
+			log.WithContext(ctx).Errorf("failed to apply account configuration, %d of %d changes applied: %v", i, len(changes), err)
+			return nil, changeError(change, err)
+		}
+	}
+
+	return changes, nil
+}
+
+func (m *managerImpl) plan(ctx context.Context, accountID, userID string, document *accountconfig.Document) ([]*accountconfig.Change, *state, error) {
+	if err := document.Validate(); err != nil {
+		return nil, nil, status.Errorf(status.InvalidArgument, "%s", err.Error())
+	}
+	document.Normalize()
+
+	current, err := m.loadState(ctx, accountID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return accountconfig.Diff(current.document(), document), current, nil
+}
+
+func changeError(change *accountconfig.Change, err error) error {
+	name := change.Name
+	if change.Parent != "" {
+		name = change.Parent + "/" + name
+	}
+
+	if sErr, ok := status.FromError(err); ok {
+		return status.Errorf(sErr.Type(), "failed to %s %s %q: %s", change.Action, change.Kind, name, sErr.Message)
+	}
+	return fmt.Errorf("failed to %s %s %q: %w", change.Action, change.Kind, name, err)
+}
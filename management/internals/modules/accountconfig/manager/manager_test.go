@@ -0,0 +1,370 @@
+package manager
+
+import (
+	"context"
+	"net/netip"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/management/internals/modules/accountconfig"
+	zonesManager "github.com/netbirdio/netbird/management/internals/modules/zones/manager"
+	recordsManager "github.com/netbirdio/netbird/management/internals/modules/zones/records/manager"
+	"github.com/netbirdio/netbird/management/server/mock_server"
+	"github.com/netbirdio/netbird/management/server/networks"
+	"github.com/netbirdio/netbird/management/server/networks/resources"
+	"github.com/netbirdio/netbird/management/server/networks/routers"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/permissions"
+	"github.com/netbirdio/netbird/management/server/posture"
+	"github.com/netbirdio/netbird/management/server/store"
+	"github.com/netbirdio/netbird/management/server/types"
+	"github.com/netbirdio/netbird/route"
+	"github.com/netbirdio/netbird/shared/management/domain"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+const (
+	testAccountID = "test-account-id"
+	testUserID    = "test-user-id"
+	testGroupID   = "test-group-id"
+	testPeerID    = "test-peer-id"
+)
+
+func setupTest(t *testing.T) (*managerImpl, *mock_server.MockAccountManager) {
+	t.Helper()
+
+	ctx := context.Background()
+	testStore, cleanup, err := store.NewTestStoreFromSQL(ctx, "", t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(cleanup)
+
+	err = testStore.SaveAccount(ctx, &types.Account{
+		Id: testAccountID,
+		Groups: map[string]*types.Group{
+			testGroupID: {
+				ID:   testGroupID,
+				Name: "devs",
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	mockPermissionsManager := permissions.NewMockManager(ctrl)
+	mockPermissionsManager.EXPECT().
+		ValidateUserPermissions(gomock.Any(), testAccountID, testUserID, gomock.Any(), gomock.Any()).
+		Return(true, nil).
+		AnyTimes()
+
+	groups := []*types.Group{
+		{ID: "group-all", Name: "All", Issued: types.GroupIssuedAPI, Peers: []string{testPeerID}},
+		{ID: testGroupID, Name: "devs", Issued: types.GroupIssuedAPI, Peers: []string{testPeerID}},
+		{ID: "jwt-group-id", Name: "admins", Issued: types.GroupIssuedJWT},
+	}
+
+	mockAccountManager := &mock_server.MockAccountManager{
+		GetAllGroupsFunc: func(_ context.Context, _, _ string) ([]*types.Group, error) {
+			return groups, nil
+		},
+		GetPeersFunc: func(_ context.Context, _, _, _, _ string) ([]*nbpeer.Peer, error) {
+			return []*nbpeer.Peer{{ID: testPeerID, Name: "laptop"}}, nil
+		},
+		ListPostureChecksFunc: func(_ context.Context, _, _ string) ([]*posture.Checks, error) {
+			return []*posture.Checks{}, nil
+		},
+		ListPoliciesFunc: func(_ context.Context, _, _ string) ([]*types.Policy, error) {
+			return []*types.Policy{{
+				ID:      "policy-id",
+				Name:    "devs to devs",
+				Enabled: true,
+				Rules: []*types.PolicyRule{{
+					ID:            "rule-id",
+					Name:          "ssh",
+					Enabled:       true,
+					Action:        types.PolicyTrafficActionAccept,
+					Bidirectional: true,
+					Protocol:      types.PolicyRuleProtocolTCP,
+					Ports:         []string{"22"},
+					Sources:       []string{testGroupID},
+					Destinations:  []string{testGroupID},
+				}},
+			}}, nil
+		},
+		ListRoutesFunc: func(_ context.Context, _, _ string) ([]*route.Route, error) {
+			return []*route.Route{}, nil
+		},
+	}
+
+	manager := &managerImpl{
+		accountManager:   mockAccountManager,
+		networksManager:  networks.NewManagerMock(),
+		resourcesManager: resources.NewManagerMock(),
+		routersManager:   routers.NewManagerMock(),
+		zonesManager:     zonesManager.NewManager(testStore, mockAccountManager, mockPermissionsManager, ""),
+		recordsManager:   recordsManager.NewManager(testStore, mockAccountManager, mockPermissionsManager),
+	}
+
+	return manager, mockAccountManager
+}
+
+func TestManagerImpl_Export(t *testing.T) {
+	manager, _ := setupTest(t)
+
+	document, err := manager.Export(context.Background(), testAccountID, testUserID)
+	require.NoError(t, err)
+
+	assert.Equal(t, accountconfig.DocumentVersion, document.Version)
+	assert.Equal(t, []*accountconfig.Group{{Name: "devs", Peers: []string{"laptop"}}}, document.Groups, "only API groups other than All are exported")
+	require.Len(t, document.Policies, 1)
+	assert.Equal(t, []string{"devs"}, document.Policies[0].Rules[0].Sources)
+	assert.Equal(t, []string{"22"}, document.Policies[0].Rules[0].Ports)
+	assert.NotNil(t, document.Zones)
+	assert.Empty(t, document.Zones)
+}
+
+func TestManagerImpl_Plan(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("invalid document", func(t *testing.T) {
+		manager, _ := setupTest(t)
+
+		_, err := manager.Plan(ctx, testAccountID, testUserID, &accountconfig.Document{Version: "v0"})
+		require.Error(t, err)
+		s, ok := status.FromError(err)
+		require.True(t, ok)
+		assert.Equal(t, status.InvalidArgument, s.Type())
+	})
+
+	t.Run("exported document has no changes", func(t *testing.T) {
+		manager, _ := setupTest(t)
+
+		document, err := manager.Export(ctx, testAccountID, testUserID)
+		require.NoError(t, err)
+
+		changes, err := manager.Plan(ctx, testAccountID, testUserID, document)
+		require.NoError(t, err)
+		assert.Empty(t, changes)
+	})
+}
+
+func TestManagerImpl_Apply(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		manager, mockAccountManager := setupTest(t)
+
+		var savedGroups []*types.Group
+		mockAccountManager.SaveGroupFunc = func(_ context.Context, _, _ string, group *types.Group, create bool) error {
+			if create {
+				group.ID = "created-group-id"
+			}
+			savedGroups = append(savedGroups, group)
+			return nil
+		}
+		var savedPolicy *types.Policy
+		mockAccountManager.SavePolicyFunc = func(_ context.Context, _, _ string, policy *types.Policy, _ bool) (*types.Policy, error) {
+			savedPolicy = policy
+			return policy, nil
+		}
+
+		document := &accountconfig.Document{
+			Version: accountconfig.DocumentVersion,
+			Groups: []*accountconfig.Group{
+				{Name: "devs", Peers: []string{"laptop"}},
+				{Name: "ops"},
+			},
+			Policies: []*accountconfig.Policy{{
+				Name:    "devs to devs",
+				Enabled: true,
+				Rules: []*accountconfig.PolicyRule{{
+					Name:          "ssh",
+					Enabled:       true,
+					Action:        string(types.PolicyTrafficActionAccept),
+					Bidirectional: true,
+					Protocol:      string(types.PolicyRuleProtocolTCP),
+					Ports:         []string{"22", "2222"},
+					Sources:       []string{"devs"},
+					Destinations:  []string{"ops"},
+				}},
+			}},
+			Zones: []*accountconfig.Zone{{
+				Domain:             "example.internal",
+				Name:               "internal",
+				Enabled:            true,
+				DistributionGroups: []string{"devs"},
+				Records:            []*accountconfig.Record{{Name: "db.example.internal", Type: "A", Content: "10.0.0.1", TTL: 300}},
+			}},
+		}
+
+		changes, err := manager.Apply(ctx, testAccountID, testUserID, document)
+		require.NoError(t, err)
+
+		assert.Equal(t, []*accountconfig.Change{
+			{Kind: accountconfig.KindGroup, Action: accountconfig.ActionCreate, Name: "ops"},
+			{Kind: accountconfig.KindPolicy, Action: accountconfig.ActionUpdate, Name: "devs to devs"},
+			{Kind: accountconfig.KindZone, Action: accountconfig.ActionCreate, Name: "example.internal"},
+			{Kind: accountconfig.KindRecord, Action: accountconfig.ActionCreate, Name: "db.example.internal A 10.0.0.1", Parent: "example.internal"},
+		}, changes)
+
+		require.Len(t, savedGroups, 1)
+		assert.Equal(t, "ops", savedGroups[0].Name)
+		assert.Equal(t, types.GroupIssuedAPI, savedGroups[0].Issued)
+
+		require.NotNil(t, savedPolicy)
+		assert.Equal(t, "policy-id", savedPolicy.ID)
+		require.Len(t, savedPolicy.Rules, 1)
+		assert.Equal(t, "rule-id", savedPolicy.Rules[0].ID, "rule IDs are kept for rules with the same name")
+		assert.Equal(t, []string{"created-group-id"}, savedPolicy.Rules[0].Destinations)
+
+		zones, err := manager.zonesManager.GetAllZones(ctx, testAccountID, testUserID)
+		require.NoError(t, err)
+		require.Len(t, zones, 1)
+		assert.Equal(t, []string{testGroupID}, zones[0].DistributionGroups)
+		require.Len(t, zones[0].Records, 1)
+		assert.Equal(t, "10.0.0.1", zones[0].Records[0].Content)
+	})
+
+	t.Run("unknown reference", func(t *testing.T) {
+		manager, _ := setupTest(t)
+
+		document := &accountconfig.Document{
+			Version: accountconfig.DocumentVersion,
+			Groups:  []*accountconfig.Group{{Name: "devs", Peers: []string{"unknown"}}},
+		}
+
+		_, err := manager.Apply(ctx, testAccountID, testUserID, document)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `failed to update group "devs"`)
+	})
+
+	t.Run("invalid change leaves the account unchanged", func(t *testing.T) {
+		manager, mockAccountManager := setupTest(t)
+
+		mockAccountManager.SaveGroupFunc = func(_ context.Context, _, _ string, group *types.Group, _ bool) error {
+			t.Errorf("group %q saved although a later change is invalid", group.Name)
+			return nil
+		}
+		mockAccountManager.SavePolicyFunc = func(_ context.Context, _, _ string, policy *types.Policy, _ bool) (*types.Policy, error) {
+			t.Errorf("policy %q saved although a later change is invalid", policy.Name)
+			return policy, nil
+		}
+
+		document := &accountconfig.Document{
+			Version: accountconfig.DocumentVersion,
+			Groups: []*accountconfig.Group{
+				{Name: "devs", Peers: []string{"laptop"}},
+				{Name: "ops"},
+			},
+			Policies: []*accountconfig.Policy{{
+				Name:    "devs to devs",
+				Enabled: true,
+				Rules: []*accountconfig.PolicyRule{{
+					Name:          "ssh",
+					Enabled:       true,
+					Action:        string(types.PolicyTrafficActionAccept),
+					Bidirectional: true,
+					Protocol:      string(types.PolicyRuleProtocolTCP),
+					Ports:         []string{"22"},
+					Sources:       []string{"devs"},
+					Destinations:  []string{"ops"},
+				}},
+			}},
+			Zones: []*accountconfig.Zone{{
+				Domain:             "example.internal",
+				Name:               "internal",
+				Enabled:            true,
+				DistributionGroups: []string{"unknown"},
+			}},
+		}
+
+		_, err := manager.Apply(ctx, testAccountID, testUserID, document)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `failed to create zone "example.internal"`)
+
+		zones, err := manager.zonesManager.GetAllZones(ctx, testAccountID, testUserID)
+		require.NoError(t, err)
+		assert.Empty(t, zones)
+	})
+
+	t.Run("manager validators run in the dry run", func(t *testing.T) {
+		newRoute := func(networkID, peer string, peerGroups []string, metric int) *accountconfig.Route {
+			return &accountconfig.Route{
+				NetworkID:  networkID,
+				Network:    "10.0.0.0/24",
+				Peer:       peer,
+				PeerGroups: peerGroups,
+				Metric:     metric,
+				Enabled:    true,
+				Groups:     []string{"devs"},
+			}
+		}
+
+		testCases := []struct {
+			name          string
+			document      *accountconfig.Document
+			expectedError string
+		}{
+			{
+				name: "route metric out of range",
+				document: &accountconfig.Document{
+					Routes: []*accountconfig.Route{newRoute("office", "laptop", nil, 0)},
+				},
+				expectedError: "metric should be between",
+			},
+			{
+				name: "route already routed by the group of the peer",
+				document: &accountconfig.Document{
+					Routes: []*accountconfig.Route{
+						newRoute("office", "", []string{"devs"}, 9999),
+						newRoute("office-ha", "laptop", nil, 9999),
+					},
+				},
+				expectedError: "already has this route",
+			},
+			{
+				name: "nameserver group without nameservers",
+				document: &accountconfig.Document{
+					NameserverGroups: []*accountconfig.NameserverGroup{{
+						Name:    "google",
+						Groups:  []string{"devs"},
+						Primary: true,
+						Enabled: true,
+					}},
+				},
+				expectedError: "the list of nameservers should be 1 or 3",
+			},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				manager, mockAccountManager := setupTest(t)
+
+				mockAccountManager.SaveGroupFunc = func(_ context.Context, _, _ string, group *types.Group, _ bool) error {
+					t.Errorf("group %q saved although a later change is invalid", group.Name)
+					return nil
+				}
+				mockAccountManager.CreateRouteFunc = func(_ context.Context, _ string, _ netip.Prefix, _ route.NetworkType, _ domain.List, _ string, _ []string, _ string, netID route.NetID, _ bool, _ int, _, _ []string, _ bool, _ string, _ bool, _ bool) (*route.Route, error) {
+					t.Errorf("route %q created although a change is invalid", netID)
+					return &route.Route{}, nil
+				}
+
+				tc.document.Version = accountconfig.DocumentVersion
+				tc.document.Groups = []*accountconfig.Group{
+					{Name: "devs", Peers: []string{"laptop"}},
+					{Name: "ops"},
+				}
+
+				_, err := manager.Apply(ctx, testAccountID, testUserID, tc.document)
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+
+				sErr, ok := status.FromError(err)
+				require.True(t, ok)
+				assert.Contains(t, []status.Type{status.InvalidArgument, status.AlreadyExists}, sErr.Type())
+			})
+		}
+	})
+}
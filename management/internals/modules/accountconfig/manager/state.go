@@ -0,0 +1,507 @@
+package manager
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+
+	nbdns "github.com/netbirdio/netbird/dns"
+	"github.com/netbirdio/netbird/management/internals/modules/accountconfig"
+	"github.com/netbirdio/netbird/management/internals/modules/zones"
+	"github.com/netbirdio/netbird/management/internals/modules/zones/records"
+	resourceTypes "github.com/netbirdio/netbird/management/server/networks/resources/types"
+	routerTypes "github.com/netbirdio/netbird/management/server/networks/routers/types"
+	networkTypes "github.com/netbirdio/netbird/management/server/networks/types"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/posture"
+	"github.com/netbirdio/netbird/management/server/types"
+	"github.com/netbirdio/netbird/route"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+// state is the current account configuration with the lookups needed to translate IDs to names and back
+type state struct {
+	groups    []*types.Group
+	peers     []*nbpeer.Peer
+	checks    []*posture.Checks
+	policies  []*types.Policy
+	routes    []*route.Route
+	nsGroups  []*nbdns.NameServerGroup
+	networks  []*networkTypes.Network
+	resources []*resourceTypes.NetworkResource
+	routers   map[string][]*routerTypes.NetworkRouter
+	zones     []*zones.Zone
+
+	// dryRun marks a copy of the state the plan is validated against without changing the account
+	dryRun bool
+
+	groupsByID    map[string]*types.Group
+	groupsByName  map[string]*types.Group
+	peersByID     map[string]*nbpeer.Peer
+	peersByName   map[string][]*nbpeer.Peer
+	checksByID    map[string]*posture.Checks
+	checksByName  map[string]*posture.Checks
+	resourcesByID map[string]*resourceTypes.NetworkResource
+}
+
+func (m *managerImpl) loadState(ctx context.Context, accountID, userID string) (*state, error) {
+	var err error
+	s := &state{}
+
+	if s.groups, err = m.accountManager.GetAllGroups(ctx, accountID, userID); err != nil {
+		return nil, err
+	}
+	if s.peers, err = m.accountManager.GetPeers(ctx, accountID, userID, "", ""); err != nil {
+		return nil, err
+	}
+	if s.checks, err = m.accountManager.ListPostureChecks(ctx, accountID, userID); err != nil {
+		return nil, err
+	}
+	if s.policies, err = m.accountManager.ListPolicies(ctx, accountID, userID); err != nil {
+		return nil, err
+	}
+	if s.routes, err = m.accountManager.ListRoutes(ctx, accountID, userID); err != nil {
+		return nil, err
+	}
+	if s.nsGroups, err = m.accountManager.ListNameServerGroups(ctx, accountID, userID); err != nil {
+		return nil, err
+	}
+	if s.networks, err = m.networksManager.GetAllNetworks(ctx, accountID, userID); err != nil {
+		return nil, err
+	}
+	if s.resources, err = m.resourcesManager.GetAllResourcesInAccount(ctx, accountID, userID); err != nil {
+		return nil, err
+	}
+	if s.routers, err = m.routersManager.GetAllRoutersInAccount(ctx, accountID, userID); err != nil {
+		return nil, err
+	}
+	if s.zones, err = m.zonesManager.GetAllZones(ctx, accountID, userID); err != nil {
+		return nil, err
+	}
+
+	s.index()
+
+	return s, nil
+}
+
+func (s *state) index() {
+	s.groupsByID = make(map[string]*types.Group, len(s.groups))
+	s.groupsByName = make(map[string]*types.Group, len(s.groups))
+	for _, group := range s.groups {
+		s.groupsByID[group.ID] = group
+		// names of JWT and integration groups are not unique, the API group takes precedence
+		if existing, ok := s.groupsByName[group.Name]; !ok || existing.Issued != types.GroupIssuedAPI {
+			s.groupsByName[group.Name] = group
+		}
+	}
+
+	s.peersByID = make(map[string]*nbpeer.Peer, len(s.peers))
+	s.peersByName = make(map[string][]*nbpeer.Peer, len(s.peers))
+	for _, peer := range s.peers {
+		s.peersByID[peer.ID] = peer
+		s.peersByName[peer.Name] = append(s.peersByName[peer.Name], peer)
+	}
+
+	s.checksByID = make(map[string]*posture.Checks, len(s.checks))
+	s.checksByName = make(map[string]*posture.Checks, len(s.checks))
+	for _, check := range s.checks {
+		s.checksByID[check.ID] = check
+		s.checksByName[check.Name] = check
+	}
+
+	s.resourcesByID = make(map[string]*resourceTypes.NetworkResource, len(s.resources))
+	for _, resource := range s.resources {
+		s.resourcesByID[resource.ID] = resource
+	}
+}
+
+// dryRunCopy returns a copy of the state that changes can be validated against without affecting the original
+func (s *state) dryRunCopy() *state {
+	c := &state{
+		groups:    slices.Clone(s.groups),
+		peers:     slices.Clone(s.peers),
+		checks:    slices.Clone(s.checks),
+		policies:  slices.Clone(s.policies),
+		routes:    slices.Clone(s.routes),
+		nsGroups:  slices.Clone(s.nsGroups),
+		networks:  slices.Clone(s.networks),
+		resources: slices.Clone(s.resources),
+		routers:   maps.Clone(s.routers),
+		zones:     slices.Clone(s.zones),
+		dryRun:    true,
+	}
+	c.index()
+	return c
+}
+
+// document returns the current configuration as a normalized document
+func (s *state) document() *accountconfig.Document {
+	document := &accountconfig.Document{
+		Version:          accountconfig.DocumentVersion,
+		Groups:           make([]*accountconfig.Group, 0),
+		Policies:         make([]*accountconfig.Policy, 0, len(s.policies)),
+		Routes:           make([]*accountconfig.Route, 0, len(s.routes)),
+		NameserverGroups: make([]*accountconfig.NameserverGroup, 0, len(s.nsGroups)),
+		Networks:         make([]*accountconfig.Network, 0, len(s.networks)),
+		Zones:            make([]*accountconfig.Zone, 0, len(s.zones)),
+	}
+
+	for _, group := range s.groups {
+		if group.Issued != types.GroupIssuedAPI || group.IsGroupAll() {
+			continue
+		}
+		document.Groups = append(document.Groups, &accountconfig.Group{
+			Name:  group.Name,
+			Peers: s.peerNames(group.Peers),
+		})
+	}
+
+	for _, policy := range s.policies {
+		document.Policies = append(document.Policies, s.toDocumentPolicy(policy))
+	}
+
+	for _, r := range s.routes {
+		document.Routes = append(document.Routes, s.toDocumentRoute(r))
+	}
+
+	for _, nsGroup := range s.nsGroups {
+		document.NameserverGroups = append(document.NameserverGroups, s.toDocumentNameserverGroup(nsGroup))
+	}
+
+	for _, network := range s.networks {
+		document.Networks = append(document.Networks, s.toDocumentNetwork(network))
+	}
+
+	for _, zone := range s.zones {
+		document.Zones = append(document.Zones, s.toDocumentZone(zone))
+	}
+
+	sortByKey(document.Groups, func(g *accountconfig.Group) string { return g.Name })
+	sortByKey(document.Policies, func(p *accountconfig.Policy) string { return p.Name })
+	sortByKey(document.Routes, (*accountconfig.Route).Key)
+	sortByKey(document.NameserverGroups, func(g *accountconfig.NameserverGroup) string { return g.Name })
+	sortByKey(document.Networks, func(n *accountconfig.Network) string { return n.Name })
+	sortByKey(document.Zones, func(z *accountconfig.Zone) string { return z.Domain })
+
+	document.Normalize()
+
+	return document
+}
+
+func (s *state) toDocumentPolicy(policy *types.Policy) *accountconfig.Policy {
+	p := &accountconfig.Policy{
+		Name:                policy.Name,
+		Description:         policy.Description,
+		Enabled:             policy.Enabled,
+		SourcePostureChecks: make([]string, 0, len(policy.SourcePostureChecks)),
+		Rules:               make([]*accountconfig.PolicyRule, 0, len(policy.Rules)),
+	}
+
+	for _, checkID := range policy.SourcePostureChecks {
+		name := checkID
+		if check, ok := s.checksByID[checkID]; ok {
+			name = check.Name
+		}
+		p.SourcePostureChecks = append(p.SourcePostureChecks, name)
+	}
+
+	if policy.Schedule != nil {
+		p.Schedule = &accountconfig.PolicySchedule{
+			NotBefore: policy.Schedule.NotBefore,
+			NotAfter:  policy.Schedule.NotAfter,
+			TimeZone:  policy.Schedule.TimeZone,
+		}
+		for _, window := range policy.Schedule.Windows {
+			days := make([]string, 0, len(window.Days))
+			for _, day := range window.Days {
+				days = append(days, accountconfig.WeekdayName(day))
+			}
+			p.Schedule.Windows = append(p.Schedule.Windows, accountconfig.PolicyScheduleWindow{
+				Days:  days,
+				Start: window.Start,
+				End:   window.End,
+			})
+		}
+	}
+
+	for _, rule := range policy.Rules {
+		r := &accountconfig.PolicyRule{
+			Name:                rule.Name,
+			Description:         rule.Description,
+			Enabled:             rule.Enabled,
+			Action:              string(rule.Action),
+			Bidirectional:       rule.Bidirectional,
+			Protocol:            string(rule.Protocol),
+			Ports:               rule.Ports,
+			Sources:             s.groupNames(rule.Sources),
+			Destinations:        s.groupNames(rule.Destinations),
+			SourceResource:      s.toDocumentRuleResource(rule.SourceResource),
+			DestinationResource: s.toDocumentRuleResource(rule.DestinationResource),
+			AuthorizedUser:      rule.AuthorizedUser,
+		}
+		for _, portRange := range rule.PortRanges {
+			r.PortRanges = append(r.PortRanges, accountconfig.PortRange{Start: portRange.Start, End: portRange.End})
+		}
+		if len(rule.AuthorizedGroups) > 0 {
+			r.AuthorizedGroups = make(map[string][]string, len(rule.AuthorizedGroups))
+			for groupID, users := range rule.AuthorizedGroups {
+				r.AuthorizedGroups[s.groupName(groupID)] = users
+			}
+		}
+		p.Rules = append(p.Rules, r)
+	}
+
+	return p
+}
+
+func (s *state) toDocumentRuleResource(resource types.Resource) *accountconfig.RuleResource {
+	if resource.ID == "" {
+		return nil
+	}
+
+	if resource.Type == types.ResourceTypePeer {
+		return &accountconfig.RuleResource{Type: accountconfig.RuleResourceTypePeer, Name: s.peerName(resource.ID)}
+	}
+
+	name := resource.ID
+	if r, ok := s.resourcesByID[resource.ID]; ok {
+		name = r.Name
+	}
+	return &accountconfig.RuleResource{Type: string(resource.Type), Name: name}
+}
+
+func (s *state) toDocumentRoute(r *route.Route) *accountconfig.Route {
+	documentRoute := &accountconfig.Route{
+		NetworkID:           string(r.NetID),
+		Description:         r.Description,
+		KeepRoute:           r.KeepRoute,
+		PeerGroups:          s.groupNames(r.PeerGroups),
+		Metric:              r.Metric,
+		Masquerade:          r.Masquerade,
+		Enabled:             r.Enabled,
+		Groups:              s.groupNames(r.Groups),
+		AccessControlGroups: s.groupNames(r.AccessControlGroups),
+		SkipAutoApply:       r.SkipAutoApply,
+	}
+
+	if r.IsDynamic() {
+		documentRoute.Domains = r.Domains.ToSafeStringList()
+	} else {
+		documentRoute.Network = r.Network.String()
+	}
+
+	if r.Peer != "" {
+		documentRoute.Peer = s.peerName(r.Peer)
+	}
+
+	return documentRoute
+}
+
+func (s *state) toDocumentNameserverGroup(nsGroup *nbdns.NameServerGroup) *accountconfig.NameserverGroup {
+	g := &accountconfig.NameserverGroup{
+		Name:                 nsGroup.Name,
+		Description:          nsGroup.Description,
+		Groups:               s.groupNames(nsGroup.Groups),
+		Primary:              nsGroup.Primary,
+		Domains:              nsGroup.Domains,
+		Enabled:              nsGroup.Enabled,
+		SearchDomainsEnabled: nsGroup.SearchDomainsEnabled,
+	}
+
+	for _, ns := range nsGroup.NameServers {
+		g.Nameservers = append(g.Nameservers, accountconfig.Nameserver{
+			IP:     ns.IP.String(),
+			NSType: ns.NSType.String(),
+			Port:   ns.Port,
+		})
+	}
+
+	return g
+}
+
+func (s *state) toDocumentNetwork(network *networkTypes.Network) *accountconfig.Network {
+	n := &accountconfig.Network{
+		Name:        network.Name,
+		Description: network.Description,
+	}
+
+	for _, resource := range s.resources {
+		if resource.NetworkID != network.ID {
+			continue
+		}
+		address := resource.Prefix.String()
+		if resource.Type == resourceTypes.Domain {
+			address = resource.Domain
+		}
+		n.Resources = append(n.Resources, &accountconfig.NetworkResource{
+			Name:        resource.Name,
+			Description: resource.Description,
+			Address:     address,
+			Groups:      s.resourceGroupNames(resource.ID),
+			Enabled:     resource.Enabled,
+		})
+	}
+
+	for _, router := range s.routers[network.ID] {
+		n.Routers = append(n.Routers, s.toDocumentRouter(router))
+	}
+
+	sortByKey(n.Resources, func(r *accountconfig.NetworkResource) string { return r.Name })
+	sortByKey(n.Routers, (*accountconfig.NetworkRouter).Key)
+
+	return n
+}
+
+func (s *state) toDocumentRouter(router *routerTypes.NetworkRouter) *accountconfig.NetworkRouter {
+	r := &accountconfig.NetworkRouter{
+		PeerGroups: s.groupNames(router.PeerGroups),
+		Masquerade: router.Masquerade,
+		Metric:     router.Metric,
+		Enabled:    router.Enabled,
+	}
+	if router.Peer != "" {
+		r.Peer = s.peerName(router.Peer)
+	}
+	return r
+}
+
+func (s *state) toDocumentZone(zone *zones.Zone) *accountconfig.Zone {
+	z := &accountconfig.Zone{
+		Domain:             zone.Domain,
+		Name:               zone.Name,
+		Enabled:            zone.Enabled,
+		EnableSearchDomain: zone.EnableSearchDomain,
+		DistributionGroups: s.groupNames(zone.DistributionGroups),
+	}
+
+	for _, record := range zone.Records {
+		z.Records = append(z.Records, s.toDocumentRecord(record))
+	}
+
+	sortByKey(z.Records, (*accountconfig.Record).Key)
+
+	return z
+}
+
+func (s *state) toDocumentRecord(record *records.Record) *accountconfig.Record {
+	return &accountconfig.Record{
+		Name:    record.Name,
+		Type:    string(record.Type),
+		Content: record.Content,
+		TTL:     record.TTL,
+	}
+}
+
+func (s *state) groupName(groupID string) string {
+	if group, ok := s.groupsByID[groupID]; ok {
+		return group.Name
+	}
+	return groupID
+}
+
+func (s *state) groupNames(groupIDs []string) []string {
+	names := make([]string, 0, len(groupIDs))
+	for _, groupID := range groupIDs {
+		names = append(names, s.groupName(groupID))
+	}
+	return names
+}
+
+func (s *state) peerName(peerID string) string {
+	if peer, ok := s.peersByID[peerID]; ok {
+		return peer.Name
+	}
+	return peerID
+}
+
+func (s *state) peerNames(peerIDs []string) []string {
+	names := make([]string, 0, len(peerIDs))
+	for _, peerID := range peerIDs {
+		names = append(names, s.peerName(peerID))
+	}
+	return names
+}
+
+func (s *state) resourceGroupNames(resourceID string) []string {
+	var names []string
+	for _, group := range s.groups {
+		if slices.ContainsFunc(group.Resources, func(r types.Resource) bool { return r.ID == resourceID }) {
+			names = append(names, group.Name)
+		}
+	}
+	return names
+}
+
+// groupID resolves a group name of the document
+func (s *state) groupID(name string) (string, error) {
+	group, ok := s.groupsByName[name]
+	if !ok {
+		return "", status.Errorf(status.InvalidArgument, "group %q not found", name)
+	}
+	return group.ID, nil
+}
+
+func (s *state) groupIDs(names []string) ([]string, error) {
+	ids := make([]string, 0, len(names))
+	for _, name := range names {
+		id, err := s.groupID(name)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// peerID resolves a peer name of the document, which must identify a single peer
+func (s *state) peerID(name string) (string, error) {
+	peers := s.peersByName[name]
+	switch len(peers) {
+	case 0:
+		return "", status.Errorf(status.InvalidArgument, "peer %q not found", name)
+	case 1:
+		return peers[0].ID, nil
+	default:
+		return "", status.Errorf(status.InvalidArgument, "peer name %q is ambiguous, %d peers have it", name, len(peers))
+	}
+}
+
+func (s *state) peerIDs(names []string) ([]string, error) {
+	ids := make([]string, 0, len(names))
+	for _, name := range names {
+		id, err := s.peerID(name)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (s *state) postureCheckIDs(names []string) ([]string, error) {
+	ids := make([]string, 0, len(names))
+	for _, name := range names {
+		check, ok := s.checksByName[name]
+		if !ok {
+			return nil, status.Errorf(status.InvalidArgument, "posture check %q not found", name)
+		}
+		ids = append(ids, check.ID)
+	}
+	return ids, nil
+}
+
+func (s *state) resourceByName(name string) (*resourceTypes.NetworkResource, error) {
+	for _, resource := range s.resources {
+		if resource.Name == name {
+			return resource, nil
+		}
+	}
+	return nil, status.Errorf(status.InvalidArgument, "network resource %q not found", name)
+}
+
+func sortByKey[T any](items []T, key func(T) string) {
+	slices.SortFunc(items, func(a, b T) int {
+		return strings.Compare(key(a), key(b))
+	})
+}
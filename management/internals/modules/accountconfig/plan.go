@@ -0,0 +1,195 @@
+package accountconfig
+
+import (
+	"reflect"
+
+	"github.com/netbirdio/netbird/shared/management/http/api"
+)
+
+type Kind string
+
+const (
+	KindGroup           Kind = "group"
+	KindPolicy          Kind = "policy"
+	KindRoute           Kind = "route"
+	KindNameserverGroup Kind = "nameserver_group"
+	KindNetwork         Kind = "network"
+	KindNetworkResource Kind = "network_resource"
+	KindNetworkRouter   Kind = "network_router"
+	KindZone            Kind = "zone"
+	KindRecord          Kind = "dns_record"
+)
+
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+// Change is a single change required to make the account configuration match a document
+type Change struct {
+	Kind   Kind   `json:"kind" yaml:"kind"`
+	Action Action `json:"action" yaml:"action"`
+	// Name is the key of the object in the document
+	Name string `json:"name" yaml:"name"`
+	// Parent is the network name or zone domain of nested objects
+	Parent string `json:"parent,omitempty" yaml:"parent,omitempty"`
+}
+
+// Diff returns the changes required to turn the current configuration into the desired one, in the order
+// they have to be applied: objects are created and updated before the objects referencing them,
+// and deleted after. Sections omitted from the desired document are skipped.
+// Both documents are expected to be normalized.
+func Diff(current, desired *Document) []*Change {
+	var upserts []*Change
+	var groupDeletes, networkDeletes, nestedNetworkDeletes, policyDeletes, routeDeletes, nsGroupDeletes, zoneDeletes []*Change
+
+	if desired.Groups != nil {
+		var u []*Change
+		u, groupDeletes = diffItems(KindGroup, "", current.Groups, desired.Groups, func(g *Group) string { return g.Name }, equal[*Group])
+		upserts = append(upserts, u...)
+	}
+
+	if desired.Networks != nil {
+		var u []*Change
+		u, networkDeletes, nestedNetworkDeletes = diffNetworks(current.Networks, desired.Networks)
+		upserts = append(upserts, u...)
+	}
+
+	if desired.Policies != nil {
+		var u []*Change
+		u, policyDeletes = diffItems(KindPolicy, "", current.Policies, desired.Policies, func(p *Policy) string { return p.Name }, equal[*Policy])
+		upserts = append(upserts, u...)
+	}
+
+	if desired.Routes != nil {
+		var u []*Change
+		u, routeDeletes = diffItems(KindRoute, "", current.Routes, desired.Routes, (*Route).Key, equal[*Route])
+		upserts = append(upserts, u...)
+	}
+
+	if desired.NameserverGroups != nil {
+		var u []*Change
+		u, nsGroupDeletes = diffItems(KindNameserverGroup, "", current.NameserverGroups, desired.NameserverGroups, func(g *NameserverGroup) string { return g.Name }, equal[*NameserverGroup])
+		upserts = append(upserts, u...)
+	}
+
+	if desired.Zones != nil {
+		var u []*Change
+		u, zoneDeletes = diffZones(current.Zones, desired.Zones)
+		upserts = append(upserts, u...)
+	}
+
+	// objects are deleted in the reverse order, once nothing references them anymore
+	changes := upserts
+	for _, deletes := range [][]*Change{zoneDeletes, nsGroupDeletes, routeDeletes, policyDeletes, nestedNetworkDeletes, networkDeletes, groupDeletes} {
+		changes = append(changes, deletes...)
+	}
+
+	return changes
+}
+
+func diffNetworks(current, desired []*Network) (upserts, networkDeletes, nestedDeletes []*Change) {
+	currentByName := make(map[string]*Network, len(current))
+	for _, network := range current {
+		currentByName[network.Name] = network
+	}
+
+	sameNetwork := func(a, b *Network) bool {
+		return a.Description == b.Description
+	}
+	upserts, networkDeletes = diffItems(KindNetwork, "", current, desired, func(n *Network) string { return n.Name }, sameNetwork)
+
+	for _, network := range desired {
+		existing, ok := currentByName[network.Name]
+		if !ok {
+			existing = &Network{}
+		}
+
+		resourceUpserts, resourceDeletes := diffItems(KindNetworkResource, network.Name, existing.Resources, network.Resources, func(r *NetworkResource) string { return r.Name }, equal[*NetworkResource])
+		routerUpserts, routerDeletes := diffItems(KindNetworkRouter, network.Name, existing.Routers, network.Routers, (*NetworkRouter).Key, equal[*NetworkRouter])
+
+		upserts = append(upserts, resourceUpserts...)
+		upserts = append(upserts, routerUpserts...)
+		nestedDeletes = append(nestedDeletes, routerDeletes...)
+		nestedDeletes = append(nestedDeletes, resourceDeletes...)
+	}
+
+	return upserts, networkDeletes, nestedDeletes
+}
+
+func diffZones(current, desired []*Zone) (upserts, deletes []*Change) {
+	currentByDomain := make(map[string]*Zone, len(current))
+	for _, zone := range current {
+		currentByDomain[zone.Domain] = zone
+	}
+
+	sameZone := func(a, b *Zone) bool {
+		return a.Name == b.Name && a.Enabled == b.Enabled && a.EnableSearchDomain == b.EnableSearchDomain &&
+			reflect.DeepEqual(a.DistributionGroups, b.DistributionGroups)
+	}
+	upserts, deletes = diffItems(KindZone, "", current, desired, func(z *Zone) string { return z.Domain }, sameZone)
+
+	var recordDeletes []*Change
+	for _, zone := range desired {
+		existing, ok := currentByDomain[zone.Domain]
+		if !ok {
+			existing = &Zone{}
+		}
+
+		recordUpserts, d := diffItems(KindRecord, zone.Domain, existing.Records, zone.Records, (*Record).Key, equal[*Record])
+		upserts = append(upserts, recordUpserts...)
+		recordDeletes = append(recordDeletes, d...)
+	}
+
+	return upserts, append(recordDeletes, deletes...)
+}
+
+// diffItems compares the objects of a kind by key and returns the creates and updates, followed by the deletes
+func diffItems[T any](kind Kind, parent string, current, desired []T, key func(T) string, same func(a, b T) bool) (upserts, deletes []*Change) {
+	currentByKey := make(map[string]T, len(current))
+	for _, item := range current {
+		currentByKey[key(item)] = item
+	}
+
+	desiredKeys := make(map[string]struct{}, len(desired))
+	for _, item := range desired {
+		k := key(item)
+		desiredKeys[k] = struct{}{}
+
+		existing, ok := currentByKey[k]
+		switch {
+		case !ok:
+			upserts = append(upserts, &Change{Kind: kind, Action: ActionCreate, Name: k, Parent: parent})
+		case !same(existing, item):
+			upserts = append(upserts, &Change{Kind: kind, Action: ActionUpdate, Name: k, Parent: parent})
+		}
+	}
+
+	for _, item := range current {
+		k := key(item)
+		if _, ok := desiredKeys[k]; !ok {
+			deletes = append(deletes, &Change{Kind: kind, Action: ActionDelete, Name: k, Parent: parent})
+		}
+	}
+
+	return upserts, deletes
+}
+
+func equal[T any](a, b T) bool {
+	return reflect.DeepEqual(a, b)
+}
+
+func (c *Change) ToAPIResponse() api.AccountConfigChange {
+	change := api.AccountConfigChange{
+		Kind:   api.AccountConfigChangeKind(c.Kind),
+		Action: api.AccountConfigChangeAction(c.Action),
+		Name:   c.Name,
+	}
+	if c.Parent != "" {
+		change.Parent = &c.Parent
+	}
+	return change
+}
@@ -0,0 +1,99 @@
+package accountconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	current := &Document{
+		Version: DocumentVersion,
+		Groups:  []*Group{{Name: "devs", Peers: []string{"laptop"}}, {Name: "legacy"}},
+		Policies: []*Policy{
+			{Name: "devs to servers", Enabled: true, Rules: []*PolicyRule{{Name: "ssh", Protocol: "tcp", Ports: []string{"22"}}}},
+			{Name: "legacy", Enabled: true},
+		},
+		Networks: []*Network{{
+			Name:      "office",
+			Resources: []*NetworkResource{{Name: "db", Address: "10.0.0.1", Groups: []string{"devs"}}},
+			Routers:   []*NetworkRouter{{Peer: "router", Enabled: true}},
+		}},
+		Zones: []*Zone{{
+			Domain:  "example.internal",
+			Name:    "internal",
+			Records: []*Record{{Name: "db.example.internal", Type: "A", Content: "10.0.0.1", TTL: 300}},
+		}},
+	}
+
+	desired := &Document{
+		Version: DocumentVersion,
+		Groups:  []*Group{{Name: "devs", Peers: []string{"laptop", "desktop"}}, {Name: "ops"}},
+		Policies: []*Policy{
+			{Name: "devs to servers", Enabled: true, Rules: []*PolicyRule{{Name: "ssh", Protocol: "tcp", Ports: []string{"22"}}}},
+		},
+		Networks: []*Network{{
+			Name:      "office",
+			Resources: []*NetworkResource{{Name: "web", Address: "10.0.0.2", Groups: []string{"ops"}}},
+			Routers:   []*NetworkRouter{{Peer: "router", Enabled: false}},
+		}},
+		Zones: []*Zone{{
+			Domain:  "example.internal",
+			Name:    "internal",
+			Records: []*Record{{Name: "db.example.internal", Type: "A", Content: "10.0.0.1", TTL: 60}},
+		}},
+	}
+
+	changes := Diff(current, desired)
+
+	assert.Equal(t, []*Change{
+		{Kind: KindGroup, Action: ActionUpdate, Name: "devs"},
+		{Kind: KindGroup, Action: ActionCreate, Name: "ops"},
+		{Kind: KindNetworkResource, Action: ActionCreate, Name: "web", Parent: "office"},
+		{Kind: KindNetworkRouter, Action: ActionUpdate, Name: "peer router", Parent: "office"},
+		{Kind: KindRecord, Action: ActionUpdate, Name: "db.example.internal A 10.0.0.1", Parent: "example.internal"},
+		{Kind: KindPolicy, Action: ActionDelete, Name: "legacy"},
+		{Kind: KindNetworkResource, Action: ActionDelete, Name: "db", Parent: "office"},
+		{Kind: KindGroup, Action: ActionDelete, Name: "legacy"},
+	}, changes)
+}
+
+func TestDiff_UnmanagedSections(t *testing.T) {
+	current := &Document{
+		Version:  DocumentVersion,
+		Groups:   []*Group{{Name: "devs"}},
+		Policies: []*Policy{{Name: "devs to servers"}},
+		Routes:   []*Route{{NetworkID: "office", Network: "10.0.0.0/24", Peer: "router"}},
+	}
+
+	t.Run("omitted sections are left untouched", func(t *testing.T) {
+		desired := &Document{Version: DocumentVersion, Groups: []*Group{{Name: "devs"}}}
+		assert.Empty(t, Diff(current, desired))
+	})
+
+	t.Run("empty sections delete everything", func(t *testing.T) {
+		desired := &Document{Version: DocumentVersion, Routes: []*Route{}}
+		assert.Equal(t, []*Change{{Kind: KindRoute, Action: ActionDelete, Name: "office via peer router"}}, Diff(current, desired))
+	})
+
+	t.Run("same document makes no changes", func(t *testing.T) {
+		assert.Empty(t, Diff(current, current))
+	})
+}
+
+func TestDiff_CreatedNetwork(t *testing.T) {
+	desired := &Document{
+		Version: DocumentVersion,
+		Networks: []*Network{{
+			Name:      "office",
+			Resources: []*NetworkResource{{Name: "db", Address: "10.0.0.1"}},
+			Routers:   []*NetworkRouter{{PeerGroups: []string{"routers"}}},
+		}},
+	}
+
+	assert.Equal(t, []*Change{
+		{Kind: KindNetwork, Action: ActionCreate, Name: "office"},
+		{Kind: KindNetworkResource, Action: ActionCreate, Name: "db", Parent: "office"},
+		{Kind: KindNetworkRouter, Action: ActionCreate, Name: "groups routers", Parent: "office"},
+	}, Diff(&Document{}, desired))
+}
@@ -0,0 +1,161 @@
+package manager
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/netbirdio/netbird/management/internals/modules/customroles"
+	nbcontext "github.com/netbirdio/netbird/management/server/context"
+	"github.com/netbirdio/netbird/shared/management/http/api"
+	"github.com/netbirdio/netbird/shared/management/http/util"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+type handler struct {
+	manager customroles.Manager
+}
+
+func RegisterEndpoints(router *mux.Router, manager customroles.Manager) {
+	h := &handler{
+		manager: manager,
+	}
+
+	router.HandleFunc("/roles", h.getAllRoles).Methods("GET", "OPTIONS")
+	router.HandleFunc("/roles", h.createRole).Methods("POST", "OPTIONS")
+	router.HandleFunc("/roles/{roleId}", h.getRole).Methods("GET", "OPTIONS")
+	router.HandleFunc("/roles/{roleId}", h.updateRole).Methods("PUT", "OPTIONS")
+	router.HandleFunc("/roles/{roleId}", h.deleteRole).Methods("DELETE", "OPTIONS")
+}
+
+func (h *handler) getAllRoles(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	allRoles, err := h.manager.GetAllRoles(r.Context(), userAuth.AccountId, userAuth.UserId)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	apiRoles := make([]*api.Role, 0, len(allRoles))
+	for _, role := range allRoles {
+		apiRoles = append(apiRoles, role.ToAPIResponse())
+	}
+
+	util.WriteJSONObject(r.Context(), w, apiRoles)
+}
+
+func (h *handler) createRole(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	var req api.PostApiRolesJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.WriteErrorResponse("couldn't parse JSON request", http.StatusBadRequest, w)
+		return
+	}
+
+	role := new(customroles.Role)
+	role.FromAPIRequest(&req)
+
+	if err = role.Validate(); err != nil {
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "%s", err.Error()), w)
+		return
+	}
+
+	createdRole, err := h.manager.CreateRole(r.Context(), userAuth.AccountId, userAuth.UserId, role)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, createdRole.ToAPIResponse())
+}
+
+func (h *handler) getRole(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	roleID := mux.Vars(r)["roleId"]
+	if roleID == "" {
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "role ID is required"), w)
+		return
+	}
+
+	role, err := h.manager.GetRole(r.Context(), userAuth.AccountId, userAuth.UserId, roleID)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, role.ToAPIResponse())
+}
+
+func (h *handler) updateRole(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	roleID := mux.Vars(r)["roleId"]
+	if roleID == "" {
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "role ID is required"), w)
+		return
+	}
+
+	var req api.PutApiRolesRoleIdJSONRequestBody
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.WriteErrorResponse("couldn't parse JSON request", http.StatusBadRequest, w)
+		return
+	}
+
+	role := new(customroles.Role)
+	role.FromAPIRequest(&req)
+	role.ID = roleID
+
+	if err = role.Validate(); err != nil {
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "%s", err.Error()), w)
+		return
+	}
+
+	updatedRole, err := h.manager.UpdateRole(r.Context(), userAuth.AccountId, userAuth.UserId, role)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, updatedRole.ToAPIResponse())
+}
+
+func (h *handler) deleteRole(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	roleID := mux.Vars(r)["roleId"]
+	if roleID == "" {
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "role ID is required"), w)
+		return
+	}
+
+	if err = h.manager.DeleteRole(r.Context(), userAuth.AccountId, userAuth.UserId, roleID); err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, util.EmptyObject{})
+}
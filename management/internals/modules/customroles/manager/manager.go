@@ -0,0 +1,219 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/netbirdio/netbird/management/internals/modules/customroles"
+	"github.com/netbirdio/netbird/management/server/account"
+	"github.com/netbirdio/netbird/management/server/activity"
+	"github.com/netbirdio/netbird/management/server/permissions"
+	"github.com/netbirdio/netbird/management/server/permissions/modules"
+	"github.com/netbirdio/netbird/management/server/permissions/operations"
+	"github.com/netbirdio/netbird/management/server/store"
+	"github.com/netbirdio/netbird/management/server/types"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+type managerImpl struct {
+	store              store.Store
+	accountManager     account.Manager
+	permissionsManager permissions.Manager
+}
+
+func NewManager(store store.Store, accountManager account.Manager, permissionsManager permissions.Manager) customroles.Manager {
+	return &managerImpl{
+		store:              store,
+		accountManager:     accountManager,
+		permissionsManager: permissionsManager,
+	}
+}
+
+func (m *managerImpl) GetAllRoles(ctx context.Context, accountID, userID string) ([]*customroles.Role, error) {
+	ok, err := m.permissionsManager.ValidateUserPermissions(ctx, accountID, userID, modules.Roles, operations.Read)
+	if err != nil {
+		return nil, status.NewPermissionValidationError(err)
+	}
+	if !ok {
+		return nil, status.NewPermissionDeniedError()
+	}
+
+	return m.store.GetAccountCustomRoles(ctx, store.LockingStrengthNone, accountID)
+}
+
+func (m *managerImpl) GetRole(ctx context.Context, accountID, userID, roleID string) (*customroles.Role, error) {
+	ok, err := m.permissionsManager.ValidateUserPermissions(ctx, accountID, userID, modules.Roles, operations.Read)
+	if err != nil {
+		return nil, status.NewPermissionValidationError(err)
+	}
+	if !ok {
+		return nil, status.NewPermissionDeniedError()
+	}
+
+	return m.store.GetCustomRoleByID(ctx, store.LockingStrengthNone, accountID, roleID)
+}
+
+func (m *managerImpl) CreateRole(ctx context.Context, accountID, userID string, role *customroles.Role) (*customroles.Role, error) {
+	ok, err := m.permissionsManager.ValidateUserPermissions(ctx, accountID, userID, modules.Roles, operations.Create)
+	if err != nil {
+		return nil, status.NewPermissionValidationError(err)
+	}
+	if !ok {
+		return nil, status.NewPermissionDeniedError()
+	}
+
+	role = customroles.NewRole(accountID, role.Name, role.Description, role.Permissions)
+	err = m.store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+		if err = validateRoleNameConflict(ctx, transaction, accountID, role); err != nil {
+			return err
+		}
+
+		initiatorUser, err := transaction.GetUserByUserID(ctx, store.LockingStrengthNone, userID)
+		if err != nil {
+			return fmt.Errorf("failed to get initiator user: %w", err)
+		}
+
+		if err = validateRolePermissions(ctx, transaction, accountID, initiatorUser, role); err != nil {
+			return err
+		}
+
+		if err = transaction.CreateCustomRole(ctx, role); err != nil {
+			return fmt.Errorf("failed to create custom role: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	m.accountManager.StoreEvent(ctx, userID, role.ID, accountID, activity.CustomRoleCreated, role.EventMeta())
+
+	return role, nil
+}
+
+func (m *managerImpl) UpdateRole(ctx context.Context, accountID, userID string, updatedRole *customroles.Role) (*customroles.Role, error) {
+	ok, err := m.permissionsManager.ValidateUserPermissions(ctx, accountID, userID, modules.Roles, operations.Update)
+	if err != nil {
+		return nil, status.NewPermissionValidationError(err)
+	}
+	if !ok {
+		return nil, status.NewPermissionDeniedError()
+	}
+
+	var role *customroles.Role
+	err = m.store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+		role, err = transaction.GetCustomRoleByID(ctx, store.LockingStrengthUpdate, accountID, updatedRole.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get custom role: %w", err)
+		}
+
+		initiatorUser, err := transaction.GetUserByUserID(ctx, store.LockingStrengthNone, userID)
+		if err != nil {
+			return fmt.Errorf("failed to get initiator user: %w", err)
+		}
+
+		// users must not be able to widen the permissions of the role they hold themselves
+		if initiatorUser.Role == role.UserRole() {
+			return status.Errorf(status.PermissionDenied, "users can't update the role they are assigned to")
+		}
+
+		if role.Name != updatedRole.Name {
+			if err = validateRoleNameConflict(ctx, transaction, accountID, updatedRole); err != nil {
+				return err
+			}
+		}
+
+		role.Name = updatedRole.Name
+		role.Description = updatedRole.Description
+		role.Permissions = updatedRole.Permissions
+
+		if err = validateRolePermissions(ctx, transaction, accountID, initiatorUser, role); err != nil {
+			return err
+		}
+
+		if err = transaction.UpdateCustomRole(ctx, role); err != nil {
+			return fmt.Errorf("failed to update custom role: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	m.accountManager.StoreEvent(ctx, userID, role.ID, accountID, activity.CustomRoleUpdated, role.EventMeta())
+
+	return role, nil
+}
+
+func (m *managerImpl) DeleteRole(ctx context.Context, accountID, userID, roleID string) error {
+	ok, err := m.permissionsManager.ValidateUserPermissions(ctx, accountID, userID, modules.Roles, operations.Delete)
+	if err != nil {
+		return status.NewPermissionValidationError(err)
+	}
+	if !ok {
+		return status.NewPermissionDeniedError()
+	}
+
+	var role *customroles.Role
+	err = m.store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+		role, err = transaction.GetCustomRoleByID(ctx, store.LockingStrengthUpdate, accountID, roleID)
+		if err != nil {
+			return fmt.Errorf("failed to get custom role: %w", err)
+		}
+
+		users, err := transaction.GetAccountUsers(ctx, store.LockingStrengthNone, accountID)
+		if err != nil {
+			return fmt.Errorf("failed to get account users: %w", err)
+		}
+
+		for _, user := range users {
+			if user.Role == role.UserRole() {
+				return status.Errorf(status.PreconditionFailed, "custom role %s is assigned to user %s", role.Name, user.Id)
+			}
+		}
+
+		if err = transaction.DeleteCustomRole(ctx, accountID, roleID); err != nil {
+			return fmt.Errorf("failed to delete custom role: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	m.accountManager.StoreEvent(ctx, userID, roleID, accountID, activity.CustomRoleDeleted, role.EventMeta())
+
+	return nil
+}
+
+// validateRolePermissions ensures that the role doesn't grant any permission the initiator doesn't hold,
+// otherwise users allowed to manage roles could assign themselves or service users more permissions
+func validateRolePermissions(ctx context.Context, transaction store.Store, accountID string, initiatorUser *types.User, role *customroles.Role) error {
+	initiatorPermissions, err := permissions.GetRolePermissions(ctx, transaction, accountID, initiatorUser.Role)
+	if err != nil {
+		return fmt.Errorf("failed to get initiator permissions: %w", err)
+	}
+
+	if !initiatorPermissions.Covers(role.RolePermissions()) {
+		return status.Errorf(status.PermissionDenied, "custom role %s grants permissions the initiator doesn't have", role.Name)
+	}
+
+	return nil
+}
+
+func validateRoleNameConflict(ctx context.Context, transaction store.Store, accountID string, role *customroles.Role) error {
+	existingRole, err := transaction.GetCustomRoleByName(ctx, store.LockingStrengthNone, accountID, role.Name)
+	if err != nil {
+		if sErr, ok := status.FromError(err); !ok || sErr.Type() != status.NotFound {
+			return fmt.Errorf("failed to check existing custom role: %w", err)
+		}
+	}
+	if existingRole != nil {
+		return status.Errorf(status.AlreadyExists, "custom role with name %s already exists", role.Name)
+	}
+
+	return nil
+}
@@ -0,0 +1,428 @@
+package manager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/management/internals/modules/customroles"
+	"github.com/netbirdio/netbird/management/server/activity"
+	"github.com/netbirdio/netbird/management/server/mock_server"
+	"github.com/netbirdio/netbird/management/server/permissions"
+	"github.com/netbirdio/netbird/management/server/permissions/modules"
+	"github.com/netbirdio/netbird/management/server/permissions/operations"
+	"github.com/netbirdio/netbird/management/server/permissions/roles"
+	"github.com/netbirdio/netbird/management/server/store"
+	"github.com/netbirdio/netbird/management/server/types"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+const (
+	testAccountID = "test-account-id"
+	testUserID    = "test-user-id"
+	testRoleID    = "test-role-id"
+)
+
+var dnsOperatorPermissions = roles.Permissions{
+	modules.Dns: {
+		operations.Read:   true,
+		operations.Create: true,
+		operations.Update: true,
+		operations.Delete: true,
+	},
+	modules.Nameservers: {
+		operations.Read:   true,
+		operations.Create: true,
+		operations.Update: true,
+		operations.Delete: true,
+	},
+}
+
+func setupTest(t *testing.T) (*managerImpl, store.Store, *mock_server.MockAccountManager, *permissions.MockManager, *gomock.Controller, func()) {
+	t.Helper()
+
+	ctx := context.Background()
+	testStore, cleanup, err := store.NewTestStoreFromSQL(ctx, "", t.TempDir())
+	require.NoError(t, err)
+
+	err = testStore.SaveAccount(ctx, &types.Account{
+		Id: testAccountID,
+		Users: map[string]*types.User{
+			testUserID: {
+				Id:        testUserID,
+				AccountID: testAccountID,
+				Role:      types.UserRoleAdmin,
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	mockAccountManager := &mock_server.MockAccountManager{}
+	mockPermissionsManager := permissions.NewMockManager(ctrl)
+
+	manager := &managerImpl{
+		store:              testStore,
+		accountManager:     mockAccountManager,
+		permissionsManager: mockPermissionsManager,
+	}
+
+	return manager, testStore, mockAccountManager, mockPermissionsManager, ctrl, cleanup
+}
+
+func TestManagerImpl_GetAllRoles(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		manager, testStore, _, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+		defer cleanup()
+		defer ctrl.Finish()
+
+		role1 := customroles.NewRole(testAccountID, "dns-operator", "", dnsOperatorPermissions)
+		require.NoError(t, testStore.CreateCustomRole(ctx, role1))
+
+		role2 := customroles.NewRole(testAccountID, "helpdesk", "", roles.Permissions{})
+		require.NoError(t, testStore.CreateCustomRole(ctx, role2))
+
+		mockPermissionsManager.EXPECT().
+			ValidateUserPermissions(ctx, testAccountID, testUserID, modules.Roles, operations.Read).
+			Return(true, nil)
+
+		result, err := manager.GetAllRoles(ctx, testAccountID, testUserID)
+		require.NoError(t, err)
+		assert.Len(t, result, 2)
+	})
+
+	t.Run("permission denied", func(t *testing.T) {
+		manager, _, _, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+		defer cleanup()
+		defer ctrl.Finish()
+
+		mockPermissionsManager.EXPECT().
+			ValidateUserPermissions(ctx, testAccountID, testUserID, modules.Roles, operations.Read).
+			Return(false, nil)
+
+		result, err := manager.GetAllRoles(ctx, testAccountID, testUserID)
+		require.Error(t, err)
+		assert.Nil(t, result)
+		s, ok := status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, status.PermissionDenied, s.Type())
+	})
+}
+
+func TestManagerImpl_CreateRole(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		manager, testStore, mockAccountManager, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+		defer cleanup()
+		defer ctrl.Finish()
+
+		mockPermissionsManager.EXPECT().
+			ValidateUserPermissions(ctx, testAccountID, testUserID, modules.Roles, operations.Create).
+			Return(true, nil)
+
+		mockAccountManager.StoreEventFunc = func(ctx context.Context, initiatorID, targetID, accountID string, activityID activity.ActivityDescriber, meta map[string]any) {
+			assert.Equal(t, testUserID, initiatorID)
+			assert.Equal(t, testAccountID, accountID)
+			assert.Equal(t, activity.CustomRoleCreated, activityID)
+		}
+
+		result, err := manager.CreateRole(ctx, testAccountID, testUserID, &customroles.Role{
+			Name:        "dns-operator",
+			Description: "Manages DNS",
+			Permissions: dnsOperatorPermissions,
+		})
+		require.NoError(t, err)
+		assert.NotEmpty(t, result.ID)
+		assert.Equal(t, testAccountID, result.AccountID)
+
+		stored, err := testStore.GetCustomRoleByID(ctx, store.LockingStrengthNone, testAccountID, result.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "dns-operator", stored.Name)
+		assert.Equal(t, dnsOperatorPermissions, stored.Permissions)
+	})
+
+	t.Run("duplicate name", func(t *testing.T) {
+		manager, testStore, _, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+		defer cleanup()
+		defer ctrl.Finish()
+
+		require.NoError(t, testStore.CreateCustomRole(ctx, customroles.NewRole(testAccountID, "dns-operator", "", dnsOperatorPermissions)))
+
+		mockPermissionsManager.EXPECT().
+			ValidateUserPermissions(ctx, testAccountID, testUserID, modules.Roles, operations.Create).
+			Return(true, nil)
+
+		result, err := manager.CreateRole(ctx, testAccountID, testUserID, &customroles.Role{Name: "dns-operator"})
+		require.Error(t, err)
+		assert.Nil(t, result)
+		s, ok := status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, status.AlreadyExists, s.Type())
+	})
+
+	t.Run("permissions the initiator doesn't have", func(t *testing.T) {
+		manager, testStore, _, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+		defer cleanup()
+		defer ctrl.Finish()
+
+		roleManager := customroles.NewRole(testAccountID, "role-manager", "", roles.Permissions{
+			modules.Roles: {operations.Read: true, operations.Create: true},
+			modules.Users: {operations.Read: true, operations.Create: true},
+		})
+		require.NoError(t, testStore.CreateCustomRole(ctx, roleManager))
+
+		user, err := testStore.GetUserByUserID(ctx, store.LockingStrengthNone, testUserID)
+		require.NoError(t, err)
+		user.Role = roleManager.UserRole()
+		require.NoError(t, testStore.SaveUser(ctx, user))
+
+		mockPermissionsManager.EXPECT().
+			ValidateUserPermissions(ctx, testAccountID, testUserID, modules.Roles, operations.Create).
+			Return(true, nil)
+
+		result, err := manager.CreateRole(ctx, testAccountID, testUserID, &customroles.Role{
+			Name: "user-admin",
+			Permissions: roles.Permissions{
+				modules.Users: {
+					operations.Read:   true,
+					operations.Create: true,
+					operations.Update: true,
+					operations.Delete: true,
+				},
+			},
+		})
+		require.Error(t, err)
+		assert.Nil(t, result)
+		s, ok := status.FromError(err)
+		require.True(t, ok)
+		assert.Equal(t, status.PermissionDenied, s.Type())
+
+		storedRoles, err := testStore.GetAccountCustomRoles(ctx, store.LockingStrengthNone, testAccountID)
+		require.NoError(t, err)
+		assert.Len(t, storedRoles, 1)
+	})
+}
+
+func TestManagerImpl_UpdateRole(t *testing.T) {
+	ctx := context.Background()
+
+	manager, testStore, mockAccountManager, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+	defer cleanup()
+	defer ctrl.Finish()
+
+	role := customroles.NewRole(testAccountID, "dns-operator", "", dnsOperatorPermissions)
+	require.NoError(t, testStore.CreateCustomRole(ctx, role))
+
+	mockPermissionsManager.EXPECT().
+		ValidateUserPermissions(ctx, testAccountID, testUserID, modules.Roles, operations.Update).
+		Return(true, nil)
+
+	mockAccountManager.StoreEventFunc = func(ctx context.Context, initiatorID, targetID, accountID string, activityID activity.ActivityDescriber, meta map[string]any) {
+		assert.Equal(t, role.ID, targetID)
+		assert.Equal(t, activity.CustomRoleUpdated, activityID)
+	}
+
+	readOnly := roles.Permissions{
+		modules.Peers: {operations.Read: true},
+	}
+	result, err := manager.UpdateRole(ctx, testAccountID, testUserID, &customroles.Role{
+		ID:          role.ID,
+		Name:        "helpdesk",
+		Permissions: readOnly,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "helpdesk", result.Name)
+
+	stored, err := testStore.GetCustomRoleByID(ctx, store.LockingStrengthNone, testAccountID, role.ID)
+	require.NoError(t, err)
+	assert.Equal(t, readOnly, stored.Permissions)
+}
+
+func TestManagerImpl_UpdateRole_AssignedToInitiator(t *testing.T) {
+	ctx := context.Background()
+
+	manager, testStore, _, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+	defer cleanup()
+	defer ctrl.Finish()
+
+	role := customroles.NewRole(testAccountID, "role-manager", "", roles.Permissions{
+		modules.Roles: {operations.Read: true, operations.Update: true},
+	})
+	require.NoError(t, testStore.CreateCustomRole(ctx, role))
+
+	user, err := testStore.GetUserByUserID(ctx, store.LockingStrengthNone, testUserID)
+	require.NoError(t, err)
+	user.Role = role.UserRole()
+	require.NoError(t, testStore.SaveUser(ctx, user))
+
+	mockPermissionsManager.EXPECT().
+		ValidateUserPermissions(ctx, testAccountID, testUserID, modules.Roles, operations.Update).
+		Return(true, nil)
+
+	_, err = manager.UpdateRole(ctx, testAccountID, testUserID, &customroles.Role{
+		ID:          role.ID,
+		Name:        role.Name,
+		Permissions: dnsOperatorPermissions,
+	})
+	require.Error(t, err)
+	sErr, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, status.PermissionDenied, sErr.Type())
+
+	stored, err := testStore.GetCustomRoleByID(ctx, store.LockingStrengthNone, testAccountID, role.ID)
+	require.NoError(t, err)
+	assert.Equal(t, role.Permissions, stored.Permissions)
+}
+
+func TestManagerImpl_UpdateRole_PermissionsNotHeldByInitiator(t *testing.T) {
+	ctx := context.Background()
+
+	manager, testStore, _, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+	defer cleanup()
+	defer ctrl.Finish()
+
+	roleManager := customroles.NewRole(testAccountID, "role-manager", "", roles.Permissions{
+		modules.Roles: {operations.Read: true, operations.Update: true},
+		modules.Dns:   {operations.Read: true, operations.Update: true},
+	})
+	require.NoError(t, testStore.CreateCustomRole(ctx, roleManager))
+
+	user, err := testStore.GetUserByUserID(ctx, store.LockingStrengthNone, testUserID)
+	require.NoError(t, err)
+	user.Role = roleManager.UserRole()
+	require.NoError(t, testStore.SaveUser(ctx, user))
+
+	role := customroles.NewRole(testAccountID, "dns-reader", "", roles.Permissions{
+		modules.Dns: {operations.Read: true},
+	})
+	require.NoError(t, testStore.CreateCustomRole(ctx, role))
+
+	mockPermissionsManager.EXPECT().
+		ValidateUserPermissions(ctx, testAccountID, testUserID, modules.Roles, operations.Update).
+		Return(true, nil).
+		Times(2)
+
+	// permissions held by the initiator can be granted
+	_, err = manager.UpdateRole(ctx, testAccountID, testUserID, &customroles.Role{
+		ID:          role.ID,
+		Name:        role.Name,
+		Permissions: roles.Permissions{modules.Dns: {operations.Read: true, operations.Update: true}},
+	})
+	require.NoError(t, err)
+
+	_, err = manager.UpdateRole(ctx, testAccountID, testUserID, &customroles.Role{
+		ID:          role.ID,
+		Name:        role.Name,
+		Permissions: dnsOperatorPermissions,
+	})
+	require.Error(t, err)
+	sErr, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, status.PermissionDenied, sErr.Type())
+
+	stored, err := testStore.GetCustomRoleByID(ctx, store.LockingStrengthNone, testAccountID, role.ID)
+	require.NoError(t, err)
+	assert.Equal(t, roles.Permissions{modules.Dns: {operations.Read: true, operations.Update: true}}, stored.Permissions)
+}
+
+func TestManagerImpl_DeleteRole(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		manager, testStore, mockAccountManager, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+		defer cleanup()
+		defer ctrl.Finish()
+
+		role := customroles.NewRole(testAccountID, "dns-operator", "", dnsOperatorPermissions)
+		require.NoError(t, testStore.CreateCustomRole(ctx, role))
+
+		mockPermissionsManager.EXPECT().
+			ValidateUserPermissions(ctx, testAccountID, testUserID, modules.Roles, operations.Delete).
+			Return(true, nil)
+
+		mockAccountManager.StoreEventFunc = func(ctx context.Context, initiatorID, targetID, accountID string, activityID activity.ActivityDescriber, meta map[string]any) {
+			assert.Equal(t, activity.CustomRoleDeleted, activityID)
+		}
+
+		err := manager.DeleteRole(ctx, testAccountID, testUserID, role.ID)
+		require.NoError(t, err)
+
+		_, err = testStore.GetCustomRoleByID(ctx, store.LockingStrengthNone, testAccountID, role.ID)
+		require.Error(t, err)
+	})
+
+	t.Run("assigned to user", func(t *testing.T) {
+		manager, testStore, _, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+		defer cleanup()
+		defer ctrl.Finish()
+
+		role := customroles.NewRole(testAccountID, "dns-operator", "", dnsOperatorPermissions)
+		require.NoError(t, testStore.CreateCustomRole(ctx, role))
+
+		user := types.NewUser("dns-user", role.UserRole(), false, false, "", nil, types.UserIssuedAPI, "", "")
+		user.AccountID = testAccountID
+		require.NoError(t, testStore.SaveUser(ctx, user))
+
+		mockPermissionsManager.EXPECT().
+			ValidateUserPermissions(ctx, testAccountID, testUserID, modules.Roles, operations.Delete).
+			Return(true, nil)
+
+		err := manager.DeleteRole(ctx, testAccountID, testUserID, role.ID)
+		require.Error(t, err)
+		s, ok := status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, status.PreconditionFailed, s.Type())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		manager, _, _, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+		defer cleanup()
+		defer ctrl.Finish()
+
+		mockPermissionsManager.EXPECT().
+			ValidateUserPermissions(ctx, testAccountID, testUserID, modules.Roles, operations.Delete).
+			Return(true, nil)
+
+		err := manager.DeleteRole(ctx, testAccountID, testUserID, testRoleID)
+		require.Error(t, err)
+	})
+}
+
+func TestCustomRole_ValidateUserPermissions(t *testing.T) {
+	ctx := context.Background()
+
+	testStore, cleanup, err := store.NewTestStoreFromSQL(ctx, "", t.TempDir())
+	require.NoError(t, err)
+	defer cleanup()
+
+	role := customroles.NewRole(testAccountID, "dns-operator", "", dnsOperatorPermissions)
+	user := types.NewUser("dns-user", role.UserRole(), false, false, "", nil, types.UserIssuedAPI, "", "")
+	user.AccountID = testAccountID
+
+	require.NoError(t, testStore.SaveAccount(ctx, &types.Account{
+		Id:    testAccountID,
+		Users: map[string]*types.User{user.Id: user},
+	}))
+	require.NoError(t, testStore.CreateCustomRole(ctx, role))
+
+	permissionsManager := permissions.NewManager(testStore)
+
+	allowed, err := permissionsManager.ValidateUserPermissions(ctx, testAccountID, user.Id, modules.Dns, operations.Update)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	allowed, err = permissionsManager.ValidateUserPermissions(ctx, testAccountID, user.Id, modules.Peers, operations.Read)
+	require.NoError(t, err)
+	assert.False(t, allowed, "modules that are not listed in a custom role must be denied")
+
+	require.NoError(t, testStore.DeleteCustomRole(ctx, testAccountID, role.ID))
+
+	_, err = permissionsManager.ValidateUserPermissions(ctx, testAccountID, user.Id, modules.Dns, operations.Read)
+	require.Error(t, err)
+}
@@ -0,0 +1,124 @@
+package customroles
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/rs/xid"
+
+	"github.com/netbirdio/netbird/management/server/permissions/modules"
+	"github.com/netbirdio/netbird/management/server/permissions/operations"
+	"github.com/netbirdio/netbird/management/server/permissions/roles"
+	"github.com/netbirdio/netbird/management/server/types"
+	"github.com/netbirdio/netbird/shared/management/http/api"
+)
+
+// Role is an account defined role that grants operations on a set of permission modules.
+// Users reference it through a types.UserRole created with types.NewCustomUserRole.
+type Role struct {
+	ID          string `gorm:"primaryKey"`
+	AccountID   string `gorm:"index"`
+	Name        string
+	Description string
+	Permissions roles.Permissions `gorm:"serializer:json"`
+}
+
+func NewRole(accountID, name, description string, permissions roles.Permissions) *Role {
+	return &Role{
+		ID:          xid.New().String(),
+		AccountID:   accountID,
+		Name:        name,
+		Description: description,
+		Permissions: permissions,
+	}
+}
+
+// UserRole returns the value that assigns this role to a user
+func (r *Role) UserRole() types.UserRole {
+	return types.NewCustomUserRole(r.ID)
+}
+
+// RolePermissions converts the role to the representation evaluated by the permissions manager.
+// Custom roles never grant access to modules that are not explicitly listed.
+func (r *Role) RolePermissions() roles.RolePermissions {
+	return roles.RolePermissions{
+		Role:        r.UserRole(),
+		Permissions: r.Permissions,
+		AutoAllowNew: map[operations.Operation]bool{
+			operations.Read:   false,
+			operations.Create: false,
+			operations.Update: false,
+			operations.Delete: false,
+		},
+	}
+}
+
+func (r *Role) ToAPIResponse() *api.Role {
+	permissions := make(api.RolePermissions, len(r.Permissions))
+	for module, ops := range r.Permissions {
+		apiOps := make(map[string]bool, len(ops))
+		for op, allowed := range ops {
+			apiOps[string(op)] = allowed
+		}
+		permissions[string(module)] = apiOps
+	}
+
+	var description *string
+	if r.Description != "" {
+		description = &r.Description
+	}
+
+	return &api.Role{
+		Id:          r.ID,
+		Name:        r.Name,
+		Description: description,
+		Permissions: permissions,
+		UserRole:    string(r.UserRole()),
+	}
+}
+
+func (r *Role) FromAPIRequest(req *api.RoleRequest) {
+	r.Name = req.Name
+	r.Description = ""
+	if req.Description != nil {
+		r.Description = *req.Description
+	}
+
+	r.Permissions = make(roles.Permissions, len(req.Permissions))
+	for module, ops := range req.Permissions {
+		permissionOps := make(map[operations.Operation]bool, len(ops))
+		for op, allowed := range ops {
+			permissionOps[operations.Operation(op)] = allowed
+		}
+		r.Permissions[modules.Module(module)] = permissionOps
+	}
+}
+
+func (r *Role) Validate() error {
+	if r.Name == "" {
+		return errors.New("role name is required")
+	}
+	if len(r.Name) > 255 {
+		return errors.New("role name exceeds maximum length of 255 characters")
+	}
+	if _, ok := roles.RolesMap[types.UserRole(r.Name)]; ok {
+		return fmt.Errorf("role name %s is reserved for a built-in role", r.Name)
+	}
+
+	for module, ops := range r.Permissions {
+		if _, ok := modules.All[module]; !ok {
+			return fmt.Errorf("unknown permission module %s", module)
+		}
+		for op := range ops {
+			if _, ok := operations.All[op]; !ok {
+				return fmt.Errorf("unknown operation %s on module %s", op, module)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (r *Role) EventMeta() map[string]any {
+	return map[string]any{"name": r.Name}
+}
@@ -0,0 +1,21 @@
+package peerapproval
+
+import (
+	"context"
+)
+
+type Manager interface {
+	// GetPendingPeers returns the peers waiting for approval with the context of their registration
+	GetPendingPeers(ctx context.Context, accountID, userID string) ([]*PendingPeer, error)
+	// ApprovePeers approves the pending peers once the integrated validator accepted their approval,
+	// nothing is approved if one of the peers isn't pending approval or its approval isn't accepted
+	ApprovePeers(ctx context.Context, accountID, userID string, peerIDs []string, reason string) error
+	// RejectPeers deletes the pending peers, nothing is rejected if one of the peers isn't pending approval or can't be deleted
+	RejectPeers(ctx context.Context, accountID, userID string, peerIDs []string, reason string) error
+
+	GetAllRules(ctx context.Context, accountID, userID string) ([]*Rule, error)
+	GetRule(ctx context.Context, accountID, userID, ruleID string) (*Rule, error)
+	CreateRule(ctx context.Context, accountID, userID string, rule *Rule) (*Rule, error)
+	UpdateRule(ctx context.Context, accountID, userID string, rule *Rule) (*Rule, error)
+	DeleteRule(ctx context.Context, accountID, userID, ruleID string) error
+}
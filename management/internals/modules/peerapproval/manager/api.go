@@ -0,0 +1,235 @@
+package manager
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/netbirdio/netbird/management/internals/modules/peerapproval"
+	nbcontext "github.com/netbirdio/netbird/management/server/context"
+	"github.com/netbirdio/netbird/shared/management/http/api"
+	"github.com/netbirdio/netbird/shared/management/http/util"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+type handler struct {
+	manager peerapproval.Manager
+}
+
+// RegisterEndpoints registers the peer approval queue and the peer approval rule endpoints in the management API
+func RegisterEndpoints(router *mux.Router, manager peerapproval.Manager) {
+	h := &handler{
+		manager: manager,
+	}
+
+	router.HandleFunc("/peer-approvals", h.getPendingPeers).Methods("GET", "OPTIONS")
+	router.HandleFunc("/peer-approvals/approve", h.approvePeers).Methods("POST", "OPTIONS")
+	router.HandleFunc("/peer-approvals/reject", h.rejectPeers).Methods("POST", "OPTIONS")
+	router.HandleFunc("/peer-approval-rules", h.getAllRules).Methods("GET", "OPTIONS")
+	router.HandleFunc("/peer-approval-rules", h.createRule).Methods("POST", "OPTIONS")
+	router.HandleFunc("/peer-approval-rules/{ruleId}", h.getRule).Methods("GET", "OPTIONS")
+	router.HandleFunc("/peer-approval-rules/{ruleId}", h.updateRule).Methods("PUT", "OPTIONS")
+	router.HandleFunc("/peer-approval-rules/{ruleId}", h.deleteRule).Methods("DELETE", "OPTIONS")
+}
+
+func (h *handler) getPendingPeers(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	pendingPeers, err := h.manager.GetPendingPeers(r.Context(), userAuth.AccountId, userAuth.UserId)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	apiPendingPeers := make([]*api.PendingPeer, 0, len(pendingPeers))
+	for _, pendingPeer := range pendingPeers {
+		apiPendingPeers = append(apiPendingPeers, pendingPeer.ToAPIResponse())
+	}
+
+	util.WriteJSONObject(r.Context(), w, apiPendingPeers)
+}
+
+func (h *handler) approvePeers(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	var req api.PostApiPeerApprovalsApproveJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.WriteErrorResponse("couldn't parse JSON request", http.StatusBadRequest, w)
+		return
+	}
+
+	if err = h.manager.ApprovePeers(r.Context(), userAuth.AccountId, userAuth.UserId, req.PeerIds, decisionReason(req)); err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, util.EmptyObject{})
+}
+
+func (h *handler) rejectPeers(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	var req api.PostApiPeerApprovalsRejectJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.WriteErrorResponse("couldn't parse JSON request", http.StatusBadRequest, w)
+		return
+	}
+
+	if err = h.manager.RejectPeers(r.Context(), userAuth.AccountId, userAuth.UserId, req.PeerIds, decisionReason(req)); err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, util.EmptyObject{})
+}
+
+func (h *handler) getAllRules(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	rules, err := h.manager.GetAllRules(r.Context(), userAuth.AccountId, userAuth.UserId)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	apiRules := make([]*api.PeerApprovalRule, 0, len(rules))
+	for _, rule := range rules {
+		apiRules = append(apiRules, rule.ToAPIResponse())
+	}
+
+	util.WriteJSONObject(r.Context(), w, apiRules)
+}
+
+func (h *handler) createRule(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	var req api.PostApiPeerApprovalRulesJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.WriteErrorResponse("couldn't parse JSON request", http.StatusBadRequest, w)
+		return
+	}
+
+	rule := new(peerapproval.Rule)
+	rule.FromAPIRequest(&req)
+
+	if err = rule.Validate(); err != nil {
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "%s", err.Error()), w)
+		return
+	}
+
+	createdRule, err := h.manager.CreateRule(r.Context(), userAuth.AccountId, userAuth.UserId, rule)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, createdRule.ToAPIResponse())
+}
+
+func (h *handler) getRule(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	ruleID := mux.Vars(r)["ruleId"]
+	if ruleID == "" {
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "invalid rule ID"), w)
+		return
+	}
+
+	rule, err := h.manager.GetRule(r.Context(), userAuth.AccountId, userAuth.UserId, ruleID)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, rule.ToAPIResponse())
+}
+
+func (h *handler) updateRule(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	ruleID := mux.Vars(r)["ruleId"]
+	if ruleID == "" {
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "invalid rule ID"), w)
+		return
+	}
+
+	var req api.PutApiPeerApprovalRulesRuleIdJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.WriteErrorResponse("couldn't parse JSON request", http.StatusBadRequest, w)
+		return
+	}
+
+	rule := new(peerapproval.Rule)
+	rule.FromAPIRequest(&req)
+	rule.ID = ruleID
+
+	if err = rule.Validate(); err != nil {
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "%s", err.Error()), w)
+		return
+	}
+
+	updatedRule, err := h.manager.UpdateRule(r.Context(), userAuth.AccountId, userAuth.UserId, rule)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, updatedRule.ToAPIResponse())
+}
+
+func (h *handler) deleteRule(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	ruleID := mux.Vars(r)["ruleId"]
+	if ruleID == "" {
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "invalid rule ID"), w)
+		return
+	}
+
+	if err := h.manager.DeleteRule(r.Context(), userAuth.AccountId, userAuth.UserId, ruleID); err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, util.EmptyObject{})
+}
+
+func decisionReason(req api.PeerApprovalDecision) string {
+	if req.Reason == nil {
+		return ""
+	}
+	return *req.Reason
+}
@@ -0,0 +1,241 @@
+package manager
+
+import (
+	"context"
+	"slices"
+
+	"github.com/netbirdio/netbird/management/internals/modules/peerapproval"
+	"github.com/netbirdio/netbird/management/server/account"
+	"github.com/netbirdio/netbird/management/server/activity"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/permissions"
+	"github.com/netbirdio/netbird/management/server/permissions/modules"
+	"github.com/netbirdio/netbird/management/server/permissions/operations"
+	"github.com/netbirdio/netbird/management/server/store"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+type managerImpl struct {
+	store              store.Store
+	accountManager     account.Manager
+	permissionsManager permissions.Manager
+}
+
+func NewManager(store store.Store, accountManager account.Manager, permissionsManager permissions.Manager) peerapproval.Manager {
+	return &managerImpl{
+		store:              store,
+		accountManager:     accountManager,
+		permissionsManager: permissionsManager,
+	}
+}
+
+func (m *managerImpl) GetPendingPeers(ctx context.Context, accountID, userID string) ([]*peerapproval.PendingPeer, error) {
+	if err := m.validatePermissions(ctx, accountID, userID, operations.Read); err != nil {
+		return nil, err
+	}
+
+	peers, err := m.store.GetAccountPeers(ctx, store.LockingStrengthNone, accountID, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	requests, err := m.store.GetAccountPeerApprovalRequests(ctx, store.LockingStrengthNone, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	requestsByPeer := make(map[string]*peerapproval.Request, len(requests))
+	for _, request := range requests {
+		requestsByPeer[request.PeerID] = request
+	}
+
+	peers = slices.DeleteFunc(peers, func(peer *nbpeer.Peer) bool {
+		return peer.Status == nil || !peer.Status.RequiresApproval
+	})
+
+	results, err := m.accountManager.GetPeersPostureCheckResults(ctx, accountID, userID, peers)
+	if err != nil {
+		return nil, err
+	}
+
+	pendingPeers := make([]*peerapproval.PendingPeer, 0, len(peers))
+	for _, peer := range peers {
+		pendingPeers = append(pendingPeers, &peerapproval.PendingPeer{
+			Peer:          peer,
+			Request:       requestsByPeer[peer.ID],
+			PostureChecks: results[peer.ID],
+		})
+	}
+
+	slices.SortFunc(pendingPeers, func(a, b *peerapproval.PendingPeer) int {
+		return a.Peer.CreatedAt.Compare(b.Peer.CreatedAt)
+	})
+
+	return pendingPeers, nil
+}
+
+func (m *managerImpl) ApprovePeers(ctx context.Context, accountID, userID string, peerIDs []string, reason string) error {
+	if err := m.validatePermissions(ctx, accountID, userID, operations.Update); err != nil {
+		return err
+	}
+
+	peers, err := m.accountManager.ApprovePendingPeers(ctx, accountID, userID, peerIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, peer := range peers {
+		m.accountManager.StoreEvent(ctx, userID, peer.ID, accountID, activity.PeerApproved, decisionEventMeta(peer, reason))
+	}
+
+	return nil
+}
+
+func (m *managerImpl) RejectPeers(ctx context.Context, accountID, userID string, peerIDs []string, reason string) error {
+	if err := m.validatePermissions(ctx, accountID, userID, operations.Delete); err != nil {
+		return err
+	}
+
+	peers, err := m.accountManager.RejectPendingPeers(ctx, accountID, userID, peerIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, peer := range peers {
+		m.accountManager.StoreEvent(ctx, userID, peer.ID, accountID, activity.PeerRejected, decisionEventMeta(peer, reason))
+	}
+
+	return nil
+}
+
+func (m *managerImpl) GetAllRules(ctx context.Context, accountID, userID string) ([]*peerapproval.Rule, error) {
+	if err := m.validatePermissions(ctx, accountID, userID, operations.Read); err != nil {
+		return nil, err
+	}
+
+	return m.store.GetAccountPeerApprovalRules(ctx, store.LockingStrengthNone, accountID)
+}
+
+func (m *managerImpl) GetRule(ctx context.Context, accountID, userID, ruleID string) (*peerapproval.Rule, error) {
+	if err := m.validatePermissions(ctx, accountID, userID, operations.Read); err != nil {
+		return nil, err
+	}
+
+	return m.store.GetPeerApprovalRuleByID(ctx, store.LockingStrengthNone, accountID, ruleID)
+}
+
+func (m *managerImpl) CreateRule(ctx context.Context, accountID, userID string, rule *peerapproval.Rule) (*peerapproval.Rule, error) {
+	if err := m.validatePermissions(ctx, accountID, userID, operations.Create); err != nil {
+		return nil, err
+	}
+
+	rule = peerapproval.NewRule(accountID, rule.Name, rule.Enabled, rule.UserGroups, rule.SetupKeys, rule.OperatingSystems, rule.CountryCodes)
+	err := m.store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+		if err := validateRuleReferences(ctx, transaction, accountID, rule); err != nil {
+			return err
+		}
+
+		return transaction.SavePeerApprovalRule(ctx, rule)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	m.accountManager.StoreEvent(ctx, userID, rule.ID, accountID, activity.PeerApprovalRuleCreated, rule.EventMeta())
+
+	return rule, nil
+}
+
+func (m *managerImpl) UpdateRule(ctx context.Context, accountID, userID string, updatedRule *peerapproval.Rule) (*peerapproval.Rule, error) {
+	if err := m.validatePermissions(ctx, accountID, userID, operations.Update); err != nil {
+		return nil, err
+	}
+
+	var rule *peerapproval.Rule
+	err := m.store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+		var err error
+		rule, err = transaction.GetPeerApprovalRuleByID(ctx, store.LockingStrengthUpdate, accountID, updatedRule.ID)
+		if err != nil {
+			return err
+		}
+
+		if err = validateRuleReferences(ctx, transaction, accountID, updatedRule); err != nil {
+			return err
+		}
+
+		rule.Name = updatedRule.Name
+		rule.Enabled = updatedRule.Enabled
+		rule.UserGroups = updatedRule.UserGroups
+		rule.SetupKeys = updatedRule.SetupKeys
+		rule.OperatingSystems = updatedRule.OperatingSystems
+		rule.CountryCodes = updatedRule.CountryCodes
+
+		return transaction.SavePeerApprovalRule(ctx, rule)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	m.accountManager.StoreEvent(ctx, userID, rule.ID, accountID, activity.PeerApprovalRuleUpdated, rule.EventMeta())
+
+	return rule, nil
+}
+
+func (m *managerImpl) DeleteRule(ctx context.Context, accountID, userID, ruleID string) error {
+	if err := m.validatePermissions(ctx, accountID, userID, operations.Delete); err != nil {
+		return err
+	}
+
+	rule, err := m.store.GetPeerApprovalRuleByID(ctx, store.LockingStrengthNone, accountID, ruleID)
+	if err != nil {
+		return err
+	}
+
+	if err = m.store.DeletePeerApprovalRule(ctx, accountID, ruleID); err != nil {
+		return err
+	}
+
+	m.accountManager.StoreEvent(ctx, userID, ruleID, accountID, activity.PeerApprovalRuleDeleted, rule.EventMeta())
+
+	return nil
+}
+
+func (m *managerImpl) validatePermissions(ctx context.Context, accountID, userID string, operation operations.Operation) error {
+	allowed, err := m.permissionsManager.ValidateUserPermissions(ctx, accountID, userID, modules.Peers, operation)
+	if err != nil {
+		return status.NewPermissionValidationError(err)
+	}
+	if !allowed {
+		return status.NewPermissionDeniedError()
+	}
+	return nil
+}
+
+func validateRuleReferences(ctx context.Context, transaction store.Store, accountID string, rule *peerapproval.Rule) error {
+	groups, err := transaction.GetGroupsByIDs(ctx, store.LockingStrengthNone, accountID, rule.UserGroups)
+	if err != nil {
+		return err
+	}
+
+	for _, groupID := range rule.UserGroups {
+		if _, ok := groups[groupID]; !ok {
+			return status.Errorf(status.InvalidArgument, "group %s not found", groupID)
+		}
+	}
+
+	for _, setupKeyID := range rule.SetupKeys {
+		if _, err = transaction.GetSetupKeyByID(ctx, store.LockingStrengthNone, accountID, setupKeyID); err != nil {
+			return status.Errorf(status.InvalidArgument, "setup key %s not found", setupKeyID)
+		}
+	}
+
+	return nil
+}
+
+func decisionEventMeta(peer *nbpeer.Peer, reason string) map[string]any {
+	meta := peer.EventMeta("")
+	if reason != "" {
+		meta["reason"] = reason
+	}
+	return meta
+}
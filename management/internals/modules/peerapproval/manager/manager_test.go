@@ -0,0 +1,253 @@
+package manager
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/management/internals/modules/peerapproval"
+	"github.com/netbirdio/netbird/management/server/activity"
+	"github.com/netbirdio/netbird/management/server/mock_server"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/permissions"
+	"github.com/netbirdio/netbird/management/server/posture"
+	"github.com/netbirdio/netbird/management/server/store"
+	"github.com/netbirdio/netbird/management/server/types"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+const (
+	testAccountID     = "test-account-id"
+	testAdminID       = "test-admin-id"
+	testGroupID       = "test-group-id"
+	testSetupKeyID    = "test-setup-key-id"
+	testPendingPeerID = "test-pending-peer-id"
+	testOtherPeerID   = "test-other-pending-peer-id"
+	testActivePeerID  = "test-active-peer-id"
+)
+
+type recordedEvent struct {
+	activity activity.Activity
+	targetID string
+	meta     map[string]any
+}
+
+func setupTest(t *testing.T) (*managerImpl, store.Store, *[]recordedEvent) {
+	t.Helper()
+
+	ctx := context.Background()
+	testStore, cleanup, err := store.NewTestStoreFromSQL(ctx, "", t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(cleanup)
+
+	registeredAt := time.Now().UTC().Truncate(time.Second)
+	newPeer := func(id, name string, ip net.IP, pending bool, createdAt time.Time) *nbpeer.Peer {
+		return &nbpeer.Peer{
+			ID:        id,
+			AccountID: testAccountID,
+			Key:       id + "-key",
+			IP:        ip,
+			Name:      name,
+			DNSLabel:  name,
+			Meta:      nbpeer.PeerSystemMeta{Hostname: name, GoOS: "linux", OS: "Ubuntu", OSVersion: "24.04", WtVersion: "0.60.0"},
+			Status:    &nbpeer.PeerStatus{RequiresApproval: pending},
+			Location:  nbpeer.Location{CountryCode: "DE", CityName: "Berlin"},
+			CreatedAt: createdAt,
+		}
+	}
+
+	err = testStore.SaveAccount(ctx, &types.Account{
+		Id: testAccountID,
+		Users: map[string]*types.User{
+			testAdminID: {Id: testAdminID, AccountID: testAccountID, Role: types.UserRoleAdmin},
+		},
+		Groups: map[string]*types.Group{
+			testGroupID: {ID: testGroupID, AccountID: testAccountID, Name: "ops", Issued: types.GroupIssuedAPI, Peers: []string{}},
+		},
+		SetupKeys: map[string]*types.SetupKey{
+			testSetupKeyID: {Id: testSetupKeyID, AccountID: testAccountID, Key: "hashed-key", Name: "ci", AutoGroups: []string{}},
+		},
+		PostureChecks: []*posture.Checks{{
+			ID:        "checks",
+			Name:      "Default",
+			AccountID: testAccountID,
+			Checks:    posture.ChecksDefinition{OSVersionCheck: &posture.OSVersionCheck{Linux: &posture.MinKernelVersionCheck{MinKernelVersion: "5.0"}}},
+		}},
+		Peers: map[string]*nbpeer.Peer{
+			testPendingPeerID: newPeer(testPendingPeerID, "runner", net.IP{100, 64, 0, 1}, true, registeredAt.Add(-time.Minute)),
+			testOtherPeerID:   newPeer(testOtherPeerID, "laptop", net.IP{100, 64, 0, 2}, true, registeredAt),
+			testActivePeerID:  newPeer(testActivePeerID, "server", net.IP{100, 64, 0, 3}, false, registeredAt),
+		},
+	})
+	require.NoError(t, err)
+
+	err = testStore.SavePeerApprovalRequest(ctx, &peerapproval.Request{
+		PeerID:       testPendingPeerID,
+		AccountID:    testAccountID,
+		SetupKeyID:   testSetupKeyID,
+		SetupKeyName: "ci",
+		CreatedAt:    registeredAt,
+	})
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	permissionsManager := permissions.NewMockManager(ctrl)
+	permissionsManager.EXPECT().ValidateUserPermissions(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(true, nil).AnyTimes()
+
+	var events []recordedEvent
+	accountManager := &mock_server.MockAccountManager{
+		StoreEventFunc: func(_ context.Context, _, targetID, _ string, activityID activity.ActivityDescriber, meta map[string]any) {
+			events = append(events, recordedEvent{activity: activityID.(activity.Activity), targetID: targetID, meta: meta})
+		},
+	}
+
+	return NewManager(testStore, accountManager, permissionsManager).(*managerImpl), testStore, &events
+}
+
+func TestManager_GetPendingPeers(t *testing.T) {
+	manager, _, _ := setupTest(t)
+
+	accountManager := manager.accountManager.(*mock_server.MockAccountManager)
+	accountManager.GetPeersPostureCheckResultsFunc = func(_ context.Context, _, _ string, peers []*nbpeer.Peer) (map[string][]posture.Result, error) {
+		assert.Len(t, peers, 2, "the posture checks of the pending peers are evaluated at once")
+		return map[string][]posture.Result{
+			testPendingPeerID: {{PostureChecksID: "checks", PostureChecksName: "Default", Check: posture.OSVersionCheckName, Passed: true}},
+		}, nil
+	}
+
+	pendingPeers, err := manager.GetPendingPeers(context.Background(), testAccountID, testAdminID)
+	require.NoError(t, err)
+	require.Len(t, pendingPeers, 2, "approved peers are not listed")
+
+	assert.Equal(t, testPendingPeerID, pendingPeers[0].Peer.ID, "oldest registration first")
+	require.NotNil(t, pendingPeers[0].Request)
+	assert.Equal(t, "ci", pendingPeers[0].Request.SetupKeyName)
+	require.Len(t, pendingPeers[0].PostureChecks, 1, "the posture check results of the peer are listed")
+	assert.Equal(t, "Default", pendingPeers[0].PostureChecks[0].PostureChecksName)
+	assert.Empty(t, pendingPeers[1].PostureChecks)
+
+	assert.Equal(t, testOtherPeerID, pendingPeers[1].Peer.ID)
+	assert.Nil(t, pendingPeers[1].Request, "peers can become pending without a registration request")
+
+	resp := pendingPeers[0].ToAPIResponse()
+	assert.Equal(t, "Ubuntu 24.04", resp.Os)
+	assert.Equal(t, testSetupKeyID, resp.SetupKeyId)
+	assert.Equal(t, "DE", resp.CountryCode)
+}
+
+func TestManager_ApprovePeers(t *testing.T) {
+	manager, testStore, events := setupTest(t)
+	ctx := context.Background()
+
+	accountManager := manager.accountManager.(*mock_server.MockAccountManager)
+	accountManager.ApprovePendingPeersFunc = func(ctx context.Context, accountID, _ string, peerIDs []string) ([]*nbpeer.Peer, error) {
+		peersByID, err := testStore.GetPeersByIDs(ctx, store.LockingStrengthNone, accountID, peerIDs)
+		if err != nil {
+			return nil, err
+		}
+		if len(peersByID) != len(peerIDs) {
+			return nil, status.Errorf(status.PreconditionFailed, "peer is not pending approval")
+		}
+		peers := make([]*nbpeer.Peer, 0, len(peerIDs))
+		for _, peerID := range peerIDs {
+			peers = append(peers, peersByID[peerID])
+		}
+		return peers, nil
+	}
+
+	err := manager.ApprovePeers(ctx, testAccountID, testAdminID, []string{testPendingPeerID, "unknown-peer"}, "")
+	sErr, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, status.PreconditionFailed, sErr.Type())
+	assert.Empty(t, *events, "failed decisions are not recorded")
+
+	err = manager.ApprovePeers(ctx, testAccountID, testAdminID, []string{testPendingPeerID, testOtherPeerID}, "known devices")
+	require.NoError(t, err)
+
+	require.Len(t, *events, 2)
+	for _, event := range *events {
+		assert.Equal(t, activity.PeerApproved, event.activity)
+		assert.Equal(t, "known devices", event.meta["reason"])
+	}
+}
+
+func TestManager_RejectPeers(t *testing.T) {
+	manager, testStore, events := setupTest(t)
+	ctx := context.Background()
+
+	accountManager := manager.accountManager.(*mock_server.MockAccountManager)
+	accountManager.RejectPendingPeersFunc = func(ctx context.Context, accountID, _ string, peerIDs []string) ([]*nbpeer.Peer, error) {
+		peer, err := testStore.GetPeerByID(ctx, store.LockingStrengthNone, accountID, peerIDs[0])
+		if err != nil {
+			return nil, err
+		}
+		return []*nbpeer.Peer{peer}, nil
+	}
+
+	err := manager.RejectPeers(ctx, testAccountID, testAdminID, []string{"unknown-peer"}, "")
+	sErr, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, status.NotFound, sErr.Type())
+	assert.Empty(t, *events, "failed decisions are not recorded")
+
+	err = manager.RejectPeers(ctx, testAccountID, testAdminID, []string{testPendingPeerID}, "unknown device")
+	require.NoError(t, err)
+
+	require.Len(t, *events, 1)
+	assert.Equal(t, activity.PeerRejected, (*events)[0].activity)
+	assert.Equal(t, testPendingPeerID, (*events)[0].targetID)
+	assert.Equal(t, "unknown device", (*events)[0].meta["reason"])
+}
+
+func TestManager_Rules(t *testing.T) {
+	manager, _, events := setupTest(t)
+	ctx := context.Background()
+
+	_, err := manager.CreateRule(ctx, testAccountID, testAdminID, &peerapproval.Rule{Name: "ops", UserGroups: []string{"unknown-group"}})
+	sErr, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, status.InvalidArgument, sErr.Type())
+
+	_, err = manager.CreateRule(ctx, testAccountID, testAdminID, &peerapproval.Rule{Name: "ci", SetupKeys: []string{"unknown-key"}})
+	assert.Error(t, err)
+
+	rule, err := manager.CreateRule(ctx, testAccountID, testAdminID, &peerapproval.Rule{
+		Name:             "ops on linux",
+		Enabled:          true,
+		UserGroups:       []string{testGroupID},
+		OperatingSystems: []string{"linux"},
+	})
+	require.NoError(t, err)
+
+	rule.Enabled = false
+	rule.SetupKeys = []string{testSetupKeyID}
+	_, err = manager.UpdateRule(ctx, testAccountID, testAdminID, rule)
+	require.NoError(t, err)
+
+	stored, err := manager.GetRule(ctx, testAccountID, testAdminID, rule.ID)
+	require.NoError(t, err)
+	assert.False(t, stored.Enabled)
+	assert.Equal(t, []string{testSetupKeyID}, stored.SetupKeys)
+
+	require.NoError(t, manager.DeleteRule(ctx, testAccountID, testAdminID, rule.ID))
+	rules, err := manager.GetAllRules(ctx, testAccountID, testAdminID)
+	require.NoError(t, err)
+	assert.Empty(t, rules)
+
+	err = manager.DeleteRule(ctx, testAccountID, testAdminID, rule.ID)
+	sErr, ok = status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, status.NotFound, sErr.Type())
+
+	eventTypes := make([]activity.Activity, 0, len(*events))
+	for _, event := range *events {
+		eventTypes = append(eventTypes, event.activity)
+	}
+	assert.Equal(t, []activity.Activity{activity.PeerApprovalRuleCreated, activity.PeerApprovalRuleUpdated, activity.PeerApprovalRuleDeleted}, eventTypes)
+}
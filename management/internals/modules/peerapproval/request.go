@@ -0,0 +1,74 @@
+package peerapproval
+
+import (
+	"fmt"
+	"time"
+
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/posture"
+	"github.com/netbirdio/netbird/shared/management/http/api"
+)
+
+// Request keeps the context of the registration of a peer pending approval, it is deleted once the peer is approved or rejected
+type Request struct {
+	PeerID       string `gorm:"primaryKey"`
+	AccountID    string `gorm:"index"`
+	UserID       string
+	SetupKeyID   string
+	SetupKeyName string
+	CreatedAt    time.Time
+}
+
+func (Request) TableName() string {
+	return "peer_approval_requests"
+}
+
+// PendingPeer is a peer pending approval with the context an administrator needs to decide on it
+type PendingPeer struct {
+	Peer *nbpeer.Peer
+	// Request is nil when the peer became pending after its registration
+	Request       *Request
+	PostureChecks []posture.Result
+}
+
+func (p *PendingPeer) ToAPIResponse() *api.PendingPeer {
+	osVersion := p.Peer.Meta.OSVersion
+	if osVersion == "" {
+		osVersion = p.Peer.Meta.Core
+	}
+
+	resp := &api.PendingPeer{
+		Id:            p.Peer.ID,
+		Name:          p.Peer.Name,
+		Ip:            p.Peer.IP.String(),
+		Os:            fmt.Sprintf("%s %s", p.Peer.Meta.OS, osVersion),
+		Version:       p.Peer.Meta.WtVersion,
+		UserId:        p.Peer.UserID,
+		CountryCode:   p.Peer.Location.CountryCode,
+		CityName:      p.Peer.Location.CityName,
+		RegisteredAt:  p.Peer.CreatedAt,
+		PostureChecks: make([]api.PeerPostureCheckResult, 0, len(p.PostureChecks)),
+	}
+
+	if p.Peer.Location.ConnectionIP != nil {
+		resp.ConnectionIp = p.Peer.Location.ConnectionIP.String()
+	}
+
+	if p.Request != nil {
+		resp.SetupKeyId = p.Request.SetupKeyID
+		resp.SetupKeyName = p.Request.SetupKeyName
+	}
+
+	for _, result := range p.PostureChecks {
+		resp.PostureChecks = append(resp.PostureChecks, api.PeerPostureCheckResult{
+			PostureCheckId:   result.PostureChecksID,
+			PostureCheckName: result.PostureChecksName,
+			Check:            result.Check,
+			Passed:           result.Passed,
+			Reason:           result.Reason,
+			EvaluatedAt:      result.EvaluatedAt,
+		})
+	}
+
+	return resp
+}
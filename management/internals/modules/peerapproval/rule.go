@@ -0,0 +1,136 @@
+package peerapproval
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/rs/xid"
+
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/shared/management/http/api"
+)
+
+// Rule approves new peers matching all of its conditions without an administrator
+type Rule struct {
+	ID        string `gorm:"primaryKey"`
+	AccountID string `gorm:"index"`
+	Name      string
+	Enabled   bool
+	// UserGroups matches peers registered by a user in one of the groups
+	UserGroups []string `gorm:"serializer:json"`
+	// SetupKeys matches peers registered with one of the setup keys
+	SetupKeys []string `gorm:"serializer:json"`
+	// OperatingSystems matches the GoOS of the peers, e.g. linux
+	OperatingSystems []string `gorm:"serializer:json"`
+	// CountryCodes matches the country the peers connect from
+	CountryCodes []string `gorm:"serializer:json"`
+}
+
+func (Rule) TableName() string {
+	return "peer_approval_rules"
+}
+
+func NewRule(accountID, name string, enabled bool, userGroups, setupKeys, operatingSystems, countryCodes []string) *Rule {
+	return &Rule{
+		ID:               xid.New().String(),
+		AccountID:        accountID,
+		Name:             name,
+		Enabled:          enabled,
+		UserGroups:       userGroups,
+		SetupKeys:        setupKeys,
+		OperatingSystems: operatingSystems,
+		CountryCodes:     countryCodes,
+	}
+}
+
+func (r *Rule) ToAPIResponse() *api.PeerApprovalRule {
+	return &api.PeerApprovalRule{
+		Id:               r.ID,
+		Name:             r.Name,
+		Enabled:          r.Enabled,
+		UserGroups:       &r.UserGroups,
+		SetupKeys:        &r.SetupKeys,
+		OperatingSystems: &r.OperatingSystems,
+		CountryCodes:     &r.CountryCodes,
+	}
+}
+
+func (r *Rule) FromAPIRequest(req *api.PeerApprovalRuleRequest) {
+	r.Name = req.Name
+	r.Enabled = req.Enabled
+	r.UserGroups = valueOrEmpty(req.UserGroups)
+	r.SetupKeys = valueOrEmpty(req.SetupKeys)
+	r.OperatingSystems = valueOrEmpty(req.OperatingSystems)
+	r.CountryCodes = valueOrEmpty(req.CountryCodes)
+}
+
+func (r *Rule) Validate() error {
+	if r.Name == "" {
+		return errors.New("rule name is required")
+	}
+
+	if len(r.UserGroups) == 0 && len(r.SetupKeys) == 0 && len(r.OperatingSystems) == 0 && len(r.CountryCodes) == 0 {
+		return errors.New("at least one condition is required")
+	}
+
+	for _, code := range r.CountryCodes {
+		if len(code) != 2 {
+			return errors.New("country codes have to be 2-letter ISO 3166-1 alpha-2 codes")
+		}
+	}
+
+	return nil
+}
+
+// Matches returns true if the enabled rule matches the peer registered by a user in the userGroups or with the setup key
+func (r *Rule) Matches(peer *nbpeer.Peer, userGroups []string, setupKeyID string) bool {
+	if !r.Enabled {
+		return false
+	}
+
+	if len(r.UserGroups) > 0 && !slices.ContainsFunc(userGroups, func(groupID string) bool {
+		return slices.Contains(r.UserGroups, groupID)
+	}) {
+		return false
+	}
+
+	if len(r.SetupKeys) > 0 && (setupKeyID == "" || !slices.Contains(r.SetupKeys, setupKeyID)) {
+		return false
+	}
+
+	if len(r.OperatingSystems) > 0 && !slices.ContainsFunc(r.OperatingSystems, func(os string) bool {
+		return strings.EqualFold(os, peer.Meta.GoOS)
+	}) {
+		return false
+	}
+
+	if len(r.CountryCodes) > 0 && !slices.ContainsFunc(r.CountryCodes, func(code string) bool {
+		return strings.EqualFold(code, peer.Location.CountryCode)
+	}) {
+		return false
+	}
+
+	return true
+}
+
+func (r *Rule) EventMeta() map[string]any {
+	return map[string]any{"name": r.Name}
+}
+
+// MatchRule returns the first rule matching the peer, nil if none does
+func MatchRule(rules []*Rule, peer *nbpeer.Peer, userGroups []string, setupKeyID string) *Rule {
+	for _, rule := range rules {
+		if rule.Matches(peer, userGroups, setupKeyID) {
+			return rule
+		}
+	}
+	return nil
+}
+
+func valueOrEmpty[T any](values *[]T) []T {
+	if values == nil {
+		return []T{}
+	}
+	return *values
+}
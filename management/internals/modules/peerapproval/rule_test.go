@@ -0,0 +1,93 @@
+package peerapproval
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+)
+
+func TestRule_Matches(t *testing.T) {
+	linuxPeer := &nbpeer.Peer{
+		Meta:     nbpeer.PeerSystemMeta{GoOS: "linux"},
+		Location: nbpeer.Location{CountryCode: "DE"},
+	}
+
+	tests := []struct {
+		name       string
+		rule       *Rule
+		peer       *nbpeer.Peer
+		userGroups []string
+		setupKeyID string
+		expected   bool
+	}{
+		{
+			name:       "user in group on linux",
+			rule:       &Rule{Enabled: true, UserGroups: []string{"ops"}, OperatingSystems: []string{"linux"}},
+			peer:       linuxPeer,
+			userGroups: []string{"dev", "ops"},
+			expected:   true,
+		},
+		{
+			name:       "user not in group",
+			rule:       &Rule{Enabled: true, UserGroups: []string{"ops"}, OperatingSystems: []string{"linux"}},
+			peer:       linuxPeer,
+			userGroups: []string{"dev"},
+			expected:   false,
+		},
+		{
+			name:       "other operating system",
+			rule:       &Rule{Enabled: true, UserGroups: []string{"ops"}, OperatingSystems: []string{"windows"}},
+			peer:       linuxPeer,
+			userGroups: []string{"ops"},
+			expected:   false,
+		},
+		{
+			name:       "setup key",
+			rule:       &Rule{Enabled: true, SetupKeys: []string{"key"}},
+			peer:       linuxPeer,
+			setupKeyID: "key",
+			expected:   true,
+		},
+		{
+			name:       "peer registered by a user doesn't match a setup key",
+			rule:       &Rule{Enabled: true, SetupKeys: []string{"key"}},
+			peer:       linuxPeer,
+			userGroups: []string{"ops"},
+			expected:   false,
+		},
+		{
+			name:     "country code is case insensitive",
+			rule:     &Rule{Enabled: true, CountryCodes: []string{"de"}},
+			peer:     linuxPeer,
+			expected: true,
+		},
+		{
+			name:     "other country",
+			rule:     &Rule{Enabled: true, CountryCodes: []string{"FR"}},
+			peer:     linuxPeer,
+			expected: false,
+		},
+		{
+			name:       "disabled rule",
+			rule:       &Rule{Enabled: false, UserGroups: []string{"ops"}},
+			peer:       linuxPeer,
+			userGroups: []string{"ops"},
+			expected:   false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.rule.Matches(tc.peer, tc.userGroups, tc.setupKeyID))
+		})
+	}
+}
+
+func TestRule_Validate(t *testing.T) {
+	assert.NoError(t, (&Rule{Name: "ops", OperatingSystems: []string{"linux"}}).Validate())
+	assert.Error(t, (&Rule{OperatingSystems: []string{"linux"}}).Validate(), "name is required")
+	assert.Error(t, (&Rule{Name: "everything"}).Validate(), "a rule without conditions would approve every peer")
+	assert.Error(t, (&Rule{Name: "country", CountryCodes: []string{"Germany"}}).Validate())
+}
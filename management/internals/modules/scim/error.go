@@ -0,0 +1,86 @@
+package scim
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+// Detail error types of RFC 7644 section 3.12
+const (
+	ErrTypeInvalidFilter = "invalidFilter"
+	ErrTypeTooMany       = "tooMany"
+	ErrTypeUniqueness    = "uniqueness"
+	ErrTypeMutability    = "mutability"
+	ErrTypeInvalidSyntax = "invalidSyntax"
+	ErrTypeInvalidPath   = "invalidPath"
+	ErrTypeNoTarget      = "noTarget"
+	ErrTypeInvalidValue  = "invalidValue"
+)
+
+// Error is the SCIM error response
+type Error struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+}
+
+// NewError creates an error with the HTTP status code and the detail error type
+func NewError(code int, scimType, format string, a ...any) *Error {
+	return &Error{
+		Schemas:  []string{SchemaError},
+		Status:   strconv.Itoa(code),
+		ScimType: scimType,
+		Detail:   fmt.Sprintf(format, a...),
+	}
+}
+
+func (e *Error) Error() string {
+	if e.ScimType != "" {
+		return e.ScimType + ": " + e.Detail
+	}
+	return e.Detail
+}
+
+// Code returns the HTTP status code of the error
+func (e *Error) Code() int {
+	code, err := strconv.Atoi(e.Status)
+	if err != nil {
+		return http.StatusInternalServerError
+	}
+	return code
+}
+
+// ToError converts the errors of the account manager to SCIM errors
+func ToError(err error) *Error {
+	var scimErr *Error
+	if errors.As(err, &scimErr) {
+		return scimErr
+	}
+
+	sErr, ok := status.FromError(err)
+	if !ok {
+		return NewError(http.StatusInternalServerError, "", "internal server error")
+	}
+
+	switch sErr.Type() {
+	case status.NotFound:
+		return NewError(http.StatusNotFound, "", "%s", sErr.Message)
+	case status.AlreadyExists, status.UserAlreadyExists:
+		return NewError(http.StatusConflict, ErrTypeUniqueness, "%s", sErr.Message)
+	case status.InvalidArgument, status.BadRequest:
+		return NewError(http.StatusBadRequest, ErrTypeInvalidValue, "%s", sErr.Message)
+	case status.PermissionDenied:
+		return NewError(http.StatusForbidden, "", "%s", sErr.Message)
+	case status.Unauthorized, status.Unauthenticated:
+		return NewError(http.StatusUnauthorized, "", "%s", sErr.Message)
+	case status.PreconditionFailed:
+		return NewError(http.StatusPreconditionFailed, "", "%s", sErr.Message)
+	default:
+		return NewError(http.StatusInternalServerError, "", "internal server error")
+	}
+}
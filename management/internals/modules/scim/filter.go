@@ -0,0 +1,316 @@
+package scim
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// Attributes returns the values of an attribute of a resource by its lower cased path,
+// e.g. "username" or "emails.value". Unknown attributes have no values.
+type Attributes func(path string) []string
+
+// Filter is a filter expression of a query, see RFC 7644 section 3.4.2.2.
+// Attributes are compared case-insensitively, complex attribute filters like emails[type eq "work"]
+// are not supported.
+type Filter interface {
+	// Match reports whether the attributes of a resource match the filter
+	Match(attrs Attributes) bool
+}
+
+// ParseFilter parses a filter expression, an empty expression returns a nil filter
+func ParseFilter(expr string) (Filter, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, nil //nolint:nilnil
+	}
+
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &filterParser{tokens: tokens}
+	f, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.tokens) {
+		return nil, invalidFilter("unexpected %q", p.tokens[p.pos].text)
+	}
+
+	return f, nil
+}
+
+// MatchFilter reports whether the attributes match the filter, a nil filter matches everything
+func MatchFilter(f Filter, attrs Attributes) bool {
+	return f == nil || f.Match(attrs)
+}
+
+type logicalFilter struct {
+	and         bool
+	left, right Filter
+}
+
+func (f *logicalFilter) Match(attrs Attributes) bool {
+	if f.and {
+		return f.left.Match(attrs) && f.right.Match(attrs)
+	}
+	return f.left.Match(attrs) || f.right.Match(attrs)
+}
+
+type notFilter struct {
+	filter Filter
+}
+
+func (f *notFilter) Match(attrs Attributes) bool {
+	return !f.filter.Match(attrs)
+}
+
+type attrFilter struct {
+	path  string
+	op    string
+	value string
+	null  bool
+}
+
+func (f *attrFilter) Match(attrs Attributes) bool {
+	values := attrs(f.path)
+
+	present := false
+	for _, v := range values {
+		if v != "" {
+			present = true
+		}
+	}
+
+	if f.op == "pr" || f.null {
+		switch f.op {
+		case "pr", "ne":
+			return present
+		case "eq":
+			return !present
+		default:
+			return false
+		}
+	}
+
+	if f.op == "ne" {
+		for _, v := range values {
+			if strings.EqualFold(v, f.value) {
+				return false
+			}
+		}
+		return true
+	}
+
+	for _, v := range values {
+		if compare(f.op, strings.ToLower(v), f.value) {
+			return true
+		}
+	}
+	return false
+}
+
+// compare compares a lower cased value of an attribute with the lower cased value of the filter
+func compare(op, v, value string) bool {
+	switch op {
+	case "eq":
+		return v == value
+	case "co":
+		return strings.Contains(v, value)
+	case "sw":
+		return strings.HasPrefix(v, value)
+	case "ew":
+		return strings.HasSuffix(v, value)
+	case "gt":
+		return v > value
+	case "ge":
+		return v >= value
+	case "lt":
+		return v < value
+	case "le":
+		return v <= value
+	default:
+		return false
+	}
+}
+
+var comparisonOperators = map[string]struct{}{
+	"eq": {}, "ne": {}, "co": {}, "sw": {}, "ew": {}, "gt": {}, "ge": {}, "lt": {}, "le": {},
+}
+
+type filterToken struct {
+	text   string
+	quoted bool
+}
+
+func tokenize(expr string) ([]filterToken, error) {
+	var tokens []filterToken
+	for i := 0; i < len(expr); {
+		switch c := expr[i]; {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '(' || c == ')':
+			tokens = append(tokens, filterToken{text: string(c)})
+			i++
+		case c == '"':
+			end := i + 1
+			for end < len(expr) && expr[end] != '"' {
+				if expr[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(expr) {
+				return nil, invalidFilter("unterminated string")
+			}
+
+			var value string
+			if err := json.Unmarshal([]byte(expr[i:end+1]), &value); err != nil {
+				return nil, invalidFilter("invalid string %s", expr[i:end+1])
+			}
+			tokens = append(tokens, filterToken{text: value, quoted: true})
+			i = end + 1
+		case c == '[':
+			return nil, invalidFilter("complex attribute filters are not supported")
+		default:
+			end := i
+			for end < len(expr) && !strings.ContainsRune(" \t()\"[", rune(expr[end])) {
+				end++
+			}
+			tokens = append(tokens, filterToken{text: expr[i:end]})
+			i = end
+		}
+	}
+	return tokens, nil
+}
+
+type filterParser struct {
+	tokens []filterToken
+	pos    int
+}
+
+func (p *filterParser) peek() (filterToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return filterToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *filterParser) next() (filterToken, bool) {
+	t, ok := p.peek()
+	if ok {
+		p.pos++
+	}
+	return t, ok
+}
+
+// keyword reports whether the next token is the unquoted keyword and consumes it
+func (p *filterParser) keyword(word string) bool {
+	t, ok := p.peek()
+	if !ok || t.quoted || !strings.EqualFold(t.text, word) {
+		return false
+	}
+	p.pos++
+	return true
+}
+
+func (p *filterParser) parseOr() (Filter, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalFilter{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (Filter, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") {
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalFilter{and: true, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseTerm() (Filter, error) {
+	if p.keyword("not") {
+		if !p.keyword("(") {
+			return nil, invalidFilter("expected ( after not")
+		}
+		f, err := p.parseGroup()
+		if err != nil {
+			return nil, err
+		}
+		return &notFilter{filter: f}, nil
+	}
+	if p.keyword("(") {
+		return p.parseGroup()
+	}
+
+	attr, ok := p.next()
+	if !ok || attr.quoted {
+		return nil, invalidFilter("expected attribute")
+	}
+	op, ok := p.next()
+	if !ok || op.quoted {
+		return nil, invalidFilter("expected operator after %s", attr.text)
+	}
+
+	f := &attrFilter{path: NormalizePath(attr.text), op: strings.ToLower(op.text)}
+	if f.op == "pr" {
+		return f, nil
+	}
+	if _, ok := comparisonOperators[f.op]; !ok {
+		return nil, invalidFilter("unknown operator %s", op.text)
+	}
+
+	value, ok := p.next()
+	if !ok {
+		return nil, invalidFilter("expected value after %s %s", attr.text, op.text)
+	}
+	if !value.quoted && value.text == "null" {
+		f.null = true
+	}
+	f.value = strings.ToLower(value.text)
+
+	return f, nil
+}
+
+func (p *filterParser) parseGroup() (Filter, error) {
+	f, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.keyword(")") {
+		return nil, invalidFilter("expected )")
+	}
+	return f, nil
+}
+
+// NormalizePath lower cases an attribute path and strips the schema URN prefix
+func NormalizePath(path string) string {
+	path = strings.ToLower(path)
+	if strings.HasPrefix(path, "urn:") {
+		if i := strings.LastIndex(path, ":"); i >= 0 {
+			path = path[i+1:]
+		}
+	}
+	return path
+}
+
+func invalidFilter(format string, a ...any) error {
+	return NewError(http.StatusBadRequest, ErrTypeInvalidFilter, format, a...)
+}
@@ -0,0 +1,81 @@
+package scim
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testAttributes(path string) []string {
+	switch path {
+	case "username":
+		return []string{"Alice@Example.com"}
+	case "active":
+		return []string{"true"}
+	case "emails", "emails.value":
+		return []string{"alice@example.com", "alice@corp.example"}
+	case "members":
+		return []string{"user-1", "user-2"}
+	}
+	return nil
+}
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		filter string
+		match  bool
+	}{
+		{filter: `userName eq "alice@example.com"`, match: true},
+		{filter: `userName eq "bob@example.com"`, match: false},
+		{filter: `urn:ietf:params:scim:schemas:core:2.0:User:userName eq "alice@example.com"`, match: true},
+		{filter: `userName sw "alice" and active eq true`, match: true},
+		{filter: `userName sw "bob" or emails.value ew "@corp.example"`, match: true},
+		{filter: `emails co "corp"`, match: true},
+		{filter: `members eq "user-2"`, match: true},
+		{filter: `not (userName pr)`, match: false},
+		{filter: `displayName pr`, match: false},
+		{filter: `displayName eq null`, match: true},
+		{filter: `userName ne "alice@example.com"`, match: false},
+		{filter: `externalId ne "x"`, match: true},
+		{filter: `(userName eq "x" or active eq true) and members eq "user-1"`, match: true},
+		{filter: `userName EQ "ALICE@example.com" AND active Eq TRUE`, match: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			f, err := ParseFilter(tt.filter)
+			require.NoError(t, err)
+			assert.Equal(t, tt.match, MatchFilter(f, testAttributes))
+		})
+	}
+}
+
+func TestParseFilter_Empty(t *testing.T) {
+	f, err := ParseFilter(" ")
+	require.NoError(t, err)
+	assert.True(t, MatchFilter(f, testAttributes))
+}
+
+func TestParseFilter_Invalid(t *testing.T) {
+	for _, filter := range []string{
+		`userName`,
+		`userName eq`,
+		`userName xx "a"`,
+		`userName eq "unterminated`,
+		`(userName eq "a"`,
+		`userName eq "a" "b"`,
+		`emails[type eq "work"].value eq "a"`,
+		`not userName pr`,
+	} {
+		_, err := ParseFilter(filter)
+		require.Error(t, err, filter)
+
+		var scimErr *Error
+		require.True(t, errors.As(err, &scimErr), filter)
+		assert.Equal(t, http.StatusBadRequest, scimErr.Code())
+		assert.Equal(t, ErrTypeInvalidFilter, scimErr.ScimType)
+	}
+}
@@ -0,0 +1,27 @@
+package scim
+
+import (
+	"context"
+)
+
+type Manager interface {
+	GetToken(ctx context.Context, accountID, userID string) (*Token, error)
+	CreateToken(ctx context.Context, accountID, userID string) (*TokenGenerated, error)
+	DeleteToken(ctx context.Context, accountID, userID string) error
+	// AuthenticateToken returns the account of a plain text SCIM token
+	AuthenticateToken(ctx context.Context, token string) (string, error)
+
+	ListUsers(ctx context.Context, accountID string, filter Filter) ([]*User, error)
+	GetUser(ctx context.Context, accountID, id string) (*User, error)
+	CreateUser(ctx context.Context, accountID string, user *User) (*User, error)
+	ReplaceUser(ctx context.Context, accountID, id string, user *User) (*User, error)
+	PatchUser(ctx context.Context, accountID, id string, operations []PatchOperation) (*User, error)
+	DeleteUser(ctx context.Context, accountID, id string) error
+
+	ListGroups(ctx context.Context, accountID string, filter Filter) ([]*Group, error)
+	GetGroup(ctx context.Context, accountID, id string) (*Group, error)
+	CreateGroup(ctx context.Context, accountID string, group *Group) (*Group, error)
+	ReplaceGroup(ctx context.Context, accountID, id string, group *Group) (*Group, error)
+	PatchGroup(ctx context.Context, accountID, id string, operations []PatchOperation) (*Group, error)
+	DeleteGroup(ctx context.Context, accountID, id string) error
+}
@@ -0,0 +1,73 @@
+package manager
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/netbirdio/netbird/management/internals/modules/scim"
+	nbcontext "github.com/netbirdio/netbird/management/server/context"
+	"github.com/netbirdio/netbird/shared/management/http/util"
+)
+
+type handler struct {
+	manager scim.Manager
+}
+
+// RegisterEndpoints registers the endpoints managing the SCIM token in the management API
+func RegisterEndpoints(router *mux.Router, manager scim.Manager) {
+	h := &handler{
+		manager: manager,
+	}
+
+	router.HandleFunc("/scim/token", h.getToken).Methods("GET", "OPTIONS")
+	router.HandleFunc("/scim/token", h.createToken).Methods("POST", "OPTIONS")
+	router.HandleFunc("/scim/token", h.deleteToken).Methods("DELETE", "OPTIONS")
+}
+
+func (h *handler) getToken(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	token, err := h.manager.GetToken(r.Context(), userAuth.AccountId, userAuth.UserId)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, token.ToAPIResponse())
+}
+
+func (h *handler) createToken(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	token, err := h.manager.CreateToken(r.Context(), userAuth.AccountId, userAuth.UserId)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, token.ToAPIResponse())
+}
+
+func (h *handler) deleteToken(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	if err := h.manager.DeleteToken(r.Context(), userAuth.AccountId, userAuth.UserId); err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, util.EmptyObject{})
+}
@@ -0,0 +1,931 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/xid"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/management/internals/modules/scim"
+	"github.com/netbirdio/netbird/management/server/account"
+	"github.com/netbirdio/netbird/management/server/activity"
+	"github.com/netbirdio/netbird/management/server/permissions"
+	"github.com/netbirdio/netbird/management/server/permissions/modules"
+	"github.com/netbirdio/netbird/management/server/permissions/operations"
+	"github.com/netbirdio/netbird/management/server/store"
+	"github.com/netbirdio/netbird/management/server/types"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+type managerImpl struct {
+	store              store.Store
+	accountManager     account.Manager
+	permissionsManager permissions.Manager
+}
+
+func NewManager(store store.Store, accountManager account.Manager, permissionsManager permissions.Manager) scim.Manager {
+	return &managerImpl{
+		store:              store,
+		accountManager:     accountManager,
+		permissionsManager: permissionsManager,
+	}
+}
+
+func (m *managerImpl) GetToken(ctx context.Context, accountID, userID string) (*scim.Token, error) {
+	if err := m.validatePermissions(ctx, accountID, userID, operations.Read); err != nil {
+		return nil, err
+	}
+
+	return m.store.GetSCIMToken(ctx, store.LockingStrengthNone, accountID)
+}
+
+func (m *managerImpl) CreateToken(ctx context.Context, accountID, userID string) (*scim.TokenGenerated, error) {
+	if err := m.validatePermissions(ctx, accountID, userID, operations.Update); err != nil {
+		return nil, err
+	}
+
+	token, err := scim.NewToken(accountID, userID)
+	if err != nil {
+		return nil, status.Errorf(status.Internal, "failed to generate SCIM token: %v", err)
+	}
+
+	if err := m.store.SaveSCIMToken(ctx, &token.Token); err != nil {
+		return nil, err
+	}
+
+	m.accountManager.StoreEvent(ctx, userID, accountID, accountID, activity.SCIMTokenCreated, nil)
+
+	return token, nil
+}
+
+func (m *managerImpl) DeleteToken(ctx context.Context, accountID, userID string) error {
+	if err := m.validatePermissions(ctx, accountID, userID, operations.Update); err != nil {
+		return err
+	}
+
+	if err := m.store.DeleteSCIMToken(ctx, accountID); err != nil {
+		return err
+	}
+
+	m.accountManager.StoreEvent(ctx, userID, accountID, accountID, activity.SCIMTokenDeleted, nil)
+
+	return nil
+}
+
+func (m *managerImpl) AuthenticateToken(ctx context.Context, plainToken string) (string, error) {
+	hashedToken, err := scim.HashToken(plainToken)
+	if err != nil {
+		return "", status.Errorf(status.Unauthenticated, "invalid SCIM token: %v", err)
+	}
+
+	token, err := m.store.GetSCIMTokenByHashedToken(ctx, store.LockingStrengthNone, hashedToken)
+	if err != nil {
+		if s, ok := status.FromError(err); ok && s.Type() == status.NotFound {
+			return "", status.Errorf(status.Unauthenticated, "invalid SCIM token")
+		}
+		return "", err
+	}
+
+	if err := m.store.MarkSCIMTokenUsed(ctx, token.AccountID); err != nil {
+		log.WithContext(ctx).Warnf("failed to mark SCIM token of account %s as used: %v", token.AccountID, err)
+	}
+
+	return token.AccountID, nil
+}
+
+func (m *managerImpl) validatePermissions(ctx context.Context, accountID, userID string, operation operations.Operation) error {
+	allowed, err := m.permissionsManager.ValidateUserPermissions(ctx, accountID, userID, modules.Settings, operation)
+	if err != nil {
+		return status.NewPermissionValidationError(err)
+	}
+	if !allowed {
+		return status.NewPermissionDeniedError()
+	}
+	return nil
+}
+
+func (m *managerImpl) ListUsers(ctx context.Context, accountID string, filter scim.Filter) ([]*scim.User, error) {
+	users, err := m.store.GetAccountUsers(ctx, store.LockingStrengthNone, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	userInfos, err := m.accountManager.GetUsersFromAccount(ctx, accountID, activity.SystemInitiator)
+	if err != nil {
+		return nil, err
+	}
+
+	groupNames, err := m.groupNames(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*scim.User, 0, len(users))
+	for _, user := range users {
+		if user.IsServiceUser {
+			continue
+		}
+
+		scimUser := toSCIMUser(user, userInfos[user.Id], groupNames)
+		if scim.MatchFilter(filter, userAttributes(scimUser)) {
+			result = append(result, scimUser)
+		}
+	}
+
+	slices.SortFunc(result, func(a, b *scim.User) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	return result, nil
+}
+
+func (m *managerImpl) GetUser(ctx context.Context, accountID, id string) (*scim.User, error) {
+	user, err := m.getUser(ctx, accountID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	userInfos, err := m.accountManager.GetUsersFromAccount(ctx, accountID, activity.SystemInitiator)
+	if err != nil {
+		return nil, err
+	}
+
+	groupNames, err := m.groupNames(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toSCIMUser(user, userInfos[user.Id], groupNames), nil
+}
+
+func (m *managerImpl) CreateUser(ctx context.Context, accountID string, req *scim.User) (*scim.User, error) {
+	id := req.ExternalID
+	if id == "" {
+		return nil, scim.NewError(http.StatusBadRequest, scim.ErrTypeInvalidValue, "externalId is required, it has to be the subject of the user at the identity provider")
+	}
+
+	_, err := m.store.GetUserByUserID(ctx, store.LockingStrengthNone, id)
+	if err == nil {
+		return nil, scim.NewError(http.StatusConflict, scim.ErrTypeUniqueness, "user %s already exists", id)
+	}
+	if s, ok := status.FromError(err); !ok || s.Type() != status.NotFound {
+		return nil, err
+	}
+
+	email, name := userContact(req)
+	user := types.NewUser(id, types.UserRoleUser, false, false, "", []string{}, types.UserIssuedSCIM, email, name)
+	user.Blocked = req.Active != nil && !bool(*req.Active)
+
+	if _, err := m.accountManager.SaveOrAddUser(ctx, accountID, activity.SystemInitiator, user, true); err != nil {
+		return nil, err
+	}
+
+	return m.GetUser(ctx, accountID, id)
+}
+
+func (m *managerImpl) ReplaceUser(ctx context.Context, accountID, id string, req *scim.User) (*scim.User, error) {
+	user, err := m.getUser(ctx, accountID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.ExternalID != "" && req.ExternalID != user.Id {
+		return nil, scim.NewError(http.StatusBadRequest, scim.ErrTypeMutability, "externalId can't be changed")
+	}
+
+	email, name := userContact(req)
+	update := &userUpdate{email: &email, name: &name}
+	if req.Active != nil {
+		update.active = (*bool)(req.Active)
+	}
+
+	if err := m.updateUser(ctx, accountID, user, update); err != nil {
+		return nil, err
+	}
+
+	return m.GetUser(ctx, accountID, id)
+}
+
+func (m *managerImpl) PatchUser(ctx context.Context, accountID, id string, patchOperations []scim.PatchOperation) (*scim.User, error) {
+	user, err := m.getUser(ctx, accountID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	update := &userUpdate{}
+	for _, op := range patchOperations {
+		if err := update.apply(op); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := m.updateUser(ctx, accountID, user, update); err != nil {
+		return nil, err
+	}
+
+	return m.GetUser(ctx, accountID, id)
+}
+
+// DeleteUser deletes the user and its peers. Deactivating a user blocks it instead, which keeps the peers.
+func (m *managerImpl) DeleteUser(ctx context.Context, accountID, id string) error {
+	user, err := m.getUser(ctx, accountID, id)
+	if err != nil {
+		return err
+	}
+
+	if user.Role == types.UserRoleOwner {
+		return scim.NewError(http.StatusBadRequest, scim.ErrTypeMutability, "the owner of the account can't be deleted")
+	}
+
+	userInfos, err := m.accountManager.GetUsersFromAccount(ctx, accountID, activity.SystemInitiator)
+	if err != nil {
+		return err
+	}
+
+	return m.accountManager.DeleteRegularUsers(ctx, accountID, activity.SystemInitiator, []string{id}, userInfos)
+}
+
+// getUser returns a regular user of the account, service users are not managed by SCIM
+func (m *managerImpl) getUser(ctx context.Context, accountID, id string) (*types.User, error) {
+	user, err := m.store.GetUserByUserID(ctx, store.LockingStrengthNone, id)
+	if err != nil {
+		if s, ok := status.FromError(err); ok && s.Type() == status.NotFound {
+			return nil, scim.NewError(http.StatusNotFound, "", "user %s not found", id)
+		}
+		return nil, err
+	}
+
+	if user.AccountID != accountID || user.IsServiceUser {
+		return nil, scim.NewError(http.StatusNotFound, "", "user %s not found", id)
+	}
+
+	return user, nil
+}
+
+// userUpdate holds the attributes of a user changed by a replace or a patch
+type userUpdate struct {
+	email      *string
+	userName   *string
+	name       *string
+	givenName  *string
+	familyName *string
+	active     *bool
+}
+
+func (u *userUpdate) apply(op scim.PatchOperation) error {
+	switch strings.ToLower(op.Op) {
+	case "add", "replace":
+	case "remove":
+		// removing attributes of a user has no effect on NetBird
+		return nil
+	default:
+		return scim.NewError(http.StatusBadRequest, scim.ErrTypeInvalidSyntax, "unknown patch operation %q", op.Op)
+	}
+
+	if op.Path != "" {
+		return u.set(scim.NormalizePath(op.Path), op.Value)
+	}
+
+	var attributes map[string]json.RawMessage
+	if err := json.Unmarshal(op.Value, &attributes); err != nil {
+		return scim.NewError(http.StatusBadRequest, scim.ErrTypeInvalidValue, "patch value without path has to be an object")
+	}
+	for path, value := range attributes {
+		if err := u.set(scim.NormalizePath(path), value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// set sets an attribute of the update, attributes not used by NetBird are ignored
+func (u *userUpdate) set(path string, value json.RawMessage) error {
+	var err error
+	switch {
+	case path == "active":
+		var active scim.Bool
+		if err = json.Unmarshal(value, &active); err == nil {
+			u.active = (*bool)(&active)
+		}
+	case path == "username":
+		err = unmarshalString(value, &u.userName)
+	case path == "displayname" || path == "name.formatted":
+		err = unmarshalString(value, &u.name)
+	case path == "name.givenname":
+		err = unmarshalString(value, &u.givenName)
+	case path == "name.familyname":
+		err = unmarshalString(value, &u.familyName)
+	case path == "name":
+		var name scim.Name
+		if err = json.Unmarshal(value, &name); err == nil {
+			formatted := formatName(&name)
+			u.name = &formatted
+		}
+	case path == "emails":
+		var emails []scim.Email
+		if err = json.Unmarshal(value, &emails); err == nil {
+			email := primaryEmail(emails)
+			u.email = &email
+		}
+	case strings.HasPrefix(path, "emails["):
+		// e.g. emails[type eq "work"].value, a user has a single email in NetBird
+		err = unmarshalString(value, &u.email)
+	}
+
+	if err != nil {
+		return scim.NewError(http.StatusBadRequest, scim.ErrTypeInvalidValue, "invalid value of %s: %v", path, err)
+	}
+	return nil
+}
+
+func (m *managerImpl) updateUser(ctx context.Context, accountID string, user *types.User, update *userUpdate) error {
+	if update.active != nil && !*update.active && user.Role == types.UserRoleOwner {
+		return scim.NewError(http.StatusBadRequest, scim.ErrTypeMutability, "the owner of the account can't be deactivated")
+	}
+
+	email, name := user.Email, user.Name
+	if update.email != nil {
+		email = *update.email
+	} else if update.userName != nil && strings.Contains(*update.userName, "@") {
+		email = *update.userName
+	}
+	if update.name != nil {
+		name = *update.name
+	} else if update.givenName != nil || update.familyName != nil {
+		name = formatName(&scim.Name{GivenName: deref(update.givenName), FamilyName: deref(update.familyName)})
+	}
+
+	// blocking the user expires its peers right away
+	if update.active != nil && *update.active == user.Blocked {
+		blocked := user.Copy()
+		blocked.Blocked = !*update.active
+		if _, err := m.accountManager.SaveOrAddUser(ctx, accountID, activity.SystemInitiator, blocked, false); err != nil {
+			return err
+		}
+	}
+
+	if email != user.Email || name != user.Name {
+		err := m.store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+			current, err := transaction.GetUserByUserID(ctx, store.LockingStrengthUpdate, user.Id)
+			if err != nil {
+				return err
+			}
+			current.Email = email
+			current.Name = name
+			return transaction.SaveUser(ctx, current)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *managerImpl) ListGroups(ctx context.Context, accountID string, filter scim.Filter) ([]*scim.Group, error) {
+	groups, err := m.store.GetAccountGroups(ctx, store.LockingStrengthNone, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := m.groupMembers(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*scim.Group, 0, len(groups))
+	for _, group := range groups {
+		if group.IsGroupAll() {
+			continue
+		}
+
+		scimGroup := toSCIMGroup(group, members[group.ID])
+		if scim.MatchFilter(filter, groupAttributes(scimGroup)) {
+			result = append(result, scimGroup)
+		}
+	}
+
+	slices.SortFunc(result, func(a, b *scim.Group) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	return result, nil
+}
+
+func (m *managerImpl) GetGroup(ctx context.Context, accountID, id string) (*scim.Group, error) {
+	group, err := m.getGroup(ctx, accountID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := m.groupMembers(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toSCIMGroup(group, members[group.ID]), nil
+}
+
+func (m *managerImpl) CreateGroup(ctx context.Context, accountID string, req *scim.Group) (*scim.Group, error) {
+	if err := m.validateGroupName(ctx, accountID, "", req.DisplayName); err != nil {
+		return nil, err
+	}
+
+	group := &types.Group{
+		ID:     xid.New().String(),
+		Name:   req.DisplayName,
+		Issued: types.GroupIssuedSCIM,
+		Peers:  []string{},
+	}
+
+	updates, err := m.memberUpdates(ctx, accountID, group.ID, memberIDs(req.Members))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.accountManager.CreateGroup(ctx, accountID, activity.SystemInitiator, group); err != nil {
+		return nil, err
+	}
+
+	if _, err := m.accountManager.SaveOrAddUsers(ctx, accountID, activity.SystemInitiator, updates, false); err != nil {
+		return nil, err
+	}
+
+	return m.GetGroup(ctx, accountID, group.ID)
+}
+
+func (m *managerImpl) ReplaceGroup(ctx context.Context, accountID, id string, req *scim.Group) (*scim.Group, error) {
+	group, err := m.getGroup(ctx, accountID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updates, err := m.memberUpdates(ctx, accountID, id, memberIDs(req.Members))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.renameGroup(ctx, accountID, group, req.DisplayName); err != nil {
+		return nil, err
+	}
+
+	if _, err := m.accountManager.SaveOrAddUsers(ctx, accountID, activity.SystemInitiator, updates, false); err != nil {
+		return nil, err
+	}
+
+	return m.GetGroup(ctx, accountID, id)
+}
+
+func (m *managerImpl) PatchGroup(ctx context.Context, accountID, id string, patchOperations []scim.PatchOperation) (*scim.Group, error) {
+	group, err := m.getGroup(ctx, accountID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := m.groupMembers(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	update := &groupUpdate{members: memberIDs(members[id])}
+	for _, op := range patchOperations {
+		if err := update.apply(op); err != nil {
+			return nil, err
+		}
+	}
+
+	updates, err := m.memberUpdates(ctx, accountID, id, update.members)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.name != nil {
+		if err := m.renameGroup(ctx, accountID, group, *update.name); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := m.accountManager.SaveOrAddUsers(ctx, accountID, activity.SystemInitiator, updates, false); err != nil {
+		return nil, err
+	}
+
+	return m.GetGroup(ctx, accountID, id)
+}
+
+// DeleteGroup removes the group from the auto groups of its members and deletes it.
+// It fails when the group is still used by policies, routes or other resources.
+func (m *managerImpl) DeleteGroup(ctx context.Context, accountID, id string) error {
+	if _, err := m.getGroup(ctx, accountID, id); err != nil {
+		return err
+	}
+
+	updates, err := m.memberUpdates(ctx, accountID, id, nil)
+	if err != nil {
+		return err
+	}
+
+	if _, err := m.accountManager.SaveOrAddUsers(ctx, accountID, activity.SystemInitiator, updates, false); err != nil {
+		return err
+	}
+
+	return m.accountManager.DeleteGroup(ctx, accountID, activity.SystemInitiator, id)
+}
+
+func (m *managerImpl) getGroup(ctx context.Context, accountID, id string) (*types.Group, error) {
+	group, err := m.store.GetGroupByID(ctx, store.LockingStrengthNone, accountID, id)
+	if err != nil {
+		if s, ok := status.FromError(err); ok && s.Type() == status.NotFound {
+			return nil, scim.NewError(http.StatusNotFound, "", "group %s not found", id)
+		}
+		return nil, err
+	}
+
+	if group.IsGroupAll() {
+		return nil, scim.NewError(http.StatusNotFound, "", "group %s not found", id)
+	}
+
+	return group, nil
+}
+
+// validateGroupName checks that the name is set and not used by another group than the one with the id
+func (m *managerImpl) validateGroupName(ctx context.Context, accountID, id, name string) error {
+	if name == "" {
+		return scim.NewError(http.StatusBadRequest, scim.ErrTypeInvalidValue, "displayName is required")
+	}
+
+	existing, err := m.store.GetGroupByName(ctx, store.LockingStrengthNone, accountID, name)
+	if err != nil {
+		if s, ok := status.FromError(err); ok && s.Type() == status.NotFound {
+			return nil
+		}
+		return err
+	}
+
+	if existing.ID != id {
+		return scim.NewError(http.StatusConflict, scim.ErrTypeUniqueness, "group with name %s already exists", name)
+	}
+
+	return nil
+}
+
+func (m *managerImpl) renameGroup(ctx context.Context, accountID string, group *types.Group, name string) error {
+	if name == group.Name {
+		return nil
+	}
+
+	if err := m.validateGroupName(ctx, accountID, group.ID, name); err != nil {
+		return err
+	}
+
+	// the update keeps the peers and resources of the group
+	renamed := group.Copy()
+	renamed.Name = name
+	return m.accountManager.UpdateGroup(ctx, accountID, activity.SystemInitiator, renamed)
+}
+
+// memberUpdates returns the user updates that make the users the members of the group,
+// the members are the users that have the group in their auto groups
+func (m *managerImpl) memberUpdates(ctx context.Context, accountID, groupID string, userIDs []string) ([]*types.User, error) {
+	users, err := m.store.GetAccountUsers(ctx, store.LockingStrengthNone, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	regularUsers := make(map[string]*types.User, len(users))
+	for _, user := range users {
+		if !user.IsServiceUser {
+			regularUsers[user.Id] = user
+		}
+	}
+
+	for _, id := range userIDs {
+		if _, ok := regularUsers[id]; !ok {
+			return nil, scim.NewError(http.StatusBadRequest, scim.ErrTypeInvalidValue, "member %s is not a user of the account", id)
+		}
+	}
+
+	var updates []*types.User
+	for id, user := range regularUsers {
+		member := slices.Contains(userIDs, id)
+		if member == slices.Contains(user.AutoGroups, groupID) {
+			continue
+		}
+
+		update := user.Copy()
+		if member {
+			update.AutoGroups = append(update.AutoGroups, groupID)
+		} else {
+			update.AutoGroups = slices.DeleteFunc(update.AutoGroups, func(g string) bool {
+				return g == groupID
+			})
+		}
+		updates = append(updates, update)
+	}
+
+	return updates, nil
+}
+
+// groupMembers returns the members of the groups of the account by group ID
+func (m *managerImpl) groupMembers(ctx context.Context, accountID string) (map[string][]scim.Member, error) {
+	users, err := m.store.GetAccountUsers(ctx, store.LockingStrengthNone, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make(map[string][]scim.Member)
+	for _, user := range users {
+		if user.IsServiceUser {
+			continue
+		}
+		for _, groupID := range user.AutoGroups {
+			members[groupID] = append(members[groupID], scim.Member{Value: user.Id, Display: userDisplay(user)})
+		}
+	}
+
+	for _, groupMembers := range members {
+		slices.SortFunc(groupMembers, func(a, b scim.Member) int {
+			return strings.Compare(a.Value, b.Value)
+		})
+	}
+
+	return members, nil
+}
+
+func (m *managerImpl) groupNames(ctx context.Context, accountID string) (map[string]string, error) {
+	groups, err := m.store.GetAccountGroups(ctx, store.LockingStrengthNone, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]string, len(groups))
+	for _, group := range groups {
+		names[group.ID] = group.Name
+	}
+	return names, nil
+}
+
+// groupUpdate holds the attributes of a group changed by a patch
+type groupUpdate struct {
+	name    *string
+	members []string
+}
+
+func (g *groupUpdate) apply(op scim.PatchOperation) error {
+	operation := strings.ToLower(op.Op)
+	if operation != "add" && operation != "replace" && operation != "remove" {
+		return scim.NewError(http.StatusBadRequest, scim.ErrTypeInvalidSyntax, "unknown patch operation %q", op.Op)
+	}
+
+	path := scim.NormalizePath(op.Path)
+	switch {
+	case path == "":
+		if operation == "remove" {
+			return scim.NewError(http.StatusBadRequest, scim.ErrTypeNoTarget, "remove requires a path")
+		}
+
+		var attributes map[string]json.RawMessage
+		if err := json.Unmarshal(op.Value, &attributes); err != nil {
+			return scim.NewError(http.StatusBadRequest, scim.ErrTypeInvalidValue, "patch value without path has to be an object")
+		}
+		for attr, value := range attributes {
+			if err := g.apply(scim.PatchOperation{Op: op.Op, Path: attr, Value: value}); err != nil {
+				return err
+			}
+		}
+	case path == "displayname":
+		if operation == "remove" {
+			return scim.NewError(http.StatusBadRequest, scim.ErrTypeMutability, "displayName is required")
+		}
+		if err := unmarshalString(op.Value, &g.name); err != nil {
+			return scim.NewError(http.StatusBadRequest, scim.ErrTypeInvalidValue, "invalid value of displayName: %v", err)
+		}
+	case path == "members":
+		var members []scim.Member
+		if len(op.Value) > 0 {
+			if err := json.Unmarshal(op.Value, &members); err != nil {
+				return scim.NewError(http.StatusBadRequest, scim.ErrTypeInvalidValue, "invalid value of members: %v", err)
+			}
+		}
+		g.applyMembers(operation, memberIDs(members))
+	case strings.HasPrefix(path, "members["):
+		// e.g. members[value eq "user-id"], only used to remove members
+		if operation != "remove" {
+			return scim.NewError(http.StatusBadRequest, scim.ErrTypeInvalidPath, "filtered member paths are only supported for remove")
+		}
+		end := strings.LastIndex(op.Path, "]")
+		if end < 0 {
+			return scim.NewError(http.StatusBadRequest, scim.ErrTypeInvalidPath, "invalid path %s", op.Path)
+		}
+		filter, err := scim.ParseFilter(op.Path[len("members["):end])
+		if err != nil {
+			return err
+		}
+		g.members = slices.DeleteFunc(g.members, func(id string) bool {
+			return scim.MatchFilter(filter, memberAttributes(id))
+		})
+	}
+
+	return nil
+}
+
+func (g *groupUpdate) applyMembers(operation string, ids []string) {
+	switch operation {
+	case "replace":
+		g.members = ids
+	case "add":
+		for _, id := range ids {
+			if !slices.Contains(g.members, id) {
+				g.members = append(g.members, id)
+			}
+		}
+	case "remove":
+		if len(ids) == 0 {
+			g.members = nil
+			return
+		}
+		g.members = slices.DeleteFunc(g.members, func(id string) bool {
+			return slices.Contains(ids, id)
+		})
+	}
+}
+
+func toSCIMUser(user *types.User, userInfo *types.UserInfo, groupNames map[string]string) *scim.User {
+	email, name := user.Email, user.Name
+	if userInfo != nil {
+		if email == "" {
+			email = userInfo.Email
+		}
+		if name == "" {
+			name = userInfo.Name
+		}
+	}
+
+	userName := email
+	if userName == "" {
+		userName = user.Id
+	}
+
+	active := scim.Bool(!user.Blocked)
+	scimUser := &scim.User{
+		Schemas:     []string{scim.SchemaUser},
+		ID:          user.Id,
+		ExternalID:  user.Id,
+		UserName:    userName,
+		DisplayName: name,
+		Active:      &active,
+		Meta:        &scim.Meta{ResourceType: scim.ResourceTypeUser},
+	}
+	if name != "" {
+		scimUser.Name = &scim.Name{Formatted: name}
+	}
+	if email != "" {
+		scimUser.Emails = []scim.Email{{Value: email, Type: "work", Primary: true}}
+	}
+	if !user.CreatedAt.IsZero() {
+		created := user.CreatedAt.UTC()
+		scimUser.Meta.Created = &created
+	}
+
+	for _, groupID := range user.AutoGroups {
+		if groupName, ok := groupNames[groupID]; ok {
+			scimUser.Groups = append(scimUser.Groups, scim.Member{Value: groupID, Display: groupName})
+		}
+	}
+
+	return scimUser
+}
+
+func toSCIMGroup(group *types.Group, members []scim.Member) *scim.Group {
+	return &scim.Group{
+		Schemas:     []string{scim.SchemaGroup},
+		ID:          group.ID,
+		DisplayName: group.Name,
+		Members:     members,
+		Meta:        &scim.Meta{ResourceType: scim.ResourceTypeGroup},
+	}
+}
+
+func userAttributes(user *scim.User) scim.Attributes {
+	return func(path string) []string {
+		switch path {
+		case "id":
+			return []string{user.ID}
+		case "externalid":
+			return []string{user.ExternalID}
+		case "username":
+			return []string{user.UserName}
+		case "displayname", "name.formatted":
+			return []string{user.DisplayName}
+		case "active":
+			return []string{strconv.FormatBool(user.Active == nil || bool(*user.Active))}
+		case "emails", "emails.value":
+			values := make([]string, 0, len(user.Emails))
+			for _, email := range user.Emails {
+				values = append(values, email.Value)
+			}
+			return values
+		case "groups", "groups.value":
+			return memberIDs(user.Groups)
+		case "meta.created":
+			if user.Meta != nil && user.Meta.Created != nil {
+				return []string{user.Meta.Created.Format(time.RFC3339)}
+			}
+		}
+		return nil
+	}
+}
+
+func groupAttributes(group *scim.Group) scim.Attributes {
+	return func(path string) []string {
+		switch path {
+		case "id":
+			return []string{group.ID}
+		case "displayname":
+			return []string{group.DisplayName}
+		case "members", "members.value":
+			return memberIDs(group.Members)
+		}
+		return nil
+	}
+}
+
+func memberAttributes(id string) scim.Attributes {
+	return func(path string) []string {
+		if path == "value" {
+			return []string{id}
+		}
+		return nil
+	}
+}
+
+// userContact returns the email and the name of a SCIM user
+func userContact(user *scim.User) (string, string) {
+	email := primaryEmail(user.Emails)
+	if email == "" && strings.Contains(user.UserName, "@") {
+		email = user.UserName
+	}
+
+	name := user.DisplayName
+	if name == "" && user.Name != nil {
+		name = formatName(user.Name)
+	}
+
+	return email, name
+}
+
+func primaryEmail(emails []scim.Email) string {
+	for _, email := range emails {
+		if email.Primary {
+			return email.Value
+		}
+	}
+	if len(emails) > 0 {
+		return emails[0].Value
+	}
+	return ""
+}
+
+func formatName(name *scim.Name) string {
+	if name.Formatted != "" {
+		return name.Formatted
+	}
+	return strings.TrimSpace(name.GivenName + " " + name.FamilyName)
+}
+
+func userDisplay(user *types.User) string {
+	if user.Name != "" {
+		return user.Name
+	}
+	return user.Email
+}
+
+func memberIDs(members []scim.Member) []string {
+	ids := make([]string, 0, len(members))
+	for _, member := range members {
+		ids = append(ids, member.Value)
+	}
+	return ids
+}
+
+func unmarshalString(value json.RawMessage, target **string) error {
+	var s string
+	if err := json.Unmarshal(value, &s); err != nil {
+		return err
+	}
+	*target = &s
+	return nil
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
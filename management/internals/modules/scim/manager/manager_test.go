@@ -0,0 +1,406 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/management/internals/modules/scim"
+	"github.com/netbirdio/netbird/management/server/activity"
+	"github.com/netbirdio/netbird/management/server/mock_server"
+	"github.com/netbirdio/netbird/management/server/permissions"
+	"github.com/netbirdio/netbird/management/server/permissions/modules"
+	"github.com/netbirdio/netbird/management/server/permissions/operations"
+	"github.com/netbirdio/netbird/management/server/store"
+	"github.com/netbirdio/netbird/management/server/types"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+const (
+	testAccountID = "test-account-id"
+	testAdminID   = "test-admin-id"
+	testUserID    = "test-user-id"
+	testServiceID = "test-service-id"
+	testGroupID   = "test-group-id"
+	testAllID     = "test-all-id"
+)
+
+func setupTest(t *testing.T) (*managerImpl, store.Store, *mock_server.MockAccountManager, *permissions.MockManager) {
+	t.Helper()
+
+	ctx := context.Background()
+	testStore, cleanup, err := store.NewTestStoreFromSQL(ctx, "", t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(cleanup)
+
+	err = testStore.SaveAccount(ctx, &types.Account{
+		Id: testAccountID,
+		Users: map[string]*types.User{
+			testAdminID: {
+				Id:        testAdminID,
+				AccountID: testAccountID,
+				Role:      types.UserRoleOwner,
+				Email:     "admin@example.com",
+			},
+			testUserID: {
+				Id:         testUserID,
+				AccountID:  testAccountID,
+				Role:       types.UserRoleUser,
+				Email:      "alice@example.com",
+				Name:       "Alice",
+				AutoGroups: []string{testGroupID},
+			},
+			testServiceID: {
+				Id:            testServiceID,
+				AccountID:     testAccountID,
+				Role:          types.UserRoleAdmin,
+				IsServiceUser: true,
+			},
+		},
+		Groups: map[string]*types.Group{
+			testGroupID: {
+				ID:        testGroupID,
+				AccountID: testAccountID,
+				Name:      "engineering",
+				Issued:    types.GroupIssuedAPI,
+				Peers:     []string{},
+			},
+			testAllID: {
+				ID:        testAllID,
+				AccountID: testAccountID,
+				Name:      "All",
+				Issued:    types.GroupIssuedAPI,
+				Peers:     []string{},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	mockAccountManager := &mock_server.MockAccountManager{}
+	mockPermissionsManager := permissions.NewMockManager(ctrl)
+
+	// the account manager persists the changes, the manager is expected to act as the system
+	saveUsers := func(ctx context.Context, accountID, initiator string, users []*types.User, addIfNotExists bool) error {
+		assert.Equal(t, activity.SystemInitiator, initiator)
+		for _, user := range users {
+			if _, err := testStore.GetUserByUserID(ctx, store.LockingStrengthNone, user.Id); err != nil && !addIfNotExists {
+				return err
+			}
+			user.AccountID = accountID
+			if err := testStore.SaveUser(ctx, user); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	mockAccountManager.SaveOrAddUserFunc = func(ctx context.Context, accountID, initiator string, user *types.User, addIfNotExists bool) (*types.UserInfo, error) {
+		return &types.UserInfo{ID: user.Id}, saveUsers(ctx, accountID, initiator, []*types.User{user}, addIfNotExists)
+	}
+	mockAccountManager.SaveOrAddUsersFunc = func(ctx context.Context, accountID, initiator string, users []*types.User, addIfNotExists bool) ([]*types.UserInfo, error) {
+		return nil, saveUsers(ctx, accountID, initiator, users, addIfNotExists)
+	}
+	mockAccountManager.GetUsersFromAccountFunc = func(ctx context.Context, accountID, initiator string) (map[string]*types.UserInfo, error) {
+		assert.Equal(t, activity.SystemInitiator, initiator)
+		users, err := testStore.GetAccountUsers(ctx, store.LockingStrengthNone, accountID)
+		if err != nil {
+			return nil, err
+		}
+		infos := make(map[string]*types.UserInfo, len(users))
+		for _, user := range users {
+			infos[user.Id] = &types.UserInfo{ID: user.Id}
+		}
+		return infos, nil
+	}
+	mockAccountManager.SaveGroupFunc = func(ctx context.Context, accountID, initiator string, group *types.Group, create bool) error {
+		assert.Equal(t, activity.SystemInitiator, initiator)
+		group.AccountID = accountID
+		if create {
+			return testStore.CreateGroup(ctx, group)
+		}
+		return testStore.UpdateGroup(ctx, group)
+	}
+	mockAccountManager.DeleteGroupFunc = func(ctx context.Context, accountID, initiator, groupID string) error {
+		assert.Equal(t, activity.SystemInitiator, initiator)
+		return testStore.DeleteGroup(ctx, accountID, groupID)
+	}
+
+	manager := &managerImpl{
+		store:              testStore,
+		accountManager:     mockAccountManager,
+		permissionsManager: mockPermissionsManager,
+	}
+
+	return manager, testStore, mockAccountManager, mockPermissionsManager
+}
+
+func requireSCIMError(t *testing.T, err error, code int) {
+	t.Helper()
+
+	var scimErr *scim.Error
+	require.True(t, errors.As(err, &scimErr), "expected a SCIM error, got %v", err)
+	assert.Equal(t, code, scimErr.Code())
+}
+
+func TestManagerImpl_Token(t *testing.T) {
+	ctx := context.Background()
+	manager, _, mockAccountManager, mockPermissionsManager := setupTest(t)
+
+	var events []activity.ActivityDescriber
+	mockAccountManager.StoreEventFunc = func(ctx context.Context, initiatorID, targetID, accountID string, activityID activity.ActivityDescriber, meta map[string]any) {
+		assert.Equal(t, testAdminID, initiatorID)
+		events = append(events, activityID)
+	}
+	mockPermissionsManager.EXPECT().
+		ValidateUserPermissions(gomock.Any(), testAccountID, testAdminID, modules.Settings, gomock.Any()).
+		Return(true, nil).AnyTimes()
+
+	_, err := manager.GetToken(ctx, testAccountID, testAdminID)
+	require.Error(t, err)
+
+	first, err := manager.CreateToken(ctx, testAccountID, testAdminID)
+	require.NoError(t, err)
+
+	accountID, err := manager.AuthenticateToken(ctx, first.PlainToken)
+	require.NoError(t, err)
+	assert.Equal(t, testAccountID, accountID)
+
+	token, err := manager.GetToken(ctx, testAccountID, testAdminID)
+	require.NoError(t, err)
+	require.NotNil(t, token.LastUsed, "authentication marks the token as used")
+
+	// creating a new token replaces the old one
+	second, err := manager.CreateToken(ctx, testAccountID, testAdminID)
+	require.NoError(t, err)
+	_, err = manager.AuthenticateToken(ctx, first.PlainToken)
+	assert.Error(t, err)
+	_, err = manager.AuthenticateToken(ctx, second.PlainToken)
+	assert.NoError(t, err)
+
+	require.NoError(t, manager.DeleteToken(ctx, testAccountID, testAdminID))
+	_, err = manager.AuthenticateToken(ctx, second.PlainToken)
+	sErr, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, status.Unauthenticated, sErr.Type())
+
+	assert.Equal(t, []activity.ActivityDescriber{activity.SCIMTokenCreated, activity.SCIMTokenCreated, activity.SCIMTokenDeleted}, events)
+}
+
+func TestManagerImpl_Token_PermissionDenied(t *testing.T) {
+	manager, _, _, mockPermissionsManager := setupTest(t)
+
+	mockPermissionsManager.EXPECT().
+		ValidateUserPermissions(gomock.Any(), testAccountID, testUserID, modules.Settings, operations.Update).
+		Return(false, nil)
+
+	_, err := manager.CreateToken(context.Background(), testAccountID, testUserID)
+	sErr, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, status.PermissionDenied, sErr.Type())
+}
+
+func TestManagerImpl_ListUsers(t *testing.T) {
+	manager, _, _, _ := setupTest(t)
+
+	users, err := manager.ListUsers(context.Background(), testAccountID, nil)
+	require.NoError(t, err)
+	require.Len(t, users, 2, "service users are not listed")
+
+	filter, err := scim.ParseFilter(`userName eq "Alice@example.com"`)
+	require.NoError(t, err)
+	users, err = manager.ListUsers(context.Background(), testAccountID, filter)
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+
+	alice := users[0]
+	assert.Equal(t, testUserID, alice.ID)
+	assert.Equal(t, testUserID, alice.ExternalID)
+	assert.Equal(t, "Alice", alice.DisplayName)
+	assert.True(t, bool(*alice.Active))
+	require.Len(t, alice.Groups, 1)
+	assert.Equal(t, scim.Member{Value: testGroupID, Display: "engineering"}, alice.Groups[0])
+}
+
+func TestManagerImpl_CreateUser(t *testing.T) {
+	ctx := context.Background()
+	manager, testStore, _, _ := setupTest(t)
+
+	_, err := manager.CreateUser(ctx, testAccountID, &scim.User{UserName: "bob@example.com"})
+	requireSCIMError(t, err, http.StatusBadRequest)
+
+	_, err = manager.CreateUser(ctx, testAccountID, &scim.User{ExternalID: testUserID, UserName: "alice@example.com"})
+	requireSCIMError(t, err, http.StatusConflict)
+
+	inactive := scim.Bool(false)
+	user, err := manager.CreateUser(ctx, testAccountID, &scim.User{
+		ExternalID: "bob-subject",
+		UserName:   "bob",
+		Name:       &scim.Name{GivenName: "Bob", FamilyName: "Builder"},
+		Emails:     []scim.Email{{Value: "other@example.com"}, {Value: "bob@example.com", Primary: true}},
+		Active:     &inactive,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "bob-subject", user.ID)
+	assert.Equal(t, "bob@example.com", user.UserName)
+	assert.False(t, bool(*user.Active))
+
+	stored, err := testStore.GetUserByUserID(ctx, store.LockingStrengthNone, "bob-subject")
+	require.NoError(t, err)
+	assert.Equal(t, testAccountID, stored.AccountID)
+	assert.Equal(t, types.UserRoleUser, stored.Role)
+	assert.Equal(t, types.UserIssuedSCIM, stored.Issued)
+	assert.Equal(t, "Bob Builder", stored.Name)
+	assert.True(t, stored.Blocked)
+}
+
+func TestManagerImpl_PatchUser(t *testing.T) {
+	ctx := context.Background()
+	manager, testStore, _, _ := setupTest(t)
+
+	// Entra ID sends the booleans as strings
+	user, err := manager.PatchUser(ctx, testAccountID, testUserID, []scim.PatchOperation{
+		{Op: "Replace", Path: "active", Value: json.RawMessage(`"False"`)},
+		{Op: "Replace", Path: `emails[type eq "work"].value`, Value: json.RawMessage(`"alice@corp.example"`)},
+	})
+	require.NoError(t, err)
+	assert.False(t, bool(*user.Active))
+	assert.Equal(t, "alice@corp.example", user.UserName)
+
+	stored, err := testStore.GetUserByUserID(ctx, store.LockingStrengthNone, testUserID)
+	require.NoError(t, err)
+	assert.True(t, stored.Blocked)
+	assert.Equal(t, []string{testGroupID}, stored.AutoGroups, "auto groups are kept")
+
+	// Okta sends the attributes without path
+	user, err = manager.PatchUser(ctx, testAccountID, testUserID, []scim.PatchOperation{
+		{Op: "replace", Value: json.RawMessage(`{"active": true, "displayName": "Alice A."}`)},
+	})
+	require.NoError(t, err)
+	assert.True(t, bool(*user.Active))
+	assert.Equal(t, "Alice A.", user.DisplayName)
+
+	_, err = manager.PatchUser(ctx, testAccountID, testUserID, []scim.PatchOperation{{Op: "move", Path: "active"}})
+	requireSCIMError(t, err, http.StatusBadRequest)
+
+	_, err = manager.PatchUser(ctx, testAccountID, testServiceID, []scim.PatchOperation{{Op: "replace", Path: "active", Value: json.RawMessage(`false`)}})
+	requireSCIMError(t, err, http.StatusNotFound)
+
+	_, err = manager.PatchUser(ctx, testAccountID, testAdminID, []scim.PatchOperation{{Op: "replace", Path: "active", Value: json.RawMessage(`false`)}})
+	requireSCIMError(t, err, http.StatusBadRequest)
+	owner, err := testStore.GetUserByUserID(ctx, store.LockingStrengthNone, testAdminID)
+	require.NoError(t, err)
+	assert.False(t, owner.Blocked, "the owner can't be deactivated")
+}
+
+func TestManagerImpl_ReplaceUser(t *testing.T) {
+	ctx := context.Background()
+	manager, _, _, _ := setupTest(t)
+
+	_, err := manager.ReplaceUser(ctx, testAccountID, testUserID, &scim.User{ExternalID: "other", UserName: "alice@example.com"})
+	requireSCIMError(t, err, http.StatusBadRequest)
+
+	user, err := manager.ReplaceUser(ctx, testAccountID, testUserID, &scim.User{
+		UserName:    "alice@example.com",
+		DisplayName: "Alice Liddell",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Alice Liddell", user.DisplayName)
+	assert.True(t, bool(*user.Active), "active is unchanged when not set")
+
+	inactive := scim.Bool(false)
+	_, err = manager.ReplaceUser(ctx, testAccountID, testAdminID, &scim.User{UserName: "admin@example.com", Active: &inactive})
+	requireSCIMError(t, err, http.StatusBadRequest)
+}
+
+func TestManagerImpl_DeleteUser(t *testing.T) {
+	ctx := context.Background()
+	manager, _, mockAccountManager, _ := setupTest(t)
+
+	var deleted []string
+	mockAccountManager.DeleteRegularUsersFunc = func(ctx context.Context, accountID, initiator string, targetUserIDs []string, userInfos map[string]*types.UserInfo) error {
+		assert.Equal(t, activity.SystemInitiator, initiator)
+		assert.Contains(t, userInfos, testUserID)
+		deleted = append(deleted, targetUserIDs...)
+		return nil
+	}
+
+	require.NoError(t, manager.DeleteUser(ctx, testAccountID, testUserID))
+	assert.Equal(t, []string{testUserID}, deleted)
+
+	err := manager.DeleteUser(ctx, testAccountID, testAdminID)
+	requireSCIMError(t, err, http.StatusBadRequest)
+
+	err = manager.DeleteUser(ctx, testAccountID, "unknown")
+	requireSCIMError(t, err, http.StatusNotFound)
+}
+
+func TestManagerImpl_Groups(t *testing.T) {
+	ctx := context.Background()
+	manager, testStore, _, _ := setupTest(t)
+
+	groups, err := manager.ListGroups(ctx, testAccountID, nil)
+	require.NoError(t, err)
+	require.Len(t, groups, 1, "the All group is not listed")
+	assert.Equal(t, []scim.Member{{Value: testUserID, Display: "Alice"}}, groups[0].Members)
+
+	_, err = manager.CreateGroup(ctx, testAccountID, &scim.Group{DisplayName: "engineering"})
+	requireSCIMError(t, err, http.StatusConflict)
+
+	_, err = manager.CreateGroup(ctx, testAccountID, &scim.Group{DisplayName: "ops", Members: []scim.Member{{Value: testServiceID}}})
+	requireSCIMError(t, err, http.StatusBadRequest)
+
+	group, err := manager.CreateGroup(ctx, testAccountID, &scim.Group{
+		DisplayName: "ops",
+		Members:     []scim.Member{{Value: testUserID}, {Value: testAdminID}},
+	})
+	require.NoError(t, err)
+	assert.Len(t, group.Members, 2)
+
+	stored, err := testStore.GetGroupByID(ctx, store.LockingStrengthNone, testAccountID, group.ID)
+	require.NoError(t, err)
+	assert.Equal(t, types.GroupIssuedSCIM, stored.Issued)
+
+	user, err := testStore.GetUserByUserID(ctx, store.LockingStrengthNone, testUserID)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{testGroupID, group.ID}, user.AutoGroups)
+
+	filter, err := scim.ParseFilter(`displayName eq "OPS"`)
+	require.NoError(t, err)
+	groups, err = manager.ListGroups(ctx, testAccountID, filter)
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	assert.Equal(t, group.ID, groups[0].ID)
+
+	group, err = manager.PatchGroup(ctx, testAccountID, group.ID, []scim.PatchOperation{
+		{Op: "remove", Path: `members[value eq "` + testUserID + `"]`},
+		{Op: "replace", Path: "displayName", Value: json.RawMessage(`"operations"`)},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "operations", group.DisplayName)
+	assert.Equal(t, []scim.Member{{Value: testAdminID, Display: "admin@example.com"}}, group.Members)
+
+	user, err = testStore.GetUserByUserID(ctx, store.LockingStrengthNone, testUserID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{testGroupID}, user.AutoGroups)
+
+	group, err = manager.ReplaceGroup(ctx, testAccountID, group.ID, &scim.Group{DisplayName: "operations", Members: []scim.Member{{Value: testUserID}}})
+	require.NoError(t, err)
+	assert.Equal(t, []scim.Member{{Value: testUserID, Display: "Alice"}}, group.Members)
+
+	require.NoError(t, manager.DeleteGroup(ctx, testAccountID, group.ID))
+	_, err = testStore.GetGroupByID(ctx, store.LockingStrengthNone, testAccountID, group.ID)
+	assert.Error(t, err)
+
+	user, err = testStore.GetUserByUserID(ctx, store.LockingStrengthNone, testUserID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{testGroupID}, user.AutoGroups, "the deleted group is removed from the members")
+
+	_, err = manager.GetGroup(ctx, testAccountID, testAllID)
+	requireSCIMError(t, err, http.StatusNotFound)
+}
@@ -0,0 +1,498 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/management/internals/modules/scim"
+	nbcontext "github.com/netbirdio/netbird/management/server/context"
+)
+
+const (
+	contentType = "application/scim+json"
+
+	resourceUsers  = "Users"
+	resourceGroups = "Groups"
+
+	defaultCount = 100
+	maxCount     = 1000
+
+	maxBulkOperations  = 100
+	maxBulkPayloadSize = 1 << 20
+	maxPayloadSize     = 1 << 20
+)
+
+// RegisterProvisioningEndpoints registers the SCIM 2.0 endpoints (RFC 7644) the identity provider pushes users
+// and groups to. The router has to be mounted at scim.PathPrefix, outside of the management API authentication,
+// the requests are authenticated with the SCIM token of the account.
+func RegisterProvisioningEndpoints(router *mux.Router, manager scim.Manager) {
+	h := &handler{
+		manager: manager,
+	}
+
+	router.Use(h.authenticate)
+
+	router.HandleFunc("/ServiceProviderConfig", h.getServiceProviderConfig).Methods("GET")
+	router.HandleFunc("/ResourceTypes", h.getResourceTypes).Methods("GET")
+	router.HandleFunc("/Bulk", h.bulk).Methods("POST")
+
+	for _, resourceType := range []string{resourceUsers, resourceGroups} {
+		router.HandleFunc("/"+resourceType, h.list(resourceType)).Methods("GET")
+		router.HandleFunc("/"+resourceType, h.resource(resourceType)).Methods("POST")
+		router.HandleFunc("/"+resourceType+"/{id}", h.resource(resourceType)).Methods("GET", "PUT", "PATCH", "DELETE")
+	}
+}
+
+type accountIDKey struct{}
+
+// authenticate resolves the account of the SCIM token of the request
+func (h *handler) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok {
+			writeSCIMError(r.Context(), w, scim.NewError(http.StatusUnauthorized, "", "missing bearer token"))
+			return
+		}
+
+		accountID, err := h.manager.AuthenticateToken(r.Context(), strings.TrimSpace(token))
+		if err != nil {
+			writeSCIMError(r.Context(), w, err)
+			return
+		}
+
+		//nolint
+		ctx := context.WithValue(r.Context(), nbcontext.AccountIDKey, accountID)
+		ctx = context.WithValue(ctx, accountIDKey{}, accountID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func accountIDFromContext(ctx context.Context) string {
+	accountID, _ := ctx.Value(accountIDKey{}).(string)
+	return accountID
+}
+
+func (h *handler) list(resourceType string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		query := r.URL.Query()
+
+		filter, err := scim.ParseFilter(query.Get("filter"))
+		if err != nil {
+			writeSCIMError(ctx, w, err)
+			return
+		}
+
+		startIndex, count, err := pagination(query.Get("startIndex"), query.Get("count"))
+		if err != nil {
+			writeSCIMError(ctx, w, err)
+			return
+		}
+
+		var resources []any
+		switch resourceType {
+		case resourceUsers:
+			users, err := h.manager.ListUsers(ctx, accountIDFromContext(ctx), filter)
+			if err != nil {
+				writeSCIMError(ctx, w, err)
+				return
+			}
+			for _, user := range users {
+				resources = append(resources, user)
+			}
+		case resourceGroups:
+			groups, err := h.manager.ListGroups(ctx, accountIDFromContext(ctx), filter)
+			if err != nil {
+				writeSCIMError(ctx, w, err)
+				return
+			}
+			for _, group := range groups {
+				resources = append(resources, group)
+			}
+		}
+
+		response := &scim.ListResponse{
+			Schemas:      []string{scim.SchemaListResponse},
+			TotalResults: len(resources),
+			StartIndex:   startIndex,
+			Resources:    []any{},
+		}
+
+		base := baseURL(r)
+		excluded := excludedAttributes(query.Get("excludedAttributes"))
+		for i := startIndex - 1; i < len(resources) && len(response.Resources) < count; i++ {
+			setLocation(base, resources[i], excluded)
+			response.Resources = append(response.Resources, resources[i])
+		}
+		response.ItemsPerPage = len(response.Resources)
+
+		writeSCIMResponse(ctx, w, http.StatusOK, response)
+	}
+}
+
+func (h *handler) resource(resourceType string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPayloadSize))
+		if err != nil {
+			writeSCIMError(ctx, w, scim.NewError(http.StatusRequestEntityTooLarge, "", "request body too large"))
+			return
+		}
+
+		resource, code, err := h.execute(ctx, accountIDFromContext(ctx), r.Method, resourceType, mux.Vars(r)["id"], data)
+		if err != nil {
+			writeSCIMError(ctx, w, err)
+			return
+		}
+
+		if resource == nil {
+			w.WriteHeader(code)
+			return
+		}
+
+		location := setLocation(baseURL(r), resource, excludedAttributes(r.URL.Query().Get("excludedAttributes")))
+		if code == http.StatusCreated {
+			w.Header().Set("Location", location)
+		}
+		writeSCIMResponse(ctx, w, code, resource)
+	}
+}
+
+// execute runs an operation on a user or a group and returns the resource and the HTTP status code of the result
+func (h *handler) execute(ctx context.Context, accountID, method, resourceType, id string, data []byte) (any, int, error) {
+	if method != http.MethodPost && id == "" {
+		return nil, 0, scim.NewError(http.StatusBadRequest, scim.ErrTypeInvalidPath, "%s requires the id of the resource", method)
+	}
+
+	switch resourceType {
+	case resourceUsers:
+		return h.executeUser(ctx, accountID, method, id, data)
+	case resourceGroups:
+		return h.executeGroup(ctx, accountID, method, id, data)
+	default:
+		return nil, 0, scim.NewError(http.StatusNotFound, "", "unknown resource type %s", resourceType)
+	}
+}
+
+func (h *handler) executeUser(ctx context.Context, accountID, method, id string, data []byte) (any, int, error) {
+	switch method {
+	case http.MethodGet:
+		user, err := h.manager.GetUser(ctx, accountID, id)
+		return user, http.StatusOK, err
+	case http.MethodPost:
+		var req scim.User
+		if err := decode(data, &req); err != nil {
+			return nil, 0, err
+		}
+		user, err := h.manager.CreateUser(ctx, accountID, &req)
+		return user, http.StatusCreated, err
+	case http.MethodPut:
+		var req scim.User
+		if err := decode(data, &req); err != nil {
+			return nil, 0, err
+		}
+		user, err := h.manager.ReplaceUser(ctx, accountID, id, &req)
+		return user, http.StatusOK, err
+	case http.MethodPatch:
+		var req scim.PatchRequest
+		if err := decode(data, &req); err != nil {
+			return nil, 0, err
+		}
+		user, err := h.manager.PatchUser(ctx, accountID, id, req.Operations)
+		return user, http.StatusOK, err
+	case http.MethodDelete:
+		return nil, http.StatusNoContent, h.manager.DeleteUser(ctx, accountID, id)
+	default:
+		return nil, 0, scim.NewError(http.StatusMethodNotAllowed, "", "method %s is not supported", method)
+	}
+}
+
+func (h *handler) executeGroup(ctx context.Context, accountID, method, id string, data []byte) (any, int, error) {
+	switch method {
+	case http.MethodGet:
+		group, err := h.manager.GetGroup(ctx, accountID, id)
+		return group, http.StatusOK, err
+	case http.MethodPost:
+		var req scim.Group
+		if err := decode(data, &req); err != nil {
+			return nil, 0, err
+		}
+		group, err := h.manager.CreateGroup(ctx, accountID, &req)
+		return group, http.StatusCreated, err
+	case http.MethodPut:
+		var req scim.Group
+		if err := decode(data, &req); err != nil {
+			return nil, 0, err
+		}
+		group, err := h.manager.ReplaceGroup(ctx, accountID, id, &req)
+		return group, http.StatusOK, err
+	case http.MethodPatch:
+		var req scim.PatchRequest
+		if err := decode(data, &req); err != nil {
+			return nil, 0, err
+		}
+		group, err := h.manager.PatchGroup(ctx, accountID, id, req.Operations)
+		return group, http.StatusOK, err
+	case http.MethodDelete:
+		return nil, http.StatusNoContent, h.manager.DeleteGroup(ctx, accountID, id)
+	default:
+		return nil, 0, scim.NewError(http.StatusMethodNotAllowed, "", "method %s is not supported", method)
+	}
+}
+
+// bulk runs the operations of a bulk request in order. Operations can reference the resources created by
+// earlier operations with "bulkId:<id>", processing stops after failOnErrors failed operations.
+func (h *handler) bulk(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req scim.BulkRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBulkPayloadSize)).Decode(&req); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeSCIMError(ctx, w, scim.NewError(http.StatusRequestEntityTooLarge, "", "bulk request exceeds %d bytes", maxBulkPayloadSize))
+			return
+		}
+		writeSCIMError(ctx, w, scim.NewError(http.StatusBadRequest, scim.ErrTypeInvalidSyntax, "invalid bulk request: %v", err))
+		return
+	}
+
+	if len(req.Operations) > maxBulkOperations {
+		writeSCIMError(ctx, w, scim.NewError(http.StatusRequestEntityTooLarge, scim.ErrTypeTooMany, "bulk request exceeds %d operations", maxBulkOperations))
+		return
+	}
+
+	accountID := accountIDFromContext(ctx)
+	base := baseURL(r)
+	bulkIDs := make(map[string]string)
+
+	response := &scim.BulkResponse{
+		Schemas:    []string{scim.SchemaBulkResponse},
+		Operations: []scim.BulkOperationResponse{},
+	}
+
+	var failed int
+	for _, op := range req.Operations {
+		if req.FailOnErrors > 0 && failed >= req.FailOnErrors {
+			break
+		}
+
+		result := h.bulkOperation(ctx, base, accountID, op, bulkIDs)
+		if result.Response != nil {
+			failed++
+		}
+		response.Operations = append(response.Operations, result)
+	}
+
+	writeSCIMResponse(ctx, w, http.StatusOK, response)
+}
+
+func (h *handler) bulkOperation(ctx context.Context, base, accountID string, op scim.BulkOperation, bulkIDs map[string]string) scim.BulkOperationResponse {
+	result := scim.BulkOperationResponse{
+		Method: op.Method,
+		BulkID: op.BulkID,
+	}
+
+	fail := func(err error) scim.BulkOperationResponse {
+		scimErr := scim.ToError(err)
+		result.Status = scimErr.Status
+		result.Response = scimErr
+		return result
+	}
+
+	method := strings.ToUpper(op.Method)
+	if method == http.MethodPost && op.BulkID == "" {
+		return fail(scim.NewError(http.StatusBadRequest, scim.ErrTypeInvalidValue, "bulkId is required for POST"))
+	}
+
+	path := op.Path
+	data := []byte(op.Data)
+	for bulkID, id := range bulkIDs {
+		path = strings.ReplaceAll(path, "bulkId:"+bulkID, id)
+		data = []byte(strings.ReplaceAll(string(data), "bulkId:"+bulkID, id))
+	}
+	if strings.Contains(path, "bulkId:") || strings.Contains(string(data), "bulkId:") {
+		return fail(scim.NewError(http.StatusConflict, scim.ErrTypeInvalidValue, "unresolved bulkId reference"))
+	}
+
+	resourceType, id, _ := strings.Cut(strings.Trim(path, "/"), "/")
+	resource, code, err := h.execute(ctx, accountID, method, resourceType, id, data)
+	if err != nil {
+		return fail(err)
+	}
+
+	result.Status = strconv.Itoa(code)
+	if resource != nil {
+		result.Location = setLocation(base, resource, nil)
+		if method == http.MethodPost {
+			bulkIDs[op.BulkID] = resourceID(resource)
+		}
+	}
+
+	return result
+}
+
+func (h *handler) getServiceProviderConfig(w http.ResponseWriter, r *http.Request) {
+	writeSCIMResponse(r.Context(), w, http.StatusOK, map[string]any{
+		"schemas":        []string{scim.SchemaServiceProviderConfig},
+		"patch":          map[string]any{"supported": true},
+		"bulk":           map[string]any{"supported": true, "maxOperations": maxBulkOperations, "maxPayloadSize": maxBulkPayloadSize},
+		"filter":         map[string]any{"supported": true, "maxResults": maxCount},
+		"changePassword": map[string]any{"supported": false},
+		"sort":           map[string]any{"supported": false},
+		"etag":           map[string]any{"supported": false},
+		"authenticationSchemes": []map[string]any{{
+			"type":        "oauthbearertoken",
+			"name":        "OAuth Bearer Token",
+			"description": "Authentication with the SCIM token of the account",
+			"primary":     true,
+		}},
+	})
+}
+
+func (h *handler) getResourceTypes(w http.ResponseWriter, r *http.Request) {
+	base := baseURL(r)
+	resourceTypes := []any{
+		map[string]any{
+			"schemas":  []string{scim.SchemaResourceType},
+			"id":       scim.ResourceTypeUser,
+			"name":     scim.ResourceTypeUser,
+			"endpoint": "/" + resourceUsers,
+			"schema":   scim.SchemaUser,
+			"meta":     scim.Meta{ResourceType: "ResourceType", Location: base + "/ResourceTypes/" + scim.ResourceTypeUser},
+		},
+		map[string]any{
+			"schemas":  []string{scim.SchemaResourceType},
+			"id":       scim.ResourceTypeGroup,
+			"name":     scim.ResourceTypeGroup,
+			"endpoint": "/" + resourceGroups,
+			"schema":   scim.SchemaGroup,
+			"meta":     scim.Meta{ResourceType: "ResourceType", Location: base + "/ResourceTypes/" + scim.ResourceTypeGroup},
+		},
+	}
+
+	writeSCIMResponse(r.Context(), w, http.StatusOK, &scim.ListResponse{
+		Schemas:      []string{scim.SchemaListResponse},
+		TotalResults: len(resourceTypes),
+		StartIndex:   1,
+		ItemsPerPage: len(resourceTypes),
+		Resources:    resourceTypes,
+	})
+}
+
+func pagination(startIndexParam, countParam string) (int, int, error) {
+	startIndex, count := 1, defaultCount
+
+	if startIndexParam != "" {
+		v, err := strconv.Atoi(startIndexParam)
+		if err != nil {
+			return 0, 0, scim.NewError(http.StatusBadRequest, scim.ErrTypeInvalidValue, "invalid startIndex %s", startIndexParam)
+		}
+		startIndex = max(v, 1)
+	}
+
+	if countParam != "" {
+		v, err := strconv.Atoi(countParam)
+		if err != nil {
+			return 0, 0, scim.NewError(http.StatusBadRequest, scim.ErrTypeInvalidValue, "invalid count %s", countParam)
+		}
+		count = min(max(v, 0), maxCount)
+	}
+
+	return startIndex, count, nil
+}
+
+// excludedAttributes returns the lower cased attributes of the excludedAttributes parameter
+func excludedAttributes(param string) map[string]struct{} {
+	excluded := make(map[string]struct{})
+	for _, attr := range strings.Split(param, ",") {
+		if attr = strings.TrimSpace(attr); attr != "" {
+			excluded[scim.NormalizePath(attr)] = struct{}{}
+		}
+	}
+	return excluded
+}
+
+// setLocation sets the location of the resource and of its references and returns the location.
+// Members and groups are dropped when excluded, the other attributes are always returned.
+func setLocation(base string, resource any, excluded map[string]struct{}) string {
+	switch r := resource.(type) {
+	case *scim.User:
+		if _, ok := excluded["groups"]; ok {
+			r.Groups = nil
+		}
+		for i := range r.Groups {
+			r.Groups[i].Ref = base + "/" + resourceGroups + "/" + r.Groups[i].Value
+		}
+		r.Meta.Location = base + "/" + resourceUsers + "/" + r.ID
+		return r.Meta.Location
+	case *scim.Group:
+		if _, ok := excluded["members"]; ok {
+			r.Members = nil
+		}
+		for i := range r.Members {
+			r.Members[i].Ref = base + "/" + resourceUsers + "/" + r.Members[i].Value
+		}
+		r.Meta.Location = base + "/" + resourceGroups + "/" + r.ID
+		return r.Meta.Location
+	default:
+		return ""
+	}
+}
+
+func resourceID(resource any) string {
+	switch r := resource.(type) {
+	case *scim.User:
+		return r.ID
+	case *scim.Group:
+		return r.ID
+	default:
+		return ""
+	}
+}
+
+// baseURL returns the URL of the SCIM endpoint the request was sent to
+func baseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	return fmt.Sprintf("%s://%s%s", scheme, r.Host, scim.PathPrefix)
+}
+
+func decode(data []byte, v any) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return scim.NewError(http.StatusBadRequest, scim.ErrTypeInvalidSyntax, "invalid request body: %v", err)
+	}
+	return nil
+}
+
+func writeSCIMResponse(ctx context.Context, w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.WithContext(ctx).Errorf("failed to write SCIM response: %v", err)
+	}
+}
+
+func writeSCIMError(ctx context.Context, w http.ResponseWriter, err error) {
+	scimErr := scim.ToError(err)
+	if scimErr.Code() >= http.StatusInternalServerError {
+		log.WithContext(ctx).Errorf("SCIM request failed: %v", err)
+	} else {
+		log.WithContext(ctx).Debugf("SCIM request failed: %v", err)
+	}
+	writeSCIMResponse(ctx, w, scimErr.Code(), scimErr)
+}
@@ -0,0 +1,151 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/management/internals/modules/scim"
+	"github.com/netbirdio/netbird/management/server/activity"
+	"github.com/netbirdio/netbird/management/server/permissions/modules"
+	"github.com/netbirdio/netbird/management/server/store"
+)
+
+func setupProvisioningTest(t *testing.T) (*mux.Router, string, store.Store) {
+	t.Helper()
+
+	manager, testStore, mockAccountManager, mockPermissionsManager := setupTest(t)
+	mockAccountManager.StoreEventFunc = func(ctx context.Context, initiatorID, targetID, accountID string, activityID activity.ActivityDescriber, meta map[string]any) {
+	}
+	mockPermissionsManager.EXPECT().
+		ValidateUserPermissions(gomock.Any(), testAccountID, testAdminID, modules.Settings, gomock.Any()).
+		Return(true, nil).AnyTimes()
+
+	token, err := manager.CreateToken(context.Background(), testAccountID, testAdminID)
+	require.NoError(t, err)
+
+	router := mux.NewRouter()
+	RegisterProvisioningEndpoints(router.PathPrefix(scim.PathPrefix).Subrouter(), manager)
+
+	return router, token.PlainToken, testStore
+}
+
+func doSCIMRequest(t *testing.T, router *mux.Router, token, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(method, "https://netbird.example.com"+scim.PathPrefix+path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestProvisioning_Authentication(t *testing.T) {
+	router, _, _ := setupProvisioningTest(t)
+
+	rec := doSCIMRequest(t, router, "", http.MethodGet, "/Users", "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	other, err := scim.NewToken(testAccountID, testAdminID)
+	require.NoError(t, err)
+	rec = doSCIMRequest(t, router, other.PlainToken, http.MethodGet, "/Users", "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, "application/scim+json", rec.Header().Get("Content-Type"))
+
+	var scimErr scim.Error
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &scimErr))
+	assert.Equal(t, []string{scim.SchemaError}, scimErr.Schemas)
+	assert.Equal(t, "401", scimErr.Status)
+}
+
+func TestProvisioning_ListUsers(t *testing.T) {
+	router, token, _ := setupProvisioningTest(t)
+
+	rec := doSCIMRequest(t, router, token, http.MethodGet, `/Users?filter=userName+eq+"ALICE@example.com"`, "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var response struct {
+		TotalResults int         `json:"totalResults"`
+		ItemsPerPage int         `json:"itemsPerPage"`
+		Resources    []scim.User `json:"Resources"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.Equal(t, 1, response.TotalResults)
+	require.Len(t, response.Resources, 1)
+
+	user := response.Resources[0]
+	assert.Equal(t, testUserID, user.ID)
+	assert.Equal(t, "https://netbird.example.com/scim/v2/Users/"+testUserID, user.Meta.Location)
+	require.Len(t, user.Groups, 1)
+	assert.Equal(t, "https://netbird.example.com/scim/v2/Groups/"+testGroupID, user.Groups[0].Ref)
+
+	rec = doSCIMRequest(t, router, token, http.MethodGet, "/Users?startIndex=2&count=1", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, 2, response.TotalResults, "service users are not provisioned")
+	assert.Equal(t, 1, response.ItemsPerPage)
+
+	rec = doSCIMRequest(t, router, token, http.MethodGet, `/Users?filter=emails[type+eq+"work"]`, "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestProvisioning_Bulk(t *testing.T) {
+	router, token, testStore := setupProvisioningTest(t)
+
+	body := `{
+		"schemas": ["urn:ietf:params:scim:api:messages:2.0:BulkRequest"],
+		"failOnErrors": 1,
+		"Operations": [
+			{
+				"method": "POST",
+				"bulkId": "bob",
+				"path": "/Users",
+				"data": {"schemas": ["urn:ietf:params:scim:schemas:core:2.0:User"], "externalId": "bob-id", "userName": "bob@example.com"}
+			},
+			{
+				"method": "POST",
+				"bulkId": "ops",
+				"path": "/Groups",
+				"data": {"schemas": ["urn:ietf:params:scim:schemas:core:2.0:Group"], "displayName": "ops", "members": [{"value": "bulkId:bob"}]}
+			},
+			{
+				"method": "PATCH",
+				"path": "/Groups/bulkId:unknown",
+				"data": {"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"], "Operations": []}
+			},
+			{
+				"method": "DELETE",
+				"path": "/Users/bulkId:bob"
+			}
+		]
+	}`
+
+	rec := doSCIMRequest(t, router, token, http.MethodPost, "/Bulk", body)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var response scim.BulkResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.Len(t, response.Operations, 3, "processing stops after failOnErrors errors")
+	assert.Equal(t, "201", response.Operations[0].Status)
+	assert.Equal(t, "https://netbird.example.com/scim/v2/Users/bob-id", response.Operations[0].Location)
+	assert.Equal(t, "201", response.Operations[1].Status)
+	assert.Equal(t, "409", response.Operations[2].Status)
+
+	user, err := testStore.GetUserByUserID(context.Background(), store.LockingStrengthNone, "bob-id")
+	require.NoError(t, err)
+	require.Len(t, user.AutoGroups, 1)
+
+	group, err := testStore.GetGroupByID(context.Background(), store.LockingStrengthNone, testAccountID, user.AutoGroups[0])
+	require.NoError(t, err)
+	assert.Equal(t, "ops", group.Name)
+}
@@ -0,0 +1,160 @@
+package scim
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PathPrefix is the path the SCIM endpoint is served at
+const PathPrefix = "/scim/v2"
+
+// Schema URNs of RFC 7643 and RFC 7644
+const (
+	SchemaUser                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SchemaGroup                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SchemaListResponse          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SchemaPatchOp               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SchemaBulkRequest           = "urn:ietf:params:scim:api:messages:2.0:BulkRequest"
+	SchemaBulkResponse          = "urn:ietf:params:scim:api:messages:2.0:BulkResponse"
+	SchemaError                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+	SchemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	SchemaResourceType          = "urn:ietf:params:scim:schemas:core:2.0:ResourceType"
+)
+
+const (
+	ResourceTypeUser  = "User"
+	ResourceTypeGroup = "Group"
+)
+
+// User is the SCIM representation of a NetBird user.
+// The id is the NetBird user ID, which is the subject of the user's tokens at the IdP and has to be
+// provided as externalId on creation.
+type User struct {
+	Schemas     []string `json:"schemas"`
+	ID          string   `json:"id,omitempty"`
+	ExternalID  string   `json:"externalId,omitempty"`
+	UserName    string   `json:"userName"`
+	Name        *Name    `json:"name,omitempty"`
+	DisplayName string   `json:"displayName,omitempty"`
+	Emails      []Email  `json:"emails,omitempty"`
+	Active      *Bool    `json:"active,omitempty"`
+	Groups      []Member `json:"groups,omitempty"`
+	Meta        *Meta    `json:"meta,omitempty"`
+}
+
+// Name is the name of a user
+type Name struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+// Email is an email address of a user
+type Email struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary Bool   `json:"primary,omitempty"`
+}
+
+// Group is the SCIM representation of a NetBird group. Its members are the users that have
+// the group in their auto groups, which the peers of the users are added to.
+type Group struct {
+	Schemas     []string `json:"schemas"`
+	ID          string   `json:"id,omitempty"`
+	ExternalID  string   `json:"externalId,omitempty"`
+	DisplayName string   `json:"displayName"`
+	Members     []Member `json:"members,omitempty"`
+	Meta        *Meta    `json:"meta,omitempty"`
+}
+
+// Member references a user of a group or a group of a user
+type Member struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+}
+
+// Meta is the metadata of a resource
+type Meta struct {
+	ResourceType string     `json:"resourceType"`
+	Created      *time.Time `json:"created,omitempty"`
+	LastModified *time.Time `json:"lastModified,omitempty"`
+	Location     string     `json:"location,omitempty"`
+}
+
+// Bool is a boolean that is also decoded from the strings "True" and "False" sent by some clients
+type Bool bool
+
+func (b *Bool) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		v, err := strconv.ParseBool(strings.ToLower(s))
+		if err != nil {
+			return err
+		}
+		*b = Bool(v)
+		return nil
+	}
+
+	var v bool
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*b = Bool(v)
+	return nil
+}
+
+// ListResponse is the response of a query
+type ListResponse struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int      `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    []any    `json:"Resources"`
+}
+
+// PatchRequest modifies a resource
+type PatchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []PatchOperation `json:"Operations"`
+}
+
+// PatchOperation is an add, remove or replace of an attribute of a resource
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// BulkRequest groups operations on several resources in one request
+type BulkRequest struct {
+	Schemas      []string        `json:"schemas"`
+	FailOnErrors int             `json:"failOnErrors,omitempty"`
+	Operations   []BulkOperation `json:"Operations"`
+}
+
+// BulkOperation is a single operation of a bulk request. The path and data can reference resources
+// created by earlier operations with "bulkId:<id>".
+type BulkOperation struct {
+	Method string          `json:"method"`
+	BulkID string          `json:"bulkId,omitempty"`
+	Path   string          `json:"path"`
+	Data   json.RawMessage `json:"data,omitempty"`
+}
+
+// BulkResponse holds the results of the operations of a bulk request
+type BulkResponse struct {
+	Schemas    []string                `json:"schemas"`
+	Operations []BulkOperationResponse `json:"Operations"`
+}
+
+// BulkOperationResponse is the result of a single operation of a bulk request
+type BulkOperationResponse struct {
+	Method   string `json:"method"`
+	BulkID   string `json:"bulkId,omitempty"`
+	Location string `json:"location,omitempty"`
+	Status   string `json:"status"`
+	Response any    `json:"response,omitempty"`
+}
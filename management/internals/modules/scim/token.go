@@ -0,0 +1,110 @@
+package scim
+
+import (
+	"crypto/sha256"
+	b64 "encoding/base64"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"time"
+
+	b "github.com/hashicorp/go-secure-stdlib/base62"
+
+	"github.com/netbirdio/netbird/base62"
+	"github.com/netbirdio/netbird/shared/management/http/api"
+)
+
+const (
+	// TokenPrefix is the 4 char prefix of SCIM tokens, distinguishing them from personal access tokens
+	TokenPrefix = "nbs_"
+	// TokenSecretLength number of characters used for the secret inside the token
+	TokenSecretLength = 30
+	// TokenChecksumLength number of characters used for the encoded checksum of the secret inside the token
+	TokenChecksumLength = 6
+	// TokenLength total number of characters used for the token
+	TokenLength = 40
+)
+
+// Token authenticates the SCIM client of an account. An account has at most one token,
+// creating a new one replaces it.
+type Token struct {
+	AccountID   string `gorm:"primaryKey"`
+	HashedToken string `gorm:"uniqueIndex"`
+	CreatedBy   string
+	CreatedAt   time.Time
+	LastUsed    *time.Time
+}
+
+// TableName returns the table of the SCIM tokens, the default would be too generic
+func (Token) TableName() string {
+	return "scim_tokens"
+}
+
+// TokenGenerated holds a new token and the plain text version of it
+type TokenGenerated struct {
+	PlainToken string
+	Token
+}
+
+// NewToken generates a new token of the account. The plain text version is returned once,
+// only the hash of it is stored.
+func NewToken(accountID, createdBy string) (*TokenGenerated, error) {
+	secret, err := b.Random(TokenSecretLength)
+	if err != nil {
+		return nil, err
+	}
+
+	checksum := crc32.ChecksumIEEE([]byte(secret))
+	plainToken := TokenPrefix + secret + fmt.Sprintf("%06s", base62.Encode(checksum))
+
+	return &TokenGenerated{
+		PlainToken: plainToken,
+		Token: Token{
+			AccountID:   accountID,
+			HashedToken: hashToken(plainToken),
+			CreatedBy:   createdBy,
+			CreatedAt:   time.Now().UTC(),
+		},
+	}, nil
+}
+
+// HashToken validates the format of a plain text token and returns the hash it is stored with
+func HashToken(token string) (string, error) {
+	if len(token) != TokenLength {
+		return "", errors.New("SCIM token has incorrect length")
+	}
+	if token[:len(TokenPrefix)] != TokenPrefix {
+		return "", errors.New("SCIM token has wrong prefix")
+	}
+
+	secret := token[len(TokenPrefix) : len(TokenPrefix)+TokenSecretLength]
+	verificationChecksum, err := base62.Decode(token[len(TokenPrefix)+TokenSecretLength:])
+	if err != nil {
+		return "", fmt.Errorf("SCIM token checksum decoding failed: %w", err)
+	}
+	if crc32.ChecksumIEEE([]byte(secret)) != verificationChecksum {
+		return "", errors.New("SCIM token checksum does not match")
+	}
+
+	return hashToken(token), nil
+}
+
+func hashToken(token string) string {
+	hashed := sha256.Sum256([]byte(token))
+	return b64.StdEncoding.EncodeToString(hashed[:])
+}
+
+func (t *Token) ToAPIResponse() *api.ScimToken {
+	return &api.ScimToken{
+		CreatedAt: t.CreatedAt,
+		CreatedBy: t.CreatedBy,
+		LastUsed:  t.LastUsed,
+	}
+}
+
+func (t *TokenGenerated) ToAPIResponse() *api.ScimTokenGenerated {
+	return &api.ScimTokenGenerated{
+		PlainToken: t.PlainToken,
+		ScimToken:  *t.Token.ToAPIResponse(),
+	}
+}
@@ -0,0 +1,54 @@
+package scim
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewToken(t *testing.T) {
+	token, err := NewToken("account", "user")
+	require.NoError(t, err)
+
+	assert.Len(t, token.PlainToken, TokenLength)
+	assert.Equal(t, TokenPrefix, token.PlainToken[:len(TokenPrefix)])
+	assert.NotContains(t, token.HashedToken, token.PlainToken)
+
+	hashed, err := HashToken(token.PlainToken)
+	require.NoError(t, err)
+	assert.Equal(t, token.HashedToken, hashed)
+}
+
+func TestHashToken_Invalid(t *testing.T) {
+	token, err := NewToken("account", "user")
+	require.NoError(t, err)
+
+	for _, invalid := range []string{
+		"",
+		token.PlainToken[:TokenLength-1],
+		"nbp_" + token.PlainToken[len(TokenPrefix):],
+		// secret changed, the checksum doesn't match anymore
+		TokenPrefix + "x" + token.PlainToken[len(TokenPrefix)+1:],
+	} {
+		_, err := HashToken(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestBool_UnmarshalJSON(t *testing.T) {
+	for input, expected := range map[string]bool{
+		`true`:    true,
+		`false`:   false,
+		`"True"`:  true,
+		`"False"`: false,
+	} {
+		var b Bool
+		require.NoError(t, json.Unmarshal([]byte(input), &b), input)
+		assert.Equal(t, expected, bool(b), input)
+	}
+
+	var b Bool
+	assert.Error(t, json.Unmarshal([]byte(`"maybe"`), &b))
+}
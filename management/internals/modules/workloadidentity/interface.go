@@ -0,0 +1,22 @@
+package workloadidentity
+
+import (
+	"context"
+	"errors"
+
+	"github.com/netbirdio/netbird/management/server/types"
+)
+
+// ErrNotWorkloadToken is returned by Authenticate when the token is issued by the identity provider of the users or
+// is not accepted by any provider, the token is then left to the user login
+var ErrNotWorkloadToken = errors.New("not a workload identity token")
+
+type Manager interface {
+	GetAllProviders(ctx context.Context, accountID, userID string) ([]*Provider, error)
+	GetProvider(ctx context.Context, accountID, userID, providerID string) (*Provider, error)
+	CreateProvider(ctx context.Context, accountID, userID string, provider *Provider) (*Provider, error)
+	UpdateProvider(ctx context.Context, accountID, userID string, provider *Provider) (*Provider, error)
+	DeleteProvider(ctx context.Context, accountID, userID, providerID string) error
+	// Authenticate validates a workload identity token and returns the identity registering the peer
+	Authenticate(ctx context.Context, token string) (*types.WorkloadIdentity, error)
+}
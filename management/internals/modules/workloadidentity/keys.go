@@ -0,0 +1,29 @@
+package workloadidentity
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/netbirdio/netbird/management/server/util"
+)
+
+const keysRequestTimeout = 10 * time.Second
+
+// NewKeysHTTPClient returns the client fetching the keys of the providers. It refuses connections to internal
+// addresses when dialing, so host names resolving to them are rejected as well, and only follows https redirects.
+func NewKeysHTTPClient() *http.Client {
+	return &http.Client{
+		Transport: util.NewPublicTransport(keysRequestTimeout),
+		Timeout:   keysRequestTimeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return errors.New("too many redirects")
+			}
+			if req.URL.Scheme != "https" {
+				return errors.New("redirect to a location without https")
+			}
+			return nil
+		},
+	}
+}
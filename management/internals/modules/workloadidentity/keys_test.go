@@ -0,0 +1,21 @@
+package workloadidentity
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewKeysHTTPClient_RefusesInternalAddresses(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	_, err := NewKeysHTTPClient().Get(server.URL)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "internal address")
+}
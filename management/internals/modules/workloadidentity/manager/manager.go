@@ -0,0 +1,338 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/management/internals/modules/workloadidentity"
+	"github.com/netbirdio/netbird/management/server/account"
+	"github.com/netbirdio/netbird/management/server/activity"
+	"github.com/netbirdio/netbird/management/server/permissions"
+	"github.com/netbirdio/netbird/management/server/permissions/modules"
+	"github.com/netbirdio/netbird/management/server/permissions/operations"
+	"github.com/netbirdio/netbird/management/server/store"
+	"github.com/netbirdio/netbird/management/server/types"
+	nbjwt "github.com/netbirdio/netbird/shared/auth/jwt"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+type managerImpl struct {
+	store              store.Store
+	accountManager     account.Manager
+	permissionsManager permissions.Manager
+	// keysClient fetches the keys of the providers
+	keysClient *http.Client
+	// userIssuers are the issuers of the identity providers of the users, they can't be used by providers
+	userIssuers []string
+
+	validatorsMu sync.Mutex
+	// validators are shared by the providers with the same issuer, audiences and keys
+	validators map[string]*nbjwt.Validator
+}
+
+func NewManager(store store.Store, accountManager account.Manager, permissionsManager permissions.Manager, userIssuers []string) workloadidentity.Manager {
+	var issuers []string
+	for _, issuer := range userIssuers {
+		if issuer = normalizeIssuer(issuer); issuer != "" {
+			issuers = append(issuers, issuer)
+		}
+	}
+
+	return &managerImpl{
+		store:              store,
+		accountManager:     accountManager,
+		permissionsManager: permissionsManager,
+		keysClient:         workloadidentity.NewKeysHTTPClient(),
+		userIssuers:        issuers,
+		validators:         make(map[string]*nbjwt.Validator),
+	}
+}
+
+func (m *managerImpl) GetAllProviders(ctx context.Context, accountID, userID string) ([]*workloadidentity.Provider, error) {
+	if err := m.validatePermissions(ctx, accountID, userID, operations.Read); err != nil {
+		return nil, err
+	}
+
+	return m.store.GetAccountWorkloadIdentityProviders(ctx, store.LockingStrengthNone, accountID)
+}
+
+func (m *managerImpl) GetProvider(ctx context.Context, accountID, userID, providerID string) (*workloadidentity.Provider, error) {
+	if err := m.validatePermissions(ctx, accountID, userID, operations.Read); err != nil {
+		return nil, err
+	}
+
+	return m.store.GetWorkloadIdentityProviderByID(ctx, store.LockingStrengthNone, accountID, providerID)
+}
+
+func (m *managerImpl) CreateProvider(ctx context.Context, accountID, userID string, provider *workloadidentity.Provider) (*workloadidentity.Provider, error) {
+	if err := m.validatePermissions(ctx, accountID, userID, operations.Create); err != nil {
+		return nil, err
+	}
+
+	if err := m.validateIssuer(provider.Issuer); err != nil {
+		return nil, err
+	}
+
+	if err := validateAudiences(accountID, provider.Audiences); err != nil {
+		return nil, err
+	}
+
+	provider = workloadidentity.NewProvider(accountID, provider.Name, provider.Issuer, provider.Audiences, provider.KeysLocation,
+		provider.Rules, provider.Ephemeral, provider.Enabled)
+	err := m.store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+		if err := validateGroups(ctx, transaction, accountID, provider.GroupIDs()); err != nil {
+			return err
+		}
+
+		return transaction.SaveWorkloadIdentityProvider(ctx, provider)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	m.accountManager.StoreEvent(ctx, userID, provider.ID, accountID, activity.WorkloadIdentityProviderCreated, provider.EventMeta())
+
+	return provider, nil
+}
+
+func (m *managerImpl) UpdateProvider(ctx context.Context, accountID, userID string, updatedProvider *workloadidentity.Provider) (*workloadidentity.Provider, error) {
+	if err := m.validatePermissions(ctx, accountID, userID, operations.Update); err != nil {
+		return nil, err
+	}
+
+	if err := m.validateIssuer(updatedProvider.Issuer); err != nil {
+		return nil, err
+	}
+
+	if err := validateAudiences(accountID, updatedProvider.Audiences); err != nil {
+		return nil, err
+	}
+
+	var provider *workloadidentity.Provider
+	err := m.store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+		var err error
+		provider, err = transaction.GetWorkloadIdentityProviderByID(ctx, store.LockingStrengthUpdate, accountID, updatedProvider.ID)
+		if err != nil {
+			return err
+		}
+
+		if err = validateGroups(ctx, transaction, accountID, updatedProvider.GroupIDs()); err != nil {
+			return err
+		}
+
+		provider.Name = updatedProvider.Name
+		provider.Issuer = updatedProvider.Issuer
+		provider.Audiences = updatedProvider.Audiences
+		provider.KeysLocation = updatedProvider.KeysLocation
+		provider.Rules = updatedProvider.Rules
+		provider.Ephemeral = updatedProvider.Ephemeral
+		provider.Enabled = updatedProvider.Enabled
+
+		return transaction.SaveWorkloadIdentityProvider(ctx, provider)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	m.accountManager.StoreEvent(ctx, userID, provider.ID, accountID, activity.WorkloadIdentityProviderUpdated, provider.EventMeta())
+
+	return provider, nil
+}
+
+func (m *managerImpl) DeleteProvider(ctx context.Context, accountID, userID, providerID string) error {
+	if err := m.validatePermissions(ctx, accountID, userID, operations.Delete); err != nil {
+		return err
+	}
+
+	provider, err := m.store.GetWorkloadIdentityProviderByID(ctx, store.LockingStrengthNone, accountID, providerID)
+	if err != nil {
+		return err
+	}
+
+	if err = m.store.DeleteWorkloadIdentityProvider(ctx, accountID, providerID); err != nil {
+		return err
+	}
+
+	m.accountManager.StoreEvent(ctx, userID, providerID, accountID, activity.WorkloadIdentityProviderDeleted, provider.EventMeta())
+
+	return nil
+}
+
+// Authenticate validates the token with the providers configured for its issuer and audiences. The audiences are
+// bound to the account of the providers, and the token has to match the rules of exactly one provider,
+// so a token can't register peers in several accounts. Tokens of the identity provider of the users and tokens
+// no provider accepts are not workload tokens and are left to the user login.
+func (m *managerImpl) Authenticate(ctx context.Context, token string) (*types.WorkloadIdentity, error) {
+	unverified := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(token, unverified); err != nil {
+		return nil, workloadidentity.ErrNotWorkloadToken
+	}
+
+	issuer, err := unverified.GetIssuer()
+	if err != nil || issuer == "" || m.isUserIssuer(issuer) {
+		return nil, workloadidentity.ErrNotWorkloadToken
+	}
+
+	audiences, err := unverified.GetAudience()
+	if err != nil || len(audiences) == 0 {
+		return nil, workloadidentity.ErrNotWorkloadToken
+	}
+
+	providers, err := m.store.GetWorkloadIdentityProvidersByIssuer(ctx, store.LockingStrengthNone, issuer)
+	if err != nil {
+		return nil, err
+	}
+	providers = slices.DeleteFunc(providers, func(provider *workloadidentity.Provider) bool {
+		return !provider.Enabled || !provider.SharesAudience(audiences)
+	})
+	if len(providers) == 0 {
+		return nil, workloadidentity.ErrNotWorkloadToken
+	}
+
+	var identity *types.WorkloadIdentity
+	for _, provider := range providers {
+		claims, err := m.validateToken(ctx, provider, token)
+		if err != nil {
+			log.WithContext(ctx).Debugf("workload identity token is not valid for provider %s: %v", provider.ID, err)
+			continue
+		}
+
+		groupIDs := provider.MatchGroups(claims)
+		if len(groupIDs) == 0 {
+			log.WithContext(ctx).Debugf("workload identity token matches no rule of provider %s", provider.ID)
+			continue
+		}
+
+		if identity != nil {
+			return nil, status.Errorf(status.PermissionDenied, "workload identity token matches several providers")
+		}
+
+		subject, _ := claims.GetSubject()
+		identity = &types.WorkloadIdentity{
+			AccountID:    provider.AccountID,
+			ProviderID:   provider.ID,
+			ProviderName: provider.Name,
+			Subject:      subject,
+			AutoGroups:   groupIDs,
+			Ephemeral:    provider.Ephemeral,
+		}
+	}
+
+	if identity == nil {
+		return nil, workloadidentity.ErrNotWorkloadToken
+	}
+
+	// the groups could have been deleted after the provider was saved
+	groups, err := m.store.GetGroupsByIDs(ctx, store.LockingStrengthNone, identity.AccountID, identity.AutoGroups)
+	if err != nil {
+		return nil, err
+	}
+	existing := make([]string, 0, len(identity.AutoGroups))
+	for _, groupID := range identity.AutoGroups {
+		if _, ok := groups[groupID]; ok {
+			existing = append(existing, groupID)
+		}
+	}
+	identity.AutoGroups = existing
+
+	return identity, nil
+}
+
+func (m *managerImpl) validateToken(ctx context.Context, provider *workloadidentity.Provider, token string) (jwt.MapClaims, error) {
+	parsed, err := m.getValidator(provider).ValidateAndParse(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := parsed.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, errors.New("unexpected claims type")
+	}
+
+	// workload tokens are short-lived, tokens without an expiration are not accepted
+	if exp, err := claims.GetExpirationTime(); err != nil || exp == nil {
+		return nil, errors.New("token has no expiration time")
+	}
+
+	return claims, nil
+}
+
+func (m *managerImpl) getValidator(provider *workloadidentity.Provider) *nbjwt.Validator {
+	key := strings.Join([]string{provider.Issuer, provider.KeysLocation, strings.Join(provider.Audiences, ",")}, "|")
+
+	m.validatorsMu.Lock()
+	defer m.validatorsMu.Unlock()
+
+	validator, ok := m.validators[key]
+	if !ok {
+		validator = nbjwt.NewValidator(provider.Issuer, provider.Audiences, provider.KeysLocation, true, nbjwt.WithHTTPClient(m.keysClient))
+		m.validators[key] = validator
+	}
+	return validator
+}
+
+// validateIssuer prevents providers from accepting the tokens of the identity provider of the users, the users of
+// every account would otherwise register their peers through the provider
+func (m *managerImpl) validateIssuer(issuer string) error {
+	if m.isUserIssuer(issuer) {
+		return status.Errorf(status.InvalidArgument, "issuer is the identity provider of the users")
+	}
+	return nil
+}
+
+func (m *managerImpl) isUserIssuer(issuer string) bool {
+	return slices.Contains(m.userIssuers, normalizeIssuer(issuer))
+}
+
+func normalizeIssuer(issuer string) string {
+	return strings.ToLower(strings.TrimRight(strings.TrimSpace(issuer), "/"))
+}
+
+func (m *managerImpl) validatePermissions(ctx context.Context, accountID, userID string, operation operations.Operation) error {
+	allowed, err := m.permissionsManager.ValidateUserPermissions(ctx, accountID, userID, modules.SetupKeys, operation)
+	if err != nil {
+		return status.NewPermissionValidationError(err)
+	}
+	if !allowed {
+		return status.NewPermissionDeniedError()
+	}
+	return nil
+}
+
+// validateAudiences binds the providers to the account, the tokens have to be issued for the account. Any account
+// could otherwise configure the issuer and audience of a shared public issuer and register the peers of the
+// workloads of other accounts.
+func validateAudiences(accountID string, audiences []string) error {
+	for _, audience := range audiences {
+		if !workloadidentity.IsAccountAudience(accountID, audience) {
+			return status.Errorf(status.InvalidArgument, "audience %s has to be the account ID or end with /%s", audience, accountID)
+		}
+	}
+	return nil
+}
+
+func validateGroups(ctx context.Context, transaction store.Store, accountID string, groupIDs []string) error {
+	groups, err := transaction.GetGroupsByIDs(ctx, store.LockingStrengthNone, accountID, groupIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, groupID := range groupIDs {
+		group, ok := groups[groupID]
+		if !ok {
+			return status.Errorf(status.InvalidArgument, "group %s not found", groupID)
+		}
+		if group.IsGroupAll() {
+			return status.Errorf(status.InvalidArgument, "can't assign the All group")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,299 @@
+package manager
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/management/internals/modules/workloadidentity"
+	"github.com/netbirdio/netbird/management/server/activity"
+	"github.com/netbirdio/netbird/management/server/mock_server"
+	"github.com/netbirdio/netbird/management/server/permissions"
+	"github.com/netbirdio/netbird/management/server/store"
+	"github.com/netbirdio/netbird/management/server/types"
+	nbjwt "github.com/netbirdio/netbird/shared/auth/jwt"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+const (
+	testAccountID = "test-account-id"
+	testOtherID   = "test-other-account-id"
+	testUserID    = "test-user-id"
+	testGroupID   = "test-group-id"
+	testOtherGID  = "test-other-group-id"
+	testKeyID     = "test-key"
+	// testUserIssuer is the issuer of the identity provider of the users
+	testUserIssuer = "https://idp.example.com"
+)
+
+// testAudience is the audience of the tokens issued for the account
+func testAudience(accountID string) string {
+	return "https://netbird.example.com/" + accountID
+}
+
+// testIssuer is a local OIDC issuer serving its JSON Web Key Set
+type testIssuer struct {
+	url string
+	key *rsa.PrivateKey
+	// client trusts the certificate of the issuer, the keys client of the manager refuses local addresses
+	client *http.Client
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	jwks := nbjwt.Jwks{Keys: []nbjwt.JSONWebKey{{
+		Kty: "RSA",
+		Kid: testKeyID,
+		Use: "sig",
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}}
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=3600")
+		_ = json.NewEncoder(w).Encode(jwks)
+	}))
+	t.Cleanup(server.Close)
+
+	return &testIssuer{url: server.URL, key: key, client: server.Client()}
+}
+
+func (i *testIssuer) keysLocation() string {
+	return i.url + "/keys"
+}
+
+func (i *testIssuer) sign(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = testKeyID
+	signed, err := token.SignedString(i.key)
+	require.NoError(t, err)
+	return signed
+}
+
+func (i *testIssuer) claims(subject string) jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss": i.url,
+		"aud": testAudience(testAccountID),
+		"sub": subject,
+		"iat": time.Now().Unix(),
+		"exp": time.Now().Add(10 * time.Minute).Unix(),
+	}
+}
+
+func setupTest(t *testing.T) (*managerImpl, store.Store, *[]activity.Activity) {
+	t.Helper()
+
+	ctx := context.Background()
+	testStore, cleanup, err := store.NewTestStoreFromSQL(ctx, "", t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(cleanup)
+
+	for accountID, groupID := range map[string]string{testAccountID: testGroupID, testOtherID: testOtherGID} {
+		err = testStore.SaveAccount(ctx, &types.Account{
+			Id:     accountID,
+			Domain: accountID + ".example.com",
+			Users: map[string]*types.User{
+				accountID + testUserID: {Id: accountID + testUserID, AccountID: accountID, Role: types.UserRoleOwner},
+			},
+			Groups: map[string]*types.Group{
+				groupID: {ID: groupID, AccountID: accountID, Name: "ci", Issued: types.GroupIssuedAPI, Peers: []string{}},
+			},
+		})
+		require.NoError(t, err)
+	}
+
+	ctrl := gomock.NewController(t)
+	permissionsManager := permissions.NewMockManager(ctrl)
+	permissionsManager.EXPECT().ValidateUserPermissions(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(true, nil).AnyTimes()
+
+	var events []activity.Activity
+	accountManager := &mock_server.MockAccountManager{
+		StoreEventFunc: func(_ context.Context, _, _, _ string, activityID activity.ActivityDescriber, _ map[string]any) {
+			events = append(events, activityID.(activity.Activity))
+		},
+	}
+
+	return NewManager(testStore, accountManager, permissionsManager, []string{testUserIssuer}).(*managerImpl), testStore, &events
+}
+
+func TestManager_CreateProvider(t *testing.T) {
+	manager, _, events := setupTest(t)
+	issuer := newTestIssuer(t)
+	manager.keysClient = issuer.client
+	ctx := context.Background()
+
+	provider := workloadidentity.NewProvider("", "cluster", issuer.url, []string{testAudience(testAccountID)}, issuer.keysLocation(),
+		[]workloadidentity.Rule{{Claims: map[string]string{"sub": "*"}, AutoGroups: []string{testOtherGID}}}, true, true)
+	_, err := manager.CreateProvider(ctx, testAccountID, testUserID, provider)
+	sErr, ok := status.FromError(err)
+	require.True(t, ok, "groups of another account can't be assigned")
+	assert.Equal(t, status.InvalidArgument, sErr.Type())
+
+	provider.Rules[0].AutoGroups = []string{testGroupID}
+	provider.Audiences = []string{"netbird"}
+	_, err = manager.CreateProvider(ctx, testAccountID, testUserID, provider)
+	sErr, ok = status.FromError(err)
+	require.True(t, ok, "audiences have to be bound to the account")
+	assert.Equal(t, status.InvalidArgument, sErr.Type())
+
+	provider.Audiences = []string{testAudience(testAccountID)}
+	created, err := manager.CreateProvider(ctx, testAccountID, testUserID, provider)
+	require.NoError(t, err)
+	assert.Equal(t, testAccountID, created.AccountID)
+	assert.Equal(t, []activity.Activity{activity.WorkloadIdentityProviderCreated}, *events)
+
+	providers, err := manager.GetAllProviders(ctx, testAccountID, testUserID)
+	require.NoError(t, err)
+	require.Len(t, providers, 1)
+	assert.Equal(t, created.Rules, providers[0].Rules)
+
+	require.NoError(t, manager.DeleteProvider(ctx, testAccountID, testUserID, created.ID))
+	_, err = manager.GetProvider(ctx, testAccountID, testUserID, created.ID)
+	assert.Error(t, err)
+}
+
+func TestManager_Authenticate(t *testing.T) {
+	manager, testStore, _ := setupTest(t)
+	issuer := newTestIssuer(t)
+	manager.keysClient = issuer.client
+	ctx := context.Background()
+
+	provider := workloadidentity.NewProvider(testAccountID, "cluster", issuer.url, []string{testAudience(testAccountID)}, issuer.keysLocation(),
+		[]workloadidentity.Rule{{Claims: map[string]string{"sub": "system:serviceaccount:ci:*"}, AutoGroups: []string{testGroupID}}}, true, true)
+	require.NoError(t, testStore.SaveWorkloadIdentityProvider(ctx, provider))
+
+	identity, err := manager.Authenticate(ctx, issuer.sign(t, issuer.claims("system:serviceaccount:ci:runner")))
+	require.NoError(t, err)
+	assert.Equal(t, &types.WorkloadIdentity{
+		AccountID:    testAccountID,
+		ProviderID:   provider.ID,
+		ProviderName: "cluster",
+		Subject:      "system:serviceaccount:ci:runner",
+		AutoGroups:   []string{testGroupID},
+		Ephemeral:    true,
+	}, identity)
+
+	_, err = manager.Authenticate(ctx, "not-a-token")
+	assert.ErrorIs(t, err, workloadidentity.ErrNotWorkloadToken)
+
+	unknown := issuer.claims("system:serviceaccount:ci:runner")
+	unknown["iss"] = "https://unknown.example.com"
+	_, err = manager.Authenticate(ctx, issuer.sign(t, unknown))
+	assert.ErrorIs(t, err, workloadidentity.ErrNotWorkloadToken, "tokens of unknown issuers are left to the user login")
+
+	_, err = manager.Authenticate(ctx, issuer.sign(t, issuer.claims("system:serviceaccount:prod:web")))
+	assert.ErrorIs(t, err, workloadidentity.ErrNotWorkloadToken, "no rule matches the subject")
+
+	wrongAudience := issuer.claims("system:serviceaccount:ci:runner")
+	wrongAudience["aud"] = "another-service"
+	_, err = manager.Authenticate(ctx, issuer.sign(t, wrongAudience))
+	assert.Error(t, err, "token issued for another audience")
+
+	expired := issuer.claims("system:serviceaccount:ci:runner")
+	expired["exp"] = time.Now().Add(-time.Minute).Unix()
+	_, err = manager.Authenticate(ctx, issuer.sign(t, expired))
+	assert.Error(t, err, "expired token")
+
+	withoutExpiry := issuer.claims("system:serviceaccount:ci:runner")
+	delete(withoutExpiry, "exp")
+	_, err = manager.Authenticate(ctx, issuer.sign(t, withoutExpiry))
+	assert.Error(t, err, "token without expiration")
+
+	forger := newTestIssuer(t)
+	_, err = manager.Authenticate(ctx, forger.sign(t, issuer.claims("system:serviceaccount:ci:runner")))
+	assert.Error(t, err, "token signed with another key")
+
+	unbound := issuer.claims("system:serviceaccount:ci:runner")
+	unbound["aud"] = "netbird"
+	provider.Audiences = []string{"netbird"}
+	require.NoError(t, testStore.SaveWorkloadIdentityProvider(ctx, provider))
+	_, err = manager.Authenticate(ctx, issuer.sign(t, unbound))
+	assert.ErrorIs(t, err, workloadidentity.ErrNotWorkloadToken, "audiences not bound to the account are not accepted")
+
+	provider.Audiences = []string{testAudience(testAccountID)}
+	provider.Enabled = false
+	require.NoError(t, testStore.SaveWorkloadIdentityProvider(ctx, provider))
+	_, err = manager.Authenticate(ctx, issuer.sign(t, issuer.claims("system:serviceaccount:ci:runner")))
+	assert.ErrorIs(t, err, workloadidentity.ErrNotWorkloadToken, "disabled providers don't accept tokens")
+}
+
+func TestManager_Authenticate_SeveralAccounts(t *testing.T) {
+	manager, _, _ := setupTest(t)
+	issuer := newTestIssuer(t)
+	manager.keysClient = issuer.client
+	ctx := context.Background()
+
+	for accountID, groupID := range map[string]string{testAccountID: testGroupID, testOtherID: testOtherGID} {
+		provider := workloadidentity.NewProvider(accountID, "ci", issuer.url, []string{testAudience(accountID)}, issuer.keysLocation(),
+			[]workloadidentity.Rule{{Claims: map[string]string{"sub": "*"}, AutoGroups: []string{groupID}}}, false, true)
+		_, err := manager.CreateProvider(ctx, accountID, testUserID, provider)
+		require.NoError(t, err)
+	}
+
+	claims := issuer.claims("repo:org/app")
+	claims["aud"] = testAudience(testOtherID)
+	identity, err := manager.Authenticate(ctx, issuer.sign(t, claims))
+	require.NoError(t, err)
+	assert.Equal(t, testOtherID, identity.AccountID, "the audience selects the account")
+	assert.Equal(t, []string{testOtherGID}, identity.AutoGroups)
+
+	squatter := workloadidentity.NewProvider("", "squatter", issuer.url, []string{testAudience(testOtherID)}, issuer.keysLocation(),
+		[]workloadidentity.Rule{{Claims: map[string]string{"sub": "*"}, AutoGroups: []string{testGroupID}}}, false, true)
+	_, err = manager.CreateProvider(ctx, testAccountID, testUserID, squatter)
+	sErr, ok := status.FromError(err)
+	require.True(t, ok, "the audience of another account can't be configured")
+	assert.Equal(t, status.InvalidArgument, sErr.Type())
+
+	claims["aud"] = []string{testAudience(testAccountID), testAudience(testOtherID)}
+	_, err = manager.Authenticate(ctx, issuer.sign(t, claims))
+	assert.Error(t, err, "tokens matching providers of several accounts are rejected")
+}
+
+func TestManager_UserIssuer(t *testing.T) {
+	manager, testStore, _ := setupTest(t)
+	issuer := newTestIssuer(t)
+	manager.keysClient = issuer.client
+	ctx := context.Background()
+
+	provider := workloadidentity.NewProvider("", "sso", testUserIssuer+"/", []string{testAudience(testAccountID)}, issuer.keysLocation(),
+		[]workloadidentity.Rule{{Claims: map[string]string{"sub": "*"}, AutoGroups: []string{testGroupID}}}, false, true)
+	_, err := manager.CreateProvider(ctx, testAccountID, testUserID, provider)
+	sErr, ok := status.FromError(err)
+	require.True(t, ok, "the issuer of the users can't be configured")
+	assert.Equal(t, status.InvalidArgument, sErr.Type())
+
+	provider.Issuer = issuer.url
+	created, err := manager.CreateProvider(ctx, testAccountID, testUserID, provider)
+	require.NoError(t, err)
+	created.Issuer = testUserIssuer
+	_, err = manager.UpdateProvider(ctx, testAccountID, testUserID, created)
+	sErr, ok = status.FromError(err)
+	require.True(t, ok, "providers can't be updated to the issuer of the users")
+	assert.Equal(t, status.InvalidArgument, sErr.Type())
+
+	// a provider stored before the issuer became the issuer of the users
+	require.NoError(t, testStore.SaveWorkloadIdentityProvider(ctx, created))
+	userToken := issuer.claims("user")
+	userToken["iss"] = testUserIssuer
+	_, err = manager.Authenticate(ctx, issuer.sign(t, userToken))
+	assert.ErrorIs(t, err, workloadidentity.ErrNotWorkloadToken, "user tokens are left to the user login")
+}
@@ -0,0 +1,213 @@
+package workloadidentity
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/xid"
+
+	"github.com/netbirdio/netbird/management/server/util"
+	"github.com/netbirdio/netbird/shared/management/http/api"
+)
+
+// Provider is an issuer of signed workload identity tokens, e.g. a Kubernetes cluster or a CI system.
+// Workloads presenting a token of the provider register peers without a setup key.
+type Provider struct {
+	ID           string `gorm:"primaryKey"`
+	AccountID    string `gorm:"index"`
+	Name         string
+	Issuer       string   `gorm:"index"`
+	Audiences    []string `gorm:"serializer:json"`
+	KeysLocation string
+	Rules        []Rule `gorm:"serializer:json"`
+	Ephemeral    bool
+	Enabled      bool
+}
+
+// Rule maps the claims of a token to the groups of the registered peer
+type Rule struct {
+	// Claims are the values the token claims have to match, a * matches any sequence of characters
+	Claims map[string]string
+	// AutoGroups are the groups the peers matching the rule are added to
+	AutoGroups []string
+}
+
+func NewProvider(accountID, name, issuer string, audiences []string, keysLocation string, rules []Rule, ephemeral, enabled bool) *Provider {
+	return &Provider{
+		ID:           xid.New().String(),
+		AccountID:    accountID,
+		Name:         name,
+		Issuer:       issuer,
+		Audiences:    audiences,
+		KeysLocation: keysLocation,
+		Rules:        rules,
+		Ephemeral:    ephemeral,
+		Enabled:      enabled,
+	}
+}
+
+func (p *Provider) ToAPIResponse() *api.WorkloadIdentityProvider {
+	rules := make([]api.WorkloadIdentityRule, 0, len(p.Rules))
+	for _, rule := range p.Rules {
+		rules = append(rules, api.WorkloadIdentityRule{
+			Claims:     rule.Claims,
+			AutoGroups: rule.AutoGroups,
+		})
+	}
+
+	return &api.WorkloadIdentityProvider{
+		Id:           p.ID,
+		Name:         p.Name,
+		Issuer:       p.Issuer,
+		Audiences:    p.Audiences,
+		KeysLocation: p.KeysLocation,
+		Rules:        rules,
+		Ephemeral:    p.Ephemeral,
+		Enabled:      p.Enabled,
+	}
+}
+
+func (p *Provider) FromAPIRequest(req *api.WorkloadIdentityProviderRequest) {
+	p.Name = req.Name
+	p.Issuer = req.Issuer
+	p.Audiences = req.Audiences
+	p.KeysLocation = req.KeysLocation
+	p.Ephemeral = req.Ephemeral
+	p.Enabled = req.Enabled
+
+	p.Rules = make([]Rule, 0, len(req.Rules))
+	for _, rule := range req.Rules {
+		p.Rules = append(p.Rules, Rule{
+			Claims:     rule.Claims,
+			AutoGroups: rule.AutoGroups,
+		})
+	}
+}
+
+func (p *Provider) Validate() error {
+	if p.Name == "" {
+		return errors.New("provider name is required")
+	}
+	if p.Issuer == "" {
+		return errors.New("issuer is required")
+	}
+	if len(p.Audiences) == 0 || slices.Contains(p.Audiences, "") {
+		return errors.New("at least one non empty audience is required")
+	}
+
+	keysURL, err := url.ParseRequestURI(p.KeysLocation)
+	if err != nil || keysURL.Scheme != "https" || keysURL.Hostname() == "" {
+		return errors.New("keys location has to be an https URL")
+	}
+	if util.IsInternalHost(keysURL.Hostname()) {
+		return errors.New("keys location can't be an internal address")
+	}
+
+	if len(p.Rules) == 0 {
+		return errors.New("at least one rule is required")
+	}
+	for i, rule := range p.Rules {
+		if len(rule.Claims) == 0 {
+			return fmt.Errorf("rule %d has to match at least one claim", i)
+		}
+		if len(rule.AutoGroups) == 0 {
+			return fmt.Errorf("rule %d has to assign at least one group", i)
+		}
+	}
+
+	return nil
+}
+
+// SharesAudience returns true if the provider accepts tokens for any of the audiences, only the audiences bound to
+// the account of the provider are accepted
+func (p *Provider) SharesAudience(audiences []string) bool {
+	return slices.ContainsFunc(p.Audiences, func(audience string) bool {
+		return IsAccountAudience(p.AccountID, audience) && slices.Contains(audiences, audience)
+	})
+}
+
+// IsAccountAudience returns true if the audience is bound to the account, it is the account ID or ends with
+// /<account ID>. Account IDs don't contain a /, so the audiences of different accounts never overlap.
+func IsAccountAudience(accountID, audience string) bool {
+	return accountID != "" && (audience == accountID || strings.HasSuffix(audience, "/"+accountID))
+}
+
+// GroupIDs returns the groups assigned by the rules of the provider
+func (p *Provider) GroupIDs() []string {
+	var groupIDs []string
+	for _, rule := range p.Rules {
+		for _, groupID := range rule.AutoGroups {
+			if !slices.Contains(groupIDs, groupID) {
+				groupIDs = append(groupIDs, groupID)
+			}
+		}
+	}
+	return groupIDs
+}
+
+// MatchGroups returns the groups of all rules matching the claims, it is empty if no rule matches
+func (p *Provider) MatchGroups(claims jwt.MapClaims) []string {
+	var groupIDs []string
+	for _, rule := range p.Rules {
+		if !rule.Matches(claims) {
+			continue
+		}
+		for _, groupID := range rule.AutoGroups {
+			if !slices.Contains(groupIDs, groupID) {
+				groupIDs = append(groupIDs, groupID)
+			}
+		}
+	}
+	return groupIDs
+}
+
+func (p *Provider) EventMeta() map[string]any {
+	return map[string]any{"name": p.Name, "issuer": p.Issuer}
+}
+
+// Matches returns true if the claims contain all the claims of the rule
+func (r Rule) Matches(claims jwt.MapClaims) bool {
+	for name, pattern := range r.Claims {
+		if !claimMatches(claims[name], pattern) {
+			return false
+		}
+	}
+	return true
+}
+
+func claimMatches(value any, pattern string) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case string:
+		return globMatch(pattern, v)
+	case []any:
+		return slices.ContainsFunc(v, func(item any) bool {
+			return claimMatches(item, pattern)
+		})
+	case []string:
+		return slices.ContainsFunc(v, func(item string) bool {
+			return globMatch(pattern, item)
+		})
+	default:
+		return globMatch(pattern, fmt.Sprint(v))
+	}
+}
+
+// globMatch matches the value against a pattern where * matches any sequence of characters
+func globMatch(pattern, value string) bool {
+	if !strings.Contains(pattern, "*") {
+		return pattern == value
+	}
+
+	parts := strings.Split(pattern, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	return regexp.MustCompile("^" + strings.Join(parts, ".*") + "$").MatchString(value)
+}
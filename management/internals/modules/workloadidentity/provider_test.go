@@ -0,0 +1,90 @@
+package workloadidentity
+
+import (
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRule_Matches(t *testing.T) {
+	claims := jwt.MapClaims{
+		"sub":              "repo:netbirdio/netbird:ref:refs/heads/main",
+		"repository_owner": "netbirdio",
+		"run_attempt":      float64(2),
+		"groups":           []any{"ci", "deploy"},
+	}
+
+	tests := []struct {
+		name    string
+		claims  map[string]string
+		matches bool
+	}{
+		{name: "exact", claims: map[string]string{"repository_owner": "netbirdio"}, matches: true},
+		{name: "wildcard across separators", claims: map[string]string{"sub": "repo:netbirdio/*:ref:refs/heads/*"}, matches: true},
+		{name: "all claims have to match", claims: map[string]string{"repository_owner": "netbirdio", "sub": "repo:other/*"}, matches: false},
+		{name: "array claim", claims: map[string]string{"groups": "deploy"}, matches: true},
+		{name: "number claim", claims: map[string]string{"run_attempt": "2"}, matches: true},
+		{name: "missing claim", claims: map[string]string{"environment": "*"}, matches: false},
+		{name: "regex characters are literal", claims: map[string]string{"repository_owner": "netbird.o"}, matches: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.matches, Rule{Claims: tc.claims}.Matches(claims))
+		})
+	}
+}
+
+func TestProvider_MatchGroups(t *testing.T) {
+	provider := &Provider{
+		Rules: []Rule{
+			{Claims: map[string]string{"sub": "system:serviceaccount:ci:*"}, AutoGroups: []string{"ci"}},
+			{Claims: map[string]string{"sub": "system:serviceaccount:*:runner"}, AutoGroups: []string{"runners", "ci"}},
+			{Claims: map[string]string{"sub": "system:serviceaccount:prod:*"}, AutoGroups: []string{"prod"}},
+		},
+	}
+
+	assert.Equal(t, []string{"ci", "runners"}, provider.MatchGroups(jwt.MapClaims{"sub": "system:serviceaccount:ci:runner"}))
+	assert.Empty(t, provider.MatchGroups(jwt.MapClaims{"sub": "system:serviceaccount:dev:web"}))
+}
+
+func TestIsAccountAudience(t *testing.T) {
+	assert.True(t, IsAccountAudience("account", "account"))
+	assert.True(t, IsAccountAudience("account", "https://netbird.example.com/account"))
+	assert.False(t, IsAccountAudience("account", "netbird"))
+	assert.False(t, IsAccountAudience("account", "https://netbird.example.com/other-account"))
+	assert.False(t, IsAccountAudience("account", "account/other"))
+	assert.False(t, IsAccountAudience("", "netbird/"), "providers without an account accept no audience")
+}
+
+func TestProvider_Validate(t *testing.T) {
+	valid := func() *Provider {
+		return NewProvider("account", "cluster", "https://oidc.example.com", []string{"netbird"},
+			"https://oidc.example.com/keys", []Rule{{Claims: map[string]string{"sub": "*"}, AutoGroups: []string{"group"}}}, true, true)
+	}
+
+	assert.NoError(t, valid().Validate())
+
+	p := valid()
+	p.Audiences = nil
+	assert.Error(t, p.Validate(), "audience is required")
+
+	p = valid()
+	p.KeysLocation = "/etc/jwks.json"
+	assert.Error(t, p.Validate(), "keys location has to be a URL")
+
+	p = valid()
+	p.KeysLocation = "http://oidc.example.com/keys"
+	assert.Error(t, p.Validate(), "keys location has to use https")
+
+	for _, location := range []string{"https://127.0.0.1/keys", "https://[::1]/keys", "https://169.254.169.254/keys", "https://10.0.0.1/keys", "https://localhost/keys"} {
+		p = valid()
+		p.KeysLocation = location
+		assert.Error(t, p.Validate(), "keys location %s is internal", location)
+	}
+
+	p = valid()
+	p.Rules = []Rule{{AutoGroups: []string{"group"}}}
+	assert.Error(t, p.Validate(), "rule without claims would match every token")
+}
@@ -23,8 +23,10 @@ import (
 	"github.com/netbirdio/netbird/formatter/hook"
 	nbgrpc "github.com/netbirdio/netbird/management/internals/shared/grpc"
 	"github.com/netbirdio/netbird/management/server/activity"
+	"github.com/netbirdio/netbird/management/server/activity/sink"
 	nbContext "github.com/netbirdio/netbird/management/server/context"
 	nbhttp "github.com/netbirdio/netbird/management/server/http"
+	"github.com/netbirdio/netbird/management/server/http/middleware"
 	"github.com/netbirdio/netbird/management/server/store"
 	"github.com/netbirdio/netbird/management/server/telemetry"
 	mgmtProto "github.com/netbirdio/netbird/shared/management/proto"
@@ -86,17 +88,27 @@ func (s *BaseServer) EventStore() activity.Store {
 			log.Fatalf("failed to initialize event store: %v", err)
 		}
 
+		if len(s.Config.EventSinks) > 0 {
+			eventStore, err = sink.NewStore(eventStore, s.Config.EventSinks)
+			if err != nil {
+				log.Fatalf("failed to initialize event sinks: %v", err)
+			}
+		}
+
 		return eventStore
 	})
 }
 
 func (s *BaseServer) APIHandler() http.Handler {
 	return Create(s, func() http.Handler {
-		httpAPIHandler, err := nbhttp.NewAPIHandler(context.Background(), s.AccountManager(), s.NetworksManager(), s.ResourcesManager(), s.RoutesManager(), s.GroupsManager(), s.GeoLocationManager(), s.AuthManager(), s.Metrics(), s.IntegratedValidator(), s.ProxyController(), s.PermissionsManager(), s.PeersManager(), s.SettingsManager(), s.ZonesManager(), s.RecordsManager(), s.NetworkMapController(), s.IdpManager())
+		httpAPIHandler, err := nbhttp.NewAPIHandler(context.Background(), s.AccountManager(), s.NetworksManager(), s.ResourcesManager(), s.RoutesManager(), s.GroupsManager(), s.GeoLocationManager(), s.AuthManager(), s.Metrics(), s.IntegratedValidator(), s.ProxyController(), s.PermissionsManager(), s.PeersManager(), s.SettingsManager(), s.ZonesManager(), s.RecordsManager(), s.CustomRolesManager(), s.AccessRequestsManager(), s.AccountConfigManager(), s.SCIMManager(), s.WorkloadIdentityManager(), s.PeerApprovalManager(), s.NetworkMapController(), s.IdpManager())
 		if err != nil {
 			log.Fatalf("failed to create API handler: %v", err)
 		}
-		return httpAPIHandler
+		// unlike for gRPC, the forwarding headers are only trusted from explicitly configured peers, as they
+		// decide about the source ranges of personal access tokens
+		realIPMiddleware := middleware.NewRealIPMiddleware(s.Config.ReverseProxy.TrustedPeers, s.Config.ReverseProxy.TrustedHTTPProxies, s.Config.ReverseProxy.TrustedHTTPProxiesCount)
+		return realIPMiddleware.Handler(httpAPIHandler)
 	})
 }
 
@@ -144,7 +156,7 @@ func (s *BaseServer) GRPCServer() *grpc.Server {
 		}
 
 		gRPCAPIHandler := grpc.NewServer(gRPCOpts...)
-		srv, err := nbgrpc.NewServer(s.Config, s.AccountManager(), s.SettingsManager(), s.SecretsManager(), s.Metrics(), s.AuthManager(), s.IntegratedValidator(), s.NetworkMapController(), s.OAuthConfigProvider())
+		srv, err := nbgrpc.NewServer(s.Config, s.AccountManager(), s.SettingsManager(), s.SecretsManager(), s.Metrics(), s.AuthManager(), s.IntegratedValidator(), s.NetworkMapController(), s.OAuthConfigProvider(), s.WorkloadIdentityManager())
 		if err != nil {
 			log.Fatalf("failed to create management server: %v", err)
 		}
@@ -3,6 +3,7 @@ package config
 import (
 	"net/netip"
 
+	"github.com/netbirdio/netbird/management/server/activity/sink"
 	"github.com/netbirdio/netbird/management/server/idp"
 	"github.com/netbirdio/netbird/management/server/types"
 	"github.com/netbirdio/netbird/shared/management/client/common"
@@ -39,6 +40,7 @@ type Config struct {
 	TURNConfig *TURNConfig
 	Relay      *Relay
 	Signal     *Host
+	Flow       *Flow
 
 	Datadir                string
 	DataStoreEncryptionKey string
@@ -61,6 +63,9 @@ type Config struct {
 	// EmbeddedIdP contains configuration for the embedded Dex OIDC provider.
 	// When set, Dex will be embedded in the management server and serve requests at /oauth2/
 	EmbeddedIdP *idp.EmbeddedIdPConfig
+
+	// EventSinks stream the activity events to webhooks, syslog servers or files as they are stored
+	EventSinks []*sink.Config
 }
 
 // GetAuthAudiences returns the audience from the http config and device authorization flow config
@@ -91,6 +96,23 @@ type Relay struct {
 	Addresses      []string
 	CredentialsTTL util.Duration
 	Secret         string
+	// AccountTokens adds the account ID of the peer to the relay tokens, so that the relay servers can apply the
+	// per-account limits. Relay servers without account support reject these tokens, enable it only after all
+	// relay servers have been upgraded.
+	AccountTokens bool
+}
+
+// Flow configuration type, the peers send their network flow events to the flow collector at URL
+type Flow struct {
+	URL string
+	// Secret signs the tokens of the peers, the flow collector validates them with the same secret
+	Secret         string
+	CredentialsTTL util.Duration
+	// Interval is the interval the peers send their events and expect acknowledgements in
+	Interval           util.Duration
+	Counters           bool
+	ExitNodeCollection bool
+	DNSCollection      bool
 }
 
 // HttpServerConfig is a config of the HTTP Management service server
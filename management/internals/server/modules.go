@@ -7,7 +7,19 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/netbirdio/management-integrations/integrations"
+	"github.com/netbirdio/netbird/management/internals/modules/accessrequests"
+	accessRequestsManager "github.com/netbirdio/netbird/management/internals/modules/accessrequests/manager"
+	"github.com/netbirdio/netbird/management/internals/modules/accountconfig"
+	accountConfigManager "github.com/netbirdio/netbird/management/internals/modules/accountconfig/manager"
+	"github.com/netbirdio/netbird/management/internals/modules/customroles"
+	customRolesManager "github.com/netbirdio/netbird/management/internals/modules/customroles/manager"
+	"github.com/netbirdio/netbird/management/internals/modules/peerapproval"
+	peerApprovalManager "github.com/netbirdio/netbird/management/internals/modules/peerapproval/manager"
 	"github.com/netbirdio/netbird/management/internals/modules/peers"
+	"github.com/netbirdio/netbird/management/internals/modules/scim"
+	scimManager "github.com/netbirdio/netbird/management/internals/modules/scim/manager"
+	"github.com/netbirdio/netbird/management/internals/modules/workloadidentity"
+	workloadIdentityManager "github.com/netbirdio/netbird/management/internals/modules/workloadidentity/manager"
 	"github.com/netbirdio/netbird/management/internals/modules/zones"
 	zonesManager "github.com/netbirdio/netbird/management/internals/modules/zones/manager"
 	"github.com/netbirdio/netbird/management/internals/modules/zones/records"
@@ -174,3 +186,43 @@ func (s *BaseServer) RecordsManager() records.Manager {
 		return recordsManager.NewManager(s.Store(), s.AccountManager(), s.PermissionsManager())
 	})
 }
+
+func (s *BaseServer) CustomRolesManager() customroles.Manager {
+	return Create(s, func() customroles.Manager {
+		return customRolesManager.NewManager(s.Store(), s.AccountManager(), s.PermissionsManager())
+	})
+}
+
+func (s *BaseServer) AccessRequestsManager() accessrequests.Manager {
+	return Create(s, func() accessrequests.Manager {
+		return accessRequestsManager.NewManager(s.Store(), s.AccountManager(), s.PermissionsManager())
+	})
+}
+
+func (s *BaseServer) AccountConfigManager() accountconfig.Manager {
+	return Create(s, func() accountconfig.Manager {
+		return accountConfigManager.NewManager(s.AccountManager(), s.NetworksManager(), s.ResourcesManager(), s.RoutesManager(), s.ZonesManager(), s.RecordsManager())
+	})
+}
+
+func (s *BaseServer) SCIMManager() scim.Manager {
+	return Create(s, func() scim.Manager {
+		return scimManager.NewManager(s.Store(), s.AccountManager(), s.PermissionsManager())
+	})
+}
+
+func (s *BaseServer) WorkloadIdentityManager() workloadidentity.Manager {
+	return Create(s, func() workloadidentity.Manager {
+		userIssuers := []string{s.Config.HttpConfig.AuthIssuer}
+		if s.Config.EmbeddedIdP != nil && s.Config.EmbeddedIdP.Enabled {
+			userIssuers = append(userIssuers, s.Config.EmbeddedIdP.Issuer)
+		}
+		return workloadIdentityManager.NewManager(s.Store(), s.AccountManager(), s.PermissionsManager(), userIssuers)
+	})
+}
+
+func (s *BaseServer) PeerApprovalManager() peerapproval.Manager {
+	return Create(s, func() peerapproval.Manager {
+		return peerApprovalManager.NewManager(s.Store(), s.AccountManager(), s.PermissionsManager())
+	})
+}
@@ -100,6 +100,7 @@ func (s *BaseServer) Start(ctx context.Context) error {
 		return fmt.Errorf("failed to expose metrics: %v", err)
 	}
 	s.EphemeralManager().LoadInitialPeers(srvCtx)
+	s.AccessRequestsManager().LoadActiveGrants(srvCtx)
 
 	var tlsConfig *tls.Config
 	tlsEnabled := false
@@ -207,6 +208,8 @@ func (s *BaseServer) Stop() error {
 		_ = s.GeoLocationManager().Stop()
 	}
 	s.EphemeralManager().Stop()
+	s.AccessRequestsManager().Stop()
+	s.AccountManager().Stop()
 	_ = s.Metrics().Close()
 	if s.listener != nil {
 		_ = s.listener.Close()
@@ -7,6 +7,8 @@ import (
 	"strings"
 
 	log "github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	integrationsConfig "github.com/netbirdio/management-integrations/integrations/config"
 	"github.com/netbirdio/netbird/client/ssh/auth"
@@ -22,7 +24,7 @@ import (
 	"github.com/netbirdio/netbird/shared/sshauth"
 )
 
-func toNetbirdConfig(config *nbconfig.Config, turnCredentials *Token, relayToken *Token, extraSettings *types.ExtraSettings) *proto.NetbirdConfig {
+func toNetbirdConfig(config *nbconfig.Config, turnCredentials *Token, relayToken *Token, flowToken *Token, extraSettings *types.ExtraSettings) *proto.NetbirdConfig {
 	if config == nil {
 		return nil
 	}
@@ -83,11 +85,35 @@ func toNetbirdConfig(config *nbconfig.Config, turnCredentials *Token, relayToken
 		Turns:  turns,
 		Signal: signalCfg,
 		Relay:  relayCfg,
+		Flow:   toFlowConfig(config.Flow, flowToken),
 	}
 
 	return nbConfig
 }
 
+// toFlowConfig returns the flow configuration of a peer, it is nil without a flow collector or a token of the peer
+func toFlowConfig(config *nbconfig.Flow, flowToken *Token) *proto.FlowConfig {
+	if config == nil || config.URL == "" || flowToken == nil {
+		return nil
+	}
+
+	interval := config.Interval.Duration
+	if interval <= 0 {
+		interval = defaultFlowInterval
+	}
+
+	return &proto.FlowConfig{
+		Url:                config.URL,
+		TokenPayload:       flowToken.Payload,
+		TokenSignature:     flowToken.Signature,
+		Interval:           durationpb.New(interval),
+		Enabled:            true,
+		Counters:           config.Counters,
+		ExitNodeCollection: config.ExitNodeCollection,
+		DnsCollection:      config.DNSCollection,
+	}
+}
+
 func toPeerConfig(peer *nbpeer.Peer, network *types.Network, dnsName string, settings *types.Settings, httpConfig *nbconfig.HttpServerConfig, deviceFlowConfig *nbconfig.DeviceAuthorizationFlow, enableSSH bool) *proto.PeerConfig {
 	netmask, _ := network.Net.Mask.Size()
 	fqdn := peer.FQDN(dnsName)
@@ -100,7 +126,7 @@ func toPeerConfig(peer *nbpeer.Peer, network *types.Network, dnsName string, set
 		sshConfig.JwtConfig = buildJWTConfig(httpConfig, deviceFlowConfig)
 	}
 
-	return &proto.PeerConfig{
+	peerConfig := &proto.PeerConfig{
 		Address:                         fmt.Sprintf("%s/%d", peer.IP.String(), netmask),
 		SshConfig:                       sshConfig,
 		Fqdn:                            fqdn,
@@ -110,9 +136,15 @@ func toPeerConfig(peer *nbpeer.Peer, network *types.Network, dnsName string, set
 			Version: settings.AutoUpdateVersion,
 		},
 	}
+
+	if settings.NetworkRangeV6.IsValid() && peer.SupportsIPv6() {
+		peerConfig.AddressV6 = fmt.Sprintf("%s/%d", peer.IPv6.String(), settings.NetworkRangeV6.Bits())
+	}
+
+	return peerConfig
 }
 
-func ToSyncResponse(ctx context.Context, config *nbconfig.Config, httpConfig *nbconfig.HttpServerConfig, deviceFlowConfig *nbconfig.DeviceAuthorizationFlow, peer *nbpeer.Peer, turnCredentials *Token, relayCredentials *Token, networkMap *types.NetworkMap, dnsName string, checks []*posture.Checks, dnsCache *cache.DNSConfigCache, settings *types.Settings, extraSettings *types.ExtraSettings, peerGroups []string, dnsFwdPort int64) *proto.SyncResponse {
+func ToSyncResponse(ctx context.Context, config *nbconfig.Config, httpConfig *nbconfig.HttpServerConfig, deviceFlowConfig *nbconfig.DeviceAuthorizationFlow, peer *nbpeer.Peer, turnCredentials *Token, relayCredentials *Token, flowCredentials *Token, networkMap *types.NetworkMap, dnsName string, checks []*posture.Checks, dnsCache *cache.DNSConfigCache, settings *types.Settings, extraSettings *types.ExtraSettings, peerGroups []string, dnsFwdPort int64) *proto.SyncResponse {
 	response := &proto.SyncResponse{
 		PeerConfig: toPeerConfig(peer, networkMap.Network, dnsName, settings, httpConfig, deviceFlowConfig, networkMap.EnableSSH),
 		NetworkMap: &proto.NetworkMap{
@@ -121,23 +153,24 @@ func ToSyncResponse(ctx context.Context, config *nbconfig.Config, httpConfig *nb
 			DNSConfig:  toProtocolDNSConfig(networkMap.DNSConfig, dnsCache, dnsFwdPort),
 			PeerConfig: toPeerConfig(peer, networkMap.Network, dnsName, settings, httpConfig, deviceFlowConfig, networkMap.EnableSSH),
 		},
-		Checks: toProtocolChecks(ctx, checks),
+		Checks:              toProtocolChecks(ctx, checks),
+		PostureCheckResults: toProtocolPostureCheckResults(ctx, checks, peer),
 	}
 
-	nbConfig := toNetbirdConfig(config, turnCredentials, relayCredentials, extraSettings)
+	nbConfig := toNetbirdConfig(config, turnCredentials, relayCredentials, flowCredentials, extraSettings)
 	extendedConfig := integrationsConfig.ExtendNetBirdConfig(peer.ID, peerGroups, nbConfig, extraSettings)
 	response.NetbirdConfig = extendedConfig
 
 	response.NetworkMap.PeerConfig = response.PeerConfig
 
 	remotePeers := make([]*proto.RemotePeerConfig, 0, len(networkMap.Peers)+len(networkMap.OfflinePeers))
-	remotePeers = appendRemotePeerConfig(remotePeers, networkMap.Peers, dnsName)
+	remotePeers = appendRemotePeerConfig(remotePeers, networkMap.Peers, dnsName, response.PeerConfig.AddressV6 != "")
 	response.RemotePeers = remotePeers
 	response.NetworkMap.RemotePeers = remotePeers
 	response.RemotePeersIsEmpty = len(remotePeers) == 0
 	response.NetworkMap.RemotePeersIsEmpty = response.RemotePeersIsEmpty
 
-	response.NetworkMap.OfflinePeers = appendRemotePeerConfig(nil, networkMap.OfflinePeers, dnsName)
+	response.NetworkMap.OfflinePeers = appendRemotePeerConfig(nil, networkMap.OfflinePeers, dnsName, response.PeerConfig.AddressV6 != "")
 
 	firewallRules := toProtocolFirewallRules(networkMap.FirewallRules)
 	response.NetworkMap.FirewallRules = firewallRules
@@ -167,6 +200,24 @@ func ToSyncResponse(ctx context.Context, config *nbconfig.Config, httpConfig *nb
 	return response
 }
 
+// toProtocolPostureCheckResults evaluates the posture checks applied to the peer so the client can report failures
+func toProtocolPostureCheckResults(ctx context.Context, checks []*posture.Checks, peer *nbpeer.Peer) []*proto.PostureCheckResult {
+	var results []*proto.PostureCheckResult
+	for _, postureChecks := range checks {
+		for _, result := range postureChecks.Evaluate(ctx, *peer) {
+			results = append(results, &proto.PostureCheckResult{
+				PostureCheckId:   result.PostureChecksID,
+				PostureCheckName: result.PostureChecksName,
+				Check:            result.Check,
+				Passed:           result.Passed,
+				Reason:           result.Reason,
+				EvaluatedAt:      timestamppb.New(result.EvaluatedAt),
+			})
+		}
+	}
+	return results
+}
+
 func buildAuthorizedUsersProto(ctx context.Context, authorizedUsers map[string]map[string]struct{}) ([][]byte, map[string]*proto.MachineUserIndexes) {
 	userIDToIndex := make(map[string]uint32)
 	var hashedUsers [][]byte
@@ -194,11 +245,17 @@ func buildAuthorizedUsersProto(ctx context.Context, authorizedUsers map[string]m
 	return hashedUsers, machineUsers
 }
 
-func appendRemotePeerConfig(dst []*proto.RemotePeerConfig, peers []*nbpeer.Peer, dnsName string) []*proto.RemotePeerConfig {
+// appendRemotePeerConfig converts the remote peers to proto. The IPv6 address of a remote peer is appended to its
+// allowed IPs after the IPv4 one when both the local peer (withIPv6) and the remote peer have IPv6 configured.
+func appendRemotePeerConfig(dst []*proto.RemotePeerConfig, peers []*nbpeer.Peer, dnsName string, withIPv6 bool) []*proto.RemotePeerConfig {
 	for _, rPeer := range peers {
+		allowedIPs := []string{rPeer.IP.String() + "/32"}
+		if withIPv6 && rPeer.SupportsIPv6() {
+			allowedIPs = append(allowedIPs, rPeer.IPv6.String()+"/128")
+		}
 		dst = append(dst, &proto.RemotePeerConfig{
 			WgPubKey:     rPeer.Key,
-			AllowedIps:   []string{rPeer.IP.String() + "/32"},
+			AllowedIps:   allowedIPs,
 			SshConfig:    &proto.SSHConfig{SshPubKey: []byte(rPeer.SSHKey)},
 			Fqdn:         rPeer.FQDN(dnsName),
 			AgentVersion: rPeer.Meta.WtVersion,
@@ -2,6 +2,7 @@ package grpc
 
 import (
 	"fmt"
+	"net"
 	"net/netip"
 	"reflect"
 	"testing"
@@ -12,6 +13,8 @@ import (
 	"github.com/netbirdio/netbird/management/internals/controllers/network_map"
 	"github.com/netbirdio/netbird/management/internals/controllers/network_map/controller/cache"
 	nbconfig "github.com/netbirdio/netbird/management/internals/server/config"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/types"
 )
 
 func TestToProtocolDNSConfigWithCache(t *testing.T) {
@@ -199,3 +202,47 @@ func TestBuildJWTConfig_Audiences(t *testing.T) {
 		})
 	}
 }
+
+func TestToPeerConfig_IPv6(t *testing.T) {
+	network := &types.Network{Net: net.IPNet{IP: net.ParseIP("100.64.0.0"), Mask: net.CIDRMask(16, 32)}}
+	settings := &types.Settings{NetworkRangeV6: netip.MustParsePrefix("fd00:4e42:1::/64")}
+
+	peer := &nbpeer.Peer{
+		IP:   net.ParseIP("100.64.0.10"),
+		IPv6: net.ParseIP("fd00:4e42:1::a"),
+		Meta: nbpeer.PeerSystemMeta{Flags: nbpeer.Flags{IPv6Supported: true}},
+	}
+
+	config := toPeerConfig(peer, network, "", settings, nil, nil, false)
+	assert.Equal(t, "100.64.0.10/16", config.Address)
+	assert.Equal(t, "fd00:4e42:1::a/64", config.AddressV6)
+
+	peer.Meta.Flags.IPv6Supported = false
+	config = toPeerConfig(peer, network, "", settings, nil, nil, false)
+	assert.Empty(t, config.AddressV6, "clients without IPv6 support must not receive an IPv6 address")
+
+	peer.Meta.Flags.IPv6Supported = true
+	config = toPeerConfig(peer, network, "", &types.Settings{}, nil, nil, false)
+	assert.Empty(t, config.AddressV6, "no IPv6 address must be sent when the account has no IPv6 range")
+}
+
+func TestAppendRemotePeerConfig_IPv6(t *testing.T) {
+	dualStack := &nbpeer.Peer{
+		Key:  "dual",
+		IP:   net.ParseIP("100.64.0.1"),
+		IPv6: net.ParseIP("fd00::1"),
+		Meta: nbpeer.PeerSystemMeta{Flags: nbpeer.Flags{IPv6Supported: true}},
+	}
+	legacy := &nbpeer.Peer{
+		Key:  "legacy",
+		IP:   net.ParseIP("100.64.0.2"),
+		IPv6: net.ParseIP("fd00::2"),
+	}
+
+	remotePeers := appendRemotePeerConfig(nil, []*nbpeer.Peer{dualStack, legacy}, "", true)
+	assert.Equal(t, []string{"100.64.0.1/32", "fd00::1/128"}, remotePeers[0].AllowedIps)
+	assert.Equal(t, []string{"100.64.0.2/32"}, remotePeers[1].AllowedIps)
+
+	remotePeers = appendRemotePeerConfig(nil, []*nbpeer.Peer{dualStack}, "", false)
+	assert.Equal(t, []string{"100.64.0.1/32"}, remotePeers[0].AllowedIps)
+}
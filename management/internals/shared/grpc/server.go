@@ -24,6 +24,7 @@ import (
 	"google.golang.org/grpc/status"
 
 	"github.com/netbirdio/netbird/management/internals/controllers/network_map"
+	"github.com/netbirdio/netbird/management/internals/modules/workloadidentity"
 	nbconfig "github.com/netbirdio/netbird/management/internals/server/config"
 	"github.com/netbirdio/netbird/management/server/idp"
 
@@ -73,6 +74,8 @@ type Server struct {
 
 	oAuthConfigProvider idp.OAuthConfigProvider
 
+	workloadIdentityManager workloadidentity.Manager
+
 	syncSem atomic.Int32
 	syncLim int32
 }
@@ -88,6 +91,7 @@ func NewServer(
 	integratedPeerValidator integrated_validator.IntegratedValidator,
 	networkMapController network_map.Controller,
 	oAuthConfigProvider idp.OAuthConfigProvider,
+	workloadIdentityManager workloadidentity.Manager,
 ) (*Server, error) {
 	if appMetrics != nil {
 		// update gauge based on number of connected peers which is equal to open gRPC streams
@@ -125,6 +129,7 @@ func NewServer(
 		integratedPeerValidator:  integratedPeerValidator,
 		networkMapController:     networkMapController,
 		oAuthConfigProvider:      oAuthConfigProvider,
+		workloadIdentityManager:  workloadIdentityManager,
 
 		loginFilter: newLoginFilter(),
 
@@ -273,7 +278,7 @@ func (s *Server) Sync(req *proto.EncryptedMessage, srv proto.ManagementService_S
 		return err
 	}
 
-	s.secretsManager.SetupRefresh(ctx, accountID, peer.ID)
+	s.secretsManager.SetupRefresh(ctx, accountID, peer.ID, peer.Key)
 
 	if s.appMetrics != nil {
 		s.appMetrics.GRPCMetrics().CountSyncRequestDuration(time.Since(reqStart), accountID)
@@ -499,8 +504,15 @@ func extractPeerMeta(ctx context.Context, meta *proto.PeerSystemMeta) nbpeer.Pee
 			BlockLANAccess:        meta.GetFlags().GetBlockLANAccess(),
 			BlockInbound:          meta.GetFlags().GetBlockInbound(),
 			LazyConnectionEnabled: meta.GetFlags().GetLazyConnectionEnabled(),
+			IPv6Supported:         meta.GetFlags().GetIpv6Supported(),
 		},
 		Files: files,
+		DiskEncryption: nbpeer.DiskEncryption{
+			EncryptedVolumes: meta.GetDiskEncryption().GetEncryptedVolumes(),
+		},
+		OSFirewall: nbpeer.OSFirewall{
+			ActiveBackends: meta.GetOsFirewall().GetActiveBackends(),
+		},
 	}
 }
 
@@ -583,7 +595,7 @@ func (s *Server) Login(ctx context.Context, req *proto.EncryptedMessage) (*proto
 		return nil, msg
 	}
 
-	userID, err := s.processJwtToken(ctx, loginReq, peerKey)
+	userID, workload, err := s.processJwtToken(ctx, loginReq, peerKey)
 	if err != nil {
 		return nil, err
 	}
@@ -601,6 +613,8 @@ func (s *Server) Login(ctx context.Context, req *proto.EncryptedMessage) (*proto
 		SetupKey:        loginReq.GetSetupKey(),
 		ConnectionIP:    realIP,
 		ExtraDNSLabels:  loginReq.GetDnsLabels(),
+
+		WorkloadIdentity: workload,
 	})
 	if err != nil {
 		log.WithContext(ctx).Warnf("failed logging in peer %s: %s", peerKey, err)
@@ -635,12 +649,14 @@ func (s *Server) prepareLoginResponse(ctx context.Context, peer *nbpeer.Peer, ne
 	var relayToken *Token
 	var err error
 	if s.config.Relay != nil && len(s.config.Relay.Addresses) > 0 {
-		relayToken, err = s.secretsManager.GenerateRelayToken()
+		relayToken, err = s.secretsManager.GenerateRelayToken(peer.AccountID)
 		if err != nil {
 			log.Errorf("failed generating Relay token: %v", err)
 		}
 	}
 
+	flowToken := s.generateFlowToken(peer)
+
 	settings, err := s.settingsManager.GetSettings(ctx, peer.AccountID, activity.SystemInitiator)
 	if err != nil {
 		log.WithContext(ctx).Warnf("failed getting settings for peer %s: %s", peer.Key, err)
@@ -649,7 +665,7 @@ func (s *Server) prepareLoginResponse(ctx context.Context, peer *nbpeer.Peer, ne
 
 	// if peer has reached this point then it has logged in
 	loginResp := &proto.LoginResponse{
-		NetbirdConfig: toNetbirdConfig(s.config, nil, relayToken, nil),
+		NetbirdConfig: toNetbirdConfig(s.config, nil, relayToken, flowToken, nil),
 		PeerConfig:    toPeerConfig(peer, netMap.Network, s.networkMapController.GetDNSDomain(settings), settings, s.config.HttpConfig, s.config.DeviceAuthorizationFlow, netMap.EnableSSH),
 		Checks:        toProtocolChecks(ctx, postureChecks),
 	}
@@ -658,14 +674,22 @@ func (s *Server) prepareLoginResponse(ctx context.Context, peer *nbpeer.Peer, ne
 }
 
 // processJwtToken validates the existence of a JWT token in the login request, and returns the corresponding user ID if
-// the token is valid.
+// the token is valid. Tokens of the configured workload identity providers return the workload identity instead.
 //
 // The user ID can be empty if the token is not provided, which is acceptable if the peer is already
 // registered or if it uses a setup key to register.
-func (s *Server) processJwtToken(ctx context.Context, loginReq *proto.LoginRequest, peerKey wgtypes.Key) (string, error) {
+func (s *Server) processJwtToken(ctx context.Context, loginReq *proto.LoginRequest, peerKey wgtypes.Key) (string, *types.WorkloadIdentity, error) {
 	userID := ""
 	if loginReq.GetJwtToken() != "" {
-		var err error
+		workload, err := s.authenticateWorkload(ctx, loginReq.GetJwtToken())
+		if err != nil {
+			log.WithContext(ctx).Warnf("failed validating workload identity token sent from peer %s: %v", peerKey.String(), err)
+			return "", nil, mapError(ctx, err)
+		}
+		if workload != nil {
+			return "", workload, nil
+		}
+
 		for i := 0; i < 3; i++ {
 			userID, err = s.validateToken(ctx, loginReq.GetJwtToken())
 			if err == nil {
@@ -676,10 +700,24 @@ func (s *Server) processJwtToken(ctx context.Context, loginReq *proto.LoginReque
 			time.Sleep(200 * time.Millisecond)
 		}
 		if err != nil {
-			return "", err
+			return "", nil, err
 		}
 	}
-	return userID, nil
+	return userID, nil, nil
+}
+
+// authenticateWorkload returns the workload identity of the token, it is nil if the token is not a workload
+// identity token
+func (s *Server) authenticateWorkload(ctx context.Context, token string) (*types.WorkloadIdentity, error) {
+	if s.workloadIdentityManager == nil {
+		return nil, nil
+	}
+
+	workload, err := s.workloadIdentityManager.Authenticate(ctx, token)
+	if errors.Is(err, workloadidentity.ErrNotWorkloadToken) {
+		return nil, nil
+	}
+	return workload, err
 }
 
 // IsHealthy indicates whether the service is healthy
@@ -687,6 +725,20 @@ func (s *Server) IsHealthy(ctx context.Context, req *proto.Empty) (*proto.Empty,
 	return &proto.Empty{}, nil
 }
 
+// generateFlowToken returns the flow collector token of the peer, it is nil if no flow collector is configured
+func (s *Server) generateFlowToken(peer *nbpeer.Peer) *Token {
+	if s.config.Flow == nil || s.config.Flow.URL == "" {
+		return nil
+	}
+
+	flowToken, err := s.secretsManager.GenerateFlowToken(peer.Key)
+	if err != nil {
+		log.Errorf("failed generating flow token: %v", err)
+		return nil
+	}
+	return flowToken
+}
+
 // sendInitialSync sends initial proto.SyncResponse to the peer requesting synchronization
 func (s *Server) sendInitialSync(ctx context.Context, peerKey wgtypes.Key, peer *nbpeer.Peer, networkMap *types.NetworkMap, postureChecks []*posture.Checks, srv proto.ManagementService_SyncServer, dnsFwdPort int64) error {
 	var err error
@@ -701,12 +753,14 @@ func (s *Server) sendInitialSync(ctx context.Context, peerKey wgtypes.Key, peer
 
 	var relayToken *Token
 	if s.config.Relay != nil && len(s.config.Relay.Addresses) > 0 {
-		relayToken, err = s.secretsManager.GenerateRelayToken()
+		relayToken, err = s.secretsManager.GenerateRelayToken(peer.AccountID)
 		if err != nil {
 			log.Errorf("failed generating Relay token: %v", err)
 		}
 	}
 
+	flowToken := s.generateFlowToken(peer)
+
 	settings, err := s.settingsManager.GetSettings(ctx, peer.AccountID, activity.SystemInitiator)
 	if err != nil {
 		return status.Errorf(codes.Internal, "error handling request")
@@ -717,7 +771,7 @@ func (s *Server) sendInitialSync(ctx context.Context, peerKey wgtypes.Key, peer
 		return status.Errorf(codes.Internal, "failed to get peer groups %s", err)
 	}
 
-	plainResp := ToSyncResponse(ctx, s.config, s.config.HttpConfig, s.config.DeviceAuthorizationFlow, peer, turnToken, relayToken, networkMap, s.networkMapController.GetDNSDomain(settings), postureChecks, nil, settings, settings.Extra, peerGroups, dnsFwdPort)
+	plainResp := ToSyncResponse(ctx, s.config, s.config.HttpConfig, s.config.DeviceAuthorizationFlow, peer, turnToken, relayToken, flowToken, networkMap, s.networkMapController.GetDNSDomain(settings), postureChecks, nil, settings, settings.Extra, peerGroups, dnsFwdPort)
 
 	key, err := s.secretsManager.GetWGKey()
 	if err != nil {
@@ -983,5 +1037,13 @@ func toProtocolCheck(postureCheck *posture.Checks) *proto.Checks {
 		}
 	}
 
+	if postureCheck.Checks.DiskEncryptionCheck != nil {
+		protoCheck.DiskEncryption = true
+	}
+
+	if postureCheck.Checks.OSFirewallCheck != nil {
+		protoCheck.OsFirewall = true
+	}
+
 	return protoCheck
 }
@@ -17,40 +17,49 @@ import (
 	nbconfig "github.com/netbirdio/netbird/management/internals/server/config"
 	"github.com/netbirdio/netbird/management/server/groups"
 	"github.com/netbirdio/netbird/management/server/settings"
+	flowauth "github.com/netbirdio/netbird/shared/flow/auth"
 	"github.com/netbirdio/netbird/shared/management/proto"
 	auth "github.com/netbirdio/netbird/shared/relay/auth/hmac"
 	authv2 "github.com/netbirdio/netbird/shared/relay/auth/hmac/v2"
 )
 
-const defaultDuration = 12 * time.Hour
+const (
+	defaultDuration     = 12 * time.Hour
+	defaultFlowInterval = 10 * time.Second
+)
 
-// SecretsManager used to manage TURN and relay secrets
+// SecretsManager used to manage TURN, relay and flow secrets
 type SecretsManager interface {
 	GenerateTurnToken() (*Token, error)
-	GenerateRelayToken() (*Token, error)
-	SetupRefresh(ctx context.Context, accountID, peerKey string)
-	CancelRefresh(peerKey string)
+	GenerateRelayToken(accountID string) (*Token, error)
+	GenerateFlowToken(peerKey string) (*Token, error)
+	SetupRefresh(ctx context.Context, accountID, peerID, peerKey string)
+	CancelRefresh(peerID string)
 	GetWGKey() (wgtypes.Key, error)
 }
 
-// TimeBasedAuthSecretsManager generates credentials with TTL and using pre-shared secret known to TURN server
+// TimeBasedAuthSecretsManager generates credentials with TTL and using pre-shared secret known to TURN, relay and
+// flow servers
 type TimeBasedAuthSecretsManager struct {
 	mux             sync.Mutex
 	turnCfg         *nbconfig.TURNConfig
 	relayCfg        *nbconfig.Relay
+	flowCfg         *nbconfig.Flow
 	turnHmacToken   *auth.TimedHMAC
 	relayHmacToken  *authv2.Generator
+	flowTokenTTL    time.Duration
 	updateManager   network_map.PeersUpdateManager
 	settingsManager settings.Manager
 	groupsManager   groups.Manager
 	turnCancelMap   map[string]chan struct{}
 	relayCancelMap  map[string]chan struct{}
+	flowCancelMap   map[string]chan struct{}
 	wgKey           wgtypes.Key
 }
 
 type Token auth.Token
 
-func NewTimeBasedAuthSecretsManager(updateManager network_map.PeersUpdateManager, turnCfg *nbconfig.TURNConfig, relayCfg *nbconfig.Relay, settingsManager settings.Manager, groupsManager groups.Manager) (*TimeBasedAuthSecretsManager, error) {
+func NewTimeBasedAuthSecretsManager(updateManager network_map.PeersUpdateManager, turnCfg *nbconfig.TURNConfig, relayCfg *nbconfig.Relay, flowCfg *nbconfig.Flow, settingsManager settings.Manager, groupsManager groups.Manager) (*TimeBasedAuthSecretsManager, error) {
 	key, err := wgtypes.GeneratePrivateKey()
 	if err != nil {
 		return nil, err
@@ -62,6 +71,7 @@ func NewTimeBasedAuthSecretsManager(updateManager network_map.PeersUpdateManager
 		relayCfg:        relayCfg,
 		turnCancelMap:   make(map[string]chan struct{}),
 		relayCancelMap:  make(map[string]chan struct{}),
+		flowCancelMap:   make(map[string]chan struct{}),
 		settingsManager: settingsManager,
 		groupsManager:   groupsManager,
 		wgKey:           key,
@@ -90,6 +100,15 @@ func NewTimeBasedAuthSecretsManager(updateManager network_map.PeersUpdateManager
 		}
 	}
 
+	if flowCfg != nil && flowCfg.URL != "" {
+		mgr.flowCfg = flowCfg
+		mgr.flowTokenTTL = flowCfg.CredentialsTTL.Duration
+		if mgr.flowTokenTTL <= 0 {
+			log.Warnf("Flow credentials TTL is not set or invalid, using default value %s", defaultDuration)
+			mgr.flowTokenTTL = defaultDuration
+		}
+	}
+
 	return mgr, nil
 }
 
@@ -110,12 +129,16 @@ func (m *TimeBasedAuthSecretsManager) GenerateTurnToken() (*Token, error) {
 	return (*Token)(turnToken), nil
 }
 
-// GenerateRelayToken generates new time-based secret credentials for relay
-func (m *TimeBasedAuthSecretsManager) GenerateRelayToken() (*Token, error) {
+// GenerateRelayToken generates new time-based secret credentials for relay. The account ID is added to the token
+// when account tokens are enabled in the relay configuration.
+func (m *TimeBasedAuthSecretsManager) GenerateRelayToken(accountID string) (*Token, error) {
 	if m.relayHmacToken == nil {
 		return nil, fmt.Errorf("relay configuration is not set")
 	}
-	relayToken, err := m.relayHmacToken.GenerateToken()
+	if !m.relayCfg.AccountTokens {
+		accountID = ""
+	}
+	relayToken, err := m.relayHmacToken.GenerateAccountToken(accountID)
 	if err != nil {
 		return nil, fmt.Errorf("generate relay token: %s", err)
 	}
@@ -126,6 +149,23 @@ func (m *TimeBasedAuthSecretsManager) GenerateRelayToken() (*Token, error) {
 	}, nil
 }
 
+// GenerateFlowToken generates new time-based secret credentials for the flow collector. The token is bound to the
+// WireGuard public key of the peer, so the collector only accepts the events of the peer.
+func (m *TimeBasedAuthSecretsManager) GenerateFlowToken(peerKey string) (*Token, error) {
+	if m.flowCfg == nil {
+		return nil, fmt.Errorf("flow configuration is not set")
+	}
+	payload, signature, err := flowauth.GenerateToken(m.flowCfg.Secret, peerKey, m.flowTokenTTL)
+	if err != nil {
+		return nil, fmt.Errorf("generate flow token: %s", err)
+	}
+
+	return &Token{
+		Payload:   payload,
+		Signature: signature,
+	}, nil
+}
+
 func (m *TimeBasedAuthSecretsManager) cancelTURN(peerID string) {
 	if channel, ok := m.turnCancelMap[peerID]; ok {
 		close(channel)
@@ -140,21 +180,30 @@ func (m *TimeBasedAuthSecretsManager) cancelRelay(peerID string) {
 	}
 }
 
+func (m *TimeBasedAuthSecretsManager) cancelFlow(peerID string) {
+	if channel, ok := m.flowCancelMap[peerID]; ok {
+		close(channel)
+		delete(m.flowCancelMap, peerID)
+	}
+}
+
 // CancelRefresh cancels scheduled peer credentials refresh
 func (m *TimeBasedAuthSecretsManager) CancelRefresh(peerID string) {
 	m.mux.Lock()
 	defer m.mux.Unlock()
 	m.cancelTURN(peerID)
 	m.cancelRelay(peerID)
+	m.cancelFlow(peerID)
 }
 
 // SetupRefresh starts peer credentials refresh
-func (m *TimeBasedAuthSecretsManager) SetupRefresh(ctx context.Context, accountID, peerID string) {
+func (m *TimeBasedAuthSecretsManager) SetupRefresh(ctx context.Context, accountID, peerID, peerKey string) {
 	m.mux.Lock()
 	defer m.mux.Unlock()
 
 	m.cancelTURN(peerID)
 	m.cancelRelay(peerID)
+	m.cancelFlow(peerID)
 
 	if m.turnCfg != nil && m.turnCfg.TimeBasedCredentials {
 		turnCancel := make(chan struct{}, 1)
@@ -169,6 +218,13 @@ func (m *TimeBasedAuthSecretsManager) SetupRefresh(ctx context.Context, accountI
 		go m.refreshRelayTokens(ctx, accountID, peerID, relayCancel)
 		log.WithContext(ctx).Tracef("starting relay refresh for %s", peerID)
 	}
+
+	if m.flowCfg != nil {
+		flowCancel := make(chan struct{}, 1)
+		m.flowCancelMap[peerID] = flowCancel
+		go m.refreshFlowTokens(ctx, accountID, peerID, peerKey, flowCancel)
+		log.WithContext(ctx).Tracef("starting flow refresh for %s", peerID)
+	}
 }
 
 func (m *TimeBasedAuthSecretsManager) refreshTURNTokens(ctx context.Context, accountID, peerID string, cancel chan struct{}) {
@@ -201,6 +257,21 @@ func (m *TimeBasedAuthSecretsManager) refreshRelayTokens(ctx context.Context, ac
 	}
 }
 
+func (m *TimeBasedAuthSecretsManager) refreshFlowTokens(ctx context.Context, accountID, peerID, peerKey string, cancel chan struct{}) {
+	ticker := time.NewTicker(m.flowTokenTTL / 4 * 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-cancel:
+			log.WithContext(ctx).Tracef("stopping flow refresh for %s", peerID)
+			return
+		case <-ticker.C:
+			m.pushNewFlowTokens(ctx, accountID, peerID, peerKey)
+		}
+	}
+}
+
 func (m *TimeBasedAuthSecretsManager) pushNewTURNAndRelayTokens(ctx context.Context, accountID, peerID string) {
 	turnToken, err := m.turnHmacToken.GenerateToken(sha1.New)
 	if err != nil {
@@ -229,7 +300,7 @@ func (m *TimeBasedAuthSecretsManager) pushNewTURNAndRelayTokens(ctx context.Cont
 
 	// workaround for the case when client is unable to handle turn and relay updates at different time
 	if m.relayCfg != nil {
-		token, err := m.GenerateRelayToken()
+		token, err := m.GenerateRelayToken(accountID)
 		if err == nil {
 			update.NetbirdConfig.Relay = &proto.RelayConfig{
 				Urls:           m.relayCfg.Addresses,
@@ -246,7 +317,7 @@ func (m *TimeBasedAuthSecretsManager) pushNewTURNAndRelayTokens(ctx context.Cont
 }
 
 func (m *TimeBasedAuthSecretsManager) pushNewRelayTokens(ctx context.Context, accountID, peerID string) {
-	relayToken, err := m.relayHmacToken.GenerateToken()
+	relayToken, err := m.GenerateRelayToken(accountID)
 	if err != nil {
 		log.Errorf("failed to generate relay token for peer '%s': %s", peerID, err)
 		return
@@ -256,8 +327,8 @@ func (m *TimeBasedAuthSecretsManager) pushNewRelayTokens(ctx context.Context, ac
 		NetbirdConfig: &proto.NetbirdConfig{
 			Relay: &proto.RelayConfig{
 				Urls:           m.relayCfg.Addresses,
-				TokenPayload:   string(relayToken.Payload),
-				TokenSignature: base64.StdEncoding.EncodeToString(relayToken.Signature),
+				TokenPayload:   relayToken.Payload,
+				TokenSignature: relayToken.Signature,
 			},
 			// omit Turns to avoid updates there
 		},
@@ -269,6 +340,38 @@ func (m *TimeBasedAuthSecretsManager) pushNewRelayTokens(ctx context.Context, ac
 	m.updateManager.SendUpdate(ctx, peerID, &network_map.UpdateMessage{Update: update})
 }
 
+func (m *TimeBasedAuthSecretsManager) pushNewFlowTokens(ctx context.Context, accountID, peerID, peerKey string) {
+	flowToken, err := m.GenerateFlowToken(peerKey)
+	if err != nil {
+		log.WithContext(ctx).Errorf("failed to generate flow token for peer '%s': %s", peerID, err)
+		return
+	}
+
+	update := &proto.SyncResponse{
+		NetbirdConfig: &proto.NetbirdConfig{
+			Flow: toFlowConfig(m.flowCfg, flowToken),
+			// omit Turns to avoid updates there
+		},
+	}
+
+	// the client drops the relay servers on updates without relay configuration
+	if m.relayCfg != nil {
+		token, err := m.GenerateRelayToken(accountID)
+		if err == nil {
+			update.NetbirdConfig.Relay = &proto.RelayConfig{
+				Urls:           m.relayCfg.Addresses,
+				TokenPayload:   token.Payload,
+				TokenSignature: token.Signature,
+			}
+		}
+	}
+
+	m.extendNetbirdConfig(ctx, peerID, accountID, update)
+
+	log.WithContext(ctx).Debugf("sending new flow credentials to peer %s", peerID)
+	m.updateManager.SendUpdate(ctx, peerID, &network_map.UpdateMessage{Update: update})
+}
+
 func (m *TimeBasedAuthSecretsManager) extendNetbirdConfig(ctx context.Context, peerID, accountID string, update *proto.SyncResponse) {
 	extraSettings, err := m.settingsManager.GetExtraSettings(ctx, accountID)
 	if err != nil {
@@ -19,7 +19,9 @@ import (
 	"github.com/netbirdio/netbird/management/server/groups"
 	"github.com/netbirdio/netbird/management/server/settings"
 	"github.com/netbirdio/netbird/management/server/types"
+	flowauth "github.com/netbirdio/netbird/shared/flow/auth"
 	"github.com/netbirdio/netbird/shared/management/proto"
+	authv2 "github.com/netbirdio/netbird/shared/relay/auth/hmac/v2"
 	"github.com/netbirdio/netbird/util"
 )
 
@@ -51,7 +53,7 @@ func TestTimeBasedAuthSecretsManager_GenerateCredentials(t *testing.T) {
 		Secret:               secret,
 		Turns:                []*config.Host{TurnTestHost},
 		TimeBasedCredentials: true,
-	}, rc, settingsMockManager, groupsManager)
+	}, rc, nil, settingsMockManager, groupsManager)
 	require.NoError(t, err)
 
 	turnCredentials, err := tested.GenerateTurnToken()
@@ -66,8 +68,9 @@ func TestTimeBasedAuthSecretsManager_GenerateCredentials(t *testing.T) {
 
 	validateMAC(t, sha1.New, turnCredentials.Payload, turnCredentials.Signature, []byte(secret))
 
-	relayCredentials, err := tested.GenerateRelayToken()
+	relayCredentials, err := tested.GenerateRelayToken("account-id")
 	require.NoError(t, err)
+	require.NotContains(t, relayCredentials.Payload, "account-id", "account tokens are disabled by default")
 
 	if relayCredentials.Payload == "" {
 		t.Errorf("expected generated relay payload not to be empty, got empty")
@@ -80,11 +83,40 @@ func TestTimeBasedAuthSecretsManager_GenerateCredentials(t *testing.T) {
 	validateMAC(t, sha256.New, relayCredentials.Payload, relayCredentials.Signature, hashedSecret[:])
 }
 
+func TestTimeBasedAuthSecretsManager_GenerateAccountRelayToken(t *testing.T) {
+	secret := "some_secret"
+	rc := &config.Relay{
+		Addresses:      []string{"localhost:0"},
+		CredentialsTTL: util.Duration{Duration: time.Hour},
+		Secret:         secret,
+		AccountTokens:  true,
+	}
+
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	tested, err := NewTimeBasedAuthSecretsManager(update_channel.NewPeersUpdateManager(nil), nil, rc, nil, settings.NewMockManager(ctrl), groups.NewManagerMock())
+	require.NoError(t, err)
+
+	relayCredentials, err := tested.GenerateRelayToken("account-id")
+	require.NoError(t, err)
+
+	signature, err := base64.StdEncoding.DecodeString(relayCredentials.Signature)
+	require.NoError(t, err)
+	token := &authv2.Token{AuthAlgo: authv2.AuthAlgoHMACSHA256, Signature: signature, Payload: []byte(relayCredentials.Payload)}
+
+	hashedSecret := sha256.Sum256([]byte(secret))
+	validator := authv2.NewValidator(hashedSecret[:])
+	require.NoError(t, validator.Validate(token.Marshal()))
+	require.Equal(t, "account-id", validator.AccountID(token.Marshal()))
+}
+
 func TestTimeBasedAuthSecretsManager_SetupRefresh(t *testing.T) {
 	ttl := util.Duration{Duration: 2 * time.Second}
 	secret := "some_secret"
 	peersManager := update_channel.NewPeersUpdateManager(nil)
 	peer := "some_peer"
+	peerKey := "some_peer_key"
 	updateChannel := peersManager.CreateChannel(context.Background(), peer)
 
 	rc := &config.Relay{
@@ -104,13 +136,13 @@ func TestTimeBasedAuthSecretsManager_SetupRefresh(t *testing.T) {
 		Secret:               secret,
 		Turns:                []*config.Host{TurnTestHost},
 		TimeBasedCredentials: true,
-	}, rc, settingsMockManager, groupsManager)
+	}, rc, nil, settingsMockManager, groupsManager)
 	require.NoError(t, err)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	tested.SetupRefresh(ctx, "someAccountID", peer)
+	tested.SetupRefresh(ctx, "someAccountID", peer, peerKey)
 
 	if _, ok := tested.turnCancelMap[peer]; !ok {
 		t.Errorf("expecting peer to be present in the turn cancel map, got not present")
@@ -186,11 +218,65 @@ loop:
 	}
 }
 
+func TestTimeBasedAuthSecretsManager_FlowTokens(t *testing.T) {
+	secret := "some_secret"
+	peersManager := update_channel.NewPeersUpdateManager(nil)
+	peer := "some_peer"
+	peerKey := "some_peer_key"
+	updateChannel := peersManager.CreateChannel(context.Background(), peer)
+
+	fc := &config.Flow{
+		URL:            "https://flow.netbird.io:443",
+		Secret:         secret,
+		CredentialsTTL: util.Duration{Duration: 2 * time.Second},
+		Counters:       true,
+	}
+
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+	settingsMockManager := settings.NewMockManager(ctrl)
+	settingsMockManager.EXPECT().GetExtraSettings(gomock.Any(), "someAccountID").Return(&types.ExtraSettings{}, nil).AnyTimes()
+
+	tested, err := NewTimeBasedAuthSecretsManager(peersManager, nil, nil, fc, settingsMockManager, groups.NewManagerMock())
+	require.NoError(t, err)
+
+	flowCredentials, err := tested.GenerateFlowToken(peerKey)
+	require.NoError(t, err)
+	boundKey, err := flowauth.ValidateToken(secret, flowCredentials.Payload, flowCredentials.Signature)
+	require.NoError(t, err)
+	require.Equal(t, peerKey, boundKey, "flow tokens are bound to the peer key")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tested.SetupRefresh(ctx, "someAccountID", peer, peerKey)
+
+	select {
+	case update := <-updateChannel:
+		flow := update.Update.GetNetbirdConfig().GetFlow()
+		require.NotNil(t, flow, "expecting a flow configuration update")
+		require.Equal(t, fc.URL, flow.GetUrl())
+		require.True(t, flow.GetEnabled())
+		require.True(t, flow.GetCounters())
+		boundKey, err = flowauth.ValidateToken(secret, flow.GetTokenPayload(), flow.GetTokenSignature())
+		require.NoError(t, err)
+		require.Equal(t, peerKey, boundKey)
+	case <-time.After(5 * time.Second):
+		t.Fatal("expecting a flow credentials update")
+	}
+
+	tested.CancelRefresh(peer)
+	if _, ok := tested.flowCancelMap[peer]; ok {
+		t.Errorf("expecting peer to be not present in flow cancel map, got present")
+	}
+}
+
 func TestTimeBasedAuthSecretsManager_CancelRefresh(t *testing.T) {
 	ttl := util.Duration{Duration: time.Hour}
 	secret := "some_secret"
 	peersManager := update_channel.NewPeersUpdateManager(nil)
 	peer := "some_peer"
+	peerKey := "some_peer_key"
 
 	rc := &config.Relay{
 		Addresses:      []string{"localhost:0"},
@@ -208,10 +294,10 @@ func TestTimeBasedAuthSecretsManager_CancelRefresh(t *testing.T) {
 		Secret:               secret,
 		Turns:                []*config.Host{TurnTestHost},
 		TimeBasedCredentials: true,
-	}, rc, settingsMockManager, groupsManager)
+	}, rc, nil, settingsMockManager, groupsManager)
 	require.NoError(t, err)
 
-	tested.SetupRefresh(context.Background(), "someAccountID", peer)
+	tested.SetupRefresh(context.Background(), "someAccountID", peer, peerKey)
 	if _, ok := tested.turnCancelMap[peer]; !ok {
 		t.Errorf("expecting peer to be present in turn cancel map, got not present")
 	}
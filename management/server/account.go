@@ -96,6 +96,8 @@ type DefaultAccountManager struct {
 
 	peerInactivityExpiry Scheduler
 
+	policySchedules Scheduler
+
 	// userDeleteFromIDPEnabled allows to delete user from IDP when user is deleted from account
 	userDeleteFromIDPEnabled bool
 
@@ -106,6 +108,13 @@ type DefaultAccountManager struct {
 	permissionsManager permissions.Manager
 
 	disableDefaultPolicy bool
+
+	// externalVerdicts caches the verdicts of the external posture checks and holds their gRPC connections
+	externalVerdicts *posture.ExternalVerdicts
+
+	accountDeletedListenersMu sync.Mutex
+	// accountDeletedListeners release the in-memory state other modules keep for an account once it is deleted
+	accountDeletedListeners []func(ctx context.Context, accountID string)
 }
 
 var _ account.Manager = (*DefaultAccountManager)(nil)
@@ -207,6 +216,7 @@ func BuildManager(
 		eventStore:               eventStore,
 		peerLoginExpiry:          NewDefaultScheduler(),
 		peerInactivityExpiry:     NewDefaultScheduler(),
+		policySchedules:          NewDefaultScheduler(),
 		userDeleteFromIDPEnabled: userDeleteFromIDPEnabled,
 		integratedPeerValidator:  integratedPeerValidator,
 		metrics:                  metrics,
@@ -217,6 +227,11 @@ func BuildManager(
 		disableDefaultPolicy:     disableDefaultPolicy,
 	}
 
+	am.externalVerdicts = posture.NewExternalVerdicts(func(accountID string) {
+		am.BufferUpdateAccountPeers(ctx, accountID)
+	})
+	store.SetExternalVerdicts(am.externalVerdicts)
+
 	am.networkMapController.StartWarmup(ctx)
 
 	accountsCounter, err := store.GetAccountsCounter(ctx)
@@ -258,9 +273,16 @@ func BuildManager(
 		am.onPeersInvalidated(ctx, accountID, peerIDs)
 	})
 
+	am.scheduleAllPolicyTransitions(ctx)
+
 	return am, nil
 }
 
+// Stop closes the connections of the external posture checks.
+func (am *DefaultAccountManager) Stop() {
+	am.externalVerdicts.Close()
+}
+
 func (am *DefaultAccountManager) GetExternalCacheManager() account.ExternalCacheManager {
 	return am.externalCacheManager
 }
@@ -319,6 +341,13 @@ func (am *DefaultAccountManager) UpdateAccountSettings(ctx context.Context, acco
 			updateAccountPeers = true
 		}
 
+		if oldSettings.NetworkRangeV6 != newSettings.NetworkRangeV6 {
+			if err = am.reallocateAccountPeerIPv6s(ctx, transaction, accountID, newSettings.NetworkRangeV6); err != nil {
+				return err
+			}
+			updateAccountPeers = true
+		}
+
 		if oldSettings.RoutingPeerDNSResolutionEnabled != newSettings.RoutingPeerDNSResolutionEnabled ||
 			oldSettings.LazyConnectionEnabled != newSettings.LazyConnectionEnabled ||
 			oldSettings.DNSDomain != newSettings.DNSDomain ||
@@ -380,6 +409,13 @@ func (am *DefaultAccountManager) UpdateAccountSettings(ctx context.Context, acco
 		}
 		am.StoreEvent(ctx, userID, accountID, accountID, activity.AccountNetworkRangeUpdated, eventMeta)
 	}
+	if oldSettings.NetworkRangeV6 != newSettings.NetworkRangeV6 {
+		eventMeta := map[string]any{
+			"old_network_range_v6": oldSettings.NetworkRangeV6.String(),
+			"new_network_range_v6": newSettings.NetworkRangeV6.String(),
+		}
+		am.StoreEvent(ctx, userID, accountID, accountID, activity.AccountNetworkRangeV6Updated, eventMeta)
+	}
 
 	if updateAccountPeers || extraSettingsChanged || groupChangesAffectPeers {
 		go am.UpdateAccountPeers(ctx, accountID)
@@ -398,6 +434,10 @@ func (am *DefaultAccountManager) validateSettingsUpdate(ctx context.Context, tra
 		return status.Errorf(status.InvalidArgument, "peer login expiration can't be smaller than one hour")
 	}
 
+	if err := types.ValidateNetworkRangeV6(newSettings.NetworkRangeV6); err != nil {
+		return err
+	}
+
 	if newSettings.DNSDomain != "" && !isDomainValid(newSettings.DNSDomain) {
 		return status.Errorf(status.InvalidArgument, "invalid domain \"%s\" provided for DNS domain", newSettings.DNSDomain)
 	}
@@ -736,6 +776,8 @@ func (am *DefaultAccountManager) DeleteAccount(ctx context.Context, accountID, u
 	}
 	// cancel peer login expiry job
 	am.peerLoginExpiry.Cancel(ctx, []string{account.Id})
+	am.policySchedules.Cancel(ctx, []string{account.Id})
+	am.notifyAccountDeleted(ctx, account.Id)
 
 	meta := map[string]any{"account_id": account.Id, "domain": account.Domain, "created_at": account.CreatedAt}
 	am.StoreEvent(ctx, userID, accountID, accountID, activity.AccountDeleted, meta)
@@ -744,6 +786,24 @@ func (am *DefaultAccountManager) DeleteAccount(ctx context.Context, accountID, u
 	return nil
 }
 
+// AddAccountDeletedListener registers a listener called after an account is deleted
+func (am *DefaultAccountManager) AddAccountDeletedListener(listener func(ctx context.Context, accountID string)) {
+	am.accountDeletedListenersMu.Lock()
+	defer am.accountDeletedListenersMu.Unlock()
+
+	am.accountDeletedListeners = append(am.accountDeletedListeners, listener)
+}
+
+func (am *DefaultAccountManager) notifyAccountDeleted(ctx context.Context, accountID string) {
+	am.accountDeletedListenersMu.Lock()
+	listeners := slices.Clone(am.accountDeletedListeners)
+	am.accountDeletedListenersMu.Unlock()
+
+	for _, listener := range listeners {
+		listener(ctx, accountID)
+	}
+}
+
 // AccountExists checks if an account exists.
 func (am *DefaultAccountManager) AccountExists(ctx context.Context, accountID string) (bool, error) {
 	return am.Store.AccountExists(ctx, store.LockingStrengthNone, accountID)
@@ -2083,6 +2143,43 @@ func (am *DefaultAccountManager) reallocateAccountPeerIPs(ctx context.Context, t
 	return nil
 }
 
+// reallocateAccountPeerIPv6s allocates new IPv6 addresses for all peers when the IPv6 network range changes.
+// Peer IPv6 addresses are removed when the range is unset.
+func (am *DefaultAccountManager) reallocateAccountPeerIPv6s(ctx context.Context, transaction store.Store, accountID string, newNetworkRange netip.Prefix) error {
+	peers, err := transaction.GetAccountPeers(ctx, store.LockingStrengthUpdate, accountID, "", "")
+	if err != nil {
+		return err
+	}
+
+	var takenIPs []net.IP
+
+	for _, peer := range peers {
+		if !newNetworkRange.IsValid() {
+			peer.IPv6 = nil
+			continue
+		}
+
+		newIP, err := types.AllocatePeerIPv6(newNetworkRange, takenIPs)
+		if err != nil {
+			return status.Errorf(status.Internal, "allocate IPv6 for peer %s: %v", peer.ID, err)
+		}
+
+		peer.IPv6 = newIP
+		takenIPs = append(takenIPs, newIP)
+	}
+
+	for _, peer := range peers {
+		if err = transaction.SavePeer(ctx, accountID, peer); err != nil {
+			return status.Errorf(status.Internal, "save updated peer %s: %v", peer.ID, err)
+		}
+	}
+
+	log.WithContext(ctx).Infof("updated IPv6 addresses of %d peers in account %s to network range %q",
+		len(peers), accountID, newNetworkRange.String())
+
+	return nil
+}
+
 func (am *DefaultAccountManager) validateIPForUpdate(account *types.Account, peers []*nbpeer.Peer, peerID string, newIP netip.Addr) error {
 	if !account.Network.Net.Contains(newIP.AsSlice()) {
 		return status.Errorf(status.InvalidArgument, "IP %s is not within the account network range %s", newIP.String(), account.Network.Net.String())
@@ -1037,7 +1037,7 @@ func TestAccountManager_AddPeer(t *testing.T) {
 
 	serial := account.Network.CurrentSerial() // should be 0
 
-	setupKey, err := manager.CreateSetupKey(context.Background(), account.Id, "test-key", types.SetupKeyReusable, time.Hour, nil, 999, userID, false, false)
+	setupKey, err := manager.CreateSetupKey(context.Background(), account.Id, "test-key", types.SetupKeyReusable, time.Hour, nil, 999, userID, false, false, types.SetupKeyConstraints{})
 	if err != nil {
 		t.Fatal("error creating setup key")
 		return
@@ -1478,7 +1478,7 @@ func TestAccountManager_DeletePeer(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	setupKey, err := manager.CreateSetupKey(context.Background(), account.Id, "test-key", types.SetupKeyReusable, time.Hour, nil, 999, userID, false, false)
+	setupKey, err := manager.CreateSetupKey(context.Background(), account.Id, "test-key", types.SetupKeyReusable, time.Hour, nil, 999, userID, false, false, types.SetupKeyConstraints{})
 	if err != nil {
 		t.Fatal("error creating setup key")
 		return
@@ -2059,6 +2059,70 @@ func TestDefaultAccountManager_UpdateAccountSettings(t *testing.T) {
 	require.Error(t, err, "expecting to fail when providing PeerLoginExpiration more than 180 days")
 }
 
+func TestDefaultAccountManager_UpdateAccountSettings_NetworkRangeV6(t *testing.T) {
+	manager, _, err := createManager(t)
+	require.NoError(t, err, "unable to create account manager")
+
+	accountID, err := manager.GetAccountIDByUserID(context.Background(), auth.UserAuth{UserId: userID})
+	require.NoError(t, err, "unable to create an account")
+
+	key, err := wgtypes.GenerateKey()
+	require.NoError(t, err, "unable to generate WireGuard key")
+	peer, _, _, err := manager.AddPeer(context.Background(), "", "", userID, &nbpeer.Peer{
+		Key:  key.PublicKey().String(),
+		Meta: nbpeer.PeerSystemMeta{Hostname: "test-peer"},
+	}, false)
+	require.NoError(t, err, "unable to add peer")
+	assert.Nil(t, peer.IPv6, "no IPv6 address expected without an IPv6 network range")
+
+	_, err = manager.UpdateAccountSettings(context.Background(), accountID, userID, &types.Settings{
+		PeerLoginExpiration: time.Hour,
+		NetworkRangeV6:      netip.MustParsePrefix("2001:db8::/64"),
+		Extra:               &types.ExtraSettings{},
+	})
+	require.Error(t, err, "expecting to fail when providing a non unique local IPv6 range")
+
+	networkRangeV6 := netip.MustParsePrefix("fd00:4e42:1::/64")
+	_, err = manager.UpdateAccountSettings(context.Background(), accountID, userID, &types.Settings{
+		PeerLoginExpiration: time.Hour,
+		NetworkRangeV6:      networkRangeV6,
+		Extra:               &types.ExtraSettings{},
+	})
+	require.NoError(t, err, "expecting to update account settings successfully but got error")
+
+	peer, err = manager.Store.GetPeerByID(context.Background(), store.LockingStrengthNone, accountID, peer.ID)
+	require.NoError(t, err)
+	ipv6, ok := netip.AddrFromSlice(peer.IPv6)
+	require.True(t, ok, "existing peer should get an IPv6 address")
+	assert.True(t, networkRangeV6.Contains(ipv6))
+
+	key2, err := wgtypes.GenerateKey()
+	require.NoError(t, err, "unable to generate WireGuard key")
+	peer2, _, _, err := manager.AddPeer(context.Background(), "", "", userID, &nbpeer.Peer{
+		Key:  key2.PublicKey().String(),
+		Meta: nbpeer.PeerSystemMeta{Hostname: "test-peer-2"},
+	}, false)
+	require.NoError(t, err, "unable to add peer")
+	ipv6, ok = netip.AddrFromSlice(peer2.IPv6)
+	require.True(t, ok, "new peer should get an IPv6 address")
+	assert.True(t, networkRangeV6.Contains(ipv6))
+	assert.NotEqual(t, peer.IPv6.String(), peer2.IPv6.String(), "new peer must not reuse a taken IPv6 address")
+
+	takenIPv6s, err := manager.Store.GetTakenIPv6s(context.Background(), store.LockingStrengthNone, accountID)
+	require.NoError(t, err)
+	assert.Len(t, takenIPv6s, 2)
+
+	_, err = manager.UpdateAccountSettings(context.Background(), accountID, userID, &types.Settings{
+		PeerLoginExpiration: time.Hour,
+		Extra:               &types.ExtraSettings{},
+	})
+	require.NoError(t, err, "expecting to update account settings successfully but got error")
+
+	peer, err = manager.Store.GetPeerByID(context.Background(), store.LockingStrengthNone, accountID, peer.ID)
+	require.NoError(t, err)
+	assert.Empty(t, peer.IPv6, "IPv6 address should be removed when the IPv6 network range is unset")
+}
+
 func TestDefaultAccountManager_UpdateAccountSettings_PeerApproval(t *testing.T) {
 	manager, _, account, peer1, peer2, peer3 := setupNetworkMapTest(t)
 
@@ -3076,7 +3140,7 @@ func setupNetworkMapTest(t *testing.T) (*DefaultAccountManager, *update_channel.
 		t.Fatal(err)
 	}
 
-	setupKey, err := manager.CreateSetupKey(context.Background(), account.Id, "test-key", types.SetupKeyReusable, time.Hour, nil, 999, userID, false, false)
+	setupKey, err := manager.CreateSetupKey(context.Background(), account.Id, "test-key", types.SetupKeyReusable, time.Hour, nil, 999, userID, false, false, types.SetupKeyConstraints{})
 	if err != nil {
 		t.Fatal("error creating setup key")
 	}
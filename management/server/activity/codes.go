@@ -195,6 +195,53 @@ const (
 	DNSRecordUpdated Activity = 100
 	DNSRecordDeleted Activity = 101
 
+	CustomRoleCreated Activity = 102
+	CustomRoleUpdated Activity = 103
+	CustomRoleDeleted Activity = 104
+
+	// PolicyExpired indicates that a scheduled policy reached its expiry time
+	PolicyExpired Activity = 105
+
+	// AccessRequestCreated indicates that a user requested temporary access to a group or policy
+	AccessRequestCreated Activity = 106
+	// AccessRequestApproved indicates that a user approved an access request
+	AccessRequestApproved Activity = 107
+	// AccessRequestDenied indicates that a user denied an access request
+	AccessRequestDenied Activity = 108
+	// AccessRequestExpired indicates that the access granted by an access request expired
+	AccessRequestExpired Activity = 109
+
+	// AccountNetworkRangeV6Updated indicates that a user changed the account IPv6 network range
+	AccountNetworkRangeV6Updated Activity = 110
+
+	// SCIMTokenCreated indicates that a user created or rotated the SCIM provisioning token
+	SCIMTokenCreated Activity = 111
+	// SCIMTokenDeleted indicates that a user deleted the SCIM provisioning token
+	SCIMTokenDeleted Activity = 112
+
+	// SetupKeyPeerRejected indicates that a peer registration with a setup key didn't meet the key constraints
+	SetupKeyPeerRejected Activity = 113
+
+	// WorkloadIdentityProviderCreated indicates that a user created a workload identity provider
+	WorkloadIdentityProviderCreated Activity = 114
+	// WorkloadIdentityProviderUpdated indicates that a user updated a workload identity provider
+	WorkloadIdentityProviderUpdated Activity = 115
+	// WorkloadIdentityProviderDeleted indicates that a user deleted a workload identity provider
+	WorkloadIdentityProviderDeleted Activity = 116
+	// PeerAddedWithWorkloadIdentity indicates that a new peer joined with a workload identity token
+	PeerAddedWithWorkloadIdentity Activity = 117
+
+	// PeerRejected indicates that a user rejected a peer pending approval
+	PeerRejected Activity = 118
+	// PeerAutoApproved indicates that a new peer was approved by a peer approval rule
+	PeerAutoApproved Activity = 119
+	// PeerApprovalRuleCreated indicates that a user created a peer approval rule
+	PeerApprovalRuleCreated Activity = 120
+	// PeerApprovalRuleUpdated indicates that a user updated a peer approval rule
+	PeerApprovalRuleUpdated Activity = 121
+	// PeerApprovalRuleDeleted indicates that a user deleted a peer approval rule
+	PeerApprovalRuleDeleted Activity = 122
+
 	AccountDeleted Activity = 99999
 )
 
@@ -319,6 +366,35 @@ var activityMap = map[Activity]Code{
 	DNSRecordCreated: {"DNS zone record created", "dns.zone.record.create"},
 	DNSRecordUpdated: {"DNS zone record updated", "dns.zone.record.update"},
 	DNSRecordDeleted: {"DNS zone record deleted", "dns.zone.record.delete"},
+
+	CustomRoleCreated: {"Custom role created", "role.custom.create"},
+	CustomRoleUpdated: {"Custom role updated", "role.custom.update"},
+	CustomRoleDeleted: {"Custom role deleted", "role.custom.delete"},
+
+	PolicyExpired: {"Policy expired", "policy.expire"},
+
+	AccessRequestCreated:  {"Access request created", "access.request.create"},
+	AccessRequestApproved: {"Access request approved", "access.request.approve"},
+	AccessRequestDenied:   {"Access request denied", "access.request.deny"},
+	AccessRequestExpired:  {"Access request expired", "access.request.expire"},
+
+	AccountNetworkRangeV6Updated: {"Account IPv6 network range updated", "account.network.range.v6.update"},
+
+	SCIMTokenCreated: {"SCIM token created", "scim.token.create"},
+	SCIMTokenDeleted: {"SCIM token deleted", "scim.token.delete"},
+
+	SetupKeyPeerRejected: {"Peer registration with setup key rejected", "setupkey.peer.reject"},
+
+	WorkloadIdentityProviderCreated: {"Workload identity provider created", "workload.identity.provider.create"},
+	WorkloadIdentityProviderUpdated: {"Workload identity provider updated", "workload.identity.provider.update"},
+	WorkloadIdentityProviderDeleted: {"Workload identity provider deleted", "workload.identity.provider.delete"},
+	PeerAddedWithWorkloadIdentity:   {"Peer added with workload identity", "peer.workload.identity.add"},
+
+	PeerRejected:            {"Peer rejected", "peer.reject"},
+	PeerAutoApproved:        {"Peer approved by rule", "peer.auto.approve"},
+	PeerApprovalRuleCreated: {"Peer approval rule created", "peer.approval.rule.create"},
+	PeerApprovalRuleUpdated: {"Peer approval rule updated", "peer.approval.rule.update"},
+	PeerApprovalRuleDeleted: {"Peer approval rule deleted", "peer.approval.rule.delete"},
 }
 
 // StringCode returns a string code of the activity
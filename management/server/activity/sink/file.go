@@ -0,0 +1,46 @@
+package sink
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/netbirdio/netbird/management/server/activity"
+)
+
+// FileSink appends each event as a line of JSON to a file
+type FileSink struct {
+	file *os.File
+}
+
+// NewFileSink opens the file of the sink, creating it if it does not exist
+func NewFileSink(config *FileConfig) (*FileSink, error) {
+	if config.Path == "" {
+		return nil, fmt.Errorf("missing file path")
+	}
+
+	file, err := os.OpenFile(config.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open file: %w", err)
+	}
+
+	return &FileSink{file: file}, nil
+}
+
+// Send appends the event to the file
+func (f *FileSink) Send(_ context.Context, event *activity.Event) error {
+	data, err := marshalEvent(event)
+	if err != nil {
+		return fmt.Errorf("marshal event: %w", err)
+	}
+
+	if _, err = f.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("write event: %w", err)
+	}
+	return nil
+}
+
+// Close closes the file
+func (f *FileSink) Close() error {
+	return f.file.Close()
+}
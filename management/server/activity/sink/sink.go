@@ -0,0 +1,134 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/netbirdio/netbird/management/server/activity"
+	"github.com/netbirdio/netbird/util"
+)
+
+const (
+	// TypeWebhook posts each event as JSON to an HTTP endpoint
+	TypeWebhook = "webhook"
+	// TypeSyslog sends each event as a RFC5424 message to a syslog server over TCP or TLS
+	TypeSyslog = "syslog"
+	// TypeFile appends each event as a line of JSON to a file
+	TypeFile = "file"
+
+	defaultQueueSize = 1000
+)
+
+// Sink receives the activity events after they are stored
+type Sink interface {
+	// Send delivers a single event, it is never called concurrently
+	Send(ctx context.Context, event *activity.Event) error
+	// Close releases the resources of the sink
+	Close() error
+}
+
+// Config of an event sink
+type Config struct {
+	// Name identifies the sink in logs, defaults to the type
+	Name string
+	// Type of the sink: webhook, syslog or file
+	Type string
+	// QueueSize is the number of events buffered for the sink. Events are dropped when the queue is full.
+	QueueSize int
+	// AccountIDs limits the sink to the events of these accounts, all accounts when empty
+	AccountIDs []string
+
+	Webhook *WebhookConfig
+	Syslog  *SyslogConfig
+	File    *FileConfig
+}
+
+// WebhookConfig of a webhook sink
+type WebhookConfig struct {
+	// URL the events are posted to
+	URL string
+	// Secret used to sign the requests with HMAC-SHA256, the requests are not signed when empty
+	Secret string
+	// Headers added to every request, e.g. an authorization header
+	Headers map[string]string
+	// Timeout of a single request, defaults to 10 seconds
+	Timeout util.Duration
+	// MaxRetries is the number of times a failed request is retried, defaults to 5
+	MaxRetries int
+}
+
+// SyslogConfig of a syslog sink
+type SyslogConfig struct {
+	// Address of the syslog server, host:port
+	Address string
+	// TLS enables TLS on the connection
+	TLS bool
+	// CAFile is the PEM file with the CA certificates used to verify the server, the system pool when empty
+	CAFile string
+	// InsecureSkipVerify disables the verification of the server certificate
+	InsecureSkipVerify bool
+	// Facility of the messages, defaults to local0
+	Facility string
+	// AppName of the messages, defaults to netbird
+	AppName string
+	// Hostname of the messages, defaults to the hostname of the management server
+	Hostname string
+}
+
+// FileConfig of a file sink
+type FileConfig struct {
+	// Path of the file the events are appended to
+	Path string
+}
+
+// New creates the sink described by the config
+func New(config *Config) (Sink, error) {
+	switch config.Type {
+	case TypeWebhook:
+		if config.Webhook == nil {
+			return nil, fmt.Errorf("missing webhook configuration")
+		}
+		return NewWebhookSink(config.Webhook)
+	case TypeSyslog:
+		if config.Syslog == nil {
+			return nil, fmt.Errorf("missing syslog configuration")
+		}
+		return NewSyslogSink(config.Syslog)
+	case TypeFile:
+		if config.File == nil {
+			return nil, fmt.Errorf("missing file configuration")
+		}
+		return NewFileSink(config.File)
+	default:
+		return nil, fmt.Errorf("unsupported event sink type %q", config.Type)
+	}
+}
+
+// eventMessage is the JSON representation of an event sent to the sinks
+type eventMessage struct {
+	ID           uint64         `json:"id"`
+	Timestamp    time.Time      `json:"timestamp"`
+	Activity     string         `json:"activity"`
+	ActivityCode int            `json:"activity_code"`
+	Message      string         `json:"message"`
+	InitiatorID  string         `json:"initiator_id"`
+	TargetID     string         `json:"target_id"`
+	AccountID    string         `json:"account_id"`
+	Meta         map[string]any `json:"meta,omitempty"`
+}
+
+func marshalEvent(event *activity.Event) ([]byte, error) {
+	return json.Marshal(eventMessage{
+		ID:           event.ID,
+		Timestamp:    event.Timestamp.UTC(),
+		Activity:     event.Activity.StringCode(),
+		ActivityCode: int(event.Activity),
+		Message:      event.Activity.Message(),
+		InitiatorID:  event.InitiatorID,
+		TargetID:     event.TargetID,
+		AccountID:    event.AccountID,
+		Meta:         event.Meta,
+	})
+}
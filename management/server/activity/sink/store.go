@@ -0,0 +1,162 @@
+package sink
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/management/server/activity"
+)
+
+// Store wraps an activity.Store and streams every saved event to the configured sinks.
+// Each sink has its own bounded queue and worker, so a slow or unavailable sink never blocks saving events.
+type Store struct {
+	activity.Store
+
+	queues []*queue
+	// mu guards closed, events are not queued once the queues are closed
+	mu     sync.RWMutex
+	closed bool
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+type queue struct {
+	name       string
+	sink       Sink
+	accountIDs []string
+	events     chan *activity.Event
+	dropped    atomic.Uint64
+}
+
+// NewStore creates the sinks described by the configs and starts streaming the events saved in the store to them
+func NewStore(store activity.Store, configs []*Config) (*Store, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Store{
+		Store:  store,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+
+	for _, config := range configs {
+		sink, err := New(config)
+		if err != nil {
+			s.closeSinks()
+			cancel()
+			return nil, fmt.Errorf("create event sink %s: %w", sinkName(config), err)
+		}
+
+		queueSize := config.QueueSize
+		if queueSize <= 0 {
+			queueSize = defaultQueueSize
+		}
+
+		s.queues = append(s.queues, &queue{
+			name:       sinkName(config),
+			sink:       sink,
+			accountIDs: config.AccountIDs,
+			events:     make(chan *activity.Event, queueSize),
+		})
+	}
+
+	for _, q := range s.queues {
+		s.wg.Add(1)
+		go s.run(q)
+	}
+
+	return s, nil
+}
+
+// Save stores the event and queues the stored event for every sink
+func (s *Store) Save(ctx context.Context, event *activity.Event) (*activity.Event, error) {
+	saved, err := s.Store.Save(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return saved, nil
+	}
+
+	for _, q := range s.queues {
+		if len(q.accountIDs) > 0 && !slices.Contains(q.accountIDs, saved.AccountID) {
+			continue
+		}
+
+		select {
+		case q.events <- saved:
+		default:
+			if dropped := q.dropped.Add(1); dropped == 1 || dropped%1000 == 0 {
+				log.WithContext(ctx).Warnf("event sink %s queue is full, %d events dropped so far", q.name, dropped)
+			}
+		}
+	}
+
+	return saved, nil
+}
+
+// Close stops the sinks, sending the queued events until the context is done, and closes the wrapped store
+func (s *Store) Close(ctx context.Context) error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		for _, q := range s.queues {
+			close(q.events)
+		}
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		log.WithContext(ctx).Warnf("timed out sending the queued events to the event sinks")
+		s.cancel()
+		<-done
+	}
+	s.cancel()
+
+	s.closeSinks()
+
+	return s.Store.Close(ctx)
+}
+
+func (s *Store) run(q *queue) {
+	defer s.wg.Done()
+
+	for event := range q.events {
+		if s.ctx.Err() != nil {
+			continue
+		}
+		if err := q.sink.Send(s.ctx, event); err != nil {
+			log.Errorf("failed to send activity event %d to event sink %s: %v", event.ID, q.name, err)
+		}
+	}
+}
+
+func (s *Store) closeSinks() {
+	for _, q := range s.queues {
+		if err := q.sink.Close(); err != nil {
+			log.Errorf("failed to close event sink %s: %v", q.name, err)
+		}
+	}
+}
+
+func sinkName(config *Config) string {
+	if config.Name != "" {
+		return config.Name
+	}
+	return config.Type
+}
@@ -0,0 +1,150 @@
+package sink
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/management/server/activity"
+)
+
+type blockingSink struct {
+	release chan struct{}
+	sent    chan *activity.Event
+}
+
+func (b *blockingSink) Send(ctx context.Context, event *activity.Event) error {
+	select {
+	case <-b.release:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	b.sent <- event
+	return nil
+}
+
+func (b *blockingSink) Close() error {
+	return nil
+}
+
+func TestStore_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.log")
+
+	store, err := NewStore(&activity.InMemoryEventStore{}, []*Config{{Type: TypeFile, File: &FileConfig{Path: path}}})
+	require.NoError(t, err)
+
+	timestamp := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	for _, accountID := range []string{"account-1", "account-2"} {
+		_, err = store.Save(context.Background(), &activity.Event{
+			Timestamp:   timestamp,
+			Activity:    activity.PeerAddedByUser,
+			InitiatorID: "user-id",
+			TargetID:    "peer-id",
+			AccountID:   accountID,
+			Meta:        map[string]any{"name": "laptop"},
+		})
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, store.Close(context.Background()))
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var messages []eventMessage
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var message eventMessage
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &message))
+		messages = append(messages, message)
+	}
+	require.NoError(t, scanner.Err())
+
+	require.Len(t, messages, 2)
+	assert.Equal(t, eventMessage{
+		ID:           0,
+		Timestamp:    timestamp,
+		Activity:     activity.PeerAddedByUser.StringCode(),
+		ActivityCode: int(activity.PeerAddedByUser),
+		Message:      activity.PeerAddedByUser.Message(),
+		InitiatorID:  "user-id",
+		TargetID:     "peer-id",
+		AccountID:    "account-1",
+		Meta:         map[string]any{"name": "laptop"},
+	}, messages[0])
+	assert.Equal(t, uint64(1), messages[1].ID)
+	assert.Equal(t, "account-2", messages[1].AccountID)
+}
+
+func TestStore_FullQueueDoesNotBlock(t *testing.T) {
+	blocking := &blockingSink{release: make(chan struct{}), sent: make(chan *activity.Event, 10)}
+	store := &Store{Store: &activity.InMemoryEventStore{}}
+	store.ctx, store.cancel = context.WithCancel(context.Background())
+	store.queues = []*queue{{name: "blocking", sink: blocking, events: make(chan *activity.Event, 1)}}
+	store.wg.Add(1)
+	go store.run(store.queues[0])
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 5; i++ {
+			_, err := store.Save(context.Background(), &activity.Event{AccountID: "account-1"})
+			assert.NoError(t, err)
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("saving events blocked on a slow sink")
+	}
+
+	events, err := store.Get(context.Background(), "account-1", 0, 10, false)
+	require.NoError(t, err)
+	assert.Len(t, events, 5, "all events are stored")
+	assert.NotZero(t, store.queues[0].dropped.Load())
+
+	close(blocking.release)
+	require.NoError(t, store.Close(context.Background()))
+	assert.LessOrEqual(t, len(blocking.sent), 2, "one event in flight and one queued")
+}
+
+func TestStore_AccountFilter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.log")
+
+	store, err := NewStore(&activity.InMemoryEventStore{}, []*Config{{
+		Type:       TypeFile,
+		AccountIDs: []string{"account-2"},
+		File:       &FileConfig{Path: path},
+	}})
+	require.NoError(t, err)
+
+	for _, accountID := range []string{"account-1", "account-2", "account-3"} {
+		_, err = store.Save(context.Background(), &activity.Event{Activity: activity.UserJoined, AccountID: accountID})
+		require.NoError(t, err)
+	}
+	require.NoError(t, store.Close(context.Background()))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var message eventMessage
+	require.NoError(t, json.Unmarshal(data, &message))
+	assert.Equal(t, "account-2", message.AccountID)
+}
+
+func TestNewStore_InvalidConfig(t *testing.T) {
+	_, err := NewStore(&activity.InMemoryEventStore{}, []*Config{{Type: TypeWebhook}})
+	assert.EqualError(t, err, "create event sink webhook: missing webhook configuration")
+
+	_, err = NewStore(&activity.InMemoryEventStore{}, []*Config{{Name: "siem", Type: "kafka"}})
+	assert.EqualError(t, err, `create event sink siem: unsupported event sink type "kafka"`)
+}
@@ -0,0 +1,199 @@
+package sink
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/netbirdio/netbird/management/server/activity"
+)
+
+const (
+	defaultSyslogAppName = "netbird"
+	syslogSeverityInfo   = 6
+	syslogDialTimeout    = 10 * time.Second
+	syslogWriteTimeout   = 10 * time.Second
+	// syslogTimestampFormat is RFC3339 limited to the microsecond precision allowed by RFC5424
+	syslogTimestampFormat = "2006-01-02T15:04:05.000000Z07:00"
+)
+
+var syslogFacilities = map[string]int{
+	"kern":     0,
+	"user":     1,
+	"daemon":   3,
+	"auth":     4,
+	"syslog":   5,
+	"authpriv": 10,
+	"local0":   16,
+	"local1":   17,
+	"local2":   18,
+	"local3":   19,
+	"local4":   20,
+	"local5":   21,
+	"local6":   22,
+	"local7":   23,
+}
+
+// SyslogSink sends each event as a RFC5424 message with the JSON event as the message body.
+// Messages are framed with octet counting (RFC6587) over TCP, optionally with TLS (RFC5425).
+type SyslogSink struct {
+	address   string
+	tlsConfig *tls.Config
+	facility  int
+	appName   string
+	hostname  string
+	procID    string
+
+	conn net.Conn
+}
+
+// NewSyslogSink creates a syslog sink, the connection is established when the first event is sent
+func NewSyslogSink(config *SyslogConfig) (*SyslogSink, error) {
+	if config.Address == "" {
+		return nil, fmt.Errorf("missing syslog address")
+	}
+
+	facilityName := config.Facility
+	if facilityName == "" {
+		facilityName = "local0"
+	}
+	facility, ok := syslogFacilities[strings.ToLower(facilityName)]
+	if !ok {
+		return nil, fmt.Errorf("unsupported syslog facility %q", config.Facility)
+	}
+
+	s := &SyslogSink{
+		address:  config.Address,
+		facility: facility,
+		appName:  config.AppName,
+		hostname: config.Hostname,
+		procID:   strconv.Itoa(os.Getpid()),
+	}
+
+	if s.appName == "" {
+		s.appName = defaultSyslogAppName
+	}
+	if s.hostname == "" {
+		s.hostname, _ = os.Hostname()
+	}
+	if s.hostname == "" {
+		s.hostname = "-"
+	}
+
+	if config.TLS {
+		s.tlsConfig = &tls.Config{
+			MinVersion:         tls.VersionTLS12,
+			InsecureSkipVerify: config.InsecureSkipVerify, //nolint:gosec
+		}
+		if config.CAFile != "" {
+			pem, err := os.ReadFile(config.CAFile)
+			if err != nil {
+				return nil, fmt.Errorf("read CA file: %w", err)
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no certificates found in CA file %s", config.CAFile)
+			}
+			s.tlsConfig.RootCAs = pool
+		}
+	}
+
+	return s, nil
+}
+
+// Send writes the event to the syslog server, reconnecting once if the connection was lost
+func (s *SyslogSink) Send(ctx context.Context, event *activity.Event) error {
+	message, err := s.format(event)
+	if err != nil {
+		return err
+	}
+	frame := []byte(strconv.Itoa(len(message)) + " " + message)
+
+	if err = s.write(ctx, frame); err == nil {
+		return nil
+	}
+
+	s.closeConn()
+	return s.write(ctx, frame)
+}
+
+// Close closes the connection to the syslog server
+func (s *SyslogSink) Close() error {
+	s.closeConn()
+	return nil
+}
+
+func (s *SyslogSink) write(ctx context.Context, frame []byte) error {
+	if s.conn == nil {
+		conn, err := s.dial(ctx)
+		if err != nil {
+			return fmt.Errorf("connect to syslog server: %w", err)
+		}
+		s.conn = conn
+	}
+
+	if err := s.conn.SetWriteDeadline(time.Now().Add(syslogWriteTimeout)); err != nil {
+		return err
+	}
+	_, err := s.conn.Write(frame)
+	return err
+}
+
+func (s *SyslogSink) dial(ctx context.Context) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: syslogDialTimeout}
+	if s.tlsConfig == nil {
+		return dialer.DialContext(ctx, "tcp", s.address)
+	}
+
+	tlsDialer := &tls.Dialer{NetDialer: dialer, Config: s.tlsConfig}
+	return tlsDialer.DialContext(ctx, "tcp", s.address)
+}
+
+func (s *SyslogSink) closeConn() {
+	if s.conn != nil {
+		_ = s.conn.Close()
+		s.conn = nil
+	}
+}
+
+// format returns the RFC5424 message of the event: <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID - MSG
+func (s *SyslogSink) format(event *activity.Event) (string, error) {
+	body, err := marshalEvent(event)
+	if err != nil {
+		return "", fmt.Errorf("marshal event: %w", err)
+	}
+
+	return fmt.Sprintf("<%d>1 %s %s %s %s %s - %s",
+		s.facility*8+syslogSeverityInfo,
+		event.Timestamp.UTC().Format(syslogTimestampFormat),
+		headerField(s.hostname, 255),
+		headerField(s.appName, 48),
+		s.procID,
+		headerField(event.Activity.StringCode(), 32),
+		body,
+	), nil
+}
+
+// headerField returns the value limited to the printable ASCII characters and the length allowed in a RFC5424 header field
+func headerField(value string, maxLen int) string {
+	field := strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 {
+			return -1
+		}
+		return r
+	}, value)
+
+	if len(field) > maxLen {
+		field = field[:maxLen]
+	}
+	if field == "" {
+		return "-"
+	}
+	return field
+}
@@ -0,0 +1,76 @@
+package sink
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/management/server/activity"
+)
+
+func TestSyslogSink_Send(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	received := make(chan string, 2)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		reader := bufio.NewReader(conn)
+		for {
+			length, err := reader.ReadString(' ')
+			if err != nil {
+				return
+			}
+			n, err := strconv.Atoi(strings.TrimSpace(length))
+			if err != nil {
+				return
+			}
+			message := make([]byte, n)
+			if _, err = io.ReadFull(reader, message); err != nil {
+				return
+			}
+			received <- string(message)
+		}
+	}()
+
+	sink, err := NewSyslogSink(&SyslogConfig{Address: listener.Addr().String(), Hostname: "management", Facility: "local3"})
+	require.NoError(t, err)
+	defer sink.Close()
+
+	timestamp := time.Date(2024, 5, 1, 10, 0, 0, 123456789, time.UTC)
+	event := &activity.Event{ID: 3, Timestamp: timestamp, Activity: activity.UserJoined, AccountID: "account-1"}
+	require.NoError(t, sink.Send(context.Background(), event))
+
+	select {
+	case message := <-received:
+		header := "<158>1 2024-05-01T10:00:00.123456Z management netbird " + sink.procID + " user.join - "
+		require.True(t, strings.HasPrefix(message, header), message)
+
+		var body eventMessage
+		require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(message, header)), &body))
+		assert.Equal(t, uint64(3), body.ID)
+		assert.Equal(t, "account-1", body.AccountID)
+	case <-time.After(5 * time.Second):
+		t.Fatal("syslog message not received")
+	}
+}
+
+func TestNewSyslogSink_InvalidFacility(t *testing.T) {
+	_, err := NewSyslogSink(&SyslogConfig{Address: "127.0.0.1:514", Facility: "mail2"})
+	assert.EqualError(t, err, `unsupported syslog facility "mail2"`)
+}
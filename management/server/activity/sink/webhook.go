@@ -0,0 +1,133 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+
+	"github.com/netbirdio/netbird/management/server/activity"
+)
+
+const (
+	// SignatureHeader carries the hex encoded HMAC-SHA256 of "<timestamp>.<body>" prefixed with "sha256="
+	SignatureHeader = "X-Netbird-Signature"
+	// TimestampHeader carries the unix time the request was signed at
+	TimestampHeader = "X-Netbird-Timestamp"
+	// EventIDHeader carries the ID of the event, it is the same for all the retries of an event
+	EventIDHeader = "X-Netbird-Event-Id"
+
+	defaultWebhookTimeout    = 10 * time.Second
+	defaultWebhookMaxRetries = 5
+)
+
+// WebhookSink posts each event as JSON to an HTTP endpoint, retrying with exponential backoff
+type WebhookSink struct {
+	url        string
+	secret     []byte
+	headers    map[string]string
+	maxRetries int
+	client     *http.Client
+}
+
+// NewWebhookSink creates a webhook sink
+func NewWebhookSink(config *WebhookConfig) (*WebhookSink, error) {
+	u, err := url.Parse(config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("parse webhook URL: %w", err)
+	}
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return nil, fmt.Errorf("unsupported webhook URL scheme %q", u.Scheme)
+	}
+
+	timeout := config.Timeout.Duration
+	if timeout <= 0 {
+		timeout = defaultWebhookTimeout
+	}
+
+	maxRetries := config.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = defaultWebhookMaxRetries
+	}
+
+	return &WebhookSink{
+		url:        config.URL,
+		secret:     []byte(config.Secret),
+		headers:    config.Headers,
+		maxRetries: maxRetries,
+		client:     &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Send posts the event, retrying on connection errors, 429 and 5xx responses
+func (w *WebhookSink) Send(ctx context.Context, event *activity.Event) error {
+	body, err := marshalEvent(event)
+	if err != nil {
+		return fmt.Errorf("marshal event: %w", err)
+	}
+
+	bo := backoff.WithContext(backoff.WithMaxRetries(backoff.NewExponentialBackOff(), uint64(w.maxRetries)), ctx)
+
+	return backoff.Retry(func() error {
+		return w.post(ctx, event, body)
+	}, bo)
+}
+
+func (w *WebhookSink) post(ctx context.Context, event *activity.Event, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return backoff.Permanent(err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventIDHeader, strconv.FormatUint(event.ID, 10))
+	for key, value := range w.headers {
+		req.Header.Set(key, value)
+	}
+
+	if len(w.secret) > 0 {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(TimestampHeader, timestamp)
+		req.Header.Set(SignatureHeader, "sha256="+Sign(w.secret, timestamp, body))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	default:
+		return backoff.Permanent(fmt.Errorf("webhook responded with status %d", resp.StatusCode))
+	}
+}
+
+// Close closes the idle connections of the sink
+func (w *WebhookSink) Close() error {
+	w.client.CloseIdleConnections()
+	return nil
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of "<timestamp>.<body>", receivers recompute it to verify a request
+func Sign(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,72 @@
+package sink
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/management/server/activity"
+)
+
+func TestWebhookSink_Send(t *testing.T) {
+	secret := "secret"
+	var requests atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		assert.Equal(t, "7", r.Header.Get(EventIDHeader))
+		assert.Equal(t, "sha256="+Sign([]byte(secret), r.Header.Get(TimestampHeader), body), r.Header.Get(SignatureHeader))
+
+		if requests.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	sink, err := NewWebhookSink(&WebhookConfig{
+		URL:     server.URL,
+		Secret:  secret,
+		Headers: map[string]string{"Authorization": "Bearer token"},
+	})
+	require.NoError(t, err)
+	defer sink.Close()
+
+	err = sink.Send(context.Background(), &activity.Event{ID: 7, Activity: activity.UserJoined, AccountID: "account-1"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), requests.Load(), "unavailable responses are retried")
+}
+
+func TestWebhookSink_SendPermanentError(t *testing.T) {
+	var requests atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	sink, err := NewWebhookSink(&WebhookConfig{URL: server.URL})
+	require.NoError(t, err)
+	defer sink.Close()
+
+	err = sink.Send(context.Background(), &activity.Event{Activity: activity.UserJoined})
+	assert.EqualError(t, err, "webhook responded with status 401")
+	assert.Equal(t, int32(1), requests.Load(), "client errors are not retried")
+}
+
+func TestNewWebhookSink_InvalidURL(t *testing.T) {
+	_, err := NewWebhookSink(&WebhookConfig{URL: "ftp://example.com"})
+	assert.EqualError(t, err, `unsupported webhook URL scheme "ftp"`)
+}
@@ -9,6 +9,7 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/netbirdio/netbird/management/server/activity"
+	nbcontext "github.com/netbirdio/netbird/management/server/context"
 	"github.com/netbirdio/netbird/management/server/permissions/modules"
 	"github.com/netbirdio/netbird/management/server/permissions/operations"
 	"github.com/netbirdio/netbird/management/server/store"
@@ -63,6 +64,7 @@ func (am *DefaultAccountManager) GetEvents(ctx context.Context, accountID, userI
 
 func (am *DefaultAccountManager) StoreEvent(ctx context.Context, initiatorID, targetID, accountID string, activityID activity.ActivityDescriber, meta map[string]any) {
 	if isEnabled() {
+		meta = withTokenMeta(ctx, initiatorID, meta)
 		go func() {
 			_, err := am.eventStore.Save(ctx, &activity.Event{
 				Timestamp:   time.Now().UTC(),
@@ -80,6 +82,22 @@ func (am *DefaultAccountManager) StoreEvent(ctx context.Context, initiatorID, ta
 	}
 }
 
+// withTokenMeta adds the personal access token the initiator has authenticated with to the event meta
+func withTokenMeta(ctx context.Context, initiatorID string, meta map[string]any) map[string]any {
+	userAuth, err := nbcontext.GetUserAuthFromContext(ctx)
+	if err != nil || !userAuth.IsPAT || userAuth.PATID == "" || userAuth.UserId != initiatorID {
+		return meta
+	}
+
+	tokenMeta := make(map[string]any, len(meta)+2)
+	for k, v := range meta {
+		tokenMeta[k] = v
+	}
+	tokenMeta["token_id"] = userAuth.PATID
+	tokenMeta["token_name"] = userAuth.PATName
+	return tokenMeta
+}
+
 type eventUserInfo struct {
 	email     string
 	name      string
@@ -8,6 +8,8 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/netbirdio/netbird/management/server/activity"
+	nbcontext "github.com/netbirdio/netbird/management/server/context"
+	"github.com/netbirdio/netbird/shared/auth"
 )
 
 func generateAndStoreEvents(t *testing.T, manager *DefaultAccountManager, typ activity.Activity, initiatorID, targetID,
@@ -65,3 +67,21 @@ func TestDefaultAccountManager_GetEvents(t *testing.T) {
 		_ = manager.eventStore.Close(context.Background()) //nolint
 	})
 }
+
+func TestWithTokenMeta(t *testing.T) {
+	meta := map[string]any{"name": "key"}
+
+	assert.Equal(t, meta, withTokenMeta(context.Background(), "user", meta), "requests without user auth are not changed")
+
+	jwtCtx := nbcontext.SetUserAuthInContext(context.Background(), auth.UserAuth{UserId: "user"})
+	assert.Equal(t, meta, withTokenMeta(jwtCtx, "user", meta))
+
+	patCtx := nbcontext.SetUserAuthInContext(context.Background(), auth.UserAuth{UserId: "user", IsPAT: true, PATID: "token-id", PATName: "ci"})
+	assert.Equal(t, meta, withTokenMeta(patCtx, "other", meta), "events initiated by other users are not changed")
+
+	got := withTokenMeta(patCtx, "user", meta)
+	assert.Equal(t, map[string]any{"name": "key", "token_id": "token-id", "token_name": "ci"}, got)
+	assert.Equal(t, map[string]any{"name": "key"}, meta, "the meta of the caller is not modified")
+
+	assert.Equal(t, map[string]any{"token_id": "token-id", "token_name": "ci"}, withTokenMeta(patCtx, "user", nil))
+}
@@ -15,6 +15,12 @@ import (
 
 	"github.com/netbirdio/management-integrations/integrations"
 	"github.com/netbirdio/netbird/management/internals/controllers/network_map"
+	"github.com/netbirdio/netbird/management/internals/modules/accessrequests"
+	accessRequestsManager "github.com/netbirdio/netbird/management/internals/modules/accessrequests/manager"
+	"github.com/netbirdio/netbird/management/internals/modules/accountconfig"
+	accountConfigManager "github.com/netbirdio/netbird/management/internals/modules/accountconfig/manager"
+	"github.com/netbirdio/netbird/management/internals/modules/customroles"
+	customRolesManager "github.com/netbirdio/netbird/management/internals/modules/customroles/manager"
 	"github.com/netbirdio/netbird/management/internals/modules/zones"
 	zonesManager "github.com/netbirdio/netbird/management/internals/modules/zones/manager"
 	"github.com/netbirdio/netbird/management/internals/modules/zones/records"
@@ -25,7 +31,13 @@ import (
 	"github.com/netbirdio/netbird/management/server/integrations/port_forwarding"
 	"github.com/netbirdio/netbird/management/server/permissions"
 
+	"github.com/netbirdio/netbird/management/internals/modules/peerapproval"
+	peerApprovalManager "github.com/netbirdio/netbird/management/internals/modules/peerapproval/manager"
 	nbpeers "github.com/netbirdio/netbird/management/internals/modules/peers"
+	"github.com/netbirdio/netbird/management/internals/modules/scim"
+	scimManager "github.com/netbirdio/netbird/management/internals/modules/scim/manager"
+	"github.com/netbirdio/netbird/management/internals/modules/workloadidentity"
+	workloadIdentityManager "github.com/netbirdio/netbird/management/internals/modules/workloadidentity/manager"
 	"github.com/netbirdio/netbird/management/server/auth"
 	"github.com/netbirdio/netbird/management/server/geolocation"
 	nbgroups "github.com/netbirdio/netbird/management/server/groups"
@@ -59,7 +71,7 @@ const (
 )
 
 // NewAPIHandler creates the Management service HTTP API handler registering all the available endpoints.
-func NewAPIHandler(ctx context.Context, accountManager account.Manager, networksManager nbnetworks.Manager, resourceManager resources.Manager, routerManager routers.Manager, groupsManager nbgroups.Manager, LocationManager geolocation.Geolocation, authManager auth.Manager, appMetrics telemetry.AppMetrics, integratedValidator integrated_validator.IntegratedValidator, proxyController port_forwarding.Controller, permissionsManager permissions.Manager, peersManager nbpeers.Manager, settingsManager settings.Manager, zManager zones.Manager, rManager records.Manager, networkMapController network_map.Controller, idpManager idpmanager.Manager) (http.Handler, error) {
+func NewAPIHandler(ctx context.Context, accountManager account.Manager, networksManager nbnetworks.Manager, resourceManager resources.Manager, routerManager routers.Manager, groupsManager nbgroups.Manager, LocationManager geolocation.Geolocation, authManager auth.Manager, appMetrics telemetry.AppMetrics, integratedValidator integrated_validator.IntegratedValidator, proxyController port_forwarding.Controller, permissionsManager permissions.Manager, peersManager nbpeers.Manager, settingsManager settings.Manager, zManager zones.Manager, rManager records.Manager, crManager customroles.Manager, arManager accessrequests.Manager, acManager accountconfig.Manager, sManager scim.Manager, wiManager workloadidentity.Manager, paManager peerapproval.Manager, networkMapController network_map.Controller, idpManager idpmanager.Manager) (http.Handler, error) {
 
 	// Register bypass paths for unauthenticated endpoints
 	if err := bypass.AddBypassPath("/api/instance"); err != nil {
@@ -143,9 +155,20 @@ func NewAPIHandler(ctx context.Context, accountManager account.Manager, networks
 	networks.AddEndpoints(networksManager, resourceManager, routerManager, groupsManager, accountManager, router)
 	zonesManager.RegisterEndpoints(router, zManager)
 	recordsManager.RegisterEndpoints(router, rManager)
+	customRolesManager.RegisterEndpoints(router, crManager)
+	accessRequestsManager.RegisterEndpoints(router, arManager)
+	accountConfigManager.RegisterEndpoints(router, acManager)
+	scimManager.RegisterEndpoints(router, sManager)
+	workloadIdentityManager.RegisterEndpoints(router, wiManager)
+	peerApprovalManager.RegisterEndpoints(router, paManager)
 	idp.AddEndpoints(accountManager, router)
 	instance.AddEndpoints(instanceManager, router)
 
+	// Mount the SCIM provisioning endpoint, it is authenticated with the SCIM token of the account
+	scimRouter := rootRouter.PathPrefix(scim.PathPrefix).Subrouter()
+	scimRouter.Use(metricsMiddleware.Handler, corsMiddleware.Handler)
+	scimManager.RegisterProvisioningEndpoints(scimRouter, sManager)
+
 	// Mount embedded IdP handler at /oauth2 path if configured
 	if embeddedIdpEnabled {
 		rootRouter.PathPrefix("/oauth2").Handler(corsMiddleware.Handler(embeddedIdP.Handler()))
@@ -100,6 +100,33 @@ func (h *handler) validateNetworkRange(ctx context.Context, accountID, userID st
 	return h.validateCapacity(ctx, accountID, userID, networkRange)
 }
 
+// setNetworkRangeV6 applies the requested IPv6 network range to settings. An omitted value keeps the current
+// range so that clients unaware of IPv6 overlay addressing don't disable it, an empty value disables it.
+func (h *handler) setNetworkRangeV6(ctx context.Context, accountID, userID string, networkRangeV6 *string, settings *types.Settings) error {
+	if networkRangeV6 == nil {
+		current, err := h.settingsManager.GetSettings(ctx, accountID, userID)
+		if err != nil {
+			return err
+		}
+		settings.NetworkRangeV6 = current.NetworkRangeV6
+		return nil
+	}
+
+	if *networkRangeV6 == "" {
+		return nil
+	}
+
+	prefix, err := netip.ParsePrefix(*networkRangeV6)
+	if err != nil {
+		return status.Errorf(status.InvalidArgument, "invalid IPv6 CIDR format: %v", err)
+	}
+	if err := types.ValidateNetworkRangeV6(prefix); err != nil {
+		return err
+	}
+	settings.NetworkRangeV6 = prefix.Masked()
+	return nil
+}
+
 func (h *handler) validateCapacity(ctx context.Context, accountID, userID string, prefix netip.Prefix) error {
 	peers, err := h.accountManager.GetPeers(ctx, accountID, userID, "", "")
 	if err != nil {
@@ -265,6 +292,10 @@ func (h *handler) updateAccount(w http.ResponseWriter, r *http.Request) {
 		}
 		settings.NetworkRange = prefix
 	}
+	if err := h.setNetworkRangeV6(r.Context(), accountID, userID, req.Settings.NetworkRangeV6, settings); err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
 
 	var onboarding *types.AccountOnboarding
 	if req.Onboarding != nil {
@@ -348,6 +379,10 @@ func toAccountResponse(accountID string, settings *types.Settings, meta *types.A
 		networkRangeStr := settings.NetworkRange.String()
 		apiSettings.NetworkRange = &networkRangeStr
 	}
+	if settings.NetworkRangeV6.IsValid() {
+		networkRangeV6Str := settings.NetworkRangeV6.String()
+		apiSettings.NetworkRangeV6 = &networkRangeV6Str
+	}
 
 	apiOnboarding := api.AccountOnboarding{
 		OnboardingFlowPending: onboarding.OnboardingFlowPending,
@@ -35,6 +35,7 @@ func AddEndpoints(accountManager account.Manager, router *mux.Router, networkMap
 	router.HandleFunc("/peers/{peerId}", peersHandler.HandlePeer).
 		Methods("GET", "PUT", "DELETE", "OPTIONS")
 	router.HandleFunc("/peers/{peerId}/accessible-peers", peersHandler.GetAccessiblePeers).Methods("GET", "OPTIONS")
+	router.HandleFunc("/peers/{peerId}/posture-checks", peersHandler.GetPeerPostureCheckResults).Methods("GET", "OPTIONS")
 	router.HandleFunc("/peers/{peerId}/temporary-access", peersHandler.CreateTemporaryAccess).Methods("POST", "OPTIONS")
 }
 
@@ -304,6 +305,41 @@ func (h *Handler) GetAccessiblePeers(w http.ResponseWriter, r *http.Request) {
 	util.WriteJSONObject(r.Context(), w, toAccessiblePeers(netMap, dnsDomain))
 }
 
+// GetPeerPostureCheckResults returns the results of the posture checks evaluated on the peer
+func (h *Handler) GetPeerPostureCheckResults(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	peerID := mux.Vars(r)["peerId"]
+	if len(peerID) == 0 {
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "invalid peer ID"), w)
+		return
+	}
+
+	results, err := h.accountManager.GetPeerPostureCheckResults(r.Context(), userAuth.AccountId, peerID, userAuth.UserId)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	resp := make([]api.PeerPostureCheckResult, 0, len(results))
+	for _, result := range results {
+		resp = append(resp, api.PeerPostureCheckResult{
+			PostureCheckId:   result.PostureChecksID,
+			PostureCheckName: result.PostureChecksName,
+			Check:            result.Check,
+			Passed:           result.Passed,
+			Reason:           result.Reason,
+			EvaluatedAt:      result.EvaluatedAt,
+		})
+	}
+
+	util.WriteJSONObject(r.Context(), w, resp)
+}
+
 func (h *Handler) CreateTemporaryAccess(w http.ResponseWriter, r *http.Request) {
 	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
 	if err != nil {
@@ -429,6 +465,7 @@ func toSinglePeerResponse(peer *nbpeer.Peer, groupsInfo []api.GroupMinimum, dnsD
 		Id:                          peer.ID,
 		Name:                        peer.Name,
 		Ip:                          peer.IP.String(),
+		Ipv6:                        peerIPv6(peer),
 		ConnectionIp:                peer.Location.ConnectionIP.String(),
 		Connected:                   peer.Status.Connected,
 		LastSeen:                    peer.Status.LastSeen,
@@ -460,6 +497,7 @@ func toSinglePeerResponse(peer *nbpeer.Peer, groupsInfo []api.GroupMinimum, dnsD
 			DisableFirewall:       &peer.Meta.Flags.DisableFirewall,
 			DisableServerRoutes:   &peer.Meta.Flags.DisableServerRoutes,
 			LazyConnectionEnabled: &peer.Meta.Flags.LazyConnectionEnabled,
+			Ipv6Supported:         &peer.Meta.Flags.IPv6Supported,
 			RosenpassEnabled:      &peer.Meta.Flags.RosenpassEnabled,
 			RosenpassPermissive:   &peer.Meta.Flags.RosenpassPermissive,
 			ServerSshAllowed:      &peer.Meta.Flags.ServerSSHAllowed,
@@ -483,6 +521,7 @@ func toPeerListItemResponse(peer *nbpeer.Peer, groupsInfo []api.GroupMinimum, dn
 		Id:                          peer.ID,
 		Name:                        peer.Name,
 		Ip:                          peer.IP.String(),
+		Ipv6:                        peerIPv6(peer),
 		ConnectionIp:                peer.Location.ConnectionIP.String(),
 		Connected:                   peer.Status.Connected,
 		LastSeen:                    peer.Status.LastSeen,
@@ -514,6 +553,7 @@ func toPeerListItemResponse(peer *nbpeer.Peer, groupsInfo []api.GroupMinimum, dn
 			DisableFirewall:       &peer.Meta.Flags.DisableFirewall,
 			DisableServerRoutes:   &peer.Meta.Flags.DisableServerRoutes,
 			LazyConnectionEnabled: &peer.Meta.Flags.LazyConnectionEnabled,
+			Ipv6Supported:         &peer.Meta.Flags.IPv6Supported,
 			RosenpassEnabled:      &peer.Meta.Flags.RosenpassEnabled,
 			RosenpassPermissive:   &peer.Meta.Flags.RosenpassPermissive,
 			ServerSshAllowed:      &peer.Meta.Flags.ServerSSHAllowed,
@@ -521,6 +561,14 @@ func toPeerListItemResponse(peer *nbpeer.Peer, groupsInfo []api.GroupMinimum, dn
 	}
 }
 
+func peerIPv6(peer *nbpeer.Peer) *string {
+	if len(peer.IPv6) == 0 {
+		return nil
+	}
+	ip := peer.IPv6.String()
+	return &ip
+}
+
 func fqdn(peer *nbpeer.Peer, dnsDomain string) string {
 	fqdn := peer.FQDN(dnsDomain)
 	if fqdn == "" {
@@ -20,9 +20,11 @@ import (
 	"github.com/netbirdio/netbird/management/internals/controllers/network_map"
 	nbcontext "github.com/netbirdio/netbird/management/server/context"
 	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/posture"
 	"github.com/netbirdio/netbird/management/server/types"
 	"github.com/netbirdio/netbird/shared/auth"
 	"github.com/netbirdio/netbird/shared/management/http/api"
+	"github.com/netbirdio/netbird/shared/management/status"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -197,6 +199,22 @@ func initTestMetaData(t *testing.T, peers ...*nbpeer.Peer) *Handler {
 			GetAccountSettingsFunc: func(ctx context.Context, accountID string, userID string) (*types.Settings, error) {
 				return account.Settings, nil
 			},
+			GetPeerPostureCheckResultsFunc: func(_ context.Context, accountID, peerID, userID string) ([]posture.Result, error) {
+				for _, peer := range peers {
+					if peer.ID != peerID {
+						continue
+					}
+					checks := &posture.Checks{
+						ID:   "checks",
+						Name: "minimum versions",
+						Checks: posture.ChecksDefinition{
+							NBVersionCheck: &posture.NBVersionCheck{MinVersion: "0.25.0"},
+						},
+					}
+					return checks.Evaluate(context.Background(), *peer), nil
+				}
+				return nil, status.Errorf(status.NotFound, "peer not found")
+			},
 		},
 		networkMapController: networkMapController,
 	}
@@ -533,3 +551,76 @@ func TestPeersHandlerUpdatePeerIP(t *testing.T) {
 		})
 	}
 }
+
+func TestGetPeerPostureCheckResults(t *testing.T) {
+	peers := []*nbpeer.Peer{
+		{
+			ID:   testPeerID,
+			Name: "outdated",
+			Meta: nbpeer.PeerSystemMeta{WtVersion: "0.24.3"},
+		},
+		{
+			ID:   "peer2",
+			Name: "up to date",
+			Meta: nbpeer.PeerSystemMeta{WtVersion: "0.30.0"},
+		},
+	}
+
+	p := initTestMetaData(t, peers...)
+
+	tt := []struct {
+		name           string
+		peerID         string
+		expectedStatus int
+		expectedPassed bool
+		expectedReason string
+	}{
+		{
+			name:           "failed check",
+			peerID:         testPeerID,
+			expectedStatus: http.StatusOK,
+			expectedReason: "NetBird version 0.24.3 < 0.25.0",
+		},
+		{
+			name:           "passed check",
+			peerID:         "peer2",
+			expectedStatus: http.StatusOK,
+			expectedPassed: true,
+		},
+		{
+			name:           "unknown peer",
+			peerID:         "unknown",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/peers/%s/posture-checks", tc.peerID), nil)
+			req = nbcontext.SetUserAuthInRequest(req, auth.UserAuth{
+				UserId:    adminUser,
+				Domain:    "hotmail.com",
+				AccountId: "test_id",
+			})
+
+			rr := httptest.NewRecorder()
+			router := mux.NewRouter()
+			router.HandleFunc("/peers/{peerId}/posture-checks", p.GetPeerPostureCheckResults).Methods("GET")
+			router.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.expectedStatus, rr.Code)
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var results []api.PeerPostureCheckResult
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &results))
+			require.Len(t, results, 1)
+			assert.Equal(t, "checks", results[0].PostureCheckId)
+			assert.Equal(t, "minimum versions", results[0].PostureCheckName)
+			assert.Equal(t, posture.NBVersionCheckName, results[0].Check)
+			assert.Equal(t, tc.expectedPassed, results[0].Passed)
+			assert.Equal(t, tc.expectedReason, results[0].Reason)
+		})
+	}
+}
@@ -2,8 +2,11 @@ package policies
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 
@@ -25,6 +28,7 @@ func AddEndpoints(accountManager account.Manager, locationManager geolocation.Ge
 	policiesHandler := newHandler(accountManager)
 	router.HandleFunc("/policies", policiesHandler.getAllPolicies).Methods("GET", "OPTIONS")
 	router.HandleFunc("/policies", policiesHandler.createPolicy).Methods("POST", "OPTIONS")
+	router.HandleFunc("/policies/simulate", policiesHandler.simulatePolicy).Methods("POST", "OPTIONS")
 	router.HandleFunc("/policies/{policyId}", policiesHandler.updatePolicy).Methods("PUT", "OPTIONS")
 	router.HandleFunc("/policies/{policyId}", policiesHandler.getPolicy).Methods("GET", "OPTIONS")
 	router.HandleFunc("/policies/{policyId}", policiesHandler.deletePolicy).Methods("DELETE", "OPTIONS")
@@ -111,6 +115,73 @@ func (h *handler) createPolicy(w http.ResponseWriter, r *http.Request) {
 	h.savePolicy(w, r, accountID, userID, "", true)
 }
 
+// simulatePolicy handles a request to check whether traffic is allowed by the policies
+func (h *handler) simulatePolicy(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	accountID, userID := userAuth.AccountId, userAuth.UserId
+
+	var req api.PostApiPoliciesSimulateJSONRequestBody
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.WriteErrorResponse("couldn't parse JSON request", http.StatusBadRequest, w)
+		return
+	}
+
+	simulation := types.PolicySimulation{
+		SourcePeerID: req.SourcePeerId,
+		Protocol:     types.PolicyRuleProtocolType(req.Protocol),
+	}
+	if req.DestinationPeerId != nil {
+		simulation.DestinationPeerID = *req.DestinationPeerId
+	}
+	if req.DestinationResourceId != nil {
+		simulation.DestinationResourceID = *req.DestinationResourceId
+	}
+	if req.Port != nil {
+		if *req.Port < 1 || *req.Port > 65535 {
+			util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "valid port value is in 1..65535 range"), w)
+			return
+		}
+		simulation.Port = uint16(*req.Port)
+	}
+
+	var policies []*types.Policy
+	if req.Policies != nil {
+		for _, p := range *req.Policies {
+			var policyID string
+			if p.Id != nil {
+				policyID = *p.Id
+			}
+
+			policy, err := toPolicy(api.PolicyCreate{
+				Name:                p.Name,
+				Description:         p.Description,
+				Enabled:             p.Enabled,
+				Rules:               p.Rules,
+				Schedule:            p.Schedule,
+				SourcePostureChecks: p.SourcePostureChecks,
+			}, accountID, policyID)
+			if err != nil {
+				util.WriteError(r.Context(), err, w)
+				return
+			}
+			policies = append(policies, policy)
+		}
+	}
+
+	result, err := h.accountManager.SimulatePolicy(r.Context(), accountID, userID, simulation, policies)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, toPolicySimulationResponse(result))
+}
+
 // savePolicy handles policy creation and update
 func (h *handler) savePolicy(w http.ResponseWriter, r *http.Request, accountID string, userID string, policyID string, create bool) {
 	var req api.PutApiPoliciesPolicyIdJSONRequestBody
@@ -119,16 +190,63 @@ func (h *handler) savePolicy(w http.ResponseWriter, r *http.Request, accountID s
 		return
 	}
 
-	if req.Name == "" {
-		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "policy name shouldn't be empty"), w)
+	var dryRun bool
+	if value := r.URL.Query().Get("dry_run"); value != "" {
+		var err error
+		dryRun, err = strconv.ParseBool(value)
+		if err != nil {
+			util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "invalid dry_run query parameter"), w)
+			return
+		}
+	}
+
+	policy, err := toPolicy(req, accountID, policyID)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
 		return
 	}
 
-	if len(req.Rules) == 0 {
-		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "policy rules shouldn't be empty"), w)
+	if dryRun {
+		diffs, err := h.accountManager.DryRunSavePolicy(r.Context(), accountID, userID, policy, create)
+		if err != nil {
+			util.WriteError(r.Context(), err, w)
+			return
+		}
+		util.WriteJSONObject(r.Context(), w, toPolicyDryRunResponse(diffs))
+		return
+	}
+
+	policy, err = h.accountManager.SavePolicy(r.Context(), accountID, userID, policy, create)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	allGroups, err := h.accountManager.GetAllGroups(r.Context(), accountID, userID)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
 		return
 	}
 
+	resp := toPolicyResponse(allGroups, policy)
+	if len(resp.Rules) == 0 {
+		util.WriteError(r.Context(), status.Errorf(status.Internal, "no rules in the policy"), w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, resp)
+}
+
+// toPolicy converts the policy request to a policy
+func toPolicy(req api.PolicyCreate, accountID, policyID string) (*types.Policy, error) {
+	if req.Name == "" {
+		return nil, status.Errorf(status.InvalidArgument, "policy name shouldn't be empty")
+	}
+
+	if len(req.Rules) == 0 {
+		return nil, status.Errorf(status.InvalidArgument, "policy rules shouldn't be empty")
+	}
+
 	description := ""
 	if req.Description != nil {
 		description = *req.Description
@@ -154,18 +272,15 @@ func (h *handler) savePolicy(w http.ResponseWriter, r *http.Request, accountID s
 		hasDestinationResource := rule.DestinationResource != nil
 
 		if hasSources && hasSourceResource {
-			util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "specify either sources or  source resources, not both"), w)
-			return
+			return nil, status.Errorf(status.InvalidArgument, "specify either sources or  source resources, not both")
 		}
 
 		if hasDestinations && hasDestinationResource {
-			util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "specify either destinations or  destination resources, not both"), w)
-			return
+			return nil, status.Errorf(status.InvalidArgument, "specify either destinations or  destination resources, not both")
 		}
 
 		if !(hasSources || hasSourceResource) || !(hasDestinations || hasDestinationResource) {
-			util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "specify either sources or source resources and destinations or destination resources"), w)
-			return
+			return nil, status.Errorf(status.InvalidArgument, "specify either sources or source resources and destinations or destination resources")
 		}
 
 		pr := types.PolicyRule{
@@ -208,8 +323,7 @@ func (h *handler) savePolicy(w http.ResponseWriter, r *http.Request, accountID s
 		case api.PolicyRuleUpdateActionDrop:
 			pr.Action = types.PolicyTrafficActionDrop
 		default:
-			util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "unknown action type"), w)
-			return
+			return nil, status.Errorf(status.InvalidArgument, "unknown action type")
 		}
 
 		switch rule.Protocol {
@@ -224,20 +338,17 @@ func (h *handler) savePolicy(w http.ResponseWriter, r *http.Request, accountID s
 		case api.PolicyRuleUpdateProtocolNetbirdSsh:
 			pr.Protocol = types.PolicyRuleProtocolNetbirdSSH
 		default:
-			util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "unknown protocol type: %v", rule.Protocol), w)
-			return
+			return nil, status.Errorf(status.InvalidArgument, "unknown protocol type: %v", rule.Protocol)
 		}
 
 		if (rule.Ports != nil && len(*rule.Ports) != 0) && (rule.PortRanges != nil && len(*rule.PortRanges) != 0) {
-			util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "specify either individual ports or port ranges, not both"), w)
-			return
+			return nil, status.Errorf(status.InvalidArgument, "specify either individual ports or port ranges, not both")
 		}
 
 		if rule.Ports != nil && len(*rule.Ports) != 0 {
 			for _, v := range *rule.Ports {
 				if port, err := strconv.Atoi(v); err != nil || port < 1 || port > 65535 {
-					util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "valid port value is in 1..65535 range"), w)
-					return
+					return nil, status.Errorf(status.InvalidArgument, "valid port value is in 1..65535 range")
 				}
 				pr.Ports = append(pr.Ports, v)
 			}
@@ -246,8 +357,7 @@ func (h *handler) savePolicy(w http.ResponseWriter, r *http.Request, accountID s
 		if rule.PortRanges != nil && len(*rule.PortRanges) != 0 {
 			for _, portRange := range *rule.PortRanges {
 				if portRange.Start < 1 || portRange.End > 65535 {
-					util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "valid port value is in 1..65535 range"), w)
-					return
+					return nil, status.Errorf(status.InvalidArgument, "valid port value is in 1..65535 range")
 				}
 				pr.PortRanges = append(pr.PortRanges, types.RulePortRange{
 					Start: uint16(portRange.Start),
@@ -260,8 +370,7 @@ func (h *handler) savePolicy(w http.ResponseWriter, r *http.Request, accountID s
 			for _, sourceGroupID := range pr.Sources {
 				_, ok := (*rule.AuthorizedGroups)[sourceGroupID]
 				if !ok {
-					util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "authorized group for netbird-ssh protocol should be specified for each source group"), w)
-					return
+					return nil, status.Errorf(status.InvalidArgument, "authorized group for netbird-ssh protocol should be specified for each source group")
 				}
 			}
 			pr.AuthorizedGroups = *rule.AuthorizedGroups
@@ -270,8 +379,7 @@ func (h *handler) savePolicy(w http.ResponseWriter, r *http.Request, accountID s
 		// validate policy object
 		if pr.Protocol == types.PolicyRuleProtocolALL || pr.Protocol == types.PolicyRuleProtocolICMP {
 			if len(pr.Ports) != 0 || len(pr.PortRanges) != 0 {
-				util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "for ALL or ICMP protocol ports is not allowed"), w)
-				return
+				return nil, status.Errorf(status.InvalidArgument, "for ALL or ICMP protocol ports is not allowed")
 			}
 		}
 		policy.Rules = append(policy.Rules, &pr)
@@ -281,25 +389,15 @@ func (h *handler) savePolicy(w http.ResponseWriter, r *http.Request, accountID s
 		policy.SourcePostureChecks = *req.SourcePostureChecks
 	}
 
-	policy, err := h.accountManager.SavePolicy(r.Context(), accountID, userID, policy, create)
-	if err != nil {
-		util.WriteError(r.Context(), err, w)
-		return
-	}
-
-	allGroups, err := h.accountManager.GetAllGroups(r.Context(), accountID, userID)
-	if err != nil {
-		util.WriteError(r.Context(), err, w)
-		return
-	}
-
-	resp := toPolicyResponse(allGroups, policy)
-	if len(resp.Rules) == 0 {
-		util.WriteError(r.Context(), status.Errorf(status.Internal, "no rules in the policy"), w)
-		return
+	if req.Schedule != nil {
+		schedule, err := toPolicySchedule(req.Schedule)
+		if err != nil {
+			return nil, status.Errorf(status.InvalidArgument, "%s", err.Error())
+		}
+		policy.Schedule = schedule
 	}
 
-	util.WriteJSONObject(r.Context(), w, resp)
+	return policy, nil
 }
 
 // deletePolicy handles policy deletion request
@@ -377,6 +475,7 @@ func toPolicyResponse(groups []*types.Group, policy *types.Policy) *api.Policy {
 		Description:         &policy.Description,
 		Enabled:             policy.Enabled,
 		SourcePostureChecks: policy.SourcePostureChecks,
+		Schedule:            toPolicyScheduleResponse(policy.Schedule),
 	}
 	for _, r := range policy.Rules {
 		rID := r.ID
@@ -457,3 +556,215 @@ func toPolicyResponse(groups []*types.Group, policy *types.Policy) *api.Policy {
 	}
 	return ap
 }
+
+func toPolicySimulationResponse(result *types.PolicySimulationResult) *api.PolicySimulationResult {
+	resp := &api.PolicySimulationResult{
+		Allowed:       result.Allowed,
+		Reason:        result.Reason,
+		Rules:         make([]api.PolicySimulationRule, 0, len(result.Rules)),
+		PostureChecks: make([]api.PeerPostureCheckResult, 0, len(result.PostureChecks)),
+		Routes:        make([]api.PolicySimulationRoute, 0, len(result.Routes)),
+	}
+
+	for _, rule := range result.Rules {
+		resp.Rules = append(resp.Rules, api.PolicySimulationRule{
+			PolicyId:   rule.PolicyID,
+			PolicyName: rule.PolicyName,
+			RuleId:     rule.RuleID,
+			RuleName:   rule.RuleName,
+			Action:     api.PolicySimulationRuleAction(rule.Action),
+			Applied:    rule.Applied,
+		})
+	}
+
+	for _, check := range result.PostureChecks {
+		resp.PostureChecks = append(resp.PostureChecks, api.PeerPostureCheckResult{
+			PostureCheckId:   check.PostureChecksID,
+			PostureCheckName: check.PostureChecksName,
+			Check:            check.Check,
+			Passed:           check.Passed,
+			Reason:           check.Reason,
+			EvaluatedAt:      check.EvaluatedAt,
+		})
+	}
+
+	for _, r := range result.Routes {
+		route := api.PolicySimulationRoute{
+			Id:     string(r.ID),
+			PeerId: r.PeerID,
+		}
+		if r.IsDynamic() {
+			domains := r.Domains.ToPunycodeList()
+			route.Domains = &domains
+		} else {
+			network := r.Network.String()
+			route.Network = &network
+		}
+		resp.Routes = append(resp.Routes, route)
+	}
+
+	return resp
+}
+
+func toPolicyDryRunResponse(diffs []*types.PeerNetworkMapDiff) *api.PolicyDryRunResult {
+	resp := &api.PolicyDryRunResult{
+		Peers: make([]api.PolicyDryRunPeer, 0, len(diffs)),
+	}
+
+	for _, diff := range diffs {
+		peer := api.PolicyDryRunPeer{
+			PeerId:                    diff.PeerID,
+			PeerName:                  diff.PeerName,
+			AddedPeers:                append([]string{}, diff.AddedPeers...),
+			RemovedPeers:              append([]string{}, diff.RemovedPeers...),
+			AddedRoutes:               make([]string, 0, len(diff.AddedRoutes)),
+			RemovedRoutes:             make([]string, 0, len(diff.RemovedRoutes)),
+			AddedFirewallRules:        toPolicyDryRunFirewallRules(diff.AddedFirewallRules),
+			RemovedFirewallRules:      toPolicyDryRunFirewallRules(diff.RemovedFirewallRules),
+			AddedRouteFirewallRules:   toPolicyDryRunRouteFirewallRules(diff.AddedRouteFirewallRules),
+			RemovedRouteFirewallRules: toPolicyDryRunRouteFirewallRules(diff.RemovedRouteFirewallRules),
+		}
+		for _, id := range diff.AddedRoutes {
+			peer.AddedRoutes = append(peer.AddedRoutes, string(id))
+		}
+		for _, id := range diff.RemovedRoutes {
+			peer.RemovedRoutes = append(peer.RemovedRoutes, string(id))
+		}
+		resp.Peers = append(resp.Peers, peer)
+	}
+
+	return resp
+}
+
+func toPolicyDryRunFirewallRules(rules []*types.FirewallRule) []api.PolicyDryRunFirewallRule {
+	resp := make([]api.PolicyDryRunFirewallRule, 0, len(rules))
+	for _, rule := range rules {
+		direction := api.PolicyDryRunFirewallRuleDirectionIn
+		if rule.Direction == types.FirewallRuleDirectionOUT {
+			direction = api.PolicyDryRunFirewallRuleDirectionOut
+		}
+
+		fr := api.PolicyDryRunFirewallRule{
+			PolicyId:  rule.PolicyID,
+			PeerIp:    rule.PeerIP,
+			Direction: direction,
+			Action:    api.PolicyDryRunFirewallRuleAction(rule.Action),
+			Protocol:  rule.Protocol,
+		}
+		if rule.Port != "" {
+			port := rule.Port
+			fr.Port = &port
+		}
+		if rule.PortRange.Start != 0 || rule.PortRange.End != 0 {
+			fr.PortRange = &api.RulePortRange{
+				Start: int(rule.PortRange.Start),
+				End:   int(rule.PortRange.End),
+			}
+		}
+		resp = append(resp, fr)
+	}
+	return resp
+}
+
+func toPolicyDryRunRouteFirewallRules(rules []*types.RouteFirewallRule) []api.PolicyDryRunRouteFirewallRule {
+	resp := make([]api.PolicyDryRunRouteFirewallRule, 0, len(rules))
+	for _, rule := range rules {
+		fr := api.PolicyDryRunRouteFirewallRule{
+			PolicyId:     rule.PolicyID,
+			RouteId:      string(rule.RouteID),
+			SourceRanges: append([]string{}, rule.SourceRanges...),
+			Destination:  rule.Destination,
+			Action:       api.PolicyDryRunRouteFirewallRuleAction(rule.Action),
+			Protocol:     rule.Protocol,
+		}
+		if len(rule.Domains) != 0 {
+			domains := rule.Domains.ToPunycodeList()
+			fr.Domains = &domains
+		}
+		if rule.Port != 0 {
+			port := int(rule.Port)
+			fr.Port = &port
+		}
+		if rule.PortRange.Start != 0 || rule.PortRange.End != 0 {
+			fr.PortRange = &api.RulePortRange{
+				Start: int(rule.PortRange.Start),
+				End:   int(rule.PortRange.End),
+			}
+		}
+		resp = append(resp, fr)
+	}
+	return resp
+}
+
+var apiWeekdays = map[api.PolicyScheduleWindowDays]time.Weekday{
+	api.PolicyScheduleWindowDaysSun: time.Sunday,
+	api.PolicyScheduleWindowDaysMon: time.Monday,
+	api.PolicyScheduleWindowDaysTue: time.Tuesday,
+	api.PolicyScheduleWindowDaysWed: time.Wednesday,
+	api.PolicyScheduleWindowDaysThu: time.Thursday,
+	api.PolicyScheduleWindowDaysFri: time.Friday,
+	api.PolicyScheduleWindowDaysSat: time.Saturday,
+}
+
+func toPolicySchedule(req *api.PolicySchedule) (*types.PolicySchedule, error) {
+	schedule := &types.PolicySchedule{
+		NotBefore: req.NotBefore,
+		NotAfter:  req.NotAfter,
+	}
+	if req.TimeZone != nil {
+		schedule.TimeZone = *req.TimeZone
+	}
+
+	if req.Windows != nil {
+		for _, window := range *req.Windows {
+			days := make([]time.Weekday, 0, len(window.Days))
+			for _, day := range window.Days {
+				weekday, ok := apiWeekdays[day]
+				if !ok {
+					return nil, fmt.Errorf("invalid schedule window day %s", day)
+				}
+				days = append(days, weekday)
+			}
+			schedule.Windows = append(schedule.Windows, types.PolicyScheduleWindow{
+				Days:  days,
+				Start: window.Start,
+				End:   window.End,
+			})
+		}
+	}
+
+	return schedule, nil
+}
+
+func toPolicyScheduleResponse(schedule *types.PolicySchedule) *api.PolicySchedule {
+	if schedule == nil {
+		return nil
+	}
+
+	resp := &api.PolicySchedule{
+		NotBefore: schedule.NotBefore,
+		NotAfter:  schedule.NotAfter,
+	}
+	if schedule.TimeZone != "" {
+		timeZone := schedule.TimeZone
+		resp.TimeZone = &timeZone
+	}
+
+	if len(schedule.Windows) != 0 {
+		windows := make([]api.PolicyScheduleWindow, 0, len(schedule.Windows))
+		for _, window := range schedule.Windows {
+			days := make([]api.PolicyScheduleWindowDays, 0, len(window.Days))
+			for _, day := range window.Days {
+				days = append(days, api.PolicyScheduleWindowDays(strings.ToLower(day.String()[:3])))
+			}
+			windows = append(windows, api.PolicyScheduleWindow{
+				Days:  days,
+				Start: window.Start,
+				End:   window.End,
+			})
+		}
+		resp.Windows = &windows
+	}
+
+	return resp
+}
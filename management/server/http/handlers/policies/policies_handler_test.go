@@ -308,3 +308,226 @@ func TestPoliciesWritePolicy(t *testing.T) {
 		})
 	}
 }
+
+func TestPoliciesSimulatePolicy(t *testing.T) {
+	tt := []struct {
+		name             string
+		requestBody      string
+		expectedStatus   int
+		expectedPolicies int
+		expectedResult   *api.PolicySimulationResult
+	}{
+		{
+			name:           "simulate current policies",
+			requestBody:    `{"source_peer_id":"peer1","destination_peer_id":"peer2","protocol":"tcp","port":22}`,
+			expectedStatus: http.StatusOK,
+			expectedResult: &api.PolicySimulationResult{
+				Allowed: true,
+				Rules: []api.PolicySimulationRule{
+					{PolicyId: "id-existed", PolicyName: "ssh", RuleId: "id-existed", RuleName: "ssh", Action: "accept", Applied: true},
+				},
+				PostureChecks: []api.PeerPostureCheckResult{},
+				Routes:        []api.PolicySimulationRoute{},
+			},
+		},
+		{
+			name: "simulate hypothetical policies",
+			requestBody: `{"source_peer_id":"peer1","destination_peer_id":"peer2","protocol":"tcp","port":22,"policies":[
+				{"name":"deny","enabled":true,"rules":[{"name":"deny","enabled":true,"protocol":"all","action":"drop","bidirectional":true,"sources":["F"],"destinations":["G"]}]}
+			]}`,
+			expectedStatus:   http.StatusOK,
+			expectedPolicies: 1,
+			expectedResult: &api.PolicySimulationResult{
+				Reason:        "no policy rule matches the traffic",
+				Rules:         []api.PolicySimulationRule{},
+				PostureChecks: []api.PeerPostureCheckResult{},
+				Routes:        []api.PolicySimulationRoute{},
+			},
+		},
+		{
+			name:           "invalid port",
+			requestBody:    `{"source_peer_id":"peer1","destination_peer_id":"peer2","protocol":"tcp","port":70000}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "invalid hypothetical policy",
+			requestBody:    `{"source_peer_id":"peer1","destination_peer_id":"peer2","protocol":"tcp","policies":[{"name":"","enabled":true,"rules":[]}]}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			p := initPoliciesTestData()
+			p.accountManager.(*mock_server.MockAccountManager).SimulatePolicyFunc = func(_ context.Context, _, _ string, simulation types.PolicySimulation, policies []*types.Policy) (*types.PolicySimulationResult, error) {
+				assert.Equal(t, types.PolicySimulation{SourcePeerID: "peer1", DestinationPeerID: "peer2", Protocol: types.PolicyRuleProtocolTCP, Port: 22}, simulation)
+				assert.Len(t, policies, tc.expectedPolicies)
+				if len(policies) > 0 {
+					return &types.PolicySimulationResult{Reason: "no policy rule matches the traffic"}, nil
+				}
+				return &types.PolicySimulationResult{
+					Allowed: true,
+					Rules: []types.PolicySimulationRule{
+						{PolicyID: "id-existed", PolicyName: "ssh", RuleID: "id-existed", RuleName: "ssh", Action: types.PolicyTrafficActionAccept, Applied: true},
+					},
+				}, nil
+			}
+
+			recorder := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/policies/simulate", bytes.NewBufferString(tc.requestBody))
+			req = nbcontext.SetUserAuthInRequest(req, auth.UserAuth{
+				UserId:    "test_user",
+				Domain:    "hotmail.com",
+				AccountId: "test_id",
+			})
+
+			router := mux.NewRouter()
+			router.HandleFunc("/api/policies/simulate", p.simulatePolicy).Methods("POST")
+			router.ServeHTTP(recorder, req)
+
+			res := recorder.Result()
+			defer res.Body.Close()
+
+			content, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Fatalf("I don't know what I expected; %v", err)
+			}
+
+			if status := recorder.Code; status != tc.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v, content: %s",
+					status, tc.expectedStatus, string(content))
+				return
+			}
+
+			if tc.expectedResult == nil {
+				return
+			}
+
+			var got api.PolicySimulationResult
+			if err = json.Unmarshal(content, &got); err != nil {
+				t.Fatalf("Sent content is not in correct json format; %v", err)
+			}
+			assert.Equal(t, *tc.expectedResult, got)
+		})
+	}
+}
+
+func TestPoliciesWritePolicyDryRun(t *testing.T) {
+	tt := []struct {
+		name           string
+		requestType    string
+		requestPath    string
+		expectedStatus int
+		expectedCreate bool
+	}{
+		{
+			name:           "dry run POST",
+			requestType:    http.MethodPost,
+			requestPath:    "/api/policies?dry_run=true",
+			expectedStatus: http.StatusOK,
+			expectedCreate: true,
+		},
+		{
+			name:           "dry run PUT",
+			requestType:    http.MethodPut,
+			requestPath:    "/api/policies/id-existed?dry_run=true",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid dry run",
+			requestType:    http.MethodPost,
+			requestPath:    "/api/policies?dry_run=maybe",
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			p := initPoliciesTestData(&types.Policy{ID: "id-existed"})
+			mockManager := p.accountManager.(*mock_server.MockAccountManager)
+			mockManager.SavePolicyFunc = func(_ context.Context, _, _ string, _ *types.Policy, _ bool) (*types.Policy, error) {
+				t.Error("dry run should not save the policy")
+				return nil, status.Errorf(status.Internal, "unexpected save")
+			}
+			mockManager.DryRunSavePolicyFunc = func(_ context.Context, _, _ string, policy *types.Policy, create bool) ([]*types.PeerNetworkMapDiff, error) {
+				assert.Equal(t, tc.expectedCreate, create)
+				assert.Equal(t, "Dry Run Policy", policy.Name)
+				return []*types.PeerNetworkMapDiff{
+					{
+						PeerID:     "peer1",
+						PeerName:   "peer1",
+						AddedPeers: []string{"peer2"},
+						AddedFirewallRules: []*types.FirewallRule{
+							{PolicyID: "rule1", PeerIP: "100.64.0.2", Direction: types.FirewallRuleDirectionIN, Action: "accept", Protocol: "tcp", Port: "22"},
+						},
+						RemovedRouteFirewallRules: []*types.RouteFirewallRule{
+							{PolicyID: "policy1", RouteID: "route1", SourceRanges: []string{"100.64.0.2/32"}, Destination: "10.0.0.0/24", Action: "accept", Protocol: "all"},
+						},
+					},
+				}, nil
+			}
+
+			recorder := httptest.NewRecorder()
+			req := httptest.NewRequest(tc.requestType, tc.requestPath, bytes.NewBufferString(`{
+				"name":"Dry Run Policy",
+				"enabled":true,
+				"rules":[{"name":"ssh","enabled":true,"protocol":"tcp","ports":["22"],"action":"accept","bidirectional":true,"sources":["F"],"destinations":["G"]}]
+			}`))
+			req = nbcontext.SetUserAuthInRequest(req, auth.UserAuth{
+				UserId:    "test_user",
+				Domain:    "hotmail.com",
+				AccountId: "test_id",
+			})
+
+			router := mux.NewRouter()
+			router.HandleFunc("/api/policies", p.createPolicy).Methods("POST")
+			router.HandleFunc("/api/policies/{policyId}", p.updatePolicy).Methods("PUT")
+			router.ServeHTTP(recorder, req)
+
+			res := recorder.Result()
+			defer res.Body.Close()
+
+			content, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Fatalf("I don't know what I expected; %v", err)
+			}
+
+			if status := recorder.Code; status != tc.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v, content: %s",
+					status, tc.expectedStatus, string(content))
+				return
+			}
+
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var got api.PolicyDryRunResult
+			if err = json.Unmarshal(content, &got); err != nil {
+				t.Fatalf("Sent content is not in correct json format; %v", err)
+			}
+
+			port := "22"
+			assert.Equal(t, api.PolicyDryRunResult{
+				Peers: []api.PolicyDryRunPeer{
+					{
+						PeerId:        "peer1",
+						PeerName:      "peer1",
+						AddedPeers:    []string{"peer2"},
+						RemovedPeers:  []string{},
+						AddedRoutes:   []string{},
+						RemovedRoutes: []string{},
+						AddedFirewallRules: []api.PolicyDryRunFirewallRule{
+							{PolicyId: "rule1", PeerIp: "100.64.0.2", Direction: api.PolicyDryRunFirewallRuleDirectionIn, Action: "accept", Protocol: "tcp", Port: &port},
+						},
+						RemovedFirewallRules:    []api.PolicyDryRunFirewallRule{},
+						AddedRouteFirewallRules: []api.PolicyDryRunRouteFirewallRule{},
+						RemovedRouteFirewallRules: []api.PolicyDryRunRouteFirewallRule{
+							{PolicyId: "policy1", RouteId: "route1", SourceRanges: []string{"100.64.0.2/32"}, Destination: "10.0.0.0/24", Action: "accept", Protocol: "all"},
+						},
+					},
+				},
+			}, got)
+		})
+	}
+}
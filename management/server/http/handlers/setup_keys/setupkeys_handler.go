@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"net/netip"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -85,8 +87,14 @@ func (h *handler) createSetupKey(w http.ResponseWriter, r *http.Request) {
 		allowExtraDNSLabels = *req.AllowExtraDnsLabels
 	}
 
+	constraints, err := toSetupKeyConstraints(req.Constraints)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
 	setupKey, err := h.accountManager.CreateSetupKey(r.Context(), accountID, req.Name, types.SetupKeyType(req.Type), expiresIn,
-		req.AutoGroups, req.UsageLimit, userID, ephemeral, allowExtraDNSLabels)
+		req.AutoGroups, req.UsageLimit, userID, ephemeral, allowExtraDNSLabels, constraints)
 	if err != nil {
 		util.WriteError(r.Context(), err, w)
 		return
@@ -157,6 +165,22 @@ func (h *handler) updateSetupKey(w http.ResponseWriter, r *http.Request) {
 	newKey.Revoked = req.Revoked
 	newKey.Id = keyID
 
+	// the constraints are kept when the request doesn't contain them
+	if req.Constraints == nil {
+		oldKey, err := h.accountManager.GetSetupKey(r.Context(), accountID, userID, keyID)
+		if err != nil {
+			util.WriteError(r.Context(), err, w)
+			return
+		}
+		newKey.Constraints = oldKey.Constraints
+	} else {
+		newKey.Constraints, err = toSetupKeyConstraints(req.Constraints)
+		if err != nil {
+			util.WriteError(r.Context(), err, w)
+			return
+		}
+	}
+
 	newKey, err = h.accountManager.SaveSetupKey(r.Context(), accountID, newKey, userID)
 	if err != nil {
 		util.WriteError(r.Context(), err, w)
@@ -235,7 +259,7 @@ func ToResponseBody(key *types.SetupKey) *api.SetupKey {
 		state = "valid"
 	}
 
-	return &api.SetupKey{
+	apiKey := &api.SetupKey{
 		Id:                  key.Id,
 		Key:                 key.KeySecret,
 		Name:                key.Name,
@@ -252,4 +276,61 @@ func ToResponseBody(key *types.SetupKey) *api.SetupKey {
 		Ephemeral:           key.Ephemeral,
 		AllowExtraDnsLabels: key.AllowExtraDNSLabels,
 	}
+
+	if !key.Constraints.IsEmpty() {
+		apiKey.Constraints = toConstraintsResponse(key.Constraints)
+	}
+
+	return apiKey
+}
+
+func toSetupKeyConstraints(req *api.SetupKeyConstraints) (types.SetupKeyConstraints, error) {
+	var constraints types.SetupKeyConstraints
+	if req == nil {
+		return constraints, nil
+	}
+
+	if req.AllowedSourceRanges != nil {
+		for _, sourceRange := range *req.AllowedSourceRanges {
+			prefix, err := netip.ParsePrefix(strings.TrimSpace(sourceRange))
+			if err != nil {
+				return constraints, status.Errorf(status.InvalidArgument, "invalid source range %s", sourceRange)
+			}
+			constraints.AllowedSourceRanges = append(constraints.AllowedSourceRanges, prefix.Masked())
+		}
+	}
+
+	if req.HostnamePattern != nil {
+		constraints.HostnamePattern = *req.HostnamePattern
+	}
+	if req.RequiredOs != nil {
+		constraints.RequiredOS = *req.RequiredOs
+	}
+	constraints.NotBefore = req.NotBefore
+	constraints.NotAfter = req.NotAfter
+
+	return constraints, nil
+}
+
+func toConstraintsResponse(constraints types.SetupKeyConstraints) *api.SetupKeyConstraints {
+	resp := &api.SetupKeyConstraints{
+		NotBefore: constraints.NotBefore,
+		NotAfter:  constraints.NotAfter,
+	}
+
+	if len(constraints.AllowedSourceRanges) > 0 {
+		sourceRanges := make([]string, 0, len(constraints.AllowedSourceRanges))
+		for _, prefix := range constraints.AllowedSourceRanges {
+			sourceRanges = append(sourceRanges, prefix.String())
+		}
+		resp.AllowedSourceRanges = &sourceRanges
+	}
+	if constraints.HostnamePattern != "" {
+		resp.HostnamePattern = &constraints.HostnamePattern
+	}
+	if constraints.RequiredOS != "" {
+		resp.RequiredOs = &constraints.RequiredOS
+	}
+
+	return resp
 }
@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/netip"
 	"testing"
 	"time"
 
@@ -33,12 +34,13 @@ func initSetupKeysTestMetaData(defaultKey *types.SetupKey, newKey *types.SetupKe
 	return &handler{
 		accountManager: &mock_server.MockAccountManager{
 			CreateSetupKeyFunc: func(_ context.Context, _ string, keyName string, typ types.SetupKeyType, _ time.Duration, _ []string,
-				_ int, _ string, ephemeral bool, allowExtraDNSLabels bool,
+				_ int, _ string, ephemeral bool, allowExtraDNSLabels bool, constraints types.SetupKeyConstraints,
 			) (*types.SetupKey, error) {
 				if keyName == newKey.Name || typ != newKey.Type {
 					nk := newKey.Copy()
 					nk.Ephemeral = ephemeral
 					nk.AllowExtraDNSLabels = allowExtraDNSLabels
+					nk.Constraints = constraints
 					return nk, nil
 				}
 				return nil, fmt.Errorf("failed creating setup key")
@@ -91,6 +93,15 @@ func TestSetupKeysHandlers(t *testing.T) {
 
 	expectedNewKey := ToResponseBody(newSetupKey)
 	expectedNewKey.Key = plainKey
+
+	constrainedKey := newSetupKey.Copy()
+	constrainedKey.Constraints = types.SetupKeyConstraints{
+		AllowedSourceRanges: []netip.Prefix{netip.MustParsePrefix("203.0.113.0/24")},
+		HostnamePattern:     "^ci-",
+		RequiredOS:          "linux",
+	}
+	expectedConstrainedKey := ToResponseBody(constrainedKey)
+	expectedConstrainedKey.Key = plainKey
 	tt := []struct {
 		name              string
 		requestType       string
@@ -134,6 +145,28 @@ func TestSetupKeysHandlers(t *testing.T) {
 			expectedBody:     true,
 			expectedSetupKey: expectedNewKey,
 		},
+		{
+			name:        "Create Setup Key With Constraints",
+			requestType: http.MethodPost,
+			requestPath: "/api/setup-keys",
+			requestBody: bytes.NewBuffer(
+				[]byte(fmt.Sprintf("{\"name\":\"%s\",\"type\":\"%s\",\"expires_in\":86400, \"ephemeral\":true, "+
+					"\"constraints\":{\"allowed_source_ranges\":[\"203.0.113.7/24\"],\"hostname_pattern\":\"^ci-\",\"required_os\":\"linux\"}}",
+					newSetupKey.Name, newSetupKey.Type))),
+			expectedStatus:   http.StatusOK,
+			expectedBody:     true,
+			expectedSetupKey: expectedConstrainedKey,
+		},
+		{
+			name:        "Create Setup Key Invalid Source Range",
+			requestType: http.MethodPost,
+			requestPath: "/api/setup-keys",
+			requestBody: bytes.NewBuffer(
+				[]byte(fmt.Sprintf("{\"name\":\"%s\",\"type\":\"%s\",\"expires_in\":86400, \"constraints\":{\"allowed_source_ranges\":[\"office\"]}}",
+					newSetupKey.Name, newSetupKey.Type))),
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   false,
+		},
 		{
 			name:        "Update Setup Key",
 			requestType: http.MethodPut,
@@ -231,4 +264,5 @@ func assertKeys(t *testing.T, got *api.SetupKey, expected *api.SetupKey) {
 	assert.Equal(t, got.Revoked, expected.Revoked)
 	assert.ElementsMatch(t, got.AutoGroups, expected.AutoGroups)
 	assert.Equal(t, got.Ephemeral, expected.Ephemeral)
+	assert.Equal(t, got.Constraints, expected.Constraints)
 }
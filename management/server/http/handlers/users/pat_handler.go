@@ -3,11 +3,13 @@ package users
 import (
 	"encoding/json"
 	"net/http"
+	"net/netip"
 
 	"github.com/gorilla/mux"
 
 	"github.com/netbirdio/netbird/management/server/account"
 	nbcontext "github.com/netbirdio/netbird/management/server/context"
+	"github.com/netbirdio/netbird/management/server/permissions/modules"
 	"github.com/netbirdio/netbird/management/server/types"
 	"github.com/netbirdio/netbird/shared/management/http/api"
 	"github.com/netbirdio/netbird/shared/management/http/util"
@@ -118,7 +120,26 @@ func (h *patHandler) createToken(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pat, err := h.accountManager.CreatePAT(r.Context(), accountID, userID, targetUserID, req.Name, req.ExpiresIn)
+	var scopes []types.PATScope
+	if req.Scopes != nil {
+		for _, scope := range *req.Scopes {
+			scopes = append(scopes, types.PATScope{Module: modules.Module(scope.Module), Access: types.PATScopeAccess(scope.Access)})
+		}
+	}
+
+	var allowedSourceRanges []netip.Prefix
+	if req.AllowedSourceRanges != nil {
+		for _, sourceRange := range *req.AllowedSourceRanges {
+			prefix, err := netip.ParsePrefix(sourceRange)
+			if err != nil {
+				util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "invalid source range %s", sourceRange), w)
+				return
+			}
+			allowedSourceRanges = append(allowedSourceRanges, prefix)
+		}
+	}
+
+	pat, err := h.accountManager.CreatePAT(r.Context(), accountID, userID, targetUserID, req.Name, req.ExpiresIn, scopes, allowedSourceRanges)
 	if err != nil {
 		util.WriteError(r.Context(), err, w)
 		return
@@ -159,7 +180,7 @@ func (h *patHandler) deleteToken(w http.ResponseWriter, r *http.Request) {
 }
 
 func toPATResponse(pat *types.PersonalAccessToken) *api.PersonalAccessToken {
-	return &api.PersonalAccessToken{
+	response := &api.PersonalAccessToken{
 		CreatedAt:      pat.CreatedAt,
 		CreatedBy:      pat.CreatedBy,
 		Name:           pat.Name,
@@ -167,6 +188,24 @@ func toPATResponse(pat *types.PersonalAccessToken) *api.PersonalAccessToken {
 		Id:             pat.ID,
 		LastUsed:       pat.LastUsed,
 	}
+
+	if pat.IsScoped() {
+		scopes := make([]api.PersonalAccessTokenScope, 0, len(pat.Scopes))
+		for _, scope := range pat.Scopes {
+			scopes = append(scopes, api.PersonalAccessTokenScope{Module: string(scope.Module), Access: api.PersonalAccessTokenScopeAccess(scope.Access)})
+		}
+		response.Scopes = &scopes
+	}
+
+	if len(pat.AllowedSourceRanges) > 0 {
+		sourceRanges := make([]string, 0, len(pat.AllowedSourceRanges))
+		for _, prefix := range pat.AllowedSourceRanges {
+			sourceRanges = append(sourceRanges, prefix.String())
+		}
+		response.AllowedSourceRanges = &sourceRanges
+	}
+
+	return response
 }
 
 func toPATGeneratedResponse(pat *types.PersonalAccessTokenGenerated) *api.PersonalAccessTokenGenerated {
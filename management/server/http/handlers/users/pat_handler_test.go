@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/netip"
 	"testing"
 	"time"
 
@@ -67,7 +68,7 @@ var testAccount = &types.Account{
 func initPATTestData() *patHandler {
 	return &patHandler{
 		accountManager: &mock_server.MockAccountManager{
-			CreatePATFunc: func(_ context.Context, accountID string, initiatorUserID string, targetUserID string, tokenName string, expiresIn int) (*types.PersonalAccessTokenGenerated, error) {
+			CreatePATFunc: func(_ context.Context, accountID string, initiatorUserID string, targetUserID string, tokenName string, expiresIn int, scopes []types.PATScope, allowedSourceRanges []netip.Prefix) (*types.PersonalAccessTokenGenerated, error) {
 				if accountID != existingAccountID {
 					return nil, status.Errorf(status.NotFound, "account with ID %s not found", accountID)
 				}
@@ -75,8 +76,11 @@ func initPATTestData() *patHandler {
 					return nil, status.Errorf(status.NotFound, "user with ID %s not found", targetUserID)
 				}
 				return &types.PersonalAccessTokenGenerated{
-					PlainToken:          "nbp_z1pvsg2wP3EzmEou4S679KyTNhov632eyrXe",
-					PersonalAccessToken: types.PersonalAccessToken{},
+					PlainToken: "nbp_z1pvsg2wP3EzmEou4S679KyTNhov632eyrXe",
+					PersonalAccessToken: types.PersonalAccessToken{
+						Scopes:              scopes,
+						AllowedSourceRanges: allowedSourceRanges,
+					},
 				}, nil
 			},
 			DeletePATFunc: func(_ context.Context, accountID string, initiatorUserID string, targetUserID string, tokenID string) error {
@@ -166,6 +170,23 @@ func TestTokenHandlers(t *testing.T) {
 			expectedStatus: http.StatusOK,
 			expectedBody:   true,
 		},
+		{
+			name:        "POST Scoped",
+			requestType: http.MethodPost,
+			requestPath: "/api/users/" + existingUserID + "/tokens",
+			requestBody: bytes.NewBuffer(
+				[]byte(`{"name":"ci","expires_in":7,"scopes":[{"module":"setup_keys","access":"write"},{"module":"peers","access":"read"}],"allowed_source_ranges":["203.0.113.0/24"]}`)),
+			expectedStatus: http.StatusOK,
+			expectedBody:   true,
+		},
+		{
+			name:        "POST Invalid Source Range",
+			requestType: http.MethodPost,
+			requestPath: "/api/users/" + existingUserID + "/tokens",
+			requestBody: bytes.NewBuffer(
+				[]byte(`{"name":"ci","expires_in":7,"allowed_source_ranges":["203.0.113.1"]}`)),
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
 	}
 
 	p := initPATTestData()
@@ -213,6 +234,19 @@ func TestTokenHandlers(t *testing.T) {
 				}
 				assert.NotEmpty(t, got.PlainToken)
 				assert.Equal(t, types.PATLength, len(got.PlainToken))
+				assert.Nil(t, got.PersonalAccessToken.Scopes)
+				assert.Nil(t, got.PersonalAccessToken.AllowedSourceRanges)
+			case "POST Scoped":
+				got := &api.PersonalAccessTokenGenerated{}
+				if err = json.Unmarshal(content, &got); err != nil {
+					t.Fatalf("Sent content is not in correct json format; %v", err)
+				}
+				expectedScopes := []api.PersonalAccessTokenScope{
+					{Module: "setup_keys", Access: api.PersonalAccessTokenScopeAccessWrite},
+					{Module: "peers", Access: api.PersonalAccessTokenScopeAccessRead},
+				}
+				assert.Equal(t, &expectedScopes, got.PersonalAccessToken.Scopes)
+				assert.Equal(t, &[]string{"203.0.113.0/24"}, got.PersonalAccessToken.AllowedSourceRanges)
 			case "Get All Tokens":
 				expectedTokens := []api.PersonalAccessToken{
 					toTokenResponse(*testAccount.Users[existingUserID].PATs[existingTokenID]),
@@ -20,6 +20,9 @@ import (
 	"github.com/netbirdio/netbird/shared/management/status"
 )
 
+// apiPrefix is the path prefix of the API endpoints the middleware authenticates
+const apiPrefix = "/api"
+
 type EnsureAccountFunc func(ctx context.Context, userAuth auth.UserAuth) (string, string, error)
 type SyncUserJWTGroupsFunc func(ctx context.Context, userAuth auth.UserAuth) error
 
@@ -193,6 +196,21 @@ func (m *AuthMiddleware) checkPATFromRequest(r *http.Request, authHeaderParts []
 		return r, fmt.Errorf("token expired")
 	}
 
+	if len(pat.AllowedSourceRanges) > 0 {
+		addr, ok := ClientIPFromRequest(r)
+		if !ok || !pat.AllowsSource(addr) {
+			return r, status.Errorf(status.PermissionDenied, "token is not allowed from this address")
+		}
+	}
+
+	// scoped tokens are rejected here before the endpoints validate the permissions of the user
+	if pat.IsScoped() {
+		module, operation, ok := requestScope(r.Method, strings.TrimPrefix(r.URL.Path, apiPrefix))
+		if !ok || !pat.AllowsOperation(module, operation) {
+			return r, status.Errorf(status.PermissionDenied, "token scopes don't allow this request")
+		}
+	}
+
 	err = m.authManager.MarkPATUsed(ctx, pat.ID)
 	if err != nil {
 		return r, err
@@ -204,6 +222,8 @@ func (m *AuthMiddleware) checkPATFromRequest(r *http.Request, authHeaderParts []
 		Domain:         accDomain,
 		DomainCategory: accCategory,
 		IsPAT:          true,
+		PATID:          pat.ID,
+		PATName:        pat.Name,
 	}
 
 	if impersonate, ok := r.URL.Query()["account"]; ok && len(impersonate) == 1 {
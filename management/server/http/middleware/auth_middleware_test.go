@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/netip"
 	"testing"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/netbirdio/netbird/management/server/auth"
 	nbcontext "github.com/netbirdio/netbird/management/server/context"
 	"github.com/netbirdio/netbird/management/server/http/middleware/bypass"
+	"github.com/netbirdio/netbird/management/server/permissions/modules"
 	"github.com/netbirdio/netbird/management/server/types"
 	"github.com/netbirdio/netbird/management/server/util"
 	nbauth "github.com/netbirdio/netbird/shared/auth"
@@ -624,6 +626,8 @@ func TestAuthMiddleware_Handler_Child(t *testing.T) {
 				Domain:         testAccount.Domain,
 				DomainCategory: testAccount.DomainCategory,
 				IsPAT:          true,
+				PATID:          tokenID,
+				PATName:        "My first token",
 			},
 		},
 		{
@@ -637,6 +641,8 @@ func TestAuthMiddleware_Handler_Child(t *testing.T) {
 				DomainCategory: testAccount.DomainCategory,
 				IsChild:        true,
 				IsPAT:          true,
+				PATID:          tokenID,
+				PATName:        "My first token",
 			},
 		},
 		{
@@ -717,3 +723,111 @@ func TestAuthMiddleware_Handler_Child(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthMiddleware_ScopedPAT(t *testing.T) {
+	scopedPAT := &types.PersonalAccessToken{
+		ID:             tokenID,
+		Name:           "ci",
+		HashedToken:    "someHash",
+		ExpirationDate: util.ToPtr(time.Now().UTC().AddDate(0, 0, 7)),
+		Scopes: []types.PATScope{
+			{Module: modules.SetupKeys, Access: types.PATScopeAccessWrite},
+			{Module: modules.Peers, Access: types.PATScopeAccessRead},
+		},
+		AllowedSourceRanges: []netip.Prefix{netip.MustParsePrefix("203.0.113.0/24")},
+	}
+
+	mockAuth := &auth.MockManager{
+		MarkPATUsedFunc: mockMarkPATUsed,
+		GetPATInfoFunc: func(_ context.Context, token string) (*types.User, *types.PersonalAccessToken, string, string, error) {
+			if token != PAT {
+				return nil, nil, "", "", fmt.Errorf("PAT invalid")
+			}
+			return testAccount.Users[userID], scopedPAT, testAccount.Domain, testAccount.DomainCategory, nil
+		},
+	}
+
+	authMiddleware := NewAuthMiddleware(mockAuth, nil, nil, nil, nil, nil)
+
+	var gotUserAuth nbauth.UserAuth
+	handlerToTest := authMiddleware.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUserAuth, _ = nbcontext.GetUserAuthFromRequest(r)
+	}))
+
+	tt := []struct {
+		name               string
+		method             string
+		path               string
+		remoteAddr         string
+		expectedStatusCode int
+	}{
+		{
+			name:               "Create setup key",
+			method:             http.MethodPost,
+			path:               "/api/setup-keys",
+			remoteAddr:         "203.0.113.10:4242",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "Read peers",
+			method:             http.MethodGet,
+			path:               "/api/peers/peer-id",
+			remoteAddr:         "203.0.113.10:4242",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "Delete peer with read scope",
+			method:             http.MethodDelete,
+			path:               "/api/peers/peer-id",
+			remoteAddr:         "203.0.113.10:4242",
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name:               "Module outside of the scopes",
+			method:             http.MethodGet,
+			path:               "/api/groups",
+			remoteAddr:         "203.0.113.10:4242",
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name:               "Tokens of the user",
+			method:             http.MethodPost,
+			path:               "/api/users/" + userID + "/tokens",
+			remoteAddr:         "203.0.113.10:4242",
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name:               "Endpoint spanning modules",
+			method:             http.MethodPost,
+			path:               "/api/account-config/apply",
+			remoteAddr:         "203.0.113.10:4242",
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name:               "Source outside of the allowed ranges",
+			method:             http.MethodGet,
+			path:               "/api/peers",
+			remoteAddr:         "198.51.100.10:4242",
+			expectedStatusCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			gotUserAuth = nbauth.UserAuth{}
+
+			req := httptest.NewRequest(tc.method, "http://testing"+tc.path, nil)
+			req.RemoteAddr = tc.remoteAddr
+			req.Header.Set("Authorization", "Token "+PAT)
+			rec := httptest.NewRecorder()
+
+			handlerToTest.ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedStatusCode, rec.Code)
+
+			if tc.expectedStatusCode == http.StatusOK {
+				assert.Equal(t, tokenID, gotUserAuth.PATID)
+				assert.Equal(t, "ci", gotUserAuth.PATName)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/netbirdio/netbird/management/server/permissions/modules"
+	"github.com/netbirdio/netbird/management/server/permissions/operations"
+)
+
+// scopeModules maps the first segment of the API paths to the module the endpoints validate permissions for.
+// Endpoints that are not listed span several modules and can't be used with scoped tokens.
+var scopeModules = map[string]modules.Module{
+	"accounts":                    modules.Accounts,
+	"peers":                       modules.Peers,
+	"users":                       modules.Users,
+	"setup-keys":                  modules.SetupKeys,
+	"groups":                      modules.Groups,
+	"policies":                    modules.Policies,
+	"posture-checks":              modules.Policies,
+	"locations":                   modules.Policies,
+	"routes":                      modules.Routes,
+	"networks":                    modules.Networks,
+	"events":                      modules.Events,
+	"identity-providers":          modules.IdentityProviders,
+	"roles":                       modules.Roles,
+	"access-requests":             modules.AccessRequests,
+	"scim":                        modules.Settings,
+	"workload-identity-providers": modules.SetupKeys,
+	"peer-approvals":              modules.Peers,
+	"peer-approval-rules":         modules.Peers,
+}
+
+// readOnlyPaths are POST endpoints that don't modify anything
+var readOnlyPaths = map[string]struct{}{
+	"policies/simulate": {},
+}
+
+// requestScope returns the module and operation of an API request, the path is relative to the API prefix
+func requestScope(method, path string) (modules.Module, operations.Operation, bool) {
+	path = strings.Trim(path, "/")
+	segments := strings.Split(path, "/")
+
+	var module modules.Module
+	switch {
+	case segments[0] == "users" && len(segments) >= 3 && segments[2] == "tokens":
+		module = modules.Pats
+	case segments[0] == "dns" && len(segments) >= 2 && segments[1] == "nameservers":
+		module = modules.Nameservers
+	case segments[0] == "dns" && len(segments) >= 2 && (segments[1] == "settings" || segments[1] == "zones"):
+		module = modules.Dns
+	default:
+		var ok bool
+		module, ok = scopeModules[segments[0]]
+		if !ok {
+			return "", "", false
+		}
+	}
+
+	if _, ok := readOnlyPaths[path]; ok {
+		return module, operations.Read, true
+	}
+
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return module, operations.Read, true
+	case http.MethodPost:
+		return module, operations.Create, true
+	case http.MethodPut, http.MethodPatch:
+		return module, operations.Update, true
+	case http.MethodDelete:
+		return module, operations.Delete, true
+	default:
+		return "", "", false
+	}
+}
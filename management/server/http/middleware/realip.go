@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+type realIPKey struct{}
+
+// RealIPMiddleware resolves the address of the client of a request behind reverse proxies.
+// It follows the trusted proxy configuration of the gRPC server.
+type RealIPMiddleware struct {
+	trustedPeers        []netip.Prefix
+	trustedProxies      []netip.Prefix
+	trustedProxiesCount uint
+}
+
+// NewRealIPMiddleware instance constructor. The forwarding headers are only used for requests from the trusted peers.
+func NewRealIPMiddleware(trustedPeers, trustedProxies []netip.Prefix, trustedProxiesCount uint) *RealIPMiddleware {
+	return &RealIPMiddleware{
+		trustedPeers:        trustedPeers,
+		trustedProxies:      trustedProxies,
+		trustedProxiesCount: trustedProxiesCount,
+	}
+}
+
+// Handler stores the address of the client in the request context
+func (m *RealIPMiddleware) Handler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		addr, ok := m.clientIP(r)
+		if ok {
+			r = r.WithContext(context.WithValue(r.Context(), realIPKey{}, addr))
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+func (m *RealIPMiddleware) clientIP(r *http.Request) (netip.Addr, bool) {
+	remote, ok := remoteAddr(r)
+	if !ok || !prefixesContain(m.trustedPeers, remote) {
+		return remote, ok
+	}
+
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		ips := strings.Split(forwarded, ",")
+		if addr, ok := m.fromForwardedFor(ips); ok {
+			return addr, true
+		}
+	}
+
+	if addr, err := netip.ParseAddr(strings.TrimSpace(r.Header.Get("X-Real-Ip"))); err == nil {
+		return addr.Unmap(), true
+	}
+
+	return remote, true
+}
+
+// fromForwardedFor picks the client address from the X-Forwarded-For list, skipping the trusted proxies
+// from the right or the configured number of proxies
+func (m *RealIPMiddleware) fromForwardedFor(ips []string) (netip.Addr, bool) {
+	if m.trustedProxiesCount > 0 {
+		idx := len(ips) - int(m.trustedProxiesCount)
+		if idx < 0 {
+			return netip.Addr{}, false
+		}
+		addr, err := netip.ParseAddr(strings.TrimSpace(ips[idx]))
+		return addr.Unmap(), err == nil
+	}
+
+	for i := len(ips) - 1; i >= 0; i-- {
+		addr, err := netip.ParseAddr(strings.TrimSpace(ips[i]))
+		if err != nil {
+			return netip.Addr{}, false
+		}
+		addr = addr.Unmap()
+		if !prefixesContain(m.trustedProxies, addr) {
+			return addr, true
+		}
+	}
+
+	return netip.Addr{}, false
+}
+
+// ClientIPFromRequest returns the address of the client of the request. Without the RealIPMiddleware
+// the address of the connection is returned.
+func ClientIPFromRequest(r *http.Request) (netip.Addr, bool) {
+	if addr, ok := r.Context().Value(realIPKey{}).(netip.Addr); ok {
+		return addr, true
+	}
+	return remoteAddr(r)
+}
+
+func remoteAddr(r *http.Request) (netip.Addr, bool) {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap(), true
+}
+
+func prefixesContain(prefixes []netip.Prefix, addr netip.Addr) bool {
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
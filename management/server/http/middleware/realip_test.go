@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRealIPMiddleware(t *testing.T) {
+	trustedPeers := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+	trustedProxies := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("192.0.2.1/32")}
+
+	tt := []struct {
+		name       string
+		middleware *RealIPMiddleware
+		remoteAddr string
+		headers    map[string]string
+		expected   string
+	}{
+		{
+			name:       "No proxy configuration",
+			middleware: NewRealIPMiddleware(nil, nil, 0),
+			remoteAddr: "203.0.113.10:4242",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.1"},
+			expected:   "203.0.113.10",
+		},
+		{
+			name:       "Untrusted peer",
+			middleware: NewRealIPMiddleware(trustedPeers, trustedProxies, 0),
+			remoteAddr: "203.0.113.10:4242",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.1"},
+			expected:   "203.0.113.10",
+		},
+		{
+			name:       "Trusted proxies",
+			middleware: NewRealIPMiddleware(trustedPeers, trustedProxies, 0),
+			remoteAddr: "10.0.0.2:4242",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.1, 203.0.113.7, 192.0.2.1"},
+			expected:   "203.0.113.7",
+		},
+		{
+			name:       "Trusted proxies count",
+			middleware: NewRealIPMiddleware(trustedPeers, nil, 2),
+			remoteAddr: "10.0.0.2:4242",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.1, 203.0.113.7, 192.0.2.1"},
+			expected:   "203.0.113.7",
+		},
+		{
+			name:       "X-Real-Ip",
+			middleware: NewRealIPMiddleware(trustedPeers, nil, 0),
+			remoteAddr: "10.0.0.2:4242",
+			headers:    map[string]string{"X-Real-Ip": "198.51.100.1"},
+			expected:   "198.51.100.1",
+		},
+		{
+			name:       "IPv4 mapped IPv6 connection",
+			middleware: NewRealIPMiddleware(nil, nil, 0),
+			remoteAddr: "[::ffff:203.0.113.10]:4242",
+			expected:   "203.0.113.10",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var got netip.Addr
+			var ok bool
+			handler := tc.middleware.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got, ok = ClientIPFromRequest(r)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "http://testing/api/peers", nil)
+			req.RemoteAddr = tc.remoteAddr
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			require.True(t, ok)
+			assert.Equal(t, tc.expected, got.String())
+		})
+	}
+}
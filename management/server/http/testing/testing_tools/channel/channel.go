@@ -10,6 +10,12 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/netbirdio/management-integrations/integrations"
+	accessRequestsManager "github.com/netbirdio/netbird/management/internals/modules/accessrequests/manager"
+	accountConfigManager "github.com/netbirdio/netbird/management/internals/modules/accountconfig/manager"
+	customRolesManager "github.com/netbirdio/netbird/management/internals/modules/customroles/manager"
+	peerApprovalManager "github.com/netbirdio/netbird/management/internals/modules/peerapproval/manager"
+	scimManager "github.com/netbirdio/netbird/management/internals/modules/scim/manager"
+	workloadIdentityManager "github.com/netbirdio/netbird/management/internals/modules/workloadidentity/manager"
 	zonesManager "github.com/netbirdio/netbird/management/internals/modules/zones/manager"
 	recordsManager "github.com/netbirdio/netbird/management/internals/modules/zones/records/manager"
 	"github.com/netbirdio/netbird/management/internals/server/config"
@@ -97,8 +103,14 @@ func BuildApiBlackBoxWithDBState(t testing_tools.TB, sqlFile string, expectedPee
 	peersManager := peers.NewManager(store, permissionsManager)
 	customZonesManager := zonesManager.NewManager(store, am, permissionsManager, "")
 	zoneRecordsManager := recordsManager.NewManager(store, am, permissionsManager)
-
-	apiHandler, err := http2.NewAPIHandler(context.Background(), am, networksManagerMock, resourcesManagerMock, routersManagerMock, groupsManagerMock, geoMock, authManagerMock, metrics, validatorMock, proxyController, permissionsManager, peersManager, settingsManager, customZonesManager, zoneRecordsManager, networkMapController, nil)
+	rolesManager := customRolesManager.NewManager(store, am, permissionsManager)
+	requestsManager := accessRequestsManager.NewManager(store, am, permissionsManager)
+	configManager := accountConfigManager.NewManager(am, networksManagerMock, resourcesManagerMock, routersManagerMock, customZonesManager, zoneRecordsManager)
+	provisioningManager := scimManager.NewManager(store, am, permissionsManager)
+	workloadManager := workloadIdentityManager.NewManager(store, am, permissionsManager, nil)
+	approvalManager := peerApprovalManager.NewManager(store, am, permissionsManager)
+
+	apiHandler, err := http2.NewAPIHandler(context.Background(), am, networksManagerMock, resourcesManagerMock, routersManagerMock, groupsManagerMock, geoMock, authManagerMock, metrics, validatorMock, proxyController, permissionsManager, peersManager, settingsManager, customZonesManager, zoneRecordsManager, rolesManager, requestsManager, configManager, provisioningManager, workloadManager, approvalManager, networkMapController, nil)
 	if err != nil {
 		t.Fatalf("Failed to create API handler: %v", err)
 	}
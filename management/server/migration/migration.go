@@ -359,6 +359,34 @@ func MigrateNewField[T any](ctx context.Context, db *gorm.DB, columnName string,
 	return nil
 }
 
+// MigrateEmptyJSONStringToNull sets the column to NULL where it holds an empty JSON string,
+// so the rows don't collide in a uniqueness index on that column.
+func MigrateEmptyJSONStringToNull[T any](ctx context.Context, db *gorm.DB, columnName string) error {
+	var model T
+
+	if !db.Migrator().HasTable(&model) {
+		log.WithContext(ctx).Debugf("Table for %T does not exist, no migration needed", model)
+		return nil
+	}
+
+	stmt := &gorm.Statement{DB: db}
+	err := stmt.Parse(&model)
+	if err != nil {
+		return fmt.Errorf("parse model: %w", err)
+	}
+	tableName := stmt.Schema.Table
+
+	result := db.Table(tableName).Where(columnName+" = ?", `""`).Update(columnName, nil)
+	if result.Error != nil {
+		return fmt.Errorf("set empty %s to NULL in table %s: %w", columnName, tableName, result.Error)
+	}
+
+	if result.RowsAffected > 0 {
+		log.WithContext(ctx).Infof("Migration of %d empty %s values to NULL in table %s completed", result.RowsAffected, columnName, tableName)
+	}
+	return nil
+}
+
 func DropIndex[T any](ctx context.Context, db *gorm.DB, indexName string) error {
 	var model T
 
@@ -405,7 +433,7 @@ func CreateIndexIfNotExists[T any](ctx context.Context, db *gorm.DB, indexName s
 		var withLength []string
 		for _, col := range columns {
 			quotedCol := fmt.Sprintf("`%s`", col)
-			if col == "ip" || col == "dns_label" || col == "key" {
+			if col == "ip" || col == "ipv6" || col == "dns_label" || col == "key" {
 				withLength = append(withLength, fmt.Sprintf("%s(64)", quotedCol))
 			} else {
 				withLength = append(withLength, quotedCol)
@@ -212,6 +212,32 @@ func TestMigrateNetIPFieldFromBlobToJSON_WithJSONData(t *testing.T) {
 	assert.JSONEq(t, `"10.0.0.1"`, jsonStr, "Data should be unchanged")
 }
 
+func TestMigrateEmptyJSONStringToNull(t *testing.T) {
+	db := setupDatabase(t)
+
+	err := db.AutoMigrate(&nbpeer.Peer{})
+	require.NoError(t, err, "Failed to auto-migrate tables")
+
+	err = db.Create(&nbpeer.Peer{ID: "ipv6-empty", AccountID: "ipv6-account", Key: "ipv6-empty", IP: net.IP{10, 0, 1, 1}}).Error
+	require.NoError(t, err, "Failed to insert peer")
+	err = db.Model(&nbpeer.Peer{}).Where("id = ?", "ipv6-empty").Update("ipv6", `""`).Error
+	require.NoError(t, err, "Failed to set empty JSON string")
+
+	err = db.Create(&nbpeer.Peer{ID: "ipv6-set", AccountID: "ipv6-account", Key: "ipv6-set", IP: net.IP{10, 0, 1, 2}, IPv6: net.ParseIP("fd00::1")}).Error
+	require.NoError(t, err, "Failed to insert peer")
+
+	err = migration.MigrateEmptyJSONStringToNull[nbpeer.Peer](context.Background(), db, "ipv6")
+	require.NoError(t, err, "Migration should not fail")
+
+	var count int64
+	db.Model(&nbpeer.Peer{}).Where("account_id = ? AND ipv6 IS NULL", "ipv6-account").Count(&count)
+	assert.Equal(t, int64(1), count, "Empty IPv6 should be migrated to NULL")
+
+	var jsonStr string
+	db.Model(&nbpeer.Peer{}).Select("ipv6").Where("id = ?", "ipv6-set").First(&jsonStr)
+	assert.JSONEq(t, `"fd00::1"`, jsonStr, "Set IPv6 should be unchanged")
+}
+
 func TestMigrateSetupKeyToHashedSetupKey_ForPlainKey(t *testing.T) {
 	db := setupDatabase(t)
 
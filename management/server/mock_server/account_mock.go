@@ -30,7 +30,8 @@ type MockAccountManager struct {
 	GetOrCreateAccountByUserFunc func(ctx context.Context, userAuth auth.UserAuth) (*types.Account, error)
 	GetAccountFunc               func(ctx context.Context, accountID string) (*types.Account, error)
 	CreateSetupKeyFunc           func(ctx context.Context, accountId string, keyName string, keyType types.SetupKeyType,
-		expiresIn time.Duration, autoGroups []string, usageLimit int, userID string, ephemeral bool, allowExtraDNSLabels bool) (*types.SetupKey, error)
+		expiresIn time.Duration, autoGroups []string, usageLimit int, userID string, ephemeral bool, allowExtraDNSLabels bool,
+		constraints types.SetupKeyConstraints) (*types.SetupKey, error)
 	GetSetupKeyFunc                       func(ctx context.Context, accountID, userID, keyID string) (*types.SetupKey, error)
 	AccountExistsFunc                     func(ctx context.Context, accountID string) (bool, error)
 	GetAccountIDByUserIdFunc              func(ctx context.Context, userAuth auth.UserAuth) (string, error)
@@ -40,6 +41,8 @@ type MockAccountManager struct {
 	MarkPeerConnectedFunc                 func(ctx context.Context, peerKey string, connected bool, realIP net.IP) error
 	SyncAndMarkPeerFunc                   func(ctx context.Context, accountID string, peerPubKey string, meta nbpeer.PeerSystemMeta, realIP net.IP) (*nbpeer.Peer, *types.NetworkMap, []*posture.Checks, int64, error)
 	DeletePeerFunc                        func(ctx context.Context, accountID, peerKey, userID string) error
+	ApprovePendingPeersFunc               func(ctx context.Context, accountID, userID string, peerIDs []string) ([]*nbpeer.Peer, error)
+	RejectPendingPeersFunc                func(ctx context.Context, accountID, userID string, peerIDs []string) ([]*nbpeer.Peer, error)
 	GetNetworkMapFunc                     func(ctx context.Context, peerKey string) (*types.NetworkMap, error)
 	GetPeerNetworkFunc                    func(ctx context.Context, peerKey string) (*types.Network, error)
 	AddPeerFunc                           func(ctx context.Context, accountID string, setupKey string, userId string, peer *nbpeer.Peer, temporary bool) (*nbpeer.Peer, *types.NetworkMap, []*posture.Checks, error)
@@ -56,8 +59,10 @@ type MockAccountManager struct {
 	DeleteRuleFunc                        func(ctx context.Context, accountID, ruleID, userID string) error
 	GetPolicyFunc                         func(ctx context.Context, accountID, policyID, userID string) (*types.Policy, error)
 	SavePolicyFunc                        func(ctx context.Context, accountID, userID string, policy *types.Policy, create bool) (*types.Policy, error)
+	DryRunSavePolicyFunc                  func(ctx context.Context, accountID, userID string, policy *types.Policy, create bool) ([]*types.PeerNetworkMapDiff, error)
 	DeletePolicyFunc                      func(ctx context.Context, accountID, policyID, userID string) error
 	ListPoliciesFunc                      func(ctx context.Context, accountID, userID string) ([]*types.Policy, error)
+	SimulatePolicyFunc                    func(ctx context.Context, accountID, userID string, simulation types.PolicySimulation, policies []*types.Policy) (*types.PolicySimulationResult, error)
 	GetUsersFromAccountFunc               func(ctx context.Context, accountID, userID string) (map[string]*types.UserInfo, error)
 	UpdatePeerMetaFunc                    func(ctx context.Context, peerID string, meta nbpeer.PeerSystemMeta) error
 	UpdatePeerFunc                        func(ctx context.Context, accountID, userID string, peer *nbpeer.Peer) (*nbpeer.Peer, error)
@@ -74,7 +79,7 @@ type MockAccountManager struct {
 	SaveOrAddUsersFunc                    func(ctx context.Context, accountID, initiatorUserID string, update []*types.User, addIfNotExists bool) ([]*types.UserInfo, error)
 	DeleteUserFunc                        func(ctx context.Context, accountID string, initiatorUserID string, targetUserID string) error
 	DeleteRegularUsersFunc                func(ctx context.Context, accountID, initiatorUserID string, targetUserIDs []string, userInfos map[string]*types.UserInfo) error
-	CreatePATFunc                         func(ctx context.Context, accountID string, initiatorUserID string, targetUserId string, tokenName string, expiresIn int) (*types.PersonalAccessTokenGenerated, error)
+	CreatePATFunc                         func(ctx context.Context, accountID string, initiatorUserID string, targetUserId string, tokenName string, expiresIn int, scopes []types.PATScope, allowedSourceRanges []netip.Prefix) (*types.PersonalAccessTokenGenerated, error)
 	DeletePATFunc                         func(ctx context.Context, accountID string, initiatorUserID string, targetUserId string, tokenID string) error
 	GetPATFunc                            func(ctx context.Context, accountID string, initiatorUserID string, targetUserId string, tokenID string) (*types.PersonalAccessToken, error)
 	GetAllPATsFunc                        func(ctx context.Context, accountID string, initiatorUserID string, targetUserId string) ([]*types.PersonalAccessToken, error)
@@ -92,6 +97,8 @@ type MockAccountManager struct {
 	GetDNSSettingsFunc                    func(ctx context.Context, accountID, userID string) (*types.DNSSettings, error)
 	SaveDNSSettingsFunc                   func(ctx context.Context, accountID, userID string, dnsSettingsToSave *types.DNSSettings) error
 	GetPeerFunc                           func(ctx context.Context, accountID, peerID, userID string) (*nbpeer.Peer, error)
+	GetPeerPostureCheckResultsFunc        func(ctx context.Context, accountID, peerID, userID string) ([]posture.Result, error)
+	GetPeersPostureCheckResultsFunc       func(ctx context.Context, accountID, userID string, peers []*nbpeer.Peer) (map[string][]posture.Result, error)
 	UpdateAccountSettingsFunc             func(ctx context.Context, accountID, userID string, newSettings *types.Settings) (*types.Settings, error)
 	LoginPeerFunc                         func(ctx context.Context, login types.PeerLogin) (*nbpeer.Peer, *types.NetworkMap, []*posture.Checks, error)
 	SyncPeerFunc                          func(ctx context.Context, sync types.PeerSync, accountID string) (*nbpeer.Peer, *types.NetworkMap, []*posture.Checks, int64, error)
@@ -106,6 +113,7 @@ type MockAccountManager struct {
 	DeletePostureChecksFunc               func(ctx context.Context, accountID, postureChecksID, userID string) error
 	ListPostureChecksFunc                 func(ctx context.Context, accountID, userID string) ([]*posture.Checks, error)
 	GetIdpManagerFunc                     func() idp.Manager
+	AddAccountDeletedListenerFunc         func(listener func(ctx context.Context, accountID string))
 	UpdateIntegratedValidatorFunc         func(ctx context.Context, accountID, userID, validator string, groups []string) error
 	GroupValidationFunc                   func(ctx context.Context, accountId string, groups []string) (bool, error)
 	SyncPeerMetaFunc                      func(ctx context.Context, peerPubKey string, meta nbpeer.PeerSystemMeta) error
@@ -241,6 +249,22 @@ func (am *MockAccountManager) DeletePeer(ctx context.Context, accountID, peerID,
 	return status.Errorf(codes.Unimplemented, "method DeletePeer is not implemented")
 }
 
+// ApprovePendingPeers mock implementation of ApprovePendingPeers from server.AccountManager interface
+func (am *MockAccountManager) ApprovePendingPeers(ctx context.Context, accountID, userID string, peerIDs []string) ([]*nbpeer.Peer, error) {
+	if am.ApprovePendingPeersFunc != nil {
+		return am.ApprovePendingPeersFunc(ctx, accountID, userID, peerIDs)
+	}
+	return nil, status.Errorf(codes.Unimplemented, "method ApprovePendingPeers is not implemented")
+}
+
+// RejectPendingPeers mock implementation of RejectPendingPeers from server.AccountManager interface
+func (am *MockAccountManager) RejectPendingPeers(ctx context.Context, accountID, userID string, peerIDs []string) ([]*nbpeer.Peer, error) {
+	if am.RejectPendingPeersFunc != nil {
+		return am.RejectPendingPeersFunc(ctx, accountID, userID, peerIDs)
+	}
+	return nil, status.Errorf(codes.Unimplemented, "method RejectPendingPeers is not implemented")
+}
+
 // GetOrCreateAccountByUser mock implementation of GetOrCreateAccountByUser from server.AccountManager interface
 func (am *MockAccountManager) GetOrCreateAccountByUser(
 	ctx context.Context, userAuth auth.UserAuth,
@@ -266,9 +290,10 @@ func (am *MockAccountManager) CreateSetupKey(
 	userID string,
 	ephemeral bool,
 	allowExtraDNSLabels bool,
+	constraints types.SetupKeyConstraints,
 ) (*types.SetupKey, error) {
 	if am.CreateSetupKeyFunc != nil {
-		return am.CreateSetupKeyFunc(ctx, accountID, keyName, keyType, expiresIn, autoGroups, usageLimit, userID, ephemeral, allowExtraDNSLabels)
+		return am.CreateSetupKeyFunc(ctx, accountID, keyName, keyType, expiresIn, autoGroups, usageLimit, userID, ephemeral, allowExtraDNSLabels, constraints)
 	}
 	return nil, status.Errorf(codes.Unimplemented, "method CreateSetupKey is not implemented")
 }
@@ -309,9 +334,9 @@ func (am *MockAccountManager) DeleteAccount(ctx context.Context, accountID, user
 }
 
 // CreatePAT mock implementation of GetPAT from server.AccountManager interface
-func (am *MockAccountManager) CreatePAT(ctx context.Context, accountID string, initiatorUserID string, targetUserID string, name string, expiresIn int) (*types.PersonalAccessTokenGenerated, error) {
+func (am *MockAccountManager) CreatePAT(ctx context.Context, accountID string, initiatorUserID string, targetUserID string, name string, expiresIn int, scopes []types.PATScope, allowedSourceRanges []netip.Prefix) (*types.PersonalAccessTokenGenerated, error) {
 	if am.CreatePATFunc != nil {
-		return am.CreatePATFunc(ctx, accountID, initiatorUserID, targetUserID, name, expiresIn)
+		return am.CreatePATFunc(ctx, accountID, initiatorUserID, targetUserID, name, expiresIn, scopes, allowedSourceRanges)
 	}
 	return nil, status.Errorf(codes.Unimplemented, "method CreatePAT is not implemented")
 }
@@ -451,6 +476,14 @@ func (am *MockAccountManager) SavePolicy(ctx context.Context, accountID, userID
 	return nil, status.Errorf(codes.Unimplemented, "method SavePolicy is not implemented")
 }
 
+// DryRunSavePolicy mock implementation of DryRunSavePolicy from server.AccountManager interface
+func (am *MockAccountManager) DryRunSavePolicy(ctx context.Context, accountID, userID string, policy *types.Policy, create bool) ([]*types.PeerNetworkMapDiff, error) {
+	if am.DryRunSavePolicyFunc != nil {
+		return am.DryRunSavePolicyFunc(ctx, accountID, userID, policy, create)
+	}
+	return nil, status.Errorf(codes.Unimplemented, "method DryRunSavePolicy is not implemented")
+}
+
 // DeletePolicy mock implementation of DeletePolicy from server.AccountManager interface
 func (am *MockAccountManager) DeletePolicy(ctx context.Context, accountID, policyID, userID string) error {
 	if am.DeletePolicyFunc != nil {
@@ -467,6 +500,14 @@ func (am *MockAccountManager) ListPolicies(ctx context.Context, accountID, userI
 	return nil, status.Errorf(codes.Unimplemented, "method ListPolicies is not implemented")
 }
 
+// SimulatePolicy mock implementation of SimulatePolicy from server.AccountManager interface
+func (am *MockAccountManager) SimulatePolicy(ctx context.Context, accountID, userID string, simulation types.PolicySimulation, policies []*types.Policy) (*types.PolicySimulationResult, error) {
+	if am.SimulatePolicyFunc != nil {
+		return am.SimulatePolicyFunc(ctx, accountID, userID, simulation, policies)
+	}
+	return nil, status.Errorf(codes.Unimplemented, "method SimulatePolicy is not implemented")
+}
+
 // UpdatePeerMeta mock implementation of UpdatePeerMeta from server.AccountManager interface
 func (am *MockAccountManager) UpdatePeerMeta(ctx context.Context, peerID string, meta nbpeer.PeerSystemMeta) error {
 	if am.UpdatePeerMetaFunc != nil {
@@ -736,6 +777,22 @@ func (am *MockAccountManager) GetPeer(ctx context.Context, accountID, peerID, us
 	return nil, status.Errorf(codes.Unimplemented, "method GetPeer is not implemented")
 }
 
+// GetPeerPostureCheckResults mocks GetPeerPostureCheckResults of the AccountManager interface
+func (am *MockAccountManager) GetPeerPostureCheckResults(ctx context.Context, accountID, peerID, userID string) ([]posture.Result, error) {
+	if am.GetPeerPostureCheckResultsFunc != nil {
+		return am.GetPeerPostureCheckResultsFunc(ctx, accountID, peerID, userID)
+	}
+	return nil, status.Errorf(codes.Unimplemented, "method GetPeerPostureCheckResults is not implemented")
+}
+
+// GetPeersPostureCheckResults mocks GetPeersPostureCheckResults of the AccountManager interface
+func (am *MockAccountManager) GetPeersPostureCheckResults(ctx context.Context, accountID, userID string, peers []*nbpeer.Peer) (map[string][]posture.Result, error) {
+	if am.GetPeersPostureCheckResultsFunc != nil {
+		return am.GetPeersPostureCheckResultsFunc(ctx, accountID, userID, peers)
+	}
+	return nil, status.Errorf(codes.Unimplemented, "method GetPeersPostureCheckResults is not implemented")
+}
+
 // UpdateAccountSettings mocks UpdateAccountSettings of the AccountManager interface
 func (am *MockAccountManager) UpdateAccountSettings(ctx context.Context, accountID, userID string, newSettings *types.Settings) (*types.Settings, error) {
 	if am.UpdateAccountSettingsFunc != nil {
@@ -833,6 +890,17 @@ func (am *MockAccountManager) GetIdpManager() idp.Manager {
 	return nil
 }
 
+// AddAccountDeletedListener mocks AddAccountDeletedListener of the AccountManager interface
+func (am *MockAccountManager) AddAccountDeletedListener(listener func(ctx context.Context, accountID string)) {
+	if am.AddAccountDeletedListenerFunc != nil {
+		am.AddAccountDeletedListenerFunc(listener)
+	}
+}
+
+// Stop mocks Stop of the AccountManager interface
+func (am *MockAccountManager) Stop() {
+}
+
 // UpdateIntegratedValidator mocks UpdateIntegratedApprovalGroups of the AccountManager interface
 func (am *MockAccountManager) UpdateIntegratedValidator(ctx context.Context, accountID, userID, validator string, groups []string) error {
 	if am.UpdateIntegratedValidatorFunc != nil {
@@ -2,11 +2,7 @@ package server
 
 import (
 	"context"
-	"errors"
-	"regexp"
-	"unicode/utf8"
 
-	"github.com/miekg/dns"
 	"github.com/rs/xid"
 
 	nbdns "github.com/netbirdio/netbird/dns"
@@ -18,10 +14,6 @@ import (
 	"github.com/netbirdio/netbird/shared/management/status"
 )
 
-const domainPattern = `^(?i)[a-z0-9]+([\-\.]{1}[a-z0-9]+)*[*.a-z]{1,}$`
-
-var errInvalidDomainName = errors.New("invalid domain name")
-
 // GetNameServerGroup gets a nameserver group object from account and nameserver group IDs
 func (am *DefaultAccountManager) GetNameServerGroup(ctx context.Context, accountID, userID, nsGroupID string) (*nbdns.NameServerGroup, error) {
 	allowed, err := am.permissionsManager.ValidateUserPermissions(ctx, accountID, userID, modules.Nameservers, operations.Read)
@@ -197,12 +189,7 @@ func (am *DefaultAccountManager) ListNameServerGroups(ctx context.Context, accou
 }
 
 func validateNameServerGroup(ctx context.Context, transaction store.Store, accountID string, nameserverGroup *nbdns.NameServerGroup) error {
-	err := validateDomainInput(nameserverGroup.Primary, nameserverGroup.Domains, nameserverGroup.SearchDomainsEnabled)
-	if err != nil {
-		return err
-	}
-
-	err = validateNSList(nameserverGroup.NameServers)
+	err := nameserverGroup.Validate()
 	if err != nil {
 		return err
 	}
@@ -243,34 +230,7 @@ func areNameServerGroupChangesAffectPeers(ctx context.Context, transaction store
 	return anyGroupHasPeersOrResources(ctx, transaction, oldNSGroup.AccountID, oldNSGroup.Groups)
 }
 
-func validateDomainInput(primary bool, domains []string, searchDomainsEnabled bool) error {
-	if !primary && len(domains) == 0 {
-		return status.Errorf(status.InvalidArgument, "nameserver group primary status is false and domains are empty,"+
-			" it should be primary or have at least one domain")
-	}
-	if primary && len(domains) != 0 {
-		return status.Errorf(status.InvalidArgument, "nameserver group primary status is true and domains are not empty,"+
-			" you should set either primary or domain")
-	}
-
-	if primary && searchDomainsEnabled {
-		return status.Errorf(status.InvalidArgument, "nameserver group primary status is true and search domains is enabled,"+
-			" you should not set search domains for primary nameservers")
-	}
-
-	for _, domain := range domains {
-		if err := validateDomain(domain); err != nil {
-			return status.Errorf(status.InvalidArgument, "nameserver group got an invalid domain: %s %q", domain, err)
-		}
-	}
-	return nil
-}
-
 func validateNSGroupName(name, nsGroupID string, groups []*nbdns.NameServerGroup) error {
-	if utf8.RuneCountInString(name) > nbdns.MaxGroupNameChar || name == "" {
-		return status.Errorf(status.InvalidArgument, "nameserver group name should be between 1 and %d", nbdns.MaxGroupNameChar)
-	}
-
 	for _, nsGroup := range groups {
 		if name == nsGroup.Name && nsGroup.ID != nsGroupID {
 			return status.Errorf(status.InvalidArgument, "nameserver group with name %s already exist", name)
@@ -280,14 +240,6 @@ func validateNSGroupName(name, nsGroupID string, groups []*nbdns.NameServerGroup
 	return nil
 }
 
-func validateNSList(list []nbdns.NameServer) error {
-	nsListLength := len(list)
-	if nsListLength == 0 || nsListLength > 3 {
-		return status.Errorf(status.InvalidArgument, "the list of nameservers should be 1 or 3, got %d", len(list))
-	}
-	return nil
-}
-
 func validateGroups(list []string, groups map[string]*types.Group) error {
 	if len(list) == 0 {
 		return status.Errorf(status.InvalidArgument, "the list of group IDs should not be empty")
@@ -304,18 +256,3 @@ func validateGroups(list []string, groups map[string]*types.Group) error {
 
 	return nil
 }
-
-var domainMatcher = regexp.MustCompile(domainPattern)
-
-func validateDomain(domain string) error {
-	if !domainMatcher.MatchString(domain) {
-		return errors.New("domain should consists of only letters, numbers, and hyphens with no leading, trailing hyphens, or spaces")
-	}
-
-	_, valid := dns.IsDomainName(domain)
-	if !valid {
-		return errInvalidDomainName
-	}
-
-	return nil
-}
@@ -15,6 +15,7 @@ import (
 	"golang.org/x/exp/maps"
 
 	nbdns "github.com/netbirdio/netbird/dns"
+	"github.com/netbirdio/netbird/management/internals/modules/peerapproval"
 	"github.com/netbirdio/netbird/management/server/geolocation"
 	"github.com/netbirdio/netbird/management/server/idp"
 	routerTypes "github.com/netbirdio/netbird/management/server/networks/routers/types"
@@ -415,7 +416,12 @@ func (am *DefaultAccountManager) GetPeerNetwork(ctx context.Context, peerID stri
 // Each new Peer will be assigned a new next net.IP from the Account.Network and Account.Network.LastIP will be updated (IP's are not reused).
 // The peer property is just a placeholder for the Peer properties to pass further
 func (am *DefaultAccountManager) AddPeer(ctx context.Context, accountID, setupKey, userID string, peer *nbpeer.Peer, temporary bool) (*nbpeer.Peer, *types.NetworkMap, []*posture.Checks, error) {
-	if setupKey == "" && userID == "" {
+	return am.addPeer(ctx, accountID, setupKey, userID, nil, peer, temporary)
+}
+
+// addPeer registers the peer authenticated by a setup key, a user or a workload identity
+func (am *DefaultAccountManager) addPeer(ctx context.Context, accountID, setupKey, userID string, workload *types.WorkloadIdentity, peer *nbpeer.Peer, temporary bool) (*nbpeer.Peer, *types.NetworkMap, []*posture.Checks, error) {
+	if setupKey == "" && userID == "" && workload == nil {
 		// no auth method provided => reject access
 		return nil, nil, nil, status.Errorf(status.Unauthenticated, "no peer auth method provided, please use a setup key or interactive SSO login")
 	}
@@ -469,6 +475,16 @@ func (am *DefaultAccountManager) AddPeer(ctx context.Context, accountID, setupKe
 		}
 		opEvent.InitiatorID = userID
 		opEvent.Activity = activity.PeerAddedByUser
+	} else if workload != nil {
+		if len(peer.ExtraDNSLabels) > 0 {
+			return nil, nil, nil, status.Errorf(status.PreconditionFailed, "couldn't add peer: workload identities don't allow extra DNS labels")
+		}
+
+		opEvent.InitiatorID = workload.ProviderID
+		opEvent.Activity = activity.PeerAddedWithWorkloadIdentity
+		accountID = workload.AccountID
+		groupsToAdd = workload.AutoGroups
+		ephemeral = workload.Ephemeral
 	} else {
 		// Validate the setup key
 		sk, err := am.Store.GetSetupKeyBySecret(ctx, store.LockingStrengthNone, encodedHashedKey)
@@ -481,6 +497,11 @@ func (am *DefaultAccountManager) AddPeer(ctx context.Context, accountID, setupKe
 			return nil, nil, nil, status.Errorf(status.NotFound, "couldn't add peer: setup key is invalid")
 		}
 
+		if err = sk.Constraints.Check(peer.Location.ConnectionIP, peer.Meta.Hostname, peer.Meta.GoOS, time.Now().UTC()); err != nil {
+			am.storeSetupKeyRejectedEvent(ctx, sk, peer, err)
+			return nil, nil, nil, status.Errorf(status.PermissionDenied, "couldn't add peer: %v", err)
+		}
+
 		opEvent.InitiatorID = sk.Id
 		opEvent.Activity = activity.PeerAddedWithSetupKey
 		groupsToAdd = sk.AutoGroups
@@ -550,6 +571,18 @@ func (am *DefaultAccountManager) AddPeer(ctx context.Context, accountID, setupKe
 
 	newPeer = am.integratedPeerValidator.PreparePeer(ctx, accountID, newPeer, groupsToAdd, settings.Extra, temporary)
 
+	var approvalRule *peerapproval.Rule
+	if newPeer.Status.RequiresApproval {
+		approvalRule, err = am.getMatchingPeerApprovalRule(ctx, accountID, userID, setupKeyID, newPeer)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if approvalRule != nil && !am.validateAutoApproval(ctx, accountID, userID, newPeer, groupsToAdd, settings) {
+			approvalRule = nil
+		}
+		newPeer.Status.RequiresApproval = approvalRule == nil
+	}
+
 	network, err := am.Store.GetAccountNetwork(ctx, store.LockingStrengthNone, accountID)
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("failed getting network: %w", err)
@@ -578,6 +611,19 @@ func (am *DefaultAccountManager) AddPeer(ctx context.Context, accountID, setupKe
 		newPeer.DNSLabel = freeLabel
 		newPeer.IP = freeIP
 
+		if settings.NetworkRangeV6.IsValid() {
+			var takenIPv6s []net.IP
+			takenIPv6s, err = am.Store.GetTakenIPv6s(ctx, store.LockingStrengthNone, accountID)
+			if err != nil {
+				return nil, nil, nil, fmt.Errorf("failed getting taken IPv6 addresses: %w", err)
+			}
+
+			newPeer.IPv6, err = types.AllocatePeerIPv6(settings.NetworkRangeV6, takenIPv6s)
+			if err != nil {
+				return nil, nil, nil, fmt.Errorf("failed to get free IPv6: %w", err)
+			}
+		}
+
 		err = am.Store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
 			err = transaction.AddPeerToAccount(ctx, newPeer)
 			if err != nil {
@@ -603,7 +649,7 @@ func (am *DefaultAccountManager) AddPeer(ctx context.Context, accountID, setupKe
 				if err != nil {
 					log.WithContext(ctx).Debugf("failed to update user last login: %v", err)
 				}
-			} else {
+			} else if workload == nil {
 				sk, err := transaction.GetSetupKeyBySecret(ctx, store.LockingStrengthUpdate, encodedHashedKey)
 				if err != nil {
 					return fmt.Errorf("failed to get setup key: %w", err)
@@ -620,6 +666,20 @@ func (am *DefaultAccountManager) AddPeer(ctx context.Context, accountID, setupKe
 				}
 			}
 
+			if newPeer.Status.RequiresApproval {
+				err = transaction.SavePeerApprovalRequest(ctx, &peerapproval.Request{
+					PeerID:       newPeer.ID,
+					AccountID:    accountID,
+					UserID:       userID,
+					SetupKeyID:   setupKeyID,
+					SetupKeyName: setupKeyName,
+					CreatedAt:    registrationTime,
+				})
+				if err != nil {
+					return fmt.Errorf("failed to save peer approval request: %w", err)
+				}
+			}
+
 			err = transaction.IncrementNetworkSerial(ctx, accountID)
 			if err != nil {
 				return fmt.Errorf("failed to increment network serial: %w", err)
@@ -633,7 +693,7 @@ func (am *DefaultAccountManager) AddPeer(ctx context.Context, accountID, setupKe
 		}
 
 		if isUniqueConstraintError(err) {
-			log.WithContext(ctx).WithFields(log.Fields{"dns_label": freeLabel, "ip": freeIP}).Tracef("Failed to add peer in attempt %d, retrying: %v", attempt, err)
+			log.WithContext(ctx).WithFields(log.Fields{"dns_label": freeLabel, "ip": freeIP, "ipv6": newPeer.IPv6}).Tracef("Failed to add peer in attempt %d, retrying: %v", attempt, err)
 			continue
 		}
 
@@ -649,12 +709,23 @@ func (am *DefaultAccountManager) AddPeer(ctx context.Context, accountID, setupKe
 
 	opEvent.TargetID = newPeer.ID
 	opEvent.Meta = newPeer.EventMeta(am.networkMapController.GetDNSDomain(settings))
-	if !addedByUser {
+	switch {
+	case workload != nil:
+		opEvent.Meta["workload_identity_provider"] = workload.ProviderName
+		opEvent.Meta["workload_subject"] = workload.Subject
+	case !addedByUser:
 		opEvent.Meta["setup_key_name"] = setupKeyName
 	}
 
 	am.StoreEvent(ctx, opEvent.InitiatorID, opEvent.TargetID, opEvent.AccountID, opEvent.Activity, opEvent.Meta)
 
+	if approvalRule != nil {
+		meta := newPeer.EventMeta(am.networkMapController.GetDNSDomain(settings))
+		meta["rule_id"] = approvalRule.ID
+		meta["rule_name"] = approvalRule.Name
+		am.StoreEvent(ctx, activity.SystemInitiator, newPeer.ID, accountID, activity.PeerAutoApproved, meta)
+	}
+
 	if err := am.networkMapController.OnPeersAdded(ctx, accountID, []string{newPeer.ID}); err != nil {
 		log.WithContext(ctx).Errorf("failed to update network map cache for peer %s: %v", newPeer.ID, err)
 	}
@@ -663,6 +734,208 @@ func (am *DefaultAccountManager) AddPeer(ctx context.Context, accountID, setupKe
 	return p, nmap, pc, err
 }
 
+// getMatchingPeerApprovalRule returns the first peer approval rule of the account matching the new peer, nil if none does
+func (am *DefaultAccountManager) getMatchingPeerApprovalRule(ctx context.Context, accountID, userID, setupKeyID string, peer *nbpeer.Peer) (*peerapproval.Rule, error) {
+	rules, err := am.Store.GetAccountPeerApprovalRules(ctx, store.LockingStrengthNone, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	var userGroups []string
+	if userID != "" {
+		user, err := am.Store.GetUserByUserID(ctx, store.LockingStrengthNone, userID)
+		if err != nil {
+			return nil, err
+		}
+		userGroups = user.AutoGroups
+	}
+
+	return peerapproval.MatchRule(rules, peer, userGroups, setupKeyID), nil
+}
+
+// validateAutoApproval lets the integrated validator accept the approval of a new peer matching a peer approval rule,
+// the peer stays pending approval if the validator doesn't accept it
+func (am *DefaultAccountManager) validateAutoApproval(ctx context.Context, accountID, userID string, peer *nbpeer.Peer, peerGroups []string, settings *types.Settings) bool {
+	update := peer.Copy()
+	update.Status.RequiresApproval = false
+
+	validated, _, err := am.integratedPeerValidator.ValidatePeer(ctx, update, peer, userID, accountID, am.networkMapController.GetDNSDomain(settings), peerGroups, settings.Extra)
+	if err != nil {
+		log.WithContext(ctx).Warnf("automatic approval of peer %s was not accepted by the integrated validator: %v", peer.Key, err)
+		return false
+	}
+
+	return !validated.Status.RequiresApproval
+}
+
+// ApprovePendingPeers approves the peers pending approval once the integrated validator accepted the approval of each of them.
+// Nothing is approved if one of the peers isn't pending approval or its approval is not accepted.
+func (am *DefaultAccountManager) ApprovePendingPeers(ctx context.Context, accountID, userID string, peerIDs []string) ([]*nbpeer.Peer, error) {
+	allowed, err := am.permissionsManager.ValidateUserPermissions(ctx, accountID, userID, modules.Peers, operations.Update)
+	if err != nil {
+		return nil, status.NewPermissionValidationError(err)
+	}
+	if !allowed {
+		return nil, status.NewPermissionDeniedError()
+	}
+
+	var peers []*nbpeer.Peer
+	err = am.Store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+		peers, err = getPendingApprovalPeers(ctx, transaction, accountID, peerIDs)
+		if err != nil {
+			return err
+		}
+
+		settings, err := transaction.GetAccountSettings(ctx, store.LockingStrengthNone, accountID)
+		if err != nil {
+			return err
+		}
+		dnsDomain := am.networkMapController.GetDNSDomain(settings)
+
+		for _, peer := range peers {
+			peerGroupList, err := getPeerGroupIDs(ctx, transaction, accountID, peer.ID)
+			if err != nil {
+				return err
+			}
+
+			update := peer.Copy()
+			update.Status.RequiresApproval = false
+			update, _, err = am.integratedPeerValidator.ValidatePeer(ctx, update, peer, userID, accountID, dnsDomain, peerGroupList, settings.Extra)
+			if err != nil {
+				return err
+			}
+			if update.Status.RequiresApproval {
+				return status.Errorf(status.PreconditionFailed, "approval of peer %s was not accepted", peer.ID)
+			}
+			peer.Status.RequiresApproval = false
+		}
+
+		if _, err = transaction.ApprovePeers(ctx, accountID, peerIDs); err != nil {
+			return err
+		}
+
+		if err = transaction.DeletePeerApprovalRequests(ctx, accountID, peerIDs); err != nil {
+			return err
+		}
+
+		return transaction.IncrementNetworkSerial(ctx, accountID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = am.networkMapController.OnPeersUpdated(ctx, accountID, peerIDs); err != nil {
+		log.WithContext(ctx).Errorf("failed to update network map for approved peers: %v", err)
+	}
+
+	return peers, nil
+}
+
+// RejectPendingPeers deletes the peers pending approval. Nothing is deleted if one of the peers isn't pending approval
+// or can't be deleted.
+func (am *DefaultAccountManager) RejectPendingPeers(ctx context.Context, accountID, userID string, peerIDs []string) ([]*nbpeer.Peer, error) {
+	allowed, err := am.permissionsManager.ValidateUserPermissions(ctx, accountID, userID, modules.Peers, operations.Delete)
+	if err != nil {
+		return nil, status.NewPermissionValidationError(err)
+	}
+	if !allowed {
+		return nil, status.NewPermissionDeniedError()
+	}
+
+	var peers []*nbpeer.Peer
+	var settings *types.Settings
+	var eventsToStore []func()
+
+	err = am.Store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+		peers, err = getPendingApprovalPeers(ctx, transaction, accountID, peerIDs)
+		if err != nil {
+			return err
+		}
+
+		settings, err = transaction.GetAccountSettings(ctx, store.LockingStrengthNone, accountID)
+		if err != nil {
+			return err
+		}
+
+		for _, peer := range peers {
+			if err = am.validatePeerDelete(ctx, transaction, accountID, peer.ID); err != nil {
+				return err
+			}
+		}
+
+		eventsToStore, err = deletePeers(ctx, am, transaction, accountID, userID, peers, settings)
+		if err != nil {
+			return fmt.Errorf("failed to delete peers: %w", err)
+		}
+
+		return transaction.IncrementNetworkSerial(ctx, accountID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, storeEvent := range eventsToStore {
+		storeEvent()
+	}
+
+	for _, peer := range peers {
+		if err = am.integratedPeerValidator.PeerDeleted(ctx, accountID, peer.ID, settings.Extra); err != nil {
+			log.WithContext(ctx).Errorf("failed to delete peer %s from integrated validator: %v", peer.ID, err)
+		}
+	}
+
+	if err = am.networkMapController.OnPeersDeleted(ctx, accountID, peerIDs); err != nil {
+		log.WithContext(ctx).Errorf("failed to delete rejected peers from network map: %v", err)
+	}
+
+	return peers, nil
+}
+
+// getPendingApprovalPeers returns the peers of an approval decision, all of them have to be pending approval
+func getPendingApprovalPeers(ctx context.Context, transaction store.Store, accountID string, peerIDs []string) ([]*nbpeer.Peer, error) {
+	if len(peerIDs) == 0 {
+		return nil, status.Errorf(status.InvalidArgument, "at least one peer is required")
+	}
+
+	peersByID, err := transaction.GetPeersByIDs(ctx, store.LockingStrengthUpdate, accountID, peerIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	peers := make([]*nbpeer.Peer, 0, len(peerIDs))
+	for _, peerID := range peerIDs {
+		peer, ok := peersByID[peerID]
+		if !ok {
+			return nil, status.NewPeerNotFoundError(peerID)
+		}
+		if peer.Status == nil || !peer.Status.RequiresApproval {
+			return nil, status.Errorf(status.PreconditionFailed, "peer %s is not pending approval", peerID)
+		}
+		peers = append(peers, peer)
+	}
+
+	return peers, nil
+}
+
+// storeSetupKeyRejectedEvent records a peer registration that didn't meet the setup key constraints
+func (am *DefaultAccountManager) storeSetupKeyRejectedEvent(ctx context.Context, sk *types.SetupKey, peer *nbpeer.Peer, reason error) {
+	meta := map[string]any{
+		"setup_key_name": sk.Name,
+		"reason":         reason.Error(),
+		"hostname":       peer.Meta.Hostname,
+		"os":             peer.Meta.GoOS,
+	}
+	if peer.Location.ConnectionIP != nil {
+		meta["connection_ip"] = peer.Location.ConnectionIP.String()
+	}
+
+	log.WithContext(ctx).Warnf("rejected peer %s registration with setup key %s: %v", peer.Meta.Hostname, sk.Id, reason)
+	am.StoreEvent(ctx, sk.Id, sk.Id, sk.AccountID, activity.SetupKeyPeerRejected, meta)
+}
+
 func getPeerIPDNSLabel(ip net.IP, peerHostName string) (string, error) {
 	ip = ip.To4()
 
@@ -759,7 +1032,7 @@ func (am *DefaultAccountManager) handlePeerLoginNotFound(ctx context.Context, lo
 			ExtraDNSLabels: login.ExtraDNSLabels,
 		}
 
-		return am.AddPeer(ctx, "", login.SetupKey, login.UserID, newPeer, false)
+		return am.addPeer(ctx, "", login.SetupKey, login.UserID, login.WorkloadIdentity, newPeer, false)
 	}
 
 	log.WithContext(ctx).Errorf("failed while logging in peer %s: %v", login.WireGuardPubKey, err)
@@ -877,62 +1150,94 @@ func (am *DefaultAccountManager) LoginPeer(ctx context.Context, login types.Peer
 
 // getPeerPostureChecks returns the posture checks for the peer.
 func getPeerPostureChecks(ctx context.Context, transaction store.Store, accountID, peerID string) ([]*posture.Checks, error) {
+	peersPostureChecks, err := getPeersPostureChecks(ctx, transaction, accountID, []string{peerID})
+	if err != nil {
+		return nil, err
+	}
+	return peersPostureChecks[peerID], nil
+}
+
+// getPeersPostureChecks returns the posture checks applied to each of the peers by the account policies. The policies,
+// their source groups and the posture checks are loaded once for all the peers.
+func getPeersPostureChecks(ctx context.Context, transaction store.Store, accountID string, peerIDs []string) (map[string][]*posture.Checks, error) {
 	policies, err := transaction.GetAccountPolicies(ctx, store.LockingStrengthNone, accountID)
 	if err != nil {
 		return nil, err
 	}
 
+	policies = slices.DeleteFunc(policies, func(policy *types.Policy) bool {
+		return !policy.IsActive() || len(policy.SourcePostureChecks) == 0
+	})
 	if len(policies) == 0 {
 		return nil, nil
 	}
 
-	var peerPostureChecksIDs []string
-
+	var sourceGroupIDs []string
 	for _, policy := range policies {
-		if !policy.Enabled || len(policy.SourcePostureChecks) == 0 {
-			continue
+		for _, rule := range policy.Rules {
+			if rule.Enabled {
+				sourceGroupIDs = append(sourceGroupIDs, rule.Sources...)
+			}
 		}
+	}
 
-		postureChecksIDs, err := processPeerPostureChecks(ctx, transaction, policy, accountID, peerID)
-		if err != nil {
-			return nil, err
-		}
+	sourceGroups, err := transaction.GetGroupsByIDs(ctx, store.LockingStrengthNone, accountID, sourceGroupIDs)
+	if err != nil {
+		return nil, err
+	}
 
-		peerPostureChecksIDs = append(peerPostureChecksIDs, postureChecksIDs...)
+	var postureChecksIDs []string
+	peersPostureChecksIDs := make(map[string][]string, len(peerIDs))
+	for _, policy := range policies {
+		for _, peerID := range peerIDs {
+			inSources, err := isPeerInPolicySources(policy, sourceGroups, peerID)
+			if err != nil {
+				return nil, err
+			}
+			if inSources {
+				peersPostureChecksIDs[peerID] = append(peersPostureChecksIDs[peerID], policy.SourcePostureChecks...)
+				postureChecksIDs = append(postureChecksIDs, policy.SourcePostureChecks...)
+			}
+		}
 	}
 
-	peerPostureChecks, err := transaction.GetPostureChecksByIDs(ctx, store.LockingStrengthNone, accountID, peerPostureChecksIDs)
+	postureChecks, err := transaction.GetPostureChecksByIDs(ctx, store.LockingStrengthNone, accountID, postureChecksIDs)
 	if err != nil {
 		return nil, err
 	}
 
-	return maps.Values(peerPostureChecks), nil
+	peersPostureChecks := make(map[string][]*posture.Checks, len(peersPostureChecksIDs))
+	for peerID, ids := range peersPostureChecksIDs {
+		slices.Sort(ids)
+		for _, id := range slices.Compact(ids) {
+			if checks, ok := postureChecks[id]; ok {
+				peersPostureChecks[peerID] = append(peersPostureChecks[peerID], checks)
+			}
+		}
+	}
+
+	return peersPostureChecks, nil
 }
 
-// processPeerPostureChecks checks if the peer is in the source group of the policy and returns the posture checks.
-func processPeerPostureChecks(ctx context.Context, transaction store.Store, policy *types.Policy, accountID, peerID string) ([]string, error) {
+// isPeerInPolicySources checks if the peer is in a source group of an enabled rule of the policy.
+func isPeerInPolicySources(policy *types.Policy, sourceGroups map[string]*types.Group, peerID string) (bool, error) {
 	for _, rule := range policy.Rules {
 		if !rule.Enabled {
 			continue
 		}
 
-		sourceGroups, err := transaction.GetGroupsByIDs(ctx, store.LockingStrengthNone, accountID, rule.Sources)
-		if err != nil {
-			return nil, err
-		}
-
 		for _, sourceGroup := range rule.Sources {
 			group, ok := sourceGroups[sourceGroup]
 			if !ok {
-				return nil, fmt.Errorf("failed to check peer in policy source group")
+				return false, fmt.Errorf("failed to check peer in policy source group")
 			}
 
 			if slices.Contains(group.Peers, peerID) {
-				return policy.SourcePostureChecks, nil
+				return true, nil
 			}
 		}
 	}
-	return nil, nil
+	return false, nil
 }
 
 // checkIFPeerNeedsLoginWithoutLock checks if the peer needs login without acquiring the account lock. The check validate if the peer was not added via SSO
@@ -1050,6 +1355,63 @@ func (am *DefaultAccountManager) GetPeer(ctx context.Context, accountID, peerID,
 	return am.checkIfUserOwnsPeer(ctx, accountID, userID, peer)
 }
 
+// GetPeerPostureCheckResults evaluates every posture check applied to the peer by the account policies
+// and returns the results with the failure reasons.
+func (am *DefaultAccountManager) GetPeerPostureCheckResults(ctx context.Context, accountID, peerID, userID string) ([]posture.Result, error) {
+	peer, err := am.GetPeer(ctx, accountID, peerID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	postureChecks, err := getPeerPostureChecks(ctx, am.Store, accountID, peer.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return evaluatePeerPostureChecks(ctx, postureChecks, peer), nil
+}
+
+// GetPeersPostureCheckResults evaluates the posture checks applied to each of the peers by the account policies
+// and returns the results by peer ID.
+func (am *DefaultAccountManager) GetPeersPostureCheckResults(ctx context.Context, accountID, userID string, peers []*nbpeer.Peer) (map[string][]posture.Result, error) {
+	allowed, err := am.permissionsManager.ValidateUserPermissions(ctx, accountID, userID, modules.Peers, operations.Read)
+	if err != nil {
+		return nil, status.NewPermissionValidationError(err)
+	}
+	if !allowed {
+		return nil, status.NewPermissionDeniedError()
+	}
+
+	peerIDs := make([]string, 0, len(peers))
+	for _, peer := range peers {
+		peerIDs = append(peerIDs, peer.ID)
+	}
+
+	peersPostureChecks, err := getPeersPostureChecks(ctx, am.Store, accountID, peerIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[string][]posture.Result, len(peers))
+	for _, peer := range peers {
+		results[peer.ID] = evaluatePeerPostureChecks(ctx, peersPostureChecks[peer.ID], peer)
+	}
+
+	return results, nil
+}
+
+func evaluatePeerPostureChecks(ctx context.Context, postureChecks []*posture.Checks, peer *nbpeer.Peer) []posture.Result {
+	slices.SortFunc(postureChecks, func(a, b *posture.Checks) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	results := make([]posture.Result, 0, len(postureChecks))
+	for _, checks := range postureChecks {
+		results = append(results, checks.Evaluate(ctx, *peer)...)
+	}
+	return results
+}
+
 func (am *DefaultAccountManager) checkIfUserOwnsPeer(ctx context.Context, accountID, userID string, peer *nbpeer.Peer) (*nbpeer.Peer, error) {
 	account, err := am.requestBuffer.GetAccountWithBackpressure(ctx, accountID)
 	if err != nil {
@@ -1274,6 +1636,12 @@ func deletePeers(ctx context.Context, am *DefaultAccountManager, transaction sto
 		if err = transaction.DeletePeer(ctx, accountID, peer.ID); err != nil {
 			return nil, err
 		}
+
+		if peer.Status != nil && peer.Status.RequiresApproval {
+			if err = transaction.DeletePeerApprovalRequests(ctx, accountID, []string{peer.ID}); err != nil {
+				return nil, err
+			}
+		}
 		peerDeletedEvents = append(peerDeletedEvents, func() {
 			am.StoreEvent(ctx, userID, peer.ID, accountID, activity.PeerRemovedByUser, peer.EventMeta(dnsDomain))
 		})
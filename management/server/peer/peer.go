@@ -22,6 +22,8 @@ type Peer struct {
 	Key string // uniqueness index (check migrations)
 	// IP address of the Peer
 	IP net.IP `gorm:"serializer:json"` // uniqueness index per accountID (check migrations)
+	// IPv6 is the optional IPv6 overlay address of the Peer, allocated when the account has an IPv6 network range
+	IPv6 net.IP `gorm:"serializer:nullable_ip"` // uniqueness index per accountID (check migrations)
 	// Meta is a Peer system meta data
 	Meta PeerSystemMeta `gorm:"embedded;embeddedPrefix:meta_"`
 	// Name is peer's name (machine name)
@@ -95,6 +97,18 @@ type File struct {
 	ProcessIsRunning bool
 }
 
+// DiskEncryption is the disk encryption state of the system
+type DiskEncryption struct {
+	// EncryptedVolumes are the mount points backed by an encrypted block device
+	EncryptedVolumes []string
+}
+
+// OSFirewall is the state of the operating system firewall
+type OSFirewall struct {
+	// ActiveBackends are the firewall frontends that currently enforce rules
+	ActiveBackends []string
+}
+
 // Flags defines a set of options to control feature behavior
 type Flags struct {
 	RosenpassEnabled    bool
@@ -109,6 +123,8 @@ type Flags struct {
 	BlockInbound        bool
 
 	LazyConnectionEnabled bool
+
+	IPv6Supported bool
 }
 
 // PeerSystemMeta is a metadata of a Peer machine system
@@ -127,9 +143,11 @@ type PeerSystemMeta struct { //nolint:revive
 	SystemSerialNumber string
 	SystemProductName  string
 	SystemManufacturer string
-	Environment        Environment `gorm:"serializer:json"`
-	Flags              Flags       `gorm:"serializer:json"`
-	Files              []File      `gorm:"serializer:json"`
+	Environment        Environment    `gorm:"serializer:json"`
+	Flags              Flags          `gorm:"serializer:json"`
+	Files              []File         `gorm:"serializer:json"`
+	DiskEncryption     DiskEncryption `gorm:"serializer:json"`
+	OSFirewall         OSFirewall     `gorm:"serializer:json"`
 }
 
 func (p PeerSystemMeta) isEqual(other PeerSystemMeta) bool {
@@ -159,6 +177,11 @@ func (p PeerSystemMeta) isEqual(other PeerSystemMeta) bool {
 		return false
 	}
 
+	if !slices.Equal(p.DiskEncryption.EncryptedVolumes, other.DiskEncryption.EncryptedVolumes) ||
+		!slices.Equal(p.OSFirewall.ActiveBackends, other.OSFirewall.ActiveBackends) {
+		return false
+	}
+
 	return p.Hostname == other.Hostname &&
 		p.GoOS == other.GoOS &&
 		p.Kernel == other.Kernel &&
@@ -213,6 +236,7 @@ func (p *Peer) Copy() *Peer {
 		AccountID:                   p.AccountID,
 		Key:                         p.Key,
 		IP:                          p.IP,
+		IPv6:                        p.IPv6,
 		Meta:                        p.Meta,
 		Name:                        p.Name,
 		DNSLabel:                    p.DNSLabel,
@@ -231,6 +255,11 @@ func (p *Peer) Copy() *Peer {
 	}
 }
 
+// SupportsIPv6 returns true if the peer has an IPv6 overlay address and its client is able to configure it
+func (p *Peer) SupportsIPv6() bool {
+	return len(p.IPv6) > 0 && p.Meta.Flags.IPv6Supported
+}
+
 // UpdateMetaIfNew updates peer's system metadata if new information is provided
 // returns true if meta was updated, false otherwise
 func (p *Peer) UpdateMetaIfNew(meta PeerSystemMeta) (updated, versionChanged bool) {
@@ -359,5 +388,6 @@ func (f Flags) isEqual(other Flags) bool {
 		f.DisableFirewall == other.DisableFirewall &&
 		f.BlockLANAccess == other.BlockLANAccess &&
 		f.BlockInbound == other.BlockInbound &&
-		f.LazyConnectionEnabled == other.LazyConnectionEnabled
+		f.LazyConnectionEnabled == other.LazyConnectionEnabled &&
+		f.IPv6Supported == other.IPv6Supported
 }
@@ -0,0 +1,28 @@
+package peer
+
+import (
+	"context"
+	"net"
+	"reflect"
+
+	"gorm.io/gorm/schema"
+)
+
+func init() {
+	schema.RegisterSerializer("nullable_ip", nullableIPSerializer{})
+}
+
+// nullableIPSerializer stores a net.IP as JSON like the default json serializer, except that an empty IP
+// is stored as NULL. The json serializer stores it as an empty JSON string, which would make peers
+// without an address collide in a uniqueness index.
+type nullableIPSerializer struct {
+	schema.JSONSerializer
+}
+
+// Value implements the schema.SerializerValuerInterface
+func (s nullableIPSerializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	if ip, ok := fieldValue.(net.IP); ok && len(ip) == 0 {
+		return nil, nil
+	}
+	return s.JSONSerializer.Value(ctx, field, dst, fieldValue)
+}
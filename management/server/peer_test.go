@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/sha256"
 	b64 "encoding/base64"
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -45,6 +46,7 @@ import (
 	networkTypes "github.com/netbirdio/netbird/management/server/networks/types"
 
 	nbdns "github.com/netbirdio/netbird/dns"
+	"github.com/netbirdio/netbird/management/internals/modules/peerapproval"
 	"github.com/netbirdio/netbird/management/server/activity"
 	nbpeer "github.com/netbirdio/netbird/management/server/peer"
 	"github.com/netbirdio/netbird/management/server/posture"
@@ -52,6 +54,7 @@ import (
 	"github.com/netbirdio/netbird/management/server/telemetry"
 	"github.com/netbirdio/netbird/management/server/types"
 	nbroute "github.com/netbirdio/netbird/route"
+	"github.com/netbirdio/netbird/shared/auth"
 	"github.com/netbirdio/netbird/shared/management/domain"
 	"github.com/netbirdio/netbird/shared/management/proto"
 )
@@ -195,7 +198,7 @@ func testGetNetworkMapGeneral(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	setupKey, err := manager.CreateSetupKey(context.Background(), account.Id, "test-key", types.SetupKeyReusable, time.Hour, nil, 999, userId, false, false)
+	setupKey, err := manager.CreateSetupKey(context.Background(), account.Id, "test-key", types.SetupKeyReusable, time.Hour, nil, 999, userId, false, false, types.SetupKeyConstraints{})
 	if err != nil {
 		t.Fatal("error creating setup key")
 		return
@@ -444,7 +447,7 @@ func TestAccountManager_GetPeerNetwork(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	setupKey, err := manager.CreateSetupKey(context.Background(), account.Id, "test-key", types.SetupKeyReusable, time.Hour, nil, 999, userId, false, false)
+	setupKey, err := manager.CreateSetupKey(context.Background(), account.Id, "test-key", types.SetupKeyReusable, time.Hour, nil, 999, userId, false, false, types.SetupKeyConstraints{})
 	if err != nil {
 		t.Fatal("error creating setup key")
 		return
@@ -516,7 +519,7 @@ func TestDefaultAccountManager_GetPeer(t *testing.T) {
 	}
 
 	// two peers one added by a regular user and one with a setup key
-	setupKey, err := manager.CreateSetupKey(context.Background(), account.Id, "test-key", types.SetupKeyReusable, time.Hour, nil, 999, adminUser, false, false)
+	setupKey, err := manager.CreateSetupKey(context.Background(), account.Id, "test-key", types.SetupKeyReusable, time.Hour, nil, 999, adminUser, false, false, types.SetupKeyConstraints{})
 	if err != nil {
 		t.Fatal("error creating setup key")
 		return
@@ -1179,7 +1182,7 @@ func TestToSyncResponse(t *testing.T) {
 	}
 	dnsCache := &cache.DNSConfigCache{}
 	accountSettings := &types.Settings{RoutingPeerDNSResolutionEnabled: true}
-	response := grpc.ToSyncResponse(context.Background(), config, config.HttpConfig, config.DeviceAuthorizationFlow, peer, turnRelayToken, turnRelayToken, networkMap, dnsName, checks, dnsCache, accountSettings, nil, []string{}, int64(dnsForwarderPort))
+	response := grpc.ToSyncResponse(context.Background(), config, config.HttpConfig, config.DeviceAuthorizationFlow, peer, turnRelayToken, turnRelayToken, nil, networkMap, dnsName, checks, dnsCache, accountSettings, nil, []string{}, int64(dnsForwarderPort))
 
 	assert.NotNil(t, response)
 	// assert peer config
@@ -2229,7 +2232,7 @@ func Test_AddPeer(t *testing.T) {
 		return
 	}
 
-	setupKey, err := manager.CreateSetupKey(context.Background(), accountID, "test-key", types.SetupKeyReusable, time.Hour, nil, 10000, userID, false, false)
+	setupKey, err := manager.CreateSetupKey(context.Background(), accountID, "test-key", types.SetupKeyReusable, time.Hour, nil, 10000, userID, false, false, types.SetupKeyConstraints{})
 	if err != nil {
 		t.Fatal("error creating setup key")
 		return
@@ -2484,3 +2487,288 @@ func TestLoginPeer_ApprovedUserCanLogin(t *testing.T) {
 	_, _, _, err = manager.LoginPeer(context.Background(), login)
 	require.NoError(t, err, "Regular user should be able to login peers")
 }
+
+func TestLoginPeer_WorkloadIdentity(t *testing.T) {
+	manager, _, err := createManager(t)
+	require.NoError(t, err)
+
+	account, err := manager.GetOrCreateAccountByUser(context.Background(), auth.UserAuth{UserId: "testingUser"})
+	require.NoError(t, err)
+
+	group := &types.Group{ID: "ci-group", Name: "ci", Issued: types.GroupIssuedAPI}
+	require.NoError(t, manager.CreateGroup(context.Background(), account.Id, "testingUser", group))
+
+	key, err := wgtypes.GeneratePrivateKey()
+	require.NoError(t, err)
+
+	login := types.PeerLogin{
+		WireGuardPubKey: key.PublicKey().String(),
+		Meta:            nbpeer.PeerSystemMeta{Hostname: "runner", GoOS: "linux"},
+		ConnectionIP:    net.ParseIP("203.0.113.10"),
+		WorkloadIdentity: &types.WorkloadIdentity{
+			AccountID:    account.Id,
+			ProviderID:   "provider-id",
+			ProviderName: "cluster",
+			Subject:      "system:serviceaccount:ci:runner",
+			AutoGroups:   []string{group.ID},
+			Ephemeral:    true,
+		},
+	}
+
+	peer, _, _, err := manager.LoginPeer(context.Background(), login)
+	require.NoError(t, err)
+	assert.True(t, peer.Ephemeral)
+	assert.Empty(t, peer.UserID)
+
+	peerGroups, err := manager.Store.GetPeerGroups(context.Background(), store.LockingStrengthNone, account.Id, peer.ID)
+	require.NoError(t, err)
+	groupIDs := make([]string, 0, len(peerGroups))
+	for _, g := range peerGroups {
+		groupIDs = append(groupIDs, g.ID)
+	}
+	assert.Contains(t, groupIDs, group.ID)
+
+	ev := getEvent(t, account.Id, manager, activity.PeerAddedWithWorkloadIdentity)
+	assert.Equal(t, "provider-id", ev.InitiatorID)
+	assert.Equal(t, "cluster", ev.Meta["workload_identity_provider"])
+	assert.Equal(t, "system:serviceaccount:ci:runner", ev.Meta["workload_subject"])
+
+	// the peer logs in again with a fresh token of the same workload
+	_, _, _, err = manager.LoginPeer(context.Background(), login)
+	require.NoError(t, err)
+
+	login.ExtraDNSLabels = []string{"runner"}
+	login.WireGuardPubKey = "other-" + login.WireGuardPubKey
+	_, _, _, err = manager.LoginPeer(context.Background(), login)
+	assert.Error(t, err, "workload identities don't allow extra DNS labels")
+}
+
+// pendingApprovalValidator requires the approval of every new peer like an account with peer approval enabled
+type pendingApprovalValidator struct {
+	MockIntegratedValidator
+	// approvalErr is returned when the approval of a peer is validated
+	approvalErr error
+}
+
+func (v pendingApprovalValidator) ValidatePeer(_ context.Context, update *nbpeer.Peer, _ *nbpeer.Peer, _ string, _ string, _ string, _ []string, _ *types.ExtraSettings) (*nbpeer.Peer, bool, error) {
+	if v.approvalErr != nil {
+		return nil, false, v.approvalErr
+	}
+	return update, false, nil
+}
+
+func (pendingApprovalValidator) PreparePeer(_ context.Context, _ string, peer *nbpeer.Peer, _ []string, _ *types.ExtraSettings, _ bool) *nbpeer.Peer {
+	prepared := peer.Copy()
+	prepared.Status.RequiresApproval = true
+	return prepared
+}
+
+func TestAddPeer_PeerApprovalRules(t *testing.T) {
+	manager, _, err := createManager(t)
+	require.NoError(t, err)
+	manager.integratedPeerValidator = pendingApprovalValidator{}
+
+	userID := "testingUser"
+	account, err := manager.GetOrCreateAccountByUser(context.Background(), auth.UserAuth{UserId: userID})
+	require.NoError(t, err)
+
+	key, err := manager.CreateSetupKey(context.Background(), account.Id, "ci", types.SetupKeyReusable, time.Hour, nil,
+		types.SetupKeyUnlimitedUsage, userID, false, false, types.SetupKeyConstraints{})
+	require.NoError(t, err)
+
+	rule := peerapproval.NewRule(account.Id, "ci on linux", true, nil, []string{key.Id}, []string{"linux"}, nil)
+	require.NoError(t, manager.Store.SavePeerApprovalRule(context.Background(), rule))
+
+	addPeer := func(goOS string) *nbpeer.Peer {
+		peerKey, err := wgtypes.GeneratePrivateKey()
+		require.NoError(t, err)
+		peer, _, _, err := manager.AddPeer(context.Background(), "", key.Key, "", &nbpeer.Peer{
+			Key:  peerKey.PublicKey().String(),
+			Meta: nbpeer.PeerSystemMeta{Hostname: "ci-" + goOS, GoOS: goOS},
+		}, false)
+		require.NoError(t, err)
+		return peer
+	}
+
+	approved := addPeer("linux")
+	assert.False(t, approved.Status.RequiresApproval, "the rule approves linux peers of the setup key")
+
+	ev := getEvent(t, account.Id, manager, activity.PeerAutoApproved)
+	assert.Equal(t, activity.SystemInitiator, ev.InitiatorID)
+	assert.Equal(t, approved.ID, ev.TargetID)
+	assert.Equal(t, rule.ID, ev.Meta["rule_id"])
+	assert.Equal(t, "ci on linux", ev.Meta["rule_name"])
+
+	pending := addPeer("windows")
+	assert.True(t, pending.Status.RequiresApproval)
+
+	requests, err := manager.Store.GetAccountPeerApprovalRequests(context.Background(), store.LockingStrengthNone, account.Id)
+	require.NoError(t, err)
+	require.Len(t, requests, 1, "only the pending peer keeps its registration context")
+	assert.Equal(t, pending.ID, requests[0].PeerID)
+	assert.Equal(t, key.Id, requests[0].SetupKeyID)
+	assert.Equal(t, "ci", requests[0].SetupKeyName)
+
+	require.NoError(t, manager.DeletePeer(context.Background(), account.Id, pending.ID, userID))
+	requests, err = manager.Store.GetAccountPeerApprovalRequests(context.Background(), store.LockingStrengthNone, account.Id)
+	require.NoError(t, err)
+	assert.Empty(t, requests, "the request is deleted with the peer")
+
+	manager.integratedPeerValidator = pendingApprovalValidator{approvalErr: errors.New("approval not allowed")}
+	assert.True(t, addPeer("linux").Status.RequiresApproval, "the rule can't approve peers the integrated validator doesn't accept")
+}
+
+// setupPendingPeers creates an account with peer approval and registers the given number of peers pending approval
+func setupPendingPeers(t *testing.T, count int) (*DefaultAccountManager, string, string, []string) {
+	t.Helper()
+
+	manager, _, err := createManager(t)
+	require.NoError(t, err)
+	manager.integratedPeerValidator = pendingApprovalValidator{}
+
+	userID := "testingUser"
+	account, err := manager.GetOrCreateAccountByUser(context.Background(), auth.UserAuth{UserId: userID})
+	require.NoError(t, err)
+
+	key, err := manager.CreateSetupKey(context.Background(), account.Id, "ci", types.SetupKeyReusable, time.Hour, nil,
+		types.SetupKeyUnlimitedUsage, userID, false, false, types.SetupKeyConstraints{})
+	require.NoError(t, err)
+
+	peerIDs := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		peerKey, err := wgtypes.GeneratePrivateKey()
+		require.NoError(t, err)
+		peer, _, _, err := manager.AddPeer(context.Background(), "", key.Key, "", &nbpeer.Peer{
+			Key:  peerKey.PublicKey().String(),
+			Meta: nbpeer.PeerSystemMeta{Hostname: fmt.Sprintf("pending-%d", i), GoOS: "linux"},
+		}, false)
+		require.NoError(t, err)
+		require.True(t, peer.Status.RequiresApproval)
+		peerIDs = append(peerIDs, peer.ID)
+	}
+
+	return manager, account.Id, userID, peerIDs
+}
+
+func TestDefaultAccountManager_ApprovePendingPeers(t *testing.T) {
+	manager, accountID, userID, peerIDs := setupPendingPeers(t, 2)
+	ctx := context.Background()
+
+	assertPending := func(peerID string, pending bool) {
+		t.Helper()
+		peer, err := manager.Store.GetPeerByID(ctx, store.LockingStrengthNone, accountID, peerID)
+		require.NoError(t, err)
+		assert.Equal(t, pending, peer.Status.RequiresApproval)
+	}
+
+	_, err := manager.ApprovePendingPeers(ctx, accountID, userID, []string{peerIDs[0], "unknown-peer"})
+	sErr, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, status.NotFound, sErr.Type())
+	assertPending(peerIDs[0], true)
+
+	manager.integratedPeerValidator = pendingApprovalValidator{approvalErr: status.Errorf(status.PermissionDenied, "approval not allowed")}
+	_, err = manager.ApprovePendingPeers(ctx, accountID, userID, peerIDs)
+	assert.Error(t, err, "the integrated validator has to accept the approval")
+	assertPending(peerIDs[0], true)
+	assertPending(peerIDs[1], true)
+
+	manager.integratedPeerValidator = pendingApprovalValidator{}
+	approved, err := manager.ApprovePendingPeers(ctx, accountID, userID, peerIDs)
+	require.NoError(t, err)
+	assert.Len(t, approved, 2)
+	assertPending(peerIDs[0], false)
+	assertPending(peerIDs[1], false)
+
+	requests, err := manager.Store.GetAccountPeerApprovalRequests(ctx, store.LockingStrengthNone, accountID)
+	require.NoError(t, err)
+	assert.Empty(t, requests)
+
+	_, err = manager.ApprovePendingPeers(ctx, accountID, userID, peerIDs[:1])
+	sErr, ok = status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, status.PreconditionFailed, sErr.Type(), "approved peers can't be approved again")
+}
+
+func TestDefaultAccountManager_RejectPendingPeers(t *testing.T) {
+	manager, accountID, userID, peerIDs := setupPendingPeers(t, 3)
+	ctx := context.Background()
+
+	_, err := manager.ApprovePendingPeers(ctx, accountID, userID, peerIDs[2:])
+	require.NoError(t, err)
+
+	_, err = manager.RejectPendingPeers(ctx, accountID, userID, peerIDs)
+	sErr, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, status.PreconditionFailed, sErr.Type())
+
+	peers, err := manager.Store.GetPeersByIDs(ctx, store.LockingStrengthNone, accountID, peerIDs)
+	require.NoError(t, err)
+	assert.Len(t, peers, 3, "nothing is deleted when the batch is invalid")
+
+	rejected, err := manager.RejectPendingPeers(ctx, accountID, userID, peerIDs[:2])
+	require.NoError(t, err)
+	assert.Len(t, rejected, 2)
+
+	peers, err = manager.Store.GetPeersByIDs(ctx, store.LockingStrengthNone, accountID, peerIDs)
+	require.NoError(t, err)
+	assert.Len(t, peers, 1, "rejected peers are deleted")
+	assert.Contains(t, peers, peerIDs[2])
+
+	requests, err := manager.Store.GetAccountPeerApprovalRequests(ctx, store.LockingStrengthNone, accountID)
+	require.NoError(t, err)
+	assert.Empty(t, requests)
+}
+
+func TestGetPeersPostureChecks(t *testing.T) {
+	ctx := context.Background()
+	s, err := createStore(t)
+	require.NoError(t, err)
+
+	accountID := "account-id"
+	err = s.SaveAccount(ctx, &types.Account{
+		Id: accountID,
+		Peers: map[string]*nbpeer.Peer{
+			"peer-1": {ID: "peer-1", AccountID: accountID, Key: "key-1", IP: net.IP{100, 64, 0, 1}, DNSLabel: "peer-1", Status: &nbpeer.PeerStatus{}},
+			"peer-2": {ID: "peer-2", AccountID: accountID, Key: "key-2", IP: net.IP{100, 64, 0, 2}, DNSLabel: "peer-2", Status: &nbpeer.PeerStatus{}},
+			"peer-3": {ID: "peer-3", AccountID: accountID, Key: "key-3", IP: net.IP{100, 64, 0, 3}, DNSLabel: "peer-3", Status: &nbpeer.PeerStatus{}},
+		},
+		Groups: map[string]*types.Group{
+			"group-1": {ID: "group-1", AccountID: accountID, Name: "group-1", Peers: []string{"peer-1", "peer-2"}},
+			"group-2": {ID: "group-2", AccountID: accountID, Name: "group-2", Peers: []string{"peer-2"}},
+		},
+		Policies: []*types.Policy{
+			{
+				ID: "policy-1", AccountID: accountID, Enabled: true, SourcePostureChecks: []string{"checks-1"},
+				Rules: []*types.PolicyRule{{ID: "rule-1", Enabled: true, Sources: []string{"group-1"}, Destinations: []string{"group-1"}}},
+			},
+			{
+				ID: "policy-2", AccountID: accountID, Enabled: true, SourcePostureChecks: []string{"checks-1", "checks-2"},
+				Rules: []*types.PolicyRule{{ID: "rule-2", Enabled: true, Sources: []string{"group-2"}, Destinations: []string{"group-1"}}},
+			},
+		},
+		PostureChecks: []*posture.Checks{
+			{ID: "checks-1", AccountID: accountID, Name: "checks-1", Checks: posture.ChecksDefinition{NBVersionCheck: &posture.NBVersionCheck{MinVersion: "0.0.1"}}},
+			{ID: "checks-2", AccountID: accountID, Name: "checks-2", Checks: posture.ChecksDefinition{NBVersionCheck: &posture.NBVersionCheck{MinVersion: "0.0.2"}}},
+		},
+	})
+	require.NoError(t, err)
+
+	peersPostureChecks, err := getPeersPostureChecks(ctx, s, accountID, []string{"peer-1", "peer-2", "peer-3"})
+	require.NoError(t, err)
+
+	checksIDs := func(peerID string) []string {
+		var ids []string
+		for _, checks := range peersPostureChecks[peerID] {
+			ids = append(ids, checks.ID)
+		}
+		return ids
+	}
+	assert.ElementsMatch(t, []string{"checks-1"}, checksIDs("peer-1"))
+	assert.ElementsMatch(t, []string{"checks-1", "checks-2"}, checksIDs("peer-2"), "the checks of several policies are listed once")
+	assert.Empty(t, checksIDs("peer-3"))
+
+	postureChecks, err := getPeerPostureChecks(ctx, s, accountID, "peer-2")
+	require.NoError(t, err)
+	assert.Len(t, postureChecks, 2)
+}
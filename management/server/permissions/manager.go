@@ -22,7 +22,7 @@ type Manager interface {
 	ValidateRoleModuleAccess(ctx context.Context, accountID string, role roles.RolePermissions, module modules.Module, operation operations.Operation) bool
 	ValidateAccountAccess(ctx context.Context, accountID string, user *types.User, allowOwnerAndAdmin bool) error
 
-	GetPermissionsByRole(ctx context.Context, role types.UserRole) (roles.Permissions, error)
+	GetPermissionsByRole(ctx context.Context, accountID string, role types.UserRole) (roles.Permissions, error)
 	SetAccountManager(accountManager account.Manager)
 }
 
@@ -68,18 +68,44 @@ func (m *managerImpl) ValidateUserPermissions(
 		return false, err
 	}
 
-	if operation == operations.Read && user.IsServiceUser {
+	if operation == operations.Read && user.IsServiceUser && !user.Role.IsCustom() {
 		return true, nil // this should be replaced by proper granular access role
 	}
 
-	role, ok := roles.RolesMap[user.Role]
-	if !ok {
-		return false, status.NewUserRoleNotFoundError(string(user.Role))
+	role, err := m.getRolePermissions(ctx, accountID, user.Role)
+	if err != nil {
+		return false, err
 	}
 
 	return m.ValidateRoleModuleAccess(ctx, accountID, role, module, operation), nil
 }
 
+func (m *managerImpl) getRolePermissions(ctx context.Context, accountID string, role types.UserRole) (roles.RolePermissions, error) {
+	return GetRolePermissions(ctx, m.store, accountID, role)
+}
+
+// GetRolePermissions resolves built-in roles from the static roles map and custom roles from the given store,
+// which can be a transaction
+func GetRolePermissions(ctx context.Context, s store.Store, accountID string, role types.UserRole) (roles.RolePermissions, error) {
+	if !role.IsCustom() {
+		rolePermissions, ok := roles.RolesMap[role]
+		if !ok {
+			return roles.RolePermissions{}, status.NewUserRoleNotFoundError(string(role))
+		}
+		return rolePermissions, nil
+	}
+
+	customRole, err := s.GetCustomRoleByID(ctx, store.LockingStrengthNone, accountID, role.CustomRoleID())
+	if err != nil {
+		if sErr, ok := status.FromError(err); ok && sErr.Type() == status.NotFound {
+			return roles.RolePermissions{}, status.NewUserRoleNotFoundError(string(role))
+		}
+		return roles.RolePermissions{}, err
+	}
+
+	return customRole.RolePermissions(), nil
+}
+
 func (m *managerImpl) ValidateRoleModuleAccess(
 	ctx context.Context,
 	accountID string,
@@ -105,10 +131,10 @@ func (m *managerImpl) ValidateAccountAccess(ctx context.Context, accountID strin
 	return nil
 }
 
-func (m *managerImpl) GetPermissionsByRole(ctx context.Context, role types.UserRole) (roles.Permissions, error) {
-	roleMap, ok := roles.RolesMap[role]
-	if !ok {
-		return roles.Permissions{}, status.NewUserRoleNotFoundError(string(role))
+func (m *managerImpl) GetPermissionsByRole(ctx context.Context, accountID string, role types.UserRole) (roles.Permissions, error) {
+	roleMap, err := m.getRolePermissions(ctx, accountID, role)
+	if err != nil {
+		return roles.Permissions{}, err
 	}
 
 	permissions := roles.Permissions{}
@@ -17,6 +17,8 @@ const (
 	SetupKeys         Module = "setup_keys"
 	Pats              Module = "pats"
 	IdentityProviders Module = "identity_providers"
+	Roles             Module = "roles"
+	AccessRequests    Module = "access_requests"
 )
 
 var All = map[Module]struct{}{
@@ -34,4 +36,6 @@ var All = map[Module]struct{}{
 	SetupKeys:         {},
 	Pats:              {},
 	IdentityProviders: {},
+	Roles:             {},
+	AccessRequests:    {},
 }
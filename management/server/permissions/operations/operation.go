@@ -8,3 +8,10 @@ const (
 	Update Operation = "update"
 	Delete Operation = "delete"
 )
+
+var All = map[Operation]struct{}{
+	Create: {},
+	Read:   {},
+	Update: {},
+	Delete: {},
+}
@@ -21,3 +21,23 @@ var RolesMap = map[types.UserRole]RolePermissions{
 	types.UserRoleAuditor:      Auditor,
 	types.UserRoleNetworkAdmin: NetworkAdmin,
 }
+
+// Allows returns true if the role allows the operation on the module
+func (r RolePermissions) Allows(module modules.Module, operation operations.Operation) bool {
+	if permissions, ok := r.Permissions[module]; ok {
+		return permissions[operation]
+	}
+	return r.AutoAllowNew[operation]
+}
+
+// Covers returns true if the role allows every operation that the other role allows
+func (r RolePermissions) Covers(other RolePermissions) bool {
+	for module := range modules.All {
+		for operation := range operations.All {
+			if other.Allows(module, operation) && !r.Allows(module, operation) {
+				return false
+			}
+		}
+	}
+	return true
+}
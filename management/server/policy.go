@@ -3,9 +3,12 @@ package server
 import (
 	"context"
 	_ "embed"
+	"slices"
 
 	"github.com/rs/xid"
+	"golang.org/x/exp/maps"
 
+	resourceTypes "github.com/netbirdio/netbird/management/server/networks/resources/types"
 	"github.com/netbirdio/netbird/management/server/permissions/modules"
 	"github.com/netbirdio/netbird/management/server/permissions/operations"
 	"github.com/netbirdio/netbird/management/server/store"
@@ -79,9 +82,43 @@ func (am *DefaultAccountManager) SavePolicy(ctx context.Context, accountID, user
 		am.UpdateAccountPeers(ctx, accountID)
 	}
 
+	am.schedulePolicyTransitions(ctx, accountID)
+
 	return policy, nil
 }
 
+// DryRunSavePolicy validates the policy like SavePolicy, but instead of persisting it returns the peers
+// whose network maps would change if the policy was saved.
+func (am *DefaultAccountManager) DryRunSavePolicy(ctx context.Context, accountID, userID string, policy *types.Policy, create bool) ([]*types.PeerNetworkMapDiff, error) {
+	operation := operations.Create
+	if !create {
+		operation = operations.Update
+	}
+	allowed, err := am.permissionsManager.ValidateUserPermissions(ctx, accountID, userID, modules.Policies, operation)
+	if err != nil {
+		return nil, status.NewPermissionValidationError(err)
+	}
+	if !allowed {
+		return nil, status.NewPermissionDeniedError()
+	}
+
+	if err = validatePolicy(ctx, am.Store, accountID, policy); err != nil {
+		return nil, err
+	}
+
+	account, err := am.Store.GetAccount(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	validatedPeers, err := am.integratedPeerValidator.GetValidatedPeers(ctx, accountID, maps.Values(account.Groups), maps.Values(account.Peers), account.Settings.Extra)
+	if err != nil {
+		return nil, err
+	}
+
+	return types.DiffPeerNetworkMaps(ctx, account, account.WithPolicies(policy), validatedPeers), nil
+}
+
 // DeletePolicy from the store
 func (am *DefaultAccountManager) DeletePolicy(ctx context.Context, accountID, policyID, userID string) error {
 	allowed, err := am.permissionsManager.ValidateUserPermissions(ctx, accountID, userID, modules.Policies, operations.Delete)
@@ -122,6 +159,10 @@ func (am *DefaultAccountManager) DeletePolicy(ctx context.Context, accountID, po
 		am.UpdateAccountPeers(ctx, accountID)
 	}
 
+	if policy.Schedule != nil {
+		am.schedulePolicyTransitions(ctx, accountID)
+	}
+
 	return nil
 }
 
@@ -138,6 +179,62 @@ func (am *DefaultAccountManager) ListPolicies(ctx context.Context, accountID, us
 	return am.Store.GetAccountPolicies(ctx, store.LockingStrengthNone, accountID)
 }
 
+// SimulatePolicy checks whether the traffic described by the simulation is allowed by the account policies.
+// The given policies overlay the current ones without being persisted: a policy with an existing ID replaces
+// the stored one and a policy without ID is added, which allows evaluating a hypothetical policy set.
+func (am *DefaultAccountManager) SimulatePolicy(ctx context.Context, accountID, userID string, simulation types.PolicySimulation, policies []*types.Policy) (*types.PolicySimulationResult, error) {
+	allowed, err := am.permissionsManager.ValidateUserPermissions(ctx, accountID, userID, modules.Policies, operations.Read)
+	if err != nil {
+		return nil, status.NewPermissionValidationError(err)
+	}
+	if !allowed {
+		return nil, status.NewPermissionDeniedError()
+	}
+
+	if err = simulation.Validate(); err != nil {
+		return nil, status.Errorf(status.InvalidArgument, "%s", err.Error())
+	}
+
+	for _, policy := range policies {
+		if err = validatePolicy(ctx, am.Store, accountID, policy); err != nil {
+			return nil, err
+		}
+	}
+
+	account, err := am.Store.GetAccount(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(policies) > 0 {
+		account = account.WithPolicies(policies...)
+	}
+
+	if account.GetPeer(simulation.SourcePeerID) == nil {
+		return nil, status.NewPeerNotFoundError(simulation.SourcePeerID)
+	}
+	if simulation.DestinationPeerID != "" && account.GetPeer(simulation.DestinationPeerID) == nil {
+		return nil, status.NewPeerNotFoundError(simulation.DestinationPeerID)
+	}
+	if simulation.DestinationResourceID != "" && !slices.ContainsFunc(account.NetworkResources, func(r *resourceTypes.NetworkResource) bool {
+		return r.ID == simulation.DestinationResourceID
+	}) {
+		return nil, status.NewNetworkResourceNotFoundError(simulation.DestinationResourceID)
+	}
+
+	validatedPeers, err := am.integratedPeerValidator.GetValidatedPeers(ctx, accountID, maps.Values(account.Groups), maps.Values(account.Peers), account.Settings.Extra)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := account.SimulatePolicy(ctx, simulation, validatedPeers)
+	if err != nil {
+		return nil, status.Errorf(status.InvalidArgument, "%s", err.Error())
+	}
+
+	return result, nil
+}
+
 // arePolicyChangesAffectPeers checks if changes to a policy will affect any associated peers.
 func arePolicyChangesAffectPeers(ctx context.Context, transaction store.Store, accountID string, policy *types.Policy, isUpdate bool) (bool, error) {
 	if isUpdate {
@@ -146,7 +243,7 @@ func arePolicyChangesAffectPeers(ctx context.Context, transaction store.Store, a
 			return false, err
 		}
 
-		if !policy.Enabled && !existingPolicy.Enabled {
+		if !policy.IsActive() && !existingPolicy.IsActive() {
 			return false, nil
 		}
 
@@ -177,6 +274,10 @@ func arePolicyChangesAffectPeers(ctx context.Context, transaction store.Store, a
 
 // validatePolicy validates the policy and its rules.
 func validatePolicy(ctx context.Context, transaction store.Store, accountID string, policy *types.Policy) error {
+	if err := policy.Schedule.Validate(); err != nil {
+		return status.Errorf(status.InvalidArgument, "%s", err.Error())
+	}
+
 	if policy.ID != "" {
 		existingPolicy, err := transaction.GetPolicyByID(ctx, store.LockingStrengthNone, accountID, policy.ID)
 		if err != nil {
@@ -0,0 +1,118 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/formatter/hook"
+	"github.com/netbirdio/netbird/management/server/activity"
+	nbcontext "github.com/netbirdio/netbird/management/server/context"
+	"github.com/netbirdio/netbird/management/server/store"
+)
+
+// policyScheduleJob pushes a new network map to the account peers when a scheduled policy becomes active or inactive
+// and returns the duration until the next schedule transition of the account if found
+func (am *DefaultAccountManager) policyScheduleJob(ctx context.Context, accountID string) func() (time.Duration, bool) {
+	lastRun := time.Now().UTC()
+
+	return func() (time.Duration, bool) {
+		//nolint
+		ctx := context.WithValue(ctx, nbcontext.AccountIDKey, accountID)
+		//nolint
+		ctx = context.WithValue(ctx, hook.ExecutionContextKey, fmt.Sprintf("%s-POLICY-SCHEDULE", hook.SystemSource))
+
+		now := time.Now().UTC()
+
+		policies, err := am.Store.GetAccountPolicies(ctx, store.LockingStrengthNone, accountID)
+		if err != nil {
+			log.WithContext(ctx).Errorf("failed to get policies of account %s: %v", accountID, err)
+			return peerSchedulerRetryInterval, true
+		}
+
+		err = am.Store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+			return transaction.IncrementNetworkSerial(ctx, accountID)
+		})
+		if err != nil {
+			log.WithContext(ctx).Errorf("failed to increment network serial of account %s: %v", accountID, err)
+			return peerSchedulerRetryInterval, true
+		}
+
+		for _, policy := range policies {
+			if policy.Enabled && policy.Schedule.IsExpiredAt(now) && !policy.Schedule.IsExpiredAt(lastRun) {
+				am.StoreEvent(ctx, activity.SystemInitiator, policy.ID, accountID, activity.PolicyExpired, policy.EventMeta())
+			}
+		}
+		lastRun = now
+
+		log.WithContext(ctx).Debugf("updating account %s peers on policy schedule transition", accountID)
+		am.UpdateAccountPeers(ctx, accountID)
+
+		return am.getNextPolicyScheduleTransition(ctx, accountID)
+	}
+}
+
+// schedulePolicyTransitions replaces the policy schedule job of the account with one that runs on the next transition
+func (am *DefaultAccountManager) schedulePolicyTransitions(ctx context.Context, accountID string) {
+	am.policySchedules.Cancel(ctx, []string{accountID})
+	if nextRun, ok := am.getNextPolicyScheduleTransition(ctx, accountID); ok {
+		go am.policySchedules.Schedule(ctx, nextRun, accountID, am.policyScheduleJob(ctx, accountID))
+	}
+}
+
+// scheduleAllPolicyTransitions schedules the policy schedule job of every account that has scheduled policies.
+// It is called once on startup, afterwards the jobs are rescheduled whenever a policy is saved or deleted.
+func (am *DefaultAccountManager) scheduleAllPolicyTransitions(ctx context.Context) {
+	accountIDs, err := am.Store.GetAccountIDsWithScheduledPolicies(ctx)
+	if err != nil {
+		log.WithContext(ctx).Errorf("failed to get accounts with scheduled policies: %v", err)
+		return
+	}
+
+	for _, accountID := range accountIDs {
+		am.schedulePolicyTransitions(ctx, accountID)
+	}
+}
+
+// getNextPolicyScheduleTransition returns the minimum duration in which an enabled policy of the account
+// becomes active or inactive according to its schedule.
+// If there is no such policy this function returns false and a duration of 0.
+func (am *DefaultAccountManager) getNextPolicyScheduleTransition(ctx context.Context, accountID string) (time.Duration, bool) {
+	policies, err := am.Store.GetAccountPolicies(ctx, store.LockingStrengthNone, accountID)
+	if err != nil {
+		log.WithContext(ctx).Errorf("failed to get policies: %v", err)
+		return peerSchedulerRetryInterval, true
+	}
+
+	now := time.Now().UTC()
+
+	var nextTransition *time.Duration
+	for _, policy := range policies {
+		if !policy.Enabled || policy.Schedule == nil {
+			continue
+		}
+
+		next, ok := policy.Schedule.NextTransition(now)
+		if !ok {
+			continue
+		}
+
+		duration := next.Sub(now)
+		if nextTransition == nil || duration < *nextTransition {
+			// if the transition is below 1s return 1s duration
+			// this avoids issues with ticker that can't be set to < 0
+			if duration < time.Second {
+				return time.Second, true
+			}
+			nextTransition = &duration
+		}
+	}
+
+	if nextTransition == nil {
+		return 0, false
+	}
+
+	return *nextTransition, true
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"golang.org/x/exp/slices"
 
 	nbpeer "github.com/netbirdio/netbird/management/server/peer"
@@ -741,6 +742,68 @@ func TestAccount_getPeersByPolicyDirect(t *testing.T) {
 	})
 }
 
+func TestAccount_getPeersByPolicySchedule(t *testing.T) {
+	expired := time.Now().UTC().Add(-time.Hour)
+	account := &types.Account{
+		Peers: map[string]*nbpeer.Peer{
+			"peerA": {
+				ID:     "peerA",
+				IP:     net.ParseIP("100.65.14.88"),
+				Status: &nbpeer.PeerStatus{},
+			},
+			"peerB": {
+				ID:     "peerB",
+				IP:     net.ParseIP("100.65.80.39"),
+				Status: &nbpeer.PeerStatus{},
+			},
+		},
+		Groups: map[string]*types.Group{
+			"GroupAll": {
+				ID:    "GroupAll",
+				Name:  "All",
+				Peers: []string{"peerA", "peerB"},
+			},
+		},
+		Policies: []*types.Policy{
+			{
+				ID:       "RuleContractor",
+				Name:     "Contractor",
+				Enabled:  true,
+				Schedule: &types.PolicySchedule{NotAfter: &expired},
+				Rules: []*types.PolicyRule{
+					{
+						ID:            "RuleContractor",
+						Name:          "Contractor",
+						Bidirectional: true,
+						Enabled:       true,
+						Protocol:      types.PolicyRuleProtocolALL,
+						Action:        types.PolicyTrafficActionAccept,
+						Sources:       []string{"GroupAll"},
+						Destinations:  []string{"GroupAll"},
+					},
+				},
+			},
+		},
+	}
+
+	approvedPeers := map[string]struct{}{"peerA": {}, "peerB": {}}
+
+	t.Run("expired policy is not applied", func(t *testing.T) {
+		peers, firewallRules, _, _ := account.GetPeerConnectionResources(context.Background(), account.Peers["peerA"], approvedPeers, account.GetActiveGroupUsers())
+		assert.Empty(t, peers)
+		assert.Empty(t, firewallRules)
+	})
+
+	t.Run("policy within its validity is applied", func(t *testing.T) {
+		notAfter := time.Now().UTC().Add(time.Hour)
+		account.Policies[0].Schedule.NotAfter = &notAfter
+
+		peers, firewallRules, _, _ := account.GetPeerConnectionResources(context.Background(), account.Peers["peerA"], approvedPeers, account.GetActiveGroupUsers())
+		assert.Contains(t, peers, account.Peers["peerB"])
+		assert.NotEmpty(t, firewallRules)
+	})
+}
+
 func TestAccount_getPeersByPolicyPostureChecks(t *testing.T) {
 	account := &types.Account{
 		Peers: map[string]*nbpeer.Peer{
@@ -1417,3 +1480,116 @@ func TestPolicyAccountPeersUpdate(t *testing.T) {
 	})
 
 }
+
+func TestDefaultAccountManager_SimulatePolicy(t *testing.T) {
+	manager, _, account, peer1, peer2, _ := setupNetworkMapTest(t)
+
+	policies, err := manager.ListPolicies(context.Background(), account.Id, userID)
+	require.NoError(t, err)
+	require.Len(t, policies, 1)
+	defaultPolicy := policies[0]
+
+	simulation := types.PolicySimulation{
+		SourcePeerID:      peer1.ID,
+		DestinationPeerID: peer2.ID,
+		Protocol:          types.PolicyRuleProtocolTCP,
+		Port:              22,
+	}
+
+	t.Run("current policies", func(t *testing.T) {
+		result, err := manager.SimulatePolicy(context.Background(), account.Id, userID, simulation, nil)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		require.Len(t, result.Rules, 1)
+		assert.Equal(t, defaultPolicy.ID, result.Rules[0].PolicyID)
+		assert.True(t, result.Rules[0].Applied)
+	})
+
+	t.Run("hypothetical policies", func(t *testing.T) {
+		disabled := defaultPolicy.Copy()
+		disabled.Enabled = false
+
+		result, err := manager.SimulatePolicy(context.Background(), account.Id, userID, simulation, []*types.Policy{disabled})
+		require.NoError(t, err)
+		assert.False(t, result.Allowed)
+		assert.Equal(t, "no policy rule matches the traffic", result.Reason)
+
+		stored, err := manager.GetPolicy(context.Background(), account.Id, defaultPolicy.ID, userID)
+		require.NoError(t, err)
+		assert.True(t, stored.Enabled, "simulation should not persist the policies")
+	})
+
+	t.Run("unknown peer", func(t *testing.T) {
+		unknown := simulation
+		unknown.DestinationPeerID = "unknown"
+		_, err := manager.SimulatePolicy(context.Background(), account.Id, userID, unknown, nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestDefaultAccountManager_DryRunSavePolicy(t *testing.T) {
+	manager, _, account, peer1, peer2, peer3 := setupNetworkMapTest(t)
+
+	policies, err := manager.ListPolicies(context.Background(), account.Id, userID)
+	require.NoError(t, err)
+	require.Len(t, policies, 1)
+
+	disabled := policies[0].Copy()
+	disabled.Enabled = false
+
+	diffs, err := manager.DryRunSavePolicy(context.Background(), account.Id, userID, disabled, false)
+	require.NoError(t, err)
+	require.Len(t, diffs, 3)
+
+	peerIDs := []string{peer1.ID, peer2.ID, peer3.ID}
+	for _, diff := range diffs {
+		assert.Contains(t, peerIDs, diff.PeerID)
+		assert.Len(t, diff.RemovedPeers, 2)
+		assert.NotEmpty(t, diff.RemovedFirewallRules)
+		assert.Empty(t, diff.AddedFirewallRules)
+	}
+
+	stored, err := manager.GetPolicy(context.Background(), account.Id, disabled.ID, userID)
+	require.NoError(t, err)
+	assert.True(t, stored.Enabled, "dry run should not persist the policy")
+
+	_, err = manager.DryRunSavePolicy(context.Background(), account.Id, userID, &types.Policy{ID: "unknown"}, false)
+	assert.Error(t, err)
+}
+
+func TestDefaultAccountManager_ScheduleAllPolicyTransitions(t *testing.T) {
+	manager, _, account, _, _, _ := setupNetworkMapTest(t)
+	ctx := context.Background()
+
+	notAfter := time.Now().UTC().Add(time.Hour)
+	scheduled := &types.Policy{
+		ID:        "scheduledPolicy",
+		AccountID: account.Id,
+		Name:      "Scheduled",
+		Enabled:   true,
+		Schedule:  &types.PolicySchedule{NotAfter: &notAfter},
+	}
+	unscheduled := &types.Policy{
+		ID:        "unscheduledPolicy",
+		AccountID: account.Id,
+		Name:      "Unscheduled",
+		Enabled:   true,
+	}
+	require.NoError(t, manager.Store.CreatePolicy(ctx, scheduled))
+	require.NoError(t, manager.Store.CreatePolicy(ctx, unscheduled))
+
+	accountIDs, err := manager.Store.GetAccountIDsWithScheduledPolicies(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{account.Id}, accountIDs)
+
+	require.False(t, manager.policySchedules.IsSchedulerRunning(account.Id))
+
+	manager.scheduleAllPolicyTransitions(ctx)
+	t.Cleanup(func() {
+		manager.policySchedules.Cancel(ctx, []string{account.Id})
+	})
+
+	assert.Eventually(t, func() bool {
+		return manager.policySchedules.IsSchedulerRunning(account.Id)
+	}, time.Second, 10*time.Millisecond)
+}
@@ -3,13 +3,17 @@ package posture
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/netip"
 	"regexp"
+	"slices"
+	"time"
 
 	"github.com/hashicorp/go-version"
 	nbpeer "github.com/netbirdio/netbird/management/server/peer"
 	"github.com/netbirdio/netbird/shared/management/http/api"
 	"github.com/netbirdio/netbird/shared/management/status"
+	"github.com/netbirdio/netbird/util/crypt"
 )
 
 const (
@@ -18,6 +22,9 @@ const (
 	GeoLocationCheckName      = "GeoLocationCheck"
 	PeerNetworkRangeCheckName = "PeerNetworkRangeCheck"
 	ProcessCheckName          = "ProcessCheck"
+	ExternalCheckName         = "ExternalCheck"
+	DiskEncryptionCheckName   = "DiskEncryptionCheck"
+	OSFirewallCheckName       = "OSFirewallCheck"
 
 	CheckActionAllow string = "allow"
 	CheckActionDeny  string = "deny"
@@ -58,6 +65,9 @@ type ChecksDefinition struct {
 	GeoLocationCheck      *GeoLocationCheck      `json:",omitempty"`
 	PeerNetworkRangeCheck *PeerNetworkRangeCheck `json:",omitempty"`
 	ProcessCheck          *ProcessCheck          `json:",omitempty"`
+	ExternalCheck         *ExternalCheck         `json:",omitempty"`
+	DiskEncryptionCheck   *DiskEncryptionCheck   `json:",omitempty"`
+	OSFirewallCheck       *OSFirewallCheck       `json:",omitempty"`
 }
 
 // Copy returns a copy of a checks definition.
@@ -110,6 +120,20 @@ func (cd ChecksDefinition) Copy() ChecksDefinition {
 		}
 		copy(cdCopy.ProcessCheck.Processes, processCheck.Processes)
 	}
+	if cd.ExternalCheck != nil {
+		externalCheck := *cd.ExternalCheck
+		cdCopy.ExternalCheck = &externalCheck
+	}
+	if cd.DiskEncryptionCheck != nil {
+		cdCopy.DiskEncryptionCheck = &DiskEncryptionCheck{
+			Volumes: slices.Clone(cd.DiskEncryptionCheck.Volumes),
+		}
+	}
+	if cd.OSFirewallCheck != nil {
+		cdCopy.OSFirewallCheck = &OSFirewallCheck{
+			Backends: slices.Clone(cd.OSFirewallCheck.Backends),
+		}
+	}
 	return cdCopy
 }
 
@@ -130,6 +154,43 @@ func (pc *Checks) Copy() *Checks {
 	return checks
 }
 
+// EncryptSensitiveData encrypts the secret of the external check in place.
+func (pc *Checks) EncryptSensitiveData(enc *crypt.FieldEncrypt) error {
+	if enc == nil || pc.Checks.ExternalCheck == nil || pc.Checks.ExternalCheck.Secret == "" {
+		return nil
+	}
+
+	secret, err := enc.Encrypt(pc.Checks.ExternalCheck.Secret)
+	if err != nil {
+		return fmt.Errorf("encrypt external check secret: %w", err)
+	}
+	pc.Checks.ExternalCheck.Secret = secret
+
+	return nil
+}
+
+// DecryptSensitiveData decrypts the secret of the external check in place.
+func (pc *Checks) DecryptSensitiveData(enc *crypt.FieldEncrypt) error {
+	if enc == nil || pc.Checks.ExternalCheck == nil || pc.Checks.ExternalCheck.Secret == "" {
+		return nil
+	}
+
+	secret, err := enc.Decrypt(pc.Checks.ExternalCheck.Secret)
+	if err != nil {
+		return fmt.Errorf("decrypt external check secret: %w", err)
+	}
+	pc.Checks.ExternalCheck.Secret = secret
+
+	return nil
+}
+
+// BindExternalVerdicts sets the verdict cache used by the external check of the posture checks.
+func (pc *Checks) BindExternalVerdicts(verdicts *ExternalVerdicts) {
+	if pc.Checks.ExternalCheck != nil {
+		pc.Checks.ExternalCheck.verdicts = verdicts
+	}
+}
+
 // EventMeta returns activity event meta-related to this posture checks.
 func (pc *Checks) EventMeta() map[string]any {
 	return map[string]any{"name": pc.Name}
@@ -153,6 +214,15 @@ func (pc *Checks) GetChecks() []Check {
 	if pc.Checks.ProcessCheck != nil {
 		checks = append(checks, pc.Checks.ProcessCheck)
 	}
+	if pc.Checks.ExternalCheck != nil {
+		checks = append(checks, pc.Checks.ExternalCheck)
+	}
+	if pc.Checks.DiskEncryptionCheck != nil {
+		checks = append(checks, pc.Checks.DiskEncryptionCheck)
+	}
+	if pc.Checks.OSFirewallCheck != nil {
+		checks = append(checks, pc.Checks.OSFirewallCheck)
+	}
 	return checks
 }
 
@@ -208,6 +278,18 @@ func buildPostureCheck(postureChecksID string, name string, description string,
 		postureChecks.Checks.ProcessCheck = toProcessCheck(processCheck)
 	}
 
+	if externalCheck := checks.ExternalCheck; externalCheck != nil {
+		postureChecks.Checks.ExternalCheck = toExternalCheck(externalCheck)
+	}
+
+	if diskEncryptionCheck := checks.DiskEncryptionCheck; diskEncryptionCheck != nil {
+		postureChecks.Checks.DiskEncryptionCheck = toDiskEncryptionCheck(diskEncryptionCheck)
+	}
+
+	if osFirewallCheck := checks.OsFirewallCheck; osFirewallCheck != nil {
+		postureChecks.Checks.OSFirewallCheck = toOSFirewallCheck(osFirewallCheck)
+	}
+
 	return &postureChecks, nil
 }
 
@@ -242,6 +324,18 @@ func (pc *Checks) ToAPIResponse() *api.PostureCheck {
 		checks.ProcessCheck = toProcessCheckResponse(pc.Checks.ProcessCheck)
 	}
 
+	if pc.Checks.ExternalCheck != nil {
+		checks.ExternalCheck = toExternalCheckResponse(pc.Checks.ExternalCheck)
+	}
+
+	if pc.Checks.DiskEncryptionCheck != nil {
+		checks.DiskEncryptionCheck = toDiskEncryptionCheckResponse(pc.Checks.DiskEncryptionCheck)
+	}
+
+	if pc.Checks.OSFirewallCheck != nil {
+		checks.OsFirewallCheck = toOSFirewallCheckResponse(pc.Checks.OSFirewallCheck)
+	}
+
 	return &api.PostureCheck{
 		Id:          pc.ID,
 		Name:        pc.Name,
@@ -386,3 +480,81 @@ func toProcessCheck(check *api.ProcessCheck) *ProcessCheck {
 		Processes: processes,
 	}
 }
+
+func toExternalCheckResponse(check *ExternalCheck) *api.ExternalCheck {
+	cacheTTL := int(check.CacheTTL.Seconds())
+	timeout := int(check.timeout().Seconds())
+	insecureConn := check.Insecure
+
+	return &api.ExternalCheck{
+		Protocol: api.ExternalCheckProtocol(check.Protocol),
+		Endpoint: check.Endpoint,
+		CacheTtl: &cacheTTL,
+		Timeout:  &timeout,
+		Insecure: &insecureConn,
+	}
+}
+
+func toExternalCheck(check *api.ExternalCheck) *ExternalCheck {
+	externalCheck := &ExternalCheck{
+		Protocol: string(check.Protocol),
+		Endpoint: check.Endpoint,
+		CacheTTL: DefaultExternalCheckCacheTTL,
+	}
+	if check.CacheTtl != nil {
+		externalCheck.CacheTTL = time.Duration(*check.CacheTtl) * time.Second
+	}
+	if check.Timeout != nil {
+		externalCheck.Timeout = time.Duration(*check.Timeout) * time.Second
+	}
+	if check.Insecure != nil {
+		externalCheck.Insecure = *check.Insecure
+	}
+	if check.Secret != nil {
+		externalCheck.Secret = *check.Secret
+	}
+
+	return externalCheck
+}
+
+func toDiskEncryptionCheckResponse(check *DiskEncryptionCheck) *api.DiskEncryptionCheck {
+	volumes := slices.Clone(check.Volumes)
+	if volumes == nil {
+		volumes = []string{}
+	}
+	return &api.DiskEncryptionCheck{
+		Volumes: &volumes,
+	}
+}
+
+func toDiskEncryptionCheck(check *api.DiskEncryptionCheck) *DiskEncryptionCheck {
+	var volumes []string
+	if check.Volumes != nil {
+		volumes = slices.Clone(*check.Volumes)
+	}
+	return &DiskEncryptionCheck{
+		Volumes: volumes,
+	}
+}
+
+func toOSFirewallCheckResponse(check *OSFirewallCheck) *api.OSFirewallCheck {
+	backends := make([]api.OSFirewallCheckBackends, 0, len(check.Backends))
+	for _, backend := range check.Backends {
+		backends = append(backends, api.OSFirewallCheckBackends(backend))
+	}
+	return &api.OSFirewallCheck{
+		Backends: &backends,
+	}
+}
+
+func toOSFirewallCheck(check *api.OSFirewallCheck) *OSFirewallCheck {
+	var backends []string
+	if check.Backends != nil {
+		for _, backend := range *check.Backends {
+			backends = append(backends, string(backend))
+		}
+	}
+	return &OSFirewallCheck{
+		Backends: backends,
+	}
+}
@@ -283,6 +283,12 @@ func TestChecks_Copy(t *testing.T) {
 					},
 				},
 			},
+			DiskEncryptionCheck: &DiskEncryptionCheck{
+				Volumes: []string{"/", "/home"},
+			},
+			OSFirewallCheck: &OSFirewallCheck{
+				Backends: []string{FirewallBackendUFW},
+			},
 		},
 	}
 	checkCopy := check.Copy()
@@ -0,0 +1,73 @@
+package posture
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+)
+
+// rootVolume is the volume checked when no volumes are configured
+const rootVolume = "/"
+
+type DiskEncryptionCheck struct {
+	// Volumes are the mount points that must be backed by an encrypted disk
+	Volumes []string
+}
+
+var _ Check = (*DiskEncryptionCheck)(nil)
+
+func (d *DiskEncryptionCheck) Check(_ context.Context, peer nbpeer.Peer) (bool, error) {
+	// only linux peers report disk encryption, peers of other operating systems can't prove it and fail the check
+	if peer.Meta.GoOS != "linux" {
+		return false, nil
+	}
+
+	volumes := d.Volumes
+	if len(volumes) == 0 {
+		volumes = []string{rootVolume}
+	}
+
+	for _, volume := range volumes {
+		if !slices.Contains(peer.Meta.DiskEncryption.EncryptedVolumes, volume) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (d *DiskEncryptionCheck) Name() string {
+	return DiskEncryptionCheckName
+}
+
+func (d *DiskEncryptionCheck) Validate() error {
+	for _, volume := range d.Volumes {
+		if !path.IsAbs(volume) || path.Clean(volume) != volume {
+			return fmt.Errorf("%s volume %q should be a clean absolute mount point", d.Name(), volume)
+		}
+	}
+	return nil
+}
+
+func (d *DiskEncryptionCheck) FailureReason(peer nbpeer.Peer) string {
+	if peer.Meta.GoOS != "linux" {
+		return fmt.Sprintf("OS %s does not report disk encryption", peer.Meta.GoOS)
+	}
+
+	volumes := d.Volumes
+	if len(volumes) == 0 {
+		volumes = []string{rootVolume}
+	}
+
+	var unencrypted []string
+	for _, volume := range volumes {
+		if !slices.Contains(peer.Meta.DiskEncryption.EncryptedVolumes, volume) {
+			unencrypted = append(unencrypted, volume)
+		}
+	}
+	return fmt.Sprintf("volumes not encrypted: %s", strings.Join(unencrypted, ", "))
+}
@@ -0,0 +1,148 @@
+package posture
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netbirdio/netbird/management/server/peer"
+)
+
+func TestDiskEncryptionCheck_Check(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   peer.Peer
+		check   DiskEncryptionCheck
+		wantErr bool
+		isValid bool
+	}{
+		{
+			name: "linux with encrypted root and default volumes",
+			input: peer.Peer{
+				Meta: peer.PeerSystemMeta{
+					GoOS:           "linux",
+					DiskEncryption: peer.DiskEncryption{EncryptedVolumes: []string{"/", "/boot/efi"}},
+				},
+			},
+			check:   DiskEncryptionCheck{},
+			isValid: true,
+		},
+		{
+			name: "linux with unencrypted root",
+			input: peer.Peer{
+				Meta: peer.PeerSystemMeta{
+					GoOS:           "linux",
+					DiskEncryption: peer.DiskEncryption{EncryptedVolumes: []string{"/home"}},
+				},
+			},
+			check:   DiskEncryptionCheck{},
+			isValid: false,
+		},
+		{
+			name: "linux with all configured volumes encrypted",
+			input: peer.Peer{
+				Meta: peer.PeerSystemMeta{
+					GoOS:           "linux",
+					DiskEncryption: peer.DiskEncryption{EncryptedVolumes: []string{"/", "/home", "/var"}},
+				},
+			},
+			check:   DiskEncryptionCheck{Volumes: []string{"/home", "/var"}},
+			isValid: true,
+		},
+		{
+			name: "linux with one configured volume not encrypted",
+			input: peer.Peer{
+				Meta: peer.PeerSystemMeta{
+					GoOS:           "linux",
+					DiskEncryption: peer.DiskEncryption{EncryptedVolumes: []string{"/", "/home"}},
+				},
+			},
+			check:   DiskEncryptionCheck{Volumes: []string{"/home", "/var"}},
+			isValid: false,
+		},
+		{
+			name: "linux without reported disk encryption",
+			input: peer.Peer{
+				Meta: peer.PeerSystemMeta{
+					GoOS: "linux",
+				},
+			},
+			check:   DiskEncryptionCheck{},
+			isValid: false,
+		},
+		{
+			name: "windows peer fails",
+			input: peer.Peer{
+				Meta: peer.PeerSystemMeta{
+					GoOS: "windows",
+				},
+			},
+			check:   DiskEncryptionCheck{},
+			isValid: false,
+		},
+		{
+			name: "darwin peer fails regardless of the reported volumes",
+			input: peer.Peer{
+				Meta: peer.PeerSystemMeta{
+					GoOS:           "darwin",
+					DiskEncryption: peer.DiskEncryption{EncryptedVolumes: []string{"/"}},
+				},
+			},
+			check:   DiskEncryptionCheck{},
+			isValid: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isValid, err := tt.check.Check(context.Background(), tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.isValid, isValid)
+		})
+	}
+}
+
+func TestDiskEncryptionCheck_Validate(t *testing.T) {
+	testCases := []struct {
+		name          string
+		check         DiskEncryptionCheck
+		expectedError bool
+	}{
+		{
+			name:          "Valid empty volumes",
+			check:         DiskEncryptionCheck{},
+			expectedError: false,
+		},
+		{
+			name:          "Valid mount points",
+			check:         DiskEncryptionCheck{Volumes: []string{"/", "/home"}},
+			expectedError: false,
+		},
+		{
+			name:          "Invalid relative mount point",
+			check:         DiskEncryptionCheck{Volumes: []string{"home"}},
+			expectedError: true,
+		},
+		{
+			name:          "Invalid mount point with trailing slash",
+			check:         DiskEncryptionCheck{Volumes: []string{"/home/"}},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.check.Validate()
+			if tc.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package posture
+
+import (
+	"context"
+	"time"
+
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+)
+
+// FailureReasoner is implemented by checks that can describe why a peer didn't pass them
+type FailureReasoner interface {
+	// FailureReason returns a human-readable reason of a failed check for the peer, e.g. "OS version 13.1 < 14.0"
+	FailureReason(peer nbpeer.Peer) string
+}
+
+// Result is the outcome of a single check evaluated on a peer
+type Result struct {
+	// PostureChecksID is the ID of the posture checks the check belongs to
+	PostureChecksID string
+	// PostureChecksName is the name of the posture checks the check belongs to
+	PostureChecksName string
+	// Check is the name of the evaluated check, e.g. OSVersionCheck
+	Check string
+	// Passed indicates whether the peer passed the check
+	Passed bool
+	// Reason describes why the peer failed the check, empty if it passed
+	Reason string
+	// EvaluatedAt is the time the check was evaluated
+	EvaluatedAt time.Time
+}
+
+// Evaluate runs every check of the posture checks on the peer with the same logic used to build the network map
+// and describes the failures.
+func (pc *Checks) Evaluate(ctx context.Context, peer nbpeer.Peer) []Result {
+	checks := pc.GetChecks()
+	results := make([]Result, 0, len(checks))
+	for _, check := range checks {
+		results = append(results, EvaluateCheck(ctx, pc, check, peer))
+	}
+	return results
+}
+
+// EvaluateCheck runs a single check on the peer and describes the failure
+func EvaluateCheck(ctx context.Context, pc *Checks, check Check, peer nbpeer.Peer) Result {
+	result := Result{
+		PostureChecksID:   pc.ID,
+		PostureChecksName: pc.Name,
+		Check:             check.Name(),
+		EvaluatedAt:       time.Now().UTC(),
+	}
+
+	passed, err := check.Check(ctx, peer)
+	switch {
+	case err != nil:
+		result.Reason = err.Error()
+	case passed:
+		result.Passed = true
+	default:
+		result.Reason = "check failed"
+		if reasoner, ok := check.(FailureReasoner); ok {
+			result.Reason = reasoner.FailureReason(peer)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,131 @@
+package posture
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/management/server/peer"
+)
+
+func TestChecks_Evaluate(t *testing.T) {
+	checks := &Checks{
+		ID:   "checks",
+		Name: "production",
+		Checks: ChecksDefinition{
+			NBVersionCheck: &NBVersionCheck{MinVersion: "0.25.0"},
+			OSVersionCheck: &OSVersionCheck{
+				Darwin: &MinVersionCheck{MinVersion: "14.0"},
+			},
+			GeoLocationCheck: &GeoLocationCheck{
+				Locations: []Location{{CountryCode: "DE"}},
+				Action:    CheckActionAllow,
+			},
+		},
+	}
+
+	results := checks.Evaluate(context.Background(), peer.Peer{
+		Meta: peer.PeerSystemMeta{
+			GoOS:      "darwin",
+			OSVersion: "13.1",
+			WtVersion: "0.30.0",
+		},
+	})
+	require.Len(t, results, 3)
+
+	for _, result := range results {
+		assert.Equal(t, "checks", result.PostureChecksID)
+		assert.Equal(t, "production", result.PostureChecksName)
+		assert.False(t, result.EvaluatedAt.IsZero())
+	}
+
+	assert.Equal(t, NBVersionCheckName, results[0].Check)
+	assert.True(t, results[0].Passed)
+	assert.Empty(t, results[0].Reason)
+
+	assert.Equal(t, OSVersionCheckName, results[1].Check)
+	assert.False(t, results[1].Passed)
+	assert.Equal(t, "OS version 13.1 < 14.0", results[1].Reason)
+
+	// errors of the check are reported as the reason
+	assert.Equal(t, GeoLocationCheckName, results[2].Check)
+	assert.False(t, results[2].Passed)
+	assert.Equal(t, "peer's location is not set", results[2].Reason)
+}
+
+func TestFailureReason(t *testing.T) {
+	tests := []struct {
+		name   string
+		check  Check
+		peer   peer.Peer
+		reason string
+	}{
+		{
+			name:   "netbird version",
+			check:  &NBVersionCheck{MinVersion: "0.28.0"},
+			peer:   peer.Peer{Meta: peer.PeerSystemMeta{WtVersion: "0.27.1"}},
+			reason: "NetBird version 0.27.1 < 0.28.0",
+		},
+		{
+			name:   "linux kernel version",
+			check:  &OSVersionCheck{Linux: &MinKernelVersionCheck{MinKernelVersion: "6.1"}},
+			peer:   peer.Peer{Meta: peer.PeerSystemMeta{GoOS: "linux", KernelVersion: "5.15.0-91-generic"}},
+			reason: "kernel version 5.15.0 < 6.1",
+		},
+		{
+			name:   "os not allowed",
+			check:  &OSVersionCheck{Linux: &MinKernelVersionCheck{MinKernelVersion: "6.1"}},
+			peer:   peer.Peer{Meta: peer.PeerSystemMeta{GoOS: "ios", OSVersion: "17.0"}},
+			reason: "OS ios is not allowed",
+		},
+		{
+			name: "denied location",
+			check: &GeoLocationCheck{
+				Locations: []Location{{CountryCode: "DE", CityName: "Berlin"}},
+				Action:    CheckActionDeny,
+			},
+			peer:   peer.Peer{Location: peer.Location{CountryCode: "DE", CityName: "Berlin"}},
+			reason: "location DE/Berlin is denied",
+		},
+		{
+			name:   "process not running",
+			check:  &ProcessCheck{Processes: []Process{{LinuxPath: "/usr/bin/edr"}}},
+			peer:   peer.Peer{Meta: peer.PeerSystemMeta{GoOS: "linux"}},
+			reason: "process /usr/bin/edr is not running",
+		},
+		{
+			name:   "unencrypted volumes",
+			check:  &DiskEncryptionCheck{Volumes: []string{"/", "/home"}},
+			peer:   peer.Peer{Meta: peer.PeerSystemMeta{GoOS: "linux", DiskEncryption: peer.DiskEncryption{EncryptedVolumes: []string{"/"}}}},
+			reason: "volumes not encrypted: /home",
+		},
+		{
+			name:   "no active firewall",
+			check:  &OSFirewallCheck{},
+			peer:   peer.Peer{Meta: peer.PeerSystemMeta{GoOS: "linux"}},
+			reason: "no active firewall",
+		},
+		{
+			name:   "disk encryption on windows",
+			check:  &DiskEncryptionCheck{},
+			peer:   peer.Peer{Meta: peer.PeerSystemMeta{GoOS: "windows"}},
+			reason: "OS windows does not report disk encryption",
+		},
+		{
+			name:   "firewall on darwin",
+			check:  &OSFirewallCheck{},
+			peer:   peer.Peer{Meta: peer.PeerSystemMeta{GoOS: "darwin"}},
+			reason: "OS darwin does not report the firewall state",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := EvaluateCheck(context.Background(), &Checks{}, tt.check, tt.peer)
+			assert.False(t, result.Passed)
+			assert.Equal(t, tt.reason, result.Reason)
+		})
+	}
+}
@@ -0,0 +1,563 @@
+package posture
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/util"
+)
+
+const (
+	ExternalCheckProtocolHTTP = "http"
+	ExternalCheckProtocolGRPC = "grpc"
+
+	// ExternalCheckGRPCMethod is the unary method called on external gRPC posture services.
+	// Request and response are google.protobuf.Struct messages with the fields of ExternalCheckRequest
+	// and ExternalCheckResponse.
+	ExternalCheckGRPCMethod = "/netbird.posture.v1.ExternalPostureCheck/Evaluate"
+
+	// DefaultExternalCheckCacheTTL is used when no cache TTL is configured
+	DefaultExternalCheckCacheTTL = 5 * time.Minute
+	// DefaultExternalCheckTimeout is used when no timeout is configured
+	DefaultExternalCheckTimeout = 5 * time.Second
+	maxExternalCheckTimeout     = 30 * time.Second
+
+	// externalCheckFailureTTL is the time a failed call is cached before the external service is called again
+	externalCheckFailureTTL = 30 * time.Second
+
+	// maxExternalVerdicts is the maximum number of cached verdicts
+	maxExternalVerdicts = 10000
+	// maxExternalRefreshes limits the number of concurrent calls to external services
+	maxExternalRefreshes = 64
+)
+
+// errExternalVerdictPending is returned until the first verdict for a peer is received from the external service
+var errExternalVerdictPending = errors.New("verdict of the external service is pending")
+
+// errExternalVerdictsUnavailable is returned by checks that are not bound to the verdicts of an account manager
+var errExternalVerdictsUnavailable = errors.New("verdicts of external services are not available")
+
+// externalCheckDialer refuses connections to internal addresses, the services are configured by the accounts
+// and must not be called on the network of the management server
+var externalCheckDialer = util.NewPublicDialer(maxExternalCheckTimeout)
+
+// externalCheckHTTPClient doesn't follow redirects so the verdict is always provided by the configured endpoint
+var externalCheckHTTPClient = newExternalCheckHTTPClient()
+
+func newExternalCheckHTTPClient() *http.Client {
+	return &http.Client{
+		Transport: util.NewPublicTransport(maxExternalCheckTimeout),
+		Timeout:   maxExternalCheckTimeout,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// ExternalCheck delegates the verdict to an external compliance service, e.g. an MDM or EDR.
+// The service receives the peer system meta and answers whether the peer is compliant.
+//
+// The service is called out of band: Check only reads the cached verdict and triggers a refresh in the background
+// when it is missing or expired, the account peers are updated once a refresh changes the verdict.
+type ExternalCheck struct {
+	// Protocol is either http or grpc
+	Protocol string
+	// Endpoint is the URL for http or the address of the server for grpc
+	Endpoint string
+	// CacheTTL is the time a verdict is reused for a peer with unchanged system meta
+	CacheTTL time.Duration
+	// Timeout limits the time to wait for a verdict
+	Timeout time.Duration
+	// Insecure disables TLS for grpc, it is refused by Validate as the calls carry the secret and the peer meta
+	Insecure bool
+	// Secret is sent as bearer token to authenticate the calls to the external service
+	Secret string
+
+	// verdicts caches the verdicts of the external service, it is bound when the check is loaded from the store
+	verdicts *ExternalVerdicts
+}
+
+// ExternalCheckRequest is the body sent to the external service
+type ExternalCheckRequest struct {
+	PeerID    string                `json:"peer_id"`
+	AccountID string                `json:"account_id"`
+	Meta      ExternalCheckPeerMeta `json:"meta"`
+}
+
+// ExternalCheckPeerMeta is the peer system meta sent to the external service
+type ExternalCheckPeerMeta struct {
+	Hostname           string   `json:"hostname"`
+	GoOS               string   `json:"goos"`
+	Kernel             string   `json:"kernel"`
+	KernelVersion      string   `json:"kernel_version"`
+	Platform           string   `json:"platform"`
+	OS                 string   `json:"os"`
+	OSVersion          string   `json:"os_version"`
+	NetBirdVersion     string   `json:"netbird_version"`
+	SystemSerialNumber string   `json:"system_serial_number"`
+	SystemProductName  string   `json:"system_product_name"`
+	SystemManufacturer string   `json:"system_manufacturer"`
+	MACAddresses       []string `json:"mac_addresses"`
+}
+
+// ExternalCheckResponse is the verdict returned by the external service
+type ExternalCheckResponse struct {
+	Compliant bool `json:"compliant"`
+}
+
+var _ Check = (*ExternalCheck)(nil)
+
+func (e *ExternalCheck) Name() string {
+	return ExternalCheckName
+}
+
+// Check returns the cached verdict of the external service for the peer. It never calls the service itself,
+// a missing or expired verdict is refreshed in the background and the peer is not compliant until a verdict is known.
+func (e *ExternalCheck) Check(_ context.Context, peer nbpeer.Peer) (bool, error) {
+	if e.verdicts == nil {
+		return false, errExternalVerdictsUnavailable
+	}
+
+	request := newExternalCheckRequest(peer)
+	body, err := json.Marshal(request)
+	if err != nil {
+		return false, fmt.Errorf("marshal external check request: %w", err)
+	}
+
+	sum := sha256.Sum256(body)
+	key := e.Protocol + "|" + e.Endpoint + "|" + hex.EncodeToString(sum[:])
+
+	verdict, ok := e.verdicts.get(key)
+	if !ok || !time.Now().Before(verdict.expiresAt) {
+		e.refresh(key, body, peer.AccountID)
+	}
+	if !ok {
+		return false, errExternalVerdictPending
+	}
+	if verdict.err != nil {
+		return false, verdict.err
+	}
+
+	return verdict.compliant, nil
+}
+
+// refresh calls the external service in the background and caches the verdict, failures included
+func (e *ExternalCheck) refresh(key string, body []byte, accountID string) {
+	if !e.verdicts.startRefresh(key) {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), e.timeout())
+		defer cancel()
+
+		compliant, err := e.evaluate(ctx, body)
+		ttl := e.CacheTTL
+		if err != nil && (ttl <= 0 || ttl > externalCheckFailureTTL) {
+			ttl = externalCheckFailureTTL
+		}
+
+		if e.verdicts.finishRefresh(key, compliant, err, ttl) {
+			e.verdicts.notify(accountID)
+		}
+	}()
+}
+
+func (e *ExternalCheck) evaluate(ctx context.Context, body []byte) (bool, error) {
+	switch e.Protocol {
+	case ExternalCheckProtocolHTTP:
+		return e.checkHTTP(ctx, body)
+	case ExternalCheckProtocolGRPC:
+		return e.checkGRPC(ctx, body)
+	default:
+		return false, fmt.Errorf("unsupported protocol %s", e.Protocol)
+	}
+}
+
+func (e *ExternalCheck) FailureReason(_ nbpeer.Peer) string {
+	return fmt.Sprintf("external service %s reported the peer as non-compliant", e.Endpoint)
+}
+
+func (e *ExternalCheck) Validate() error {
+	switch e.Protocol {
+	case ExternalCheckProtocolHTTP:
+		u, err := url.Parse(e.Endpoint)
+		if err != nil || u.Scheme != "https" || u.Hostname() == "" {
+			return fmt.Errorf("%s endpoint should be an https URL", e.Name())
+		}
+		if util.IsInternalHost(u.Hostname()) {
+			return fmt.Errorf("%s endpoint can't be an internal address", e.Name())
+		}
+	case ExternalCheckProtocolGRPC:
+		host, err := externalCheckGRPCHost(e.Endpoint)
+		if err != nil {
+			return fmt.Errorf("%s endpoint should be a host:port address: %w", e.Name(), err)
+		}
+		if util.IsInternalHost(host) {
+			return fmt.Errorf("%s endpoint can't be an internal address", e.Name())
+		}
+		if e.Insecure {
+			return fmt.Errorf("%s endpoint should be called with TLS", e.Name())
+		}
+	default:
+		return fmt.Errorf("%s protocol should be %s or %s", e.Name(), ExternalCheckProtocolHTTP, ExternalCheckProtocolGRPC)
+	}
+
+	if e.CacheTTL < 0 {
+		return fmt.Errorf("%s cache TTL shouldn't be negative", e.Name())
+	}
+	if e.Timeout < 0 || e.Timeout > maxExternalCheckTimeout {
+		return fmt.Errorf("%s timeout should be at most %s", e.Name(), maxExternalCheckTimeout)
+	}
+
+	return nil
+}
+
+// externalCheckGRPCHost returns the host of a gRPC target. Only host:port and dns:///host:port targets are accepted,
+// other resolvers and authorities would connect to addresses that can't be checked.
+func externalCheckGRPCHost(target string) (string, error) {
+	address := target
+	if strings.HasPrefix(target, "dns:") {
+		u, err := url.Parse(target)
+		if err != nil {
+			return "", err
+		}
+		if u.Host != "" {
+			return "", errors.New("dns authority is not supported")
+		}
+		address = strings.TrimPrefix(u.Path, "/")
+	}
+
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", err
+	}
+	if host == "" {
+		return "", errors.New("missing host")
+	}
+	if _, err = strconv.ParseUint(port, 10, 16); err != nil {
+		return "", fmt.Errorf("invalid port %q", port)
+	}
+
+	return host, nil
+}
+
+func (e *ExternalCheck) timeout() time.Duration {
+	if e.Timeout == 0 {
+		return DefaultExternalCheckTimeout
+	}
+	return e.Timeout
+}
+
+func (e *ExternalCheck) checkHTTP(ctx context.Context, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if e.Secret != "" {
+		req.Header.Set("Authorization", "Bearer "+e.Secret)
+	}
+
+	resp, err := externalCheckHTTPClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("call %s: %w", e.Endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("call %s: unexpected status %s", e.Endpoint, resp.Status)
+	}
+
+	var verdict ExternalCheckResponse
+	if err = json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&verdict); err != nil {
+		return false, fmt.Errorf("decode verdict: %w", err)
+	}
+
+	return verdict.Compliant, nil
+}
+
+func (e *ExternalCheck) checkGRPC(ctx context.Context, body []byte) (bool, error) {
+	var fields map[string]any
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return false, fmt.Errorf("convert request: %w", err)
+	}
+
+	request, err := structpb.NewStruct(fields)
+	if err != nil {
+		return false, fmt.Errorf("convert request: %w", err)
+	}
+
+	conn, err := e.verdicts.conn(e.Endpoint, e.Insecure)
+	if err != nil {
+		return false, err
+	}
+
+	if e.Secret != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+e.Secret)
+	}
+
+	response := &structpb.Struct{}
+	if err = conn.Invoke(ctx, ExternalCheckGRPCMethod, request, response); err != nil {
+		return false, fmt.Errorf("call %s: %w", e.Endpoint, err)
+	}
+
+	compliant, ok := response.GetFields()["compliant"]
+	if !ok {
+		return false, fmt.Errorf("verdict of %s is missing the compliant field", e.Endpoint)
+	}
+
+	return compliant.GetBoolValue(), nil
+}
+
+func newExternalCheckRequest(peer nbpeer.Peer) ExternalCheckRequest {
+	macAddresses := make([]string, 0, len(peer.Meta.NetworkAddresses))
+	for _, address := range peer.Meta.NetworkAddresses {
+		if address.Mac != "" {
+			macAddresses = append(macAddresses, address.Mac)
+		}
+	}
+
+	return ExternalCheckRequest{
+		PeerID:    peer.ID,
+		AccountID: peer.AccountID,
+		Meta: ExternalCheckPeerMeta{
+			Hostname:           peer.Meta.Hostname,
+			GoOS:               peer.Meta.GoOS,
+			Kernel:             peer.Meta.Kernel,
+			KernelVersion:      peer.Meta.KernelVersion,
+			Platform:           peer.Meta.Platform,
+			OS:                 peer.Meta.OS,
+			OSVersion:          peer.Meta.OSVersion,
+			NetBirdVersion:     peer.Meta.WtVersion,
+			SystemSerialNumber: peer.Meta.SystemSerialNumber,
+			SystemProductName:  peer.Meta.SystemProductName,
+			SystemManufacturer: peer.Meta.SystemManufacturer,
+			MACAddresses:       macAddresses,
+		},
+	}
+}
+
+// ExternalCheckServer is implemented by gRPC services that provide verdicts for external posture checks
+type ExternalCheckServer interface {
+	Evaluate(ctx context.Context, request ExternalCheckRequest) (ExternalCheckResponse, error)
+}
+
+// RegisterExternalCheckServer registers the service that serves ExternalCheckGRPCMethod on a gRPC server
+func RegisterExternalCheckServer(s *grpc.Server, srv ExternalCheckServer) {
+	s.RegisterService(&externalCheckServiceDesc, srv)
+}
+
+var externalCheckServiceDesc = grpc.ServiceDesc{
+	ServiceName: "netbird.posture.v1.ExternalPostureCheck",
+	HandlerType: (*ExternalCheckServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Evaluate",
+			Handler:    evaluateExternalCheckHandler,
+		},
+	},
+}
+
+func evaluateExternalCheckHandler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := &structpb.Struct{}
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+
+	handler := func(ctx context.Context, req any) (any, error) {
+		body, err := req.(*structpb.Struct).MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+
+		var request ExternalCheckRequest
+		if err = json.Unmarshal(body, &request); err != nil {
+			return nil, err
+		}
+
+		verdict, err := srv.(ExternalCheckServer).Evaluate(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		return structpb.NewStruct(map[string]any{"compliant": verdict.Compliant})
+	}
+
+	if interceptor == nil {
+		return handler(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExternalCheckGRPCMethod,
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+type externalVerdict struct {
+	compliant bool
+	err       error
+	expiresAt time.Time
+}
+
+// ExternalVerdicts keeps the verdicts of external services and the connections to gRPC services
+type ExternalVerdicts struct {
+	mu       sync.Mutex
+	verdicts map[string]externalVerdict
+	inflight map[string]struct{}
+	conns    map[string]*grpc.ClientConn
+	closed   bool
+	listener func(accountID string)
+}
+
+// NewExternalVerdicts creates the verdict cache of the external checks. The listener is called with the account ID
+// whenever the verdict of an external service changed for a peer of the account, so the network map of the account
+// can be recalculated.
+func NewExternalVerdicts(listener func(accountID string)) *ExternalVerdicts {
+	return &ExternalVerdicts{
+		verdicts: make(map[string]externalVerdict),
+		inflight: make(map[string]struct{}),
+		conns:    make(map[string]*grpc.ClientConn),
+		listener: listener,
+	}
+}
+
+// Close closes the connections to the gRPC services, no connections are opened afterwards
+func (c *ExternalVerdicts) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closed = true
+	for key, conn := range c.conns {
+		if err := conn.Close(); err != nil {
+			log.Debugf("failed to close connection to external posture service: %v", err)
+		}
+		delete(c.conns, key)
+	}
+}
+
+// get returns the cached verdict, expired verdicts are returned as well until they are refreshed
+func (c *ExternalVerdicts) get(key string) (externalVerdict, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	verdict, ok := c.verdicts[key]
+	return verdict, ok
+}
+
+// startRefresh marks the verdict as being refreshed, it returns false if a refresh of the verdict is already running
+// or too many refreshes are running
+func (c *ExternalVerdicts) startRefresh(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.inflight[key]; ok || len(c.inflight) >= maxExternalRefreshes {
+		return false
+	}
+	c.inflight[key] = struct{}{}
+
+	return true
+}
+
+// finishRefresh stores the refreshed verdict and returns true if it differs from the previous one
+func (c *ExternalVerdicts) finishRefresh(key string, compliant bool, err error, ttl time.Duration) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.inflight, key)
+
+	previous, existed := c.verdicts[key]
+	if !existed {
+		c.evict()
+	}
+
+	c.verdicts[key] = externalVerdict{
+		compliant: compliant,
+		err:       err,
+		expiresAt: time.Now().Add(ttl),
+	}
+
+	return !existed || previous.compliant != compliant || (previous.err == nil) != (err == nil)
+}
+
+// evict makes room for a new verdict by removing the expired verdicts, or the one expiring first if none expired
+func (c *ExternalVerdicts) evict() {
+	if len(c.verdicts) < maxExternalVerdicts {
+		return
+	}
+
+	now := time.Now()
+	var oldestKey string
+	var oldest time.Time
+	for k, verdict := range c.verdicts {
+		if !now.Before(verdict.expiresAt) {
+			delete(c.verdicts, k)
+			continue
+		}
+		if oldestKey == "" || verdict.expiresAt.Before(oldest) {
+			oldestKey, oldest = k, verdict.expiresAt
+		}
+	}
+
+	if len(c.verdicts) >= maxExternalVerdicts {
+		delete(c.verdicts, oldestKey)
+	}
+}
+
+func (c *ExternalVerdicts) notify(accountID string) {
+	if c.listener != nil && accountID != "" {
+		c.listener(accountID)
+	}
+}
+
+func (c *ExternalVerdicts) conn(endpoint string, insecureConn bool) (*grpc.ClientConn, error) {
+	key := fmt.Sprintf("%s|%t", endpoint, insecureConn)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil, errors.New("verdicts of external services are closed")
+	}
+	if conn, ok := c.conns[key]; ok {
+		return conn, nil
+	}
+
+	creds := credentials.NewTLS(nil)
+	if insecureConn {
+		creds = insecure.NewCredentials()
+	}
+
+	dialer := func(ctx context.Context, address string) (net.Conn, error) {
+		return externalCheckDialer.DialContext(ctx, "tcp", address)
+	}
+
+	conn, err := grpc.NewClient(endpoint, grpc.WithTransportCredentials(creds), grpc.WithContextDialer(dialer), grpc.WithNoProxy())
+	if err != nil {
+		return nil, fmt.Errorf("create client for %s: %w", endpoint, err)
+	}
+	c.conns[key] = conn
+
+	return conn, nil
+}
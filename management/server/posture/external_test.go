@@ -0,0 +1,367 @@
+package posture
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/netbirdio/netbird/management/server/peer"
+)
+
+// TestMain lets the external checks call the stub services listening on the loopback address
+func TestMain(m *testing.M) {
+	externalCheckDialer = &net.Dialer{Timeout: maxExternalCheckTimeout}
+	externalCheckHTTPClient = &http.Client{Timeout: maxExternalCheckTimeout}
+	os.Exit(m.Run())
+}
+
+type stubExternalCheckServer struct {
+	calls         atomic.Int32
+	authorization atomic.Value
+	compliant     func(request ExternalCheckRequest) bool
+}
+
+func (s *stubExternalCheckServer) Evaluate(ctx context.Context, request ExternalCheckRequest) (ExternalCheckResponse, error) {
+	s.calls.Add(1)
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get("authorization")) > 0 {
+		s.authorization.Store(md.Get("authorization")[0])
+	}
+	return ExternalCheckResponse{Compliant: s.compliant(request)}, nil
+}
+
+func (s *stubExternalCheckServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var request ExternalCheckRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if authorization := r.Header.Get("Authorization"); authorization != "" {
+		s.authorization.Store(authorization)
+	}
+
+	verdict, _ := s.Evaluate(r.Context(), request)
+	_ = json.NewEncoder(w).Encode(verdict)
+}
+
+func newStubExternalCheckServer() *stubExternalCheckServer {
+	return &stubExternalCheckServer{
+		compliant: func(request ExternalCheckRequest) bool {
+			return request.Meta.SystemSerialNumber == "managed"
+		},
+	}
+}
+
+func startGRPCStub(t *testing.T, stub *stubExternalCheckServer) string {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	s := grpc.NewServer()
+	RegisterExternalCheckServer(s, stub)
+	go func() {
+		_ = s.Serve(lis)
+	}()
+	t.Cleanup(s.Stop)
+
+	return lis.Addr().String()
+}
+
+// waitForVerdict calls the check until the verdict of the external service was received in the background
+func waitForVerdict(t *testing.T, check *ExternalCheck, p peer.Peer) (bool, error) {
+	t.Helper()
+
+	var valid bool
+	var err error
+	require.Eventually(t, func() bool {
+		valid, err = check.Check(context.Background(), p)
+		return !errors.Is(err, errExternalVerdictPending)
+	}, 5*time.Second, 10*time.Millisecond)
+
+	return valid, err
+}
+
+func TestExternalCheck_Check(t *testing.T) {
+	httpStub := newStubExternalCheckServer()
+	httpServer := httptest.NewServer(httpStub)
+	defer httpServer.Close()
+
+	grpcStub := newStubExternalCheckServer()
+	grpcAddr := startGRPCStub(t, grpcStub)
+
+	tests := []struct {
+		name  string
+		check ExternalCheck
+		stub  *stubExternalCheckServer
+	}{
+		{
+			name:  "http",
+			check: ExternalCheck{Protocol: ExternalCheckProtocolHTTP, Endpoint: httpServer.URL, CacheTTL: time.Minute, Secret: "secret", verdicts: NewExternalVerdicts(nil)},
+			stub:  httpStub,
+		},
+		{
+			name:  "grpc",
+			check: ExternalCheck{Protocol: ExternalCheckProtocolGRPC, Endpoint: grpcAddr, CacheTTL: time.Minute, Insecure: true, Secret: "secret", verdicts: NewExternalVerdicts(nil)},
+			stub:  grpcStub,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			managed := peer.Peer{ID: "peer-" + tt.name, Meta: peer.PeerSystemMeta{SystemSerialNumber: "managed"}}
+			unmanaged := peer.Peer{ID: "peer-" + tt.name, Meta: peer.PeerSystemMeta{SystemSerialNumber: "unknown"}}
+
+			valid, err := tt.check.Check(context.Background(), managed)
+			assert.ErrorIs(t, err, errExternalVerdictPending, "the peer is not compliant until the verdict is known")
+			assert.False(t, valid)
+
+			valid, err = waitForVerdict(t, &tt.check, managed)
+			require.NoError(t, err)
+			assert.True(t, valid)
+
+			valid, err = waitForVerdict(t, &tt.check, unmanaged)
+			require.NoError(t, err)
+			assert.False(t, valid)
+			assert.Equal(t, int32(2), tt.stub.calls.Load())
+			assert.Equal(t, "Bearer secret", tt.stub.authorization.Load())
+		})
+	}
+}
+
+func TestExternalCheck_CheckCache(t *testing.T) {
+	stub := newStubExternalCheckServer()
+	server := httptest.NewServer(stub)
+	defer server.Close()
+
+	managed := peer.Peer{ID: "peer-cache", Meta: peer.PeerSystemMeta{SystemSerialNumber: "managed"}}
+
+	check := ExternalCheck{Protocol: ExternalCheckProtocolHTTP, Endpoint: server.URL, CacheTTL: time.Minute, verdicts: NewExternalVerdicts(nil)}
+	_, err := waitForVerdict(t, &check, managed)
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		valid, err := check.Check(context.Background(), managed)
+		require.NoError(t, err)
+		assert.True(t, valid)
+	}
+	assert.Equal(t, int32(1), stub.calls.Load(), "verdict should be served from the cache")
+
+	// changed meta must not reuse the cached verdict
+	managed.Meta.Hostname = "renamed"
+	_, err = waitForVerdict(t, &check, managed)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), stub.calls.Load())
+}
+
+func TestExternalCheck_CheckFailure(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	check := ExternalCheck{Protocol: ExternalCheckProtocolHTTP, Endpoint: server.URL, CacheTTL: time.Minute, verdicts: NewExternalVerdicts(nil)}
+	failing := peer.Peer{ID: "peer-failure"}
+
+	valid, err := waitForVerdict(t, &check, failing)
+	assert.Error(t, err)
+	assert.False(t, valid)
+
+	// the failure is cached as well
+	valid, err = check.Check(context.Background(), failing)
+	assert.Error(t, err)
+	assert.False(t, valid)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestExternalCheck_VerdictListener(t *testing.T) {
+	stub := newStubExternalCheckServer()
+	server := httptest.NewServer(stub)
+	defer server.Close()
+
+	notified := make(chan string, 10)
+	verdicts := NewExternalVerdicts(func(accountID string) {
+		notified <- accountID
+	})
+
+	check := ExternalCheck{Protocol: ExternalCheckProtocolHTTP, Endpoint: server.URL, verdicts: verdicts}
+	managed := peer.Peer{ID: "peer-listener", AccountID: "account-listener", Meta: peer.PeerSystemMeta{SystemSerialNumber: "managed"}}
+
+	_, err := waitForVerdict(t, &check, managed)
+	require.NoError(t, err)
+
+	select {
+	case accountID := <-notified:
+		assert.Equal(t, "account-listener", accountID)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the account was not notified about the new verdict")
+	}
+
+	// refreshing an unchanged verdict doesn't notify the account again
+	require.Eventually(t, func() bool {
+		_, _ = check.Check(context.Background(), managed)
+		return stub.calls.Load() >= 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	select {
+	case <-notified:
+		t.Fatal("the account was notified about an unchanged verdict")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestExternalVerdicts_Bounded(t *testing.T) {
+	cache := NewExternalVerdicts(nil)
+	for i := 0; i < maxExternalVerdicts+10; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		require.True(t, cache.startRefresh(key))
+		cache.finishRefresh(key, true, nil, time.Duration(i+1)*time.Minute)
+	}
+
+	assert.Len(t, cache.verdicts, maxExternalVerdicts)
+	_, ok := cache.get("key-0")
+	assert.False(t, ok, "the verdict expiring first should be evicted")
+	_, ok = cache.get(fmt.Sprintf("key-%d", maxExternalVerdicts+9))
+	assert.True(t, ok)
+}
+
+func TestExternalVerdicts_Close(t *testing.T) {
+	grpcAddr := startGRPCStub(t, newStubExternalCheckServer())
+
+	verdicts := NewExternalVerdicts(nil)
+	conn, err := verdicts.conn(grpcAddr, true)
+	require.NoError(t, err)
+
+	verdicts.Close()
+	assert.Equal(t, connectivity.Shutdown, conn.GetState(), "the connections are closed")
+
+	_, err = verdicts.conn(grpcAddr, true)
+	assert.Error(t, err, "no connections are opened after closing")
+}
+
+func TestExternalCheck_CheckWithoutVerdicts(t *testing.T) {
+	check := ExternalCheck{Protocol: ExternalCheckProtocolHTTP, Endpoint: "https://mdm.example.com/verdict"}
+
+	valid, err := check.Check(context.Background(), peer.Peer{ID: "peer-unbound"})
+	assert.ErrorIs(t, err, errExternalVerdictsUnavailable)
+	assert.False(t, valid)
+}
+
+func TestExternalCheck_Validate(t *testing.T) {
+	tests := []struct {
+		name          string
+		check         ExternalCheck
+		expectedError bool
+	}{
+		{
+			name:  "valid http",
+			check: ExternalCheck{Protocol: ExternalCheckProtocolHTTP, Endpoint: "https://mdm.example.com/verdict"},
+		},
+		{
+			name:  "valid grpc",
+			check: ExternalCheck{Protocol: ExternalCheckProtocolGRPC, Endpoint: "mdm.example.com:443", Timeout: 10 * time.Second},
+		},
+		{
+			name:          "http without URL scheme",
+			check:         ExternalCheck{Protocol: ExternalCheckProtocolHTTP, Endpoint: "mdm.example.com"},
+			expectedError: true,
+		},
+		{
+			name:          "http without TLS",
+			check:         ExternalCheck{Protocol: ExternalCheckProtocolHTTP, Endpoint: "http://mdm.example.com/verdict"},
+			expectedError: true,
+		},
+		{
+			name:          "http to an internal address",
+			check:         ExternalCheck{Protocol: ExternalCheckProtocolHTTP, Endpoint: "https://169.254.169.254/latest/meta-data"},
+			expectedError: true,
+		},
+		{
+			name:          "grpc to localhost",
+			check:         ExternalCheck{Protocol: ExternalCheckProtocolGRPC, Endpoint: "localhost:443"},
+			expectedError: true,
+		},
+		{
+			name:  "valid grpc with dns resolver",
+			check: ExternalCheck{Protocol: ExternalCheckProtocolGRPC, Endpoint: "dns:///mdm.example.com:443"},
+		},
+		{
+			name:          "grpc without TLS",
+			check:         ExternalCheck{Protocol: ExternalCheckProtocolGRPC, Endpoint: "mdm.example.com:443", Insecure: true, Secret: "secret"},
+			expectedError: true,
+		},
+		{
+			name:          "grpc to localhost with dns resolver",
+			check:         ExternalCheck{Protocol: ExternalCheckProtocolGRPC, Endpoint: "dns:///localhost:443"},
+			expectedError: true,
+		},
+		{
+			name:          "grpc with dns authority",
+			check:         ExternalCheck{Protocol: ExternalCheckProtocolGRPC, Endpoint: "dns://10.0.0.1:53/mdm.example.com:443"},
+			expectedError: true,
+		},
+		{
+			name:          "grpc with unix socket",
+			check:         ExternalCheck{Protocol: ExternalCheckProtocolGRPC, Endpoint: "unix:///var/run/mdm.sock"},
+			expectedError: true,
+		},
+		{
+			name:          "grpc with passthrough resolver",
+			check:         ExternalCheck{Protocol: ExternalCheckProtocolGRPC, Endpoint: "passthrough:///127.0.0.1:443"},
+			expectedError: true,
+		},
+		{
+			name:          "grpc without port",
+			check:         ExternalCheck{Protocol: ExternalCheckProtocolGRPC, Endpoint: "mdm.example.com"},
+			expectedError: true,
+		},
+		{
+			name:          "unknown protocol",
+			check:         ExternalCheck{Protocol: "ftp", Endpoint: "ftp://mdm.example.com"},
+			expectedError: true,
+		},
+		{
+			name:          "timeout too long",
+			check:         ExternalCheck{Protocol: ExternalCheckProtocolGRPC, Endpoint: "mdm.example.com:443", Timeout: time.Hour},
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.check.Validate()
+			if tt.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestExternalCheckHTTPClient_RefusesInternalAddresses(t *testing.T) {
+	stub := newStubExternalCheckServer()
+	server := httptest.NewTLSServer(stub)
+	defer server.Close()
+
+	_, err := newExternalCheckHTTPClient().Post(server.URL, "application/json", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "internal address")
+	assert.Equal(t, int32(0), stub.calls.Load())
+}
@@ -87,3 +87,15 @@ func (g *GeoLocationCheck) Validate() error {
 	}
 	return nil
 }
+
+func (g *GeoLocationCheck) FailureReason(peer nbpeer.Peer) string {
+	location := peer.Location.CountryCode
+	if peer.Location.CityName != "" {
+		location += "/" + peer.Location.CityName
+	}
+
+	if g.Action == CheckActionDeny {
+		return fmt.Sprintf("location %s is denied", location)
+	}
+	return fmt.Sprintf("location %s is not in the allowed locations", location)
+}
@@ -53,6 +53,10 @@ func (n *NBVersionCheck) Validate() error {
 	return nil
 }
 
+func (n *NBVersionCheck) FailureReason(peer nbpeer.Peer) string {
+	return fmt.Sprintf("NetBird version %s < %s", peer.Meta.WtVersion, n.MinVersion)
+}
+
 // MeetsMinVersion checks if the peer's version meets or exceeds the minimum required version
 func MeetsMinVersion(minVer, peerVer string) (bool, error) {
 	peerVer = sanitizeVersion(peerVer)
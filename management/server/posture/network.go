@@ -70,3 +70,16 @@ func (p *PeerNetworkRangeCheck) Validate() error {
 	}
 	return nil
 }
+
+func (p *PeerNetworkRangeCheck) FailureReason(peer nbpeer.Peer) string {
+	if p.Action == CheckActionDeny {
+		for _, peerNetAddr := range peer.Meta.NetworkAddresses {
+			for _, prefix := range p.Ranges {
+				if prefix.Masked() == peerNetAddr.NetIP.Masked() {
+					return fmt.Sprintf("peer network %s is in a denied range", peerNetAddr.NetIP.Masked())
+				}
+			}
+		}
+	}
+	return "none of the peer networks is in the allowed ranges"
+}
@@ -0,0 +1,68 @@
+package posture
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+)
+
+const (
+	FirewallBackendNftables  = "nftables"
+	FirewallBackendUFW       = "ufw"
+	FirewallBackendFirewalld = "firewalld"
+)
+
+var supportedFirewallBackends = []string{FirewallBackendNftables, FirewallBackendUFW, FirewallBackendFirewalld}
+
+type OSFirewallCheck struct {
+	// Backends are the firewall backends of which at least one must be active, any backend is accepted if empty
+	Backends []string
+}
+
+var _ Check = (*OSFirewallCheck)(nil)
+
+func (f *OSFirewallCheck) Check(_ context.Context, peer nbpeer.Peer) (bool, error) {
+	// only linux peers report the firewall state, peers of other operating systems can't prove it and fail the check
+	if peer.Meta.GoOS != "linux" {
+		return false, nil
+	}
+
+	activeBackends := peer.Meta.OSFirewall.ActiveBackends
+	if len(f.Backends) == 0 {
+		return len(activeBackends) > 0, nil
+	}
+
+	for _, backend := range f.Backends {
+		if slices.Contains(activeBackends, backend) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (f *OSFirewallCheck) Name() string {
+	return OSFirewallCheckName
+}
+
+func (f *OSFirewallCheck) Validate() error {
+	for _, backend := range f.Backends {
+		if !slices.Contains(supportedFirewallBackends, backend) {
+			return fmt.Errorf("%s backend %q is not supported, should be one of %v", f.Name(), backend, supportedFirewallBackends)
+		}
+	}
+	return nil
+}
+
+func (f *OSFirewallCheck) FailureReason(peer nbpeer.Peer) string {
+	if peer.Meta.GoOS != "linux" {
+		return fmt.Sprintf("OS %s does not report the firewall state", peer.Meta.GoOS)
+	}
+	if len(f.Backends) == 0 {
+		return "no active firewall"
+	}
+	return fmt.Sprintf("none of the firewall backends is active: %s", strings.Join(f.Backends, ", "))
+}
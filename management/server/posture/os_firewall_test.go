@@ -0,0 +1,132 @@
+package posture
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netbirdio/netbird/management/server/peer"
+)
+
+func TestOSFirewallCheck_Check(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   peer.Peer
+		check   OSFirewallCheck
+		wantErr bool
+		isValid bool
+	}{
+		{
+			name: "linux with any active backend",
+			input: peer.Peer{
+				Meta: peer.PeerSystemMeta{
+					GoOS:       "linux",
+					OSFirewall: peer.OSFirewall{ActiveBackends: []string{FirewallBackendNftables}},
+				},
+			},
+			check:   OSFirewallCheck{},
+			isValid: true,
+		},
+		{
+			name: "linux without active backends",
+			input: peer.Peer{
+				Meta: peer.PeerSystemMeta{
+					GoOS: "linux",
+				},
+			},
+			check:   OSFirewallCheck{},
+			isValid: false,
+		},
+		{
+			name: "linux with one of the required backends active",
+			input: peer.Peer{
+				Meta: peer.PeerSystemMeta{
+					GoOS:       "linux",
+					OSFirewall: peer.OSFirewall{ActiveBackends: []string{FirewallBackendFirewalld, FirewallBackendNftables}},
+				},
+			},
+			check:   OSFirewallCheck{Backends: []string{FirewallBackendUFW, FirewallBackendFirewalld}},
+			isValid: true,
+		},
+		{
+			name: "linux without the required backend active",
+			input: peer.Peer{
+				Meta: peer.PeerSystemMeta{
+					GoOS:       "linux",
+					OSFirewall: peer.OSFirewall{ActiveBackends: []string{FirewallBackendNftables}},
+				},
+			},
+			check:   OSFirewallCheck{Backends: []string{FirewallBackendUFW}},
+			isValid: false,
+		},
+		{
+			name: "darwin peer fails",
+			input: peer.Peer{
+				Meta: peer.PeerSystemMeta{
+					GoOS: "darwin",
+				},
+			},
+			check:   OSFirewallCheck{},
+			isValid: false,
+		},
+		{
+			name: "windows peer fails regardless of the reported backends",
+			input: peer.Peer{
+				Meta: peer.PeerSystemMeta{
+					GoOS:       "windows",
+					OSFirewall: peer.OSFirewall{ActiveBackends: []string{FirewallBackendNftables}},
+				},
+			},
+			check:   OSFirewallCheck{Backends: []string{FirewallBackendNftables}},
+			isValid: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isValid, err := tt.check.Check(context.Background(), tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.isValid, isValid)
+		})
+	}
+}
+
+func TestOSFirewallCheck_Validate(t *testing.T) {
+	testCases := []struct {
+		name          string
+		check         OSFirewallCheck
+		expectedError bool
+	}{
+		{
+			name:          "Valid empty backends",
+			check:         OSFirewallCheck{},
+			expectedError: false,
+		},
+		{
+			name:          "Valid backends",
+			check:         OSFirewallCheck{Backends: []string{FirewallBackendUFW, FirewallBackendFirewalld}},
+			expectedError: false,
+		},
+		{
+			name:          "Invalid unknown backend",
+			check:         OSFirewallCheck{Backends: []string{"pf"}},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.check.Validate()
+			if tc.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
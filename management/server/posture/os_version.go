@@ -80,6 +80,35 @@ func (c *OSVersionCheck) Validate() error {
 	return nil
 }
 
+func (c *OSVersionCheck) FailureReason(peer nbpeer.Peer) string {
+	peerGoOS := peer.Meta.GoOS
+	var minVersion *MinVersionCheck
+	var minKernelVersion *MinKernelVersionCheck
+	switch peerGoOS {
+	case "android":
+		minVersion = c.Android
+	case "darwin":
+		minVersion = c.Darwin
+	case "ios":
+		minVersion = c.Ios
+	case "linux":
+		minKernelVersion = c.Linux
+	case "windows":
+		minKernelVersion = c.Windows
+	}
+
+	switch {
+	case minVersion != nil:
+		return fmt.Sprintf("OS version %s < %s", peer.Meta.OSVersion, minVersion.MinVersion)
+	case minKernelVersion != nil && peerGoOS == "linux":
+		return fmt.Sprintf("kernel version %s < %s", strings.Split(peer.Meta.KernelVersion, "-")[0], minKernelVersion.MinKernelVersion)
+	case minKernelVersion != nil:
+		return fmt.Sprintf("kernel version %s < %s", peer.Meta.KernelVersion, minKernelVersion.MinKernelVersion)
+	default:
+		return fmt.Sprintf("OS %s is not allowed", peerGoOS)
+	}
+}
+
 func checkMinVersion(ctx context.Context, peerGoOS, peerVersion string, check *MinVersionCheck) (bool, error) {
 	if check == nil {
 		log.WithContext(ctx).Tracef("peer %s OS is not allowed in the check", peerGoOS)
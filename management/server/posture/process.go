@@ -55,6 +55,28 @@ func (p *ProcessCheck) Validate() error {
 	return nil
 }
 
+func (p *ProcessCheck) FailureReason(peer nbpeer.Peer) string {
+	activeProcesses := extractPeerActiveProcesses(peer.Meta.Files)
+	for _, process := range p.Processes {
+		var path string
+		switch peer.Meta.GoOS {
+		case "linux":
+			path = process.LinuxPath
+		case "darwin":
+			path = process.MacPath
+		case "windows":
+			path = process.WindowsPath
+		}
+		if path == "" {
+			return fmt.Sprintf("no process path is defined for %s", peer.Meta.GoOS)
+		}
+		if !slices.Contains(activeProcesses, path) {
+			return fmt.Sprintf("process %s is not running", path)
+		}
+	}
+	return "required processes are not running"
+}
+
 // areAllProcessesRunning checks if all processes specified in ProcessCheck are running.
 // It uses the provided pathSelector to get the appropriate process path for the peer's OS.
 // It returns true if all processes are running, otherwise false.
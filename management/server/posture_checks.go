@@ -50,6 +50,10 @@ func (am *DefaultAccountManager) SavePostureChecks(ctx context.Context, accountI
 		}
 
 		if isUpdate {
+			if err = keepExternalCheckSecret(ctx, transaction, accountID, postureChecks); err != nil {
+				return err
+			}
+
 			updateAccountPeers, err = arePostureCheckChangesAffectPeers(ctx, transaction, accountID, postureChecks.ID)
 			if err != nil {
 				return err
@@ -155,6 +159,26 @@ func arePostureCheckChangesAffectPeers(ctx context.Context, transaction store.St
 	return false, nil
 }
 
+// keepExternalCheckSecret keeps the stored secret of the external check if the update omits it,
+// as the secret is never returned by the API and can't be sent back by the clients.
+func keepExternalCheckSecret(ctx context.Context, transaction store.Store, accountID string, postureChecks *posture.Checks) error {
+	externalCheck := postureChecks.Checks.ExternalCheck
+	if externalCheck == nil || externalCheck.Secret != "" {
+		return nil
+	}
+
+	existing, err := transaction.GetPostureChecksByID(ctx, store.LockingStrengthNone, accountID, postureChecks.ID)
+	if err != nil {
+		return err
+	}
+
+	if existingCheck := existing.Checks.ExternalCheck; existingCheck != nil && existingCheck.Endpoint == externalCheck.Endpoint {
+		externalCheck.Secret = existingCheck.Secret
+	}
+
+	return nil
+}
+
 // validatePostureChecks validates the posture checks.
 func validatePostureChecks(ctx context.Context, transaction store.Store, accountID string, postureChecks *posture.Checks) error {
 	if err := postureChecks.Validate(); err != nil {
@@ -93,6 +93,62 @@ func TestDefaultAccountManager_PostureCheck(t *testing.T) {
 	})
 }
 
+func TestDefaultAccountManager_PostureCheck_ExternalCheckSecret(t *testing.T) {
+	am, _, err := createManager(t)
+	require.NoError(t, err)
+
+	account, err := initTestPostureChecksAccount(am)
+	require.NoError(t, err)
+
+	postureCheck, err := am.SavePostureChecks(context.Background(), account.Id, adminUserID, &posture.Checks{
+		Name: "MDM",
+		Checks: posture.ChecksDefinition{
+			ExternalCheck: &posture.ExternalCheck{
+				Protocol: posture.ExternalCheckProtocolHTTP,
+				Endpoint: "https://mdm.example.com/verdict",
+				Secret:   "secret",
+			},
+		},
+	}, true)
+	require.NoError(t, err)
+	assert.Nil(t, postureCheck.ToAPIResponse().Checks.ExternalCheck.Secret, "the secret must not be returned")
+
+	// updates without a secret keep the stored one
+	_, err = am.SavePostureChecks(context.Background(), account.Id, adminUserID, &posture.Checks{
+		ID:   postureCheck.ID,
+		Name: "MDM",
+		Checks: posture.ChecksDefinition{
+			ExternalCheck: &posture.ExternalCheck{
+				Protocol: posture.ExternalCheckProtocolHTTP,
+				Endpoint: "https://mdm.example.com/verdict",
+				Timeout:  10 * time.Second,
+			},
+		},
+	}, false)
+	require.NoError(t, err)
+
+	stored, err := am.GetPostureChecks(context.Background(), account.Id, postureCheck.ID, adminUserID)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", stored.Checks.ExternalCheck.Secret)
+
+	// the secret isn't sent to a changed endpoint
+	_, err = am.SavePostureChecks(context.Background(), account.Id, adminUserID, &posture.Checks{
+		ID:   postureCheck.ID,
+		Name: "MDM",
+		Checks: posture.ChecksDefinition{
+			ExternalCheck: &posture.ExternalCheck{
+				Protocol: posture.ExternalCheckProtocolHTTP,
+				Endpoint: "https://other.example.com/verdict",
+			},
+		},
+	}, false)
+	require.NoError(t, err)
+
+	stored, err = am.GetPostureChecks(context.Background(), account.Id, postureCheck.ID, adminUserID)
+	require.NoError(t, err)
+	assert.Empty(t, stored.Checks.ExternalCheck.Secret)
+}
+
 func initTestPostureChecksAccount(am *DefaultAccountManager) (*types.Account, error) {
 	accountID := "testingAccount"
 	domain := "example.com"
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"net/netip"
 	"slices"
-	"unicode/utf8"
 
 	"github.com/rs/xid"
 
@@ -34,102 +33,47 @@ func (am *DefaultAccountManager) GetRoute(ctx context.Context, accountID string,
 
 // checkRoutePrefixOrDomainsExistForPeers checks if a route with a given prefix exists for a single peer or multiple peer groups.
 func checkRoutePrefixOrDomainsExistForPeers(ctx context.Context, transaction store.Store, accountID string, checkRoute *route.Route, groupsMap map[string]*types.Group) error {
-	// routes can have both peer and peer_groups
-	prefix := checkRoute.Network
-	domains := checkRoute.Domains
-
-	routesWithPrefix, err := getRoutesByPrefixOrDomains(ctx, transaction, accountID, prefix, domains)
+	accountRoutes, err := transaction.GetAccountRoutes(ctx, store.LockingStrengthNone, accountID)
 	if err != nil {
 		return err
 	}
+	routesWithPrefix := types.RoutesByPrefixOrDomains(accountRoutes, checkRoute.Network, checkRoute.Domains)
 
-	// lets remember all the peers and the peer groups from routesWithPrefix
-	seenPeers := make(map[string]bool)
-	seenPeerGroups := make(map[string]bool)
-
+	groupIDs := make([]string, 0)
 	for _, prefixRoute := range routesWithPrefix {
-		// we skip route(s) with the same network ID as we want to allow updating of the existing route
-		// when creating a new route routeID is newly generated so nothing will be skipped
-		if checkRoute.ID == prefixRoute.ID {
-			continue
-		}
-
-		if prefixRoute.Peer != "" {
-			seenPeers[string(prefixRoute.ID)] = true
-		}
-
-		peerGroupsMap, err := transaction.GetGroupsByIDs(ctx, store.LockingStrengthNone, accountID, prefixRoute.PeerGroups)
-		if err != nil {
-			return err
-		}
-
-		for _, groupID := range prefixRoute.PeerGroups {
-			seenPeerGroups[groupID] = true
-
-			group, ok := peerGroupsMap[groupID]
-			if !ok || group == nil {
-				return status.Errorf(
-					status.InvalidArgument, "failed to add route with %s - peer group %s doesn't exist",
-					getRouteDescriptor(prefix, domains), groupID,
-				)
-			}
-
-			for _, pID := range group.Peers {
-				seenPeers[pID] = true
-			}
-		}
+		groupIDs = append(groupIDs, prefixRoute.PeerGroups...)
 	}
 
-	if peerID := checkRoute.Peer; peerID != "" {
-		// check that peerID exists and is not in any route as single peer or part of the group
-		_, err = transaction.GetPeerByID(context.Background(), store.LockingStrengthNone, accountID, peerID)
-		if err != nil {
-			return status.Errorf(status.InvalidArgument, "peer with ID %s not found", peerID)
-		}
+	prefixGroups, err := transaction.GetGroupsByIDs(ctx, store.LockingStrengthNone, accountID, groupIDs)
+	if err != nil {
+		return err
+	}
 
-		if _, ok := seenPeers[peerID]; ok {
-			return status.Errorf(status.AlreadyExists,
-				"failed to add route with %s - peer %s already has this route", getRouteDescriptor(prefix, domains), peerID)
-		}
+	// we validated the existence of the route groups before entering this function
+	groups := make(map[string]*types.Group, len(prefixGroups)+len(groupsMap))
+	for id, group := range prefixGroups {
+		groups[id] = group
+	}
+	for id, group := range groupsMap {
+		groups[id] = group
 	}
 
-	// check that peerGroupIDs are not in any route peerGroups list
+	peerIDs := make([]string, 0)
+	if checkRoute.Peer != "" {
+		peerIDs = append(peerIDs, checkRoute.Peer)
+	}
 	for _, groupID := range checkRoute.PeerGroups {
-		group := groupsMap[groupID] // we validated the group existence before entering this function, no need to check again.
-		if _, ok := seenPeerGroups[groupID]; ok {
-			return status.Errorf(
-				status.AlreadyExists, "failed to add route with %s - peer group %s already has this route",
-				getRouteDescriptor(prefix, domains), group.Name)
-		}
-
-		// check that the peers from peerGroupIDs groups are not the same peers we saw in routesWithPrefix
-		peersMap, err := transaction.GetPeersByIDs(ctx, store.LockingStrengthNone, accountID, group.Peers)
-		if err != nil {
-			return err
-		}
-
-		for _, id := range group.Peers {
-			if _, ok := seenPeers[id]; ok {
-				peer, ok := peersMap[id]
-				if !ok || peer == nil {
-					return status.Errorf(status.InvalidArgument, "peer with ID %s not found", id)
-				}
-
-				return status.Errorf(status.AlreadyExists,
-					"failed to add route with %s - peer %s from the group %s already has this route",
-					getRouteDescriptor(prefix, domains), peer.Name, group.Name)
-			}
+		if group, ok := groups[groupID]; ok && group != nil {
+			peerIDs = append(peerIDs, group.Peers...)
 		}
 	}
 
-	return nil
-}
-
-func getRouteDescriptor(prefix netip.Prefix, domains domain.List) string {
-	if len(domains) > 0 {
-		return fmt.Sprintf("domains [%s]", domains.SafeString())
+	peers, err := transaction.GetPeersByIDs(ctx, store.LockingStrengthNone, accountID, peerIDs)
+	if err != nil {
+		return err
 	}
-	return fmt.Sprintf("prefix %s", prefix.String())
+
+	return types.ValidateRouteConflicts(checkRoute, routesWithPrefix, groups, peers)
 }
 
 // CreateRoute creates and saves a new route
@@ -312,30 +256,14 @@ func validateRoute(ctx context.Context, transaction store.Store, accountID strin
 		return status.Errorf(status.InvalidArgument, "route provided is nil")
 	}
 
-	if routeToSave.Metric < route.MinMetric || routeToSave.Metric > route.MaxMetric {
-		return status.Errorf(status.InvalidArgument, "metric should be between %d and %d", route.MinMetric, route.MaxMetric)
-	}
-
-	if utf8.RuneCountInString(string(routeToSave.NetID)) > route.MaxNetIDChar || routeToSave.NetID == "" {
-		return status.Errorf(status.InvalidArgument, "identifier should be between 1 and %d", route.MaxNetIDChar)
-	}
-
-	if len(routeToSave.Domains) > 0 && routeToSave.Network.IsValid() {
-		return status.Errorf(status.InvalidArgument, "domains and network should not be provided at the same time")
-	}
-
-	if len(routeToSave.Domains) == 0 && !routeToSave.Network.IsValid() {
-		return status.Errorf(status.InvalidArgument, "invalid Prefix")
+	if err := routeToSave.Validate(); err != nil {
+		return err
 	}
 
 	if len(routeToSave.Domains) > 0 {
 		routeToSave.Network = getPlaceholderIP()
 	}
 
-	if routeToSave.Peer != "" && len(routeToSave.PeerGroups) != 0 {
-		return status.Errorf(status.InvalidArgument, "peer with ID and peer groups should not be provided at the same time")
-	}
-
 	groupsMap, err := validateRouteGroups(ctx, transaction, accountID, routeToSave)
 	if err != nil {
 		return err
@@ -395,22 +323,3 @@ func areRouteChangesAffectPeers(ctx context.Context, transaction store.Store, ro
 
 	return anyGroupHasPeersOrResources(ctx, transaction, route.AccountID, route.PeerGroups)
 }
-
-// GetRoutesByPrefixOrDomains return list of routes by account and route prefix
-func getRoutesByPrefixOrDomains(ctx context.Context, transaction store.Store, accountID string, prefix netip.Prefix, domains domain.List) ([]*route.Route, error) {
-	accountRoutes, err := transaction.GetAccountRoutes(ctx, store.LockingStrengthNone, accountID)
-	if err != nil {
-		return nil, err
-	}
-
-	routes := make([]*route.Route, 0)
-	for _, r := range accountRoutes {
-		dynamic := r.IsDynamic()
-		if dynamic && r.Domains.PunycodeString() == domains.PunycodeString() ||
-			!dynamic && r.Network.String() == prefix.String() {
-			routes = append(routes, r)
-		}
-	}
-
-	return routes, nil
-}
@@ -53,8 +53,10 @@ type SetupKeyUpdateOperation struct {
 
 // CreateSetupKey generates a new setup key with a given name, type, list of groups IDs to auto-assign to peers registered with this key,
 // and adds it to the specified account. A list of autoGroups IDs can be empty.
+// The constraints restrict the peers that can register with the key, they can be empty.
 func (am *DefaultAccountManager) CreateSetupKey(ctx context.Context, accountID string, keyName string, keyType types.SetupKeyType,
-	expiresIn time.Duration, autoGroups []string, usageLimit int, userID string, ephemeral bool, allowExtraDNSLabels bool) (*types.SetupKey, error) {
+	expiresIn time.Duration, autoGroups []string, usageLimit int, userID string, ephemeral bool, allowExtraDNSLabels bool,
+	constraints types.SetupKeyConstraints) (*types.SetupKey, error) {
 
 	allowed, err := am.permissionsManager.ValidateUserPermissions(ctx, accountID, userID, modules.SetupKeys, operations.Create)
 	if err != nil {
@@ -64,6 +66,10 @@ func (am *DefaultAccountManager) CreateSetupKey(ctx context.Context, accountID s
 		return nil, status.NewPermissionDeniedError()
 	}
 
+	if err = constraints.Validate(); err != nil {
+		return nil, status.Errorf(status.InvalidArgument, "invalid setup key constraints: %v", err)
+	}
+
 	var setupKey *types.SetupKey
 	var plainKey string
 	var eventsToStore []func()
@@ -75,6 +81,7 @@ func (am *DefaultAccountManager) CreateSetupKey(ctx context.Context, accountID s
 
 		setupKey, plainKey = types.GenerateSetupKey(keyName, keyType, expiresIn, autoGroups, usageLimit, ephemeral, allowExtraDNSLabels)
 		setupKey.AccountID = accountID
+		setupKey.Constraints = constraints.Copy()
 
 		events := am.prepareSetupKeyEvents(ctx, transaction, accountID, userID, autoGroups, nil, setupKey)
 		eventsToStore = append(eventsToStore, events...)
@@ -99,7 +106,8 @@ func (am *DefaultAccountManager) CreateSetupKey(ctx context.Context, accountID s
 // SaveSetupKey saves the provided SetupKey to the database overriding the existing one.
 // Due to the unique nature of a SetupKey certain properties must not be overwritten
 // (e.g. the key itself, creation date, ID, etc).
-// These properties are overwritten: AutoGroups, Revoked (only from false to true), and the UpdatedAt. The rest is copied from the existing key.
+// These properties are overwritten: AutoGroups, Revoked (only from false to true), Constraints, and the UpdatedAt.
+// The rest is copied from the existing key.
 func (am *DefaultAccountManager) SaveSetupKey(ctx context.Context, accountID string, keyToSave *types.SetupKey, userID string) (*types.SetupKey, error) {
 	if keyToSave == nil {
 		return nil, status.Errorf(status.InvalidArgument, "provided setup key to update is nil")
@@ -113,6 +121,10 @@ func (am *DefaultAccountManager) SaveSetupKey(ctx context.Context, accountID str
 		return nil, status.NewPermissionDeniedError()
 	}
 
+	if err = keyToSave.Constraints.Validate(); err != nil {
+		return nil, status.Errorf(status.InvalidArgument, "invalid setup key constraints: %v", err)
+	}
+
 	var oldKey *types.SetupKey
 	var newKey *types.SetupKey
 	var eventsToStore []func()
@@ -131,10 +143,11 @@ func (am *DefaultAccountManager) SaveSetupKey(ctx context.Context, accountID str
 			return status.Errorf(status.InvalidArgument, "can't un-revoke a revoked setup key")
 		}
 
-		// only auto groups, revoked status (from false to true) can be updated
+		// only auto groups, revoked status (from false to true) and constraints can be updated
 		newKey = oldKey.Copy()
 		newKey.AutoGroups = keyToSave.AutoGroups
 		newKey.Revoked = keyToSave.Revoked
+		newKey.Constraints = keyToSave.Constraints.Copy()
 		newKey.UpdatedAt = time.Now().UTC()
 
 		addedGroups := util.Difference(newKey.AutoGroups, oldKey.AutoGroups)
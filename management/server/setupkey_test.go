@@ -5,6 +5,8 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"fmt"
+	"net"
+	"net/netip"
 	"strings"
 	"testing"
 	"time"
@@ -12,8 +14,10 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
 
 	"github.com/netbirdio/netbird/management/server/activity"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
 	"github.com/netbirdio/netbird/management/server/types"
 	"github.com/netbirdio/netbird/shared/auth"
 )
@@ -50,7 +54,7 @@ func TestDefaultAccountManager_SaveSetupKey(t *testing.T) {
 	keyName := "my-test-key"
 
 	key, err := manager.CreateSetupKey(context.Background(), account.Id, keyName, types.SetupKeyReusable, expiresIn, []string{},
-		types.SetupKeyUnlimitedUsage, userID, false, false)
+		types.SetupKeyUnlimitedUsage, userID, false, false, types.SetupKeyConstraints{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -168,7 +172,7 @@ func TestDefaultAccountManager_CreateSetupKey(t *testing.T) {
 	for _, tCase := range []testCase{testCase1, testCase2, testCase3} {
 		t.Run(tCase.name, func(t *testing.T) {
 			key, err := manager.CreateSetupKey(context.Background(), account.Id, tCase.expectedKeyName, types.SetupKeyReusable, expiresIn,
-				tCase.expectedGroups, types.SetupKeyUnlimitedUsage, userID, false, false)
+				tCase.expectedGroups, types.SetupKeyUnlimitedUsage, userID, false, false, types.SetupKeyConstraints{})
 
 			if tCase.expectedFailure {
 				if err == nil {
@@ -210,7 +214,7 @@ func TestGetSetupKeys(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	plainKey, err := manager.CreateSetupKey(context.Background(), account.Id, "key1", types.SetupKeyReusable, time.Hour, nil, types.SetupKeyUnlimitedUsage, userID, false, false)
+	plainKey, err := manager.CreateSetupKey(context.Background(), account.Id, "key1", types.SetupKeyReusable, time.Hour, nil, types.SetupKeyUnlimitedUsage, userID, false, false, types.SetupKeyConstraints{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -436,7 +440,7 @@ func TestSetupKeyAccountPeersUpdate(t *testing.T) {
 			close(done)
 		}()
 
-		setupKey, err = manager.CreateSetupKey(context.Background(), account.Id, "key1", types.SetupKeyReusable, time.Hour, nil, 999, userID, false, false)
+		setupKey, err = manager.CreateSetupKey(context.Background(), account.Id, "key1", types.SetupKeyReusable, time.Hour, nil, 999, userID, false, false, types.SetupKeyConstraints{})
 		assert.NoError(t, err)
 
 		select {
@@ -477,7 +481,7 @@ func TestDefaultAccountManager_CreateSetupKey_ShouldNotAllowToUpdateRevokedKey(t
 		t.Fatal(err)
 	}
 
-	key, err := manager.CreateSetupKey(context.Background(), account.Id, "testName", types.SetupKeyReusable, time.Hour, nil, types.SetupKeyUnlimitedUsage, userID, false, false)
+	key, err := manager.CreateSetupKey(context.Background(), account.Id, "testName", types.SetupKeyReusable, time.Hour, nil, types.SetupKeyUnlimitedUsage, userID, false, false, types.SetupKeyConstraints{})
 	assert.NoError(t, err)
 
 	// revoke the key
@@ -492,3 +496,58 @@ func TestDefaultAccountManager_CreateSetupKey_ShouldNotAllowToUpdateRevokedKey(t
 	assert.Error(t, err, "should not allow to update revoked key")
 
 }
+
+func TestDefaultAccountManager_SetupKeyConstraints(t *testing.T) {
+	manager, _, err := createManager(t)
+	require.NoError(t, err)
+
+	userID := "testingUser"
+	account, err := manager.GetOrCreateAccountByUser(context.Background(), auth.UserAuth{UserId: userID})
+	require.NoError(t, err)
+
+	_, err = manager.CreateSetupKey(context.Background(), account.Id, "invalid", types.SetupKeyReusable, time.Hour, nil,
+		types.SetupKeyUnlimitedUsage, userID, false, false, types.SetupKeyConstraints{HostnamePattern: "("})
+	assert.Error(t, err, "should not allow an invalid hostname pattern")
+
+	constraints := types.SetupKeyConstraints{
+		AllowedSourceRanges: []netip.Prefix{netip.MustParsePrefix("203.0.113.0/24")},
+		HostnamePattern:     "^ci-runner-[0-9]+$",
+		RequiredOS:          "linux",
+	}
+	key, err := manager.CreateSetupKey(context.Background(), account.Id, "ci", types.SetupKeyReusable, time.Hour, nil,
+		types.SetupKeyUnlimitedUsage, userID, false, false, constraints)
+	require.NoError(t, err)
+
+	stored, err := manager.GetSetupKey(context.Background(), account.Id, userID, key.Id)
+	require.NoError(t, err)
+	assert.Equal(t, constraints, stored.Constraints)
+
+	addPeer := func(hostname, goOS, connectionIP string) error {
+		peerKey, err := wgtypes.GeneratePrivateKey()
+		require.NoError(t, err)
+		_, _, _, err = manager.AddPeer(context.Background(), "", key.Key, "", &nbpeer.Peer{
+			Key:      peerKey.PublicKey().String(),
+			Meta:     nbpeer.PeerSystemMeta{Hostname: hostname, GoOS: goOS},
+			Location: nbpeer.Location{ConnectionIP: net.ParseIP(connectionIP)},
+		}, false)
+		return err
+	}
+
+	err = addPeer("laptop", "linux", "203.0.113.10")
+	require.Error(t, err, "hostname doesn't match the pattern")
+
+	ev := getEvent(t, account.Id, manager, activity.SetupKeyPeerRejected)
+	assert.Equal(t, key.Id, ev.InitiatorID)
+	assert.Equal(t, "ci", ev.Meta["setup_key_name"])
+	assert.Equal(t, "203.0.113.10", ev.Meta["connection_ip"])
+	assert.Contains(t, ev.Meta["reason"], "hostname laptop")
+
+	assert.Error(t, addPeer("ci-runner-1", "linux", "198.51.100.1"), "connection IP is not allowed")
+	assert.Error(t, addPeer("ci-runner-1", "windows", "203.0.113.10"), "operating system is not allowed")
+	assert.NoError(t, addPeer("ci-runner-1", "linux", "203.0.113.10"))
+
+	// an empty constraint set removes the constraints
+	_, err = manager.SaveSetupKey(context.Background(), account.Id, &types.SetupKey{Id: key.Id, AutoGroups: []string{}}, userID)
+	require.NoError(t, err)
+	assert.NoError(t, addPeer("laptop", "darwin", "198.51.100.1"))
+}
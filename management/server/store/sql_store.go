@@ -27,6 +27,11 @@ import (
 	"gorm.io/gorm/logger"
 
 	nbdns "github.com/netbirdio/netbird/dns"
+	"github.com/netbirdio/netbird/management/internals/modules/accessrequests"
+	"github.com/netbirdio/netbird/management/internals/modules/customroles"
+	"github.com/netbirdio/netbird/management/internals/modules/peerapproval"
+	"github.com/netbirdio/netbird/management/internals/modules/scim"
+	"github.com/netbirdio/netbird/management/internals/modules/workloadidentity"
 	"github.com/netbirdio/netbird/management/internals/modules/zones"
 	"github.com/netbirdio/netbird/management/internals/modules/zones/records"
 	resourceTypes "github.com/netbirdio/netbird/management/server/networks/resources/types"
@@ -67,6 +72,7 @@ type SqlStore struct {
 	storeEngine        types.Engine
 	pool               *pgxpool.Pool
 	fieldEncrypt       *crypt.FieldEncrypt
+	externalVerdicts   *posture.ExternalVerdicts
 	transactionTimeout time.Duration
 }
 
@@ -125,7 +131,8 @@ func NewSqlStore(ctx context.Context, db *gorm.DB, storeEngine types.Engine, met
 		&types.Account{}, &types.Policy{}, &types.PolicyRule{}, &route.Route{}, &nbdns.NameServerGroup{},
 		&installation{}, &types.ExtraSettings{}, &posture.Checks{}, &nbpeer.NetworkAddress{},
 		&networkTypes.Network{}, &routerTypes.NetworkRouter{}, &resourceTypes.NetworkResource{}, &types.AccountOnboarding{},
-		&zones.Zone{}, &records.Record{},
+		&zones.Zone{}, &records.Record{}, &customroles.Role{}, &accessrequests.AccessRequest{},
+		&scim.Token{}, &workloadidentity.Provider{}, &peerapproval.Request{}, &peerapproval.Rule{},
 	)
 	if err != nil {
 		return nil, fmt.Errorf("auto migratePreAuto: %w", err)
@@ -186,6 +193,20 @@ func (s *SqlStore) SaveAccount(ctx context.Context, account *types.Account) erro
 		}
 	}
 
+	// Posture checks are saved encrypted from copies, the account keeps the plain secrets
+	postureChecks := account.PostureChecks
+	defer func() {
+		account.PostureChecks = postureChecks
+	}()
+	account.PostureChecks = make([]*posture.Checks, 0, len(postureChecks))
+	for _, checks := range postureChecks {
+		checksCopy := checks.Copy()
+		if err := checksCopy.EncryptSensitiveData(s.fieldEncrypt); err != nil {
+			return fmt.Errorf("encrypt posture checks: %w", err)
+		}
+		account.PostureChecks = append(account.PostureChecks, checksCopy)
+	}
+
 	for _, group := range account.GroupsG {
 		group.StoreGroupPeers()
 	}
@@ -296,6 +317,36 @@ func (s *SqlStore) DeleteAccount(ctx context.Context, account *types.Account) er
 			return result.Error
 		}
 
+		result = tx.Delete(&workloadidentity.Provider{}, accountIDCondition, account.Id)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		result = tx.Delete(&scim.Token{}, accountIDCondition, account.Id)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		result = tx.Delete(&customroles.Role{}, accountIDCondition, account.Id)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		result = tx.Delete(&accessrequests.AccessRequest{}, accountIDCondition, account.Id)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		result = tx.Delete(&peerapproval.Request{}, accountIDCondition, account.Id)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		result = tx.Delete(&peerapproval.Rule{}, accountIDCondition, account.Id)
+		if result.Error != nil {
+			return result.Error
+		}
+
 		result = tx.Select(clause.Associations).Delete(account)
 		if result.Error != nil {
 			return result.Error
@@ -445,6 +496,18 @@ func (s *SqlStore) ApproveAccountPeers(ctx context.Context, accountID string) (i
 	return int(result.RowsAffected), nil
 }
 
+// ApprovePeers marks the given peers that currently require approval in the given account as approved.
+func (s *SqlStore) ApprovePeers(ctx context.Context, accountID string, peerIDs []string) (int, error) {
+	result := s.db.Model(&nbpeer.Peer{}).
+		Where("account_id = ? AND id IN ? AND peer_status_requires_approval = ?", accountID, peerIDs, true).
+		Update("peer_status_requires_approval", false)
+	if result.Error != nil {
+		return 0, status.Errorf(status.Internal, "failed to approve pending peers: %v", result.Error)
+	}
+
+	return int(result.RowsAffected), nil
+}
+
 // SaveUsers saves the given list of users to the database.
 func (s *SqlStore) SaveUsers(ctx context.Context, users []*types.User) error {
 	if len(users) == 0 {
@@ -954,6 +1017,11 @@ func (s *SqlStore) getAccountGorm(ctx context.Context, accountID string) (*types
 		account.NameServerGroups[ns.ID] = &ns
 	}
 	account.NameServerGroupsG = nil
+	for _, checks := range account.PostureChecks {
+		if err := s.preparePostureChecks(checks); err != nil {
+			return nil, fmt.Errorf("decrypt posture checks: %w", err)
+		}
+	}
 	account.InitOnce()
 	return &account, nil
 }
@@ -1260,6 +1328,7 @@ func (s *SqlStore) getAccount(ctx context.Context, accountID string) (*types.Acc
 			settings_regular_users_view_blocked, settings_groups_propagation_enabled,
 			settings_jwt_groups_enabled, settings_jwt_groups_claim_name, settings_jwt_allow_groups,
 			settings_routing_peer_dns_resolution_enabled, settings_dns_domain, settings_network_range,
+			settings_network_range_v6,
 			settings_lazy_connection_enabled,
 			-- Embedded ExtraSettings
 			settings_extra_peer_approval_enabled, settings_extra_user_approval_required,
@@ -1279,6 +1348,7 @@ func (s *SqlStore) getAccount(ctx context.Context, accountID string) (*types.Acc
 		sRoutingPeerDNSResolutionEnabled sql.NullBool
 		sDNSDomain                       sql.NullString
 		sNetworkRange                    sql.NullString
+		sNetworkRangeV6                  sql.NullString
 		sLazyConnectionEnabled           sql.NullBool
 		sExtraPeerApprovalEnabled        sql.NullBool
 		sExtraUserApprovalRequired       sql.NullBool
@@ -1299,7 +1369,7 @@ func (s *SqlStore) getAccount(ctx context.Context, accountID string) (*types.Acc
 		&sPeerInactivityExpirationEnabled, &sPeerInactivityExpiration,
 		&sRegularUsersViewBlocked, &sGroupsPropagationEnabled,
 		&sJWTGroupsEnabled, &sJWTGroupsClaimName, &sJWTAllowGroups,
-		&sRoutingPeerDNSResolutionEnabled, &sDNSDomain, &sNetworkRange,
+		&sRoutingPeerDNSResolutionEnabled, &sDNSDomain, &sNetworkRange, &sNetworkRangeV6,
 		&sLazyConnectionEnabled,
 		&sExtraPeerApprovalEnabled, &sExtraUserApprovalRequired,
 		&sExtraIntegratedValidator, &sExtraIntegratedValidatorGroups,
@@ -1369,6 +1439,9 @@ func (s *SqlStore) getAccount(ctx context.Context, accountID string) (*types.Acc
 	if sNetworkRange.Valid {
 		_ = json.Unmarshal([]byte(sNetworkRange.String), &account.Settings.NetworkRange)
 	}
+	if sNetworkRangeV6.Valid {
+		_ = json.Unmarshal([]byte(sNetworkRangeV6.String), &account.Settings.NetworkRangeV6)
+	}
 
 	if sExtraPeerApprovalEnabled.Valid {
 		account.Settings.Extra.PeerApprovalEnabled = sExtraPeerApprovalEnabled.Bool
@@ -1388,7 +1461,8 @@ func (s *SqlStore) getAccount(ctx context.Context, accountID string) (*types.Acc
 
 func (s *SqlStore) getSetupKeys(ctx context.Context, accountID string) ([]types.SetupKey, error) {
 	const query = `SELECT id, account_id, key, key_secret, name, type, created_at, expires_at, updated_at, 
-	revoked, used_times, last_used, auto_groups, usage_limit, ephemeral, allow_extra_dns_labels FROM setup_keys WHERE account_id = $1`
+	revoked, used_times, last_used, auto_groups, usage_limit, ephemeral, allow_extra_dns_labels, constraint_allowed_source_ranges,
+	constraint_hostname_pattern, constraint_required_os, constraint_not_before, constraint_not_after FROM setup_keys WHERE account_id = $1`
 	rows, err := s.pool.Query(ctx, query, accountID)
 	if err != nil {
 		return nil, err
@@ -1396,13 +1470,15 @@ func (s *SqlStore) getSetupKeys(ctx context.Context, accountID string) ([]types.
 
 	keys, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (types.SetupKey, error) {
 		var sk types.SetupKey
-		var autoGroups []byte
-		var skCreatedAt, expiresAt, updatedAt, lastUsed sql.NullTime
+		var autoGroups, sourceRanges []byte
+		var skCreatedAt, expiresAt, updatedAt, lastUsed, notBefore, notAfter sql.NullTime
 		var revoked, ephemeral, allowExtraDNSLabels sql.NullBool
 		var usedTimes, usageLimit sql.NullInt64
+		var hostnamePattern, requiredOS sql.NullString
 
 		err := row.Scan(&sk.Id, &sk.AccountID, &sk.Key, &sk.KeySecret, &sk.Name, &sk.Type, &skCreatedAt,
-			&expiresAt, &updatedAt, &revoked, &usedTimes, &lastUsed, &autoGroups, &usageLimit, &ephemeral, &allowExtraDNSLabels)
+			&expiresAt, &updatedAt, &revoked, &usedTimes, &lastUsed, &autoGroups, &usageLimit, &ephemeral, &allowExtraDNSLabels,
+			&sourceRanges, &hostnamePattern, &requiredOS, &notBefore, &notAfter)
 
 		if err == nil {
 			if expiresAt.Valid {
@@ -1440,6 +1516,17 @@ func (s *SqlStore) getSetupKeys(ctx context.Context, accountID string) ([]types.
 			} else {
 				sk.AutoGroups = []string{}
 			}
+			if sourceRanges != nil {
+				_ = json.Unmarshal(sourceRanges, &sk.Constraints.AllowedSourceRanges)
+			}
+			sk.Constraints.HostnamePattern = hostnamePattern.String
+			sk.Constraints.RequiredOS = requiredOS.String
+			if notBefore.Valid {
+				sk.Constraints.NotBefore = &notBefore.Time
+			}
+			if notAfter.Valid {
+				sk.Constraints.NotAfter = &notAfter.Time
+			}
 		}
 		return sk, err
 	})
@@ -1450,7 +1537,7 @@ func (s *SqlStore) getSetupKeys(ctx context.Context, accountID string) ([]types.
 }
 
 func (s *SqlStore) getPeers(ctx context.Context, accountID string) ([]nbpeer.Peer, error) {
-	const query = `SELECT id, account_id, key, ip, name, dns_label, user_id, ssh_key, ssh_enabled, login_expiration_enabled,
+	const query = `SELECT id, account_id, key, ip, ipv6, name, dns_label, user_id, ssh_key, ssh_enabled, login_expiration_enabled,
 	inactivity_expiration_enabled, last_login, created_at, ephemeral, extra_dns_labels, allow_extra_dns_labels, meta_hostname, 
 	meta_go_os, meta_kernel, meta_core, meta_platform, meta_os, meta_os_version, meta_wt_version, meta_ui_version, 
 	meta_kernel_version, meta_network_addresses, meta_system_serial_number, meta_system_product_name, meta_system_manufacturer,
@@ -1470,7 +1557,7 @@ func (s *SqlStore) getPeers(ctx context.Context, accountID string) ([]nbpeer.Pee
 			sshEnabled, loginExpirationEnabled, inactivityExpirationEnabled, ephemeral, allowExtraDNSLabels sql.NullBool
 			peerStatusLastSeen                                                                              sql.NullTime
 			peerStatusConnected, peerStatusLoginExpired, peerStatusRequiresApproval                         sql.NullBool
-			ip, extraDNS, netAddr, env, flags, files, connIP                                                []byte
+			ip, ipv6, extraDNS, netAddr, env, flags, files, connIP                                          []byte
 			metaHostname, metaGoOS, metaKernel, metaCore, metaPlatform                                      sql.NullString
 			metaOS, metaOSVersion, metaWtVersion, metaUIVersion, metaKernelVersion                          sql.NullString
 			metaSystemSerialNumber, metaSystemProductName, metaSystemManufacturer                           sql.NullString
@@ -1478,7 +1565,7 @@ func (s *SqlStore) getPeers(ctx context.Context, accountID string) ([]nbpeer.Pee
 			locationGeoNameID                                                                               sql.NullInt64
 		)
 
-		err := row.Scan(&p.ID, &p.AccountID, &p.Key, &ip, &p.Name, &p.DNSLabel, &p.UserID, &p.SSHKey, &sshEnabled,
+		err := row.Scan(&p.ID, &p.AccountID, &p.Key, &ip, &ipv6, &p.Name, &p.DNSLabel, &p.UserID, &p.SSHKey, &sshEnabled,
 			&loginExpirationEnabled, &inactivityExpirationEnabled, &lastLogin, &createdAt, &ephemeral, &extraDNS,
 			&allowExtraDNSLabels, &metaHostname, &metaGoOS, &metaKernel, &metaCore, &metaPlatform,
 			&metaOS, &metaOSVersion, &metaWtVersion, &metaUIVersion, &metaKernelVersion, &netAddr,
@@ -1571,6 +1658,9 @@ func (s *SqlStore) getPeers(ctx context.Context, accountID string) ([]nbpeer.Pee
 			if ip != nil {
 				_ = json.Unmarshal(ip, &p.IP)
 			}
+			if ipv6 != nil {
+				_ = json.Unmarshal(ipv6, &p.IPv6)
+			}
 			if extraDNS != nil {
 				_ = json.Unmarshal(extraDNS, &p.ExtraDNSLabels)
 			}
@@ -1815,6 +1905,9 @@ func (s *SqlStore) getPostureChecks(ctx context.Context, accountID string) ([]*p
 		if err == nil && checksDef != nil {
 			_ = json.Unmarshal(checksDef, &c.Checks)
 		}
+		if err == nil {
+			err = s.preparePostureChecks(&c)
+		}
 		return &c, err
 	})
 	if err != nil {
@@ -1942,7 +2035,7 @@ func (s *SqlStore) getPersonalAccessTokens(ctx context.Context, userIDs []string
 	if len(userIDs) == 0 {
 		return nil, nil
 	}
-	const query = `SELECT id, user_id, name, hashed_token, expiration_date, created_by, created_at, last_used FROM personal_access_tokens WHERE user_id = ANY($1)`
+	const query = `SELECT id, user_id, name, hashed_token, expiration_date, scopes, allowed_source_ranges, created_by, created_at, last_used FROM personal_access_tokens WHERE user_id = ANY($1)`
 	rows, err := s.pool.Query(ctx, query, userIDs)
 	if err != nil {
 		return nil, err
@@ -1950,8 +2043,15 @@ func (s *SqlStore) getPersonalAccessTokens(ctx context.Context, userIDs []string
 	pats, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (types.PersonalAccessToken, error) {
 		var pat types.PersonalAccessToken
 		var expirationDate, lastUsed, createdAt sql.NullTime
-		err := row.Scan(&pat.ID, &pat.UserID, &pat.Name, &pat.HashedToken, &expirationDate, &pat.CreatedBy, &createdAt, &lastUsed)
+		var scopes, allowedSourceRanges []byte
+		err := row.Scan(&pat.ID, &pat.UserID, &pat.Name, &pat.HashedToken, &expirationDate, &scopes, &allowedSourceRanges, &pat.CreatedBy, &createdAt, &lastUsed)
 		if err == nil {
+			if scopes != nil {
+				_ = json.Unmarshal(scopes, &pat.Scopes)
+			}
+			if allowedSourceRanges != nil {
+				_ = json.Unmarshal(allowedSourceRanges, &pat.AllowedSourceRanges)
+			}
 			if expirationDate.Valid {
 				pat.ExpirationDate = &expirationDate.Time
 			}
@@ -2208,6 +2308,39 @@ func (s *SqlStore) GetTakenIPs(ctx context.Context, lockStrength LockingStrength
 	return ips, nil
 }
 
+func (s *SqlStore) GetTakenIPv6s(ctx context.Context, lockStrength LockingStrength, accountID string) ([]net.IP, error) {
+	tx := s.db
+	if lockStrength != LockingStrengthNone {
+		tx = tx.Clauses(clause.Locking{Strength: string(lockStrength)})
+	}
+
+	var ipJSONStrings []sql.NullString
+
+	result := tx.Model(&nbpeer.Peer{}).
+		Where("account_id = ?", accountID).
+		Pluck("ipv6", &ipJSONStrings)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to get taken IPv6 addresses from store: %s", result.Error)
+		return nil, status.Errorf(status.Internal, "issue getting IPv6 addresses from store: %s", result.Error)
+	}
+
+	ips := make([]net.IP, 0, len(ipJSONStrings))
+	for _, ipJSON := range ipJSONStrings {
+		if !ipJSON.Valid || ipJSON.String == "" || ipJSON.String == "null" {
+			continue
+		}
+		var ip net.IP
+		if err := json.Unmarshal([]byte(ipJSON.String), &ip); err != nil {
+			return nil, status.Errorf(status.Internal, "issue parsing IPv6 JSON from store")
+		}
+		if len(ip) > 0 {
+			ips = append(ips, ip)
+		}
+	}
+
+	return ips, nil
+}
+
 func (s *SqlStore) GetPeerLabelsInAccount(ctx context.Context, lockStrength LockingStrength, accountID string, dnsLabel string) ([]string, error) {
 	tx := s.db
 	if lockStrength != LockingStrengthNone {
@@ -2331,6 +2464,10 @@ func (s *SqlStore) GetPostureCheckByChecksDefinition(accountID string, checks *p
 		return nil, err
 	}
 
+	if err = s.preparePostureChecks(&postureCheck); err != nil {
+		return nil, fmt.Errorf("decrypt posture checks: %w", err)
+	}
+
 	return &postureCheck, nil
 }
 
@@ -3032,9 +3169,10 @@ func (s *SqlStore) ExecuteInTransaction(ctx context.Context, operation func(stor
 
 func (s *SqlStore) withTx(tx *gorm.DB) Store {
 	return &SqlStore{
-		db:           tx,
-		storeEngine:  s.storeEngine,
-		fieldEncrypt: s.fieldEncrypt,
+		db:               tx,
+		storeEngine:      s.storeEngine,
+		fieldEncrypt:     s.fieldEncrypt,
+		externalVerdicts: s.externalVerdicts,
 	}
 }
 
@@ -3072,6 +3210,21 @@ func (s *SqlStore) SetFieldEncrypt(enc *crypt.FieldEncrypt) {
 	s.fieldEncrypt = enc
 }
 
+// SetExternalVerdicts sets the verdict cache bound to the external posture checks loaded from the store.
+func (s *SqlStore) SetExternalVerdicts(verdicts *posture.ExternalVerdicts) {
+	s.externalVerdicts = verdicts
+}
+
+// preparePostureChecks decrypts the sensitive data of posture checks loaded from the database
+// and binds their external check to the verdict cache.
+func (s *SqlStore) preparePostureChecks(checks *posture.Checks) error {
+	if err := checks.DecryptSensitiveData(s.fieldEncrypt); err != nil {
+		return err
+	}
+	checks.BindExternalVerdicts(s.externalVerdicts)
+	return nil
+}
+
 func (s *SqlStore) GetAccountDNSSettings(ctx context.Context, lockStrength LockingStrength, accountID string) (*types.DNSSettings, error) {
 	tx := s.db
 	if lockStrength != LockingStrengthNone {
@@ -3281,6 +3434,21 @@ func (s *SqlStore) GetAccountPolicies(ctx context.Context, lockStrength LockingS
 	return policies, nil
 }
 
+// GetAccountIDsWithScheduledPolicies returns the IDs of the accounts that have at least one enabled policy with a schedule.
+func (s *SqlStore) GetAccountIDsWithScheduledPolicies(ctx context.Context) ([]string, error) {
+	var accountIDs []string
+	result := s.db.Model(&types.Policy{}).
+		Where("enabled = ? AND schedule IS NOT NULL AND schedule <> ?", true, "null").
+		Distinct().
+		Pluck("account_id", &accountIDs)
+	if err := result.Error; err != nil {
+		log.WithContext(ctx).Errorf("failed to get accounts with scheduled policies from the store: %s", err)
+		return nil, status.Errorf(status.Internal, "failed to get accounts with scheduled policies from store")
+	}
+
+	return accountIDs, nil
+}
+
 // GetPolicyByID retrieves a policy by its ID and account ID.
 func (s *SqlStore) GetPolicyByID(ctx context.Context, lockStrength LockingStrength, accountID, policyID string) (*types.Policy, error) {
 	tx := s.db
@@ -3379,6 +3547,12 @@ func (s *SqlStore) GetAccountPostureChecks(ctx context.Context, lockStrength Loc
 		return nil, status.Errorf(status.Internal, "failed to get posture checks from store")
 	}
 
+	for _, postureCheck := range postureChecks {
+		if err := s.preparePostureChecks(postureCheck); err != nil {
+			return nil, fmt.Errorf("decrypt posture checks: %w", err)
+		}
+	}
+
 	return postureChecks, nil
 }
 
@@ -3400,6 +3574,10 @@ func (s *SqlStore) GetPostureChecksByID(ctx context.Context, lockStrength Lockin
 		return nil, status.Errorf(status.Internal, "failed to get posture check from store")
 	}
 
+	if err := s.preparePostureChecks(postureCheck); err != nil {
+		return nil, fmt.Errorf("decrypt posture checks: %w", err)
+	}
+
 	return postureCheck, nil
 }
 
@@ -3419,6 +3597,9 @@ func (s *SqlStore) GetPostureChecksByIDs(ctx context.Context, lockStrength Locki
 
 	postureChecksMap := make(map[string]*posture.Checks)
 	for _, postureCheck := range postureChecks {
+		if err := s.preparePostureChecks(postureCheck); err != nil {
+			return nil, fmt.Errorf("decrypt posture checks: %w", err)
+		}
 		postureChecksMap[postureCheck.ID] = postureCheck
 	}
 
@@ -3427,7 +3608,12 @@ func (s *SqlStore) GetPostureChecksByIDs(ctx context.Context, lockStrength Locki
 
 // SavePostureChecks saves a posture checks to the database.
 func (s *SqlStore) SavePostureChecks(ctx context.Context, postureCheck *posture.Checks) error {
-	result := s.db.Save(postureCheck)
+	postureCheckCopy := postureCheck.Copy()
+	if err := postureCheckCopy.EncryptSensitiveData(s.fieldEncrypt); err != nil {
+		return fmt.Errorf("encrypt posture checks: %w", err)
+	}
+
+	result := s.db.Save(postureCheckCopy)
 	if result.Error != nil {
 		log.WithContext(ctx).Errorf("failed to save posture checks to store: %s", result.Error)
 		return status.Errorf(status.Internal, "failed to save posture checks to store")
@@ -4363,3 +4549,418 @@ func (s *SqlStore) DeleteZoneDNSRecords(ctx context.Context, accountID, zoneID s
 
 	return nil
 }
+
+func (s *SqlStore) CreateCustomRole(ctx context.Context, role *customroles.Role) error {
+	result := s.db.Create(role)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to create custom role to store: %v", result.Error)
+		return status.Errorf(status.Internal, "failed to create custom role to store")
+	}
+
+	return nil
+}
+
+func (s *SqlStore) UpdateCustomRole(ctx context.Context, role *customroles.Role) error {
+	result := s.db.Select("*").Save(role)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to update custom role to store: %v", result.Error)
+		return status.Errorf(status.Internal, "failed to update custom role to store")
+	}
+
+	return nil
+}
+
+func (s *SqlStore) DeleteCustomRole(ctx context.Context, accountID, roleID string) error {
+	result := s.db.Delete(&customroles.Role{}, accountAndIDQueryCondition, accountID, roleID)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to delete custom role from store: %v", result.Error)
+		return status.Errorf(status.Internal, "failed to delete custom role from store")
+	}
+
+	if result.RowsAffected == 0 {
+		return status.NewCustomRoleNotFoundError(roleID)
+	}
+
+	return nil
+}
+
+func (s *SqlStore) GetCustomRoleByID(ctx context.Context, lockStrength LockingStrength, accountID, roleID string) (*customroles.Role, error) {
+	tx := s.db
+	if lockStrength != LockingStrengthNone {
+		tx = tx.Clauses(clause.Locking{Strength: string(lockStrength)})
+	}
+
+	var role *customroles.Role
+	result := tx.Take(&role, accountAndIDQueryCondition, accountID, roleID)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, status.NewCustomRoleNotFoundError(roleID)
+		}
+
+		log.WithContext(ctx).Errorf("failed to get custom role from store: %v", result.Error)
+		return nil, status.Errorf(status.Internal, "failed to get custom role from store")
+	}
+
+	return role, nil
+}
+
+func (s *SqlStore) GetCustomRoleByName(ctx context.Context, lockStrength LockingStrength, accountID, name string) (*customroles.Role, error) {
+	tx := s.db
+	if lockStrength != LockingStrengthNone {
+		tx = tx.Clauses(clause.Locking{Strength: string(lockStrength)})
+	}
+
+	var role *customroles.Role
+	result := tx.Where("account_id = ? AND name = ?", accountID, name).Take(&role)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, status.NewCustomRoleNotFoundError(name)
+		}
+
+		log.WithContext(ctx).Errorf("failed to get custom role by name from store: %v", result.Error)
+		return nil, status.Errorf(status.Internal, "failed to get custom role by name from store")
+	}
+
+	return role, nil
+}
+
+func (s *SqlStore) GetAccountCustomRoles(ctx context.Context, lockStrength LockingStrength, accountID string) ([]*customroles.Role, error) {
+	tx := s.db
+	if lockStrength != LockingStrengthNone {
+		tx = tx.Clauses(clause.Locking{Strength: string(lockStrength)})
+	}
+
+	var roles []*customroles.Role
+	result := tx.Find(&roles, accountIDCondition, accountID)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to get custom roles from the store: %s", result.Error)
+		return nil, status.Errorf(status.Internal, "failed to get custom roles from store")
+	}
+
+	return roles, nil
+}
+
+func (s *SqlStore) CreateAccessRequest(ctx context.Context, request *accessrequests.AccessRequest) error {
+	result := s.db.Create(request)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to create access request to store: %v", result.Error)
+		return status.Errorf(status.Internal, "failed to create access request to store")
+	}
+
+	return nil
+}
+
+func (s *SqlStore) SaveAccessRequest(ctx context.Context, request *accessrequests.AccessRequest) error {
+	result := s.db.Select("*").Save(request)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to save access request to store: %v", result.Error)
+		return status.Errorf(status.Internal, "failed to save access request to store")
+	}
+
+	return nil
+}
+
+func (s *SqlStore) GetAccessRequestByID(ctx context.Context, lockStrength LockingStrength, accountID, requestID string) (*accessrequests.AccessRequest, error) {
+	tx := s.db
+	if lockStrength != LockingStrengthNone {
+		tx = tx.Clauses(clause.Locking{Strength: string(lockStrength)})
+	}
+
+	var request *accessrequests.AccessRequest
+	result := tx.Take(&request, accountAndIDQueryCondition, accountID, requestID)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, status.NewAccessRequestNotFoundError(requestID)
+		}
+
+		log.WithContext(ctx).Errorf("failed to get access request from store: %v", result.Error)
+		return nil, status.Errorf(status.Internal, "failed to get access request from store")
+	}
+
+	return request, nil
+}
+
+func (s *SqlStore) GetAccountAccessRequests(ctx context.Context, lockStrength LockingStrength, accountID string) ([]*accessrequests.AccessRequest, error) {
+	tx := s.db
+	if lockStrength != LockingStrengthNone {
+		tx = tx.Clauses(clause.Locking{Strength: string(lockStrength)})
+	}
+
+	var requests []*accessrequests.AccessRequest
+	result := tx.Find(&requests, accountIDCondition, accountID)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to get access requests from the store: %s", result.Error)
+		return nil, status.Errorf(status.Internal, "failed to get access requests from store")
+	}
+
+	return requests, nil
+}
+
+// GetAccessRequestsByStatus returns the access requests of all accounts that are in the given status.
+func (s *SqlStore) GetAccessRequestsByStatus(ctx context.Context, lockStrength LockingStrength, requestStatus accessrequests.Status) ([]*accessrequests.AccessRequest, error) {
+	tx := s.db
+	if lockStrength != LockingStrengthNone {
+		tx = tx.Clauses(clause.Locking{Strength: string(lockStrength)})
+	}
+
+	var requests []*accessrequests.AccessRequest
+	result := tx.Find(&requests, "status = ?", requestStatus)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to get access requests by status from the store: %s", result.Error)
+		return nil, status.Errorf(status.Internal, "failed to get access requests from store")
+	}
+
+	return requests, nil
+}
+
+func (s *SqlStore) SaveSCIMToken(ctx context.Context, token *scim.Token) error {
+	// the account has a single token, creating a new one replaces the previous token
+	result := s.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(token)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to save SCIM token to store: %v", result.Error)
+		return status.Errorf(status.Internal, "failed to save SCIM token to store")
+	}
+
+	return nil
+}
+
+func (s *SqlStore) DeleteSCIMToken(ctx context.Context, accountID string) error {
+	result := s.db.Delete(&scim.Token{}, accountIDCondition, accountID)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to delete SCIM token from store: %v", result.Error)
+		return status.Errorf(status.Internal, "failed to delete SCIM token from store")
+	}
+
+	if result.RowsAffected == 0 {
+		return status.NewSCIMTokenNotFoundError()
+	}
+
+	return nil
+}
+
+func (s *SqlStore) GetSCIMToken(ctx context.Context, lockStrength LockingStrength, accountID string) (*scim.Token, error) {
+	tx := s.db
+	if lockStrength != LockingStrengthNone {
+		tx = tx.Clauses(clause.Locking{Strength: string(lockStrength)})
+	}
+
+	var token *scim.Token
+	result := tx.Take(&token, accountIDCondition, accountID)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, status.NewSCIMTokenNotFoundError()
+		}
+
+		log.WithContext(ctx).Errorf("failed to get SCIM token from store: %v", result.Error)
+		return nil, status.Errorf(status.Internal, "failed to get SCIM token from store")
+	}
+
+	return token, nil
+}
+
+func (s *SqlStore) GetSCIMTokenByHashedToken(ctx context.Context, lockStrength LockingStrength, hashedToken string) (*scim.Token, error) {
+	tx := s.db
+	if lockStrength != LockingStrengthNone {
+		tx = tx.Clauses(clause.Locking{Strength: string(lockStrength)})
+	}
+
+	var token *scim.Token
+	result := tx.Take(&token, "hashed_token = ?", hashedToken)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, status.NewSCIMTokenNotFoundError()
+		}
+
+		log.WithContext(ctx).Errorf("failed to get SCIM token by hash from store: %v", result.Error)
+		return nil, status.Errorf(status.Internal, "failed to get SCIM token from store")
+	}
+
+	return token, nil
+}
+
+func (s *SqlStore) MarkSCIMTokenUsed(ctx context.Context, accountID string) error {
+	result := s.db.Model(&scim.Token{}).Where(accountIDCondition, accountID).Update("last_used", time.Now().UTC())
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to mark SCIM token as used: %s", result.Error)
+		return status.Errorf(status.Internal, "failed to mark SCIM token as used")
+	}
+
+	if result.RowsAffected == 0 {
+		return status.NewSCIMTokenNotFoundError()
+	}
+
+	return nil
+}
+
+func (s *SqlStore) SaveWorkloadIdentityProvider(ctx context.Context, provider *workloadidentity.Provider) error {
+	result := s.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(provider)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to save workload identity provider to store: %v", result.Error)
+		return status.Errorf(status.Internal, "failed to save workload identity provider to store")
+	}
+
+	return nil
+}
+
+func (s *SqlStore) DeleteWorkloadIdentityProvider(ctx context.Context, accountID, providerID string) error {
+	result := s.db.Delete(&workloadidentity.Provider{}, accountAndIDQueryCondition, accountID, providerID)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to delete workload identity provider from store: %v", result.Error)
+		return status.Errorf(status.Internal, "failed to delete workload identity provider from store")
+	}
+
+	if result.RowsAffected == 0 {
+		return status.NewWorkloadIdentityProviderNotFoundError(providerID)
+	}
+
+	return nil
+}
+
+func (s *SqlStore) GetWorkloadIdentityProviderByID(ctx context.Context, lockStrength LockingStrength, accountID, providerID string) (*workloadidentity.Provider, error) {
+	tx := s.db
+	if lockStrength != LockingStrengthNone {
+		tx = tx.Clauses(clause.Locking{Strength: string(lockStrength)})
+	}
+
+	var provider *workloadidentity.Provider
+	result := tx.Take(&provider, accountAndIDQueryCondition, accountID, providerID)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, status.NewWorkloadIdentityProviderNotFoundError(providerID)
+		}
+
+		log.WithContext(ctx).Errorf("failed to get workload identity provider from store: %v", result.Error)
+		return nil, status.Errorf(status.Internal, "failed to get workload identity provider from store")
+	}
+
+	return provider, nil
+}
+
+func (s *SqlStore) GetAccountWorkloadIdentityProviders(ctx context.Context, lockStrength LockingStrength, accountID string) ([]*workloadidentity.Provider, error) {
+	tx := s.db
+	if lockStrength != LockingStrengthNone {
+		tx = tx.Clauses(clause.Locking{Strength: string(lockStrength)})
+	}
+
+	var providers []*workloadidentity.Provider
+	result := tx.Find(&providers, accountIDCondition, accountID)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to get workload identity providers from store: %v", result.Error)
+		return nil, status.Errorf(status.Internal, "failed to get workload identity providers from store")
+	}
+
+	return providers, nil
+}
+
+func (s *SqlStore) GetWorkloadIdentityProvidersByIssuer(ctx context.Context, lockStrength LockingStrength, issuer string) ([]*workloadidentity.Provider, error) {
+	tx := s.db
+	if lockStrength != LockingStrengthNone {
+		tx = tx.Clauses(clause.Locking{Strength: string(lockStrength)})
+	}
+
+	var providers []*workloadidentity.Provider
+	result := tx.Find(&providers, "issuer = ?", issuer)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to get workload identity providers by issuer from store: %v", result.Error)
+		return nil, status.Errorf(status.Internal, "failed to get workload identity providers from store")
+	}
+
+	return providers, nil
+}
+
+func (s *SqlStore) SavePeerApprovalRequest(ctx context.Context, request *peerapproval.Request) error {
+	result := s.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(request)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to save peer approval request to store: %v", result.Error)
+		return status.Errorf(status.Internal, "failed to save peer approval request to store")
+	}
+
+	return nil
+}
+
+// DeletePeerApprovalRequests deletes the approval requests of the given peers, peers without a request are ignored.
+func (s *SqlStore) DeletePeerApprovalRequests(ctx context.Context, accountID string, peerIDs []string) error {
+	result := s.db.Delete(&peerapproval.Request{}, "account_id = ? AND peer_id IN ?", accountID, peerIDs)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to delete peer approval requests from store: %v", result.Error)
+		return status.Errorf(status.Internal, "failed to delete peer approval requests from store")
+	}
+
+	return nil
+}
+
+func (s *SqlStore) GetAccountPeerApprovalRequests(ctx context.Context, lockStrength LockingStrength, accountID string) ([]*peerapproval.Request, error) {
+	tx := s.db
+	if lockStrength != LockingStrengthNone {
+		tx = tx.Clauses(clause.Locking{Strength: string(lockStrength)})
+	}
+
+	var requests []*peerapproval.Request
+	result := tx.Find(&requests, accountIDCondition, accountID)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to get peer approval requests from store: %v", result.Error)
+		return nil, status.Errorf(status.Internal, "failed to get peer approval requests from store")
+	}
+
+	return requests, nil
+}
+
+func (s *SqlStore) SavePeerApprovalRule(ctx context.Context, rule *peerapproval.Rule) error {
+	result := s.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(rule)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to save peer approval rule to store: %v", result.Error)
+		return status.Errorf(status.Internal, "failed to save peer approval rule to store")
+	}
+
+	return nil
+}
+
+func (s *SqlStore) DeletePeerApprovalRule(ctx context.Context, accountID, ruleID string) error {
+	result := s.db.Delete(&peerapproval.Rule{}, accountAndIDQueryCondition, accountID, ruleID)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to delete peer approval rule from store: %v", result.Error)
+		return status.Errorf(status.Internal, "failed to delete peer approval rule from store")
+	}
+
+	if result.RowsAffected == 0 {
+		return status.NewPeerApprovalRuleNotFoundError(ruleID)
+	}
+
+	return nil
+}
+
+func (s *SqlStore) GetPeerApprovalRuleByID(ctx context.Context, lockStrength LockingStrength, accountID, ruleID string) (*peerapproval.Rule, error) {
+	tx := s.db
+	if lockStrength != LockingStrengthNone {
+		tx = tx.Clauses(clause.Locking{Strength: string(lockStrength)})
+	}
+
+	var rule *peerapproval.Rule
+	result := tx.Take(&rule, accountAndIDQueryCondition, accountID, ruleID)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, status.NewPeerApprovalRuleNotFoundError(ruleID)
+		}
+
+		log.WithContext(ctx).Errorf("failed to get peer approval rule from store: %v", result.Error)
+		return nil, status.Errorf(status.Internal, "failed to get peer approval rule from store")
+	}
+
+	return rule, nil
+}
+
+func (s *SqlStore) GetAccountPeerApprovalRules(ctx context.Context, lockStrength LockingStrength, accountID string) ([]*peerapproval.Rule, error) {
+	tx := s.db
+	if lockStrength != LockingStrengthNone {
+		tx = tx.Clauses(clause.Locking{Strength: string(lockStrength)})
+	}
+
+	var rules []*peerapproval.Rule
+	result := tx.Order("name").Find(&rules, accountIDCondition, accountID)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to get peer approval rules from store: %v", result.Error)
+		return nil, status.Errorf(status.Internal, "failed to get peer approval rules from store")
+	}
+
+	return rules, nil
+}
@@ -22,6 +22,11 @@ import (
 	"github.com/stretchr/testify/require"
 
 	nbdns "github.com/netbirdio/netbird/dns"
+	"github.com/netbirdio/netbird/management/internals/modules/accessrequests"
+	"github.com/netbirdio/netbird/management/internals/modules/customroles"
+	"github.com/netbirdio/netbird/management/internals/modules/peerapproval"
+	"github.com/netbirdio/netbird/management/internals/modules/scim"
+	"github.com/netbirdio/netbird/management/internals/modules/workloadidentity"
 	"github.com/netbirdio/netbird/management/internals/modules/zones"
 	"github.com/netbirdio/netbird/management/internals/modules/zones/records"
 	resourceTypes "github.com/netbirdio/netbird/management/server/networks/resources/types"
@@ -361,12 +366,54 @@ func TestSqlite_DeleteAccount(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, o.AccountID, account.Id)
 
+	provider := workloadidentity.NewProvider(account.Id, "ci", "https://token.actions.githubusercontent.com", []string{"netbird"}, "", nil, true, true)
+	err = store.SaveWorkloadIdentityProvider(context.Background(), provider)
+	require.NoError(t, err)
+
+	err = store.SaveSCIMToken(context.Background(), &scim.Token{AccountID: account.Id, HashedToken: "hashed", CreatedBy: testUserID, CreatedAt: time.Now().UTC()})
+	require.NoError(t, err)
+
+	err = store.CreateCustomRole(context.Background(), customroles.NewRole(account.Id, "auditor", "", nil))
+	require.NoError(t, err)
+
+	err = store.CreateAccessRequest(context.Background(), accessrequests.NewAccessRequest(account.Id, testUserID, accessrequests.TargetTypeGroup, "group", "incident", time.Hour))
+	require.NoError(t, err)
+
+	err = store.SavePeerApprovalRequest(context.Background(), &peerapproval.Request{PeerID: "peer", AccountID: account.Id, CreatedAt: time.Now().UTC()})
+	require.NoError(t, err)
+
+	err = store.SavePeerApprovalRule(context.Background(), peerapproval.NewRule(account.Id, "linux", true, nil, nil, []string{"linux"}, nil))
+	require.NoError(t, err)
+
 	err = store.DeleteAccount(context.Background(), account)
 	require.NoError(t, err)
 
 	_, err = store.GetAccountOnboarding(context.Background(), account.Id)
 	require.Error(t, err, "expecting error after removing DeleteAccount when getting onboarding")
 
+	providers, err := store.GetAccountWorkloadIdentityProviders(context.Background(), LockingStrengthNone, account.Id)
+	require.NoError(t, err, "expecting no error after removing DeleteAccount when searching for workload identity providers")
+	require.Len(t, providers, 0, "expecting no workload identity providers to be found after DeleteAccount")
+
+	_, err = store.GetSCIMToken(context.Background(), LockingStrengthNone, account.Id)
+	require.Error(t, err, "expecting error after removing DeleteAccount when getting SCIM token")
+
+	customRoles, err := store.GetAccountCustomRoles(context.Background(), LockingStrengthNone, account.Id)
+	require.NoError(t, err, "expecting no error after removing DeleteAccount when searching for custom roles")
+	require.Len(t, customRoles, 0, "expecting no custom roles to be found after DeleteAccount")
+
+	accessRequests, err := store.GetAccountAccessRequests(context.Background(), LockingStrengthNone, account.Id)
+	require.NoError(t, err, "expecting no error after removing DeleteAccount when searching for access requests")
+	require.Len(t, accessRequests, 0, "expecting no access requests to be found after DeleteAccount")
+
+	approvalRequests, err := store.GetAccountPeerApprovalRequests(context.Background(), LockingStrengthNone, account.Id)
+	require.NoError(t, err, "expecting no error after removing DeleteAccount when searching for peer approval requests")
+	require.Len(t, approvalRequests, 0, "expecting no peer approval requests to be found after DeleteAccount")
+
+	approvalRules, err := store.GetAccountPeerApprovalRules(context.Background(), LockingStrengthNone, account.Id)
+	require.NoError(t, err, "expecting no error after removing DeleteAccount when searching for peer approval rules")
+	require.Len(t, approvalRules, 0, "expecting no peer approval rules to be found after DeleteAccount")
+
 	if len(store.GetAllAccounts(context.Background())) != 0 {
 		t.Errorf("expecting 0 Accounts to be stored after DeleteAccount()")
 	}
@@ -1098,6 +1145,58 @@ func Test_AddPeerWithSameIP(t *testing.T) {
 	})
 }
 
+func Test_AddPeerWithSameIPv6(t *testing.T) {
+	runTestForAllEngines(t, "../testdata/extended-store.sql", func(t *testing.T, store Store) {
+		existingAccountID := "bf1c8084-ba50-4ce7-9439-34653001fc3b"
+
+		_, err := store.GetAccount(context.Background(), existingAccountID)
+		require.NoError(t, err)
+
+		peer1 := &nbpeer.Peer{
+			ID:        "peer1",
+			AccountID: existingAccountID,
+			Key:       "key1",
+			DNSLabel:  "peer1",
+			IP:        net.IP{1, 1, 1, 1},
+			IPv6:      net.ParseIP("fd00:1234::1"),
+		}
+		err = store.AddPeerToAccount(context.Background(), peer1)
+		require.NoError(t, err)
+
+		peer2 := &nbpeer.Peer{
+			ID:        "peer1second",
+			AccountID: existingAccountID,
+			Key:       "key2",
+			DNSLabel:  "peer2",
+			IP:        net.IP{1, 1, 1, 2},
+			IPv6:      net.ParseIP("fd00:1234::1"),
+		}
+		err = store.AddPeerToAccount(context.Background(), peer2)
+		require.Error(t, err)
+
+		// peers without an IPv6 address must not collide with each other
+		peer3 := &nbpeer.Peer{
+			ID:        "peer3",
+			AccountID: existingAccountID,
+			Key:       "key3",
+			DNSLabel:  "peer3",
+			IP:        net.IP{1, 1, 1, 3},
+		}
+		err = store.AddPeerToAccount(context.Background(), peer3)
+		require.NoError(t, err)
+
+		peer4 := &nbpeer.Peer{
+			ID:        "peer4",
+			AccountID: existingAccountID,
+			Key:       "key4",
+			DNSLabel:  "peer4",
+			IP:        net.IP{1, 1, 1, 4},
+		}
+		err = store.AddPeerToAccount(context.Background(), peer4)
+		require.NoError(t, err)
+	})
+}
+
 func TestSqlite_GetAccountNetwork(t *testing.T) {
 	t.Setenv("NETBIRD_STORE_ENGINE", string(types.SqliteStoreEngine))
 	store, cleanup, err := NewTestStoreFromSQL(context.Background(), "../testdata/extended-store.sql", t.TempDir())
@@ -3256,6 +3355,65 @@ func TestSqlStore_SaveUserWithEncryption(t *testing.T) {
 	})
 }
 
+func TestSqlStore_SavePostureChecksWithEncryption(t *testing.T) {
+	store, cleanup, err := NewTestStoreFromSQL(context.Background(), "../testdata/extended-store.sql", t.TempDir())
+	t.Cleanup(cleanup)
+	require.NoError(t, err)
+
+	key, err := crypt.GenerateKey()
+	require.NoError(t, err)
+	fieldEncrypt, err := crypt.NewFieldEncrypt(key)
+	require.NoError(t, err)
+	store.SetFieldEncrypt(fieldEncrypt)
+
+	accountID := "bf1c8084-ba50-4ce7-9439-34653001fc3b"
+
+	postureChecks := &posture.Checks{
+		ID:        "external-checks",
+		AccountID: accountID,
+		Name:      "MDM",
+		Checks: posture.ChecksDefinition{
+			ExternalCheck: &posture.ExternalCheck{
+				Protocol: posture.ExternalCheckProtocolHTTP,
+				Endpoint: "https://mdm.example.com/verdict",
+				Secret:   "secret",
+			},
+		},
+	}
+	err = store.SavePostureChecks(context.Background(), postureChecks)
+	require.NoError(t, err)
+	require.Equal(t, "secret", postureChecks.Checks.ExternalCheck.Secret, "the saved posture checks keep the plain secret")
+
+	var raw posture.Checks
+	err = store.(*SqlStore).db.Where("id = ?", postureChecks.ID).First(&raw).Error
+	require.NoError(t, err)
+	require.NotEqual(t, "secret", raw.Checks.ExternalCheck.Secret, "secret should be encrypted in database")
+
+	decryptedSecret, err := fieldEncrypt.Decrypt(raw.Checks.ExternalCheck.Secret)
+	require.NoError(t, err)
+	require.Equal(t, "secret", decryptedSecret)
+
+	stored, err := store.GetPostureChecksByID(context.Background(), LockingStrengthNone, accountID, postureChecks.ID)
+	require.NoError(t, err)
+	require.Equal(t, "secret", stored.Checks.ExternalCheck.Secret)
+
+	accountChecks, err := store.GetAccountPostureChecks(context.Background(), LockingStrengthNone, accountID)
+	require.NoError(t, err)
+	for _, checks := range accountChecks {
+		if checks.ID == postureChecks.ID {
+			require.Equal(t, "secret", checks.Checks.ExternalCheck.Secret)
+		}
+	}
+
+	account, err := store.GetAccount(context.Background(), accountID)
+	require.NoError(t, err)
+	for _, checks := range account.PostureChecks {
+		if checks.ID == postureChecks.ID {
+			require.Equal(t, "secret", checks.Checks.ExternalCheck.Secret)
+		}
+	}
+}
+
 func TestSqlStore_DeleteUser(t *testing.T) {
 	store, cleanup, err := NewTestStoreFromSQL(context.Background(), "../testdata/extended-store.sql", t.TempDir())
 	t.Cleanup(cleanup)
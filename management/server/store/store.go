@@ -23,6 +23,11 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/netbirdio/netbird/dns"
+	"github.com/netbirdio/netbird/management/internals/modules/accessrequests"
+	"github.com/netbirdio/netbird/management/internals/modules/customroles"
+	"github.com/netbirdio/netbird/management/internals/modules/peerapproval"
+	"github.com/netbirdio/netbird/management/internals/modules/scim"
+	"github.com/netbirdio/netbird/management/internals/modules/workloadidentity"
 	"github.com/netbirdio/netbird/management/internals/modules/zones"
 	"github.com/netbirdio/netbird/management/internals/modules/zones/records"
 	"github.com/netbirdio/netbird/management/server/telemetry"
@@ -112,6 +117,7 @@ type Store interface {
 	DeleteGroups(ctx context.Context, accountID string, groupIDs []string) error
 
 	GetAccountPolicies(ctx context.Context, lockStrength LockingStrength, accountID string) ([]*types.Policy, error)
+	GetAccountIDsWithScheduledPolicies(ctx context.Context) ([]string, error)
 	GetPolicyByID(ctx context.Context, lockStrength LockingStrength, accountID, policyID string) (*types.Policy, error)
 	CreatePolicy(ctx context.Context, policy *types.Policy) error
 	SavePolicy(ctx context.Context, policy *types.Policy) error
@@ -147,6 +153,7 @@ type Store interface {
 	SavePeerStatus(ctx context.Context, accountID, peerID string, status nbpeer.PeerStatus) error
 	SavePeerLocation(ctx context.Context, accountID string, peer *nbpeer.Peer) error
 	ApproveAccountPeers(ctx context.Context, accountID string) (int, error)
+	ApprovePeers(ctx context.Context, accountID string, peerIDs []string) (int, error)
 	DeletePeer(ctx context.Context, accountID string, peerID string) error
 
 	GetSetupKeyBySecret(ctx context.Context, lockStrength LockingStrength, key string) (*types.SetupKey, error)
@@ -167,6 +174,7 @@ type Store interface {
 	DeleteNameServerGroup(ctx context.Context, accountID, nameServerGroupID string) error
 
 	GetTakenIPs(ctx context.Context, lockStrength LockingStrength, accountId string) ([]net.IP, error)
+	GetTakenIPv6s(ctx context.Context, lockStrength LockingStrength, accountID string) ([]net.IP, error)
 	IncrementNetworkSerial(ctx context.Context, accountId string) error
 	GetAccountNetwork(ctx context.Context, lockStrength LockingStrength, accountId string) (*types.Network, error)
 
@@ -210,6 +218,8 @@ type Store interface {
 
 	// SetFieldEncrypt sets the field encryptor for encrypting sensitive user data.
 	SetFieldEncrypt(enc *crypt.FieldEncrypt)
+	// SetExternalVerdicts sets the verdict cache bound to the external posture checks loaded from the store.
+	SetExternalVerdicts(verdicts *posture.ExternalVerdicts)
 	GetUserIDByPeerKey(ctx context.Context, lockStrength LockingStrength, peerKey string) (string, error)
 
 	CreateZone(ctx context.Context, zone *zones.Zone) error
@@ -226,6 +236,39 @@ type Store interface {
 	GetZoneDNSRecords(ctx context.Context, lockStrength LockingStrength, accountID, zoneID string) ([]*records.Record, error)
 	GetZoneDNSRecordsByName(ctx context.Context, lockStrength LockingStrength, accountID, zoneID, name string) ([]*records.Record, error)
 	DeleteZoneDNSRecords(ctx context.Context, accountID, zoneID string) error
+
+	CreateCustomRole(ctx context.Context, role *customroles.Role) error
+	UpdateCustomRole(ctx context.Context, role *customroles.Role) error
+	DeleteCustomRole(ctx context.Context, accountID, roleID string) error
+	GetCustomRoleByID(ctx context.Context, lockStrength LockingStrength, accountID, roleID string) (*customroles.Role, error)
+	GetCustomRoleByName(ctx context.Context, lockStrength LockingStrength, accountID, name string) (*customroles.Role, error)
+	GetAccountCustomRoles(ctx context.Context, lockStrength LockingStrength, accountID string) ([]*customroles.Role, error)
+
+	CreateAccessRequest(ctx context.Context, request *accessrequests.AccessRequest) error
+	SaveAccessRequest(ctx context.Context, request *accessrequests.AccessRequest) error
+	GetAccessRequestByID(ctx context.Context, lockStrength LockingStrength, accountID, requestID string) (*accessrequests.AccessRequest, error)
+	GetAccountAccessRequests(ctx context.Context, lockStrength LockingStrength, accountID string) ([]*accessrequests.AccessRequest, error)
+	GetAccessRequestsByStatus(ctx context.Context, lockStrength LockingStrength, status accessrequests.Status) ([]*accessrequests.AccessRequest, error)
+
+	SaveSCIMToken(ctx context.Context, token *scim.Token) error
+	DeleteSCIMToken(ctx context.Context, accountID string) error
+	GetSCIMToken(ctx context.Context, lockStrength LockingStrength, accountID string) (*scim.Token, error)
+	GetSCIMTokenByHashedToken(ctx context.Context, lockStrength LockingStrength, hashedToken string) (*scim.Token, error)
+	MarkSCIMTokenUsed(ctx context.Context, accountID string) error
+
+	SaveWorkloadIdentityProvider(ctx context.Context, provider *workloadidentity.Provider) error
+	DeleteWorkloadIdentityProvider(ctx context.Context, accountID, providerID string) error
+	GetWorkloadIdentityProviderByID(ctx context.Context, lockStrength LockingStrength, accountID, providerID string) (*workloadidentity.Provider, error)
+	GetAccountWorkloadIdentityProviders(ctx context.Context, lockStrength LockingStrength, accountID string) ([]*workloadidentity.Provider, error)
+	GetWorkloadIdentityProvidersByIssuer(ctx context.Context, lockStrength LockingStrength, issuer string) ([]*workloadidentity.Provider, error)
+
+	SavePeerApprovalRequest(ctx context.Context, request *peerapproval.Request) error
+	DeletePeerApprovalRequests(ctx context.Context, accountID string, peerIDs []string) error
+	GetAccountPeerApprovalRequests(ctx context.Context, lockStrength LockingStrength, accountID string) ([]*peerapproval.Request, error)
+	SavePeerApprovalRule(ctx context.Context, rule *peerapproval.Rule) error
+	DeletePeerApprovalRule(ctx context.Context, accountID, ruleID string) error
+	GetPeerApprovalRuleByID(ctx context.Context, lockStrength LockingStrength, accountID, ruleID string) (*peerapproval.Rule, error)
+	GetAccountPeerApprovalRules(ctx context.Context, lockStrength LockingStrength, accountID string) ([]*peerapproval.Rule, error)
 }
 
 const (
@@ -391,6 +434,12 @@ func getMigrationsPostAuto(ctx context.Context) []migrationFunc {
 		func(db *gorm.DB) error {
 			return migration.CreateIndexIfNotExists[nbpeer.Peer](ctx, db, "idx_account_ip", "account_id", "ip")
 		},
+		func(db *gorm.DB) error {
+			return migration.MigrateEmptyJSONStringToNull[nbpeer.Peer](ctx, db, "ipv6")
+		},
+		func(db *gorm.DB) error {
+			return migration.CreateIndexIfNotExists[nbpeer.Peer](ctx, db, "idx_account_ipv6", "account_id", "ipv6")
+		},
 		func(db *gorm.DB) error {
 			return migration.CreateIndexIfNotExists[nbpeer.Peer](ctx, db, "idx_account_dnslabel", "account_id", "dns_label")
 		},
@@ -526,6 +526,7 @@ func (a *Account) GetPeersCustomZone(ctx context.Context, dnsDomain string) nbdn
 			TTL:   defaultTTL,
 			RData: peer.IP.String(),
 		})
+		customZone.Records = appendPeerAAAARecord(customZone.Records, peer, sb.String())
 		sb.Reset()
 
 		for _, extraLabel := range peer.ExtraDNSLabels {
@@ -540,6 +541,7 @@ func (a *Account) GetPeersCustomZone(ctx context.Context, dnsDomain string) nbdn
 				TTL:   defaultTTL,
 				RData: peer.IP.String(),
 			})
+			customZone.Records = appendPeerAAAARecord(customZone.Records, peer, sb.String())
 			sb.Reset()
 		}
 
@@ -554,6 +556,20 @@ func (a *Account) GetPeersCustomZone(ctx context.Context, dnsDomain string) nbdn
 	return customZone
 }
 
+// appendPeerAAAARecord adds an AAAA record for the peer's IPv6 overlay address if the peer has it configured
+func appendPeerAAAARecord(records []nbdns.SimpleRecord, peer *nbpeer.Peer, name string) []nbdns.SimpleRecord {
+	if !peer.SupportsIPv6() {
+		return records
+	}
+	return append(records, nbdns.SimpleRecord{
+		Name:  name,
+		Type:  int(dns.TypeAAAA),
+		Class: nbdns.DefaultClass,
+		TTL:   defaultTTL,
+		RData: peer.IPv6.String(),
+	})
+}
+
 // GetExpiredPeers returns peers that have been expired
 func (a *Account) GetExpiredPeers() []*nbpeer.Peer {
 	var peers []*nbpeer.Peer
@@ -1030,7 +1046,7 @@ func (a *Account) GetPeerConnectionResources(ctx context.Context, peer *nbpeer.P
 	sshEnabled := false
 
 	for _, policy := range a.Policies {
-		if !policy.Enabled {
+		if !policy.IsActive() {
 			continue
 		}
 
@@ -1251,7 +1267,8 @@ func (a *Account) getPeerFromResource(resource Resource, peerID string) ([]*nbpe
 	return []*nbpeer.Peer{peer}, false
 }
 
-// validatePostureChecksOnPeer validates the posture checks on a peer
+// validatePostureChecksOnPeer validates the posture checks on a peer.
+// The checks are evaluated like in the posture check report, stopping at the first failed check.
 func (a *Account) validatePostureChecksOnPeer(ctx context.Context, sourcePostureChecksID []string, peerID string) bool {
 	peer, ok := a.Peers[peerID]
 	if !ok && peer == nil {
@@ -1265,11 +1282,9 @@ func (a *Account) validatePostureChecksOnPeer(ctx context.Context, sourcePosture
 		}
 
 		for _, check := range postureChecks.GetChecks() {
-			isValid, err := check.Check(ctx, *peer)
-			if err != nil {
-				log.WithContext(ctx).Debugf("an error occurred check %s: on peer: %s :%s", check.Name(), peer.ID, err.Error())
-			}
-			if !isValid {
+			result := posture.EvaluateCheck(ctx, postureChecks, check, *peer)
+			if !result.Passed {
+				log.WithContext(ctx).Debugf("peer %s failed check %s: %s", peer.ID, check.Name(), result.Reason)
 				return false
 			}
 		}
@@ -1314,7 +1329,7 @@ func (a *Account) GetPeerRoutesFirewallRules(ctx context.Context, peerID string,
 func (a *Account) getRouteFirewallRules(ctx context.Context, peerID string, policies []*Policy, route *route.Route, validatedPeersMap map[string]struct{}, distributionPeers map[string]struct{}) []*RouteFirewallRule {
 	var fwRules []*RouteFirewallRule
 	for _, policy := range policies {
-		if !policy.Enabled {
+		if !policy.IsActive() {
 			continue
 		}
 
@@ -1595,7 +1610,7 @@ func (a *Account) GetPoliciesForNetworkResource(resourceId string) []*Policy {
 	networkResourceGroups := a.getNetworkResourceGroups(resourceId)
 
 	for _, policy := range a.Policies {
-		if !policy.Enabled {
+		if !policy.IsActive() {
 			continue
 		}
 
@@ -1867,19 +1882,28 @@ func peerSupportedFirewallFeatures(peerVer string) supportedFeatures {
 }
 
 // filterZoneRecordsForPeers filters DNS records to only include peers to connect.
+// AAAA records are only kept when the peer itself has IPv6 configured, otherwise they would not be reachable.
 func filterZoneRecordsForPeers(peer *nbpeer.Peer, customZone nbdns.CustomZone, peersToConnect, expiredPeers []*nbpeer.Peer) []nbdns.SimpleRecord {
 	filteredRecords := make([]nbdns.SimpleRecord, 0, len(customZone.Records))
 	peerIPs := make(map[string]struct{})
+	withIPv6 := peer.SupportsIPv6()
+
+	addPeerIPs := func(p *nbpeer.Peer) {
+		peerIPs[p.IP.String()] = struct{}{}
+		if withIPv6 && p.SupportsIPv6() {
+			peerIPs[p.IPv6.String()] = struct{}{}
+		}
+	}
 
 	// Add peer's own IP to include its own DNS records
-	peerIPs[peer.IP.String()] = struct{}{}
+	addPeerIPs(peer)
 
 	for _, peerToConnect := range peersToConnect {
-		peerIPs[peerToConnect.IP.String()] = struct{}{}
+		addPeerIPs(peerToConnect)
 	}
 
 	for _, expiredPeer := range expiredPeers {
-		peerIPs[expiredPeer.IP.String()] = struct{}{}
+		addPeerIPs(expiredPeer)
 	}
 
 	for _, record := range customZone.Records {
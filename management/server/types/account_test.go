@@ -1417,6 +1417,50 @@ func Test_FilterZoneRecordsForPeers(t *testing.T) {
 				{Name: "router.netbird.cloud", Type: int(dns.TypeA), Class: nbdns.DefaultClass, TTL: 300, RData: "10.0.0.100"},
 			},
 		},
+		{
+			name: "AAAA records are included only for IPv6 capable peers",
+			customZone: nbdns.CustomZone{
+				Domain: "netbird.cloud.",
+				Records: []nbdns.SimpleRecord{
+					{Name: "peer1.netbird.cloud", Type: int(dns.TypeA), Class: nbdns.DefaultClass, TTL: 300, RData: "10.0.0.1"},
+					{Name: "peer1.netbird.cloud", Type: int(dns.TypeAAAA), Class: nbdns.DefaultClass, TTL: 300, RData: "fd00::1"},
+					{Name: "peer2.netbird.cloud", Type: int(dns.TypeA), Class: nbdns.DefaultClass, TTL: 300, RData: "10.0.0.2"},
+					{Name: "router.netbird.cloud", Type: int(dns.TypeA), Class: nbdns.DefaultClass, TTL: 300, RData: "10.0.0.100"},
+					{Name: "router.netbird.cloud", Type: int(dns.TypeAAAA), Class: nbdns.DefaultClass, TTL: 300, RData: "fd00::100"},
+				},
+			},
+			peersToConnect: []*nbpeer.Peer{
+				{ID: "peer1", IP: net.ParseIP("10.0.0.1"), IPv6: net.ParseIP("fd00::1"), Meta: nbpeer.PeerSystemMeta{Flags: nbpeer.Flags{IPv6Supported: true}}},
+				{ID: "peer2", IP: net.ParseIP("10.0.0.2"), IPv6: net.ParseIP("fd00::2")},
+			},
+			expiredPeers: []*nbpeer.Peer{},
+			peer:         &nbpeer.Peer{ID: "router", IP: net.ParseIP("10.0.0.100"), IPv6: net.ParseIP("fd00::100"), Meta: nbpeer.PeerSystemMeta{Flags: nbpeer.Flags{IPv6Supported: true}}},
+			expectedRecords: []nbdns.SimpleRecord{
+				{Name: "peer1.netbird.cloud", Type: int(dns.TypeA), Class: nbdns.DefaultClass, TTL: 300, RData: "10.0.0.1"},
+				{Name: "peer1.netbird.cloud", Type: int(dns.TypeAAAA), Class: nbdns.DefaultClass, TTL: 300, RData: "fd00::1"},
+				{Name: "peer2.netbird.cloud", Type: int(dns.TypeA), Class: nbdns.DefaultClass, TTL: 300, RData: "10.0.0.2"},
+				{Name: "router.netbird.cloud", Type: int(dns.TypeA), Class: nbdns.DefaultClass, TTL: 300, RData: "10.0.0.100"},
+				{Name: "router.netbird.cloud", Type: int(dns.TypeAAAA), Class: nbdns.DefaultClass, TTL: 300, RData: "fd00::100"},
+			},
+		},
+		{
+			name: "AAAA records are dropped for peers without IPv6",
+			customZone: nbdns.CustomZone{
+				Domain: "netbird.cloud.",
+				Records: []nbdns.SimpleRecord{
+					{Name: "peer1.netbird.cloud", Type: int(dns.TypeA), Class: nbdns.DefaultClass, TTL: 300, RData: "10.0.0.1"},
+					{Name: "peer1.netbird.cloud", Type: int(dns.TypeAAAA), Class: nbdns.DefaultClass, TTL: 300, RData: "fd00::1"},
+				},
+			},
+			peersToConnect: []*nbpeer.Peer{
+				{ID: "peer1", IP: net.ParseIP("10.0.0.1"), IPv6: net.ParseIP("fd00::1"), Meta: nbpeer.PeerSystemMeta{Flags: nbpeer.Flags{IPv6Supported: true}}},
+			},
+			expiredPeers: []*nbpeer.Peer{},
+			peer:         &nbpeer.Peer{ID: "router", IP: net.ParseIP("10.0.0.100")},
+			expectedRecords: []nbdns.SimpleRecord{
+				{Name: "peer1.netbird.cloud", Type: int(dns.TypeA), Class: nbdns.DefaultClass, TTL: 300, RData: "10.0.0.1"},
+			},
+		},
 	}
 
 	for _, tt := range tests {
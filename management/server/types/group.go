@@ -9,6 +9,7 @@ const (
 	GroupIssuedAPI         = "api"
 	GroupIssuedJWT         = "jwt"
 	GroupIssuedIntegration = "integration"
+	GroupIssuedSCIM        = "scim"
 )
 
 // Group of the peers for ACL
@@ -4,6 +4,7 @@ import (
 	"encoding/binary"
 	"math/rand"
 	"net"
+	"net/netip"
 	"sync"
 	"time"
 
@@ -27,8 +28,15 @@ const (
 
 	// AllowedIPsFormat generates Wireguard AllowedIPs format (e.g. 100.64.30.1/32)
 	AllowedIPsFormat = "%s/32"
+
+	// MinNetworkBitsIPv6 and MaxNetworkBitsIPv6 bound the prefix length of an account IPv6 network range
+	MinNetworkBitsIPv6 = 48
+	MaxNetworkBitsIPv6 = 64
 )
 
+// ulaPrefix is the IPv6 unique local address range (RFC 4193)
+var ulaPrefix = netip.MustParsePrefix("fc00::/7")
+
 type NetworkMap struct {
 	Peers               []*nbpeer.Peer
 	Network             *Network
@@ -214,6 +222,91 @@ func AllocateRandomPeerIP(ipNet net.IPNet) (net.IP, error) {
 	return uint32ToIP(candidate), nil
 }
 
+// ValidateNetworkRangeV6 checks that the prefix is a usable IPv6 unique local range for peer addressing.
+// An invalid (zero) prefix is accepted and means IPv6 overlay addressing is disabled.
+func ValidateNetworkRangeV6(prefix netip.Prefix) error {
+	if !prefix.IsValid() {
+		return nil
+	}
+	if !prefix.Addr().Is6() || prefix.Addr().Is4In6() {
+		return status.Errorf(status.InvalidArgument, "IPv6 network range %s is not an IPv6 prefix", prefix.String())
+	}
+	if !ulaPrefix.Contains(prefix.Addr()) {
+		return status.Errorf(status.InvalidArgument, "IPv6 network range %s must be within the unique local range %s", prefix.String(), ulaPrefix.String())
+	}
+	if prefix.Bits() < MinNetworkBitsIPv6 || prefix.Bits() > MaxNetworkBitsIPv6 {
+		return status.Errorf(status.InvalidArgument, "IPv6 network range prefix length must be between /%d and /%d", MinNetworkBitsIPv6, MaxNetworkBitsIPv6)
+	}
+	return nil
+}
+
+// AllocatePeerIPv6 picks a random IPv6 address from the given prefix that is not in takenIps.
+// The all-zeros host address (subnet-router anycast) is never returned.
+func AllocatePeerIPv6(prefix netip.Prefix, takenIps []net.IP) (net.IP, error) {
+	taken := make(map[netip.Addr]struct{}, len(takenIps))
+	for _, ip := range takenIps {
+		if addr, ok := netip.AddrFromSlice(ip); ok {
+			taken[addr.Unmap()] = struct{}{}
+		}
+	}
+
+	if err := validateAllocatablePrefixV6(prefix); err != nil {
+		return nil, err
+	}
+
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	const maxAttempts = 100
+	for i := 0; i < maxAttempts; i++ {
+		candidate := randomIPv6(prefix, rng)
+		addr, _ := netip.AddrFromSlice(candidate)
+		if _, exists := taken[addr]; !exists {
+			return candidate, nil
+		}
+	}
+
+	return nil, status.Errorf(status.PreconditionFailed, "network %s is out of IPs", prefix.String())
+}
+
+func validateAllocatablePrefixV6(prefix netip.Prefix) error {
+	if !prefix.IsValid() || !prefix.Addr().Is6() || prefix.Addr().Is4In6() {
+		return status.Errorf(status.InvalidArgument, "invalid IPv6 network range %s", prefix.String())
+	}
+	if prefix.Bits() > 126 {
+		return status.Errorf(status.InvalidArgument, "IPv6 network range %s is too small", prefix.String())
+	}
+	return nil
+}
+
+func randomIPv6(prefix netip.Prefix, rng *rand.Rand) net.IP {
+	base := prefix.Masked().Addr().As16()
+
+	var random [16]byte
+	binary.BigEndian.PutUint64(random[:8], rng.Uint64())
+	binary.BigEndian.PutUint64(random[8:], rng.Uint64())
+
+	ip := make(net.IP, net.IPv6len)
+	hostIsZero := true
+	for i := range ip {
+		netBits := prefix.Bits() - i*8
+		var mask byte
+		switch {
+		case netBits >= 8:
+			mask = 0xff
+		case netBits > 0:
+			mask = ^byte(0xff >> netBits)
+		}
+		ip[i] = base[i]&mask | random[i]&^mask
+		if random[i]&^mask != 0 {
+			hostIsZero = false
+		}
+	}
+	if hostIsZero {
+		ip[net.IPv6len-1] |= 1
+	}
+
+	return ip
+}
+
 func ipToUint32(ip net.IP) uint32 {
 	ip = ip.To4()
 	if len(ip) < 4 {
@@ -2,6 +2,7 @@ package types
 
 import (
 	"net"
+	"net/netip"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -140,6 +141,75 @@ func TestAllocatePeerIPVariousCIDRs(t *testing.T) {
 	}
 }
 
+func TestAllocatePeerIPv6(t *testing.T) {
+	prefix := netip.MustParsePrefix("fd00:1234:5678::/64")
+	var ips []net.IP
+	for i := 0; i < 500; i++ {
+		ip, err := AllocatePeerIPv6(prefix, ips)
+		require.NoError(t, err)
+
+		addr, ok := netip.AddrFromSlice(ip)
+		require.True(t, ok)
+		assert.True(t, addr.Is6(), "expected IPv6 address, got %s", addr)
+		assert.True(t, prefix.Contains(addr), "%s is not within %s", addr, prefix)
+		assert.NotEqual(t, prefix.Addr(), addr, "subnet-router anycast address must not be allocated")
+		ips = append(ips, ip)
+	}
+
+	uniq := make(map[string]struct{})
+	for _, ip := range ips {
+		if _, ok := uniq[ip.String()]; ok {
+			t.Errorf("found duplicate IP %s", ip.String())
+		}
+		uniq[ip.String()] = struct{}{}
+	}
+}
+
+func TestAllocatePeerIPv6NonByteAlignedPrefix(t *testing.T) {
+	prefix := netip.MustParsePrefix("fd12:3456:789a:bc00::/57")
+	for i := 0; i < 100; i++ {
+		ip, err := AllocatePeerIPv6(prefix, nil)
+		require.NoError(t, err)
+		addr, _ := netip.AddrFromSlice(ip)
+		assert.True(t, prefix.Contains(addr), "%s is not within %s", addr, prefix)
+	}
+}
+
+func TestAllocatePeerIPv6InvalidPrefix(t *testing.T) {
+	_, err := AllocatePeerIPv6(netip.MustParsePrefix("100.64.0.0/16"), nil)
+	assert.Error(t, err)
+
+	_, err = AllocatePeerIPv6(netip.Prefix{}, nil)
+	assert.Error(t, err)
+}
+
+func TestValidateNetworkRangeV6(t *testing.T) {
+	tests := []struct {
+		name    string
+		prefix  netip.Prefix
+		wantErr bool
+	}{
+		{name: "disabled", prefix: netip.Prefix{}},
+		{name: "ula /64", prefix: netip.MustParsePrefix("fd00:1::/64")},
+		{name: "ula /48", prefix: netip.MustParsePrefix("fd00:1::/48")},
+		{name: "ipv4", prefix: netip.MustParsePrefix("100.64.0.0/16"), wantErr: true},
+		{name: "global unicast", prefix: netip.MustParsePrefix("2001:db8::/64"), wantErr: true},
+		{name: "too large", prefix: netip.MustParsePrefix("fd00::/40"), wantErr: true},
+		{name: "too small", prefix: netip.MustParsePrefix("fd00:1::/96"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateNetworkRangeV6(tt.prefix)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestGenerateIPs(t *testing.T) {
 	ipNet := net.IPNet{IP: net.ParseIP("100.64.0.0"), Mask: net.IPMask{255, 255, 255, 0}}
 	ips, ipsLen := generateIPs(&ipNet, map[string]struct{}{"100.64.0.0": {}})
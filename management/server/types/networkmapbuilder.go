@@ -199,7 +199,7 @@ func (b *NetworkMapBuilder) buildGlobalIndexes(account *Account) {
 	}
 
 	for _, policy := range account.Policies {
-		if !policy.Enabled {
+		if !policy.IsActive() {
 			continue
 		}
 
@@ -892,7 +892,7 @@ func (b *NetworkMapBuilder) getRouteFirewallRules(
 	ctx := context.Background()
 	var fwRules []*RouteFirewallRule
 	for _, policy := range policies {
-		if !policy.Enabled {
+		if !policy.IsActive() {
 			continue
 		}
 
@@ -1515,7 +1515,7 @@ func (b *NetworkMapBuilder) calculateIncrementalUpdates(account *Account, newPee
 	}
 
 	for _, policy := range account.Policies {
-		if !policy.Enabled {
+		if !policy.IsActive() {
 			continue
 		}
 
@@ -1610,7 +1610,7 @@ func (b *NetworkMapBuilder) calculateNewRouterNetworkResourceUpdates(
 			peersWithAccess := make(map[string]struct{})
 
 			for _, policy := range policies {
-				if !policy.Enabled {
+				if !policy.IsActive() {
 					continue
 				}
 
@@ -1731,7 +1731,7 @@ func (b *NetworkMapBuilder) calculateNetworkResourceFirewallUpdates(
 		peerHasAccess := false
 
 		for _, policy := range policies {
-			if !policy.Enabled {
+			if !policy.IsActive() {
 				continue
 			}
 
@@ -34,4 +34,18 @@ type PeerLogin struct {
 
 	// ExtraDNSLabels is a list of extra DNS labels that the peer wants to use
 	ExtraDNSLabels []string
+	// WorkloadIdentity is set when a workload identity token was used to log in, and it was valid.
+	WorkloadIdentity *WorkloadIdentity
+}
+
+// WorkloadIdentity is a workload authenticated by a token of a workload identity provider of the account
+type WorkloadIdentity struct {
+	AccountID    string
+	ProviderID   string
+	ProviderName string
+	// Subject is the sub claim of the token identifying the workload
+	Subject string
+	// AutoGroups are the groups of the provider rules matching the token
+	AutoGroups []string
+	Ephemeral  bool
 }
@@ -5,6 +5,8 @@ import (
 	b64 "encoding/base64"
 	"fmt"
 	"hash/crc32"
+	"net/netip"
+	"slices"
 	"time"
 
 	b "github.com/hashicorp/go-secure-stdlib/base62"
@@ -12,6 +14,8 @@ import (
 	"github.com/rs/xid"
 
 	"github.com/netbirdio/netbird/base62"
+	"github.com/netbirdio/netbird/management/server/permissions/modules"
+	"github.com/netbirdio/netbird/management/server/permissions/operations"
 )
 
 const (
@@ -25,6 +29,33 @@ const (
 	PATLength = 40
 )
 
+// PATScopeAccess is the access a personal access token scope grants to a module
+type PATScopeAccess string
+
+const (
+	// PATScopeAccessRead allows the read operations of a module
+	PATScopeAccessRead PATScopeAccess = "read"
+	// PATScopeAccessWrite allows all operations of a module
+	PATScopeAccessWrite PATScopeAccess = "write"
+)
+
+// PATScope restricts a personal access token to a module of the permissions model
+type PATScope struct {
+	Module modules.Module `json:"module"`
+	Access PATScopeAccess `json:"access"`
+}
+
+// Validate checks that the scope references a known module and access
+func (s PATScope) Validate() error {
+	if _, ok := modules.All[s.Module]; !ok {
+		return fmt.Errorf("unknown module %q", s.Module)
+	}
+	if s.Access != PATScopeAccessRead && s.Access != PATScopeAccessWrite {
+		return fmt.Errorf("unknown access %q for module %s, must be read or write", s.Access, s.Module)
+	}
+	return nil
+}
+
 // PersonalAccessToken holds all information about a PAT including a hashed version of it for verification
 type PersonalAccessToken struct {
 	ID string `gorm:"primaryKey"`
@@ -33,22 +64,106 @@ type PersonalAccessToken struct {
 	Name           string
 	HashedToken    string
 	ExpirationDate *time.Time
-	// scope could be added in future
-	CreatedBy string
-	CreatedAt time.Time
-	LastUsed  *time.Time
+	// Scopes restrict the token to the listed modules, the token inherits all permissions of the user when empty
+	Scopes []PATScope `gorm:"serializer:json"`
+	// AllowedSourceRanges restrict the token to requests from the listed networks, any source is allowed when empty
+	AllowedSourceRanges []netip.Prefix `gorm:"serializer:json"`
+	CreatedBy           string
+	CreatedAt           time.Time
+	LastUsed            *time.Time
 }
 
 func (t *PersonalAccessToken) Copy() *PersonalAccessToken {
 	return &PersonalAccessToken{
-		ID:             t.ID,
-		Name:           t.Name,
-		HashedToken:    t.HashedToken,
-		ExpirationDate: t.ExpirationDate,
-		CreatedBy:      t.CreatedBy,
-		CreatedAt:      t.CreatedAt,
-		LastUsed:       t.LastUsed,
+		ID:                  t.ID,
+		Name:                t.Name,
+		HashedToken:         t.HashedToken,
+		ExpirationDate:      t.ExpirationDate,
+		Scopes:              slices.Clone(t.Scopes),
+		AllowedSourceRanges: slices.Clone(t.AllowedSourceRanges),
+		CreatedBy:           t.CreatedBy,
+		CreatedAt:           t.CreatedAt,
+		LastUsed:            t.LastUsed,
+	}
+}
+
+// IsScoped returns true if the token is restricted to a set of modules
+func (t *PersonalAccessToken) IsScoped() bool {
+	return len(t.Scopes) > 0
+}
+
+// AllowsOperation checks if the scopes of the token allow the operation on the module.
+// Tokens without scopes allow every operation, the permissions of the user still apply.
+func (t *PersonalAccessToken) AllowsOperation(module modules.Module, operation operations.Operation) bool {
+	if !t.IsScoped() {
+		return true
+	}
+
+	for _, scope := range t.Scopes {
+		if scope.Module != module {
+			continue
+		}
+		if scope.Access == PATScopeAccessWrite || operation == operations.Read {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsSource checks if the token can be used from the address
+func (t *PersonalAccessToken) AllowsSource(addr netip.Addr) bool {
+	if len(t.AllowedSourceRanges) == 0 {
+		return true
+	}
+
+	addr = addr.Unmap()
+	for _, prefix := range t.AllowedSourceRanges {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Covers checks if a token with the scopes and source ranges grants nothing beyond this token.
+// A token without scopes or source ranges is only covered by a token without them.
+func (t *PersonalAccessToken) Covers(scopes []PATScope, allowedSourceRanges []netip.Prefix) bool {
+	if t.IsScoped() {
+		if len(scopes) == 0 {
+			return false
+		}
+		for _, scope := range scopes {
+			operation := operations.Read
+			if scope.Access == PATScopeAccessWrite {
+				operation = operations.Create
+			}
+			if !t.AllowsOperation(scope.Module, operation) {
+				return false
+			}
+		}
+	}
+
+	if len(t.AllowedSourceRanges) > 0 {
+		if len(allowedSourceRanges) == 0 {
+			return false
+		}
+		for _, prefix := range allowedSourceRanges {
+			if !t.coversPrefix(prefix) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func (t *PersonalAccessToken) coversPrefix(prefix netip.Prefix) bool {
+	for _, allowed := range t.AllowedSourceRanges {
+		if allowed.Bits() <= prefix.Bits() && allowed.Contains(prefix.Addr()) {
+			return true
+		}
 	}
+	return false
 }
 
 // GetExpirationDate returns the expiration time of the token.
@@ -75,7 +190,7 @@ type PersonalAccessTokenGenerated struct {
 
 // CreateNewPAT will generate a new PersonalAccessToken that can be assigned to a User.
 // Additionally, it will return the token in plain text once, to give to the user and only save a hashed version
-func CreateNewPAT(name string, expirationInDays int, targetID, createdBy string) (*PersonalAccessTokenGenerated, error) {
+func CreateNewPAT(name string, expirationInDays int, targetID, createdBy string, scopes []PATScope, allowedSourceRanges []netip.Prefix) (*PersonalAccessTokenGenerated, error) {
 	hashedToken, plainToken, err := generateNewToken()
 	if err != nil {
 		return nil, err
@@ -83,13 +198,15 @@ func CreateNewPAT(name string, expirationInDays int, targetID, createdBy string)
 	currentTime := time.Now()
 	return &PersonalAccessTokenGenerated{
 		PersonalAccessToken: PersonalAccessToken{
-			ID:             xid.New().String(),
-			UserID:         targetID,
-			Name:           name,
-			HashedToken:    hashedToken,
-			ExpirationDate: util.ToPtr(currentTime.AddDate(0, 0, expirationInDays)),
-			CreatedBy:      createdBy,
-			CreatedAt:      currentTime,
+			ID:                  xid.New().String(),
+			UserID:              targetID,
+			Name:                name,
+			HashedToken:         hashedToken,
+			ExpirationDate:      util.ToPtr(currentTime.AddDate(0, 0, expirationInDays)),
+			Scopes:              scopes,
+			AllowedSourceRanges: allowedSourceRanges,
+			CreatedBy:           createdBy,
+			CreatedAt:           currentTime,
 		},
 		PlainToken: plainToken,
 	}, nil
@@ -5,12 +5,15 @@ import (
 	b64 "encoding/base64"
 	"hash/crc32"
 	"math/big"
+	"net/netip"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/netbirdio/netbird/base62"
+	"github.com/netbirdio/netbird/management/server/permissions/modules"
+	"github.com/netbirdio/netbird/management/server/permissions/operations"
 )
 
 func TestPAT_GenerateToken_Hashing(t *testing.T) {
@@ -44,3 +47,62 @@ func TestPAT_GenerateToken_Checksum(t *testing.T) {
 	}
 	assert.Equal(t, expectedChecksum, actualChecksum)
 }
+
+func TestPAT_AllowsOperation(t *testing.T) {
+	unscoped := &PersonalAccessToken{}
+	assert.True(t, unscoped.AllowsOperation(modules.Users, operations.Delete), "tokens without scopes are not restricted")
+
+	pat := &PersonalAccessToken{
+		Scopes: []PATScope{
+			{Module: modules.SetupKeys, Access: PATScopeAccessWrite},
+			{Module: modules.Peers, Access: PATScopeAccessRead},
+		},
+	}
+	assert.True(t, pat.AllowsOperation(modules.SetupKeys, operations.Create))
+	assert.True(t, pat.AllowsOperation(modules.SetupKeys, operations.Read))
+	assert.True(t, pat.AllowsOperation(modules.Peers, operations.Read))
+	assert.False(t, pat.AllowsOperation(modules.Peers, operations.Update))
+	assert.False(t, pat.AllowsOperation(modules.Groups, operations.Read))
+}
+
+func TestPAT_AllowsSource(t *testing.T) {
+	unrestricted := &PersonalAccessToken{}
+	assert.True(t, unrestricted.AllowsSource(netip.MustParseAddr("198.51.100.1")))
+
+	pat := &PersonalAccessToken{
+		AllowedSourceRanges: []netip.Prefix{netip.MustParsePrefix("203.0.113.0/24"), netip.MustParsePrefix("2001:db8::/32")},
+	}
+	assert.True(t, pat.AllowsSource(netip.MustParseAddr("203.0.113.10")))
+	assert.True(t, pat.AllowsSource(netip.MustParseAddr("::ffff:203.0.113.10")))
+	assert.True(t, pat.AllowsSource(netip.MustParseAddr("2001:db8::1")))
+	assert.False(t, pat.AllowsSource(netip.MustParseAddr("198.51.100.1")))
+}
+
+func TestPAT_Covers(t *testing.T) {
+	unrestricted := &PersonalAccessToken{}
+	assert.True(t, unrestricted.Covers(nil, nil))
+	assert.True(t, unrestricted.Covers([]PATScope{{Module: modules.Users, Access: PATScopeAccessWrite}}, nil))
+
+	pat := &PersonalAccessToken{
+		Scopes: []PATScope{
+			{Module: modules.Pats, Access: PATScopeAccessWrite},
+			{Module: modules.Peers, Access: PATScopeAccessRead},
+		},
+		AllowedSourceRanges: []netip.Prefix{netip.MustParsePrefix("203.0.113.0/24")},
+	}
+	narrower := []netip.Prefix{netip.MustParsePrefix("203.0.113.128/25")}
+	assert.True(t, pat.Covers([]PATScope{{Module: modules.Peers, Access: PATScopeAccessRead}}, narrower))
+	assert.True(t, pat.Covers([]PATScope{{Module: modules.Pats, Access: PATScopeAccessWrite}}, pat.AllowedSourceRanges))
+	assert.False(t, pat.Covers(nil, narrower), "unscoped tokens are not covered by a scoped token")
+	assert.False(t, pat.Covers([]PATScope{{Module: modules.Peers, Access: PATScopeAccessWrite}}, narrower))
+	assert.False(t, pat.Covers([]PATScope{{Module: modules.Users, Access: PATScopeAccessRead}}, narrower))
+	assert.False(t, pat.Covers([]PATScope{{Module: modules.Peers, Access: PATScopeAccessRead}}, nil), "tokens without source ranges are not covered by a restricted token")
+	assert.False(t, pat.Covers([]PATScope{{Module: modules.Peers, Access: PATScopeAccessRead}}, []netip.Prefix{netip.MustParsePrefix("203.0.112.0/23")}))
+	assert.False(t, pat.Covers([]PATScope{{Module: modules.Peers, Access: PATScopeAccessRead}}, []netip.Prefix{netip.MustParsePrefix("2001:db8::/32")}))
+}
+
+func TestPATScope_Validate(t *testing.T) {
+	assert.NoError(t, PATScope{Module: modules.SetupKeys, Access: PATScopeAccessWrite}.Validate())
+	assert.Error(t, PATScope{Module: "unknown", Access: PATScopeAccessRead}.Validate())
+	assert.Error(t, PATScope{Module: modules.Peers, Access: "delete"}.Validate())
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 )
 
 const (
@@ -73,6 +74,9 @@ type Policy struct {
 
 	// SourcePostureChecks are ID references to Posture checks for policy source groups
 	SourcePostureChecks []string `gorm:"serializer:json"`
+
+	// Schedule limits the time during which the policy is applied, always applied if nil
+	Schedule *PolicySchedule `gorm:"serializer:json"`
 }
 
 // Copy returns a copy of the policy.
@@ -85,6 +89,7 @@ func (p *Policy) Copy() *Policy {
 		Enabled:             p.Enabled,
 		Rules:               make([]*PolicyRule, len(p.Rules)),
 		SourcePostureChecks: make([]string, len(p.SourcePostureChecks)),
+		Schedule:            p.Schedule.Copy(),
 	}
 	for i, r := range p.Rules {
 		c.Rules[i] = r.Copy()
@@ -93,6 +98,16 @@ func (p *Policy) Copy() *Policy {
 	return c
 }
 
+// IsActive returns true if the policy is enabled and its schedule allows it to be applied now
+func (p *Policy) IsActive() bool {
+	return p.IsActiveAt(time.Now().UTC())
+}
+
+// IsActiveAt returns true if the policy is enabled and its schedule allows it to be applied at the given time
+func (p *Policy) IsActiveAt(t time.Time) bool {
+	return p.Enabled && p.Schedule.IsActiveAt(t)
+}
+
 // EventMeta returns activity event meta related to this policy
 func (p *Policy) EventMeta() map[string]any {
 	return map[string]any{"name": p.Name}
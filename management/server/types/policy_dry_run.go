@@ -0,0 +1,165 @@
+package types
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	nbdns "github.com/netbirdio/netbird/dns"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/route"
+)
+
+// PeerNetworkMapDiff is the change of a peer network map caused by a policy change
+type PeerNetworkMapDiff struct {
+	PeerID   string
+	PeerName string
+	// AddedPeers are the IDs of the peers the peer would start connecting to
+	AddedPeers []string
+	// RemovedPeers are the IDs of the peers the peer would stop connecting to
+	RemovedPeers []string
+	// AddedRoutes are the IDs of the routes the peer would start receiving
+	AddedRoutes []route.ID
+	// RemovedRoutes are the IDs of the routes the peer would stop receiving
+	RemovedRoutes             []route.ID
+	AddedFirewallRules        []*FirewallRule
+	RemovedFirewallRules      []*FirewallRule
+	AddedRouteFirewallRules   []*RouteFirewallRule
+	RemovedRouteFirewallRules []*RouteFirewallRule
+}
+
+// IsEmpty returns true if the network map of the peer doesn't change
+func (d *PeerNetworkMapDiff) IsEmpty() bool {
+	return len(d.AddedPeers) == 0 && len(d.RemovedPeers) == 0 &&
+		len(d.AddedRoutes) == 0 && len(d.RemovedRoutes) == 0 &&
+		len(d.AddedFirewallRules) == 0 && len(d.RemovedFirewallRules) == 0 &&
+		len(d.AddedRouteFirewallRules) == 0 && len(d.RemovedRouteFirewallRules) == 0
+}
+
+// WithPolicies returns a copy of the account with the policies overlaying the current ones:
+// a policy with an existing ID replaces the current one and other policies are appended.
+func (a *Account) WithPolicies(policies ...*Policy) *Account {
+	account := a.Copy()
+	account.Policies = overlayPolicies(account.Policies, policies)
+	return account
+}
+
+// DiffPeerNetworkMaps compares the network maps of every peer of the account before and after a change
+// and returns the peers whose peers, routes, firewall rules or route firewall rules change, sorted by the peer name.
+func DiffPeerNetworkMaps(ctx context.Context, before, after *Account, validatedPeersMap map[string]struct{}) []*PeerNetworkMapDiff {
+	var diffs []*PeerNetworkMapDiff
+
+	beforeResourcePolicies, beforeRouters, beforeGroupUsers := before.GetResourcePoliciesMap(), before.GetResourceRoutersMap(), before.GetActiveGroupUsers()
+	afterResourcePolicies, afterRouters, afterGroupUsers := after.GetResourcePoliciesMap(), after.GetResourceRoutersMap(), after.GetActiveGroupUsers()
+
+	for peerID, peer := range after.Peers {
+		if _, ok := validatedPeersMap[peerID]; !ok {
+			continue
+		}
+
+		beforeMap := before.GetPeerNetworkMap(ctx, peerID, nbdns.CustomZone{}, nil, validatedPeersMap, beforeResourcePolicies, beforeRouters, nil, beforeGroupUsers)
+		afterMap := after.GetPeerNetworkMap(ctx, peerID, nbdns.CustomZone{}, nil, validatedPeersMap, afterResourcePolicies, afterRouters, nil, afterGroupUsers)
+
+		diff := &PeerNetworkMapDiff{
+			PeerID:   peerID,
+			PeerName: peer.Name,
+		}
+		diff.AddedPeers, diff.RemovedPeers = diffSlices(peerIDs(beforeMap.Peers), peerIDs(afterMap.Peers), func(id string) string { return id })
+		diff.AddedRoutes, diff.RemovedRoutes = diffSlices(routeIDs(beforeMap.Routes), routeIDs(afterMap.Routes), func(id route.ID) string { return string(id) })
+		diff.AddedFirewallRules, diff.RemovedFirewallRules = diffSlices(beforeMap.FirewallRules, afterMap.FirewallRules, firewallRuleKey)
+		diff.AddedRouteFirewallRules, diff.RemovedRouteFirewallRules = diffSlices(beforeMap.RoutesFirewallRules, afterMap.RoutesFirewallRules, routeFirewallRuleKey)
+
+		if !diff.IsEmpty() {
+			diffs = append(diffs, diff)
+		}
+	}
+
+	slices.SortFunc(diffs, func(a, b *PeerNetworkMapDiff) int {
+		if c := strings.Compare(a.PeerName, b.PeerName); c != 0 {
+			return c
+		}
+		return strings.Compare(a.PeerID, b.PeerID)
+	})
+
+	return diffs
+}
+
+// overlayPolicies replaces the policies with the same ID and appends the new ones
+func overlayPolicies(current, overlay []*Policy) []*Policy {
+	replaced := make(map[string]*Policy, len(overlay))
+	for _, policy := range overlay {
+		replaced[policy.ID] = policy
+	}
+
+	policies := make([]*Policy, 0, len(current)+len(overlay))
+	for _, policy := range current {
+		if p, ok := replaced[policy.ID]; ok {
+			policies = append(policies, p)
+			delete(replaced, policy.ID)
+			continue
+		}
+		policies = append(policies, policy)
+	}
+
+	for _, policy := range overlay {
+		if _, ok := replaced[policy.ID]; ok {
+			policies = append(policies, policy)
+		}
+	}
+
+	return policies
+}
+
+// diffSlices returns the elements only present in after as added and only present in before as removed
+func diffSlices[T any](before, after []T, key func(T) string) (added, removed []T) {
+	beforeKeys := make(map[string]struct{}, len(before))
+	for _, item := range before {
+		beforeKeys[key(item)] = struct{}{}
+	}
+
+	afterKeys := make(map[string]struct{}, len(after))
+	for _, item := range after {
+		k := key(item)
+		afterKeys[k] = struct{}{}
+		if _, ok := beforeKeys[k]; !ok {
+			added = append(added, item)
+		}
+	}
+
+	for _, item := range before {
+		if _, ok := afterKeys[key(item)]; !ok {
+			removed = append(removed, item)
+		}
+	}
+
+	return added, removed
+}
+
+func peerIDs(peers []*nbpeer.Peer) []string {
+	ids := make([]string, 0, len(peers))
+	for _, peer := range peers {
+		ids = append(ids, peer.ID)
+	}
+	return ids
+}
+
+func routeIDs(routes []*route.Route) []route.ID {
+	ids := make([]route.ID, 0, len(routes))
+	for _, r := range routes {
+		ids = append(ids, r.ID)
+	}
+	return ids
+}
+
+func firewallRuleKey(rule *FirewallRule) string {
+	return fmt.Sprintf("%s:%s:%d:%s:%s:%s:%d-%d", rule.PolicyID, rule.PeerIP, rule.Direction, rule.Protocol,
+		rule.Action, rule.Port, rule.PortRange.Start, rule.PortRange.End)
+}
+
+func routeFirewallRuleKey(rule *RouteFirewallRule) string {
+	sourceRanges := slices.Clone(rule.SourceRanges)
+	slices.Sort(sourceRanges)
+	return fmt.Sprintf("%s:%s:%s:%s:%s:%s:%d:%d-%d:%s", rule.PolicyID, rule.RouteID, strings.Join(sourceRanges, ","),
+		rule.Action, rule.Destination, rule.Protocol, rule.Port, rule.PortRange.Start, rule.PortRange.End, rule.Domains.PunycodeString())
+}
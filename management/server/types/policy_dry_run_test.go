@@ -0,0 +1,100 @@
+package types
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffPeerNetworkMaps(t *testing.T) {
+	validatedPeers := map[string]struct{}{accNetResourcePeer1ID: {}, accNetResourcePeer2ID: {}, accNetResourceRouter1ID: {}}
+
+	t.Run("unchanged policy", func(t *testing.T) {
+		account := getPolicySimulationAccount()
+		after := account.WithPolicies(account.Policies[1].Copy())
+
+		assert.Empty(t, DiffPeerNetworkMaps(context.Background(), account, after, validatedPeers))
+	})
+
+	t.Run("changed peer policy port", func(t *testing.T) {
+		account := getPolicySimulationAccount()
+		policy := account.Policies[1].Copy()
+		policy.Rules[0].Ports = []string{"2222"}
+
+		diffs := DiffPeerNetworkMaps(context.Background(), account, account.WithPolicies(policy), validatedPeers)
+		require.Len(t, diffs, 2)
+		assert.Equal(t, accNetResourcePeer1ID, diffs[0].PeerID)
+		assert.Equal(t, accNetResourcePeer2ID, diffs[1].PeerID)
+
+		for _, diff := range diffs {
+			assert.Empty(t, diff.AddedPeers)
+			assert.Empty(t, diff.RemovedPeers)
+			require.NotEmpty(t, diff.AddedFirewallRules)
+			require.Len(t, diff.RemovedFirewallRules, len(diff.AddedFirewallRules))
+			for _, rule := range diff.AddedFirewallRules {
+				assert.Equal(t, "2222", rule.Port)
+			}
+			for _, rule := range diff.RemovedFirewallRules {
+				assert.Equal(t, "22", rule.Port)
+			}
+		}
+	})
+
+	t.Run("disabled peer policy", func(t *testing.T) {
+		account := getPolicySimulationAccount()
+		policy := account.Policies[1].Copy()
+		policy.Enabled = false
+
+		diffs := DiffPeerNetworkMaps(context.Background(), account, account.WithPolicies(policy), validatedPeers)
+		require.Len(t, diffs, 2)
+		assert.Equal(t, []string{accNetResourcePeer2ID}, diffs[0].RemovedPeers)
+		assert.Equal(t, []string{accNetResourcePeer1ID}, diffs[1].RemovedPeers)
+		assert.Empty(t, diffs[0].AddedFirewallRules)
+		assert.NotEmpty(t, diffs[0].RemovedFirewallRules)
+	})
+
+	t.Run("changed resource policy port", func(t *testing.T) {
+		account := getPolicySimulationAccount()
+		policy := account.Policies[0].Copy()
+		policy.Rules[0].Ports = []string{"443"}
+
+		diffs := DiffPeerNetworkMaps(context.Background(), account, account.WithPolicies(policy), validatedPeers)
+		require.Len(t, diffs, 1)
+		assert.Equal(t, accNetResourceRouter1ID, diffs[0].PeerID)
+		require.Len(t, diffs[0].AddedRouteFirewallRules, 1)
+		require.Len(t, diffs[0].RemovedRouteFirewallRules, 1)
+		assert.Equal(t, uint16(443), diffs[0].AddedRouteFirewallRules[0].Port)
+		assert.Equal(t, uint16(80), diffs[0].RemovedRouteFirewallRules[0].Port)
+	})
+
+	t.Run("new policy", func(t *testing.T) {
+		account := getPolicySimulationAccount()
+		policy := &Policy{
+			ID:      "policy3ID",
+			Enabled: true,
+			Rules: []*PolicyRule{
+				{
+					ID:           "rule3ID",
+					Enabled:      true,
+					Sources:      []string{group1ID},
+					Destinations: []string{group1ID},
+					Protocol:     PolicyRuleProtocolUDP,
+					Action:       PolicyTrafficActionAccept,
+				},
+			},
+		}
+
+		after := account.WithPolicies(policy)
+		assert.Len(t, after.Policies, len(account.Policies)+1)
+
+		diffs := DiffPeerNetworkMaps(context.Background(), account, after, validatedPeers)
+		require.Len(t, diffs, 2)
+		for _, diff := range diffs {
+			assert.Empty(t, diff.RemovedFirewallRules)
+			require.NotEmpty(t, diff.AddedFirewallRules)
+			assert.Equal(t, "rule3ID", diff.AddedFirewallRules[0].PolicyID)
+		}
+	})
+}
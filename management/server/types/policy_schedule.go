@@ -0,0 +1,221 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// PolicySchedule restricts the time during which an enabled policy is applied.
+// All configured conditions must match for the policy to be active.
+type PolicySchedule struct {
+	// NotBefore is the time from which the policy is applied
+	NotBefore *time.Time `json:"not_before,omitempty"`
+	// NotAfter is the time when the policy expires
+	NotAfter *time.Time `json:"not_after,omitempty"`
+	// TimeZone is the IANA time zone the recurring windows are evaluated in, UTC if empty
+	TimeZone string `json:"time_zone,omitempty"`
+	// Windows are recurring weekly windows in which the policy is applied. No windows means all day.
+	Windows []PolicyScheduleWindow `json:"windows,omitempty"`
+}
+
+// PolicyScheduleWindow is a recurring daily time window on the given days of the week.
+// If End is not after Start, the window ends on the following day.
+type PolicyScheduleWindow struct {
+	Days  []time.Weekday `json:"days"`
+	Start string         `json:"start"`
+	End   string         `json:"end"`
+}
+
+// Copy returns a copy of the schedule.
+func (s *PolicySchedule) Copy() *PolicySchedule {
+	if s == nil {
+		return nil
+	}
+
+	c := &PolicySchedule{
+		TimeZone: s.TimeZone,
+		Windows:  make([]PolicyScheduleWindow, len(s.Windows)),
+	}
+	if s.NotBefore != nil {
+		notBefore := *s.NotBefore
+		c.NotBefore = &notBefore
+	}
+	if s.NotAfter != nil {
+		notAfter := *s.NotAfter
+		c.NotAfter = &notAfter
+	}
+	for i, w := range s.Windows {
+		c.Windows[i] = PolicyScheduleWindow{
+			Days:  append([]time.Weekday(nil), w.Days...),
+			Start: w.Start,
+			End:   w.End,
+		}
+	}
+	return c
+}
+
+// Validate checks that the schedule can be evaluated.
+func (s *PolicySchedule) Validate() error {
+	if s == nil {
+		return nil
+	}
+
+	if s.NotBefore != nil && s.NotAfter != nil && !s.NotAfter.After(*s.NotBefore) {
+		return errors.New("schedule not_after must be after not_before")
+	}
+
+	if _, err := s.location(); err != nil {
+		return fmt.Errorf("invalid schedule time zone %q: %w", s.TimeZone, err)
+	}
+
+	for _, w := range s.Windows {
+		if len(w.Days) == 0 {
+			return errors.New("schedule window must have at least one day")
+		}
+		for _, day := range w.Days {
+			if day < time.Sunday || day > time.Saturday {
+				return fmt.Errorf("invalid schedule window day %d", day)
+			}
+		}
+		if _, err := parseClock(w.Start); err != nil {
+			return fmt.Errorf("invalid schedule window start: %w", err)
+		}
+		if _, err := parseClock(w.End); err != nil {
+			return fmt.Errorf("invalid schedule window end: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// IsActiveAt returns true if the schedule allows the policy to be applied at the given time.
+func (s *PolicySchedule) IsActiveAt(t time.Time) bool {
+	if s == nil {
+		return true
+	}
+
+	if s.NotBefore != nil && t.Before(*s.NotBefore) {
+		return false
+	}
+	if s.NotAfter != nil && !t.Before(*s.NotAfter) {
+		return false
+	}
+	if len(s.Windows) == 0 {
+		return true
+	}
+
+	loc, err := s.location()
+	if err != nil {
+		return false
+	}
+
+	local := t.In(loc)
+	// a window that started yesterday can still be open
+	for _, dayOffset := range []int{0, -1} {
+		for _, w := range s.Windows {
+			start, end, ok := w.boundsOn(local, dayOffset, loc)
+			if ok && !local.Before(start) && local.Before(end) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// IsExpiredAt returns true if the schedule has an expiry that is not after the given time.
+func (s *PolicySchedule) IsExpiredAt(t time.Time) bool {
+	return s != nil && s.NotAfter != nil && !t.Before(*s.NotAfter)
+}
+
+// NextTransition returns the first time after t at which the schedule may switch between active and inactive.
+func (s *PolicySchedule) NextTransition(t time.Time) (time.Time, bool) {
+	if s == nil {
+		return time.Time{}, false
+	}
+
+	var next time.Time
+	consider := func(candidate time.Time) {
+		if candidate.After(t) && (next.IsZero() || candidate.Before(next)) {
+			next = candidate
+		}
+	}
+
+	if s.NotBefore != nil {
+		consider(*s.NotBefore)
+	}
+	if s.NotAfter != nil {
+		consider(*s.NotAfter)
+	}
+
+	if s.IsExpiredAt(t) {
+		return next, !next.IsZero()
+	}
+
+	loc, err := s.location()
+	if err == nil {
+		local := t.In(loc)
+		for dayOffset := -1; dayOffset <= 7; dayOffset++ {
+			for _, w := range s.Windows {
+				start, end, ok := w.boundsOn(local, dayOffset, loc)
+				if !ok {
+					continue
+				}
+				consider(start)
+				consider(end)
+			}
+		}
+	}
+
+	return next, !next.IsZero()
+}
+
+func (s *PolicySchedule) location() (*time.Location, error) {
+	if s.TimeZone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(s.TimeZone)
+}
+
+// boundsOn returns the start and end of the window on the day at dayOffset from t if the window applies to that day.
+func (w PolicyScheduleWindow) boundsOn(t time.Time, dayOffset int, loc *time.Location) (time.Time, time.Time, bool) {
+	day := time.Date(t.Year(), t.Month(), t.Day()+dayOffset, 0, 0, 0, 0, loc)
+
+	applies := false
+	for _, d := range w.Days {
+		if d == day.Weekday() {
+			applies = true
+			break
+		}
+	}
+	if !applies {
+		return time.Time{}, time.Time{}, false
+	}
+
+	startMinutes, err := parseClock(w.Start)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	endMinutes, err := parseClock(w.End)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+
+	start := time.Date(day.Year(), day.Month(), day.Day(), startMinutes/60, startMinutes%60, 0, 0, loc)
+	end := time.Date(day.Year(), day.Month(), day.Day(), endMinutes/60, endMinutes%60, 0, 0, loc)
+	if !end.After(start) {
+		end = end.AddDate(0, 0, 1)
+	}
+
+	return start, end, true
+}
+
+// parseClock parses a HH:MM time of day and returns the number of minutes since midnight.
+func parseClock(clock string) (int, error) {
+	parsed, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, fmt.Errorf("expected HH:MM, got %q", clock)
+	}
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
@@ -0,0 +1,155 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicySchedule_IsActiveAt(t *testing.T) {
+	notBefore := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	notAfter := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		schedule *PolicySchedule
+		at       time.Time
+		expected bool
+	}{
+		{
+			name:     "nil schedule",
+			at:       notBefore,
+			expected: true,
+		},
+		{
+			name:     "before not_before",
+			schedule: &PolicySchedule{NotBefore: &notBefore},
+			at:       notBefore.Add(-time.Minute),
+			expected: false,
+		},
+		{
+			name:     "at not_before",
+			schedule: &PolicySchedule{NotBefore: &notBefore, NotAfter: &notAfter},
+			at:       notBefore,
+			expected: true,
+		},
+		{
+			name:     "at not_after",
+			schedule: &PolicySchedule{NotBefore: &notBefore, NotAfter: &notAfter},
+			at:       notAfter,
+			expected: false,
+		},
+		{
+			name: "inside business hours window",
+			schedule: &PolicySchedule{
+				Windows: []PolicyScheduleWindow{{Days: []time.Weekday{time.Monday, time.Tuesday}, Start: "09:00", End: "17:00"}},
+			},
+			// Monday
+			at:       time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC),
+			expected: true,
+		},
+		{
+			name: "outside business hours window",
+			schedule: &PolicySchedule{
+				Windows: []PolicyScheduleWindow{{Days: []time.Weekday{time.Monday}, Start: "09:00", End: "17:00"}},
+			},
+			at:       time.Date(2025, 6, 2, 17, 0, 0, 0, time.UTC),
+			expected: false,
+		},
+		{
+			name: "window on another day",
+			schedule: &PolicySchedule{
+				Windows: []PolicyScheduleWindow{{Days: []time.Weekday{time.Tuesday}, Start: "09:00", End: "17:00"}},
+			},
+			at:       time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC),
+			expected: false,
+		},
+		{
+			name: "overnight window started the previous day",
+			schedule: &PolicySchedule{
+				Windows: []PolicyScheduleWindow{{Days: []time.Weekday{time.Sunday}, Start: "22:00", End: "02:00"}},
+			},
+			at:       time.Date(2025, 6, 2, 1, 0, 0, 0, time.UTC),
+			expected: true,
+		},
+		{
+			name: "window in time zone",
+			schedule: &PolicySchedule{
+				TimeZone: "Europe/Berlin",
+				Windows:  []PolicyScheduleWindow{{Days: []time.Weekday{time.Monday}, Start: "09:00", End: "17:00"}},
+			},
+			// 07:30 UTC is 09:30 in Berlin during summer time
+			at:       time.Date(2025, 6, 2, 7, 30, 0, 0, time.UTC),
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.schedule.IsActiveAt(tt.at))
+		})
+	}
+}
+
+func TestPolicySchedule_NextTransition(t *testing.T) {
+	schedule := &PolicySchedule{
+		Windows: []PolicyScheduleWindow{{Days: []time.Weekday{time.Monday}, Start: "09:00", End: "17:00"}},
+	}
+
+	// Sunday
+	next, ok := schedule.NextTransition(time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC))
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC), next)
+
+	next, ok = schedule.NextTransition(time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC))
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2025, 6, 2, 17, 0, 0, 0, time.UTC), next)
+
+	notAfter := time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC)
+	schedule.NotAfter = &notAfter
+	next, ok = schedule.NextTransition(time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC))
+	require.True(t, ok)
+	assert.Equal(t, notAfter, next)
+
+	_, ok = schedule.NextTransition(notAfter)
+	assert.False(t, ok, "expired schedules have no further transitions")
+
+	_, ok = (&PolicySchedule{}).NextTransition(notAfter)
+	assert.False(t, ok)
+}
+
+func TestPolicySchedule_Validate(t *testing.T) {
+	notBefore := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+
+	assert.NoError(t, (*PolicySchedule)(nil).Validate())
+	assert.NoError(t, (&PolicySchedule{
+		TimeZone: "America/New_York",
+		Windows:  []PolicyScheduleWindow{{Days: []time.Weekday{time.Friday}, Start: "22:00", End: "06:00"}},
+	}).Validate())
+
+	assert.Error(t, (&PolicySchedule{NotBefore: &notBefore, NotAfter: &notBefore}).Validate())
+	assert.Error(t, (&PolicySchedule{TimeZone: "Mars/Olympus"}).Validate())
+	assert.Error(t, (&PolicySchedule{
+		Windows: []PolicyScheduleWindow{{Start: "09:00", End: "17:00"}},
+	}).Validate())
+	assert.Error(t, (&PolicySchedule{
+		Windows: []PolicyScheduleWindow{{Days: []time.Weekday{time.Monday}, Start: "9am", End: "17:00"}},
+	}).Validate())
+}
+
+func TestPolicy_IsActiveAt(t *testing.T) {
+	notAfter := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	policy := &Policy{Enabled: true, Schedule: &PolicySchedule{NotAfter: &notAfter}}
+
+	assert.True(t, policy.IsActiveAt(notAfter.Add(-time.Hour)))
+	assert.False(t, policy.IsActiveAt(notAfter))
+
+	policy.Enabled = false
+	assert.False(t, policy.IsActiveAt(notAfter.Add(-time.Hour)))
+
+	c := policy.Copy()
+	*c.Schedule.NotAfter = notAfter.Add(time.Hour)
+	assert.Equal(t, notAfter, *policy.Schedule.NotAfter, "copy must not share the schedule")
+}
@@ -0,0 +1,410 @@
+package types
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	nbdns "github.com/netbirdio/netbird/dns"
+	resourceTypes "github.com/netbirdio/netbird/management/server/networks/resources/types"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/posture"
+	"github.com/netbirdio/netbird/route"
+)
+
+// PolicySimulation describes the traffic which is checked against the account policies
+type PolicySimulation struct {
+	// SourcePeerID is the peer initiating the traffic
+	SourcePeerID string
+	// DestinationPeerID is the peer receiving the traffic, mutually exclusive with DestinationResourceID
+	DestinationPeerID string
+	// DestinationResourceID is the network resource receiving the traffic, mutually exclusive with DestinationPeerID
+	DestinationResourceID string
+	// Protocol of the traffic, PolicyRuleProtocolALL matches rules of any protocol
+	Protocol PolicyRuleProtocolType
+	// Port of the traffic, 0 matches rules of any port
+	Port uint16
+}
+
+// PolicySimulationRule is a policy rule which matches the simulated traffic
+type PolicySimulationRule struct {
+	PolicyID   string
+	PolicyName string
+	RuleID     string
+	RuleName   string
+	Action     PolicyTrafficActionType
+	// Applied indicates whether the rule ends up in the network maps of the peers,
+	// e.g. it is not applied when the source peer fails the posture checks of the policy
+	Applied bool
+}
+
+// PolicySimulationResult is the answer whether the simulated traffic is allowed
+type PolicySimulationResult struct {
+	Allowed bool
+	// Reason describes why the traffic is denied, empty if it is allowed
+	Reason string
+	// Rules are the policy rules matching the traffic
+	Rules []PolicySimulationRule
+	// PostureChecks are the results of the posture checks of the matching policies evaluated on the source peer
+	PostureChecks []posture.Result
+	// Routes are the routes of the source peer to the destination network resource
+	Routes []*route.Route
+}
+
+// Validate checks that the simulation describes a valid traffic
+func (s *PolicySimulation) Validate() error {
+	if s.SourcePeerID == "" {
+		return errors.New("source peer is required")
+	}
+
+	if (s.DestinationPeerID == "") == (s.DestinationResourceID == "") {
+		return errors.New("specify either destination peer or destination resource")
+	}
+
+	switch s.Protocol {
+	case PolicyRuleProtocolALL, PolicyRuleProtocolICMP:
+		if s.Port != 0 {
+			return fmt.Errorf("port is not allowed for %s protocol", s.Protocol)
+		}
+	case PolicyRuleProtocolTCP, PolicyRuleProtocolUDP:
+	default:
+		return fmt.Errorf("unsupported protocol: %s", s.Protocol)
+	}
+
+	return nil
+}
+
+// SimulatePolicy checks whether the traffic described by the simulation is allowed by the account policies.
+// The answer is computed from the network maps of the involved peers, so it accounts for posture checks,
+// peer approval, login expiration and routing peers the same way as the network map distributed to the peers.
+// To simulate a hypothetical policy set, call it on a copy of the account with the policies replaced.
+func (a *Account) SimulatePolicy(ctx context.Context, simulation PolicySimulation, validatedPeersMap map[string]struct{}) (*PolicySimulationResult, error) {
+	if err := simulation.Validate(); err != nil {
+		return nil, err
+	}
+
+	source := a.GetPeer(simulation.SourcePeerID)
+	if source == nil {
+		return nil, fmt.Errorf("source peer %s not found", simulation.SourcePeerID)
+	}
+
+	if simulation.DestinationPeerID != "" {
+		destination := a.GetPeer(simulation.DestinationPeerID)
+		if destination == nil {
+			return nil, fmt.Errorf("destination peer %s not found", simulation.DestinationPeerID)
+		}
+		return a.simulatePeerTraffic(ctx, simulation, source, destination, validatedPeersMap), nil
+	}
+
+	resourceIdx := slices.IndexFunc(a.NetworkResources, func(r *resourceTypes.NetworkResource) bool {
+		return r.ID == simulation.DestinationResourceID
+	})
+	if resourceIdx < 0 {
+		return nil, fmt.Errorf("destination resource %s not found", simulation.DestinationResourceID)
+	}
+
+	return a.simulateResourceTraffic(ctx, simulation, source, a.NetworkResources[resourceIdx], validatedPeersMap), nil
+}
+
+func (a *Account) simulatePeerTraffic(ctx context.Context, simulation PolicySimulation, source, destination *nbpeer.Peer, validatedPeersMap map[string]struct{}) *PolicySimulationResult {
+	result := &PolicySimulationResult{}
+
+	sourceGroups := a.GetPeerGroups(source.ID)
+	destinationGroups := a.GetPeerGroups(destination.ID)
+	matchingPolicies := make(map[string]*Policy)
+
+	for _, policy := range a.Policies {
+		if !policy.IsActive() {
+			continue
+		}
+		for _, rule := range policy.Rules {
+			if !rule.Enabled || !simulation.matchesRule(rule) {
+				continue
+			}
+
+			forward := ruleSourcesContain(rule, source.ID, sourceGroups) && ruleDestinationsContain(rule, destination.ID, destinationGroups)
+			backward := rule.Bidirectional && ruleSourcesContain(rule, destination.ID, destinationGroups) && ruleDestinationsContain(rule, source.ID, sourceGroups)
+			if !forward && !backward {
+				continue
+			}
+
+			matchingPolicies[policy.ID] = policy
+			result.Rules = append(result.Rules, PolicySimulationRule{
+				PolicyID:   policy.ID,
+				PolicyName: policy.Name,
+				RuleID:     rule.ID,
+				RuleName:   rule.Name,
+				Action:     rule.Action,
+			})
+		}
+	}
+
+	result.PostureChecks = a.evaluateSimulationPostureChecks(ctx, source, matchingPolicies)
+
+	if reason := simulationPeersApproval(source, destination, validatedPeersMap); reason != "" {
+		result.Reason = reason
+		return result
+	}
+
+	if len(result.Rules) == 0 {
+		result.Reason = "no policy rule matches the traffic"
+		return result
+	}
+
+	sourceNetworkMap := a.getSimulationNetworkMap(ctx, source.ID, validatedPeersMap)
+	destinationNetworkMap := a.getSimulationNetworkMap(ctx, destination.ID, validatedPeersMap)
+
+	// the traffic is filtered by the inbound rules of the destination peer
+	sourceIP := source.IP.String()
+	for i, rule := range result.Rules {
+		result.Rules[i].Applied = slices.ContainsFunc(destinationNetworkMap.FirewallRules, func(fr *FirewallRule) bool {
+			return fr.PolicyID == rule.RuleID && fr.Direction == FirewallRuleDirectionIN && fr.PeerIP == sourceIP &&
+				simulation.matchesFirewallRule(fr.Protocol, fr.Port, fr.PortRange)
+		})
+	}
+
+	switch {
+	case slices.ContainsFunc(sourceNetworkMap.OfflinePeers, func(p *nbpeer.Peer) bool { return p.ID == destination.ID }):
+		result.Reason = "destination peer login expired"
+	case !slices.ContainsFunc(sourceNetworkMap.Peers, func(p *nbpeer.Peer) bool { return p.ID == destination.ID }):
+		result.Reason = a.notAppliedReason(result)
+	default:
+		a.applySimulationVerdict(result)
+	}
+
+	return result
+}
+
+func (a *Account) simulateResourceTraffic(ctx context.Context, simulation PolicySimulation, source *nbpeer.Peer, resource *resourceTypes.NetworkResource, validatedPeersMap map[string]struct{}) *PolicySimulationResult {
+	result := &PolicySimulationResult{}
+
+	sourceGroups := a.GetPeerGroups(source.ID)
+	resourceGroups := make(LookupMap)
+	for _, group := range a.getNetworkResourceGroups(resource.ID) {
+		resourceGroups[group.ID] = struct{}{}
+	}
+	matchingPolicies := make(map[string]*Policy)
+	var matchingRules []*PolicyRule
+
+	for _, policy := range a.Policies {
+		if !policy.IsActive() {
+			continue
+		}
+		for _, rule := range policy.Rules {
+			if !rule.Enabled || !simulation.matchesRule(rule) {
+				continue
+			}
+
+			if !ruleSourcesContain(rule, source.ID, sourceGroups) || !ruleDestinationsContain(rule, resource.ID, resourceGroups) {
+				continue
+			}
+
+			matchingPolicies[policy.ID] = policy
+			matchingRules = append(matchingRules, rule)
+			result.Rules = append(result.Rules, PolicySimulationRule{
+				PolicyID:   policy.ID,
+				PolicyName: policy.Name,
+				RuleID:     rule.ID,
+				RuleName:   rule.Name,
+				Action:     rule.Action,
+			})
+		}
+	}
+
+	result.PostureChecks = a.evaluateSimulationPostureChecks(ctx, source, matchingPolicies)
+
+	if _, ok := validatedPeersMap[source.ID]; !ok {
+		result.Reason = "source peer is not approved"
+		return result
+	}
+
+	if !resource.Enabled {
+		result.Reason = "destination resource is disabled"
+		return result
+	}
+
+	if len(result.Rules) == 0 {
+		result.Reason = "no policy rule matches the traffic"
+		return result
+	}
+
+	sourceNetworkMap := a.getSimulationNetworkMap(ctx, source.ID, validatedPeersMap)
+	for _, r := range sourceNetworkMap.Routes {
+		if string(r.GetResourceID()) == resource.ID {
+			result.Routes = append(result.Routes, r)
+		}
+	}
+
+	if len(result.Routes) == 0 {
+		result.Reason = a.notAppliedReason(result)
+		if result.Reason == "" {
+			result.Reason = "no routing peer serves the destination resource"
+		}
+		return result
+	}
+
+	// the traffic is filtered by the route rules of the routing peers
+	sourceRange := fmt.Sprintf(AllowedIPsFormat, source.IP)
+	for _, r := range result.Routes {
+		routerNetworkMap := a.getSimulationNetworkMap(ctx, r.PeerID, validatedPeersMap)
+		for i, rule := range matchingRules {
+			if result.Rules[i].Applied {
+				continue
+			}
+			result.Rules[i].Applied = slices.ContainsFunc(routerNetworkMap.RoutesFirewallRules, func(fr *RouteFirewallRule) bool {
+				var port string
+				if fr.Port != 0 {
+					port = strconv.Itoa(int(fr.Port))
+				}
+				return fr.PolicyID == rule.PolicyID && fr.Action == string(rule.Action) && fr.RouteID == r.ID && slices.Contains(fr.SourceRanges, sourceRange) &&
+					simulation.matchesFirewallRule(fr.Protocol, port, fr.PortRange)
+			})
+		}
+	}
+
+	a.applySimulationVerdict(result)
+
+	return result
+}
+
+func (a *Account) getSimulationNetworkMap(ctx context.Context, peerID string, validatedPeersMap map[string]struct{}) *NetworkMap {
+	return a.GetPeerNetworkMap(ctx, peerID, nbdns.CustomZone{}, nil, validatedPeersMap, a.GetResourcePoliciesMap(), a.GetResourceRoutersMap(), nil, a.GetActiveGroupUsers())
+}
+
+// applySimulationVerdict allows the traffic if an accepting rule is applied, drop rules take precedence
+func (a *Account) applySimulationVerdict(result *PolicySimulationResult) {
+	var accepted bool
+	for _, rule := range result.Rules {
+		if !rule.Applied {
+			continue
+		}
+		if rule.Action == PolicyTrafficActionDrop {
+			result.Reason = fmt.Sprintf("traffic is dropped by rule %q of policy %q", rule.RuleName, rule.PolicyName)
+			return
+		}
+		accepted = true
+	}
+
+	if !accepted {
+		result.Reason = a.notAppliedReason(result)
+		return
+	}
+
+	result.Allowed = true
+}
+
+func (a *Account) notAppliedReason(result *PolicySimulationResult) string {
+	for _, check := range result.PostureChecks {
+		if !check.Passed {
+			return fmt.Sprintf("source peer fails posture checks %q: %s", check.PostureChecksName, check.Reason)
+		}
+	}
+	return "matching policy rules are not applied to the peers"
+}
+
+// evaluateSimulationPostureChecks evaluates the source posture checks of the policies on the peer
+func (a *Account) evaluateSimulationPostureChecks(ctx context.Context, peer *nbpeer.Peer, policies map[string]*Policy) []posture.Result {
+	var results []posture.Result
+	evaluated := make(map[string]struct{})
+
+	for _, policy := range a.Policies {
+		if _, ok := policies[policy.ID]; !ok {
+			continue
+		}
+		for _, postureChecksID := range policy.SourcePostureChecks {
+			if _, ok := evaluated[postureChecksID]; ok {
+				continue
+			}
+			evaluated[postureChecksID] = struct{}{}
+
+			postureChecks := a.GetPostureChecks(postureChecksID)
+			if postureChecks == nil {
+				continue
+			}
+			results = append(results, postureChecks.Evaluate(ctx, *peer)...)
+		}
+	}
+
+	return results
+}
+
+func simulationPeersApproval(source, destination *nbpeer.Peer, validatedPeersMap map[string]struct{}) string {
+	if _, ok := validatedPeersMap[source.ID]; !ok {
+		return "source peer is not approved"
+	}
+	if _, ok := validatedPeersMap[destination.ID]; !ok {
+		return "destination peer is not approved"
+	}
+	return ""
+}
+
+func ruleSourcesContain(rule *PolicyRule, peerID string, peerGroups LookupMap) bool {
+	if rule.SourceResource.ID != "" {
+		return rule.SourceResource.ID == peerID
+	}
+	return slices.ContainsFunc(rule.Sources, func(groupID string) bool {
+		_, ok := peerGroups[groupID]
+		return ok
+	})
+}
+
+func ruleDestinationsContain(rule *PolicyRule, id string, groups LookupMap) bool {
+	if rule.DestinationResource.ID != "" {
+		return rule.DestinationResource.ID == id
+	}
+	return slices.ContainsFunc(rule.Destinations, func(groupID string) bool {
+		_, ok := groups[groupID]
+		return ok
+	})
+}
+
+// matchesRule checks whether the protocol and the port of the policy rule cover the simulated traffic
+func (s *PolicySimulation) matchesRule(rule *PolicyRule) bool {
+	protocol := rule.Protocol
+	ports := rule.Ports
+	if protocol == PolicyRuleProtocolNetbirdSSH {
+		protocol = PolicyRuleProtocolTCP
+		if len(ports) == 0 && len(rule.PortRanges) == 0 {
+			ports = []string{nativeSSHPortString}
+		}
+	}
+
+	if !s.matchesProtocol(string(protocol)) {
+		return false
+	}
+
+	if s.Port == 0 || (len(ports) == 0 && len(rule.PortRanges) == 0) {
+		return true
+	}
+
+	if slices.Contains(ports, strconv.Itoa(int(s.Port))) {
+		return true
+	}
+
+	return slices.ContainsFunc(rule.PortRanges, func(r RulePortRange) bool {
+		return r.Start <= s.Port && s.Port <= r.End
+	})
+}
+
+// matchesFirewallRule checks whether a firewall rule of a network map covers the simulated traffic
+func (s *PolicySimulation) matchesFirewallRule(protocol, port string, portRange RulePortRange) bool {
+	if !s.matchesProtocol(protocol) {
+		return false
+	}
+
+	if s.Port == 0 || (port == "" && portRange.Start == 0 && portRange.End == 0) {
+		return true
+	}
+
+	if port != "" {
+		return port == strconv.Itoa(int(s.Port))
+	}
+
+	return portRange.Start <= s.Port && s.Port <= portRange.End
+}
+
+func (s *PolicySimulation) matchesProtocol(protocol string) bool {
+	return s.Protocol == PolicyRuleProtocolALL || protocol == string(PolicyRuleProtocolALL) || protocol == string(s.Protocol)
+}
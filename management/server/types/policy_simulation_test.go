@@ -0,0 +1,197 @@
+package types
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func getPolicySimulationAccount() *Account {
+	account := getBasicAccountsWithResource()
+	account.Network = &Network{}
+	account.Settings = &Settings{}
+	account.Policies = append(account.Policies, &Policy{
+		ID:        "policy2ID",
+		Name:      "ssh",
+		AccountID: accID,
+		Enabled:   true,
+		Rules: []*PolicyRule{
+			{
+				ID:           "rule2ID",
+				Name:         "ssh",
+				Enabled:      true,
+				Sources:      []string{group1ID},
+				Destinations: []string{group1ID},
+				Protocol:     PolicyRuleProtocolTCP,
+				Ports:        []string{"22"},
+				Action:       PolicyTrafficActionAccept,
+			},
+		},
+	})
+	return account
+}
+
+func TestAccount_SimulatePolicy(t *testing.T) {
+	tests := []struct {
+		name            string
+		simulation      PolicySimulation
+		setup           func(account *Account)
+		notApproved     string
+		expectedAllowed bool
+		expectedReason  string
+		expectedRules   []string
+		expectedApplied bool
+		expectedRoutes  int
+	}{
+		{
+			name:            "peer to peer allowed",
+			simulation:      PolicySimulation{SourcePeerID: accNetResourcePeer1ID, DestinationPeerID: accNetResourcePeer2ID, Protocol: PolicyRuleProtocolTCP, Port: 22},
+			expectedAllowed: true,
+			expectedRules:   []string{"rule2ID"},
+			expectedApplied: true,
+		},
+		{
+			name:           "peer to peer port mismatch",
+			simulation:     PolicySimulation{SourcePeerID: accNetResourcePeer1ID, DestinationPeerID: accNetResourcePeer2ID, Protocol: PolicyRuleProtocolTCP, Port: 443},
+			expectedReason: "no policy rule matches the traffic",
+		},
+		{
+			name:           "peer to peer protocol mismatch",
+			simulation:     PolicySimulation{SourcePeerID: accNetResourcePeer1ID, DestinationPeerID: accNetResourcePeer2ID, Protocol: PolicyRuleProtocolUDP, Port: 22},
+			expectedReason: "no policy rule matches the traffic",
+		},
+		{
+			name:       "peer to peer dropped",
+			simulation: PolicySimulation{SourcePeerID: accNetResourcePeer1ID, DestinationPeerID: accNetResourcePeer2ID, Protocol: PolicyRuleProtocolTCP, Port: 22},
+			setup: func(account *Account) {
+				account.Policies = append(account.Policies, &Policy{
+					ID:      "policy3ID",
+					Name:    "deny",
+					Enabled: true,
+					Rules: []*PolicyRule{
+						{
+							ID:           "rule3ID",
+							Name:         "deny all",
+							Enabled:      true,
+							Sources:      []string{group1ID},
+							Destinations: []string{group1ID},
+							Protocol:     PolicyRuleProtocolALL,
+							Action:       PolicyTrafficActionDrop,
+						},
+					},
+				})
+			},
+			expectedReason:  `traffic is dropped by rule "deny all" of policy "deny"`,
+			expectedRules:   []string{"rule2ID", "rule3ID"},
+			expectedApplied: true,
+		},
+		{
+			name:       "peer to peer failing posture checks",
+			simulation: PolicySimulation{SourcePeerID: accNetResourcePeer1ID, DestinationPeerID: accNetResourcePeer2ID, Protocol: PolicyRuleProtocolTCP, Port: 22},
+			setup: func(account *Account) {
+				account.Policies[1].SourcePostureChecks = []string{accNetResourceLockedPostureCheckID}
+			},
+			expectedReason: `source peer fails posture checks "lockedPostureCheck": NetBird version 0.35.1 < 7.7.7`,
+			expectedRules:  []string{"rule2ID"},
+		},
+		{
+			name:           "source peer not approved",
+			simulation:     PolicySimulation{SourcePeerID: accNetResourceRouter1ID, DestinationPeerID: accNetResourcePeer2ID, Protocol: PolicyRuleProtocolALL},
+			notApproved:    accNetResourceRouter1ID,
+			expectedReason: "source peer is not approved",
+		},
+		{
+			name:            "peer to resource allowed",
+			simulation:      PolicySimulation{SourcePeerID: accNetResourcePeer2ID, DestinationResourceID: accNetResource1ID, Protocol: PolicyRuleProtocolTCP, Port: 80},
+			expectedAllowed: true,
+			expectedRules:   []string{"rule1ID"},
+			expectedApplied: true,
+			expectedRoutes:  1,
+		},
+		{
+			name:           "peer to resource port mismatch",
+			simulation:     PolicySimulation{SourcePeerID: accNetResourcePeer2ID, DestinationResourceID: accNetResource1ID, Protocol: PolicyRuleProtocolTCP, Port: 8080},
+			expectedReason: "no policy rule matches the traffic",
+		},
+		{
+			name:       "peer to resource failing posture checks",
+			simulation: PolicySimulation{SourcePeerID: accNetResourcePeer2ID, DestinationResourceID: accNetResource1ID, Protocol: PolicyRuleProtocolTCP, Port: 80},
+			setup: func(account *Account) {
+				account.Policies[0].SourcePostureChecks = []string{accNetResourceRestrictPostureCheckID}
+			},
+			expectedReason: `source peer fails posture checks "restrictPostureCheck": NetBird version 0.34.1 < 0.35.0`,
+			expectedRules:  []string{"rule1ID"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			account := getPolicySimulationAccount()
+			validatedPeers := map[string]struct{}{accNetResourcePeer1ID: {}, accNetResourcePeer2ID: {}, accNetResourceRouter1ID: {}}
+			delete(validatedPeers, tt.notApproved)
+			if tt.setup != nil {
+				tt.setup(account)
+			}
+
+			result, err := account.SimulatePolicy(context.Background(), tt.simulation, validatedPeers)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedAllowed, result.Allowed)
+			assert.Equal(t, tt.expectedReason, result.Reason)
+			assert.Len(t, result.Routes, tt.expectedRoutes)
+
+			var rules []string
+			for _, rule := range result.Rules {
+				rules = append(rules, rule.RuleID)
+				assert.Equal(t, tt.expectedApplied, rule.Applied, "rule %s applied", rule.RuleID)
+			}
+			assert.Equal(t, tt.expectedRules, rules)
+		})
+	}
+}
+
+func TestPolicySimulation_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		simulation PolicySimulation
+		expectErr  bool
+	}{
+		{
+			name:       "valid peer destination",
+			simulation: PolicySimulation{SourcePeerID: "a", DestinationPeerID: "b", Protocol: PolicyRuleProtocolTCP, Port: 22},
+		},
+		{
+			name:       "missing source",
+			simulation: PolicySimulation{DestinationPeerID: "b", Protocol: PolicyRuleProtocolALL},
+			expectErr:  true,
+		},
+		{
+			name:       "both destinations",
+			simulation: PolicySimulation{SourcePeerID: "a", DestinationPeerID: "b", DestinationResourceID: "c", Protocol: PolicyRuleProtocolALL},
+			expectErr:  true,
+		},
+		{
+			name:       "port with icmp",
+			simulation: PolicySimulation{SourcePeerID: "a", DestinationPeerID: "b", Protocol: PolicyRuleProtocolICMP, Port: 22},
+			expectErr:  true,
+		},
+		{
+			name:       "unsupported protocol",
+			simulation: PolicySimulation{SourcePeerID: "a", DestinationPeerID: "b", Protocol: PolicyRuleProtocolNetbirdSSH},
+			expectErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.simulation.Validate()
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package types
+
+import (
+	"fmt"
+	"net/netip"
+
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/route"
+	"github.com/netbirdio/netbird/shared/management/domain"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+// ValidateRouteConflicts checks that the routing peers of the route don't already route its prefix or domains as a
+// single peer or as a member of a peer group. The routes are the routes of the account, the groups and peers have to
+// contain the peer groups of the routes and the routing peers of the route.
+func ValidateRouteConflicts(checkRoute *route.Route, routes []*route.Route, groups map[string]*Group, peers map[string]*nbpeer.Peer) error {
+	// routes can have both peer and peer_groups
+	prefix := checkRoute.Network
+	domains := checkRoute.Domains
+
+	// lets remember all the peers and the peer groups from routesWithPrefix
+	seenPeers := make(map[string]bool)
+	seenPeerGroups := make(map[string]bool)
+
+	for _, prefixRoute := range RoutesByPrefixOrDomains(routes, prefix, domains) {
+		// we skip route(s) with the same network ID as we want to allow updating of the existing route
+		// when creating a new route routeID is newly generated so nothing will be skipped
+		if checkRoute.ID == prefixRoute.ID {
+			continue
+		}
+
+		if prefixRoute.Peer != "" {
+			seenPeers[string(prefixRoute.ID)] = true
+		}
+
+		for _, groupID := range prefixRoute.PeerGroups {
+			seenPeerGroups[groupID] = true
+
+			group, ok := groups[groupID]
+			if !ok || group == nil {
+				return status.Errorf(
+					status.InvalidArgument, "failed to add route with %s - peer group %s doesn't exist",
+					routeDescriptor(prefix, domains), groupID,
+				)
+			}
+
+			for _, pID := range group.Peers {
+				seenPeers[pID] = true
+			}
+		}
+	}
+
+	if peerID := checkRoute.Peer; peerID != "" {
+		// check that peerID exists and is not in any route as single peer or part of the group
+		if _, ok := peers[peerID]; !ok {
+			return status.Errorf(status.InvalidArgument, "peer with ID %s not found", peerID)
+		}
+
+		if _, ok := seenPeers[peerID]; ok {
+			return status.Errorf(status.AlreadyExists,
+				"failed to add route with %s - peer %s already has this route", routeDescriptor(prefix, domains), peerID)
+		}
+	}
+
+	// check that peerGroupIDs are not in any route peerGroups list
+	for _, groupID := range checkRoute.PeerGroups {
+		group, ok := groups[groupID]
+		if !ok || group == nil {
+			return status.Errorf(status.InvalidArgument, "group id %s not found", groupID)
+		}
+
+		if _, ok := seenPeerGroups[groupID]; ok {
+			return status.Errorf(
+				status.AlreadyExists, "failed to add route with %s - peer group %s already has this route",
+				routeDescriptor(prefix, domains), group.Name)
+		}
+
+		// check that the peers from peerGroupIDs groups are not the same peers we saw in routesWithPrefix
+		for _, id := range group.Peers {
+			if _, ok := seenPeers[id]; ok {
+				peer, ok := peers[id]
+				if !ok || peer == nil {
+					return status.Errorf(status.InvalidArgument, "peer with ID %s not found", id)
+				}
+
+				return status.Errorf(status.AlreadyExists,
+					"failed to add route with %s - peer %s from the group %s already has this route",
+					routeDescriptor(prefix, domains), peer.Name, group.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// RoutesByPrefixOrDomains returns the routes to the prefix, or to the domains for dynamic routes
+func RoutesByPrefixOrDomains(routes []*route.Route, prefix netip.Prefix, domains domain.List) []*route.Route {
+	matching := make([]*route.Route, 0)
+	for _, r := range routes {
+		dynamic := r.IsDynamic()
+		if dynamic && r.Domains.PunycodeString() == domains.PunycodeString() ||
+			!dynamic && r.Network.String() == prefix.String() {
+			matching = append(matching, r)
+		}
+	}
+
+	return matching
+}
+
+func routeDescriptor(prefix netip.Prefix, domains domain.List) string {
+	if len(domains) > 0 {
+		return fmt.Sprintf("domains [%s]", domains.SafeString())
+	}
+	return fmt.Sprintf("prefix %s", prefix.String())
+}
@@ -3,6 +3,12 @@ package types
 import (
 	"crypto/sha256"
 	b64 "encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"regexp"
+	"slices"
 	"strings"
 	"time"
 	"unicode/utf8"
@@ -56,6 +62,111 @@ type SetupKey struct {
 	Ephemeral bool
 	// AllowExtraDNSLabels indicates if the key allows extra DNS labels
 	AllowExtraDNSLabels bool
+	// Constraints are optional conditions the peer registrations with this key have to meet
+	Constraints SetupKeyConstraints `gorm:"embedded;embeddedPrefix:constraint_"`
+}
+
+// SetupKeyConstraints are optional conditions a peer registering with a setup key has to meet.
+// They limit the damage of a leaked reusable key.
+type SetupKeyConstraints struct {
+	// AllowedSourceRanges restrict the registrations to peers connecting from the networks.
+	// The connection IP is taken from the forwarding headers of the trusted peers, with the default
+	// ReverseProxy.TrustedPeers of 0.0.0.0/0 and ::/0 any client can spoof it.
+	AllowedSourceRanges []netip.Prefix `gorm:"serializer:json"`
+	// HostnamePattern is a regular expression the whole hostname of the peer has to match
+	HostnamePattern string
+	// RequiredOS is the operating system the peer has to run, matched against the GOOS of the peer
+	RequiredOS string
+	// NotBefore is the time before which the key can't be used
+	NotBefore *time.Time
+	// NotAfter is the time after which the key can't be used
+	NotAfter *time.Time
+}
+
+// Copy copies the constraints to a new object
+func (c SetupKeyConstraints) Copy() SetupKeyConstraints {
+	c.AllowedSourceRanges = slices.Clone(c.AllowedSourceRanges)
+	if c.NotBefore != nil {
+		c.NotBefore = util.ToPtr(*c.NotBefore)
+	}
+	if c.NotAfter != nil {
+		c.NotAfter = util.ToPtr(*c.NotAfter)
+	}
+	return c
+}
+
+// IsEmpty returns true if no constraint is set
+func (c SetupKeyConstraints) IsEmpty() bool {
+	return len(c.AllowedSourceRanges) == 0 && c.HostnamePattern == "" && c.RequiredOS == "" &&
+		c.NotBefore == nil && c.NotAfter == nil
+}
+
+// Validate checks that the constraints can be evaluated
+func (c SetupKeyConstraints) Validate() error {
+	for _, prefix := range c.AllowedSourceRanges {
+		if !prefix.IsValid() {
+			return errors.New("invalid source range")
+		}
+	}
+
+	if c.HostnamePattern != "" {
+		if _, err := c.hostnameRegexp(); err != nil {
+			return fmt.Errorf("invalid hostname pattern: %w", err)
+		}
+	}
+
+	if c.NotBefore != nil && c.NotAfter != nil && !c.NotBefore.Before(*c.NotAfter) {
+		return errors.New("not before has to be earlier than not after")
+	}
+
+	return nil
+}
+
+// Check returns an error with the reason if a peer registration doesn't meet the constraints
+func (c SetupKeyConstraints) Check(connectionIP net.IP, hostname, goOS string, now time.Time) error {
+	if c.NotBefore != nil && now.Before(*c.NotBefore) {
+		return fmt.Errorf("setup key can't be used before %s", c.NotBefore.UTC().Format(time.RFC3339))
+	}
+
+	if c.NotAfter != nil && now.After(*c.NotAfter) {
+		return fmt.Errorf("setup key can't be used after %s", c.NotAfter.UTC().Format(time.RFC3339))
+	}
+
+	if len(c.AllowedSourceRanges) > 0 {
+		addr, ok := netip.AddrFromSlice(connectionIP)
+		if !ok {
+			return errors.New("connection IP of the peer is unknown")
+		}
+		addr = addr.Unmap()
+
+		allowed := slices.ContainsFunc(c.AllowedSourceRanges, func(prefix netip.Prefix) bool {
+			return prefix.Contains(addr)
+		})
+		if !allowed {
+			return fmt.Errorf("connection IP %s is not in the allowed source ranges", addr)
+		}
+	}
+
+	if c.HostnamePattern != "" {
+		re, err := c.hostnameRegexp()
+		if err != nil {
+			return fmt.Errorf("invalid hostname pattern: %w", err)
+		}
+		if !re.MatchString(hostname) {
+			return fmt.Errorf("hostname %s doesn't match the pattern %s", hostname, c.HostnamePattern)
+		}
+	}
+
+	if c.RequiredOS != "" && !strings.EqualFold(c.RequiredOS, goOS) {
+		return fmt.Errorf("operating system %s is not %s", goOS, c.RequiredOS)
+	}
+
+	return nil
+}
+
+// hostnameRegexp compiles the hostname pattern anchored to the whole hostname
+func (c SetupKeyConstraints) hostnameRegexp() (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + c.HostnamePattern + ")$")
 }
 
 // Copy copies SetupKey to a new object
@@ -82,6 +193,7 @@ func (key *SetupKey) Copy() *SetupKey {
 		UsageLimit:          key.UsageLimit,
 		Ephemeral:           key.Ephemeral,
 		AllowExtraDNSLabels: key.AllowExtraDNSLabels,
+		Constraints:         key.Constraints.Copy(),
 	}
 }
 
@@ -0,0 +1,52 @@
+package types
+
+import (
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetupKeyConstraints_Check(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	notBefore := now.Add(-time.Hour)
+	notAfter := now.Add(time.Hour)
+
+	constraints := SetupKeyConstraints{
+		AllowedSourceRanges: []netip.Prefix{netip.MustParsePrefix("203.0.113.0/24")},
+		HostnamePattern:     "^build-[a-z]+$",
+		RequiredOS:          "linux",
+		NotBefore:           &notBefore,
+		NotAfter:            &notAfter,
+	}
+
+	assert.NoError(t, SetupKeyConstraints{}.Check(nil, "any", "windows", now), "empty constraints allow everything")
+	assert.NoError(t, constraints.Check(net.ParseIP("203.0.113.5"), "build-arm", "Linux", now))
+	assert.NoError(t, constraints.Check(net.ParseIP("::ffff:203.0.113.5"), "build-arm", "linux", now))
+
+	assert.Error(t, constraints.Check(nil, "build-arm", "linux", now), "unknown connection IP")
+	assert.Error(t, constraints.Check(net.ParseIP("198.51.100.1"), "build-arm", "linux", now))
+	assert.Error(t, constraints.Check(net.ParseIP("203.0.113.5"), "laptop", "linux", now))
+
+	unanchored := SetupKeyConstraints{HostnamePattern: "ci-[0-9]+|build"}
+	assert.NoError(t, unanchored.Check(nil, "ci-42", "linux", now))
+	assert.NoError(t, unanchored.Check(nil, "build", "linux", now))
+	assert.Error(t, unanchored.Check(nil, "evil-ci-42", "linux", now), "the pattern has to match the whole hostname")
+	assert.Error(t, unanchored.Check(nil, "build-evil", "linux", now), "the pattern has to match the whole hostname")
+	assert.Error(t, constraints.Check(net.ParseIP("203.0.113.5"), "build-arm", "darwin", now))
+	assert.Error(t, constraints.Check(net.ParseIP("203.0.113.5"), "build-arm", "linux", notBefore.Add(-time.Second)))
+	assert.Error(t, constraints.Check(net.ParseIP("203.0.113.5"), "build-arm", "linux", notAfter.Add(time.Second)))
+}
+
+func TestSetupKeyConstraints_Validate(t *testing.T) {
+	now := time.Now().UTC()
+	later := now.Add(time.Hour)
+
+	assert.NoError(t, SetupKeyConstraints{}.Validate())
+	assert.NoError(t, SetupKeyConstraints{HostnamePattern: "^ci-", NotBefore: &now, NotAfter: &later}.Validate())
+	assert.Error(t, SetupKeyConstraints{HostnamePattern: "["}.Validate())
+	assert.Error(t, SetupKeyConstraints{NotBefore: &later, NotAfter: &now}.Validate())
+	assert.Error(t, SetupKeyConstraints{AllowedSourceRanges: []netip.Prefix{{}}}.Validate())
+}
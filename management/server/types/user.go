@@ -25,6 +25,10 @@ const (
 
 	UserIssuedAPI         = "api"
 	UserIssuedIntegration = "integration"
+	UserIssuedSCIM        = "scim"
+
+	// CustomUserRolePrefix is the prefix of a UserRole that references an account custom role by its ID
+	CustomUserRolePrefix = "custom:"
 )
 
 // StrRoleToUserRole returns UserRole for a given strRole or UserRoleUnknown if the specified role is unknown
@@ -43,16 +47,37 @@ func StrRoleToUserRole(strRole string) UserRole {
 	case "network_admin":
 		return UserRoleNetworkAdmin
 	default:
+		if strings.HasPrefix(strRole, CustomUserRolePrefix) && len(strRole) > len(CustomUserRolePrefix) {
+			return UserRole(strRole)
+		}
 		return UserRoleUnknown
 	}
 }
 
+// NewCustomUserRole returns the UserRole that references the custom role with the given ID
+func NewCustomUserRole(roleID string) UserRole {
+	return UserRole(CustomUserRolePrefix + roleID)
+}
+
 // UserStatus is the status of a User
 type UserStatus string
 
 // UserRole is the role of a User
 type UserRole string
 
+// IsCustom returns true if the role references an account custom role
+func (r UserRole) IsCustom() bool {
+	return strings.HasPrefix(string(r), CustomUserRolePrefix)
+}
+
+// CustomRoleID returns the ID of the referenced custom role or an empty string for built-in roles
+func (r UserRole) CustomRoleID() string {
+	if !r.IsCustom() {
+		return ""
+	}
+	return strings.TrimPrefix(string(r), CustomUserRolePrefix)
+}
+
 type UserInfo struct {
 	ID                   string                                     `json:"id"`
 	Email                string                                     `json:"email"`
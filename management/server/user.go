@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/netip"
 	"strings"
 	"time"
 
@@ -17,6 +18,7 @@ import (
 	"github.com/netbirdio/netbird/management/server/activity"
 	"github.com/netbirdio/netbird/management/server/idp"
 	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/permissions"
 	"github.com/netbirdio/netbird/management/server/permissions/modules"
 	"github.com/netbirdio/netbird/management/server/permissions/operations"
 	"github.com/netbirdio/netbird/management/server/store"
@@ -40,6 +42,20 @@ func (am *DefaultAccountManager) createServiceUser(ctx context.Context, accountI
 		return nil, status.NewServiceUserRoleInvalidError()
 	}
 
+	if err = validateCustomUserRole(ctx, am.Store, accountID, role); err != nil {
+		return nil, err
+	}
+
+	if initiatorUserID != activity.SystemInitiator {
+		initiatorUser, err := am.Store.GetUserByUserID(ctx, store.LockingStrengthNone, initiatorUserID)
+		if err != nil {
+			return nil, err
+		}
+		if err = validateRoleAssignment(ctx, am.Store, accountID, initiatorUser, role); err != nil {
+			return nil, err
+		}
+	}
+
 	newUserID := uuid.New().String()
 	newUser := types.NewUser(newUserID, role, true, nonDeletable, serviceUserName, autoGroups, types.UserIssuedAPI, "", "")
 	newUser.AccountID = accountID
@@ -91,11 +107,19 @@ func (am *DefaultAccountManager) inviteNewUser(ctx context.Context, accountID, u
 		return nil, status.NewPermissionDeniedError()
 	}
 
+	if err = validateCustomUserRole(ctx, am.Store, accountID, types.StrRoleToUserRole(invite.Role)); err != nil {
+		return nil, err
+	}
+
 	initiatorUser, err := am.Store.GetUserByUserID(ctx, store.LockingStrengthNone, userID)
 	if err != nil {
 		return nil, err
 	}
 
+	if err = validateRoleAssignment(ctx, am.Store, accountID, initiatorUser, types.StrRoleToUserRole(invite.Role)); err != nil {
+		return nil, err
+	}
+
 	inviterID := userID
 	if initiatorUser.IsServiceUser {
 		createdBy, err := am.Store.GetAccountCreatedBy(ctx, store.LockingStrengthNone, accountID)
@@ -354,7 +378,7 @@ func (am *DefaultAccountManager) InviteUser(ctx context.Context, accountID strin
 }
 
 // CreatePAT creates a new PAT for the given user
-func (am *DefaultAccountManager) CreatePAT(ctx context.Context, accountID string, initiatorUserID string, targetUserID string, tokenName string, expiresIn int) (*types.PersonalAccessTokenGenerated, error) {
+func (am *DefaultAccountManager) CreatePAT(ctx context.Context, accountID string, initiatorUserID string, targetUserID string, tokenName string, expiresIn int, scopes []types.PATScope, allowedSourceRanges []netip.Prefix) (*types.PersonalAccessTokenGenerated, error) {
 	if tokenName == "" {
 		return nil, status.Errorf(status.InvalidArgument, "token name can't be empty")
 	}
@@ -363,6 +387,19 @@ func (am *DefaultAccountManager) CreatePAT(ctx context.Context, accountID string
 		return nil, status.Errorf(status.InvalidArgument, "expiration has to be between 1 and 365")
 	}
 
+	for _, scope := range scopes {
+		if err := scope.Validate(); err != nil {
+			return nil, status.Errorf(status.InvalidArgument, "invalid token scope: %v", err)
+		}
+	}
+
+	for i, prefix := range allowedSourceRanges {
+		if !prefix.IsValid() {
+			return nil, status.Errorf(status.InvalidArgument, "invalid token source range")
+		}
+		allowedSourceRanges[i] = prefix.Masked()
+	}
+
 	allowed, err := am.permissionsManager.ValidateUserPermissions(ctx, accountID, initiatorUserID, modules.Pats, operations.Create)
 	if err != nil {
 		return nil, status.NewPermissionValidationError(err)
@@ -371,6 +408,10 @@ func (am *DefaultAccountManager) CreatePAT(ctx context.Context, accountID string
 		return nil, status.NewPermissionDeniedError()
 	}
 
+	if err = am.validateTokenCovers(ctx, initiatorUserID, scopes, allowedSourceRanges); err != nil {
+		return nil, err
+	}
+
 	initiatorUser, err := am.Store.GetUserByUserID(ctx, store.LockingStrengthNone, initiatorUserID)
 	if err != nil {
 		return nil, err
@@ -386,7 +427,7 @@ func (am *DefaultAccountManager) CreatePAT(ctx context.Context, accountID string
 		return nil, status.NewAdminPermissionError()
 	}
 
-	pat, err := types.CreateNewPAT(tokenName, expiresIn, targetUserID, initiatorUser.Id)
+	pat, err := types.CreateNewPAT(tokenName, expiresIn, targetUserID, initiatorUser.Id, scopes, allowedSourceRanges)
 	if err != nil {
 		return nil, status.Errorf(status.Internal, "failed to create PAT: %v", err)
 	}
@@ -396,11 +437,41 @@ func (am *DefaultAccountManager) CreatePAT(ctx context.Context, accountID string
 	}
 
 	meta := map[string]any{"name": pat.Name, "is_service_user": targetUser.IsServiceUser, "user_name": targetUser.ServiceUserName}
+	if pat.IsScoped() {
+		meta["scopes"] = pat.Scopes
+	}
+	if len(pat.AllowedSourceRanges) > 0 {
+		meta["allowed_source_ranges"] = pat.AllowedSourceRanges
+	}
 	am.StoreEvent(ctx, initiatorUserID, targetUserID, accountID, activity.PersonalAccessTokenCreated, meta)
 
 	return pat, nil
 }
 
+// validateTokenCovers prevents an initiator authenticated with a personal access token from creating
+// a token with scopes or source ranges beyond the ones of the token it has authenticated with
+func (am *DefaultAccountManager) validateTokenCovers(ctx context.Context, initiatorUserID string, scopes []types.PATScope, allowedSourceRanges []netip.Prefix) error {
+	userAuth, err := nbcontext.GetUserAuthFromContext(ctx)
+	if err != nil || !userAuth.IsPAT || userAuth.UserId != initiatorUserID {
+		return nil
+	}
+
+	if userAuth.PATID == "" {
+		return status.NewPermissionDeniedError()
+	}
+
+	callerPAT, err := am.Store.GetPATByID(ctx, store.LockingStrengthNone, initiatorUserID, userAuth.PATID)
+	if err != nil {
+		return err
+	}
+
+	if !callerPAT.Covers(scopes, allowedSourceRanges) {
+		return status.Errorf(status.PermissionDenied, "token scopes and source ranges must be within the ones of the token used for the request")
+	}
+
+	return nil
+}
+
 // DeletePAT deletes a specific PAT from a user
 func (am *DefaultAccountManager) DeletePAT(ctx context.Context, accountID string, initiatorUserID string, targetUserID string, tokenID string) error {
 	allowed, err := am.permissionsManager.ValidateUserPermissions(ctx, accountID, initiatorUserID, modules.Pats, operations.Delete)
@@ -710,6 +781,15 @@ func (am *DefaultAccountManager) processUserUpdate(ctx context.Context, transact
 		return false, nil, nil, nil, err
 	}
 
+	if isNewUser || oldUser.Role != update.Role {
+		if err := validateCustomUserRole(ctx, transaction, accountID, update.Role); err != nil {
+			return false, nil, nil, nil, err
+		}
+		if err := validateRoleAssignment(ctx, transaction, accountID, initiatorUser, update.Role); err != nil {
+			return false, nil, nil, nil, err
+		}
+	}
+
 	// only auto groups, revoked status, and integration reference can be updated for now
 	updatedUser := oldUser.Copy()
 	updatedUser.Role = update.Role
@@ -816,21 +896,29 @@ func validateUserUpdate(groupsMap map[string]*types.Group, initiatorUser, oldUse
 	}
 
 	// @todo double check these
-	if initiatorUser.HasAdminPower() && initiatorUser.Id == update.Id && oldUser.Blocked != update.Blocked {
-		return status.Errorf(status.PermissionDenied, "admins can't block or unblock themselves")
+	if initiatorUser.Id == update.Id && oldUser.Blocked != update.Blocked {
+		return status.Errorf(status.PermissionDenied, "users can't block or unblock themselves")
 	}
-	if initiatorUser.HasAdminPower() && initiatorUser.Id == update.Id && update.Role != initiatorUser.Role {
-		return status.Errorf(status.PermissionDenied, "admins can't change their role")
+	if initiatorUser.Id == update.Id && update.Role != initiatorUser.Role {
+		return status.Errorf(status.PermissionDenied, "users can't change their role")
 	}
-	if initiatorUser.Role == types.UserRoleAdmin && oldUser.Role == types.UserRoleOwner && update.Role != oldUser.Role {
+	if initiatorUser.Role != types.UserRoleOwner && oldUser.Role == types.UserRoleOwner && update.Role != oldUser.Role {
 		return status.Errorf(status.PermissionDenied, "only owners can remove owner role from their user")
 	}
-	if initiatorUser.Role == types.UserRoleAdmin && oldUser.Role == types.UserRoleOwner && update.IsBlocked() && !oldUser.IsBlocked() {
+	if initiatorUser.Role != types.UserRoleOwner && oldUser.Role == types.UserRoleOwner && update.IsBlocked() && !oldUser.IsBlocked() {
 		return status.Errorf(status.PermissionDenied, "unable to block owner user")
 	}
-	if initiatorUser.Role == types.UserRoleAdmin && update.Role == types.UserRoleOwner && update.Role != oldUser.Role {
+	if initiatorUser.Role != types.UserRoleOwner && update.Role == types.UserRoleOwner && update.Role != oldUser.Role {
 		return status.Errorf(status.PermissionDenied, "only owners can add owner role to other users")
 	}
+	// custom roles and the restricted built-in roles can be granted users:update, which must not be enough to
+	// hand out or take away administrative privileges
+	if !initiatorUser.HasAdminPower() && (update.Role == types.UserRoleAdmin || oldUser.Role == types.UserRoleAdmin) && update.Role != oldUser.Role {
+		return status.Errorf(status.PermissionDenied, "only owners and admins can change the admin role")
+	}
+	if !initiatorUser.HasAdminPower() && oldUser.HasAdminPower() && update.IsBlocked() && !oldUser.IsBlocked() {
+		return status.Errorf(status.PermissionDenied, "only owners and admins can block admin users")
+	}
 	if oldUser.IsServiceUser && update.Role == types.UserRoleOwner {
 		return status.Errorf(status.PermissionDenied, "can't update a service user with owner role")
 	}
@@ -849,6 +937,48 @@ func validateUserUpdate(groupsMap map[string]*types.Group, initiatorUser, oldUse
 	return nil
 }
 
+// validateCustomUserRole ensures that the custom role referenced by the given user role exists in the account.
+func validateCustomUserRole(ctx context.Context, transaction store.Store, accountID string, role types.UserRole) error {
+	if !role.IsCustom() {
+		return nil
+	}
+
+	_, err := transaction.GetCustomRoleByID(ctx, store.LockingStrengthNone, accountID, role.CustomRoleID())
+	if err != nil {
+		if sErr, ok := status.FromError(err); ok && sErr.Type() == status.NotFound {
+			return status.Errorf(status.InvalidArgument, "custom role %s doesn't exist", role.CustomRoleID())
+		}
+		return err
+	}
+
+	return nil
+}
+
+// validateRoleAssignment ensures that the initiator holds every permission granted by the assigned role, so that
+// users allowed to create or update users can't hand out more permissions than they have. Roles assigned by the
+// system have no initiator user and are not restricted.
+func validateRoleAssignment(ctx context.Context, transaction store.Store, accountID string, initiatorUser *types.User, role types.UserRole) error {
+	if initiatorUser == nil {
+		return nil
+	}
+
+	initiatorPermissions, err := permissions.GetRolePermissions(ctx, transaction, accountID, initiatorUser.Role)
+	if err != nil {
+		return err
+	}
+
+	rolePermissions, err := permissions.GetRolePermissions(ctx, transaction, accountID, role)
+	if err != nil {
+		return err
+	}
+
+	if !initiatorPermissions.Covers(rolePermissions) {
+		return status.Errorf(status.PermissionDenied, "role %s grants permissions the initiator doesn't have", role)
+	}
+
+	return nil
+}
+
 // GetOrCreateAccountByUser returns an existing account for a given user id or creates a new one if doesn't exist
 func (am *DefaultAccountManager) GetOrCreateAccountByUser(ctx context.Context, userAuth auth.UserAuth) (*types.Account, error) {
 	userID := userAuth.UserId
@@ -1119,9 +1249,12 @@ func (am *DefaultAccountManager) DeleteRegularUsers(ctx context.Context, account
 		return status.NewPermissionDeniedError()
 	}
 
-	initiatorUser, err := am.Store.GetUserByUserID(ctx, store.LockingStrengthNone, initiatorUserID)
-	if err != nil {
-		return err
+	var initiatorUser *types.User
+	if initiatorUserID != activity.SystemInitiator {
+		initiatorUser, err = am.Store.GetUserByUserID(ctx, store.LockingStrengthNone, initiatorUserID)
+		if err != nil {
+			return err
+		}
 	}
 
 	var allErrors error
@@ -1144,7 +1277,7 @@ func (am *DefaultAccountManager) DeleteRegularUsers(ctx context.Context, account
 		}
 
 		// disable deleting integration user if the initiator is not admin service user
-		if targetUser.Issued == types.UserIssuedIntegration && !initiatorUser.IsServiceUser {
+		if targetUser.Issued == types.UserIssuedIntegration && (initiatorUser == nil || !initiatorUser.IsServiceUser) {
 			allErrors = errors.Join(allErrors, errors.New("only integration service user can delete this user"))
 			continue
 		}
@@ -1328,7 +1461,7 @@ func (am *DefaultAccountManager) GetCurrentUserInfo(ctx context.Context, userAut
 		Restricted: !userAuth.IsChild && user.IsRestrictable() && settings.RegularUsersViewBlocked,
 	}
 
-	permissions, err := am.permissionsManager.GetPermissionsByRole(ctx, user.Role)
+	permissions, err := am.permissionsManager.GetPermissionsByRole(ctx, accountID, user.Role)
 	if err == nil {
 		userWithPermissions.Permissions = permissions
 	}
@@ -3,6 +3,7 @@ package server
 import (
 	"context"
 	"fmt"
+	"net/netip"
 	"os"
 	"reflect"
 	"testing"
@@ -13,9 +14,12 @@ import (
 	"golang.org/x/exp/maps"
 
 	"github.com/netbirdio/netbird/management/internals/controllers/network_map"
+	"github.com/netbirdio/netbird/management/internals/modules/customroles"
 	nbcache "github.com/netbirdio/netbird/management/server/cache"
+	nbcontext "github.com/netbirdio/netbird/management/server/context"
 	"github.com/netbirdio/netbird/management/server/permissions"
 	"github.com/netbirdio/netbird/management/server/permissions/modules"
+	"github.com/netbirdio/netbird/management/server/permissions/operations"
 	"github.com/netbirdio/netbird/management/server/permissions/roles"
 	"github.com/netbirdio/netbird/management/server/users"
 	"github.com/netbirdio/netbird/management/server/util"
@@ -74,7 +78,7 @@ func TestUser_CreatePAT_ForSameUser(t *testing.T) {
 		permissionsManager: permissionsManager,
 	}
 
-	pat, err := am.CreatePAT(context.Background(), mockAccountID, mockUserID, mockUserID, mockTokenName, mockExpiresIn)
+	pat, err := am.CreatePAT(context.Background(), mockAccountID, mockUserID, mockUserID, mockTokenName, mockExpiresIn, nil, nil)
 	if err != nil {
 		t.Fatalf("Error when adding PAT to user: %s", err)
 	}
@@ -100,6 +104,92 @@ func TestUser_CreatePAT_ForSameUser(t *testing.T) {
 	assert.Equal(t, mockUserID, user.Id)
 }
 
+func TestUser_CreatePAT_Scoped(t *testing.T) {
+	s, cleanup, err := store.NewTestStoreFromSQL(context.Background(), "", t.TempDir())
+	if err != nil {
+		t.Fatalf("Error when creating store: %s", err)
+	}
+	t.Cleanup(cleanup)
+
+	account := newAccountWithId(context.Background(), mockAccountID, mockUserID, "", "", "", false)
+
+	err = s.SaveAccount(context.Background(), account)
+	if err != nil {
+		t.Fatalf("Error when saving account: %s", err)
+	}
+
+	am := DefaultAccountManager{
+		Store:              s,
+		eventStore:         &activity.InMemoryEventStore{},
+		permissionsManager: permissions.NewManager(s),
+	}
+
+	scopes := []types.PATScope{{Module: modules.SetupKeys, Access: types.PATScopeAccessWrite}}
+	sourceRanges := []netip.Prefix{netip.MustParsePrefix("203.0.113.7/24")}
+
+	pat, err := am.CreatePAT(context.Background(), mockAccountID, mockUserID, mockUserID, mockTokenName, mockExpiresIn, scopes, sourceRanges)
+	require.NoError(t, err)
+
+	stored, err := s.GetPATByID(context.Background(), store.LockingStrengthNone, mockUserID, pat.ID)
+	require.NoError(t, err)
+	assert.Equal(t, scopes, stored.Scopes)
+	assert.Equal(t, []netip.Prefix{netip.MustParsePrefix("203.0.113.0/24")}, stored.AllowedSourceRanges)
+
+	_, err = am.CreatePAT(context.Background(), mockAccountID, mockUserID, mockUserID, mockTokenName, mockExpiresIn, []types.PATScope{{Module: "unknown", Access: types.PATScopeAccessRead}}, nil)
+	require.Error(t, err)
+	sErr, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, status.InvalidArgument, sErr.Type())
+}
+
+func TestUser_CreatePAT_FromScopedToken(t *testing.T) {
+	s, cleanup, err := store.NewTestStoreFromSQL(context.Background(), "", t.TempDir())
+	if err != nil {
+		t.Fatalf("Error when creating store: %s", err)
+	}
+	t.Cleanup(cleanup)
+
+	account := newAccountWithId(context.Background(), mockAccountID, mockUserID, "", "", "", false)
+
+	err = s.SaveAccount(context.Background(), account)
+	if err != nil {
+		t.Fatalf("Error when saving account: %s", err)
+	}
+
+	am := DefaultAccountManager{
+		Store:              s,
+		eventStore:         &activity.InMemoryEventStore{},
+		permissionsManager: permissions.NewManager(s),
+	}
+
+	callerScopes := []types.PATScope{{Module: modules.Pats, Access: types.PATScopeAccessWrite}}
+	callerRanges := []netip.Prefix{netip.MustParsePrefix("203.0.113.0/24")}
+	caller, err := am.CreatePAT(context.Background(), mockAccountID, mockUserID, mockUserID, mockTokenName, mockExpiresIn, callerScopes, callerRanges)
+	require.NoError(t, err)
+
+	ctx := nbcontext.SetUserAuthInContext(context.Background(), auth.UserAuth{
+		UserId:    mockUserID,
+		AccountId: mockAccountID,
+		IsPAT:     true,
+		PATID:     caller.ID,
+	})
+
+	_, err = am.CreatePAT(ctx, mockAccountID, mockUserID, mockUserID, mockTokenName, mockExpiresIn, nil, nil)
+	require.Error(t, err, "a scoped token must not create an unscoped token")
+	sErr, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, status.PermissionDenied, sErr.Type())
+
+	_, err = am.CreatePAT(ctx, mockAccountID, mockUserID, mockUserID, mockTokenName, mockExpiresIn, []types.PATScope{{Module: modules.Users, Access: types.PATScopeAccessWrite}}, callerRanges)
+	require.Error(t, err, "a scoped token must not create a token with other scopes")
+
+	_, err = am.CreatePAT(ctx, mockAccountID, mockUserID, mockUserID, mockTokenName, mockExpiresIn, callerScopes, nil)
+	require.Error(t, err, "a restricted token must not create a token usable from any source")
+
+	_, err = am.CreatePAT(ctx, mockAccountID, mockUserID, mockUserID, mockTokenName, mockExpiresIn, callerScopes, []netip.Prefix{netip.MustParsePrefix("203.0.113.0/28")})
+	require.NoError(t, err)
+}
+
 func TestUser_CreatePAT_ForDifferentUser(t *testing.T) {
 	store, cleanup, err := store.NewTestStoreFromSQL(context.Background(), "", t.TempDir())
 	if err != nil {
@@ -124,7 +214,7 @@ func TestUser_CreatePAT_ForDifferentUser(t *testing.T) {
 		permissionsManager: permissionsManager,
 	}
 
-	_, err = am.CreatePAT(context.Background(), mockAccountID, mockUserID, mockTargetUserId, mockTokenName, mockExpiresIn)
+	_, err = am.CreatePAT(context.Background(), mockAccountID, mockUserID, mockTargetUserId, mockTokenName, mockExpiresIn, nil, nil)
 	assert.Errorf(t, err, "Creating PAT for different user should thorw error")
 }
 
@@ -152,7 +242,7 @@ func TestUser_CreatePAT_ForServiceUser(t *testing.T) {
 		permissionsManager: permissionsManager,
 	}
 
-	pat, err := am.CreatePAT(context.Background(), mockAccountID, mockUserID, mockTargetUserId, mockTokenName, mockExpiresIn)
+	pat, err := am.CreatePAT(context.Background(), mockAccountID, mockUserID, mockTargetUserId, mockTokenName, mockExpiresIn, nil, nil)
 	if err != nil {
 		t.Fatalf("Error when adding PAT to user: %s", err)
 	}
@@ -181,7 +271,7 @@ func TestUser_CreatePAT_WithWrongExpiration(t *testing.T) {
 		permissionsManager: permissionsManager,
 	}
 
-	_, err = am.CreatePAT(context.Background(), mockAccountID, mockUserID, mockUserID, mockTokenName, mockWrongExpiresIn)
+	_, err = am.CreatePAT(context.Background(), mockAccountID, mockUserID, mockUserID, mockTokenName, mockWrongExpiresIn, nil, nil)
 	assert.Errorf(t, err, "Wrong expiration should thorw error")
 }
 
@@ -206,7 +296,7 @@ func TestUser_CreatePAT_WithEmptyName(t *testing.T) {
 		permissionsManager: permissionsManager,
 	}
 
-	_, err = am.CreatePAT(context.Background(), mockAccountID, mockUserID, mockUserID, mockEmptyTokenName, mockExpiresIn)
+	_, err = am.CreatePAT(context.Background(), mockAccountID, mockUserID, mockUserID, mockEmptyTokenName, mockExpiresIn, nil, nil)
 	assert.Errorf(t, err, "Wrong expiration should thorw error")
 }
 
@@ -452,6 +542,53 @@ func TestUser_CreateServiceUser(t *testing.T) {
 	}
 }
 
+func TestUser_CreateServiceUser_RoleEscalation(t *testing.T) {
+	store, cleanup, err := store.NewTestStoreFromSQL(context.Background(), "", t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(cleanup)
+
+	account := newAccountWithId(context.Background(), mockAccountID, mockUserID, "", "", "", false)
+
+	// a user that can only manage roles and create users
+	userManagerRole := customroles.NewRole(mockAccountID, "user-manager", "", roles.Permissions{
+		modules.Roles: {operations.Read: true, operations.Create: true},
+		modules.Users: {operations.Read: true, operations.Create: true},
+	})
+	peerAdminRole := customroles.NewRole(mockAccountID, "peer-admin", "", roles.Permissions{
+		modules.Peers: {operations.Read: true, operations.Create: true, operations.Update: true, operations.Delete: true},
+	})
+	userManagerID := "userManager"
+	account.Users[userManagerID] = &types.User{Id: userManagerID, AccountID: mockAccountID, Role: userManagerRole.UserRole()}
+
+	require.NoError(t, store.SaveAccount(context.Background(), account))
+	require.NoError(t, store.CreateCustomRole(context.Background(), userManagerRole))
+	require.NoError(t, store.CreateCustomRole(context.Background(), peerAdminRole))
+
+	am := DefaultAccountManager{
+		Store:              store,
+		eventStore:         &activity.InMemoryEventStore{},
+		permissionsManager: permissions.NewManager(store),
+	}
+
+	for _, role := range []types.UserRole{types.UserRoleAdmin, types.UserRoleAuditor, peerAdminRole.UserRole()} {
+		_, err = am.createServiceUser(context.Background(), mockAccountID, userManagerID, role, mockServiceUserName, false, nil)
+		require.Error(t, err, "role %s", role)
+		sErr, ok := status.FromError(err)
+		require.True(t, ok)
+		assert.Equal(t, status.PermissionDenied, sErr.Type(), "role %s", role)
+	}
+
+	// roles that don't exceed the permissions of the initiator can be assigned
+	_, err = am.createServiceUser(context.Background(), mockAccountID, userManagerID, userManagerRole.UserRole(), mockServiceUserName, false, nil)
+	require.NoError(t, err)
+	_, err = am.createServiceUser(context.Background(), mockAccountID, userManagerID, types.UserRoleUser, mockServiceUserName, false, nil)
+	require.NoError(t, err)
+
+	// the account owner can assign the custom role
+	_, err = am.createServiceUser(context.Background(), mockAccountID, mockUserID, peerAdminRole.UserRole(), mockServiceUserName, false, nil)
+	require.NoError(t, err)
+}
+
 func TestUser_CreateUser_ServiceUser(t *testing.T) {
 	store, cleanup, err := store.NewTestStoreFromSQL(context.Background(), "", t.TempDir())
 	if err != nil {
@@ -1196,6 +1333,7 @@ func TestDefaultAccountManager_SaveUser(t *testing.T) {
 	serviceUserID := "serviceUser"
 	adminUserID := "adminUser"
 	ownerUserID := "ownerUser"
+	customRoleUserID := "customRoleUser"
 
 	tt := []struct {
 		name        string
@@ -1322,6 +1460,56 @@ func TestDefaultAccountManager_SaveUser(t *testing.T) {
 				Blocked: true,
 			},
 		},
+		{
+			name:        "Should_Fail_To_Add_Owner_Role_By_Custom_Role_User",
+			expectedErr: true,
+			initiatorID: customRoleUserID,
+			update: &types.User{
+				Id:      regularUserID,
+				Role:    types.UserRoleOwner,
+				Blocked: false,
+			},
+		},
+		{
+			name:        "Should_Fail_To_Add_Admin_Role_By_Custom_Role_User",
+			expectedErr: true,
+			initiatorID: customRoleUserID,
+			update: &types.User{
+				Id:      regularUserID,
+				Role:    types.UserRoleAdmin,
+				Blocked: false,
+			},
+		},
+		{
+			name:        "Should_Fail_To_Remove_Admin_Role_By_Custom_Role_User",
+			expectedErr: true,
+			initiatorID: customRoleUserID,
+			update: &types.User{
+				Id:      adminUserID,
+				Role:    types.UserRoleUser,
+				Blocked: false,
+			},
+		},
+		{
+			name:        "Should_Fail_When_Custom_Role_User_Changes_Own_Role",
+			expectedErr: true,
+			initiatorID: customRoleUserID,
+			update: &types.User{
+				Id:      customRoleUserID,
+				Role:    types.UserRoleOwner,
+				Blocked: false,
+			},
+		},
+		{
+			name:        "Should_Update_Regular_User_By_Custom_Role_User",
+			expectedErr: false,
+			initiatorID: customRoleUserID,
+			update: &types.User{
+				Id:      regularUserID,
+				Role:    types.UserRoleUser,
+				Blocked: true,
+			},
+		},
 	}
 
 	for _, tc := range tt {
@@ -1337,6 +1525,12 @@ func TestDefaultAccountManager_SaveUser(t *testing.T) {
 			account.Users[regularUserID] = types.NewRegularUser(regularUserID, "", "")
 			account.Users[adminUserID] = types.NewAdminUser(adminUserID)
 			account.Users[serviceUserID] = &types.User{IsServiceUser: true, Id: serviceUserID, Role: types.UserRoleAdmin, ServiceUserName: "service"}
+			// a custom role that only allows managing users
+			userManagerRole := customroles.NewRole(account.Id, "user-manager-"+tc.name, "", roles.Permissions{
+				modules.Users: {operations.Read: true, operations.Create: true, operations.Update: true},
+			})
+			require.NoError(t, manager.Store.CreateCustomRole(context.Background(), userManagerRole))
+			account.Users[customRoleUserID] = &types.User{Id: customRoleUserID, AccountID: account.Id, Role: userManagerRole.UserRole()}
 			err = manager.Store.SaveAccount(context.Background(), account)
 			if err != nil {
 				t.Fatal(err)
@@ -0,0 +1,76 @@
+package util
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// internalPrefixes are the internal networks that are not covered by the netip.Addr classification methods
+var internalPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+// IsInternalAddress returns true if the address is not publicly routable. Services configured by the accounts
+// can't be called on the network of the management server.
+func IsInternalAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() || addr.IsLinkLocalUnicast() ||
+		addr.IsMulticast() || addr.IsInterfaceLocalMulticast() || addr.IsLinkLocalMulticast() {
+		return true
+	}
+
+	for _, prefix := range internalPrefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsInternalHost returns true if the host is an internal address or a localhost name
+func IsInternalHost(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+
+	addr, err := netip.ParseAddr(host)
+	return err == nil && IsInternalAddress(addr)
+}
+
+// NewPublicDialer returns a dialer refusing connections to internal addresses. The addresses are checked when
+// dialing, so host names resolving to internal addresses are refused as well.
+func NewPublicDialer(timeout time.Duration) *net.Dialer {
+	return &net.Dialer{
+		Timeout: timeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if IsInternalAddress(addrPort.Addr()) {
+				return fmt.Errorf("connection to internal address %s is not allowed", addrPort.Addr())
+			}
+			return nil
+		},
+	}
+}
+
+// NewPublicTransport returns an HTTP transport that only connects to public addresses
+func NewPublicTransport(timeout time.Duration) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// a proxy would connect to the internal addresses on behalf of the client
+	transport.Proxy = nil
+	transport.DialContext = NewPublicDialer(timeout).DialContext
+	return transport
+}
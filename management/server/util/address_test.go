@@ -0,0 +1,42 @@
+package util
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsInternalAddress(t *testing.T) {
+	for _, addr := range []string{"127.0.0.1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.169.254", "100.64.0.1", "0.0.0.0", "::1", "fd00::1", "fe80::1", "::ffff:10.0.0.1"} {
+		assert.True(t, IsInternalAddress(netip.MustParseAddr(addr)), addr)
+	}
+	for _, addr := range []string{"203.0.113.10", "8.8.8.8", "2001:4860:4860::8888"} {
+		assert.False(t, IsInternalAddress(netip.MustParseAddr(addr)), addr)
+	}
+}
+
+func TestIsInternalHost(t *testing.T) {
+	for _, host := range []string{"localhost", "LOCALHOST.", "api.localhost", "127.0.0.1", "::1"} {
+		assert.True(t, IsInternalHost(host), host)
+	}
+	for _, host := range []string{"example.com", "8.8.8.8"} {
+		assert.False(t, IsInternalHost(host), host)
+	}
+}
+
+func TestNewPublicTransport_RefusesInternalAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	client := &http.Client{Transport: NewPublicTransport(time.Second)}
+	_, err := client.Get(server.URL)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "internal address")
+}
@@ -8,8 +8,10 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"net/netip"
 	"os"
 	"os/signal"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
@@ -21,9 +23,12 @@ import (
 	"github.com/netbirdio/netbird/encryption"
 	"github.com/netbirdio/netbird/relay/healthcheck"
 	"github.com/netbirdio/netbird/relay/server"
+	"github.com/netbirdio/netbird/relay/server/cluster"
+	"github.com/netbirdio/netbird/relay/server/limiter"
 	"github.com/netbirdio/netbird/shared/relay/auth"
 	"github.com/netbirdio/netbird/signal/metrics"
 	"github.com/netbirdio/netbird/stun"
+	"github.com/netbirdio/netbird/turn"
 	"github.com/netbirdio/netbird/util"
 )
 
@@ -49,6 +54,28 @@ type Config struct {
 	EnableSTUN   bool
 	STUNPorts    []int
 	STUNLogLevel string
+	// TURN server configuration, the credentials are the time based TURN credentials issued by management
+	EnableTURN   bool
+	TURNPorts    []int
+	TURNRelayIP  string
+	TURNMinPort  int
+	TURNMaxPort  int
+	TURNRealm    string
+	TURNSecret   string
+	TURNLogLevel string
+	// TURNAllowedPeerRanges are the CIDRs the TURN server relays to although they are private or special purpose ranges
+	TURNAllowedPeerRanges []string
+	// Cluster configuration, the relay runs standalone without a cluster listen address
+	ClusterListenAddress string
+	ClusterMembers       []string
+	ClusterSecret        string
+	// Bandwidth limits in bytes per second and daily quotas in bytes, zero disables the limit
+	PeerRateLimit     int64
+	PeerBurst         int64
+	AccountRateLimit  int64
+	AccountBurst      int64
+	PeerDailyQuota    int64
+	AccountDailyQuota int64
 }
 
 func (c Config) Validate() error {
@@ -77,9 +104,104 @@ func (c Config) Validate() error {
 		}
 	}
 
+	if c.EnableTURN {
+		if err := c.validateTURN(); err != nil {
+			return err
+		}
+	}
+
+	if len(c.ClusterMembers) > 0 && c.ClusterListenAddress == "" {
+		return fmt.Errorf("--cluster-listen-address is required when --cluster-members is set")
+	}
+
+	if err := c.Limits().Validate(); err != nil {
+		return fmt.Errorf("invalid limits: %w", err)
+	}
+
 	return nil
 }
 
+func (c Config) validateTURN() error {
+	if len(c.TURNPorts) == 0 {
+		return fmt.Errorf("--turn-ports is required when --enable-turn is set")
+	}
+
+	seen := make(map[int]bool)
+	for _, port := range c.TURNPorts {
+		if port <= 0 || port > 65535 {
+			return fmt.Errorf("invalid TURN port %d: must be between 1 and 65535", port)
+		}
+		if seen[port] {
+			return fmt.Errorf("duplicate TURN port %d", port)
+		}
+		seen[port] = true
+	}
+
+	// the TURN server answers the STUN binding requests too, the ports can not be shared
+	if c.EnableSTUN {
+		for _, port := range c.STUNPorts {
+			if seen[port] {
+				return fmt.Errorf("port %d is used by both STUN and TURN, the TURN server also answers STUN requests", port)
+			}
+		}
+	}
+
+	if c.TURNMinPort <= 0 || c.TURNMaxPort > 65535 || c.TURNMinPort > c.TURNMaxPort {
+		return fmt.Errorf("invalid TURN relay port range %d-%d", c.TURNMinPort, c.TURNMaxPort)
+	}
+
+	if c.TURNRelayIP != "" && net.ParseIP(c.TURNRelayIP) == nil {
+		return fmt.Errorf("invalid TURN relay IP %q", c.TURNRelayIP)
+	}
+
+	if _, err := c.turnAllowedPeerRanges(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c Config) turnAllowedPeerRanges() ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(c.TURNAllowedPeerRanges))
+	for _, r := range c.TURNAllowedPeerRanges {
+		prefix, err := netip.ParsePrefix(r)
+		if err != nil {
+			return nil, fmt.Errorf("invalid TURN allowed peer range %q: %w", r, err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// Limits returns the bandwidth limits and daily quotas of the peers and accounts
+func (c Config) Limits() limiter.Config {
+	return limiter.Config{
+		PeerRate:          c.PeerRateLimit,
+		PeerBurst:         c.PeerBurst,
+		AccountRate:       c.AccountRateLimit,
+		AccountBurst:      c.AccountBurst,
+		PeerDailyQuota:    c.PeerDailyQuota,
+		AccountDailyQuota: c.AccountDailyQuota,
+	}
+}
+
+// ClusterConfig returns the cluster configuration or nil if clustering is disabled. The cluster secret defaults to
+// the auth secret.
+func (c Config) ClusterConfig() *cluster.Config {
+	if c.ClusterListenAddress == "" {
+		return nil
+	}
+
+	secret := c.ClusterSecret
+	if secret == "" {
+		secret = c.AuthSecret
+	}
+	return &cluster.Config{
+		ListenAddress: c.ClusterListenAddress,
+		Members:       c.ClusterMembers,
+		Secret:        secret,
+	}
+}
+
 func (c Config) HasCertConfig() bool {
 	return c.TlsCertFile != "" && c.TlsKeyFile != ""
 }
@@ -120,6 +242,27 @@ func init() {
 	rootCmd.PersistentFlags().IntSliceVar(&cobraConfig.STUNPorts, "stun-ports", []int{3478}, "ports for the embedded STUN server (can be specified multiple times or comma-separated)")
 	rootCmd.PersistentFlags().StringVar(&cobraConfig.STUNLogLevel, "stun-log-level", "info", "log level for STUN server (panic, fatal, error, warn, info, debug, trace)")
 
+	rootCmd.PersistentFlags().BoolVar(&cobraConfig.EnableTURN, "enable-turn", false, "enable embedded TURN server, it also answers STUN binding requests")
+	rootCmd.PersistentFlags().IntSliceVar(&cobraConfig.TURNPorts, "turn-ports", []int{3479}, "UDP ports for the embedded TURN server (can be specified multiple times or comma-separated). The default differs from the STUN port, the TURN server also answers STUN requests")
+	rootCmd.PersistentFlags().StringVar(&cobraConfig.TURNRelayIP, "turn-relay-ip", "", "public IP address advertised in the TURN allocations, defaults to the resolved host of the exposed address")
+	rootCmd.PersistentFlags().IntVar(&cobraConfig.TURNMinPort, "turn-min-port", 49152, "lowest UDP port of the TURN allocations")
+	rootCmd.PersistentFlags().IntVar(&cobraConfig.TURNMaxPort, "turn-max-port", 65535, "highest UDP port of the TURN allocations")
+	rootCmd.PersistentFlags().StringVar(&cobraConfig.TURNRealm, "turn-realm", turn.DefaultRealm, "TURN realm")
+	rootCmd.PersistentFlags().StringVar(&cobraConfig.TURNSecret, "turn-secret", "", "secret of the time based TURN credentials, must match the TURN secret of management. Defaults to the auth secret")
+	rootCmd.PersistentFlags().StringSliceVar(&cobraConfig.TURNAllowedPeerRanges, "turn-allowed-peer-ranges", nil, "CIDRs the TURN server relays to although they are loopback, private or link-local ranges, which are denied by default (can be specified multiple times or comma-separated)")
+	rootCmd.PersistentFlags().StringVar(&cobraConfig.TURNLogLevel, "turn-log-level", "info", "log level for TURN server (panic, fatal, error, warn, info, debug, trace)")
+
+	rootCmd.PersistentFlags().StringVar(&cobraConfig.ClusterListenAddress, "cluster-listen-address", "", "listen address for the connections of the sibling relay instances, e.g. :7000. Enables clustering. The cluster traffic is not encrypted, keep it on a private network")
+	rootCmd.PersistentFlags().StringSliceVar(&cobraConfig.ClusterMembers, "cluster-members", nil, "cluster addresses (host:port) of the sibling relay instances (can be specified multiple times or comma-separated)")
+	rootCmd.PersistentFlags().StringVar(&cobraConfig.ClusterSecret, "cluster-secret", "", "secret shared by the cluster members, defaults to the auth secret")
+
+	rootCmd.PersistentFlags().Int64Var(&cobraConfig.PeerRateLimit, "peer-rate-limit", 0, "bandwidth limit of a single peer in bytes per second, 0 disables the limit")
+	rootCmd.PersistentFlags().Int64Var(&cobraConfig.PeerBurst, "peer-burst", 0, "number of bytes a single peer can send at once, defaults to one second worth of the peer rate limit")
+	rootCmd.PersistentFlags().Int64Var(&cobraConfig.AccountRateLimit, "account-rate-limit", 0, "bandwidth limit shared by the peers of an account in bytes per second, 0 disables the limit. Requires the Relay.AccountTokens management setting")
+	rootCmd.PersistentFlags().Int64Var(&cobraConfig.AccountBurst, "account-burst", 0, "number of bytes the peers of an account can send at once, defaults to one second worth of the account rate limit")
+	rootCmd.PersistentFlags().Int64Var(&cobraConfig.PeerDailyQuota, "peer-daily-quota", 0, "number of bytes a single peer can relay per day (UTC), 0 disables the quota")
+	rootCmd.PersistentFlags().Int64Var(&cobraConfig.AccountDailyQuota, "account-daily-quota", 0, "number of bytes the peers of an account can relay per day (UTC), 0 disables the quota. Requires the Relay.AccountTokens management setting")
+
 	setFlagsFromEnvVars(rootCmd)
 }
 
@@ -166,12 +309,18 @@ func execute(cmd *cobra.Command, args []string) error {
 	}
 	srvListenerCfg.TLSConfig = tlsConfig
 
-	// Create STUN listeners early to fail fast
+	// Create STUN and TURN listeners early to fail fast
 	stunListeners, err := createSTUNListeners()
 	if err != nil {
 		return err
 	}
 
+	turnServer, err := createTURNServer()
+	if err != nil {
+		cleanupSTUNListeners(stunListeners)
+		return err
+	}
+
 	hashedSecret := sha256.Sum256([]byte(cobraConfig.AuthSecret))
 	authenticator := auth.NewTimedHMACValidator(hashedSecret[:], 24*time.Hour)
 
@@ -180,11 +329,14 @@ func execute(cmd *cobra.Command, args []string) error {
 		ExposedAddress: cobraConfig.ExposedAddress,
 		AuthValidator:  authenticator,
 		TLSSupport:     tlsSupport,
+		Cluster:        cobraConfig.ClusterConfig(),
+		Limits:         cobraConfig.Limits(),
 	}
 
 	srv, err := createRelayServer(cfg)
 	if err != nil {
 		cleanupSTUNListeners(stunListeners)
+		cleanupTURNServer(turnServer)
 		return err
 	}
 
@@ -195,6 +347,7 @@ func execute(cmd *cobra.Command, args []string) error {
 	httpHealthcheck, err := createHealthCheck(hCfg)
 	if err != nil {
 		cleanupSTUNListeners(stunListeners)
+		cleanupTURNServer(turnServer)
 		return err
 	}
 
@@ -204,19 +357,19 @@ func execute(cmd *cobra.Command, args []string) error {
 	}
 
 	// Start all servers (only after all resources are successfully created)
-	startServers(&wg, metricsServer, srv, srvListenerCfg, httpHealthcheck, stunServer)
+	startServers(&wg, metricsServer, srv, srvListenerCfg, httpHealthcheck, stunServer, turnServer)
 
 	waitForExitSignal()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	err = shutdownServers(ctx, metricsServer, srv, httpHealthcheck, stunServer)
+	err = shutdownServers(ctx, metricsServer, srv, httpHealthcheck, stunServer, turnServer)
 	wg.Wait()
 	return err
 }
 
-func startServers(wg *sync.WaitGroup, metricsServer *metrics.Metrics, srv *server.Server, srvListenerCfg server.ListenerConfig, httpHealthcheck *healthcheck.Server, stunServer *stun.Server) {
+func startServers(wg *sync.WaitGroup, metricsServer *metrics.Metrics, srv *server.Server, srvListenerCfg server.ListenerConfig, httpHealthcheck *healthcheck.Server, stunServer *stun.Server, turnServer *turn.Server) {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
@@ -256,9 +409,22 @@ func startServers(wg *sync.WaitGroup, metricsServer *metrics.Metrics, srv *serve
 			}
 		}()
 	}
+
+	if turnServer != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := turnServer.Listen(); err != nil {
+				if errors.Is(err, turn.ErrServerClosed) {
+					return
+				}
+				log.Errorf("TURN server error: %v", err)
+			}
+		}()
+	}
 }
 
-func shutdownServers(ctx context.Context, metricsServer *metrics.Metrics, srv *server.Server, httpHealthcheck *healthcheck.Server, stunServer *stun.Server) error {
+func shutdownServers(ctx context.Context, metricsServer *metrics.Metrics, srv *server.Server, httpHealthcheck *healthcheck.Server, stunServer *stun.Server, turnServer *turn.Server) error {
 	var errs error
 
 	if err := httpHealthcheck.Shutdown(ctx); err != nil {
@@ -271,6 +437,12 @@ func shutdownServers(ctx context.Context, metricsServer *metrics.Metrics, srv *s
 		}
 	}
 
+	if turnServer != nil {
+		if err := turnServer.Shutdown(); err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("failed to close TURN server: %w", err))
+		}
+	}
+
 	if err := srv.Shutdown(ctx); err != nil {
 		errs = multierror.Append(errs, fmt.Errorf("failed to close relay server: %w", err))
 	}
@@ -323,6 +495,104 @@ func createSTUNListeners() ([]*net.UDPConn, error) {
 	return stunListeners, nil
 }
 
+// createTURNServer creates the TURN server with its listeners, returns nil if TURN is disabled
+func createTURNServer() (*turn.Server, error) {
+	if !cobraConfig.EnableTURN {
+		return nil, nil
+	}
+
+	relayIP, err := turnRelayIP(cobraConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine TURN relay IP: %v", err)
+	}
+
+	secret := cobraConfig.TURNSecret
+	if secret == "" {
+		secret = cobraConfig.AuthSecret
+	}
+
+	allowedPeerRanges, err := cobraConfig.turnAllowedPeerRanges()
+	if err != nil {
+		return nil, err
+	}
+
+	var listeners []*net.UDPConn
+	for _, port := range cobraConfig.TURNPorts {
+		listener, err := net.ListenUDP("udp", &net.UDPAddr{Port: port})
+		if err != nil {
+			// Close already opened listeners on failure
+			cleanupTURNListeners(listeners)
+			log.Debugf("failed to create TURN listener on port %d: %v", port, err)
+			return nil, fmt.Errorf("failed to create TURN listener on port %d: %v", port, err)
+		}
+		listeners = append(listeners, listener)
+	}
+
+	turnServer, err := turn.NewServer(turn.Config{
+		Conns:             listeners,
+		RelayIP:           relayIP,
+		MinPort:           uint16(cobraConfig.TURNMinPort),
+		MaxPort:           uint16(cobraConfig.TURNMaxPort),
+		Realm:             cobraConfig.TURNRealm,
+		Secret:            secret,
+		AllowedPeerRanges: allowedPeerRanges,
+		LogLevel:          cobraConfig.TURNLogLevel,
+	})
+	if err != nil {
+		cleanupTURNListeners(listeners)
+		return nil, err
+	}
+	return turnServer, nil
+}
+
+func cleanupTURNListeners(listeners []*net.UDPConn) {
+	for _, l := range listeners {
+		_ = l.Close()
+	}
+}
+
+func cleanupTURNServer(turnServer *turn.Server) {
+	if turnServer == nil {
+		return
+	}
+	if err := turnServer.Shutdown(); err != nil {
+		log.Debugf("failed to close TURN server: %v", err)
+	}
+}
+
+// turnRelayIP returns the configured TURN relay IP or resolves the host of the exposed address
+func turnRelayIP(cfg *Config) (net.IP, error) {
+	if cfg.TURNRelayIP != "" {
+		return net.ParseIP(cfg.TURNRelayIP), nil
+	}
+
+	host := cfg.ExposedAddress
+	if _, rest, found := strings.Cut(host, "://"); found {
+		host = rest
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		return ip, nil
+	}
+
+	ips, err := net.LookupIP(host)
+	if err != nil {
+		return nil, fmt.Errorf("resolve %s: %w, set --turn-relay-ip", host, err)
+	}
+	for _, ip := range ips {
+		if ip.To4() != nil {
+			return ip, nil
+		}
+	}
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("no address found for %s, set --turn-relay-ip", host)
+	}
+	return ips[0], nil
+}
+
 func handleTLSConfig(cfg *Config) (*tls.Config, bool, error) {
 	if cfg.LetsencryptAWSRoute53 {
 		log.Debugf("using Let's Encrypt DNS resolver with Route 53 support")
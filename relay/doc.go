@@ -10,5 +10,20 @@ transported over the WebSocket connection. Optionally the server can use TLS to
 The service can support multiple Relay server instances. For this purpose the peers must know the server instance URL.
 This URL will be sent to the target peer to choose the common Relay server for the communication via Signal service.
 
+The instances can also form a cluster (--cluster-listen-address, --cluster-members). Clustered instances announce their
+connected peers to each other and forward the transport messages, so peers connected to different instances can reach
+each other without connecting to the instance of the remote peer.
+
+The binary can also run an embedded STUN server (--enable-stun) and an embedded TURN server (--enable-turn) for the ICE
+candidates. The TURN server accepts the time based TURN credentials issued by management (TimeBasedCredentials), the
+--turn-secret must match the TURN secret configured in management. The TURN server doesn't relay to loopback, private
+and link-local addresses unless they are listed in --turn-allowed-peer-ranges.
+
+The traffic of the peers can be limited with per-peer and per-account token buckets (--peer-rate-limit,
+--account-rate-limit) and daily quotas (--peer-daily-quota, --account-daily-quota). The rate limits delay the messages,
+the exhausted quotas drop them until the end of the day (UTC). The server sends a throttled message to the client with the
+reason of the limitation. The account of a peer is taken from the auth token, peers with tokens without account are
+limited by the per-peer limits only.
+
 */
 package main
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
 )
 
@@ -22,6 +23,13 @@ type Metrics struct {
 	PeerStoreTime      metric.Float64Histogram
 	peerReconnections  metric.Int64Counter
 	peers              metric.Int64UpDownCounter
+	clusterBytesSent   metric.Int64Counter
+	clusterBytesRecv   metric.Int64Counter
+	clusterMembers     metric.Int64UpDownCounter
+	clusterRemotePeers metric.Int64UpDownCounter
+	throttledBytes     metric.Int64Counter
+	throttleDelay      metric.Float64Histogram
+	quotaDroppedBytes  metric.Int64Counter
 	peerActivityChan   chan string
 	peerLastActive     map[string]time.Time
 	mutexActivity      sync.Mutex
@@ -87,6 +95,56 @@ func NewMetrics(ctx context.Context, meter metric.Meter) (*Metrics, error) {
 		return nil, err
 	}
 
+	clusterBytesSent, err := meter.Int64Counter("relay_cluster_sent_bytes_total",
+		metric.WithDescription("Total number of bytes forwarded to sibling relay instances"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterBytesRecv, err := meter.Int64Counter("relay_cluster_received_bytes_total",
+		metric.WithDescription("Total number of bytes received from sibling relay instances"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterMembers, err := meter.Int64UpDownCounter("relay_cluster_members",
+		metric.WithDescription("Number of connected sibling relay instances"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterRemotePeers, err := meter.Int64UpDownCounter("relay_cluster_remote_peers",
+		metric.WithDescription("Number of peers connected to sibling relay instances"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	throttledBytes, err := meter.Int64Counter("relay_throttled_bytes_total",
+		metric.WithDescription("Total number of bytes delayed by the bandwidth limits"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	throttleDelay, err := meter.Float64Histogram("relay_throttle_delay_milliseconds",
+		metric.WithExplicitBucketBoundaries(getStandardBucketBoundaries()...),
+		metric.WithDescription("Time the messages are delayed by the bandwidth limits"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	quotaDroppedBytes, err := meter.Int64Counter("relay_quota_dropped_bytes_total",
+		metric.WithDescription("Total number of bytes dropped because of exhausted daily quotas"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	m := &Metrics{
 		Meter:              meter,
 		TransferBytesSent:  bytesSent,
@@ -95,6 +153,13 @@ func NewMetrics(ctx context.Context, meter metric.Meter) (*Metrics, error) {
 		PeerStoreTime:      peerStoreTime,
 		peers:              peers,
 		peerReconnections:  peerReconnections,
+		clusterBytesSent:   clusterBytesSent,
+		clusterBytesRecv:   clusterBytesRecv,
+		clusterMembers:     clusterMembers,
+		clusterRemotePeers: clusterRemotePeers,
+		throttledBytes:     throttledBytes,
+		throttleDelay:      throttleDelay,
+		quotaDroppedBytes:  quotaDroppedBytes,
 
 		ctx:              ctx,
 		peerActivityChan: make(chan string, 10),
@@ -150,6 +215,43 @@ func (m *Metrics) RecordPeerReconnection() {
 	m.peerReconnections.Add(m.ctx, 1)
 }
 
+// RecordClusterBytesSent records the bytes forwarded to a sibling relay instance
+func (m *Metrics) RecordClusterBytesSent(n int) {
+	m.clusterBytesSent.Add(m.ctx, int64(n))
+}
+
+// RecordClusterBytesRecv records the bytes received from a sibling relay instance
+func (m *Metrics) RecordClusterBytesRecv(n int) {
+	m.clusterBytesRecv.Add(m.ctx, int64(n))
+}
+
+// ClusterMemberConnected increments the number of connected sibling relay instances
+func (m *Metrics) ClusterMemberConnected() {
+	m.clusterMembers.Add(m.ctx, 1)
+}
+
+// ClusterMemberDisconnected decrements the number of connected sibling relay instances
+func (m *Metrics) ClusterMemberDisconnected() {
+	m.clusterMembers.Add(m.ctx, -1)
+}
+
+// RemotePeersChanged updates the number of peers connected to sibling relay instances
+func (m *Metrics) RemotePeersChanged(delta int) {
+	m.clusterRemotePeers.Add(m.ctx, int64(delta))
+}
+
+// RecordThrottled records a message delayed by the bandwidth limit given by the reason
+func (m *Metrics) RecordThrottled(reason string, n int, delay time.Duration) {
+	attrs := metric.WithAttributes(attribute.String("reason", reason))
+	m.throttledBytes.Add(m.ctx, int64(n), attrs)
+	m.throttleDelay.Record(m.ctx, float64(delay.Nanoseconds())/1e6, attrs)
+}
+
+// RecordQuotaDropped records a message dropped because of the exhausted quota given by the reason
+func (m *Metrics) RecordQuotaDropped(reason string, n int) {
+	m.quotaDroppedBytes.Add(m.ctx, int64(n), metric.WithAttributes(attribute.String("reason", reason)))
+}
+
 // PeerActivity increases the active connections
 func (m *Metrics) PeerActivity(peerID string) {
 	select {
@@ -0,0 +1,501 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	//nolint:staticcheck
+	"github.com/netbirdio/netbird/relay/metrics"
+	"github.com/netbirdio/netbird/relay/server/store"
+	"github.com/netbirdio/netbird/shared/relay/messages"
+)
+
+const (
+	dialTimeout      = 5 * time.Second
+	handshakeTimeout = 5 * time.Second
+	writeTimeout     = 5 * time.Second
+
+	minReconnectInterval = time.Second
+	maxReconnectInterval = 30 * time.Second
+)
+
+// Config is the cluster configuration of a relay instance
+type Config struct {
+	// ListenAddress is the address the instance accepts connections of its siblings on, e.g. :7000. The connections
+	// are authenticated but not encrypted, the address should only be reachable on the private network of the cluster.
+	ListenAddress string
+	// Members are the cluster addresses (host:port) of the sibling instances
+	Members []string
+	// Secret authenticates the instances, it has to be the same on every member of the cluster
+	Secret string
+}
+
+func (c Config) validate() error {
+	if c.ListenAddress == "" {
+		return errors.New("cluster listen address is required")
+	}
+	if c.Secret == "" {
+		return errors.New("cluster secret is required")
+	}
+	return nil
+}
+
+// Cluster connects the relay instance with its siblings. It announces the locally connected peers to the siblings,
+// keeps track of the peers connected to the siblings and forwards the transport messages between the instances.
+//
+// Every instance dials all of its members and only sends over the connections it has dialed, the accepted
+// connections are only read. This way both directions are independent and there is no need to deduplicate the links.
+type Cluster struct {
+	instanceURL string
+	secret      []byte
+	cfg         Config
+
+	store    *store.Store
+	notifier *store.PeerNotifier
+	metrics  *metrics.Metrics
+
+	listener net.Listener
+
+	// links are the outgoing connections by the instance URL of the sibling
+	links   map[string]*link
+	linksMu sync.RWMutex
+
+	// remotePeers are the peers announced by the accepted connections
+	remotePeers map[*member]map[messages.PeerID]*remotePeer
+	remoteMu    sync.Mutex
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New creates a cluster for the relay instance identified by the instance URL
+func New(cfg Config, instanceURL string, peerStore *store.Store, notifier *store.PeerNotifier, m *metrics.Metrics) (*Cluster, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Cluster{
+		instanceURL: instanceURL,
+		secret:      []byte(cfg.Secret),
+		cfg:         cfg,
+		store:       peerStore,
+		notifier:    notifier,
+		metrics:     m,
+		links:       make(map[string]*link),
+		remotePeers: make(map[*member]map[messages.PeerID]*remotePeer),
+		ctx:         ctx,
+		cancel:      cancel,
+	}, nil
+}
+
+// Start listens for the sibling instances and connects to the configured members
+func (c *Cluster) Start() error {
+	listener, err := net.Listen("tcp", c.cfg.ListenAddress)
+	if err != nil {
+		return fmt.Errorf("listen on %s: %w", c.cfg.ListenAddress, err)
+	}
+	c.listener = listener
+	log.Infof("relay cluster listening on %s", listener.Addr())
+
+	c.wg.Add(1)
+	go c.acceptMembers()
+
+	for _, address := range c.cfg.Members {
+		c.AddMember(address)
+	}
+	return nil
+}
+
+// Addr returns the address of the cluster listener
+func (c *Cluster) Addr() net.Addr {
+	return c.listener.Addr()
+}
+
+// AddMember connects to a sibling instance and keeps reconnecting until the cluster is closed
+func (c *Cluster) AddMember(address string) {
+	c.wg.Add(1)
+	go c.connectMember(address)
+}
+
+// PeerCameOnline announces a locally connected peer to the siblings
+func (c *Cluster) PeerCameOnline(id messages.PeerID) {
+	c.broadcast(framePeersOnline, marshalPeerIDs([]messages.PeerID{id}))
+}
+
+// PeerWentOffline announces the disconnection of a local peer to the siblings
+func (c *Cluster) PeerWentOffline(id messages.PeerID) {
+	c.broadcast(framePeersOffline, marshalPeerIDs([]messages.PeerID{id}))
+}
+
+// Close disconnects from the siblings and stops accepting connections
+func (c *Cluster) Close() {
+	c.cancel()
+	if c.listener != nil {
+		if err := c.listener.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+			log.Errorf("failed to close cluster listener: %s", err)
+		}
+	}
+	c.wg.Wait()
+}
+
+func (c *Cluster) connectMember(address string) {
+	defer c.wg.Done()
+
+	interval := minReconnectInterval
+	for {
+		connected, err := c.runOutgoing(address)
+		if c.ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			log.Warnf("connection to relay cluster member %s failed: %s", address, err)
+		}
+		if connected {
+			interval = minReconnectInterval
+		}
+
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+		interval = min(interval*2, maxReconnectInterval)
+	}
+}
+
+// runOutgoing dials the member and keeps the link until the connection breaks. Returns true if the link was established.
+func (c *Cluster) runOutgoing(address string) (bool, error) {
+	dialer := net.Dialer{Timeout: dialTimeout}
+	conn, err := dialer.DialContext(c.ctx, "tcp", address)
+	if err != nil {
+		return false, fmt.Errorf("dial: %w", err)
+	}
+	defer closeConn(conn)
+
+	stop := context.AfterFunc(c.ctx, func() {
+		closeConn(conn)
+	})
+	defer stop()
+
+	_ = conn.SetDeadline(time.Now().Add(handshakeTimeout))
+	memberURL, err := dialHandshake(conn, c.instanceURL, c.secret)
+	if err != nil {
+		return false, err
+	}
+	_ = conn.SetDeadline(time.Time{})
+
+	if memberURL == c.instanceURL {
+		return false, errors.New("member is this instance")
+	}
+
+	l := &link{instanceURL: memberURL, conn: conn}
+	c.addLink(l)
+	defer c.removeLink(l)
+
+	log.Infof("connected to relay cluster member %s (%s)", memberURL, address)
+
+	// the member does not send anything on this connection, the read returns once the connection is closed
+	_, err = io.Copy(io.Discard, conn)
+	return true, err
+}
+
+// addLink registers the link and sends the locally connected peers to the member. The links lock is held during
+// the initial sync so that no online or offline announcement can overtake it.
+func (c *Cluster) addLink(l *link) {
+	c.linksMu.Lock()
+	defer c.linksMu.Unlock()
+
+	if old, ok := c.links[l.instanceURL]; ok {
+		log.Infof("replacing the link to relay cluster member %s", l.instanceURL)
+		closeConn(old.conn)
+	} else {
+		c.metrics.ClusterMemberConnected()
+	}
+	c.links[l.instanceURL] = l
+
+	peers := c.store.Peers()
+	ids := make([]messages.PeerID, 0, len(peers))
+	for _, p := range peers {
+		ids = append(ids, p.ID())
+	}
+
+	for start := 0; start < len(ids); start += maxPeersPerFrame {
+		end := min(start+maxPeersPerFrame, len(ids))
+		if err := c.sendOnLink(l, framePeersOnline, marshalPeerIDs(ids[start:end])); err != nil {
+			log.Errorf("failed to send online peers to relay cluster member %s: %s", l.instanceURL, err)
+			return
+		}
+	}
+}
+
+func (c *Cluster) removeLink(l *link) {
+	c.linksMu.Lock()
+	defer c.linksMu.Unlock()
+
+	if c.links[l.instanceURL] != l {
+		return
+	}
+	delete(c.links, l.instanceURL)
+	c.metrics.ClusterMemberDisconnected()
+	log.Infof("disconnected from relay cluster member %s", l.instanceURL)
+}
+
+// broadcast sends the frame to every member. The links are copied so that a slow member doesn't block the
+// registration of other links while the frame is written.
+func (c *Cluster) broadcast(t frameType, payload []byte) {
+	c.linksMu.RLock()
+	links := make([]*link, 0, len(c.links))
+	for _, l := range c.links {
+		links = append(links, l)
+	}
+	c.linksMu.RUnlock()
+
+	for _, l := range links {
+		if err := c.sendOnLink(l, t, payload); err != nil {
+			log.Errorf("failed to send %s to relay cluster member %s: %s", t, l.instanceURL, err)
+		}
+	}
+}
+
+// forward sends a transport message to the peer connected to the given member
+func (c *Cluster) forward(instanceURL string, dst messages.PeerID, msg []byte) (int, error) {
+	c.linksMu.RLock()
+	l, ok := c.links[instanceURL]
+	c.linksMu.RUnlock()
+	if !ok {
+		return 0, fmt.Errorf("relay cluster member %s is not connected", instanceURL)
+	}
+
+	if err := c.sendOnLink(l, frameTransport, marshalTransport(dst, msg)); err != nil {
+		return 0, err
+	}
+	return len(msg), nil
+}
+
+func (c *Cluster) sendOnLink(l *link, t frameType, payload []byte) error {
+	n, err := l.send(t, payload)
+	if err != nil {
+		// the broken link is re-established by the connect loop
+		closeConn(l.conn)
+		return err
+	}
+	c.metrics.RecordClusterBytesSent(n)
+	return nil
+}
+
+func (c *Cluster) acceptMembers() {
+	defer c.wg.Done()
+
+	for {
+		conn, err := c.listener.Accept()
+		if err != nil {
+			if c.ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+				return
+			}
+			log.Errorf("failed to accept relay cluster connection: %s", err)
+			continue
+		}
+
+		c.wg.Add(1)
+		go c.handleIncoming(conn)
+	}
+}
+
+func (c *Cluster) handleIncoming(conn net.Conn) {
+	defer c.wg.Done()
+	defer closeConn(conn)
+
+	stop := context.AfterFunc(c.ctx, func() {
+		closeConn(conn)
+	})
+	defer stop()
+
+	_ = conn.SetDeadline(time.Now().Add(handshakeTimeout))
+	memberURL, err := acceptHandshake(conn, c.instanceURL, c.secret)
+	if err != nil {
+		log.Warnf("rejected relay cluster connection from %s: %s", conn.RemoteAddr(), err)
+		return
+	}
+	_ = conn.SetDeadline(time.Time{})
+
+	m := &member{instanceURL: memberURL}
+	defer c.removeMember(m)
+
+	log.Infof("relay cluster member %s connected from %s", memberURL, conn.RemoteAddr())
+
+	for {
+		t, payload, err := readFrame(conn)
+		if err != nil {
+			if c.ctx.Err() == nil && !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				log.Warnf("failed to read from relay cluster member %s: %s", memberURL, err)
+			}
+			return
+		}
+		c.metrics.RecordClusterBytesRecv(frameHeaderSize + len(payload))
+
+		if err := c.handleFrame(m, t, payload); err != nil {
+			log.Errorf("failed to handle %s frame from relay cluster member %s: %s", t, memberURL, err)
+			return
+		}
+	}
+}
+
+func (c *Cluster) handleFrame(m *member, t frameType, payload []byte) error {
+	switch t {
+	case framePeersOnline:
+		ids, err := unmarshalPeerIDs(payload)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			c.addRemotePeer(m, id)
+		}
+	case framePeersOffline:
+		ids, err := unmarshalPeerIDs(payload)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			c.removeRemotePeer(m, id)
+		}
+	case frameTransport:
+		dst, msg, err := unmarshalTransport(payload)
+		if err != nil {
+			return err
+		}
+		c.deliver(dst, msg)
+	default:
+		return fmt.Errorf("unexpected frame type: %d", t)
+	}
+	return nil
+}
+
+// deliver writes the transport message forwarded by a sibling to the local peer
+func (c *Cluster) deliver(dst messages.PeerID, msg []byte) {
+	item, ok := c.store.Peer(dst)
+	if !ok {
+		log.Debugf("peer not found: %s", dst)
+		return
+	}
+
+	w, ok := item.(io.Writer)
+	if !ok {
+		log.Errorf("peer %s does not support writing", dst)
+		return
+	}
+
+	if _, err := w.Write(msg); err != nil {
+		log.Errorf("failed to write forwarded transport message to: %s", dst)
+	}
+}
+
+func (c *Cluster) addRemotePeer(m *member, id messages.PeerID) {
+	c.remoteMu.Lock()
+	peers, ok := c.remotePeers[m]
+	if !ok {
+		peers = make(map[messages.PeerID]*remotePeer)
+		c.remotePeers[m] = peers
+	}
+	if _, exists := peers[id]; exists {
+		c.remoteMu.Unlock()
+		return
+	}
+	rp := &remotePeer{id: id, instanceURL: m.instanceURL, cluster: c}
+	peers[id] = rp
+	c.remoteMu.Unlock()
+
+	c.metrics.RemotePeersChanged(1)
+	if cameOnline := c.store.AddRemotePeer(rp); cameOnline {
+		c.notifier.PeerCameOnline(id)
+	}
+}
+
+func (c *Cluster) removeRemotePeer(m *member, id messages.PeerID) {
+	c.remoteMu.Lock()
+	rp, ok := c.remotePeers[m][id]
+	if ok {
+		delete(c.remotePeers[m], id)
+	}
+	c.remoteMu.Unlock()
+
+	if ok {
+		c.dropRemotePeer(rp)
+	}
+}
+
+// removeMember removes all peers announced by the member connection
+func (c *Cluster) removeMember(m *member) {
+	c.remoteMu.Lock()
+	peers := c.remotePeers[m]
+	delete(c.remotePeers, m)
+	c.remoteMu.Unlock()
+
+	for _, rp := range peers {
+		c.dropRemotePeer(rp)
+	}
+	log.Infof("relay cluster member %s disconnected", m.instanceURL)
+}
+
+func (c *Cluster) dropRemotePeer(rp *remotePeer) {
+	c.metrics.RemotePeersChanged(-1)
+	if wentOffline := c.store.DeleteRemotePeer(rp); wentOffline {
+		c.notifier.PeerWentOffline(rp.id)
+	}
+}
+
+// member is an accepted connection of a sibling instance
+type member struct {
+	instanceURL string
+}
+
+// link is an outgoing connection to a sibling instance
+type link struct {
+	instanceURL string
+	conn        net.Conn
+	mu          sync.Mutex
+}
+
+func (l *link) send(t frameType, payload []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	_ = l.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	return writeFrame(l.conn, t, payload)
+}
+
+// remotePeer is a peer connected to a sibling instance, writing to it forwards the message to the sibling
+type remotePeer struct {
+	id          messages.PeerID
+	instanceURL string
+	cluster     *Cluster
+}
+
+func (p *remotePeer) ID() messages.PeerID {
+	return p.id
+}
+
+// Close does nothing, the connection of the peer is owned by the sibling instance
+func (p *remotePeer) Close() {}
+
+func (p *remotePeer) Write(msg []byte) (int, error) {
+	return p.cluster.forward(p.instanceURL, p.id, msg)
+}
+
+func (p *remotePeer) String() string {
+	return p.id.String() + "@" + p.instanceURL
+}
+
+func closeConn(conn net.Conn) {
+	if err := conn.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+		log.Debugf("failed to close relay cluster connection: %s", err)
+	}
+}
@@ -0,0 +1,214 @@
+package cluster
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+
+	//nolint:staticcheck
+	"github.com/netbirdio/netbird/relay/metrics"
+	"github.com/netbirdio/netbird/relay/server/store"
+	"github.com/netbirdio/netbird/shared/relay/messages"
+)
+
+type mockPeer struct {
+	id       messages.PeerID
+	mu       sync.Mutex
+	received [][]byte
+}
+
+func (p *mockPeer) ID() messages.PeerID {
+	return p.id
+}
+
+func (p *mockPeer) Close() {}
+
+func (p *mockPeer) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.received = append(p.received, append([]byte(nil), b...))
+	return len(b), nil
+}
+
+func (p *mockPeer) messages() [][]byte {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.received
+}
+
+type testInstance struct {
+	cluster  *Cluster
+	store    *store.Store
+	notifier *store.PeerNotifier
+}
+
+func newTestInstance(t *testing.T, instanceURL, secret string) *testInstance {
+	t.Helper()
+
+	m, err := metrics.NewMetrics(context.Background(), otel.Meter(""))
+	require.NoError(t, err)
+
+	i := &testInstance{
+		store:    store.NewStore(),
+		notifier: store.NewPeerNotifier(),
+	}
+	i.cluster, err = New(Config{ListenAddress: "127.0.0.1:0", Secret: secret}, instanceURL, i.store, i.notifier, m)
+	require.NoError(t, err)
+	require.NoError(t, i.cluster.Start())
+	t.Cleanup(i.cluster.Close)
+	return i
+}
+
+func (i *testInstance) addLocalPeer(p *mockPeer) {
+	i.store.AddPeer(p)
+	i.cluster.PeerCameOnline(p.ID())
+}
+
+func connectInstances(a, b *testInstance) {
+	a.cluster.AddMember(b.cluster.Addr().String())
+	b.cluster.AddMember(a.cluster.Addr().String())
+}
+
+func waitForRemotePeer(t *testing.T, i *testInstance, id messages.PeerID, online bool) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		_, ok := i.store.RemotePeer(id)
+		return ok == online
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestCluster_ForwardBetweenInstances(t *testing.T) {
+	a := newTestInstance(t, "rels://relay-a:443", "secret")
+	b := newTestInstance(t, "rels://relay-b:443", "secret")
+
+	peerA := &mockPeer{id: messages.HashID("peer-a")}
+	peerB := &mockPeer{id: messages.HashID("peer-b")}
+
+	// peer A is connected before the cluster is formed, it is announced with the initial sync
+	a.addLocalPeer(peerA)
+	connectInstances(a, b)
+	waitForRemotePeer(t, b, peerA.ID(), true)
+
+	// peer B is connected after the cluster is formed, it is announced with the online notification
+	require.Eventually(t, func() bool {
+		a.cluster.linksMu.RLock()
+		defer a.cluster.linksMu.RUnlock()
+		return len(a.cluster.links) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	b.addLocalPeer(peerB)
+	waitForRemotePeer(t, a, peerB.ID(), true)
+
+	// peer A writes to peer B through the remote peer of instance A
+	item, ok := a.store.RemotePeer(peerB.ID())
+	require.True(t, ok)
+	msg := []byte("transport message")
+	_, err := item.(*remotePeer).Write(msg)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return len(peerB.messages()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, msg, peerB.messages()[0])
+
+	// the disconnection of peer B is propagated to instance A
+	b.store.DeletePeer(peerB)
+	b.cluster.PeerWentOffline(peerB.ID())
+	waitForRemotePeer(t, a, peerB.ID(), false)
+}
+
+func TestCluster_NotifiesLocalListeners(t *testing.T) {
+	a := newTestInstance(t, "rels://relay-a:443", "secret")
+	b := newTestInstance(t, "rels://relay-b:443", "secret")
+
+	peerB := messages.HashID("peer-b")
+
+	online := make(chan []messages.PeerID, 1)
+	offline := make(chan []messages.PeerID, 1)
+	listener := a.notifier.NewListener(func(ids []messages.PeerID) { online <- ids }, func(ids []messages.PeerID) { offline <- ids })
+	defer a.notifier.RemoveListener(listener)
+	assert.Empty(t, a.store.GetOnlinePeersAndRegisterInterest([]messages.PeerID{peerB}, listener))
+
+	connectInstances(a, b)
+	b.addLocalPeer(&mockPeer{id: peerB})
+
+	select {
+	case ids := <-online:
+		assert.Equal(t, []messages.PeerID{peerB}, ids)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for online notification")
+	}
+
+	// losing the sibling takes its peers offline
+	b.cluster.Close()
+	select {
+	case ids := <-offline:
+		assert.Equal(t, []messages.PeerID{peerB}, ids)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for offline notification")
+	}
+}
+
+func TestCluster_RejectsInvalidSecret(t *testing.T) {
+	a := newTestInstance(t, "rels://relay-a:443", "secret")
+	b := newTestInstance(t, "rels://relay-b:443", "other-secret")
+
+	peerB := &mockPeer{id: messages.HashID("peer-b")}
+	b.addLocalPeer(peerB)
+	b.cluster.AddMember(a.cluster.Addr().String())
+
+	time.Sleep(500 * time.Millisecond)
+	_, ok := a.store.RemotePeer(peerB.ID())
+	assert.False(t, ok, "peers of an instance with a different secret must not be accepted")
+}
+
+func TestHandshake(t *testing.T) {
+	secret := []byte("secret")
+
+	dialer, acceptor := net.Pipe()
+	defer closeConn(dialer)
+	defer closeConn(acceptor)
+
+	accepted := make(chan string, 1)
+	go func() {
+		memberURL, err := acceptHandshake(acceptor, "rels://relay-b:443", secret)
+		assert.NoError(t, err)
+		accepted <- memberURL
+	}()
+
+	memberURL, err := dialHandshake(dialer, "rels://relay-a:443", secret)
+	require.NoError(t, err)
+	assert.Equal(t, "rels://relay-b:443", memberURL)
+	assert.Equal(t, "rels://relay-a:443", <-accepted)
+}
+
+func TestHandshake_RejectsReplayedHello(t *testing.T) {
+	secret := []byte("secret")
+
+	h := hello{InstanceURL: "rels://relay-a:443", Nonce: make([]byte, nonceSize)}
+	h.Signature = h.sign(secret, roleDialer, make([]byte, nonceSize))
+	require.NoError(t, h.validate(secret, roleDialer, make([]byte, nonceSize)))
+	assert.Error(t, h.validate([]byte("other"), roleDialer, make([]byte, nonceSize)))
+	assert.Error(t, h.validate(secret, roleAcceptor, make([]byte, nonceSize)), "hello must not be reflected to its sender")
+
+	dialer, acceptor := net.Pipe()
+	defer closeConn(dialer)
+	defer closeConn(acceptor)
+
+	result := make(chan error, 1)
+	go func() {
+		_, err := acceptHandshake(acceptor, "rels://relay-b:443", secret)
+		result <- err
+	}()
+
+	// the recorded hello was signed for another challenge
+	_, _, err := readFrame(dialer)
+	require.NoError(t, err)
+	require.NoError(t, writeHello(dialer, h))
+	assert.ErrorContains(t, <-result, "invalid signature")
+}
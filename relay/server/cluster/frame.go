@@ -0,0 +1,251 @@
+package cluster
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/netbirdio/netbird/shared/relay/messages"
+)
+
+type frameType byte
+
+const (
+	frameHello frameType = iota + 1
+	framePeersOnline
+	framePeersOffline
+	frameTransport
+	frameChallenge
+)
+
+const (
+	frameHeaderSize = 5
+	// maxFrameSize limits the payload size, the largest frames are the peer lists
+	maxFrameSize = 1 << 20
+	// maxPeersPerFrame keeps the peer list frames below maxFrameSize
+	maxPeersPerFrame = 10000
+
+	// nonceSize is the size of the handshake challenges
+	nonceSize = 32
+
+	// the roles separate the signatures of both sides, so that a hello can't be reflected to its sender
+	roleDialer   = "dialer"
+	roleAcceptor = "acceptor"
+)
+
+var peerIDSize = len(messages.PeerID{})
+
+func (t frameType) String() string {
+	switch t {
+	case frameHello:
+		return "hello"
+	case framePeersOnline:
+		return "peers online"
+	case framePeersOffline:
+		return "peers offline"
+	case frameTransport:
+		return "transport"
+	case frameChallenge:
+		return "challenge"
+	default:
+		return "unknown"
+	}
+}
+
+// writeFrame writes a frame: 1 byte type, 4 bytes big endian payload length, payload
+func writeFrame(w io.Writer, t frameType, payload []byte) (int, error) {
+	buf := make([]byte, frameHeaderSize+len(payload))
+	buf[0] = byte(t)
+	binary.BigEndian.PutUint32(buf[1:frameHeaderSize], uint32(len(payload)))
+	copy(buf[frameHeaderSize:], payload)
+	return w.Write(buf)
+}
+
+func readFrame(r io.Reader) (frameType, []byte, error) {
+	header := make([]byte, frameHeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, nil, err
+	}
+
+	size := binary.BigEndian.Uint32(header[1:])
+	if size > maxFrameSize {
+		return 0, nil, fmt.Errorf("frame too large: %d bytes", size)
+	}
+
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+	return frameType(header[0]), payload, nil
+}
+
+func marshalPeerIDs(ids []messages.PeerID) []byte {
+	payload := make([]byte, 0, len(ids)*peerIDSize)
+	for _, id := range ids {
+		payload = append(payload, id[:]...)
+	}
+	return payload
+}
+
+func unmarshalPeerIDs(payload []byte) ([]messages.PeerID, error) {
+	if len(payload)%peerIDSize != 0 {
+		return nil, fmt.Errorf("invalid peer list size: %d", len(payload))
+	}
+
+	ids := make([]messages.PeerID, 0, len(payload)/peerIDSize)
+	for offset := 0; offset < len(payload); offset += peerIDSize {
+		var id messages.PeerID
+		copy(id[:], payload[offset:offset+peerIDSize])
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// marshalTransport prefixes the relay transport message with the destination peer ID. The transport message
+// itself already carries the ID of the sender.
+func marshalTransport(dst messages.PeerID, msg []byte) []byte {
+	payload := make([]byte, 0, peerIDSize+len(msg))
+	payload = append(payload, dst[:]...)
+	return append(payload, msg...)
+}
+
+func unmarshalTransport(payload []byte) (messages.PeerID, []byte, error) {
+	var dst messages.PeerID
+	if len(payload) <= peerIDSize {
+		return dst, nil, errors.New("transport frame too short")
+	}
+	copy(dst[:], payload[:peerIDSize])
+	return dst, payload[peerIDSize:], nil
+}
+
+// hello authenticates an instance with the shared cluster secret. The signature covers the challenge the other side of
+// the handshake has sent, so a recorded hello can't be replayed on another connection.
+type hello struct {
+	InstanceURL string `json:"instance_url"`
+	// Nonce is the challenge of the dialing instance for the accepting one
+	Nonce     []byte `json:"nonce,omitempty"`
+	Signature []byte `json:"signature"`
+}
+
+func (h hello) sign(secret []byte, role string, challenge []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(role))
+	mac.Write(challenge)
+	mac.Write(h.Nonce)
+	mac.Write([]byte(h.InstanceURL))
+	return mac.Sum(nil)
+}
+
+func (h hello) validate(secret []byte, role string, challenge []byte) error {
+	if h.InstanceURL == "" {
+		return errors.New("missing instance url")
+	}
+	if !hmac.Equal(h.Signature, h.sign(secret, role, challenge)) {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+func newNonce() ([]byte, error) {
+	nonce := make([]byte, nonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+	return nonce, nil
+}
+
+// dialHandshake authenticates the connection dialed to a member: the member sends a challenge, the dialing instance
+// answers with its signed hello and a challenge of its own, which the member answers with its signed hello.
+// Returns the instance URL of the member.
+func dialHandshake(rw io.ReadWriter, instanceURL string, secret []byte) (string, error) {
+	t, challenge, err := readFrame(rw)
+	if err != nil {
+		return "", fmt.Errorf("read challenge: %w", err)
+	}
+	if t != frameChallenge {
+		return "", fmt.Errorf("unexpected frame type: %s", t)
+	}
+	if len(challenge) != nonceSize {
+		return "", fmt.Errorf("invalid challenge size: %d", len(challenge))
+	}
+
+	nonce, err := newNonce()
+	if err != nil {
+		return "", err
+	}
+	h := hello{InstanceURL: instanceURL, Nonce: nonce}
+	h.Signature = h.sign(secret, roleDialer, challenge)
+	if err := writeHello(rw, h); err != nil {
+		return "", err
+	}
+
+	reply, err := readHello(rw)
+	if err != nil {
+		return "", err
+	}
+	if err := reply.validate(secret, roleAcceptor, nonce); err != nil {
+		return "", fmt.Errorf("invalid hello from %s: %w", reply.InstanceURL, err)
+	}
+	return reply.InstanceURL, nil
+}
+
+// acceptHandshake is the accepting side of dialHandshake. Returns the instance URL of the dialing member.
+func acceptHandshake(rw io.ReadWriter, instanceURL string, secret []byte) (string, error) {
+	challenge, err := newNonce()
+	if err != nil {
+		return "", err
+	}
+	if _, err := writeFrame(rw, frameChallenge, challenge); err != nil {
+		return "", fmt.Errorf("send challenge: %w", err)
+	}
+
+	h, err := readHello(rw)
+	if err != nil {
+		return "", err
+	}
+	if len(h.Nonce) != nonceSize {
+		return "", fmt.Errorf("invalid nonce size from %s: %d", h.InstanceURL, len(h.Nonce))
+	}
+	if err := h.validate(secret, roleDialer, challenge); err != nil {
+		return "", fmt.Errorf("invalid hello from %s: %w", h.InstanceURL, err)
+	}
+
+	reply := hello{InstanceURL: instanceURL}
+	reply.Signature = reply.sign(secret, roleAcceptor, h.Nonce)
+	if err := writeHello(rw, reply); err != nil {
+		return "", err
+	}
+	return h.InstanceURL, nil
+}
+
+func writeHello(w io.Writer, h hello) error {
+	payload, err := json.Marshal(h)
+	if err != nil {
+		return fmt.Errorf("marshal hello: %w", err)
+	}
+	if _, err = writeFrame(w, frameHello, payload); err != nil {
+		return fmt.Errorf("send hello: %w", err)
+	}
+	return nil
+}
+
+func readHello(r io.Reader) (hello, error) {
+	var h hello
+	t, payload, err := readFrame(r)
+	if err != nil {
+		return h, fmt.Errorf("read hello: %w", err)
+	}
+	if t != frameHello {
+		return h, fmt.Errorf("unexpected frame type: %s", t)
+	}
+
+	if err := json.Unmarshal(payload, &h); err != nil {
+		return h, fmt.Errorf("unmarshal hello: %w", err)
+	}
+	return h, nil
+}
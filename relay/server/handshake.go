@@ -19,6 +19,12 @@ type Validator interface {
 	ValidateHelloMsgType(any) error
 }
 
+// AccountResolver is implemented by the validators which can tell the account of the peer from the auth payload.
+// The account is used to apply the per-account limits.
+type AccountResolver interface {
+	AccountID(any) string
+}
+
 // preparedMsg contains the marshalled success response messages
 type preparedMsg struct {
 	responseHelloMsg []byte
@@ -64,6 +70,7 @@ type handshake struct {
 
 	handshakeMethodAuth bool
 	peerID              *messages.PeerID
+	accountID           string
 }
 
 func (h *handshake) handshakeReceive() (*messages.PeerID, error) {
@@ -150,5 +157,9 @@ func (h *handshake) handleAuthMsg(buf []byte) (*messages.PeerID, error) {
 		return rawPeerID, fmt.Errorf("validate %s (%s): %w", rawPeerID.String(), h.conn.RemoteAddr(), err)
 	}
 
+	if resolver, ok := h.validator.(AccountResolver); ok {
+		h.accountID = resolver.AccountID(authPayload)
+	}
+
 	return rawPeerID, nil
 }
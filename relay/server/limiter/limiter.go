@@ -0,0 +1,278 @@
+// Package limiter implements the bandwidth limits and the daily traffic quotas of the relay server.
+//
+// Every peer has its own token bucket and daily quota, and the peers of the same account share an additional token
+// bucket and daily quota. The token buckets delay the messages of the peer, the exhausted quotas drop them until the
+// end of the day (UTC).
+package limiter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/netbirdio/netbird/shared/relay/messages"
+)
+
+// minBurst is the smallest bucket size, a single transport message must fit into the bucket
+const minBurst = messages.MaxMessageSize
+
+// ErrQuotaExceeded is returned when the message does not fit into the remaining daily quota
+var ErrQuotaExceeded = errors.New("daily quota exceeded")
+
+// Config holds the limits, a zero value disables the corresponding limit
+type Config struct {
+	// PeerRate is the bandwidth limit of a single peer in bytes per second
+	PeerRate int64
+	// PeerBurst is the number of bytes a peer can send at once, defaults to one second worth of PeerRate
+	PeerBurst int64
+	// AccountRate is the bandwidth limit shared by the peers of an account in bytes per second
+	AccountRate int64
+	// AccountBurst is the number of bytes the peers of an account can send at once, defaults to one second worth of
+	// AccountRate
+	AccountBurst int64
+	// PeerDailyQuota is the number of bytes a single peer can send per day
+	PeerDailyQuota int64
+	// AccountDailyQuota is the number of bytes the peers of an account can send per day
+	AccountDailyQuota int64
+}
+
+// Enabled returns true if any limit is configured
+func (c Config) Enabled() bool {
+	return c.PeerRate > 0 || c.AccountRate > 0 || c.PeerDailyQuota > 0 || c.AccountDailyQuota > 0
+}
+
+// Validate checks that the limits are not negative
+func (c Config) Validate() error {
+	for _, v := range []int64{c.PeerRate, c.PeerBurst, c.AccountRate, c.AccountBurst, c.PeerDailyQuota, c.AccountDailyQuota} {
+		if v < 0 {
+			return errors.New("limits must not be negative")
+		}
+	}
+	return nil
+}
+
+// Result describes how the limits affected a message
+type Result struct {
+	// Reason is the limit that delayed or dropped the message, unknown if the message was not affected
+	Reason messages.ThrottleReason
+	// Delay is the time the message was held back by the bandwidth limits
+	Delay time.Duration
+}
+
+// Throttled returns true if the message was delayed or dropped
+func (r Result) Throttled() bool {
+	return r.Reason != messages.ThrottleReasonUnknown
+}
+
+// Limiter keeps the state of the peers and the accounts. The state of the disconnected peers and accounts is kept
+// until the end of the day, so reconnecting does not reset the quotas.
+type Limiter struct {
+	cfg Config
+
+	mu       sync.Mutex
+	peers    map[string]*entry
+	accounts map[string]*entry
+	day      time.Time
+
+	// now is replaceable in tests
+	now func() time.Time
+}
+
+// New creates a new limiter with the given limits
+func New(cfg Config) *Limiter {
+	l := &Limiter{
+		cfg:      cfg,
+		peers:    make(map[string]*entry),
+		accounts: make(map[string]*entry),
+		now:      time.Now,
+	}
+	l.day = startOfDay(l.now())
+	return l
+}
+
+// NewPeerLimiter returns the limiter of a connected peer. The account ID can be empty, in this case only the peer
+// limits apply. Close must be called when the peer disconnects.
+func (l *Limiter) NewPeerLimiter(peerID, accountID string) *PeerLimiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.purgeUnused()
+
+	p := &PeerLimiter{
+		limiter:   l,
+		accountID: accountID,
+		peer:      l.acquire(l.peers, peerID, l.cfg.PeerRate, l.cfg.PeerBurst, l.cfg.PeerDailyQuota),
+	}
+	if accountID != "" {
+		p.account = l.acquire(l.accounts, accountID, l.cfg.AccountRate, l.cfg.AccountBurst, l.cfg.AccountDailyQuota)
+	}
+	return p
+}
+
+func (l *Limiter) acquire(entries map[string]*entry, key string, r, burst, quota int64) *entry {
+	e, ok := entries[key]
+	if !ok {
+		e = newEntry(r, burst, quota)
+		entries[key] = e
+	}
+	e.refs++
+	return e
+}
+
+func (l *Limiter) release(p *PeerLimiter) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	p.peer.refs--
+	if p.account != nil {
+		p.account.refs--
+	}
+}
+
+// purgeUnused drops the state of the disconnected peers and accounts after the day is over
+func (l *Limiter) purgeUnused() {
+	day := startOfDay(l.now())
+	if !day.After(l.day) {
+		return
+	}
+	l.day = day
+
+	for _, entries := range []map[string]*entry{l.peers, l.accounts} {
+		for key, e := range entries {
+			if e.refs == 0 {
+				delete(entries, key)
+			}
+		}
+	}
+}
+
+// PeerLimiter applies the limits to the messages of a single peer connection
+type PeerLimiter struct {
+	limiter   *Limiter
+	accountID string
+	peer      *entry
+	account   *entry
+	closeOnce sync.Once
+}
+
+// AccountID returns the account of the peer, empty if the peer has no account
+func (p *PeerLimiter) AccountID() string {
+	return p.accountID
+}
+
+// Wait accounts a message of n bytes. It blocks while the bandwidth limits hold the message back and returns
+// ErrQuotaExceeded if the message must be dropped. The result tells which limit affected the message.
+func (p *PeerLimiter) Wait(ctx context.Context, n int) (Result, error) {
+	now := p.limiter.now()
+
+	if !p.peer.quota.consume(now, n) {
+		return Result{Reason: messages.ThrottleReasonPeerQuota}, ErrQuotaExceeded
+	}
+	if p.account != nil && !p.account.quota.consume(now, n) {
+		p.peer.quota.refund(n)
+		return Result{Reason: messages.ThrottleReasonAccountQuota}, ErrQuotaExceeded
+	}
+
+	var result Result
+	if d := p.peer.reserve(now, n); d > 0 {
+		result = Result{Reason: messages.ThrottleReasonPeerRate, Delay: d}
+	}
+	if p.account != nil {
+		if d := p.account.reserve(now, n); d > result.Delay {
+			result = Result{Reason: messages.ThrottleReasonAccountRate, Delay: d}
+		}
+	}
+
+	if result.Delay == 0 {
+		return result, nil
+	}
+
+	timer := time.NewTimer(result.Delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return result, ctx.Err()
+	case <-timer.C:
+		return result, nil
+	}
+}
+
+// Close releases the peer, it is safe to call multiple times
+func (p *PeerLimiter) Close() {
+	p.closeOnce.Do(func() {
+		p.limiter.release(p)
+	})
+}
+
+// entry is the state of a peer or an account
+type entry struct {
+	refs   int
+	bucket *rate.Limiter
+	quota  *quota
+}
+
+func newEntry(r, burst, dailyQuota int64) *entry {
+	e := &entry{
+		quota: &quota{limit: dailyQuota},
+	}
+	if r > 0 {
+		if burst <= 0 {
+			burst = r
+		}
+		e.bucket = rate.NewLimiter(rate.Limit(r), int(max(burst, minBurst)))
+	}
+	return e
+}
+
+// reserve takes n tokens from the bucket and returns how long the caller has to wait for them
+func (e *entry) reserve(now time.Time, n int) time.Duration {
+	if e.bucket == nil {
+		return 0
+	}
+	return e.bucket.ReserveN(now, n).DelayFrom(now)
+}
+
+// quota counts the bytes of the current day (UTC)
+type quota struct {
+	mu    sync.Mutex
+	limit int64
+	used  int64
+	day   time.Time
+}
+
+func (q *quota) consume(now time.Time, n int) bool {
+	if q.limit <= 0 {
+		return true
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if day := startOfDay(now); day.After(q.day) {
+		q.day = day
+		q.used = 0
+	}
+
+	if q.used+int64(n) > q.limit {
+		return false
+	}
+	q.used += int64(n)
+	return true
+}
+
+func (q *quota) refund(n int) {
+	if q.limit <= 0 {
+		return
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.used -= int64(n)
+}
+
+func startOfDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
@@ -0,0 +1,139 @@
+package limiter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/shared/relay/messages"
+)
+
+func newTestLimiter(cfg Config, now *time.Time) *Limiter {
+	l := New(cfg)
+	l.now = func() time.Time { return *now }
+	l.day = startOfDay(*now)
+	return l
+}
+
+func TestPeerLimiter_NoLimits(t *testing.T) {
+	now := time.Now()
+	l := newTestLimiter(Config{}, &now)
+
+	p := l.NewPeerLimiter("peer", "account")
+	defer p.Close()
+
+	for i := 0; i < 100; i++ {
+		res, err := p.Wait(context.Background(), messages.MaxMessageSize)
+		require.NoError(t, err)
+		assert.False(t, res.Throttled())
+	}
+}
+
+func TestPeerLimiter_PeerRate(t *testing.T) {
+	now := time.Now()
+	l := newTestLimiter(Config{PeerRate: 10000, PeerBurst: 10000}, &now)
+
+	p := l.NewPeerLimiter("peer", "")
+	defer p.Close()
+
+	res, err := p.Wait(context.Background(), 10000)
+	require.NoError(t, err)
+	assert.False(t, res.Throttled(), "the burst must not be throttled")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	res, err = p.Wait(ctx, 5000)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, messages.ThrottleReasonPeerRate, res.Reason)
+	assert.InDelta(t, 500*time.Millisecond, res.Delay, float64(10*time.Millisecond))
+}
+
+func TestPeerLimiter_AccountRateIsShared(t *testing.T) {
+	now := time.Now()
+	l := newTestLimiter(Config{AccountRate: 10000, AccountBurst: 10000}, &now)
+
+	p1 := l.NewPeerLimiter("peer1", "account")
+	defer p1.Close()
+	p2 := l.NewPeerLimiter("peer2", "account")
+	defer p2.Close()
+	other := l.NewPeerLimiter("peer3", "other-account")
+	defer other.Close()
+
+	_, err := p1.Wait(context.Background(), 10000)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	res, err := p2.Wait(ctx, 5000)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, messages.ThrottleReasonAccountRate, res.Reason)
+
+	res, err = other.Wait(context.Background(), 5000)
+	require.NoError(t, err)
+	assert.False(t, res.Throttled(), "the peers of other accounts must not be throttled")
+}
+
+func TestPeerLimiter_PeerQuota(t *testing.T) {
+	now := time.Date(2026, 1, 1, 23, 0, 0, 0, time.UTC)
+	l := newTestLimiter(Config{PeerDailyQuota: 1000}, &now)
+
+	p := l.NewPeerLimiter("peer", "")
+	_, err := p.Wait(context.Background(), 800)
+	require.NoError(t, err)
+
+	res, err := p.Wait(context.Background(), 300)
+	assert.ErrorIs(t, err, ErrQuotaExceeded)
+	assert.Equal(t, messages.ThrottleReasonPeerQuota, res.Reason)
+
+	// reconnecting does not reset the quota
+	p.Close()
+	p = l.NewPeerLimiter("peer", "")
+	defer p.Close()
+	_, err = p.Wait(context.Background(), 300)
+	assert.ErrorIs(t, err, ErrQuotaExceeded)
+
+	// the quota is reset on the next day
+	now = now.Add(2 * time.Hour)
+	_, err = p.Wait(context.Background(), 300)
+	assert.NoError(t, err)
+}
+
+func TestPeerLimiter_AccountQuota(t *testing.T) {
+	now := time.Now()
+	l := newTestLimiter(Config{PeerDailyQuota: 1000, AccountDailyQuota: 1500}, &now)
+
+	p1 := l.NewPeerLimiter("peer1", "account")
+	defer p1.Close()
+	p2 := l.NewPeerLimiter("peer2", "account")
+	defer p2.Close()
+
+	_, err := p1.Wait(context.Background(), 1000)
+	require.NoError(t, err)
+
+	res, err := p2.Wait(context.Background(), 600)
+	assert.ErrorIs(t, err, ErrQuotaExceeded)
+	assert.Equal(t, messages.ThrottleReasonAccountQuota, res.Reason)
+
+	// the dropped message is not counted against the peer quota
+	_, err = p2.Wait(context.Background(), 500)
+	assert.NoError(t, err)
+}
+
+func TestLimiter_PurgeUnused(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	l := newTestLimiter(Config{PeerDailyQuota: 1000}, &now)
+
+	connected := l.NewPeerLimiter("connected", "account")
+	defer connected.Close()
+	l.NewPeerLimiter("disconnected", "").Close()
+
+	now = now.Add(24 * time.Hour)
+	l.NewPeerLimiter("new", "").Close()
+
+	assert.Contains(t, l.peers, "connected")
+	assert.Contains(t, l.accounts, "account")
+	assert.NotContains(t, l.peers, "disconnected")
+}
@@ -10,6 +10,7 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/netbirdio/netbird/relay/metrics"
+	"github.com/netbirdio/netbird/relay/server/limiter"
 	"github.com/netbirdio/netbird/relay/server/store"
 	"github.com/netbirdio/netbird/shared/relay/healthcheck"
 	"github.com/netbirdio/netbird/shared/relay/messages"
@@ -19,8 +20,17 @@ const (
 	bufferSize = messages.MaxMessageSize
 
 	errCloseConn = "failed to close connection to peer: %s"
+
+	// throttleNotifyInterval limits how often the peer is notified about the same throttle reason
+	throttleNotifyInterval = 30 * time.Second
 )
 
+// transportPeer is a local or a remote peer the transport messages can be written to
+type transportPeer interface {
+	store.IPeer
+	Write(b []byte) (int, error)
+}
+
 // Peer represents a peer connection
 type Peer struct {
 	metrics  *metrics.Metrics
@@ -30,6 +40,10 @@ type Peer struct {
 	connMu   sync.RWMutex
 	store    *store.Store
 	notifier *store.PeerNotifier
+	// limiter is nil if no limits are configured
+	limiter *limiter.PeerLimiter
+	// throttleNotified holds the last time the peer was notified about a throttle reason, used by the read loop only
+	throttleNotified map[messages.ThrottleReason]time.Time
 
 	peersListener *store.Listener
 
@@ -38,14 +52,16 @@ type Peer struct {
 }
 
 // NewPeer creates a new Peer instance and prepare custom logging
-func NewPeer(metrics *metrics.Metrics, id messages.PeerID, conn net.Conn, store *store.Store, notifier *store.PeerNotifier) *Peer {
+func NewPeer(metrics *metrics.Metrics, id messages.PeerID, conn net.Conn, store *store.Store, notifier *store.PeerNotifier, limiter *limiter.PeerLimiter) *Peer {
 	p := &Peer{
-		metrics:  metrics,
-		log:      log.WithField("peer_id", id.String()),
-		id:       id,
-		conn:     conn,
-		store:    store,
-		notifier: notifier,
+		metrics:          metrics,
+		log:              log.WithField("peer_id", id.String()),
+		id:               id,
+		conn:             conn,
+		store:            store,
+		notifier:         notifier,
+		limiter:          limiter,
+		throttleNotified: make(map[messages.ThrottleReason]time.Time),
 	}
 
 	return p
@@ -58,6 +74,9 @@ func (p *Peer) Work() {
 	p.peersListener = p.notifier.NewListener(p.sendPeersOnline, p.sendPeersWentOffline)
 	defer func() {
 		p.notifier.RemoveListener(p.peersListener)
+		if p.limiter != nil {
+			p.limiter.Close()
+		}
 
 		if err := p.conn.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
 			p.log.Errorf(errCloseConn, err)
@@ -115,6 +134,9 @@ func (p *Peer) handleMsgType(ctx context.Context, msgType messages.MsgType, hc *
 	case messages.MsgTypeTransport:
 		p.metrics.TransferBytesRecv.Add(ctx, int64(n))
 		p.metrics.PeerActivity(p.String())
+		if !p.applyLimits(ctx, n) {
+			return
+		}
 		p.handleTransportMsg(msg)
 	case messages.MsgTypeClose:
 		p.log.Infof("peer exited gracefully")
@@ -215,12 +237,21 @@ func (p *Peer) handleTransportMsg(msg []byte) {
 		return
 	}
 
+	local := true
 	item, ok := p.store.Peer(*peerID)
+	if !ok {
+		local = false
+		item, ok = p.store.RemotePeer(*peerID)
+	}
 	if !ok {
 		p.log.Debugf("peer not found: %s", peerID)
 		return
 	}
-	dp := item.(*Peer)
+	dp, ok := item.(transportPeer)
+	if !ok {
+		p.log.Errorf("peer %s does not support transport messages", peerID)
+		return
+	}
 
 	err = messages.UpdateTransportMsg(msg, p.id)
 	if err != nil {
@@ -230,10 +261,52 @@ func (p *Peer) handleTransportMsg(msg []byte) {
 
 	n, err := dp.Write(msg)
 	if err != nil {
-		p.log.Errorf("failed to write transport message to: %s", dp.String())
+		p.log.Errorf("failed to write transport message to: %s", dp.ID())
+		return
+	}
+
+	// the traffic forwarded to the sibling relay instances is recorded by the cluster
+	if local {
+		p.metrics.TransferBytesSent.Add(context.Background(), int64(n))
+	}
+}
+
+// applyLimits holds back the message while the bandwidth limits require it and returns false if the message must be
+// dropped
+func (p *Peer) applyLimits(ctx context.Context, n int) bool {
+	if p.limiter == nil {
+		return true
+	}
+
+	result, err := p.limiter.Wait(ctx, n)
+	if result.Throttled() {
+		p.notifyThrottled(result.Reason)
+	}
+
+	switch {
+	case errors.Is(err, limiter.ErrQuotaExceeded):
+		p.metrics.RecordQuotaDropped(throttleReasonLabel(result.Reason), n)
+		return false
+	case err != nil:
+		return false
+	case result.Throttled():
+		p.metrics.RecordThrottled(throttleReasonLabel(result.Reason), n, result.Delay)
+	}
+	return true
+}
+
+// notifyThrottled lets the client know why its throughput is limited. The notifications are rate limited per reason.
+func (p *Peer) notifyThrottled(reason messages.ThrottleReason) {
+	now := time.Now()
+	if last, ok := p.throttleNotified[reason]; ok && now.Sub(last) < throttleNotifyInterval {
 		return
 	}
-	p.metrics.TransferBytesSent.Add(context.Background(), int64(n))
+	p.throttleNotified[reason] = now
+
+	p.log.Debugf("traffic of the peer is limited: %s", reason)
+	if _, err := p.Write(messages.MarshalThrottledMsg(reason)); err != nil {
+		p.log.Errorf("failed to send throttled message: %s", err)
+	}
 }
 
 func (p *Peer) handleSubscribePeerState(msg []byte) {
@@ -298,3 +371,18 @@ func (p *Peer) sendPeersWentOffline(peers []messages.PeerID) {
 		}
 	}
 }
+
+func throttleReasonLabel(reason messages.ThrottleReason) string {
+	switch reason {
+	case messages.ThrottleReasonPeerRate:
+		return "peer_rate"
+	case messages.ThrottleReasonAccountRate:
+		return "account_rate"
+	case messages.ThrottleReasonPeerQuota:
+		return "peer_quota"
+	case messages.ThrottleReasonAccountQuota:
+		return "account_quota"
+	default:
+		return "unknown"
+	}
+}
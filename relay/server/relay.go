@@ -15,7 +15,10 @@ import (
 	"github.com/netbirdio/netbird/relay/healthcheck/peerid"
 	//nolint:staticcheck
 	"github.com/netbirdio/netbird/relay/metrics"
+	"github.com/netbirdio/netbird/relay/server/cluster"
+	"github.com/netbirdio/netbird/relay/server/limiter"
 	"github.com/netbirdio/netbird/relay/server/store"
+	"github.com/netbirdio/netbird/shared/relay/messages"
 )
 
 type Config struct {
@@ -23,6 +26,10 @@ type Config struct {
 	ExposedAddress string
 	TLSSupport     bool
 	AuthValidator  Validator
+	// Cluster connects the relay with its sibling instances, nil if the relay runs standalone
+	Cluster *cluster.Config
+	// Limits are the bandwidth limits and daily quotas of the peers and accounts, the zero value disables them
+	Limits limiter.Config
 
 	instanceURL url.URL
 }
@@ -44,6 +51,10 @@ func (c *Config) validate() error {
 	if c.AuthValidator == nil {
 		return fmt.Errorf("auth validator is required")
 	}
+
+	if err := c.Limits.Validate(); err != nil {
+		return fmt.Errorf("invalid limits: %v", err)
+	}
 	return nil
 }
 
@@ -55,6 +66,8 @@ type Relay struct {
 
 	store          *store.Store
 	notifier       *store.PeerNotifier
+	cluster        *cluster.Cluster
+	limiter        *limiter.Limiter
 	instanceURL    url.URL
 	exposedAddress string
 	preparedMsg    *preparedMsg
@@ -72,6 +85,8 @@ type Relay struct {
 //	  - ExposedAddress: The external address clients use to reach this relay. Required.
 //	  - TLSSupport: A boolean indicating if the relay uses TLS. Affects the generated instance URL.
 //	  - AuthValidator: A Validator implementation used to authenticate peers. Required.
+//	  - Cluster: An optional cluster configuration to reach the peers connected to sibling relay instances.
+//	  - Limits: Optional bandwidth limits and daily quotas of the peers and accounts.
 //
 // Returns:
 //
@@ -99,12 +114,28 @@ func NewRelay(config Config) (*Relay, error) {
 		notifier:       store.NewPeerNotifier(),
 	}
 
+	if config.Limits.Enabled() {
+		r.limiter = limiter.New(config.Limits)
+	}
+
 	r.preparedMsg, err = newPreparedMsg(r.instanceURL.String())
 	if err != nil {
 		metricsCancel()
 		return nil, fmt.Errorf("prepare message: %v", err)
 	}
 
+	if config.Cluster != nil {
+		r.cluster, err = cluster.New(*config.Cluster, r.instanceURL.String(), r.store, r.notifier, m)
+		if err != nil {
+			metricsCancel()
+			return nil, fmt.Errorf("create cluster: %v", err)
+		}
+		if err := r.cluster.Start(); err != nil {
+			metricsCancel()
+			return nil, fmt.Errorf("start cluster: %v", err)
+		}
+	}
+
 	return r, nil
 }
 
@@ -135,20 +166,28 @@ func (r *Relay) Accept(conn net.Conn) {
 		return
 	}
 
-	peer := NewPeer(r.metrics, *peerID, conn, r.store, r.notifier)
+	var peerLimiter *limiter.PeerLimiter
+	if r.limiter != nil {
+		peerLimiter = r.limiter.NewPeerLimiter(peerID.String(), h.accountID)
+	}
+
+	peer := NewPeer(r.metrics, *peerID, conn, r.store, r.notifier, peerLimiter)
 	peer.log.Infof("peer connected from: %s", conn.RemoteAddr())
 	storeTime := time.Now()
 	if isReconnection := r.store.AddPeer(peer); isReconnection {
 		r.metrics.RecordPeerReconnection()
 	}
 	r.notifier.PeerCameOnline(peer.ID())
+	if r.cluster != nil {
+		r.cluster.PeerCameOnline(peer.ID())
+	}
 
 	r.metrics.RecordPeerStoreTime(time.Since(storeTime))
 	r.metrics.PeerConnected(peer.String())
 	go func() {
 		peer.Work()
 		if deleted := r.store.DeletePeer(peer); deleted {
-			r.notifier.PeerWentOffline(peer.ID())
+			r.peerWentOffline(peer.ID())
 		}
 		peer.log.Debugf("relay connection closed")
 		r.metrics.PeerDisconnected(peer.String())
@@ -178,10 +217,25 @@ func (r *Relay) Shutdown(ctx context.Context) {
 		}(v.(*Peer))
 	}
 	wg.Wait()
+	if r.cluster != nil {
+		r.cluster.Close()
+	}
 	r.metricsCancel()
 	r.closed = true
 }
 
+func (r *Relay) peerWentOffline(peerID messages.PeerID) {
+	if r.cluster != nil {
+		r.cluster.PeerWentOffline(peerID)
+	}
+
+	// the peer can be still reachable through a sibling instance, e.g. after it has moved to another instance
+	if _, ok := r.store.RemotePeer(peerID); ok {
+		return
+	}
+	r.notifier.PeerWentOffline(peerID)
+}
+
 // InstanceURL returns the instance URL of the relay server
 func (r *Relay) InstanceURL() url.URL {
 	return r.instanceURL
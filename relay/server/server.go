@@ -43,6 +43,7 @@ type Server struct {
 //	  - InstanceURL: The public address (in domain:port format) used as the server's instance URL. Required.
 //	  - TLSSupport: A boolean indicating whether TLS is enabled for the server.
 //	  - AuthValidator: A Validator used to authenticate peers. Required.
+//	  - Cluster: An optional cluster configuration to reach the peers connected to sibling relay instances.
 //
 // Returns:
 //
@@ -14,14 +14,17 @@ type IPeer interface {
 // Store is a thread-safe store of peers
 // It is used to store the peers that are connected to the relay server
 type Store struct {
-	peers     map[messages.PeerID]IPeer
-	peersLock sync.RWMutex
+	peers map[messages.PeerID]IPeer
+	// remotePeers are the peers connected to sibling relay instances of the cluster
+	remotePeers map[messages.PeerID]IPeer
+	peersLock   sync.RWMutex
 }
 
 // NewStore creates a new Store instance
 func NewStore() *Store {
 	return &Store{
-		peers: make(map[messages.PeerID]IPeer),
+		peers:       make(map[messages.PeerID]IPeer),
+		remotePeers: make(map[messages.PeerID]IPeer),
 	}
 }
 
@@ -90,8 +93,50 @@ func (s *Store) GetOnlinePeersAndRegisterInterest(peerIDs []messages.PeerID, lis
 	for _, id := range peerIDs {
 		if _, ok := s.peers[id]; ok {
 			onlinePeers = append(onlinePeers, id)
+			continue
+		}
+		if _, ok := s.remotePeers[id]; ok {
+			onlinePeers = append(onlinePeers, id)
 		}
 	}
 
 	return onlinePeers
 }
+
+// AddRemotePeer adds a peer connected to a sibling relay instance
+// If the peer already exists, it will be replaced.
+// Returns true if the peer was not online before, neither locally nor remotely.
+func (s *Store) AddRemotePeer(peer IPeer) bool {
+	s.peersLock.Lock()
+	defer s.peersLock.Unlock()
+
+	_, local := s.peers[peer.ID()]
+	_, remote := s.remotePeers[peer.ID()]
+	s.remotePeers[peer.ID()] = peer
+	return !local && !remote
+}
+
+// DeleteRemotePeer deletes a peer connected to a sibling relay instance
+// Returns true if the peer was deleted and it is not connected locally.
+func (s *Store) DeleteRemotePeer(peer IPeer) bool {
+	s.peersLock.Lock()
+	defer s.peersLock.Unlock()
+
+	dp, ok := s.remotePeers[peer.ID()]
+	if !ok || dp != peer {
+		return false
+	}
+
+	delete(s.remotePeers, peer.ID())
+	_, local := s.peers[peer.ID()]
+	return !local
+}
+
+// RemotePeer returns a peer connected to a sibling relay instance by its ID
+func (s *Store) RemotePeer(id messages.PeerID) (IPeer, bool) {
+	s.peersLock.RLock()
+	defer s.peersLock.RUnlock()
+
+	p, ok := s.remotePeers[id]
+	return p, ok
+}
@@ -1,6 +1,7 @@
 package store
 
 import (
+	"context"
 	"testing"
 
 	"github.com/netbirdio/netbird/shared/relay/messages"
@@ -47,3 +48,27 @@ func TestStore_DeleteDeprecatedPeer(t *testing.T) {
 		t.Errorf("second peer was deleted")
 	}
 }
+
+func TestStore_RemotePeer(t *testing.T) {
+	s := NewStore()
+
+	pID := messages.HashID("peer_one")
+	remote := &MocPeer{id: pID}
+	if cameOnline := s.AddRemotePeer(remote); !cameOnline {
+		t.Errorf("remote peer should come online")
+	}
+
+	listener := newListener(context.Background())
+	if online := s.GetOnlinePeersAndRegisterInterest([]messages.PeerID{pID}, listener); len(online) != 1 {
+		t.Errorf("remote peer should be reported as online")
+	}
+
+	local := &MocPeer{id: pID}
+	s.AddPeer(local)
+	if wentOffline := s.DeleteRemotePeer(remote); wentOffline {
+		t.Errorf("peer is still connected locally, it should not go offline")
+	}
+	if _, ok := s.RemotePeer(pID); ok {
+		t.Errorf("remote peer was not deleted")
+	}
+}
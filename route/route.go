@@ -5,6 +5,7 @@ import (
 	"net/netip"
 	"slices"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/netbirdio/netbird/shared/management/domain"
 	"github.com/netbirdio/netbird/shared/management/status"
@@ -120,6 +121,31 @@ func (r *Route) EventMeta() map[string]any {
 	return map[string]any{"name": r.NetID, "network_range": r.Network.String(), "domains": domains, "peer_id": r.Peer, "peer_groups": r.PeerGroups}
 }
 
+// Validate validates the settings of the route that don't depend on the other objects of the account
+func (r *Route) Validate() error {
+	if r.Metric < MinMetric || r.Metric > MaxMetric {
+		return status.Errorf(status.InvalidArgument, "metric should be between %d and %d", MinMetric, MaxMetric)
+	}
+
+	if utf8.RuneCountInString(string(r.NetID)) > MaxNetIDChar || r.NetID == "" {
+		return status.Errorf(status.InvalidArgument, "identifier should be between 1 and %d", MaxNetIDChar)
+	}
+
+	if len(r.Domains) > 0 && r.Network.IsValid() {
+		return status.Errorf(status.InvalidArgument, "domains and network should not be provided at the same time")
+	}
+
+	if len(r.Domains) == 0 && !r.Network.IsValid() {
+		return status.Errorf(status.InvalidArgument, "invalid Prefix")
+	}
+
+	if r.Peer != "" && len(r.PeerGroups) != 0 {
+		return status.Errorf(status.InvalidArgument, "peer with ID and peer groups should not be provided at the same time")
+	}
+
+	return nil
+}
+
 // Copy copies a route object
 func (r *Route) Copy() *Route {
 	route := &Route{
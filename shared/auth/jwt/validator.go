@@ -61,6 +61,17 @@ type Validator struct {
 	idpSignkeyRefreshEnabled bool
 	keys                     *Jwks
 	lastForcedRefresh        time.Time
+	httpClient               *http.Client
+}
+
+// ValidatorOption configures optional settings of the Validator
+type ValidatorOption func(*Validator)
+
+// WithHTTPClient sets the client fetching the keys, http.DefaultClient is used by default
+func WithHTTPClient(client *http.Client) ValidatorOption {
+	return func(v *Validator) {
+		v.httpClient = client
+	}
 }
 
 var (
@@ -70,19 +81,25 @@ var (
 	errTokenParsing = errors.New("token could not be parsed")
 )
 
-func NewValidator(issuer string, audienceList []string, keysLocation string, idpSignkeyRefreshEnabled bool) *Validator {
-	keys, err := getPemKeys(keysLocation)
-	if err != nil {
-		log.WithField("keysLocation", keysLocation).Errorf("could not get keys from location: %s", err)
-	}
-
-	return &Validator{
-		keys:                     keys,
+func NewValidator(issuer string, audienceList []string, keysLocation string, idpSignkeyRefreshEnabled bool, opts ...ValidatorOption) *Validator {
+	v := &Validator{
 		issuer:                   issuer,
 		audienceList:             audienceList,
 		keysLocation:             keysLocation,
 		idpSignkeyRefreshEnabled: idpSignkeyRefreshEnabled,
+		httpClient:               http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(v)
 	}
+
+	keys, err := getPemKeys(v.httpClient, keysLocation)
+	if err != nil {
+		log.WithField("keysLocation", keysLocation).Errorf("could not get keys from location: %s", err)
+	}
+	v.keys = keys
+
+	return v
 }
 
 // forcedRefreshCooldown is the minimum time between forced key refreshes
@@ -131,7 +148,7 @@ func (v *Validator) refreshKeys(ctx context.Context) {
 	v.lock.Lock()
 	defer v.lock.Unlock()
 
-	refreshedKeys, err := getPemKeys(v.keysLocation)
+	refreshedKeys, err := getPemKeys(v.httpClient, v.keysLocation)
 	if err != nil {
 		log.WithContext(ctx).Debugf("cannot get JSONWebKey: %v, falling back to old keys", err)
 		return
@@ -155,7 +172,7 @@ func (v *Validator) forceRefreshKeys(ctx context.Context) bool {
 
 	log.WithContext(ctx).Debugf("key not found in cache, forcing JWKS refresh")
 
-	refreshedKeys, err := getPemKeys(v.keysLocation)
+	refreshedKeys, err := getPemKeys(v.httpClient, v.keysLocation)
 	if err != nil {
 		log.WithContext(ctx).Debugf("cannot get JSONWebKey: %v, falling back to old keys", err)
 		return false
@@ -206,7 +223,7 @@ func (jwks *Jwks) stillValid() bool {
 	return !jwks.expiresInTime.IsZero() && time.Now().Add(5*time.Second).Before(jwks.expiresInTime)
 }
 
-func getPemKeys(keysLocation string) (*Jwks, error) {
+func getPemKeys(client *http.Client, keysLocation string) (*Jwks, error) {
 	jwks := &Jwks{}
 
 	requestURI, err := url.ParseRequestURI(keysLocation)
@@ -214,7 +231,7 @@ func getPemKeys(keysLocation string) (*Jwks, error) {
 		return jwks, err
 	}
 
-	resp, err := http.Get(requestURI.String())
+	resp, err := client.Get(requestURI.String())
 	if err != nil {
 		return jwks, err
 	}
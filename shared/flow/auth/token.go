@@ -0,0 +1,55 @@
+// Package auth implements the tokens the peers authenticate with at the flow collector. The management server
+// issues the tokens in the flow configuration of the peers and the collector validates them with the shared secret.
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// payloadSeparator separates the expiry time and the peer key in the token payload, base64 has no colons
+const payloadSeparator = ":"
+
+// GenerateToken generates a time based HMAC-SHA256 token for the peer with the WireGuard public key peerKey. The
+// payload is "<unix expiry time>:<base64 peer public key>", the signature the base64 encoded HMAC of the payload.
+func GenerateToken(secret, peerKey string, ttl time.Duration) (payload, signature string, err error) {
+	if peerKey == "" {
+		return "", "", errors.New("peer key is required")
+	}
+	payload = strconv.FormatInt(time.Now().Add(ttl).Unix(), 10) + payloadSeparator + peerKey
+	return payload, sign([]byte(secret), payload), nil
+}
+
+// ValidateToken validates a token generated with the same secret and returns the peer key it was issued for
+func ValidateToken(secret, payload, signature string) (string, error) {
+	if !hmac.Equal([]byte(signature), []byte(sign([]byte(secret), payload))) {
+		return "", errors.New("signature mismatch")
+	}
+
+	expiry, peerKey, ok := strings.Cut(payload, payloadSeparator)
+	if !ok || peerKey == "" {
+		return "", errors.New("invalid payload: missing peer key")
+	}
+
+	expiresAt, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid payload: %w", err)
+	}
+	if time.Now().Unix() > expiresAt {
+		return "", errors.New("expired token")
+	}
+
+	return peerKey, nil
+}
+
+func sign(secret []byte, payload string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
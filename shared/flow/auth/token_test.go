@@ -0,0 +1,37 @@
+package auth
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "test-secret"
+
+var testPeerKey = base64.StdEncoding.EncodeToString(make([]byte, 32))
+
+func TestToken(t *testing.T) {
+	payload, signature, err := GenerateToken(testSecret, testPeerKey, time.Hour)
+	require.NoError(t, err)
+	peerKey, err := ValidateToken(testSecret, payload, signature)
+	require.NoError(t, err)
+	assert.Equal(t, testPeerKey, peerKey)
+
+	_, err = ValidateToken("other-secret", payload, signature)
+	assert.Error(t, err, "token must not be valid for another secret")
+
+	_, err = ValidateToken(testSecret, payload[:strings.Index(payload, ":")], signature)
+	assert.Error(t, err, "token must not be valid for another payload")
+
+	payload, signature, err = GenerateToken(testSecret, testPeerKey, -time.Hour)
+	require.NoError(t, err)
+	_, err = ValidateToken(testSecret, payload, signature)
+	assert.Error(t, err, "expired token must be rejected")
+
+	_, _, err = GenerateToken(testSecret, "", time.Hour)
+	assert.Error(t, err)
+}
@@ -570,6 +570,12 @@ func infoToMetaData(info *system.Info) *proto.PeerSystemMeta {
 			Platform: info.Environment.Platform,
 		},
 		Files: files,
+		DiskEncryption: &proto.DiskEncryption{
+			EncryptedVolumes: info.DiskEncryption.EncryptedVolumes,
+		},
+		OsFirewall: &proto.OSFirewall{
+			ActiveBackends: info.OSFirewall.ActiveBackends,
+		},
 
 		Flags: &proto.Flags{
 			RosenpassEnabled:    info.RosenpassEnabled,
@@ -584,6 +590,7 @@ func infoToMetaData(info *system.Info) *proto.PeerSystemMeta {
 			BlockInbound:        info.BlockInbound,
 
 			LazyConnectionEnabled: info.LazyConnectionEnabled,
+			Ipv6Supported:         info.IPv6Supported,
 		},
 	}
 }
@@ -0,0 +1,61 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/netbirdio/netbird/shared/management/http/api"
+)
+
+// AccountConfigAPI APIs for the declarative account configuration, do not use directly
+type AccountConfigAPI struct {
+	c *Client
+}
+
+// Export returns the account configuration document
+func (a *AccountConfigAPI) Export(ctx context.Context) (*api.AccountConfigDocument, error) {
+	resp, err := a.c.NewRequest(ctx, "GET", "/api/account-config", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Body != nil {
+		defer resp.Body.Close()
+	}
+	ret, err := parseResponse[api.AccountConfigDocument](resp)
+	return &ret, err
+}
+
+// Plan returns the changes required to make the account configuration match the document
+func (a *AccountConfigAPI) Plan(ctx context.Context, request api.PostApiAccountConfigPlanJSONRequestBody) (*api.AccountConfigChanges, error) {
+	requestBytes, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := a.c.NewRequest(ctx, "POST", "/api/account-config/plan", bytes.NewReader(requestBytes), nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Body != nil {
+		defer resp.Body.Close()
+	}
+	ret, err := parseResponse[api.AccountConfigChanges](resp)
+	return &ret, err
+}
+
+// Apply makes the account configuration match the document and returns the applied changes
+func (a *AccountConfigAPI) Apply(ctx context.Context, request api.PostApiAccountConfigApplyJSONRequestBody) (*api.AccountConfigChanges, error) {
+	requestBytes, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := a.c.NewRequest(ctx, "POST", "/api/account-config/apply", bytes.NewReader(requestBytes), nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Body != nil {
+		defer resp.Body.Close()
+	}
+	ret, err := parseResponse[api.AccountConfigChanges](resp)
+	return &ret, err
+}
@@ -0,0 +1,116 @@
+//go:build integration
+// +build integration
+
+package rest_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/shared/management/client/rest"
+	"github.com/netbirdio/netbird/shared/management/http/api"
+	"github.com/netbirdio/netbird/shared/management/http/util"
+)
+
+var (
+	testAccountConfigDocument = api.AccountConfigDocument{
+		Version: "v1",
+		Groups:  &[]api.AccountConfigGroup{{Name: "devs"}},
+	}
+
+	testAccountConfigChanges = api.AccountConfigChanges{
+		Changes: []api.AccountConfigChange{
+			{Kind: api.AccountConfigChangeKindGroup, Action: api.AccountConfigChangeActionCreate, Name: "devs"},
+		},
+	}
+)
+
+func TestAccountConfig_Export_200(t *testing.T) {
+	withMockClient(func(c *rest.Client, mux *http.ServeMux) {
+		mux.HandleFunc("/api/account-config", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "GET", r.Method)
+			retBytes, _ := json.Marshal(testAccountConfigDocument)
+			_, err := w.Write(retBytes)
+			require.NoError(t, err)
+		})
+		ret, err := c.AccountConfig.Export(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, testAccountConfigDocument, *ret)
+	})
+}
+
+func TestAccountConfig_Export_Err(t *testing.T) {
+	withMockClient(func(c *rest.Client, mux *http.ServeMux) {
+		mux.HandleFunc("/api/account-config", func(w http.ResponseWriter, r *http.Request) {
+			retBytes, _ := json.Marshal(util.ErrorResponse{Message: "No", Code: 400})
+			w.WriteHeader(400)
+			_, err := w.Write(retBytes)
+			require.NoError(t, err)
+		})
+		ret, err := c.AccountConfig.Export(context.Background())
+		assert.Error(t, err)
+		assert.Equal(t, "No", err.Error())
+		assert.Nil(t, ret)
+	})
+}
+
+func TestAccountConfig_Plan_200(t *testing.T) {
+	withMockClient(func(c *rest.Client, mux *http.ServeMux) {
+		mux.HandleFunc("/api/account-config/plan", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "POST", r.Method)
+			reqBytes, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			var req api.PostApiAccountConfigPlanJSONRequestBody
+			err = json.Unmarshal(reqBytes, &req)
+			require.NoError(t, err)
+			assert.Equal(t, testAccountConfigDocument, req)
+			retBytes, _ := json.Marshal(testAccountConfigChanges)
+			_, err = w.Write(retBytes)
+			require.NoError(t, err)
+		})
+		ret, err := c.AccountConfig.Plan(context.Background(), testAccountConfigDocument)
+		require.NoError(t, err)
+		assert.Equal(t, testAccountConfigChanges, *ret)
+	})
+}
+
+func TestAccountConfig_Apply_200(t *testing.T) {
+	withMockClient(func(c *rest.Client, mux *http.ServeMux) {
+		mux.HandleFunc("/api/account-config/apply", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "POST", r.Method)
+			reqBytes, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			var req api.PostApiAccountConfigApplyJSONRequestBody
+			err = json.Unmarshal(reqBytes, &req)
+			require.NoError(t, err)
+			assert.Equal(t, testAccountConfigDocument, req)
+			retBytes, _ := json.Marshal(testAccountConfigChanges)
+			_, err = w.Write(retBytes)
+			require.NoError(t, err)
+		})
+		ret, err := c.AccountConfig.Apply(context.Background(), testAccountConfigDocument)
+		require.NoError(t, err)
+		assert.Equal(t, testAccountConfigChanges, *ret)
+	})
+}
+
+func TestAccountConfig_Apply_Err(t *testing.T) {
+	withMockClient(func(c *rest.Client, mux *http.ServeMux) {
+		mux.HandleFunc("/api/account-config/apply", func(w http.ResponseWriter, r *http.Request) {
+			retBytes, _ := json.Marshal(util.ErrorResponse{Message: "No", Code: 400})
+			w.WriteHeader(400)
+			_, err := w.Write(retBytes)
+			require.NoError(t, err)
+		})
+		ret, err := c.AccountConfig.Apply(context.Background(), testAccountConfigDocument)
+		assert.Error(t, err)
+		assert.Equal(t, "No", err.Error())
+		assert.Nil(t, ret)
+	})
+}
@@ -73,6 +73,9 @@ type Client struct {
 	// Events NetBird Events APIs
 	// see more: https://docs.netbird.io/api/resources/events
 	Events *EventsAPI
+
+	// AccountConfig NetBird declarative account configuration APIs
+	AccountConfig *AccountConfigAPI
 }
 
 // New initialize new Client instance using PAT token
@@ -120,6 +123,7 @@ func (c *Client) initialize() {
 	c.DNSZones = &DNSZonesAPI{c}
 	c.GeoLocation = &GeoLocationAPI{c}
 	c.Events = &EventsAPI{c}
+	c.AccountConfig = &AccountConfigAPI{c}
 }
 
 // NewRequest creates and executes new management API request
@@ -106,3 +106,17 @@ func (a *PeersAPI) ListAccessiblePeers(ctx context.Context, peerID string) ([]ap
 	ret, err := parseResponse[[]api.Peer](resp)
 	return ret, err
 }
+
+// ListPostureCheckResults evaluates the posture checks applied to the peer and returns the results
+// See more: https://docs.netbird.io/api/resources/peers#list-peer-posture-check-results
+func (a *PeersAPI) ListPostureCheckResults(ctx context.Context, peerID string) ([]api.PeerPostureCheckResult, error) {
+	resp, err := a.c.NewRequest(ctx, "GET", "/api/peers/"+peerID+"/posture-checks", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Body != nil {
+		defer resp.Body.Close()
+	}
+	ret, err := parseResponse[[]api.PeerPostureCheckResult](resp)
+	return ret, err
+}
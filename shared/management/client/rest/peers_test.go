@@ -177,6 +177,35 @@ func TestPeers_ListAccessiblePeers_Err(t *testing.T) {
 	})
 }
 
+func TestPeers_ListPostureCheckResults_200(t *testing.T) {
+	withMockClient(func(c *rest.Client, mux *http.ServeMux) {
+		mux.HandleFunc("/api/peers/Test/posture-checks", func(w http.ResponseWriter, r *http.Request) {
+			retBytes, _ := json.Marshal([]api.PeerPostureCheckResult{{Check: "OSVersionCheck", Reason: "OS version 13.1 < 14.0"}})
+			_, err := w.Write(retBytes)
+			require.NoError(t, err)
+		})
+		ret, err := c.Peers.ListPostureCheckResults(context.Background(), "Test")
+		require.NoError(t, err)
+		require.Len(t, ret, 1)
+		assert.Equal(t, "OS version 13.1 < 14.0", ret[0].Reason)
+	})
+}
+
+func TestPeers_ListPostureCheckResults_Err(t *testing.T) {
+	withMockClient(func(c *rest.Client, mux *http.ServeMux) {
+		mux.HandleFunc("/api/peers/Test/posture-checks", func(w http.ResponseWriter, r *http.Request) {
+			retBytes, _ := json.Marshal(util.ErrorResponse{Message: "No", Code: 400})
+			w.WriteHeader(400)
+			_, err := w.Write(retBytes)
+			require.NoError(t, err)
+		})
+		ret, err := c.Peers.ListPostureCheckResults(context.Background(), "Test")
+		assert.Error(t, err)
+		assert.Equal(t, "No", err.Error())
+		assert.Empty(t, ret)
+	})
+}
+
 func TestPeers_Integration(t *testing.T) {
 	withBlackBoxServer(t, func(c *rest.Client) {
 		peers, err := c.Peers.List(context.Background())
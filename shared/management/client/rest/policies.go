@@ -81,6 +81,40 @@ func (a *PoliciesAPI) Update(ctx context.Context, policyID string, request api.P
 	return &ret, err
 }
 
+// DryRunCreate get the peers whose network maps would change if the policy was created, without creating it
+func (a *PoliciesAPI) DryRunCreate(ctx context.Context, request api.PostApiPoliciesJSONRequestBody) (*api.PolicyDryRunResult, error) {
+	requestBytes, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := a.c.NewRequest(ctx, "POST", "/api/policies", bytes.NewReader(requestBytes), map[string]string{"dry_run": "true"})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Body != nil {
+		defer resp.Body.Close()
+	}
+	ret, err := parseResponse[api.PolicyDryRunResult](resp)
+	return &ret, err
+}
+
+// DryRunUpdate get the peers whose network maps would change if the policy was updated, without updating it
+func (a *PoliciesAPI) DryRunUpdate(ctx context.Context, policyID string, request api.PutApiPoliciesPolicyIdJSONRequestBody) (*api.PolicyDryRunResult, error) {
+	requestBytes, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := a.c.NewRequest(ctx, "PUT", "/api/policies/"+policyID, bytes.NewReader(requestBytes), map[string]string{"dry_run": "true"})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Body != nil {
+		defer resp.Body.Close()
+	}
+	ret, err := parseResponse[api.PolicyDryRunResult](resp)
+	return &ret, err
+}
+
 // Delete delete policy
 // See more: https://docs.netbird.io/api/resources/policies#delete-a-policy
 func (a *PoliciesAPI) Delete(ctx context.Context, policyID string) error {
@@ -94,3 +128,20 @@ func (a *PoliciesAPI) Delete(ctx context.Context, policyID string) error {
 
 	return nil
 }
+
+// Simulate check whether traffic between a peer and a peer or network resource is allowed by the policies
+func (a *PoliciesAPI) Simulate(ctx context.Context, request api.PostApiPoliciesSimulateJSONRequestBody) (*api.PolicySimulationResult, error) {
+	requestBytes, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := a.c.NewRequest(ctx, "POST", "/api/policies/simulate", bytes.NewReader(requestBytes), nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Body != nil {
+		defer resp.Body.Close()
+	}
+	ret, err := parseResponse[api.PolicySimulationResult](resp)
+	return &ret, err
+}
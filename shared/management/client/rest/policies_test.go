@@ -185,6 +185,84 @@ func TestPolicies_Delete_Err(t *testing.T) {
 	})
 }
 
+func TestPolicies_DryRunCreate_200(t *testing.T) {
+	withMockClient(func(c *rest.Client, mux *http.ServeMux) {
+		mux.HandleFunc("/api/policies", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "POST", r.Method)
+			assert.Equal(t, "true", r.URL.Query().Get("dry_run"))
+			retBytes, _ := json.Marshal(api.PolicyDryRunResult{Peers: []api.PolicyDryRunPeer{{PeerId: "peer1"}}})
+			_, err := w.Write(retBytes)
+			require.NoError(t, err)
+		})
+		ret, err := c.Policies.DryRunCreate(context.Background(), api.PostApiPoliciesJSONRequestBody{
+			Name: "weaw",
+		})
+		require.NoError(t, err)
+		require.Len(t, ret.Peers, 1)
+		assert.Equal(t, "peer1", ret.Peers[0].PeerId)
+	})
+}
+
+func TestPolicies_DryRunUpdate_200(t *testing.T) {
+	withMockClient(func(c *rest.Client, mux *http.ServeMux) {
+		mux.HandleFunc("/api/policies/Test", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "PUT", r.Method)
+			assert.Equal(t, "true", r.URL.Query().Get("dry_run"))
+			retBytes, _ := json.Marshal(api.PolicyDryRunResult{Peers: []api.PolicyDryRunPeer{{PeerId: "peer1"}}})
+			_, err := w.Write(retBytes)
+			require.NoError(t, err)
+		})
+		ret, err := c.Policies.DryRunUpdate(context.Background(), "Test", api.PutApiPoliciesPolicyIdJSONRequestBody{
+			Name: "weaw",
+		})
+		require.NoError(t, err)
+		require.Len(t, ret.Peers, 1)
+		assert.Equal(t, "peer1", ret.Peers[0].PeerId)
+	})
+}
+
+func TestPolicies_Simulate_200(t *testing.T) {
+	withMockClient(func(c *rest.Client, mux *http.ServeMux) {
+		mux.HandleFunc("/api/policies/simulate", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "POST", r.Method)
+			reqBytes, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			var req api.PostApiPoliciesSimulateJSONRequestBody
+			err = json.Unmarshal(reqBytes, &req)
+			require.NoError(t, err)
+			assert.Equal(t, "peer1", req.SourcePeerId)
+			retBytes, _ := json.Marshal(api.PolicySimulationResult{Allowed: true})
+			_, err = w.Write(retBytes)
+			require.NoError(t, err)
+		})
+		ret, err := c.Policies.Simulate(context.Background(), api.PostApiPoliciesSimulateJSONRequestBody{
+			SourcePeerId:      "peer1",
+			DestinationPeerId: ptr("peer2"),
+			Protocol:          api.PolicySimulationRequestProtocolTcp,
+			Port:              ptr(22),
+		})
+		require.NoError(t, err)
+		assert.True(t, ret.Allowed)
+	})
+}
+
+func TestPolicies_Simulate_Err(t *testing.T) {
+	withMockClient(func(c *rest.Client, mux *http.ServeMux) {
+		mux.HandleFunc("/api/policies/simulate", func(w http.ResponseWriter, r *http.Request) {
+			retBytes, _ := json.Marshal(util.ErrorResponse{Message: "No", Code: 400})
+			w.WriteHeader(400)
+			_, err := w.Write(retBytes)
+			require.NoError(t, err)
+		})
+		ret, err := c.Policies.Simulate(context.Background(), api.PostApiPoliciesSimulateJSONRequestBody{
+			SourcePeerId: "peer1",
+		})
+		assert.Error(t, err)
+		assert.Equal(t, "No", err.Error())
+		assert.Empty(t, ret)
+	})
+}
+
 func TestPolicies_Integration(t *testing.T) {
 	withBlackBoxServer(t, func(c *rest.Client) {
 		policies, err := c.Policies.List(context.Background())
@@ -12,6 +12,74 @@ const (
 	TokenAuthScopes  = "TokenAuth.Scopes"
 )
 
+// Defines values for AccessRequestStatus.
+const (
+	AccessRequestStatusApproved AccessRequestStatus = "approved"
+	AccessRequestStatusDenied   AccessRequestStatus = "denied"
+	AccessRequestStatusExpired  AccessRequestStatus = "expired"
+	AccessRequestStatusPending  AccessRequestStatus = "pending"
+)
+
+// Defines values for AccessRequestTargetType.
+const (
+	AccessRequestTargetTypeGroup  AccessRequestTargetType = "group"
+	AccessRequestTargetTypePolicy AccessRequestTargetType = "policy"
+)
+
+// Defines values for AccessRequestCreateTargetType.
+const (
+	AccessRequestCreateTargetTypeGroup  AccessRequestCreateTargetType = "group"
+	AccessRequestCreateTargetTypePolicy AccessRequestCreateTargetType = "policy"
+)
+
+// Defines values for AccountConfigChangeAction.
+const (
+	AccountConfigChangeActionCreate AccountConfigChangeAction = "create"
+	AccountConfigChangeActionDelete AccountConfigChangeAction = "delete"
+	AccountConfigChangeActionUpdate AccountConfigChangeAction = "update"
+)
+
+// Defines values for AccountConfigChangeKind.
+const (
+	AccountConfigChangeKindDnsRecord       AccountConfigChangeKind = "dns_record"
+	AccountConfigChangeKindGroup           AccountConfigChangeKind = "group"
+	AccountConfigChangeKindNameserverGroup AccountConfigChangeKind = "nameserver_group"
+	AccountConfigChangeKindNetwork         AccountConfigChangeKind = "network"
+	AccountConfigChangeKindNetworkResource AccountConfigChangeKind = "network_resource"
+	AccountConfigChangeKindNetworkRouter   AccountConfigChangeKind = "network_router"
+	AccountConfigChangeKindPolicy          AccountConfigChangeKind = "policy"
+	AccountConfigChangeKindRoute           AccountConfigChangeKind = "route"
+	AccountConfigChangeKindZone            AccountConfigChangeKind = "zone"
+)
+
+// Defines values for AccountConfigDocumentVersion.
+const (
+	AccountConfigDocumentVersionV1 AccountConfigDocumentVersion = "v1"
+)
+
+// Defines values for AccountConfigPolicyRuleAction.
+const (
+	AccountConfigPolicyRuleActionAccept AccountConfigPolicyRuleAction = "accept"
+	AccountConfigPolicyRuleActionDrop   AccountConfigPolicyRuleAction = "drop"
+)
+
+// Defines values for AccountConfigPolicyRuleProtocol.
+const (
+	AccountConfigPolicyRuleProtocolAll        AccountConfigPolicyRuleProtocol = "all"
+	AccountConfigPolicyRuleProtocolIcmp       AccountConfigPolicyRuleProtocol = "icmp"
+	AccountConfigPolicyRuleProtocolNetbirdSsh AccountConfigPolicyRuleProtocol = "netbird-ssh"
+	AccountConfigPolicyRuleProtocolTcp        AccountConfigPolicyRuleProtocol = "tcp"
+	AccountConfigPolicyRuleProtocolUdp        AccountConfigPolicyRuleProtocol = "udp"
+)
+
+// Defines values for AccountConfigRuleResourceType.
+const (
+	AccountConfigRuleResourceTypeDomain AccountConfigRuleResourceType = "domain"
+	AccountConfigRuleResourceTypeHost   AccountConfigRuleResourceType = "host"
+	AccountConfigRuleResourceTypePeer   AccountConfigRuleResourceType = "peer"
+	AccountConfigRuleResourceTypeSubnet AccountConfigRuleResourceType = "subnet"
+)
+
 // Defines values for DNSRecordType.
 const (
 	DNSRecordTypeA     DNSRecordType = "A"
@@ -70,6 +138,12 @@ const (
 	EventActivityCodeUserUnblock                              EventActivityCode = "user.unblock"
 )
 
+// Defines values for ExternalCheckProtocol.
+const (
+	ExternalCheckProtocolGrpc ExternalCheckProtocol = "grpc"
+	ExternalCheckProtocolHttp ExternalCheckProtocol = "http"
+)
+
 // Defines values for GeoLocationCheckAction.
 const (
 	GeoLocationCheckActionAllow GeoLocationCheckAction = "allow"
@@ -81,6 +155,7 @@ const (
 	GroupIssuedApi         GroupIssued = "api"
 	GroupIssuedIntegration GroupIssued = "integration"
 	GroupIssuedJwt         GroupIssued = "jwt"
+	GroupIssuedScim        GroupIssued = "scim"
 )
 
 // Defines values for GroupMinimumIssued.
@@ -88,6 +163,7 @@ const (
 	GroupMinimumIssuedApi         GroupMinimumIssued = "api"
 	GroupMinimumIssuedIntegration GroupMinimumIssued = "integration"
 	GroupMinimumIssuedJwt         GroupMinimumIssued = "jwt"
+	GroupMinimumIssuedScim        GroupMinimumIssued = "scim"
 )
 
 // Defines values for IdentityProviderType.
@@ -134,12 +210,43 @@ const (
 	NetworkResourceTypeSubnet NetworkResourceType = "subnet"
 )
 
+// Defines values for OSFirewallCheckBackends.
+const (
+	OSFirewallCheckBackendsFirewalld OSFirewallCheckBackends = "firewalld"
+	OSFirewallCheckBackendsNftables  OSFirewallCheckBackends = "nftables"
+	OSFirewallCheckBackendsUfw       OSFirewallCheckBackends = "ufw"
+)
+
 // Defines values for PeerNetworkRangeCheckAction.
 const (
 	PeerNetworkRangeCheckActionAllow PeerNetworkRangeCheckAction = "allow"
 	PeerNetworkRangeCheckActionDeny  PeerNetworkRangeCheckAction = "deny"
 )
 
+// Defines values for PersonalAccessTokenScopeAccess.
+const (
+	PersonalAccessTokenScopeAccessRead  PersonalAccessTokenScopeAccess = "read"
+	PersonalAccessTokenScopeAccessWrite PersonalAccessTokenScopeAccess = "write"
+)
+
+// Defines values for PolicyDryRunFirewallRuleAction.
+const (
+	PolicyDryRunFirewallRuleActionAccept PolicyDryRunFirewallRuleAction = "accept"
+	PolicyDryRunFirewallRuleActionDrop   PolicyDryRunFirewallRuleAction = "drop"
+)
+
+// Defines values for PolicyDryRunFirewallRuleDirection.
+const (
+	PolicyDryRunFirewallRuleDirectionIn  PolicyDryRunFirewallRuleDirection = "in"
+	PolicyDryRunFirewallRuleDirectionOut PolicyDryRunFirewallRuleDirection = "out"
+)
+
+// Defines values for PolicyDryRunRouteFirewallRuleAction.
+const (
+	PolicyDryRunRouteFirewallRuleActionAccept PolicyDryRunRouteFirewallRuleAction = "accept"
+	PolicyDryRunRouteFirewallRuleActionDrop   PolicyDryRunRouteFirewallRuleAction = "drop"
+)
+
 // Defines values for PolicyRuleAction.
 const (
 	PolicyRuleActionAccept PolicyRuleAction = "accept"
@@ -185,6 +292,31 @@ const (
 	PolicyRuleUpdateProtocolUdp        PolicyRuleUpdateProtocol = "udp"
 )
 
+// Defines values for PolicyScheduleWindowDays.
+const (
+	PolicyScheduleWindowDaysFri PolicyScheduleWindowDays = "fri"
+	PolicyScheduleWindowDaysMon PolicyScheduleWindowDays = "mon"
+	PolicyScheduleWindowDaysSat PolicyScheduleWindowDays = "sat"
+	PolicyScheduleWindowDaysSun PolicyScheduleWindowDays = "sun"
+	PolicyScheduleWindowDaysThu PolicyScheduleWindowDays = "thu"
+	PolicyScheduleWindowDaysTue PolicyScheduleWindowDays = "tue"
+	PolicyScheduleWindowDaysWed PolicyScheduleWindowDays = "wed"
+)
+
+// Defines values for PolicySimulationRequestProtocol.
+const (
+	PolicySimulationRequestProtocolAll  PolicySimulationRequestProtocol = "all"
+	PolicySimulationRequestProtocolIcmp PolicySimulationRequestProtocol = "icmp"
+	PolicySimulationRequestProtocolTcp  PolicySimulationRequestProtocol = "tcp"
+	PolicySimulationRequestProtocolUdp  PolicySimulationRequestProtocol = "udp"
+)
+
+// Defines values for PolicySimulationRuleAction.
+const (
+	PolicySimulationRuleActionAccept PolicySimulationRuleAction = "accept"
+	PolicySimulationRuleActionDrop   PolicySimulationRuleAction = "drop"
+)
+
 // Defines values for ResourceType.
 const (
 	ResourceTypeDomain ResourceType = "domain"
@@ -200,6 +332,12 @@ const (
 	UserStatusInvited UserStatus = "invited"
 )
 
+// Defines values for GetApiAccountConfigParamsFormat.
+const (
+	GetApiAccountConfigParamsFormatJson GetApiAccountConfigParamsFormat = "json"
+	GetApiAccountConfigParamsFormatYaml GetApiAccountConfigParamsFormat = "yaml"
+)
+
 // Defines values for GetApiEventsNetworkTrafficParamsType.
 const (
 	GetApiEventsNetworkTrafficParamsTypeTYPEDROP    GetApiEventsNetworkTrafficParamsType = "TYPE_DROP"
@@ -221,6 +359,66 @@ const (
 	GetApiEventsNetworkTrafficParamsDirectionINGRESS          GetApiEventsNetworkTrafficParamsDirection = "INGRESS"
 )
 
+// AccessRequest defines model for AccessRequest.
+type AccessRequest struct {
+	// CreatedAt Access request creation date
+	CreatedAt time.Time `json:"created_at"`
+
+	// Duration Time in seconds the access is granted for once approved
+	Duration int `json:"duration"`
+
+	// ExpiresAt Date when the granted access expires
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+
+	// Id Access request ID
+	Id string `json:"id"`
+
+	// Reason Justification for the access request shown to the reviewer
+	Reason string `json:"reason"`
+
+	// RequesterId ID of the user who requested the access
+	RequesterId string `json:"requester_id"`
+
+	// ReviewedAt Date when the request was approved or denied
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+
+	// ReviewerId ID of the user who approved or denied the request
+	ReviewerId *string `json:"reviewer_id,omitempty"`
+
+	// Status Access request status
+	Status AccessRequestStatus `json:"status"`
+
+	// TargetId ID of the group the requester's peers are added to or of the policy that is enabled
+	TargetId string `json:"target_id"`
+
+	// TargetType Type of the resource the access is requested for
+	TargetType AccessRequestTargetType `json:"target_type"`
+}
+
+// AccessRequestStatus Access request status
+type AccessRequestStatus string
+
+// AccessRequestTargetType Type of the resource the access is requested for
+type AccessRequestTargetType string
+
+// AccessRequestCreate defines model for AccessRequestCreate.
+type AccessRequestCreate struct {
+	// Duration Time in seconds the access is granted for once approved
+	Duration int `json:"duration"`
+
+	// Reason Justification for the access request shown to the reviewer
+	Reason string `json:"reason"`
+
+	// TargetId ID of the group the requester's peers are added to or of the policy that is enabled
+	TargetId string `json:"target_id"`
+
+	// TargetType Type of the resource the access is requested for
+	TargetType AccessRequestCreateTargetType `json:"target_type"`
+}
+
+// AccessRequestCreateTargetType Type of the resource the access is requested for
+type AccessRequestCreateTargetType string
+
 // AccessiblePeer defines model for AccessiblePeer.
 type AccessiblePeer struct {
 	// CityName Commonly used English name of the city
@@ -277,6 +475,194 @@ type Account struct {
 	Settings   AccountSettings   `json:"settings"`
 }
 
+// AccountConfigChange defines model for AccountConfigChange.
+type AccountConfigChange struct {
+	Action AccountConfigChangeAction `json:"action"`
+	Kind   AccountConfigChangeKind   `json:"kind"`
+
+	// Name Key of the object in the document
+	Name string `json:"name"`
+
+	// Parent Network name or zone domain of nested objects
+	Parent *string `json:"parent,omitempty"`
+}
+
+// AccountConfigChangeAction defines model for AccountConfigChange.Action.
+type AccountConfigChangeAction string
+
+// AccountConfigChangeKind defines model for AccountConfigChange.Kind.
+type AccountConfigChangeKind string
+
+// AccountConfigChanges defines model for AccountConfigChanges.
+type AccountConfigChanges struct {
+	// Changes Changes in the order they are applied
+	Changes []AccountConfigChange `json:"changes"`
+}
+
+// AccountConfigDocument Declarative account configuration. Objects are referenced by name. A section that is omitted or null is not managed and left untouched, while an empty section removes every object of that kind.
+type AccountConfigDocument struct {
+	// Groups API managed groups
+	Groups           *[]AccountConfigGroup           `json:"groups"`
+	NameserverGroups *[]AccountConfigNameserverGroup `json:"nameserver_groups"`
+	Networks         *[]AccountConfigNetwork         `json:"networks"`
+	Policies         *[]AccountConfigPolicy          `json:"policies"`
+	Routes           *[]AccountConfigRoute           `json:"routes"`
+
+	// Version Document format version
+	Version AccountConfigDocumentVersion `json:"version"`
+	Zones   *[]AccountConfigZone         `json:"zones"`
+}
+
+// AccountConfigDocumentVersion Document format version
+type AccountConfigDocumentVersion string
+
+// AccountConfigGroup defines model for AccountConfigGroup.
+type AccountConfigGroup struct {
+	Name string `json:"name"`
+
+	// Peers Names of the peers in the group
+	Peers *[]string `json:"peers,omitempty"`
+}
+
+// AccountConfigNameserverGroup defines model for AccountConfigNameserverGroup.
+type AccountConfigNameserverGroup struct {
+	Description *string   `json:"description,omitempty"`
+	Domains     *[]string `json:"domains,omitempty"`
+	Enabled     bool      `json:"enabled"`
+
+	// Groups Names of the distribution groups
+	Groups               []string     `json:"groups"`
+	Name                 string       `json:"name"`
+	Nameservers          []Nameserver `json:"nameservers"`
+	Primary              bool         `json:"primary"`
+	SearchDomainsEnabled bool         `json:"search_domains_enabled"`
+}
+
+// AccountConfigNetwork defines model for AccountConfigNetwork.
+type AccountConfigNetwork struct {
+	Description *string                         `json:"description,omitempty"`
+	Name        string                          `json:"name"`
+	Resources   *[]AccountConfigNetworkResource `json:"resources,omitempty"`
+	Routers     *[]AccountConfigNetworkRouter   `json:"routers,omitempty"`
+}
+
+// AccountConfigNetworkResource defines model for AccountConfigNetworkResource.
+type AccountConfigNetworkResource struct {
+	Address     string  `json:"address"`
+	Description *string `json:"description,omitempty"`
+	Enabled     bool    `json:"enabled"`
+
+	// Groups Names of the resource groups
+	Groups *[]string `json:"groups,omitempty"`
+
+	// Name Resource name, unique in the account
+	Name string `json:"name"`
+}
+
+// AccountConfigNetworkRouter defines model for AccountConfigNetworkRouter.
+type AccountConfigNetworkRouter struct {
+	Enabled    bool `json:"enabled"`
+	Masquerade bool `json:"masquerade"`
+	Metric     int  `json:"metric"`
+
+	// Peer Name of the routing peer, conflicts with peer_groups
+	Peer *string `json:"peer,omitempty"`
+
+	// PeerGroups Names of the routing peer groups, conflicts with peer
+	PeerGroups *[]string `json:"peer_groups,omitempty"`
+}
+
+// AccountConfigPolicy defines model for AccountConfigPolicy.
+type AccountConfigPolicy struct {
+	Description *string                   `json:"description,omitempty"`
+	Enabled     bool                      `json:"enabled"`
+	Name        string                    `json:"name"`
+	Rules       []AccountConfigPolicyRule `json:"rules"`
+
+	// Schedule Optional validity of a policy. An enabled policy is only applied while all configured conditions match.
+	Schedule *PolicySchedule `json:"schedule,omitempty"`
+
+	// SourcePostureChecks Names of the posture checks applied to the sources
+	SourcePostureChecks *[]string `json:"source_posture_checks,omitempty"`
+}
+
+// AccountConfigPolicyRule defines model for AccountConfigPolicyRule.
+type AccountConfigPolicyRule struct {
+	Action AccountConfigPolicyRuleAction `json:"action"`
+
+	// AuthorizedGroups Local users authorized for SSH access, by group name
+	AuthorizedGroups    *map[string][]string       `json:"authorized_groups,omitempty"`
+	AuthorizedUser      *string                    `json:"authorized_user,omitempty"`
+	Bidirectional       bool                       `json:"bidirectional"`
+	Description         *string                    `json:"description,omitempty"`
+	DestinationResource *AccountConfigRuleResource `json:"destination_resource,omitempty"`
+
+	// Destinations Names of the destination groups
+	Destinations   *[]string                       `json:"destinations,omitempty"`
+	Enabled        bool                            `json:"enabled"`
+	Name           string                          `json:"name"`
+	PortRanges     *[]RulePortRange                `json:"port_ranges,omitempty"`
+	Ports          *[]string                       `json:"ports,omitempty"`
+	Protocol       AccountConfigPolicyRuleProtocol `json:"protocol"`
+	SourceResource *AccountConfigRuleResource      `json:"source_resource,omitempty"`
+
+	// Sources Names of the source groups
+	Sources *[]string `json:"sources,omitempty"`
+}
+
+// AccountConfigPolicyRuleAction defines model for AccountConfigPolicyRule.Action.
+type AccountConfigPolicyRuleAction string
+
+// AccountConfigPolicyRuleProtocol defines model for AccountConfigPolicyRule.Protocol.
+type AccountConfigPolicyRuleProtocol string
+
+// AccountConfigRoute defines model for AccountConfigRoute.
+type AccountConfigRoute struct {
+	AccessControlGroups *[]string `json:"access_control_groups,omitempty"`
+	Description         *string   `json:"description,omitempty"`
+	Domains             *[]string `json:"domains,omitempty"`
+	Enabled             bool      `json:"enabled"`
+
+	// Groups Names of the groups receiving the route
+	Groups     []string `json:"groups"`
+	KeepRoute  *bool    `json:"keep_route,omitempty"`
+	Masquerade bool     `json:"masquerade"`
+	Metric     int      `json:"metric"`
+
+	// Network Network range in CIDR format, conflicts with domains
+	Network   *string `json:"network,omitempty"`
+	NetworkId string  `json:"network_id"`
+
+	// Peer Name of the routing peer, conflicts with peer_groups
+	Peer *string `json:"peer,omitempty"`
+
+	// PeerGroups Names of the routing peer groups, conflicts with peer
+	PeerGroups    *[]string `json:"peer_groups,omitempty"`
+	SkipAutoApply *bool     `json:"skip_auto_apply,omitempty"`
+}
+
+// AccountConfigRuleResource defines model for AccountConfigRuleResource.
+type AccountConfigRuleResource struct {
+	Name string `json:"name"`
+
+	// Type Resource type, peer references a peer by name and other types a network resource by name
+	Type AccountConfigRuleResourceType `json:"type"`
+}
+
+// AccountConfigRuleResourceType Resource type, peer references a peer by name and other types a network resource by name
+type AccountConfigRuleResourceType string
+
+// AccountConfigZone defines model for AccountConfigZone.
+type AccountConfigZone struct {
+	// DistributionGroups Names of the distribution groups
+	DistributionGroups []string            `json:"distribution_groups"`
+	Domain             string              `json:"domain"`
+	EnableSearchDomain bool                `json:"enable_search_domain"`
+	Enabled            bool                `json:"enabled"`
+	Name               string              `json:"name"`
+	Records            *[]DNSRecordRequest `json:"records,omitempty"`
+}
+
 // AccountExtraSettings defines model for AccountExtraSettings.
 type AccountExtraSettings struct {
 	// NetworkTrafficLogsEnabled Enables or disables network traffic logging. If enabled, all network traffic events from peers will be stored.
@@ -340,6 +726,10 @@ type AccountSettings struct {
 	// NetworkRange Allows to define a custom network range for the account in CIDR format
 	NetworkRange *string `json:"network_range,omitempty"`
 
+	// NetworkRangeV6 IPv6 unique local range (fc00::/7, prefix length /48 to /64) used to allocate an IPv6 overlay address to every peer.
+	// IPv6 overlay addressing is disabled when empty. The current value is kept when the field is omitted.
+	NetworkRangeV6 *string `json:"network_range_v6,omitempty"`
+
 	// PeerInactivityExpiration Period of time of inactivity after which peer session expires (seconds).
 	PeerInactivityExpiration int `json:"peer_inactivity_expiration"`
 
@@ -370,12 +760,21 @@ type AvailablePorts struct {
 
 // Checks List of objects that perform the actual checks
 type Checks struct {
+	// DiskEncryptionCheck Posture check for volumes backed by an encrypted disk in the peer's system. Evaluated on Linux peers (LUKS/dm-crypt), peers of other operating systems fail the check.
+	DiskEncryptionCheck *DiskEncryptionCheck `json:"disk_encryption_check,omitempty"`
+
+	// ExternalCheck Posture check that delegates the compliance verdict to an external service, e.g. an MDM or EDR
+	ExternalCheck *ExternalCheck `json:"external_check,omitempty"`
+
 	// GeoLocationCheck Posture check for geo location
 	GeoLocationCheck *GeoLocationCheck `json:"geo_location_check,omitempty"`
 
 	// NbVersionCheck Posture check for the version of operating system
 	NbVersionCheck *NBVersionCheck `json:"nb_version_check,omitempty"`
 
+	// OsFirewallCheck Posture check for an enabled operating system firewall in the peer's system. Evaluated on Linux peers, peers of other operating systems fail the check.
+	OsFirewallCheck *OSFirewallCheck `json:"os_firewall_check,omitempty"`
+
 	// OsVersionCheck Posture check for the version of operating system
 	OsVersionCheck *OSVersionCheck `json:"os_version_check,omitempty"`
 
@@ -418,6 +817,9 @@ type CreateSetupKeyRequest struct {
 	// AutoGroups List of group IDs to auto-assign to peers registered with this key
 	AutoGroups []string `json:"auto_groups"`
 
+	// Constraints Optional conditions a peer registration with the setup key has to meet. Rejected registrations are recorded in the activity log.
+	Constraints *SetupKeyConstraints `json:"constraints,omitempty"`
+
 	// Ephemeral Indicate that the peer will be ephemeral or not
 	Ephemeral *bool `json:"ephemeral,omitempty"`
 
@@ -476,6 +878,12 @@ type DNSSettings struct {
 	DisabledManagementGroups []string `json:"disabled_management_groups"`
 }
 
+// DiskEncryptionCheck Posture check for volumes backed by an encrypted disk in the peer's system. Evaluated on Linux peers (LUKS/dm-crypt), peers of other operating systems fail the check.
+type DiskEncryptionCheck struct {
+	// Volumes Mount points that must be backed by an encrypted disk. The root filesystem is checked when empty.
+	Volumes *[]string `json:"volumes,omitempty"`
+}
+
 // Event defines model for Event.
 type Event struct {
 	// Activity The activity that occurred during the event
@@ -509,6 +917,30 @@ type Event struct {
 // EventActivityCode The string code of the activity that occurred during the event
 type EventActivityCode string
 
+// ExternalCheck Posture check that delegates the compliance verdict to an external service, e.g. an MDM or EDR
+type ExternalCheck struct {
+	// CacheTtl Time in seconds a verdict of the external service is reused for a peer
+	CacheTtl *int `json:"cache_ttl,omitempty"`
+
+	// Endpoint https URL of the HTTP endpoint or address of the gRPC server. Internal addresses of the management server network are refused.
+	Endpoint string `json:"endpoint"`
+
+	// Insecure Connect to the gRPC server without TLS. Refused, the calls carry the secret and the peer system meta.
+	Insecure *bool `json:"insecure,omitempty"`
+
+	// Protocol Protocol used to call the external service
+	Protocol ExternalCheckProtocol `json:"protocol"`
+
+	// Secret Sent as bearer token in the Authorization header or the authorization gRPC metadata to authenticate the calls to the external service. It is never returned, the stored secret is kept when omitted on update.
+	Secret *string `json:"secret,omitempty"`
+
+	// Timeout Time in seconds to wait for a verdict of the external service
+	Timeout *int `json:"timeout,omitempty"`
+}
+
+// ExternalCheckProtocol Protocol used to call the external service
+type ExternalCheckProtocol string
+
 // GeoLocationCheck Posture check for geo location
 type GeoLocationCheck struct {
 	// Action Action to take upon policy match
@@ -526,7 +958,7 @@ type Group struct {
 	// Id Group ID
 	Id string `json:"id"`
 
-	// Issued How the group was issued (api, integration, jwt)
+	// Issued How the group was issued (api, integration, jwt, scim)
 	Issued *GroupIssued `json:"issued,omitempty"`
 
 	// Name Group Name identifier
@@ -551,7 +983,7 @@ type GroupMinimum struct {
 	// Id Group ID
 	Id string `json:"id"`
 
-	// Issued How the group was issued (api, integration, jwt)
+	// Issued How the group was issued (api, integration, jwt, scim)
 	Issued *GroupMinimumIssued `json:"issued,omitempty"`
 
 	// Name Group Name identifier
@@ -1107,6 +1539,15 @@ type NetworkTrafficUser struct {
 	Name string `json:"name"`
 }
 
+// OSFirewallCheck Posture check for an enabled operating system firewall in the peer's system. Evaluated on Linux peers, peers of other operating systems fail the check.
+type OSFirewallCheck struct {
+	// Backends Firewall backends of which at least one must be active. Any active backend is accepted when empty.
+	Backends *[]OSFirewallCheckBackends `json:"backends,omitempty"`
+}
+
+// OSFirewallCheckBackends defines model for OSFirewallCheck.Backends.
+type OSFirewallCheckBackends string
+
 // OSVersionCheck Posture check for the version of operating system
 type OSVersionCheck struct {
 	// Android Posture check for the version of operating system
@@ -1175,6 +1616,9 @@ type Peer struct {
 	// Ip Peer's IP address
 	Ip string `json:"ip"`
 
+	// Ipv6 Peer's IPv6 overlay address, set when the account has an IPv6 network range
+	Ipv6 *string `json:"ipv6,omitempty"`
+
 	// KernelVersion Peer's operating system kernel version
 	KernelVersion string `json:"kernel_version"`
 
@@ -1213,6 +1657,60 @@ type Peer struct {
 	Version string `json:"version"`
 }
 
+// PeerApprovalDecision defines model for PeerApprovalDecision.
+type PeerApprovalDecision struct {
+	// PeerIds IDs of the pending peers the decision applies to
+	PeerIds []string `json:"peer_ids"`
+
+	// Reason Reason of the decision, recorded in the activity log
+	Reason *string `json:"reason,omitempty"`
+}
+
+// PeerApprovalRule defines model for PeerApprovalRule.
+type PeerApprovalRule struct {
+	// CountryCodes Matches peers connecting from one of the countries
+	CountryCodes *[]CountryCode `json:"country_codes,omitempty"`
+
+	// Enabled Indicates whether the rule approves peers
+	Enabled bool `json:"enabled"`
+
+	// Id Peer approval rule ID
+	Id string `json:"id"`
+
+	// Name Peer approval rule name
+	Name string `json:"name"`
+
+	// OperatingSystems Matches peers running one of the operating systems, e.g. linux, darwin or windows
+	OperatingSystems *[]string `json:"operating_systems,omitempty"`
+
+	// SetupKeys Matches peers registered with one of the setup keys
+	SetupKeys *[]string `json:"setup_keys,omitempty"`
+
+	// UserGroups Matches peers registered by a user in one of the groups
+	UserGroups *[]string `json:"user_groups,omitempty"`
+}
+
+// PeerApprovalRuleRequest Approves new peers matching every condition of the rule without an administrator. At least one condition is required.
+type PeerApprovalRuleRequest struct {
+	// CountryCodes Matches peers connecting from one of the countries
+	CountryCodes *[]CountryCode `json:"country_codes,omitempty"`
+
+	// Enabled Indicates whether the rule approves peers
+	Enabled bool `json:"enabled"`
+
+	// Name Peer approval rule name
+	Name string `json:"name"`
+
+	// OperatingSystems Matches peers running one of the operating systems, e.g. linux, darwin or windows
+	OperatingSystems *[]string `json:"operating_systems,omitempty"`
+
+	// SetupKeys Matches peers registered with one of the setup keys
+	SetupKeys *[]string `json:"setup_keys,omitempty"`
+
+	// UserGroups Matches peers registered by a user in one of the groups
+	UserGroups *[]string `json:"user_groups,omitempty"`
+}
+
 // PeerBatch defines model for PeerBatch.
 type PeerBatch struct {
 	// AccessiblePeersCount Number of accessible peers
@@ -1266,6 +1764,9 @@ type PeerBatch struct {
 	// Ip Peer's IP address
 	Ip string `json:"ip"`
 
+	// Ipv6 Peer's IPv6 overlay address, set when the account has an IPv6 network range
+	Ipv6 *string `json:"ipv6,omitempty"`
+
 	// KernelVersion Peer's operating system kernel version
 	KernelVersion string `json:"kernel_version"`
 
@@ -1324,6 +1825,9 @@ type PeerLocalFlags struct {
 	// DisableServerRoutes Indicates whether server routes are disabled on this peer or not
 	DisableServerRoutes *bool `json:"disable_server_routes,omitempty"`
 
+	// Ipv6Supported Indicates whether the peer is able to configure an IPv6 overlay address
+	Ipv6Supported *bool `json:"ipv6_supported,omitempty"`
+
 	// LazyConnectionEnabled Indicates whether lazy connection is enabled on this peer
 	LazyConnectionEnabled *bool `json:"lazy_connection_enabled,omitempty"`
 
@@ -1358,6 +1862,27 @@ type PeerNetworkRangeCheck struct {
 // PeerNetworkRangeCheckAction Action to take upon policy match
 type PeerNetworkRangeCheckAction string
 
+// PeerPostureCheckResult defines model for PeerPostureCheckResult.
+type PeerPostureCheckResult struct {
+	// Check Name of the evaluated check
+	Check string `json:"check"`
+
+	// EvaluatedAt Time the check was evaluated (UTC)
+	EvaluatedAt time.Time `json:"evaluated_at"`
+
+	// Passed Indicates whether the peer passed the check
+	Passed bool `json:"passed"`
+
+	// PostureCheckId Posture Checks ID the check belongs to
+	PostureCheckId string `json:"posture_check_id"`
+
+	// PostureCheckName Posture Checks name the check belongs to
+	PostureCheckName string `json:"posture_check_name"`
+
+	// Reason Reason of the failure, empty if the check passed
+	Reason string `json:"reason"`
+}
+
 // PeerRequest defines model for PeerRequest.
 type PeerRequest struct {
 	// ApprovalRequired (Cloud only) Indicates whether peer needs approval
@@ -1395,8 +1920,53 @@ type PeerTemporaryAccessResponse struct {
 	Rules []string `json:"rules"`
 }
 
+// PendingPeer A peer waiting for the approval of an administrator with the context of its registration
+type PendingPeer struct {
+	// CityName Commonly used English name of the city
+	CityName CityName `json:"city_name"`
+
+	// ConnectionIp Peer's public connection IP address
+	ConnectionIp string `json:"connection_ip"`
+
+	// CountryCode 2-letter ISO 3166-1 alpha-2 code that represents the country
+	CountryCode CountryCode `json:"country_code"`
+
+	// Id Peer ID
+	Id string `json:"id"`
+
+	// Ip Peer's IP address
+	Ip string `json:"ip"`
+
+	// Name Peer's hostname
+	Name string `json:"name"`
+
+	// Os Peer's operating system and version
+	Os string `json:"os"`
+
+	// PostureChecks Results of the posture checks applied to the peer by the account policies
+	PostureChecks []PeerPostureCheckResult `json:"posture_checks"`
+
+	// RegisteredAt Peer registration date (UTC)
+	RegisteredAt time.Time `json:"registered_at"`
+
+	// SetupKeyId ID of the setup key the peer was registered with, empty if the peer was registered otherwise
+	SetupKeyId string `json:"setup_key_id"`
+
+	// SetupKeyName Name of the setup key the peer was registered with
+	SetupKeyName string `json:"setup_key_name"`
+
+	// UserId User ID of the user that registered the peer, empty if the peer was registered otherwise
+	UserId string `json:"user_id"`
+
+	// Version Peer's daemon or cli version
+	Version string `json:"version"`
+}
+
 // PersonalAccessToken defines model for PersonalAccessToken.
 type PersonalAccessToken struct {
+	// AllowedSourceRanges Networks in CIDR notation the token can be used from. A token without source ranges can be used from any address.
+	AllowedSourceRanges *[]string `json:"allowed_source_ranges,omitempty"`
+
 	// CreatedAt Date the token was created
 	CreatedAt time.Time `json:"created_at"`
 
@@ -1414,6 +1984,9 @@ type PersonalAccessToken struct {
 
 	// Name Name of the token
 	Name string `json:"name"`
+
+	// Scopes Modules the token is restricted to. A token without scopes has all permissions of its user.
+	Scopes *[]PersonalAccessTokenScope `json:"scopes,omitempty"`
 }
 
 // PersonalAccessTokenGenerated defines model for PersonalAccessTokenGenerated.
@@ -1426,13 +1999,31 @@ type PersonalAccessTokenGenerated struct {
 
 // PersonalAccessTokenRequest defines model for PersonalAccessTokenRequest.
 type PersonalAccessTokenRequest struct {
+	// AllowedSourceRanges Networks in CIDR notation the token can be used from
+	AllowedSourceRanges *[]string `json:"allowed_source_ranges,omitempty"`
+
 	// ExpiresIn Expiration in days
 	ExpiresIn int `json:"expires_in"`
 
 	// Name Name of the token
 	Name string `json:"name"`
+
+	// Scopes Modules to restrict the token to. The permissions of the user still apply within the scopes.
+	Scopes *[]PersonalAccessTokenScope `json:"scopes,omitempty"`
 }
 
+// PersonalAccessTokenScope defines model for PersonalAccessTokenScope.
+type PersonalAccessTokenScope struct {
+	// Access Read access allows only reading the module, write access allows all operations of the module
+	Access PersonalAccessTokenScopeAccess `json:"access"`
+
+	// Module Permission module the scope grants access to
+	Module string `json:"module"`
+}
+
+// PersonalAccessTokenScopeAccess Read access allows only reading the module, write access allows all operations of the module
+type PersonalAccessTokenScopeAccess string
+
 // Policy defines model for Policy.
 type Policy struct {
 	// Description Policy friendly description
@@ -1450,6 +2041,9 @@ type Policy struct {
 	// Rules Policy rule object for policy UI editor
 	Rules []PolicyRule `json:"rules"`
 
+	// Schedule Optional validity of a policy. An enabled policy is only applied while all configured conditions match.
+	Schedule *PolicySchedule `json:"schedule,omitempty"`
+
 	// SourcePostureChecks Posture checks ID's applied to policy source groups
 	SourcePostureChecks []string `json:"source_posture_checks"`
 }
@@ -1468,10 +2062,115 @@ type PolicyCreate struct {
 	// Rules Policy rule object for policy UI editor
 	Rules []PolicyRuleUpdate `json:"rules"`
 
+	// Schedule Optional validity of a policy. An enabled policy is only applied while all configured conditions match.
+	Schedule *PolicySchedule `json:"schedule,omitempty"`
+
 	// SourcePostureChecks Posture checks ID's applied to policy source groups
 	SourcePostureChecks *[]string `json:"source_posture_checks,omitempty"`
 }
 
+// PolicyDryRunFirewallRule defines model for PolicyDryRunFirewallRule.
+type PolicyDryRunFirewallRule struct {
+	// Action Action of the traffic
+	Action PolicyDryRunFirewallRuleAction `json:"action"`
+
+	// Direction Direction of the traffic
+	Direction PolicyDryRunFirewallRuleDirection `json:"direction"`
+
+	// PeerIp IP address of the remote peer
+	PeerIp string `json:"peer_ip"`
+
+	// PolicyId ID of the policy rule the firewall rule is derived from
+	PolicyId string `json:"policy_id"`
+
+	// Port Port of the traffic, empty for any port
+	Port *string `json:"port,omitempty"`
+
+	// PortRange Policy rule affected ports range
+	PortRange *RulePortRange `json:"port_range,omitempty"`
+
+	// Protocol Protocol of the traffic
+	Protocol string `json:"protocol"`
+}
+
+// PolicyDryRunFirewallRuleAction Action of the traffic
+type PolicyDryRunFirewallRuleAction string
+
+// PolicyDryRunFirewallRuleDirection Direction of the traffic
+type PolicyDryRunFirewallRuleDirection string
+
+// PolicyDryRunPeer defines model for PolicyDryRunPeer.
+type PolicyDryRunPeer struct {
+	// AddedFirewallRules Firewall rules added to the peer
+	AddedFirewallRules []PolicyDryRunFirewallRule `json:"added_firewall_rules"`
+
+	// AddedPeers IDs of the peers the peer would start connecting to
+	AddedPeers []string `json:"added_peers"`
+
+	// AddedRouteFirewallRules Firewall rules of routed traffic added to the peer
+	AddedRouteFirewallRules []PolicyDryRunRouteFirewallRule `json:"added_route_firewall_rules"`
+
+	// AddedRoutes IDs of the routes the peer would start receiving
+	AddedRoutes []string `json:"added_routes"`
+
+	// PeerId Peer ID
+	PeerId string `json:"peer_id"`
+
+	// PeerName Peer name
+	PeerName string `json:"peer_name"`
+
+	// RemovedFirewallRules Firewall rules removed from the peer
+	RemovedFirewallRules []PolicyDryRunFirewallRule `json:"removed_firewall_rules"`
+
+	// RemovedPeers IDs of the peers the peer would stop connecting to
+	RemovedPeers []string `json:"removed_peers"`
+
+	// RemovedRouteFirewallRules Firewall rules of routed traffic removed from the peer
+	RemovedRouteFirewallRules []PolicyDryRunRouteFirewallRule `json:"removed_route_firewall_rules"`
+
+	// RemovedRoutes IDs of the routes the peer would stop receiving
+	RemovedRoutes []string `json:"removed_routes"`
+}
+
+// PolicyDryRunResult defines model for PolicyDryRunResult.
+type PolicyDryRunResult struct {
+	// Peers Peers whose network maps would change
+	Peers []PolicyDryRunPeer `json:"peers"`
+}
+
+// PolicyDryRunRouteFirewallRule defines model for PolicyDryRunRouteFirewallRule.
+type PolicyDryRunRouteFirewallRule struct {
+	// Action Action of the traffic
+	Action PolicyDryRunRouteFirewallRuleAction `json:"action"`
+
+	// Destination Network range of the routed traffic
+	Destination string `json:"destination"`
+
+	// Domains Domains of the routed traffic
+	Domains *[]string `json:"domains,omitempty"`
+
+	// PolicyId ID of the policy the firewall rule is derived from
+	PolicyId string `json:"policy_id"`
+
+	// Port Port of the traffic, 0 for any port
+	Port *int `json:"port,omitempty"`
+
+	// PortRange Policy rule affected ports range
+	PortRange *RulePortRange `json:"port_range,omitempty"`
+
+	// Protocol Protocol of the traffic
+	Protocol string `json:"protocol"`
+
+	// RouteId ID of the route the firewall rule belongs to
+	RouteId string `json:"route_id"`
+
+	// SourceRanges IP ranges of the peers allowed to use the route
+	SourceRanges []string `json:"source_ranges"`
+}
+
+// PolicyDryRunRouteFirewallRuleAction Action of the traffic
+type PolicyDryRunRouteFirewallRuleAction string
+
 // PolicyMinimum defines model for PolicyMinimum.
 type PolicyMinimum struct {
 	// Description Policy friendly description
@@ -1482,6 +2181,9 @@ type PolicyMinimum struct {
 
 	// Name Policy name identifier
 	Name string `json:"name"`
+
+	// Schedule Optional validity of a policy. An enabled policy is only applied while all configured conditions match.
+	Schedule *PolicySchedule `json:"schedule,omitempty"`
 }
 
 // PolicyRule defines model for PolicyRule.
@@ -1614,6 +2316,141 @@ type PolicyRuleUpdateAction string
 // PolicyRuleUpdateProtocol Policy rule type of the traffic
 type PolicyRuleUpdateProtocol string
 
+// PolicySchedule Optional validity of a policy. An enabled policy is only applied while all configured conditions match.
+type PolicySchedule struct {
+	// NotAfter Time when the policy expires and stops being applied
+	NotAfter *time.Time `json:"not_after,omitempty"`
+
+	// NotBefore Time from which the policy is applied
+	NotBefore *time.Time `json:"not_before,omitempty"`
+
+	// TimeZone IANA time zone the recurring windows are evaluated in. Defaults to UTC.
+	TimeZone *string `json:"time_zone,omitempty"`
+
+	// Windows Recurring weekly windows in which the policy is applied. If empty, the policy is applied all day.
+	Windows *[]PolicyScheduleWindow `json:"windows,omitempty"`
+}
+
+// PolicyScheduleWindow defines model for PolicyScheduleWindow.
+type PolicyScheduleWindow struct {
+	// Days Days of the week the window applies to
+	Days []PolicyScheduleWindowDays `json:"days"`
+
+	// End End time of the window in HH:MM format. If it is not after the start time, the window ends on the following day.
+	End string `json:"end"`
+
+	// Start Start time of the window in HH:MM format
+	Start string `json:"start"`
+}
+
+// PolicyScheduleWindowDays defines model for PolicyScheduleWindow.Days.
+type PolicyScheduleWindowDays string
+
+// PolicySimulationPolicy defines model for PolicySimulationPolicy.
+type PolicySimulationPolicy struct {
+	// Description Policy friendly description
+	Description *string `json:"description,omitempty"`
+
+	// Enabled Policy status
+	Enabled bool `json:"enabled"`
+
+	// Id ID of the policy to replace, a new policy is added if empty
+	Id *string `json:"id,omitempty"`
+
+	// Name Policy name identifier
+	Name string `json:"name"`
+
+	// Rules Policy rule object for policy UI editor
+	Rules []PolicyRuleUpdate `json:"rules"`
+
+	// Schedule Optional validity of a policy. An enabled policy is only applied while all configured conditions match.
+	Schedule *PolicySchedule `json:"schedule,omitempty"`
+
+	// SourcePostureChecks Posture checks ID's applied to policy source groups
+	SourcePostureChecks *[]string `json:"source_posture_checks,omitempty"`
+}
+
+// PolicySimulationRequest defines model for PolicySimulationRequest.
+type PolicySimulationRequest struct {
+	// DestinationPeerId ID of the peer receiving the traffic, mutually exclusive with destination_resource_id
+	DestinationPeerId *string `json:"destination_peer_id,omitempty"`
+
+	// DestinationResourceId ID of the network resource receiving the traffic, mutually exclusive with destination_peer_id
+	DestinationResourceId *string `json:"destination_resource_id,omitempty"`
+
+	// Policies Policies overlaying the current ones for the simulation
+	Policies *[]PolicySimulationPolicy `json:"policies,omitempty"`
+
+	// Port Destination port of the traffic, only for tcp and udp. Matches rules of any port if omitted.
+	Port *int `json:"port,omitempty"`
+
+	// Protocol Protocol of the traffic, all matches rules of any protocol
+	Protocol PolicySimulationRequestProtocol `json:"protocol"`
+
+	// SourcePeerId ID of the peer initiating the traffic
+	SourcePeerId string `json:"source_peer_id"`
+}
+
+// PolicySimulationRequestProtocol Protocol of the traffic, all matches rules of any protocol
+type PolicySimulationRequestProtocol string
+
+// PolicySimulationResult defines model for PolicySimulationResult.
+type PolicySimulationResult struct {
+	// Allowed Indicates whether the traffic is allowed
+	Allowed bool `json:"allowed"`
+
+	// PostureChecks Posture checks of the matching policies evaluated on the source peer
+	PostureChecks []PeerPostureCheckResult `json:"posture_checks"`
+
+	// Reason Reason why the traffic is denied, empty if it is allowed
+	Reason string `json:"reason"`
+
+	// Routes Routes of the source peer to the destination network resource
+	Routes []PolicySimulationRoute `json:"routes"`
+
+	// Rules Policy rules matching the traffic
+	Rules []PolicySimulationRule `json:"rules"`
+}
+
+// PolicySimulationRoute defines model for PolicySimulationRoute.
+type PolicySimulationRoute struct {
+	// Domains Domain list of domain routes
+	Domains *[]string `json:"domains,omitempty"`
+
+	// Id Route ID
+	Id string `json:"id"`
+
+	// Network Network range in CIDR format, empty for domain routes
+	Network *string `json:"network,omitempty"`
+
+	// PeerId ID of the routing peer
+	PeerId string `json:"peer_id"`
+}
+
+// PolicySimulationRule defines model for PolicySimulationRule.
+type PolicySimulationRule struct {
+	// Action Policy rule accept or drops packets
+	Action PolicySimulationRuleAction `json:"action"`
+
+	// Applied Indicates whether the rule is applied to the peers, e.g. it is not applied when the source peer fails the posture checks of the policy
+	Applied bool `json:"applied"`
+
+	// PolicyId Policy ID
+	PolicyId string `json:"policy_id"`
+
+	// PolicyName Policy name
+	PolicyName string `json:"policy_name"`
+
+	// RuleId Policy rule ID
+	RuleId string `json:"rule_id"`
+
+	// RuleName Policy rule name
+	RuleName string `json:"rule_name"`
+}
+
+// PolicySimulationRuleAction Policy rule accept or drops packets
+type PolicySimulationRuleAction string
+
 // PolicyUpdate defines model for PolicyUpdate.
 type PolicyUpdate struct {
 	// Description Policy friendly description
@@ -1628,6 +2465,9 @@ type PolicyUpdate struct {
 	// Rules Policy rule object for policy UI editor
 	Rules []PolicyRuleUpdate `json:"rules"`
 
+	// Schedule Optional validity of a policy. An enabled policy is only applied while all configured conditions match.
+	Schedule *PolicySchedule `json:"schedule,omitempty"`
+
 	// SourcePostureChecks Posture checks ID's applied to policy source groups
 	SourcePostureChecks *[]string `json:"source_posture_checks,omitempty"`
 }
@@ -1686,6 +2526,39 @@ type Resource struct {
 // ResourceType defines model for ResourceType.
 type ResourceType string
 
+// Role defines model for Role.
+type Role struct {
+	// Description Custom role description
+	Description *string `json:"description,omitempty"`
+
+	// Id Custom role ID
+	Id string `json:"id"`
+
+	// Name Custom role name
+	Name string `json:"name"`
+
+	// Permissions Allowed operations per module. Modules that are not listed are denied.
+	Permissions RolePermissions `json:"permissions"`
+
+	// UserRole Value to use as the role of a user or service user to assign this custom role
+	UserRole string `json:"user_role"`
+}
+
+// RolePermissions Allowed operations per module. Modules that are not listed are denied.
+type RolePermissions map[string]map[string]bool
+
+// RoleRequest defines model for RoleRequest.
+type RoleRequest struct {
+	// Description Custom role description
+	Description *string `json:"description,omitempty"`
+
+	// Name Custom role name
+	Name string `json:"name"`
+
+	// Permissions Allowed operations per module. Modules that are not listed are denied.
+	Permissions RolePermissions `json:"permissions"`
+}
+
 // Route defines model for Route.
 type Route struct {
 	// AccessControlGroups Access control group identifier associated with route.
@@ -1785,6 +2658,25 @@ type RulePortRange struct {
 	Start int `json:"start"`
 }
 
+// ScimToken defines model for ScimToken.
+type ScimToken struct {
+	// CreatedAt Date the token was created
+	CreatedAt time.Time `json:"created_at"`
+
+	// CreatedBy User ID of the user who created the token
+	CreatedBy string `json:"created_by"`
+
+	// LastUsed Date the token was last used by the SCIM client
+	LastUsed *time.Time `json:"last_used,omitempty"`
+}
+
+// ScimTokenGenerated defines model for ScimTokenGenerated.
+type ScimTokenGenerated struct {
+	// PlainToken Plain text representation of the generated token, to configure in the identity provider
+	PlainToken string    `json:"plain_token"`
+	ScimToken  ScimToken `json:"scim_token"`
+}
+
 // SetupKey defines model for SetupKey.
 type SetupKey struct {
 	// AllowExtraDnsLabels Allow extra DNS labels to be added to the peer
@@ -1793,6 +2685,9 @@ type SetupKey struct {
 	// AutoGroups List of group IDs to auto-assign to peers registered with this key
 	AutoGroups []string `json:"auto_groups"`
 
+	// Constraints Optional conditions a peer registration with the setup key has to meet. Rejected registrations are recorded in the activity log.
+	Constraints *SetupKeyConstraints `json:"constraints,omitempty"`
+
 	// Ephemeral Indicate that the peer will be ephemeral or not
 	Ephemeral bool `json:"ephemeral"`
 
@@ -1841,6 +2736,9 @@ type SetupKeyBase struct {
 	// AutoGroups List of group IDs to auto-assign to peers registered with this key
 	AutoGroups []string `json:"auto_groups"`
 
+	// Constraints Optional conditions a peer registration with the setup key has to meet. Rejected registrations are recorded in the activity log.
+	Constraints *SetupKeyConstraints `json:"constraints,omitempty"`
+
 	// Ephemeral Indicate that the peer will be ephemeral or not
 	Ephemeral bool `json:"ephemeral"`
 
@@ -1886,6 +2784,9 @@ type SetupKeyClear struct {
 	// AutoGroups List of group IDs to auto-assign to peers registered with this key
 	AutoGroups []string `json:"auto_groups"`
 
+	// Constraints Optional conditions a peer registration with the setup key has to meet. Rejected registrations are recorded in the activity log.
+	Constraints *SetupKeyConstraints `json:"constraints,omitempty"`
+
 	// Ephemeral Indicate that the peer will be ephemeral or not
 	Ephemeral bool `json:"ephemeral"`
 
@@ -1926,11 +2827,32 @@ type SetupKeyClear struct {
 	Valid bool `json:"valid"`
 }
 
+// SetupKeyConstraints Optional conditions a peer registration with the setup key has to meet. Rejected registrations are recorded in the activity log.
+type SetupKeyConstraints struct {
+	// AllowedSourceRanges Networks in CIDR notation the peers can register from, matched against the connection IP of the peer. The connection IP can be spoofed unless the management ReverseProxy.TrustedPeers are restricted to the reverse proxies in front of it.
+	AllowedSourceRanges *[]string `json:"allowed_source_ranges,omitempty"`
+
+	// HostnamePattern Regular expression the whole hostname of the peer has to match
+	HostnamePattern *string `json:"hostname_pattern,omitempty"`
+
+	// NotAfter Date after which the key can't be used
+	NotAfter *time.Time `json:"not_after,omitempty"`
+
+	// NotBefore Date before which the key can't be used
+	NotBefore *time.Time `json:"not_before,omitempty"`
+
+	// RequiredOs Operating system the peer has to run, e.g. linux, windows, darwin, android or ios
+	RequiredOs *string `json:"required_os,omitempty"`
+}
+
 // SetupKeyRequest defines model for SetupKeyRequest.
 type SetupKeyRequest struct {
 	// AutoGroups List of group IDs to auto-assign to peers registered with this key
 	AutoGroups []string `json:"auto_groups"`
 
+	// Constraints Constraints of the peer registrations. The current constraints are kept when omitted, an empty object removes them.
+	Constraints *SetupKeyConstraints `json:"constraints,omitempty"`
+
 	// Revoked Setup key revocation status
 	Revoked bool `json:"revoked"`
 }
@@ -1979,7 +2901,7 @@ type User struct {
 	// IsServiceUser Is true if this user is a service user
 	IsServiceUser *bool `json:"is_service_user,omitempty"`
 
-	// Issued How user was issued by API or Integration
+	// Issued How user was issued by API, Integration or SCIM
 	Issued *string `json:"issued,omitempty"`
 
 	// LastLogin Last time this user performed a login to the dashboard
@@ -1995,7 +2917,7 @@ type User struct {
 	PendingApproval bool             `json:"pending_approval"`
 	Permissions     *UserPermissions `json:"permissions,omitempty"`
 
-	// Role User's NetBird account role
+	// Role User's NetBird account role. Custom roles are referenced as `custom:<role id>`.
 	Role string `json:"role"`
 
 	// Status User's status
@@ -2019,7 +2941,7 @@ type UserCreateRequest struct {
 	// Name User's full name
 	Name *string `json:"name,omitempty"`
 
-	// Role User's NetBird account role
+	// Role User's NetBird account role. Custom roles are referenced as `custom:<role id>`.
 	Role string `json:"role"`
 }
 
@@ -2038,10 +2960,70 @@ type UserRequest struct {
 	// IsBlocked If set to true then user is blocked and can't use the system
 	IsBlocked bool `json:"is_blocked"`
 
-	// Role User's NetBird account role
+	// Role User's NetBird account role. Custom roles are referenced as `custom:<role id>`.
 	Role string `json:"role"`
 }
 
+// WorkloadIdentityProvider defines model for WorkloadIdentityProvider.
+type WorkloadIdentityProvider struct {
+	// Audiences Audiences the tokens have to be issued for, at least one is required. Every audience has to be the account ID or end with /<account ID>, which binds the tokens to the account.
+	Audiences []string `json:"audiences"`
+
+	// Enabled Indicates whether the provider accepts registrations
+	Enabled bool `json:"enabled"`
+
+	// Ephemeral Indicates whether the peers registered by the workloads are ephemeral
+	Ephemeral bool `json:"ephemeral"`
+
+	// Id Workload identity provider ID
+	Id string `json:"id"`
+
+	// Issuer Issuer of the tokens, matched against the iss claim
+	Issuer string `json:"issuer"`
+
+	// KeysLocation https URL of the JSON Web Key Set of the issuer, it can't resolve to an internal address
+	KeysLocation string `json:"keys_location"`
+
+	// Name Workload identity provider name
+	Name string `json:"name"`
+
+	// Rules Rules mapping the token claims to the groups of the peer. Tokens matching no rule are rejected, the groups of all matching rules are assigned.
+	Rules []WorkloadIdentityRule `json:"rules"`
+}
+
+// WorkloadIdentityProviderRequest defines model for WorkloadIdentityProviderRequest.
+type WorkloadIdentityProviderRequest struct {
+	// Audiences Audiences the tokens have to be issued for, at least one is required. Every audience has to be the account ID or end with /<account ID>, which binds the tokens to the account.
+	Audiences []string `json:"audiences"`
+
+	// Enabled Indicates whether the provider accepts registrations
+	Enabled bool `json:"enabled"`
+
+	// Ephemeral Indicates whether the peers registered by the workloads are ephemeral
+	Ephemeral bool `json:"ephemeral"`
+
+	// Issuer Issuer of the tokens, matched against the iss claim
+	Issuer string `json:"issuer"`
+
+	// KeysLocation https URL of the JSON Web Key Set of the issuer, it can't resolve to an internal address
+	KeysLocation string `json:"keys_location"`
+
+	// Name Workload identity provider name
+	Name string `json:"name"`
+
+	// Rules Rules mapping the token claims to the groups of the peer. Tokens matching no rule are rejected, the groups of all matching rules are assigned.
+	Rules []WorkloadIdentityRule `json:"rules"`
+}
+
+// WorkloadIdentityRule Maps the claims of a workload identity token to the groups of the registered peer
+type WorkloadIdentityRule struct {
+	// AutoGroups List of group IDs the peers matching the rule are added to
+	AutoGroups []string `json:"auto_groups"`
+
+	// Claims Claims the token has to contain. The values are matched exactly, a * matches any sequence of characters. Array claims match when any of their values matches.
+	Claims map[string]string `json:"claims"`
+}
+
 // Zone defines model for Zone.
 type Zone struct {
 	// DistributionGroups Group IDs that defines groups of peers that will resolve this zone
@@ -2084,6 +3066,15 @@ type ZoneRequest struct {
 	Name string `json:"name"`
 }
 
+// GetApiAccountConfigParams defines parameters for GetApiAccountConfig.
+type GetApiAccountConfigParams struct {
+	// Format Document format
+	Format *GetApiAccountConfigParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+}
+
+// GetApiAccountConfigParamsFormat defines parameters for GetApiAccountConfig.
+type GetApiAccountConfigParamsFormat string
+
 // GetApiEventsNetworkTrafficParams defines parameters for GetApiEventsNetworkTraffic.
 type GetApiEventsNetworkTrafficParams struct {
 	// Page Page number
@@ -2150,12 +3141,33 @@ type GetApiPeersPeerIdIngressPortsParams struct {
 	Name *string `form:"name,omitempty" json:"name,omitempty"`
 }
 
+// PostApiPoliciesParams defines parameters for PostApiPolicies.
+type PostApiPoliciesParams struct {
+	// DryRun Returns the peers whose network maps would change instead of saving the policy
+	DryRun *bool `form:"dry_run,omitempty" json:"dry_run,omitempty"`
+}
+
+// PutApiPoliciesPolicyIdParams defines parameters for PutApiPoliciesPolicyId.
+type PutApiPoliciesPolicyIdParams struct {
+	// DryRun Returns the peers whose network maps would change instead of saving the policy
+	DryRun *bool `form:"dry_run,omitempty" json:"dry_run,omitempty"`
+}
+
 // GetApiUsersParams defines parameters for GetApiUsers.
 type GetApiUsersParams struct {
 	// ServiceUser Filters users and returns either regular users or service users
 	ServiceUser *bool `form:"service_user,omitempty" json:"service_user,omitempty"`
 }
 
+// PostApiAccessRequestsJSONRequestBody defines body for PostApiAccessRequests for application/json ContentType.
+type PostApiAccessRequestsJSONRequestBody = AccessRequestCreate
+
+// PostApiAccountConfigApplyJSONRequestBody defines body for PostApiAccountConfigApply for application/json ContentType.
+type PostApiAccountConfigApplyJSONRequestBody = AccountConfigDocument
+
+// PostApiAccountConfigPlanJSONRequestBody defines body for PostApiAccountConfigPlan for application/json ContentType.
+type PostApiAccountConfigPlanJSONRequestBody = AccountConfigDocument
+
 // PutApiAccountsAccountIdJSONRequestBody defines body for PutApiAccountsAccountId for application/json ContentType.
 type PutApiAccountsAccountIdJSONRequestBody = AccountRequest
 
@@ -2216,6 +3228,18 @@ type PostApiNetworksNetworkIdRoutersJSONRequestBody = NetworkRouterRequest
 // PutApiNetworksNetworkIdRoutersRouterIdJSONRequestBody defines body for PutApiNetworksNetworkIdRoutersRouterId for application/json ContentType.
 type PutApiNetworksNetworkIdRoutersRouterIdJSONRequestBody = NetworkRouterRequest
 
+// PostApiPeerApprovalRulesJSONRequestBody defines body for PostApiPeerApprovalRules for application/json ContentType.
+type PostApiPeerApprovalRulesJSONRequestBody = PeerApprovalRuleRequest
+
+// PutApiPeerApprovalRulesRuleIdJSONRequestBody defines body for PutApiPeerApprovalRulesRuleId for application/json ContentType.
+type PutApiPeerApprovalRulesRuleIdJSONRequestBody = PeerApprovalRuleRequest
+
+// PostApiPeerApprovalsApproveJSONRequestBody defines body for PostApiPeerApprovalsApprove for application/json ContentType.
+type PostApiPeerApprovalsApproveJSONRequestBody = PeerApprovalDecision
+
+// PostApiPeerApprovalsRejectJSONRequestBody defines body for PostApiPeerApprovalsReject for application/json ContentType.
+type PostApiPeerApprovalsRejectJSONRequestBody = PeerApprovalDecision
+
 // PutApiPeersPeerIdJSONRequestBody defines body for PutApiPeersPeerId for application/json ContentType.
 type PutApiPeersPeerIdJSONRequestBody = PeerRequest
 
@@ -2231,6 +3255,9 @@ type PostApiPeersPeerIdTemporaryAccessJSONRequestBody = PeerTemporaryAccessReque
 // PostApiPoliciesJSONRequestBody defines body for PostApiPolicies for application/json ContentType.
 type PostApiPoliciesJSONRequestBody = PolicyUpdate
 
+// PostApiPoliciesSimulateJSONRequestBody defines body for PostApiPoliciesSimulate for application/json ContentType.
+type PostApiPoliciesSimulateJSONRequestBody = PolicySimulationRequest
+
 // PutApiPoliciesPolicyIdJSONRequestBody defines body for PutApiPoliciesPolicyId for application/json ContentType.
 type PutApiPoliciesPolicyIdJSONRequestBody = PolicyCreate
 
@@ -2240,6 +3267,12 @@ type PostApiPostureChecksJSONRequestBody = PostureCheckUpdate
 // PutApiPostureChecksPostureCheckIdJSONRequestBody defines body for PutApiPostureChecksPostureCheckId for application/json ContentType.
 type PutApiPostureChecksPostureCheckIdJSONRequestBody = PostureCheckUpdate
 
+// PostApiRolesJSONRequestBody defines body for PostApiRoles for application/json ContentType.
+type PostApiRolesJSONRequestBody = RoleRequest
+
+// PutApiRolesRoleIdJSONRequestBody defines body for PutApiRolesRoleId for application/json ContentType.
+type PutApiRolesRoleIdJSONRequestBody = RoleRequest
+
 // PostApiRoutesJSONRequestBody defines body for PostApiRoutes for application/json ContentType.
 type PostApiRoutesJSONRequestBody = RouteRequest
 
@@ -2263,3 +3296,9 @@ type PutApiUsersUserIdJSONRequestBody = UserRequest
 
 // PostApiUsersUserIdTokensJSONRequestBody defines body for PostApiUsersUserIdTokens for application/json ContentType.
 type PostApiUsersUserIdTokensJSONRequestBody = PersonalAccessTokenRequest
+
+// PostApiWorkloadIdentityProvidersJSONRequestBody defines body for PostApiWorkloadIdentityProviders for application/json ContentType.
+type PostApiWorkloadIdentityProvidersJSONRequestBody = WorkloadIdentityProviderRequest
+
+// PutApiWorkloadIdentityProvidersProviderIdJSONRequestBody defines body for PutApiWorkloadIdentityProvidersProviderId for application/json ContentType.
+type PutApiWorkloadIdentityProvidersProviderIdJSONRequestBody = WorkloadIdentityProviderRequest
@@ -262,3 +262,28 @@ func NewZoneNotFoundError(zoneID string) error {
 func NewDNSRecordNotFoundError(recordID string) error {
 	return Errorf(NotFound, "dns record: %s not found", recordID)
 }
+
+// NewCustomRoleNotFoundError creates a new Error with NotFound type for a missing custom role.
+func NewCustomRoleNotFoundError(roleID string) error {
+	return Errorf(NotFound, "custom role: %s not found", roleID)
+}
+
+// NewAccessRequestNotFoundError creates a new Error with NotFound type for a missing access request.
+func NewAccessRequestNotFoundError(requestID string) error {
+	return Errorf(NotFound, "access request: %s not found", requestID)
+}
+
+// NewSCIMTokenNotFoundError creates a new Error with NotFound type for a missing SCIM token.
+func NewSCIMTokenNotFoundError() error {
+	return Errorf(NotFound, "SCIM token not found")
+}
+
+// NewWorkloadIdentityProviderNotFoundError creates a new Error with NotFound type for a missing workload identity provider.
+func NewWorkloadIdentityProviderNotFoundError(providerID string) error {
+	return Errorf(NotFound, "workload identity provider: %s not found", providerID)
+}
+
+// NewPeerApprovalRuleNotFoundError creates a new Error with NotFound type for a missing peer approval rule.
+func NewPeerApprovalRuleNotFoundError(ruleID string) error {
+	return Errorf(NotFound, "peer approval rule: %s not found", ruleID)
+}
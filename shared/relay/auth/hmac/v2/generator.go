@@ -29,9 +29,19 @@ func NewGenerator(algo AuthAlgo, secret []byte, timeToLive time.Duration) (*Gene
 }
 
 func (g *Generator) GenerateToken() (*Token, error) {
+	return g.GenerateAccountToken("")
+}
+
+// GenerateAccountToken generates a token that carries the account ID of the peer. The relay server uses the account ID
+// to apply the per-account limits. Relay servers without account support reject these tokens.
+func (g *Generator) GenerateAccountToken(accountID string) (*Token, error) {
 	expirationTime := time.Now().Add(g.timeToLive).Unix()
 
 	payload := []byte(strconv.FormatInt(expirationTime, 10))
+	if accountID != "" {
+		payload = append(payload, payloadSeparator)
+		payload = append(payload, accountID...)
+	}
 
 	h := hmac.New(g.algo, g.secret)
 	h.Write(payload)
@@ -108,3 +108,32 @@ func TestInvalidPayload(t *testing.T) {
 		t.Fatalf("expected invalid token due to invalid payload")
 	}
 }
+
+func TestAccountToken(t *testing.T) {
+	secret := "supersecret"
+	g, err := NewGenerator(AuthAlgoHMACSHA256, []byte(secret), time.Hour)
+	if err != nil {
+		t.Fatalf("failed to create generator: %v", err)
+	}
+
+	token, err := g.GenerateAccountToken("account-1")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	v := NewValidator([]byte(secret))
+	if err := v.Validate(token.Marshal()); err != nil {
+		t.Fatalf("expected valid token: %s", err)
+	}
+	if accountID := v.AccountID(token.Marshal()); accountID != "account-1" {
+		t.Fatalf("expected account-1, got %q", accountID)
+	}
+
+	token, err = g.GenerateToken()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if accountID := v.AccountID(token.Marshal()); accountID != "" {
+		t.Fatalf("expected no account, got %q", accountID)
+	}
+}
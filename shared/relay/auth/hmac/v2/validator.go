@@ -1,6 +1,7 @@
 package v2
 
 import (
+	"bytes"
 	"crypto/hmac"
 	"errors"
 	"fmt"
@@ -8,7 +9,12 @@ import (
 	"time"
 )
 
-const minLengthUnixTimestamp = 10
+const (
+	minLengthUnixTimestamp = 10
+
+	// payloadSeparator separates the expiration time and the optional account ID in the token payload
+	payloadSeparator = ':'
+)
 
 type Validator struct {
 	secret []byte
@@ -46,7 +52,8 @@ func (v *Validator) Validate(data any) error {
 		return errors.New("invalid signature")
 	}
 
-	timestamp, err := strconv.ParseInt(string(token.Payload), 10, 64)
+	expiration, _ := splitPayload(token.Payload)
+	timestamp, err := strconv.ParseInt(string(expiration), 10, 64)
 	if err != nil {
 		return fmt.Errorf("invalid payload: %w", err)
 	}
@@ -57,3 +64,25 @@ func (v *Validator) Validate(data any) error {
 
 	return nil
 }
+
+// AccountID returns the account ID carried by the token, it is empty if the token has no account. The token is not
+// validated, call it only after Validate succeeded.
+func (v *Validator) AccountID(data any) string {
+	d, ok := data.([]byte)
+	if !ok {
+		return ""
+	}
+
+	token, err := UnmarshalToken(d)
+	if err != nil {
+		return ""
+	}
+
+	_, accountID := splitPayload(token.Payload)
+	return string(accountID)
+}
+
+func splitPayload(payload []byte) ([]byte, []byte) {
+	expiration, accountID, _ := bytes.Cut(payload, []byte{payloadSeparator})
+	return expiration, accountID
+}
@@ -23,6 +23,11 @@ func (a *TimedHMACValidator) Validate(credentials any) error {
 	return a.authenticatorV2.Validate(credentials)
 }
 
+// AccountID returns the account ID of the peer carried by the auth token
+func (a *TimedHMACValidator) AccountID(credentials any) string {
+	return a.authenticatorV2.AccountID(credentials)
+}
+
 func (a *TimedHMACValidator) ValidateHelloMsgType(credentials any) error {
 	return a.authenticator.Validate(credentials)
 }
@@ -421,6 +421,10 @@ func (c *Client) handleMsg(msgType messages.MsgType, buf []byte, bufPtr *[]byte,
 		c.handlePeersWentOfflineMsg(buf)
 		c.bufPool.Put(bufPtr)
 		return true
+	case messages.MsgTypeThrottled:
+		c.handleThrottledMsg(buf)
+		c.bufPool.Put(bufPtr)
+		return true
 	case messages.MsgTypeClose:
 		c.log.Debugf("relay connection close by server")
 		c.bufPool.Put(bufPtr)
@@ -666,3 +670,17 @@ func (c *Client) handlePeersWentOfflineMsg(buf []byte) {
 	}
 	c.stateSubscription.OnPeersWentOffline(peersID)
 }
+
+func (c *Client) handleThrottledMsg(buf []byte) {
+	reason, err := messages.UnmarshalThrottledMsg(buf)
+	if err != nil {
+		c.log.Errorf("failed to unmarshal throttled msg: %s", err)
+		return
+	}
+
+	if reason.IsQuota() {
+		c.log.Warnf("relay server drops the traffic of this peer: %s", reason)
+		return
+	}
+	c.log.Warnf("relay server throttles the traffic of this peer: %s", reason)
+}
@@ -30,6 +30,9 @@ const (
 	MsgTypePeersOnline          = 10
 	MsgTypePeersWentOffline     = 11
 
+	// MsgTypeThrottled is sent by the server when it limits the traffic of the peer
+	MsgTypeThrottled = 12
+
 	// base size of the message
 	sizeOfVersionByte = 1
 	sizeOfMsgType     = 1
@@ -87,6 +90,8 @@ func (m MsgType) String() string {
 		return "peers online"
 	case MsgTypePeersWentOffline:
 		return "peers went offline"
+	case MsgTypeThrottled:
+		return "throttled"
 	default:
 		return "unknown"
 	}
@@ -141,7 +146,8 @@ func DetermineServerMessageType(msg []byte) (MsgType, error) {
 		MsgTypeClose,
 		MsgTypeHealthCheck,
 		MsgTypePeersOnline,
-		MsgTypePeersWentOffline:
+		MsgTypePeersWentOffline,
+		MsgTypeThrottled:
 		return msgType, nil
 	default:
 		return MsgTypeUnknown, fmt.Errorf("invalid msg type %d", msgType)
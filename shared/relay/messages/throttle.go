@@ -0,0 +1,61 @@
+package messages
+
+import (
+	"fmt"
+)
+
+// ThrottleReason tells which limit of the relay server throttles the traffic of the peer
+type ThrottleReason byte
+
+const (
+	ThrottleReasonUnknown ThrottleReason = iota
+	// ThrottleReasonPeerRate the peer exceeded its own bandwidth limit, the messages are delayed
+	ThrottleReasonPeerRate
+	// ThrottleReasonAccountRate the peers of the account exceeded the shared bandwidth limit, the messages are delayed
+	ThrottleReasonAccountRate
+	// ThrottleReasonPeerQuota the peer used up its daily traffic quota, the messages are dropped
+	ThrottleReasonPeerQuota
+	// ThrottleReasonAccountQuota the peers of the account used up the shared daily traffic quota, the messages are dropped
+	ThrottleReasonAccountQuota
+)
+
+const sizeOfThrottledMsg = sizeOfProtoHeader + 1
+
+func (r ThrottleReason) String() string {
+	switch r {
+	case ThrottleReasonPeerRate:
+		return "peer bandwidth limit"
+	case ThrottleReasonAccountRate:
+		return "account bandwidth limit"
+	case ThrottleReasonPeerQuota:
+		return "peer daily quota exceeded"
+	case ThrottleReasonAccountQuota:
+		return "account daily quota exceeded"
+	default:
+		return "unknown"
+	}
+}
+
+// IsQuota returns true if the reason means that the messages of the peer are dropped instead of delayed
+func (r ThrottleReason) IsQuota() bool {
+	return r == ThrottleReasonPeerQuota || r == ThrottleReasonAccountQuota
+}
+
+// MarshalThrottledMsg creates a throttled message.
+// The server sends this message to let the client know why its throughput is limited. The message is informational,
+// the client does not need to respond to it.
+func MarshalThrottledMsg(reason ThrottleReason) []byte {
+	return []byte{
+		byte(CurrentProtocolVersion),
+		byte(MsgTypeThrottled),
+		byte(reason),
+	}
+}
+
+// UnmarshalThrottledMsg extracts the throttle reason from the throttled message
+func UnmarshalThrottledMsg(buf []byte) (ThrottleReason, error) {
+	if len(buf) < sizeOfThrottledMsg {
+		return ThrottleReasonUnknown, fmt.Errorf("invalid throttled message: %w", ErrInvalidMessageLength)
+	}
+	return ThrottleReason(buf[sizeOfProtoHeader]), nil
+}
@@ -0,0 +1,36 @@
+package messages
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMarshalUnmarshalThrottled(t *testing.T) {
+	msg := MarshalThrottledMsg(ThrottleReasonAccountQuota)
+
+	msgType, err := DetermineServerMessageType(msg)
+	if err != nil {
+		t.Fatalf("failed to determine message type: %v", err)
+	}
+	if msgType != MsgTypeThrottled {
+		t.Errorf("expected %d, got %d", MsgTypeThrottled, msgType)
+	}
+
+	reason, err := UnmarshalThrottledMsg(msg)
+	if err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	if reason != ThrottleReasonAccountQuota {
+		t.Errorf("expected %s, got %s", ThrottleReasonAccountQuota, reason)
+	}
+	if !reason.IsQuota() {
+		t.Errorf("expected quota reason")
+	}
+}
+
+func TestUnmarshalThrottled_InvalidLength(t *testing.T) {
+	_, err := UnmarshalThrottledMsg([]byte{byte(CurrentProtocolVersion), byte(MsgTypeThrottled)})
+	if !errors.Is(err, ErrInvalidMessageLength) {
+		t.Errorf("expected invalid message length error, got %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/netbirdio/netbird/shared/signal/proto"
+)
+
+const (
+	// TypeMemory is an in-process broker, it only connects signal servers running in the same process
+	TypeMemory = "memory"
+	// TypeRedis uses Redis pub/sub to connect signal servers running in different processes
+	TypeRedis = "redis"
+)
+
+var (
+	ErrBrokerClosed = errors.New("broker closed")
+)
+
+// MessageHandler handles a message addressed to a peer connected to this signal instance
+type MessageHandler func(ctx context.Context, msg *proto.EncryptedMessage)
+
+// Broker exchanges messages between signal server instances so that peers connected to different
+// instances can reach each other.
+type Broker interface {
+	// Publish sends the message to the instances where the destination peer is connected.
+	// Returns false if the destination peer is not connected to any instance.
+	Publish(ctx context.Context, msg *proto.EncryptedMessage) (bool, error)
+	// Subscribe delivers the messages addressed to the peer to the handler until the context is done.
+	// A peer can have multiple subscriptions (e.g. during reconnection), the subscription of the peer is
+	// removed when the last one is done.
+	Subscribe(ctx context.Context, peerID string, handler MessageHandler) error
+	// Close stops all subscriptions and releases the resources of the broker
+	Close() error
+}
+
+// NewBroker creates a broker of the given type. The address is only used by the remote brokers.
+func NewBroker(ctx context.Context, brokerType, address string) (Broker, error) {
+	switch brokerType {
+	case TypeMemory:
+		return NewMemoryBroker(), nil
+	case TypeRedis:
+		return NewRedisBroker(ctx, address)
+	default:
+		return nil, fmt.Errorf("unsupported broker type: %s", brokerType)
+	}
+}
@@ -0,0 +1,66 @@
+package broker
+
+import (
+	"context"
+
+	"github.com/netbirdio/netbird/shared/signal/proto"
+)
+
+// MemoryBroker exchanges messages between signal servers sharing the same broker instance.
+// It is meant for tests and single instance deployments.
+type MemoryBroker struct {
+	subs   *subscriptions
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewMemoryBroker creates a new in-process broker
+func NewMemoryBroker() *MemoryBroker {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &MemoryBroker{
+		subs:   newSubscriptions(),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Publish queues the message for the latest subscription of the destination peer
+func (b *MemoryBroker) Publish(ctx context.Context, msg *proto.EncryptedMessage) (bool, error) {
+	if b.ctx.Err() != nil {
+		return false, ErrBrokerClosed
+	}
+
+	sub, ok := b.subs.latest(msg.RemoteKey)
+	if !ok {
+		return false, nil
+	}
+	return sub.deliver(msg), nil
+}
+
+// Subscribe delivers the messages addressed to the peer to the handler until the context is done
+func (b *MemoryBroker) Subscribe(ctx context.Context, peerID string, handler MessageHandler) error {
+	if b.ctx.Err() != nil {
+		return ErrBrokerClosed
+	}
+
+	subCtx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(b.ctx, cancel)
+
+	sub := newSubscription(subCtx, handler)
+	b.subs.add(peerID, sub)
+	go sub.run()
+
+	go func() {
+		<-subCtx.Done()
+		stop()
+		b.subs.remove(peerID, sub)
+	}()
+
+	return nil
+}
+
+// Close stops all subscriptions
+func (b *MemoryBroker) Close() error {
+	b.cancel()
+	return nil
+}
@@ -0,0 +1,93 @@
+package broker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/shared/signal/proto"
+)
+
+func receiveMessage(t *testing.T, ch <-chan *proto.EncryptedMessage) *proto.EncryptedMessage {
+	t.Helper()
+	select {
+	case msg := <-ch:
+		return msg
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for message")
+		return nil
+	}
+}
+
+func channelHandler(ch chan *proto.EncryptedMessage) MessageHandler {
+	return func(_ context.Context, msg *proto.EncryptedMessage) {
+		ch <- msg
+	}
+}
+
+func TestMemoryBroker_PublishSubscribe(t *testing.T) {
+	b := NewMemoryBroker()
+	defer b.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	received := make(chan *proto.EncryptedMessage, 1)
+	require.NoError(t, b.Subscribe(ctx, "peerB", channelHandler(received)))
+
+	delivered, err := b.Publish(context.Background(), &proto.EncryptedMessage{Key: "peerA", RemoteKey: "peerB"})
+	require.NoError(t, err)
+	assert.True(t, delivered, "message should be delivered to the subscribed peer")
+	assert.Equal(t, "peerA", receiveMessage(t, received).Key)
+
+	delivered, err = b.Publish(context.Background(), &proto.EncryptedMessage{Key: "peerA", RemoteKey: "peerC"})
+	require.NoError(t, err)
+	assert.False(t, delivered, "message to an unknown peer should not be delivered")
+}
+
+func TestMemoryBroker_ResubscribeKeepsLatest(t *testing.T) {
+	b := NewMemoryBroker()
+	defer b.Close()
+
+	oldCtx, oldCancel := context.WithCancel(context.Background())
+	oldReceived := make(chan *proto.EncryptedMessage, 1)
+	require.NoError(t, b.Subscribe(oldCtx, "peerB", channelHandler(oldReceived)))
+
+	newCtx, newCancel := context.WithCancel(context.Background())
+	defer newCancel()
+	newReceived := make(chan *proto.EncryptedMessage, 1)
+	require.NoError(t, b.Subscribe(newCtx, "peerB", channelHandler(newReceived)))
+
+	// the old stream going away must not remove the subscription of the new one
+	oldCancel()
+	require.Eventually(t, func() bool {
+		b.subs.mu.Lock()
+		defer b.subs.mu.Unlock()
+		return len(b.subs.peers["peerB"]) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	delivered, err := b.Publish(context.Background(), &proto.EncryptedMessage{Key: "peerA", RemoteKey: "peerB"})
+	require.NoError(t, err)
+	assert.True(t, delivered)
+	receiveMessage(t, newReceived)
+	assert.Empty(t, oldReceived)
+
+	newCancel()
+	require.Eventually(t, func() bool {
+		_, ok := b.subs.latest("peerB")
+		return !ok
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestMemoryBroker_Close(t *testing.T) {
+	b := NewMemoryBroker()
+	require.NoError(t, b.Subscribe(context.Background(), "peerB", channelHandler(make(chan *proto.EncryptedMessage, 1))))
+	require.NoError(t, b.Close())
+
+	_, err := b.Publish(context.Background(), &proto.EncryptedMessage{Key: "peerA", RemoteKey: "peerB"})
+	assert.ErrorIs(t, err, ErrBrokerClosed)
+	assert.ErrorIs(t, b.Subscribe(context.Background(), "peerB", nil), ErrBrokerClosed)
+}
@@ -0,0 +1,164 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	log "github.com/sirupsen/logrus"
+	gproto "google.golang.org/protobuf/proto"
+
+	"github.com/netbirdio/netbird/shared/signal/proto"
+)
+
+const (
+	// redisChannelPrefix is the prefix of the pub/sub channel of a peer, the channel name is prefix + peer ID
+	redisChannelPrefix = "netbird:signal:peer:"
+
+	redisConnectTimeout = 10 * time.Second
+)
+
+// RedisBroker exchanges messages between signal server instances over Redis pub/sub.
+// Every instance subscribes to the channels of the peers connected to it, so a publish reaches
+// the instance of the destination peer and the number of receivers tells whether the peer is connected at all.
+type RedisBroker struct {
+	client *redis.Client
+	pubSub *redis.PubSub
+	subs   *subscriptions
+	// subMu serializes the channel subscription changes of the peers
+	subMu sync.Mutex
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewRedisBroker connects to the Redis server at the given URL (e.g. redis://localhost:6379/0)
+func NewRedisBroker(ctx context.Context, address string) (*RedisBroker, error) {
+	options, err := redis.ParseURL(address)
+	if err != nil {
+		return nil, fmt.Errorf("parse redis url: %w", err)
+	}
+
+	client := redis.NewClient(options)
+
+	pingCtx, pingCancel := context.WithTimeout(ctx, redisConnectTimeout)
+	defer pingCancel()
+	if err := client.Ping(pingCtx).Err(); err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("connect to redis: %w", err)
+	}
+
+	brokerCtx, cancel := context.WithCancel(context.Background())
+	b := &RedisBroker{
+		client: client,
+		pubSub: client.Subscribe(brokerCtx),
+		subs:   newSubscriptions(),
+		ctx:    brokerCtx,
+		cancel: cancel,
+	}
+
+	go b.receive()
+
+	log.Infof("using redis signal broker at %s", options.Addr)
+	return b, nil
+}
+
+// Publish sends the message to the channel of the destination peer
+func (b *RedisBroker) Publish(ctx context.Context, msg *proto.EncryptedMessage) (bool, error) {
+	if b.ctx.Err() != nil {
+		return false, ErrBrokerClosed
+	}
+
+	data, err := gproto.Marshal(msg)
+	if err != nil {
+		return false, fmt.Errorf("marshal message: %w", err)
+	}
+
+	receivers, err := b.client.Publish(ctx, redisChannel(msg.RemoteKey), data).Result()
+	if err != nil {
+		return false, fmt.Errorf("publish message: %w", err)
+	}
+
+	return receivers > 0, nil
+}
+
+// Subscribe subscribes to the channel of the peer and delivers its messages to the handler until the context is done
+func (b *RedisBroker) Subscribe(ctx context.Context, peerID string, handler MessageHandler) error {
+	if b.ctx.Err() != nil {
+		return ErrBrokerClosed
+	}
+
+	subCtx, cancel := context.WithCancel(ctx)
+	sub := newSubscription(subCtx, handler)
+
+	b.subMu.Lock()
+	if first := b.subs.add(peerID, sub); first {
+		if err := b.pubSub.Subscribe(ctx, redisChannel(peerID)); err != nil {
+			b.subs.remove(peerID, sub)
+			b.subMu.Unlock()
+			cancel()
+			return fmt.Errorf("subscribe to peer channel: %w", err)
+		}
+	}
+	b.subMu.Unlock()
+
+	stop := context.AfterFunc(b.ctx, cancel)
+	go sub.run()
+
+	go func() {
+		<-subCtx.Done()
+		stop()
+		b.unsubscribe(peerID, sub)
+	}()
+
+	return nil
+}
+
+func (b *RedisBroker) unsubscribe(peerID string, sub *subscription) {
+	b.subMu.Lock()
+	defer b.subMu.Unlock()
+
+	if last := b.subs.remove(peerID, sub); !last || b.ctx.Err() != nil {
+		return
+	}
+
+	if err := b.pubSub.Unsubscribe(context.Background(), redisChannel(peerID)); err != nil {
+		log.Errorf("failed to unsubscribe from channel of peer [%s]: %v", peerID, err)
+	}
+}
+
+func (b *RedisBroker) receive() {
+	for msg := range b.pubSub.Channel() {
+		peerID := strings.TrimPrefix(msg.Channel, redisChannelPrefix)
+
+		encryptedMsg := &proto.EncryptedMessage{}
+		if err := gproto.Unmarshal([]byte(msg.Payload), encryptedMsg); err != nil {
+			log.Errorf("failed to unmarshal message for peer [%s]: %v", peerID, err)
+			continue
+		}
+
+		sub, ok := b.subs.latest(peerID)
+		if !ok {
+			log.Tracef("received message for peer [%s] which is not connected anymore", peerID)
+			continue
+		}
+		sub.deliver(encryptedMsg)
+	}
+}
+
+// Close unsubscribes from all channels and closes the redis connections
+func (b *RedisBroker) Close() error {
+	b.cancel()
+
+	if err := b.pubSub.Close(); err != nil {
+		log.Debugf("failed to close redis pub/sub: %v", err)
+	}
+	return b.client.Close()
+}
+
+func redisChannel(peerID string) string {
+	return redisChannelPrefix + peerID
+}
@@ -0,0 +1,61 @@
+package broker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	testcontainersredis "github.com/testcontainers/testcontainers-go/modules/redis"
+
+	"github.com/netbirdio/netbird/shared/signal/proto"
+)
+
+func TestRedisBrokerConnectionFailure(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err := NewRedisBroker(ctx, "redis://127.0.0.1:1")
+	assert.Error(t, err, "creating redis broker should fail without a redis server")
+}
+
+func TestRedisBroker_BetweenInstances(t *testing.T) {
+	ctx := context.Background()
+	redisContainer, err := testcontainersredis.RunContainer(ctx, testcontainers.WithImage("redis:7"))
+	if err != nil {
+		t.Fatalf("couldn't start redis container: %s", err)
+	}
+	defer func() {
+		if err := redisContainer.Terminate(ctx); err != nil {
+			t.Logf("failed to terminate container: %s", err)
+		}
+	}()
+	redisURL, err := redisContainer.ConnectionString(ctx)
+	require.NoError(t, err)
+
+	// two brokers simulate two signal instances
+	instanceA, err := NewRedisBroker(ctx, redisURL)
+	require.NoError(t, err)
+	defer instanceA.Close()
+
+	instanceB, err := NewRedisBroker(ctx, redisURL)
+	require.NoError(t, err)
+	defer instanceB.Close()
+
+	subCtx, cancel := context.WithCancel(ctx)
+	received := make(chan *proto.EncryptedMessage, 1)
+	require.NoError(t, instanceB.Subscribe(subCtx, "peerB", channelHandler(received)))
+
+	delivered, err := instanceA.Publish(ctx, &proto.EncryptedMessage{Key: "peerA", RemoteKey: "peerB", Body: []byte("hello")})
+	require.NoError(t, err)
+	assert.True(t, delivered, "message should reach the instance of the destination peer")
+	assert.Equal(t, []byte("hello"), receiveMessage(t, received).Body)
+
+	cancel()
+	require.Eventually(t, func() bool {
+		delivered, err := instanceA.Publish(ctx, &proto.EncryptedMessage{Key: "peerA", RemoteKey: "peerB"})
+		return err == nil && !delivered
+	}, 5*time.Second, 50*time.Millisecond, "peer should be unsubscribed after its stream is done")
+}
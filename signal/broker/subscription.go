@@ -0,0 +1,108 @@
+package broker
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/shared/signal/proto"
+)
+
+// subscriptionBufferSize is the number of messages that can be queued for a peer before new messages get dropped
+const subscriptionBufferSize = 100
+
+// subscription delivers the messages of a peer to its handler in the order they were received
+type subscription struct {
+	ctx     context.Context
+	handler MessageHandler
+	msgs    chan *proto.EncryptedMessage
+}
+
+func newSubscription(ctx context.Context, handler MessageHandler) *subscription {
+	return &subscription{
+		ctx:     ctx,
+		handler: handler,
+		msgs:    make(chan *proto.EncryptedMessage, subscriptionBufferSize),
+	}
+}
+
+func (s *subscription) run() {
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case msg := <-s.msgs:
+			s.handler(s.ctx, msg)
+		}
+	}
+}
+
+// deliver queues the message without blocking, returns false if the message was not queued
+func (s *subscription) deliver(msg *proto.EncryptedMessage) bool {
+	select {
+	case <-s.ctx.Done():
+		return false
+	case s.msgs <- msg:
+		return true
+	default:
+		log.Warnf("dropping message from peer [%s] to peer [%s]: queue is full", msg.Key, msg.RemoteKey)
+		return false
+	}
+}
+
+// subscriptions holds the subscriptions of the peers connected to this instance
+type subscriptions struct {
+	mu    sync.Mutex
+	peers map[string][]*subscription
+}
+
+func newSubscriptions() *subscriptions {
+	return &subscriptions{
+		peers: make(map[string][]*subscription),
+	}
+}
+
+// add returns true if it is the first subscription of the peer
+func (s *subscriptions) add(peerID string, sub *subscription) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.peers[peerID] = append(s.peers[peerID], sub)
+	return len(s.peers[peerID]) == 1
+}
+
+// remove returns true if it was the last subscription of the peer
+func (s *subscriptions) remove(peerID string, sub *subscription) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subs, ok := s.peers[peerID]
+	if !ok {
+		return false
+	}
+
+	subs = slices.DeleteFunc(subs, func(existing *subscription) bool {
+		return existing == sub
+	})
+	if len(subs) > 0 {
+		s.peers[peerID] = subs
+		return false
+	}
+
+	delete(s.peers, peerID)
+	return true
+}
+
+// latest returns the most recent subscription of the peer
+func (s *subscriptions) latest(peerID string) (*subscription, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subs, ok := s.peers[peerID]
+	if !ok || len(subs) == 0 {
+		return nil, false
+	}
+	return subs[len(subs)-1], true
+}
@@ -22,6 +22,7 @@ import (
 
 	"github.com/netbirdio/netbird/encryption"
 	"github.com/netbirdio/netbird/shared/signal/proto"
+	"github.com/netbirdio/netbird/signal/broker"
 	"github.com/netbirdio/netbird/signal/server"
 	"github.com/netbirdio/netbird/util"
 	"github.com/netbirdio/netbird/util/wsproxy"
@@ -45,6 +46,8 @@ var (
 	defaultSignalSSLDir     string
 	signalCertFile          string
 	signalCertKey           string
+	brokerType              string
+	brokerURL               string
 
 	signalKaep = grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
 		MinTime:             5 * time.Second,
@@ -114,7 +117,21 @@ var (
 				}
 			}()
 
-			srv, err := server.NewServer(cmd.Context(), metricsServer.Meter)
+			var srvOpts []server.Option
+			if brokerType != "" {
+				b, err := broker.NewBroker(cmd.Context(), brokerType, brokerURL)
+				if err != nil {
+					return fmt.Errorf("creating signal broker: %v", err)
+				}
+				defer func() {
+					if err := b.Close(); err != nil {
+						log.Errorf("failed to close signal broker: %v", err)
+					}
+				}()
+				srvOpts = append(srvOpts, server.WithBroker(b))
+			}
+
+			srv, err := server.NewServer(cmd.Context(), metricsServer.Meter, srvOpts...)
 			if err != nil {
 				return fmt.Errorf("creating signal server: %v", err)
 			}
@@ -330,5 +347,7 @@ func init() {
 	runCmd.Flags().StringVar(&signalLetsencryptDomain, "letsencrypt-domain", "", "a domain to issue Let's Encrypt certificate for. Enables TLS using Let's Encrypt. Will fetch and renew certificate, and run the server with TLS")
 	runCmd.Flags().StringVar(&signalCertFile, "cert-file", "", "Location of your SSL certificate. Can be used when you have an existing certificate and don't want a new certificate be generated automatically. If letsencrypt-domain is specified this property has no effect")
 	runCmd.Flags().StringVar(&signalCertKey, "cert-key", "", "Location of your SSL certificate private key. Can be used when you have an existing certificate and don't want a new certificate be generated automatically. If letsencrypt-domain is specified this property has no effect")
+	runCmd.PersistentFlags().StringVar(&brokerType, "broker-type", "", "Broker used to forward messages between multiple signal instances, one of [redis, memory]. Leave empty to run a single instance")
+	runCmd.PersistentFlags().StringVar(&brokerURL, "broker-url", "", "Address of the broker, e.g. redis://localhost:6379/0 for the redis broker")
 	setFlagsFromEnvVars(runCmd)
 }
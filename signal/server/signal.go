@@ -18,6 +18,7 @@ import (
 	"github.com/netbirdio/signal-dispatcher/dispatcher"
 
 	"github.com/netbirdio/netbird/shared/signal/proto"
+	"github.com/netbirdio/netbird/signal/broker"
 	"github.com/netbirdio/netbird/signal/metrics"
 	"github.com/netbirdio/netbird/signal/peer"
 )
@@ -31,6 +32,7 @@ const (
 	labelTypeMessage       = "message"
 	labelTypeTimeout       = "timeout"
 	labelTypeDisconnected  = "disconnected"
+	labelTypeBroker        = "broker"
 
 	labelError                   = "error"
 	labelErrorMissingId          = "missing_id"
@@ -54,15 +56,27 @@ type Server struct {
 	registry *peer.Registry
 	proto.UnimplementedSignalExchangeServer
 	dispatcher *dispatcher.Dispatcher
-	metrics    *metrics.AppMetrics
+	// broker forwards messages to peers connected to other signal instances, nil when running a single instance
+	broker  broker.Broker
+	metrics *metrics.AppMetrics
 
 	successHeader metadata.MD
 
 	sendTimeout time.Duration
 }
 
+// Option configures the Signal server
+type Option func(*Server)
+
+// WithBroker sets the broker used to reach peers connected to other signal instances
+func WithBroker(b broker.Broker) Option {
+	return func(s *Server) {
+		s.broker = b
+	}
+}
+
 // NewServer creates a new Signal server
-func NewServer(ctx context.Context, meter metric.Meter) (*Server, error) {
+func NewServer(ctx context.Context, meter metric.Meter, opts ...Option) (*Server, error) {
 	appMetrics, err := metrics.NewAppMetrics(meter)
 	if err != nil {
 		return nil, fmt.Errorf("creating app metrics: %v", err)
@@ -88,6 +102,10 @@ func NewServer(ctx context.Context, meter metric.Meter) (*Server, error) {
 		sendTimeout:   sTimeout,
 	}
 
+	for _, opt := range opts {
+		opt(s)
+	}
+
 	return s, nil
 }
 
@@ -100,9 +118,29 @@ func (s *Server) Send(ctx context.Context, msg *proto.EncryptedMessage) (*proto.
 		return &proto.EncryptedMessage{}, nil
 	}
 
+	if s.broker != nil {
+		s.publishMessage(ctx, msg)
+		return &proto.EncryptedMessage{}, nil
+	}
+
 	return s.dispatcher.SendMessage(ctx, msg)
 }
 
+// publishMessage forwards the message through the broker to the signal instance the destination peer is connected to
+func (s *Server) publishMessage(ctx context.Context, msg *proto.EncryptedMessage) {
+	delivered, err := s.broker.Publish(ctx, msg)
+	if err != nil {
+		log.Errorf("failed to publish message from peer [%s] to peer [%s]: %v", msg.Key, msg.RemoteKey, err)
+		s.metrics.MessageForwardFailures.Add(ctx, 1, metric.WithAttributes(attribute.String(labelType, labelTypeBroker)))
+		return
+	}
+
+	if !delivered {
+		log.Tracef("message from peer [%s] can't be forwarded to peer [%s] because destination peer is not connected", msg.Key, msg.RemoteKey)
+		s.metrics.MessageForwardFailures.Add(ctx, 1, metric.WithAttributes(attribute.String(labelType, labelTypeNotConnected)))
+	}
+}
+
 // ConnectStream connects to the exchange stream
 func (s *Server) ConnectStream(stream proto.SignalExchange_ConnectStreamServer) error {
 	ctx, cancel := context.WithCancel(context.Background())
@@ -149,6 +187,14 @@ func (s *Server) RegisterPeer(stream proto.SignalExchange_ConnectStreamServer, c
 		log.Errorf("error while registering message listener for peer [%s] %v", p.Id, err)
 		return nil, status.Errorf(codes.Internal, "error while registering message listener")
 	}
+
+	if s.broker != nil {
+		if err := s.broker.Subscribe(stream.Context(), p.Id, s.forwardMessageToPeer); err != nil {
+			s.metrics.RegistrationFailures.Add(stream.Context(), 1, metric.WithAttributes(attribute.String(labelError, labelErrorFailedRegistration)))
+			log.Errorf("error while subscribing to broker messages for peer [%s] %v", p.Id, err)
+			return nil, status.Errorf(codes.Internal, "error while registering message listener")
+		}
+	}
 	return p, nil
 }
 
@@ -0,0 +1,242 @@
+// Package turn provides an embedded TURN (RFC 5766) server for ICE relay candidates.
+//
+// The server authenticates the clients with the time-windowed credentials issued by the management service for TURN
+// (TimeBasedCredentials): the username is the expiration unix timestamp and the password is the base64 encoded
+// HMAC-SHA1 of the username keyed with the shared secret.
+//
+// Like coturn, the server refuses to relay to loopback, private, link-local and other special purpose addresses by
+// default, so that the allocations can't be used to reach the internal network of the host, e.g. the cloud metadata
+// service. Additional peer ranges can be allowed with Config.AllowedPeerRanges.
+package turn
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"sync"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pion/logging"
+	pionturn "github.com/pion/turn/v3"
+	log "github.com/sirupsen/logrus"
+
+	nberrors "github.com/netbirdio/netbird/client/errors"
+)
+
+// DefaultRealm is the realm used when no realm is configured
+const DefaultRealm = "netbird"
+
+// ErrServerClosed is returned by Listen when the server is shut down gracefully.
+var ErrServerClosed = errors.New("turn: server closed")
+
+// ErrNoListeners is returned by Listen when no UDP connections were provided.
+var ErrNoListeners = errors.New("turn: no listeners configured")
+
+// deniedPeerRanges are the peer addresses the server doesn't relay to unless they are allowed explicitly
+var deniedPeerRanges = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("10.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("127.0.0.0/8"),
+	netip.MustParsePrefix("169.254.0.0/16"),
+	netip.MustParsePrefix("172.16.0.0/12"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("192.168.0.0/16"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("224.0.0.0/4"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("::/128"),
+	netip.MustParsePrefix("::1/128"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("fc00::/7"),
+	netip.MustParsePrefix("fe80::/10"),
+	netip.MustParsePrefix("ff00::/8"),
+}
+
+// Config holds the configuration of the TURN server
+type Config struct {
+	// Conns are the UDP listeners of the server, the caller is responsible for creating them
+	Conns []*net.UDPConn
+	// RelayIP is the public IP address of the host, it is advertised in the relayed addresses of the allocations
+	RelayIP net.IP
+	// MinPort and MaxPort limit the UDP ports of the allocations
+	MinPort uint16
+	MaxPort uint16
+	// Realm is the TURN realm, defaults to DefaultRealm
+	Realm string
+	// Secret is the shared secret of the time-windowed credentials, it must match the TURN secret of management
+	Secret string
+	// AllowedPeerRanges are the peer addresses the server relays to although they are in a denied range,
+	// e.g. the private network of the peers the server is deployed in
+	AllowedPeerRanges []netip.Prefix
+	// LogLevel can be: panic, fatal, error, warn, info, debug, trace
+	LogLevel string
+}
+
+func (c *Config) validate() error {
+	if c.RelayIP == nil {
+		return errors.New("relay IP is required")
+	}
+	if c.Secret == "" {
+		return errors.New("secret is required")
+	}
+	if c.MinPort == 0 || c.MaxPort == 0 || c.MinPort > c.MaxPort {
+		return fmt.Errorf("invalid relay port range: %d-%d", c.MinPort, c.MaxPort)
+	}
+	if c.Realm == "" {
+		c.Realm = DefaultRealm
+	}
+	return nil
+}
+
+// Server is a TURN server which also answers the STUN binding requests
+type Server struct {
+	cfg    Config
+	logger *log.Entry
+
+	mu     sync.Mutex
+	server *pionturn.Server
+	done   chan struct{}
+}
+
+// NewServer creates a new TURN server with the given configuration
+func NewServer(cfg Config) (*Server, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid TURN config: %w", err)
+	}
+
+	return &Server{
+		cfg:    cfg,
+		logger: log.WithField("component", "turn-server"),
+		done:   make(chan struct{}),
+	}, nil
+}
+
+// Listen starts the TURN server and blocks until the server is shut down.
+// Returns ErrServerClosed when shut down gracefully via Shutdown.
+// Returns ErrNoListeners if no UDP connections were provided.
+func (s *Server) Listen() error {
+	if len(s.cfg.Conns) == 0 {
+		return ErrNoListeners
+	}
+
+	packetConnConfigs := make([]pionturn.PacketConnConfig, 0, len(s.cfg.Conns))
+	for _, conn := range s.cfg.Conns {
+		s.logger.Infof("TURN server listening on %s", conn.LocalAddr())
+		packetConnConfigs = append(packetConnConfigs, pionturn.PacketConnConfig{
+			PacketConn: conn,
+			RelayAddressGenerator: &pionturn.RelayAddressGeneratorPortRange{
+				RelayAddress: s.cfg.RelayIP,
+				Address:      "0.0.0.0",
+				MinPort:      s.cfg.MinPort,
+				MaxPort:      s.cfg.MaxPort,
+			},
+			PermissionHandler: s.permitPeer,
+		})
+	}
+
+	loggerFactory := newLoggerFactory(s.cfg.LogLevel)
+
+	s.mu.Lock()
+	select {
+	case <-s.done:
+		s.mu.Unlock()
+		return ErrServerClosed
+	default:
+	}
+
+	server, err := pionturn.NewServer(pionturn.ServerConfig{
+		Realm:             s.cfg.Realm,
+		AuthHandler:       pionturn.NewLongTermAuthHandler(s.cfg.Secret, loggerFactory.NewLogger("turn-auth")),
+		PacketConnConfigs: packetConnConfigs,
+		LoggerFactory:     loggerFactory,
+	})
+	if err != nil {
+		s.mu.Unlock()
+		return fmt.Errorf("start TURN server: %w", err)
+	}
+	s.server = server
+	s.mu.Unlock()
+
+	s.logger.Infof("TURN server started, relay address %s, ports %d-%d", s.cfg.RelayIP, s.cfg.MinPort, s.cfg.MaxPort)
+
+	<-s.done
+	return ErrServerClosed
+}
+
+// Shutdown stops the TURN server and closes its listeners and allocations.
+func (s *Server) Shutdown() error {
+	s.logger.Info("shutting down TURN server")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	select {
+	case <-s.done:
+		return nil
+	default:
+		close(s.done)
+	}
+
+	if s.server != nil {
+		return s.server.Close()
+	}
+
+	// the server was not started, the listeners are still owned by us
+	var merr *multierror.Error
+	for _, conn := range s.cfg.Conns {
+		if err := conn.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+			merr = multierror.Append(merr, fmt.Errorf("close TURN UDP connection: %w", err))
+		}
+	}
+	return nberrors.FormatErrorOrNil(merr)
+}
+
+// permitPeer is the permission handler of the allocations, it denies the peer addresses in the denied ranges
+// unless they are allowed by the configuration
+func (s *Server) permitPeer(clientAddr net.Addr, peerIP net.IP) bool {
+	addr, ok := netip.AddrFromSlice(peerIP)
+	if !ok {
+		return false
+	}
+	addr = addr.Unmap()
+
+	for _, prefix := range s.cfg.AllowedPeerRanges {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	for _, prefix := range deniedPeerRanges {
+		if prefix.Contains(addr) {
+			s.logger.Debugf("denied permission of client %s for peer %s in %s", clientAddr, addr, prefix)
+			return false
+		}
+	}
+	return true
+}
+
+// newLoggerFactory creates a pion logger factory writing to the output of the standard logger with the given level
+func newLoggerFactory(logLevel string) *logging.DefaultLoggerFactory {
+	level, err := log.ParseLevel(logLevel)
+	if err != nil {
+		level = log.InfoLevel
+	}
+
+	factory := logging.NewDefaultLoggerFactory()
+	factory.Writer = log.StandardLogger().Out
+	switch level {
+	case log.PanicLevel, log.FatalLevel, log.ErrorLevel:
+		factory.DefaultLogLevel = logging.LogLevelError
+	case log.WarnLevel:
+		factory.DefaultLogLevel = logging.LogLevelWarn
+	case log.InfoLevel:
+		factory.DefaultLogLevel = logging.LogLevelInfo
+	case log.DebugLevel:
+		factory.DefaultLogLevel = logging.LogLevelDebug
+	default:
+		factory.DefaultLogLevel = logging.LogLevelTrace
+	}
+	return factory
+}
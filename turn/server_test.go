@@ -0,0 +1,172 @@
+package turn
+
+import (
+	"crypto/sha1"
+	"errors"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	pionturn "github.com/pion/turn/v3"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	auth "github.com/netbirdio/netbird/shared/relay/auth/hmac"
+)
+
+const testSecret = "turn-secret"
+
+// startTestServer starts a TURN server listening on a random port and returns its address
+func startTestServer(t *testing.T, allowedPeerRanges ...netip.Prefix) *net.UDPAddr {
+	t.Helper()
+
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: 0})
+	require.NoError(t, err)
+
+	server, err := NewServer(Config{
+		Conns:             []*net.UDPConn{conn},
+		RelayIP:           net.ParseIP("127.0.0.1"),
+		MinPort:           50000,
+		MaxPort:           50100,
+		Secret:            testSecret,
+		AllowedPeerRanges: allowedPeerRanges,
+		LogLevel:          "debug",
+	})
+	require.NoError(t, err)
+
+	listenErr := make(chan error, 1)
+	go func() {
+		listenErr <- server.Listen()
+	}()
+
+	t.Cleanup(func() {
+		require.NoError(t, server.Shutdown())
+		select {
+		case err := <-listenErr:
+			assert.True(t, errors.Is(err, ErrServerClosed), "unexpected listen error: %v", err)
+		case <-time.After(5 * time.Second):
+			t.Error("timeout waiting for the server to stop")
+		}
+	})
+
+	return conn.LocalAddr().(*net.UDPAddr)
+}
+
+func allocate(t *testing.T, serverAddr *net.UDPAddr, username, password string) (net.PacketConn, error) {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	client, err := pionturn.NewClient(&pionturn.ClientConfig{
+		STUNServerAddr: serverAddr.String(),
+		TURNServerAddr: serverAddr.String(),
+		Conn:           conn,
+		Username:       username,
+		Password:       password,
+		Realm:          DefaultRealm,
+		RTO:            100 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	require.NoError(t, client.Listen())
+
+	return client.Allocate()
+}
+
+func TestServer_AllocateWithManagementCredentials(t *testing.T) {
+	serverAddr := startTestServer(t)
+
+	// the same generator is used by management to issue the TURN credentials
+	token, err := auth.NewTimedHMAC(testSecret, time.Hour).GenerateToken(sha1.New)
+	require.NoError(t, err)
+
+	relayConn, err := allocate(t, serverAddr, token.Payload, token.Signature)
+	require.NoError(t, err)
+	defer relayConn.Close()
+
+	relayAddr := relayConn.LocalAddr().(*net.UDPAddr)
+	assert.Equal(t, "127.0.0.1", relayAddr.IP.String())
+	assert.GreaterOrEqual(t, relayAddr.Port, 50000)
+	assert.LessOrEqual(t, relayAddr.Port, 50100)
+}
+
+func TestServer_RejectsInvalidCredentials(t *testing.T) {
+	serverAddr := startTestServer(t)
+
+	token, err := auth.NewTimedHMAC("other-secret", time.Hour).GenerateToken(sha1.New)
+	require.NoError(t, err)
+	_, err = allocate(t, serverAddr, token.Payload, token.Signature)
+	assert.Error(t, err, "credentials signed with another secret must be rejected")
+
+	expired, err := auth.NewTimedHMAC(testSecret, -time.Hour).GenerateToken(sha1.New)
+	require.NoError(t, err)
+	_, err = allocate(t, serverAddr, expired.Payload, expired.Signature)
+	assert.Error(t, err, "expired credentials must be rejected")
+}
+
+func TestServer_DeniesInternalPeers(t *testing.T) {
+	token, err := auth.NewTimedHMAC(testSecret, time.Hour).GenerateToken(sha1.New)
+	require.NoError(t, err)
+
+	peer, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: 0})
+	require.NoError(t, err)
+	defer peer.Close()
+
+	relayConn, err := allocate(t, startTestServer(t), token.Payload, token.Signature)
+	require.NoError(t, err)
+	defer relayConn.Close()
+
+	_, err = relayConn.WriteTo([]byte("ping"), peer.LocalAddr())
+	assert.Error(t, err, "relaying to loopback must be denied by default")
+
+	relayConn, err = allocate(t, startTestServer(t, netip.MustParsePrefix("127.0.0.0/8")), token.Payload, token.Signature)
+	require.NoError(t, err)
+	defer relayConn.Close()
+
+	_, err = relayConn.WriteTo([]byte("ping"), peer.LocalAddr())
+	assert.NoError(t, err, "relaying to an allowed range must be permitted")
+}
+
+func TestServer_PermitPeer(t *testing.T) {
+	s := &Server{
+		cfg:    Config{AllowedPeerRanges: []netip.Prefix{netip.MustParsePrefix("10.1.0.0/16")}},
+		logger: log.WithField("component", "turn-server"),
+	}
+	client := &net.UDPAddr{IP: net.ParseIP("203.0.113.1"), Port: 1000}
+
+	tests := []struct {
+		peerIP  string
+		allowed bool
+	}{
+		{"198.51.100.10", true},
+		{"2001:db8::1", true},
+		{"127.0.0.1", false},
+		{"10.0.0.1", false},
+		{"10.1.0.1", true},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"::ffff:169.254.169.254", false},
+		{"::1", false},
+		{"fe80::1", false},
+		{"fd00::1", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.allowed, s.permitPeer(client, net.ParseIP(tt.peerIP)), tt.peerIP)
+	}
+}
+
+func TestNewServer_InvalidConfig(t *testing.T) {
+	_, err := NewServer(Config{RelayIP: net.ParseIP("127.0.0.1"), MinPort: 50000, MaxPort: 50100})
+	assert.Error(t, err, "secret is required")
+
+	_, err = NewServer(Config{Secret: testSecret, MinPort: 50000, MaxPort: 50100})
+	assert.Error(t, err, "relay IP is required")
+
+	_, err = NewServer(Config{Secret: testSecret, RelayIP: net.ParseIP("127.0.0.1"), MinPort: 50100, MaxPort: 50000})
+	assert.Error(t, err, "invalid port range")
+}